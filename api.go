@@ -0,0 +1,242 @@
+package learn
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runAPI implements "go run ./cmd/learn api [flags]".
+func runAPI(args []string) error {
+	flags := flag.NewFlagSet("api", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8086", "address to listen on")
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file to read and update")
+	quizDir := flags.String("quizzes", defaultQuizDir(), "folder of imported question banks")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn api [flags]")
+		fmt.Fprintln(flags.Output(), "Serves the courses, progress and quizzes as JSON.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	quizzes, err := loadQuizzes(*quizDir)
+	if err != nil {
+		return err
+	}
+	s := &apiServer{progressPath: *progressPath, quizzes: quizzes, now: time.Now}
+	fmt.Printf("API running on http://%s\n", *addr)
+	return http.ListenAndServe(*addr, s.routes())
+}
+
+// apiServer serves the course registry, the lessons and the learner's
+// progress file as JSON:
+//
+//	GET  /courses        every course
+//	GET  /courses/{id}   one course with its sections and takeaways
+//	GET  /progress       the progress file
+//	GET  /quiz/{id}      a course's quiz, without the answers
+//	POST /quiz/{id}      answer it: {"answers": [1, 0, 2]}
+type apiServer struct {
+	progressPath string
+	quizzes      map[int][]quizQuestion
+	now          func() time.Time
+}
+
+func (s *apiServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /courses", s.handleCourses)
+	mux.HandleFunc("GET /courses/{id}", s.handleCourse)
+	mux.HandleFunc("GET /progress", s.handleProgress)
+	mux.HandleFunc("GET /quiz/{id}", s.handleQuiz)
+	mux.HandleFunc("POST /quiz/{id}", s.handleAnswers)
+	return mux
+}
+
+// apiCourseSummary is a course as listed by /courses.
+type apiCourseSummary struct {
+	Number      int    `json:"number"`
+	Name        string `json:"name"`
+	File        string `json:"file"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// apiCourse is a course as returned by /courses/{id}. Text is Markdown.
+type apiCourse struct {
+	apiCourseSummary
+	Title         string       `json:"title"`
+	Intro         string       `json:"intro,omitempty"`
+	Sections      []apiSection `json:"sections"`
+	Takeaways     []string     `json:"takeaways"`
+	Exercises     []string     `json:"exercises,omitempty"`
+	QuizQuestions int          `json:"quiz_questions"`
+}
+
+type apiSection struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// apiQuestion is a quiz question as asked: the answer stays on the server.
+type apiQuestion struct {
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices"`
+}
+
+// apiAnswer is the verdict on one answered question.
+type apiAnswer struct {
+	Correct bool   `json:"correct"`
+	Answer  int    `json:"answer"`
+	Explain string `json:"explain"`
+}
+
+func summarise(c course) apiCourseSummary {
+	return apiCourseSummary{
+		Number:      c.number,
+		Name:        c.name,
+		File:        c.file,
+		Description: c.description,
+		URL:         fmt.Sprintf("/courses/%d", c.number),
+	}
+}
+
+func (s *apiServer) handleCourses(w http.ResponseWriter, r *http.Request) {
+	list := make([]apiCourseSummary, 0, len(courses))
+	for _, c := range courses {
+		list = append(list, summarise(c))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *apiServer) handleCourse(w http.ResponseWriter, r *http.Request) {
+	c, ok := pathCourse(w, r)
+	if !ok {
+		return
+	}
+	l, err := loadLesson(c.number)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	out := apiCourse{
+		apiCourseSummary: summarise(c),
+		Title:            l.Title,
+		Intro:            markdownText(l.Sections[0].Parts),
+		Sections:         []apiSection{},
+		Takeaways:        l.Takeaways,
+		QuizQuestions:    len(s.quizzes[c.number]),
+	}
+	for _, sec := range l.Sections[1:] {
+		out.Sections = append(out.Sections, apiSection{ID: sec.ID, Title: sec.Title, Text: markdownText(sec.Parts)})
+	}
+	for _, e := range exerciseList {
+		if e.course == c.number {
+			out.Exercises = append(out.Exercises, e.name)
+		}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// markdownText joins a section's parts back into one Markdown text.
+func markdownText(parts [][]string) string {
+	return strings.TrimSpace(strings.Join(joinParts(parts), "\n"))
+}
+
+func (s *apiServer) handleProgress(w http.ResponseWriter, r *http.Request) {
+	p, err := loadProgress(s.progressPath)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *apiServer) handleQuiz(w http.ResponseWriter, r *http.Request) {
+	c, ok := pathCourse(w, r)
+	if !ok {
+		return
+	}
+	var questions []apiQuestion
+	for _, q := range s.quizzes[c.number] {
+		questions = append(questions, apiQuestion{Prompt: q.Prompt, Choices: q.Choices})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"course": c.number, "questions": questions})
+}
+
+// handleAnswers scores a quiz and records the score in the progress file.
+func (s *apiServer) handleAnswers(w http.ResponseWriter, r *http.Request) {
+	c, ok := pathCourse(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Answers []int `json:"answers"`
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+		return
+	}
+	right, err := scoreQuiz(s.quizzes, c.number, body.Answers)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results := make([]apiAnswer, len(right))
+	correct := 0
+	for i, q := range s.quizzes[c.number] {
+		results[i] = apiAnswer{Correct: right[i], Answer: q.Answer, Explain: q.Explain}
+		if right[i] {
+			correct++
+		}
+	}
+	score := correct * 100 / len(right)
+
+	_, err = updateProgress(s.progressPath, func(p *progress) error {
+		p.recordQuiz(c.number, score, s.now())
+		return nil
+	})
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"course": c.number, "score": score, "results": results})
+}
+
+// pathCourse finds the course named by the {id} in the URL, replying 404
+// if there is none.
+func pathCourse(w http.ResponseWriter, r *http.Request) (course, bool) {
+	n, err := strconv.Atoi(r.PathValue("id"))
+	if err == nil {
+		if c, ok := findCourse(n); ok {
+			return c, true
+		}
+	}
+	writeJSONError(w, http.StatusNotFound, fmt.Sprintf("no course %q", r.PathValue("id")))
+	return course{}, false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("api: %v", err)
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
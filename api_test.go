@@ -0,0 +1,110 @@
+package learn
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Run with: go test -run API
+
+func TestAPI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	quizzes, err := loadQuizzes("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer((&apiServer{progressPath: path, quizzes: quizzes, now: func() time.Time { return at }}).routes())
+	defer srv.Close()
+
+	call := func(method, url, body string, v any) int {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+url, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" && resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: Content-Type %q", method, url, ct)
+		}
+		if v != nil {
+			if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+				t.Fatalf("%s %s: %v", method, url, err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	var list []apiCourseSummary
+	if call("GET", "/courses", "", &list); len(list) != len(courses) || list[2].URL != "/courses/3" {
+		t.Errorf("GET /courses: %+v", list)
+	}
+
+	var c apiCourse
+	call("GET", "/courses/1", "", &c)
+	if c.Name != "BASICS" || len(c.Sections) == 0 || len(c.Takeaways) == 0 || c.QuizQuestions != len(quizzes[1]) {
+		t.Errorf("GET /courses/1: %+v", c)
+	}
+	if strings.Join(c.Exercises, ",") != "fizzbuzz,reverse" {
+		t.Errorf("course 1 exercises: %v", c.Exercises)
+	}
+	for _, url := range []string{"/courses/99", "/courses/x", "/quiz/0"} {
+		if code := call("GET", url, "", nil); code != http.StatusNotFound {
+			t.Errorf("GET %s: got %d, want 404", url, code)
+		}
+	}
+
+	// The questions go out without their answers
+	var raw map[string]any
+	call("GET", "/quiz/2", "", &raw)
+	if q := raw["questions"].([]any); len(q) != len(quizzes[2]) || q[0].(map[string]any)["answer"] != nil {
+		t.Errorf("GET /quiz/2: %v", raw)
+	}
+
+	for _, body := range []string{`{"answers": [1]}`, `{"answer": [1, 1, 2]}`, `not json`} {
+		if code := call("POST", "/quiz/2", body, nil); code != http.StatusBadRequest {
+			t.Errorf("POST %s: got %d, want 400", body, code)
+		}
+	}
+
+	var scored struct {
+		Score   int
+		Results []apiAnswer
+	}
+	answers := []int{quizzes[2][0].Answer, quizzes[2][1].Answer, quizzes[2][2].Answer + 1}
+	b, _ := json.Marshal(map[string]any{"answers": answers})
+	call("POST", "/quiz/2", string(b), &scored)
+	if scored.Score != 66 || !scored.Results[0].Correct || scored.Results[2].Correct {
+		t.Errorf("POST /quiz/2: %+v", scored)
+	}
+
+	var p progress
+	call("GET", "/progress", "", &p)
+	if got := p.Quizzes[2]; got.Score != 66 || got.Attempts != 1 || !got.TakenAt.Equal(at) {
+		t.Errorf("progress after quiz: %+v", p.Quizzes)
+	}
+	if code := call("DELETE", "/progress", "", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE /progress: got %d", code)
+	}
+}
+
+func TestQuizzes(t *testing.T) {
+	quizzes, err := loadQuizzes("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range courses {
+		if len(quizzes[c.number]) == 0 {
+			t.Errorf("course %d has no quiz", c.number)
+		}
+	}
+	if len(quizzes) != len(courses) {
+		t.Errorf("%d quizzes for %d courses", len(quizzes), len(courses))
+	}
+}
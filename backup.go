@@ -0,0 +1,327 @@
+package learn
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/safefile"
+)
+
+// A backup is a zip of everything the learner has made:
+//
+//	progress/progress.json   grades, quizzes, reviews, tracks
+//	progress/notes.json      notes (go run ./cmd/learn note)
+//	progress/time.json       time spent per course
+//	exercises/<name>/*.go    solutions to the exercises
+//	challenges/*.go          daily challenges
+//
+// The names in the zip are fixed, not the paths on disk, so a backup made
+// with one -progress restores under another.
+
+// backupPlaces are where the learner's files live on disk.
+type backupPlaces struct {
+	progress   string // the progress file; notes and the time log sit next to it
+	exercises  string
+	challenges string
+}
+
+// maxRestoreSize is the most one file of a backup may unpack to. A zip
+// says how big its files are, but a crafted one can lie, or unpack a few
+// KB into GBs: the size is checked, and the read cut off there too.
+const maxRestoreSize = 16 << 20
+
+// files lists what a backup of places holds: the name in the zip, and the
+// path on disk. Files that don't exist yet are left out.
+func (b backupPlaces) files() (map[string]string, error) {
+	files := map[string]string{
+		"progress/progress.json": b.progress,
+		"progress/notes.json":    notesPath(b.progress),
+		"progress/time.json":     studyLogPath(b.progress),
+	}
+	for name, p := range files {
+		if _, err := os.Stat(p); errors.Is(err, fs.ErrNotExist) {
+			delete(files, name)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	for prefix, dir := range map[string]string{"exercises": b.exercises, "challenges": b.challenges} {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && p == dir {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() || !strings.HasSuffix(p, ".go") {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			files[prefix+"/"+filepath.ToSlash(rel)] = p
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// target is where the zip entry name is restored to. Names are checked
+// before anything is written: a name like exercises/../../.bashrc would
+// otherwise write outside the exercises folder, the "zip slip".
+func (b backupPlaces) target(name string) (string, error) {
+	switch name {
+	case "progress/progress.json":
+		return b.progress, nil
+	case "progress/notes.json":
+		return notesPath(b.progress), nil
+	case "progress/time.json":
+		return studyLogPath(b.progress), nil
+	}
+	prefix, rel, _ := strings.Cut(name, "/")
+	dir := map[string]string{"exercises": b.exercises, "challenges": b.challenges}[prefix]
+	if dir == "" || !filepath.IsLocal(rel) || path.Ext(rel) != ".go" {
+		return "", fmt.Errorf("%q doesn't belong in a backup", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(rel)), nil
+}
+
+// writeBackup writes a zip of the files in places to w, and returns how
+// many it holds. Each entry keeps its file's mode and modification time.
+func writeBackup(w io.Writer, places backupPlaces, now time.Time) (int, error) {
+	files, err := places.files()
+	if err != nil {
+		return 0, err
+	}
+	z := zip.NewWriter(w)
+	if err := z.SetComment("learning-golang backup, " + now.Format(time.RFC3339)); err != nil {
+		return 0, err
+	}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if err := addToZip(z, name, files[name]); err != nil {
+			return 0, err
+		}
+	}
+	return len(files), z.Close()
+}
+
+func addToZip(z *zip.Writer, name, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	h, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	h.Name = name
+	h.Method = zip.Deflate // FileInfoHeader leaves it at Store
+	w, err := z.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// restoreFile is one entry of a backup, read and checked.
+type restoreFile struct {
+	target string
+	data   []byte
+	mode   fs.FileMode
+	mtime  time.Time
+}
+
+// restoreBackup unpacks z into places and returns the files written, and
+// how many it skipped as already holding the same contents. It refuses to
+// overwrite a file whose contents differ unless force is set, and checks
+// every entry before writing any, so a bad backup changes nothing.
+func restoreBackup(z *zip.Reader, places backupPlaces, force bool) (written []string, same int, err error) {
+	// The same lock as updateProgress, taken before the files are compared:
+	// a grade or a quiz saving progress while the restore runs would
+	// otherwise be overwritten unchecked, or overwrite the restored file
+	if err := os.MkdirAll(filepath.Dir(places.progress), 0o755); err != nil {
+		return nil, 0, err
+	}
+	lock, err := safefile.Obtain(places.progress + ".lock")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer lock.Release()
+
+	var files []restoreFile
+	var changed []string
+	for _, f := range z.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		target, err := places.target(f.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if f.UncompressedSize64 > maxRestoreSize {
+			return nil, 0, fmt.Errorf("%s: %d bytes is too big for a backup", f.Name, f.UncompressedSize64)
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, 0, err
+		}
+		old, err := os.ReadFile(target)
+		if err == nil && bytes.Equal(old, data) {
+			same++
+			continue
+		}
+		if err == nil {
+			changed = append(changed, target)
+		}
+		mode := f.Mode().Perm()
+		if mode == 0 {
+			mode = 0o644
+		}
+		files = append(files, restoreFile{target, data, mode, f.Modified})
+	}
+	if len(changed) > 0 && !force {
+		return nil, 0, fmt.Errorf("restoring would overwrite %d files that differ from the backup (use -force to overwrite them):\n  %s",
+			len(changed), strings.Join(changed, "\n  "))
+	}
+
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.target), 0o755); err != nil {
+			return written, same, err
+		}
+		if err := safefile.WriteFile(f.target, f.data, f.mode); err != nil {
+			return written, same, err
+		}
+		written = append(written, f.target)
+		if !f.mtime.IsZero() {
+			if err := os.Chtimes(f.target, f.mtime, f.mtime); err != nil {
+				return written, same, err
+			}
+		}
+	}
+	return written, same, nil
+}
+
+// readZipFile reads an entry, never more than maxRestoreSize bytes, and
+// checks its CRC-32: the zip reader reports a mismatch at the end of the
+// read.
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxRestoreSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if len(data) > maxRestoreSize {
+		return nil, fmt.Errorf("%s: unpacks to more than %d bytes", f.Name, maxRestoreSize)
+	}
+	return data, nil
+}
+
+// backupFlags are the flags backup and restore share: where the files are.
+func backupFlags(flags *flag.FlagSet) *backupPlaces {
+	var b backupPlaces
+	flags.StringVar(&b.progress, "progress", defaultProgressPath(), "progress file; notes and the time log sit next to it")
+	flags.StringVar(&b.exercises, "dir", "exercises", "folder holding your exercise solutions")
+	flags.StringVar(&b.challenges, "challenges", "challenges", "folder holding your challenges")
+	return &b
+}
+
+// runBackup implements "go run ./cmd/learn backup [flags]".
+func runBackup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	places := backupFlags(flags)
+	out := flags.String("out", ".", "folder to write the backup to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn backup [flags]")
+		fmt.Fprintln(flags.Output(), "Saves your progress, notes, time log, exercise solutions and challenges")
+		fmt.Fprintln(flags.Output(), "to a timestamped zip. Bring them back with: go run ./cmd/learn restore <zip>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	n, err := writeBackup(&buf, *places, now)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("nothing to back up yet")
+	}
+	name := filepath.Join(*out, "learning-golang-backup-"+now.Format("20060102-150405")+".zip")
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	if err := safefile.WriteFile(name, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Backed up %d files to %s (%d bytes)\n", n, name, buf.Len())
+	return nil
+}
+
+// runRestore implements "go run ./cmd/learn restore [flags] <zip>".
+func runRestore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	places := backupFlags(flags)
+	force := flags.Bool("force", false, "overwrite files that differ from the backup")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn restore [flags] <zip>")
+		fmt.Fprintln(flags.Output(), "Unpacks a zip made by go run ./cmd/learn backup. If any file differs from")
+		fmt.Fprintln(flags.Output(), "the backup, nothing is restored unless -force is given.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("name the backup to restore")
+	}
+
+	z, err := zip.OpenReader(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer z.Close()
+	written, same, err := restoreBackup(&z.Reader, *places, *force)
+	for _, p := range written {
+		fmt.Println("restored", p)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Restored %d files from %s", len(written), flags.Arg(0))
+	if same > 0 {
+		fmt.Printf("; %d were already up to date", same)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,218 @@
+package learn
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/safefile"
+)
+
+// testPlaces writes a progress file, notes and two solutions under a
+// temporary directory.
+func testPlaces(t *testing.T) backupPlaces {
+	t.Helper()
+	dir := t.TempDir()
+	b := backupPlaces{
+		progress:   filepath.Join(dir, "config", "progress.json"),
+		exercises:  filepath.Join(dir, "exercises"),
+		challenges: filepath.Join(dir, "challenges"), // never created
+	}
+	for p, data := range map[string]string{
+		b.progress:            `{"exercises": {}}`,
+		notesPath(b.progress): `{"notes": []}`,
+		filepath.Join(b.exercises, "reverse", "rev.go"):   "package reverse\n",
+		filepath.Join(b.exercises, "reverse", "notes.md"): "not Go, not backed up",
+		filepath.Join(b.exercises, "fizzbuzz", "fb.go"):   "package fizzbuzz\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b
+}
+
+func backupZip(t *testing.T, b backupPlaces) *zip.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	n, err := writeBackup(&buf, b, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+	if err != nil || n != 4 {
+		t.Fatalf("writeBackup = %d, %v; want 4 files", n, err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return z
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	from := testPlaces(t)
+	mtime := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(from.exercises, "reverse", "rev.go"), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	z := backupZip(t, from)
+	var names []string
+	for _, f := range z.File {
+		names = append(names, f.Name)
+	}
+	want := []string{"exercises/fizzbuzz/fb.go", "exercises/reverse/rev.go", "progress/notes.json", "progress/progress.json"}
+	if !slices.Equal(names, want) {
+		t.Errorf("backup holds %q, want %q", names, want)
+	}
+	if z.Comment != "learning-golang backup, 2026-10-16T09:30:00Z" {
+		t.Errorf("comment = %q", z.Comment)
+	}
+
+	dir := t.TempDir()
+	to := backupPlaces{filepath.Join(dir, "progress.json"), filepath.Join(dir, "ex"), filepath.Join(dir, "ch")}
+	written, same, err := restoreBackup(z, to, false)
+	if err != nil || len(written) != 4 || same != 0 {
+		t.Fatalf("restoreBackup = %q, %d, %v", written, same, err)
+	}
+	for _, pair := range [][2]string{
+		{from.progress, to.progress},
+		{notesPath(from.progress), notesPath(to.progress)},
+		{filepath.Join(from.exercises, "reverse", "rev.go"), filepath.Join(to.exercises, "reverse", "rev.go")},
+	} {
+		want, _ := os.ReadFile(pair[0])
+		if got, err := os.ReadFile(pair[1]); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%s = %q, %v; want %q", pair[1], got, err, want)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(to.exercises, "reverse", "rev.go")); err != nil {
+		t.Error(err)
+	} else if !info.ModTime().Equal(mtime) {
+		t.Errorf("restored rev.go modified at %v, want %v", info.ModTime(), mtime)
+	}
+
+	// Again: everything is up to date
+	if written, same, err := restoreBackup(z, to, false); err != nil || len(written) != 0 || same != 4 {
+		t.Errorf("second restore = %q, %d, %v; want nothing written, 4 up to date", written, same, err)
+	}
+}
+
+func TestRestoreConflicts(t *testing.T) {
+	b := testPlaces(t)
+	z := backupZip(t, b)
+	rev := filepath.Join(b.exercises, "reverse", "rev.go")
+	if err := os.WriteFile(rev, []byte("package reverse // edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(b.progress)
+
+	_, _, err := restoreBackup(z, b, false)
+	if err == nil || !strings.Contains(err.Error(), "overwrite 1 files") || !strings.Contains(err.Error(), rev) {
+		t.Fatalf("restore over an edited file: %v", err)
+	}
+	if _, err := os.Stat(b.progress); err == nil {
+		t.Error("a refused restore wrote the missing progress file")
+	}
+
+	written, same, err := restoreBackup(z, b, true)
+	if err != nil || len(written) != 2 || same != 2 {
+		t.Fatalf("restore -force = %q, %d, %v; want the progress file and rev.go", written, same, err)
+	}
+	if data, _ := os.ReadFile(rev); string(data) != "package reverse\n" {
+		t.Errorf("rev.go = %q after -force", data)
+	}
+}
+
+// A grade saving progress while the restore waits for the lock is seen by
+// the comparison, not overwritten
+func TestRestoreWaitsForProgressLock(t *testing.T) {
+	b := testPlaces(t)
+	z := backupZip(t, b)
+	lock, err := safefile.Obtain(b.progress + ".lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		_, _, err := restoreBackup(z, b, false)
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	graded := `{"exercises": {"reverse": {}}}`
+	if err := os.WriteFile(b.progress, []byte(graded), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lock.Release()
+
+	if err := <-done; err == nil || !strings.Contains(err.Error(), "overwrite 1 files") {
+		t.Errorf("restore after a grade: %v, want a refusal", err)
+	}
+	if data, _ := os.ReadFile(b.progress); string(data) != graded {
+		t.Errorf("progress = %s, want the grade's", data)
+	}
+}
+
+// A zip made by hand may list folders too; they aren't files up to date
+func TestRestoreCountsFiles(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	zw.Create("exercises/")
+	zw.Create("exercises/reverse/")
+	w, _ := zw.Create("exercises/reverse/rev.go")
+	w.Write([]byte("package reverse\n"))
+	w, _ = zw.Create("exercises/reverse/new.go")
+	w.Write([]byte("package reverse\n"))
+	zw.Close()
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := testPlaces(t)
+	written, same, err := restoreBackup(z, b, false)
+	if err != nil || len(written) != 1 || same != 1 {
+		t.Errorf("restoreBackup = %q, %d, %v; want new.go written, rev.go up to date", written, same, err)
+	}
+}
+
+func TestRestoreRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"exercises/../../evil.go", "package evil", "doesn't belong"},
+		{"/etc/passwd", "root", "doesn't belong"},
+		{"progress/certificate.key", "key", "doesn't belong"},
+		{"exercises/x/run.sh", "rm -rf", "doesn't belong"},
+		{"exercises/x/big.go", strings.Repeat("a", maxRestoreSize+1), "too big"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			w, _ := zw.Create("exercises/ok/ok.go")
+			w.Write([]byte("package ok"))
+			w, _ = zw.Create(tt.name)
+			w.Write([]byte(tt.data))
+			zw.Close()
+			z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b := testPlaces(t)
+			if _, _, err := restoreBackup(z, b, true); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+			// Nothing was written, not even the good entry
+			if _, err := os.Stat(filepath.Join(b.exercises, "ok", "ok.go")); err == nil {
+				t.Error("a rejected backup was partly restored")
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// runCertificate implements "go run ./cmd/learn certificate [flags]" and
+// "go run ./cmd/learn certificate verify file".
+func runCertificate(args []string) error {
+	if len(args) > 0 && args[0] == "verify" {
+		return runVerifyCertificate(args[1:])
+	}
+	flags := flag.NewFlagSet("certificate", flag.ContinueOnError)
+	name := flags.String("name", "", "your name as it should appear (default: your account's full name)")
+	out := flags.String("o", "certificate.txt", "file to write the signed certificate to")
+	pngPath := flags.String("png", "", "also write the certificate as a PNG image to this file")
+	pdfPath := flags.String("pdf", "", "also write the certificate as a PDF to this file")
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file to check")
+	keyPath := flags.String("key", "", "signing key (default: certificate.key next to the progress file)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn certificate [flags]")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn certificate verify [-key file] certificate.txt")
+		fmt.Fprintln(flags.Output(), "Once every course is read and every quiz and exercise passed, writes a")
+		fmt.Fprintln(flags.Output(), "signed certificate of completion.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *keyPath == "" {
+		*keyPath = certificateKeyPath(*progressPath)
+	}
+
+	p, err := loadProgress(*progressPath)
+	if err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	log, err := loadStudyLog(studyLogPath(*progressPath))
+	if err != nil {
+		return fmt.Errorf("reading the time log: %w", err)
+	}
+	if todo := unfinished(courseStats(p, log)); len(todo) > 0 {
+		fmt.Println("Not quite there yet. Still to do:")
+		for _, t := range todo {
+			fmt.Println("  " + t)
+		}
+		return errors.New("the certificate needs every course, quiz and exercise")
+	}
+
+	if *name == "" {
+		*name = accountName()
+	}
+	if err := checkCertificateName(*name); err != nil {
+		return err
+	}
+	key, err := certificateKey(*keyPath, true)
+	if err != nil {
+		return err
+	}
+	cert, err := issueCertificate(*name, time.Now(), key)
+	if err != nil {
+		return err
+	}
+
+	var text bytes.Buffer
+	writeCertificate(&termRenderer{w: &text, width: 80}, cert)
+	fmt.Fprintf(&text, "\nVerify with: go run ./cmd/learn certificate verify %s\n", *out)
+	if err := os.WriteFile(*out, text.Bytes(), 0o644); err != nil {
+		return err
+	}
+	writeCertificate(newTermRenderer(os.Stdout), cert)
+	fmt.Printf("\nSaved to %s\n", *out)
+
+	if *pngPath != "" {
+		if err := writeFileWith(*pngPath, func(w io.Writer) error { return writeCertificatePNG(w, cert) }); err != nil {
+			return err
+		}
+		fmt.Printf("Saved to %s\n", *pngPath)
+	}
+	if *pdfPath != "" {
+		if err := writeFileWith(*pdfPath, func(w io.Writer) error { return writeCertificatePDF(w, cert) }); err != nil {
+			return err
+		}
+		fmt.Printf("Saved to %s\n", *pdfPath)
+	}
+	return nil
+}
+
+func runVerifyCertificate(args []string) error {
+	flags := flag.NewFlagSet("certificate verify", flag.ContinueOnError)
+	keyPath := flags.String("key", certificateKeyPath(defaultProgressPath()), "signing key the certificate was issued with")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn certificate verify [-key file] certificate.txt")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("name one certificate file")
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	cert, err := parseCertificate(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", flags.Arg(0), err)
+	}
+	key, err := certificateKey(*keyPath, false)
+	if err != nil {
+		return err
+	}
+	if !cert.valid(key) {
+		return fmt.Errorf("%s: INVALID: the signature doesn't match, so the certificate was changed or signed with another key", flags.Arg(0))
+	}
+	fmt.Printf("%s: valid. Issued to %s on %s for completing %d courses.\n", flags.Arg(0), cert.Name, cert.Completed, cert.Courses)
+	return nil
+}
+
+// unfinished lists what stands between the learner and the certificate.
+func unfinished(stats []courseStat) []string {
+	var todo []string
+	for _, s := range stats {
+		n := s.course.number
+		if !s.read {
+			todo = append(todo, fmt.Sprintf("course %d: run it to the end (go run ./cmd/learn %d)", n, n))
+		}
+		if s.quiz < quizPassMark {
+			todo = append(todo, fmt.Sprintf("course %d: pass the quiz (at least %d%%)", n, quizPassMark))
+		}
+		for _, e := range s.exercises {
+			if e.best < 100 {
+				todo = append(todo, fmt.Sprintf("course %d: pass exercise %s (go run ./cmd/learn grade %s)", n, e.name, e.name))
+			}
+		}
+	}
+	return todo
+}
+
+// certificate is a signed statement that someone finished the course.
+type certificate struct {
+	Name      string
+	Completed string // YYYY-MM-DD
+	Courses   int
+	ID        string // random, so that no two certificates are alike
+	Signature string // hex HMAC-SHA256 of the fields above
+}
+
+func issueCertificate(name string, at time.Time, key []byte) (certificate, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return certificate{}, err
+	}
+	c := certificate{Name: name, Completed: at.Format(dateLayout), Courses: len(courses), ID: hex.EncodeToString(id)}
+	c.Signature = c.sign(key)
+	return c, nil
+}
+
+// sign computes the signature of everything but the signature. The
+// fields go one per line, and names can't contain line breaks, so no two
+// certificates sign the same text.
+func (c certificate) sign(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "learning-golang certificate v1\nname=%s\ncompleted=%s\ncourses=%d\nid=%s\n", c.Name, c.Completed, c.Courses, c.ID)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c certificate) valid(key []byte) bool {
+	got, err := hex.DecodeString(c.Signature)
+	if err != nil {
+		return false
+	}
+	want, _ := hex.DecodeString(c.sign(key))
+	return hmac.Equal(got, want)
+}
+
+// maxNameLength keeps a name inside the certificate's frame.
+const maxNameLength = 48
+
+func checkCertificateName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return errors.New("who is the certificate for? Give your name with -name")
+	case name != strings.TrimSpace(name) || strings.IndexFunc(name, unicode.IsControl) >= 0:
+		return fmt.Errorf("the name %q has spaces at the ends or control characters", name)
+	case utf8.RuneCountInString(name) > maxNameLength:
+		return fmt.Errorf("the name is too long for the certificate (at most %d characters)", maxNameLength)
+	}
+	return nil
+}
+
+// accountName is the full name of the user running the program, or "".
+func accountName() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	// On Unix, Name is the GECOS field: "Full Name,room,phone..."
+	name, _, _ := strings.Cut(u.Name, ",")
+	return strings.TrimSpace(name)
+}
+
+// certificateKeyPath is where the signing key lives by default.
+func certificateKeyPath(progressPath string) string {
+	return filepath.Join(filepath.Dir(progressPath), "certificate.key")
+}
+
+// certificateKey reads the hex signing key at path. When create is set, a
+// missing key is generated and saved, readable only by the learner.
+// Anyone with the key can verify certificates, and issue them: to check
+// certificates for a class, share one key file and pass it with -key.
+func certificateKey(path string, create bool) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && create {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		return key, os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0o600)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no signing key at %s: certificates can only be verified with the key they were issued with (-key)", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) < 16 {
+		return nil, fmt.Errorf("%s is not a signing key", path)
+	}
+	return key, nil
+}
+
+// certificateFields are the lines under the frame, which verify reads
+// back.
+func certificateFields(c certificate) []string {
+	return []string{
+		"name: " + c.Name,
+		"completed: " + c.Completed,
+		"courses: " + strconv.Itoa(c.Courses),
+		"id: " + c.ID,
+		"signature: " + c.Signature,
+	}
+}
+
+// parseCertificate reads the fields of a certificate file back, ignoring
+// the frame around them.
+func parseCertificate(data []byte) (certificate, error) {
+	var c certificate
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "name":
+			c.Name = value
+		case "completed":
+			c.Completed = value
+		case "courses":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return c, fmt.Errorf("courses: %q is not a number", value)
+			}
+			c.Courses = n
+		case "id":
+			c.ID = value
+		case "signature":
+			c.Signature = value
+		default:
+			continue
+		}
+		seen[key] = true
+	}
+	for _, key := range []string{"name", "completed", "courses", "id", "signature"} {
+		if !seen[key] {
+			return c, fmt.Errorf("not a certificate: no %q line", key)
+		}
+	}
+	return c, nil
+}
+
+// certificateLines is the text of the certificate, shared by every format.
+func certificateLines(c certificate) (title, intro, name, body1, body2, date string) {
+	completed, _ := time.Parse(dateLayout, c.Completed)
+	return "CERTIFICATE OF COMPLETION",
+		"This certifies that",
+		c.Name,
+		fmt.Sprintf("has completed all %d courses, quizzes and exercises of", c.Courses),
+		"the Complete Go Developer Learning Course",
+		completed.Format("2 January 2006")
+}
+
+// writeCertificate draws the certificate in a frame of text, followed by
+// its fields.
+func writeCertificate(out *termRenderer, c certificate) {
+	const inner = 62
+	title, intro, name, body1, body2, date := certificateLines(c)
+	line := func(style, s string) {
+		pad := inner - utf8.RuneCountInString(s)
+		border := out.paint(styleBanner, "‖")
+		fmt.Fprintf(out.w, "%s%s%s%s%s\n", border, strings.Repeat(" ", pad/2), out.paint(style, s), strings.Repeat(" ", pad-pad/2), border)
+	}
+	rule := out.paint(styleBanner, "╬"+strings.Repeat("═", inner)+"╬")
+
+	fmt.Fprintln(out.w, rule)
+	line("", "")
+	line(styleBold, title)
+	line("", strings.Repeat("~", utf8.RuneCountInString(title)))
+	line("", "")
+	line("", intro)
+	line("", "")
+	line(styleHeading, name)
+	line("", "")
+	line("", body1)
+	line("", body2)
+	line("", "")
+	line("", date)
+	line("", "")
+	fmt.Fprintln(out.w, rule)
+	for _, f := range certificateFields(c) {
+		fmt.Fprintln(out.w, out.paint(styleComment, f))
+	}
+}
+
+// writeCertificatePDF lays the certificate out on one A4 page.
+func writeCertificatePDF(w io.Writer, c certificate) error {
+	title, intro, name, body1, body2, date := certificateLines(c)
+	d := &pdfDoc{}
+	d.newPage()
+	// Two frames, the outer one thick, in Go blue
+	fmt.Fprintf(d.page, "0 0.678 0.847 RG 6 w %.2f %.2f %.2f %.2f re S\n", pdfMargin/2, pdfMargin/2, pdfWidth-pdfMargin, pdfHeight-pdfMargin)
+	fmt.Fprintf(d.page, "1 w %.2f %.2f %.2f %.2f re S 0 G\n", pdfMargin/2+12, pdfMargin/2+12, pdfWidth-pdfMargin-24, pdfHeight-pdfMargin-24)
+
+	centre := func(font string, size, y float64, s string) {
+		fmt.Fprintf(d.page, "BT /%s %g Tf %.2f %.2f Td %s Tj ET\n", font, size, (pdfWidth-textWidth(font, size, s))/2, y, pdfString(s))
+	}
+	centre(fontBold, 28, 640, title)
+	centre(fontRegular, 14, 560, intro)
+	size := 30.0
+	for size > 14 && textWidth(fontBold, size, name) > pdfText-40 {
+		size -= 2
+	}
+	centre(fontBold, size, 500, name)
+	fmt.Fprintf(d.page, "0.6 G 0.5 w %.2f 488 m %.2f 488 l S 0 G\n", pdfMargin+60, pdfWidth-pdfMargin-60)
+	centre(fontRegular, 13, 440, body1)
+	centre(fontRegular, 13, 420, body2)
+	centre(fontRegular, 13, 360, date)
+	for i, f := range certificateFields(c) {
+		centre(fontMono, 7.5, 150-float64(i)*11, f)
+	}
+	return d.write(w)
+}
+
+// writeFileWith creates path and writes it with write.
+func writeFileWith(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,127 @@
+package learn
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCertificateSignature(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	cert, err := issueCertificate("Ada Lovelace", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cert.valid(key) {
+		t.Fatal("a fresh certificate doesn't verify")
+	}
+
+	// What verify reads back is what was signed
+	var buf bytes.Buffer
+	writeCertificate(&termRenderer{w: &buf, width: 80}, cert)
+	read, err := parseCertificate(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != cert || !read.valid(key) {
+		t.Errorf("read back %+v, want %+v", read, cert)
+	}
+	if !strings.Contains(buf.String(), "1 March 2024") {
+		t.Errorf("certificate has no date:\n%s", buf.String())
+	}
+
+	forged := strings.Replace(buf.String(), "name: Ada Lovelace", "name: Charles Babbage", 1)
+	if c, err := parseCertificate([]byte(forged)); err != nil || c.valid(key) {
+		t.Errorf("a changed name still verifies (err %v)", err)
+	}
+	if cert.valid([]byte("another key, just as long as it")) {
+		t.Error("verifies with another key")
+	}
+	if _, err := parseCertificate([]byte("name: Ada\n")); err == nil {
+		t.Error("parsed a certificate with no signature")
+	}
+}
+
+func TestCertificateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "certificate.key")
+	if _, err := certificateKey(path, false); err == nil {
+		t.Error("verifying without a key should fail")
+	}
+	key, err := certificateKey(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("key file: %v, %v; want mode 0600", fi, err)
+	}
+	again, err := certificateKey(path, false)
+	if err != nil || !bytes.Equal(key, again) {
+		t.Errorf("the key changed after saving: %v", err)
+	}
+}
+
+func TestUnfinished(t *testing.T) {
+	p := &progress{Exercises: map[string]exerciseProgress{}, Quizzes: map[int]quizProgress{}}
+	log := &studyLog{Courses: map[int]courseTime{}}
+	for _, c := range courses {
+		log.Courses[c.number] = courseTime{Finished: 1}
+		p.Quizzes[c.number] = quizProgress{Best: 100}
+	}
+	for _, ex := range exerciseList {
+		p.Exercises[ex.name] = exerciseProgress{Best: 100}
+	}
+	if todo := unfinished(courseStats(p, log)); len(todo) != 0 {
+		t.Errorf("everything passed, but still to do: %q", todo)
+	}
+
+	p.Quizzes[4] = quizProgress{Best: 33}
+	p.Exercises["reverse"] = exerciseProgress{Best: 50}
+	delete(log.Courses, 20)
+	todo := unfinished(courseStats(p, log))
+	want := []string{
+		"course 1: pass exercise reverse (go run ./cmd/learn grade reverse)",
+		"course 4: pass the quiz (at least 60%)",
+		"course 20: run it to the end (go run ./cmd/learn 20)",
+	}
+	if strings.Join(todo, "\n") != strings.Join(want, "\n") {
+		t.Errorf("still to do:\n%s\nwant:\n%s", strings.Join(todo, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCertificateNames(t *testing.T) {
+	for _, name := range []string{"", " Ada", "Ada\nLovelace", strings.Repeat("a", maxNameLength+1)} {
+		if checkCertificateName(name) == nil {
+			t.Errorf("accepted the name %q", name)
+		}
+	}
+	if err := checkCertificateName("Ada Lovelace"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCertificateFiles(t *testing.T) {
+	cert := certificate{Name: "Ada Lovelace", Completed: "2024-03-01", Courses: 20, ID: "00ff", Signature: "abcd"}
+	var buf bytes.Buffer
+	if err := writeCertificatePDF(&buf, cert); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) || !bytes.Contains(buf.Bytes(), []byte("(Ada Lovelace)")) {
+		t.Error("the PDF doesn't look like a certificate for Ada Lovelace")
+	}
+
+	buf.Reset()
+	if err := writeCertificatePNG(&buf, cert); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 1200 || b.Dy() != 850 {
+		t.Errorf("image is %v", b)
+	}
+}
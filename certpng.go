@@ -0,0 +1,146 @@
+package learn
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// writeCertificatePNG draws the certificate as a 1200x850 image. The
+// standard library has no font rasteriser, so the text is drawn with a
+// small built-in bitmap font, in capitals.
+func writeCertificatePNG(w io.Writer, c certificate) error {
+	const width, height = 1200, 850
+	var (
+		paper = color.RGBA{0xfd, 0xfb, 0xf3, 0xff}
+		blue  = color.RGBA{0x00, 0xad, 0xd8, 0xff} // Go blue
+		ink   = color.RGBA{0x20, 0x20, 0x28, 0xff}
+		grey  = color.RGBA{0x88, 0x88, 0x90, 0xff}
+	)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(paper), image.Point{}, draw.Src)
+	frame(img, image.Rect(24, 24, width-24, height-24), 10, blue)
+	frame(img, image.Rect(48, 48, width-48, height-48), 2, blue)
+
+	title, intro, name, body1, body2, date := certificateLines(c)
+	centre := func(y, scale int, col color.Color, s string) {
+		s = strings.ToUpper(s)
+		// Shrink text that would run into the frame
+		for scale > 1 && glyphWidth(s, scale) > width-160 {
+			scale--
+		}
+		drawText(img, (width-glyphWidth(s, scale))/2, y, scale, col, s)
+	}
+	centre(130, 7, ink, title)
+	draw.Draw(img, image.Rect(300, 210, width-300, 214), image.NewUniform(blue), image.Point{}, draw.Src)
+	centre(280, 3, grey, intro)
+	centre(350, 8, ink, name)
+	centre(480, 3, ink, body1)
+	centre(520, 3, ink, body2)
+	centre(600, 3, grey, date)
+	centre(height-140, 2, grey, "ID "+c.ID)
+	centre(height-110, 1, grey, "SIGNATURE "+c.Signature)
+	return png.Encode(w, img)
+}
+
+// frame draws a border of the given thickness just inside r.
+func frame(img draw.Image, r image.Rectangle, thickness int, col color.Color) {
+	u := image.NewUniform(col)
+	for _, edge := range []image.Rectangle{
+		{r.Min, image.Pt(r.Max.X, r.Min.Y+thickness)},
+		{image.Pt(r.Min.X, r.Max.Y-thickness), r.Max},
+		{r.Min, image.Pt(r.Min.X+thickness, r.Max.Y)},
+		{image.Pt(r.Max.X-thickness, r.Min.Y), r.Max},
+	} {
+		draw.Draw(img, edge, u, image.Point{}, draw.Src)
+	}
+}
+
+// glyphWidth is the width of s drawn at scale: 5 columns per glyph and
+// one between glyphs.
+func glyphWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (6*n - 1) * scale
+}
+
+// drawText draws s with its top left corner at x, y, each dot of the font
+// a scale x scale square. Letters the font lacks are drawn as '?'.
+func drawText(img draw.Image, x, y, scale int, col color.Color, s string) {
+	u := image.NewUniform(col)
+	for _, r := range s {
+		g, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			g = glyphs['?']
+		}
+		for row, bits := range g {
+			for column := range 5 {
+				if bits&(1<<(4-column)) == 0 {
+					continue
+				}
+				dot := image.Rect(x+column*scale, y+row*scale, x+(column+1)*scale, y+(row+1)*scale)
+				draw.Draw(img, dot, u, image.Point{}, draw.Src)
+			}
+		}
+		x += 6 * scale
+	}
+}
+
+// glyphs is a 5x7 bitmap font: one byte per row, the high bit of the
+// five on the left.
+var glyphs = map[rune][7]byte{
+	' ':  {},
+	'A':  {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C':  {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D':  {0b11110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b11110},
+	'E':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G':  {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H':  {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I':  {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J':  {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K':  {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L':  {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M':  {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N':  {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S':  {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T':  {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W':  {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X':  {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y':  {0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100, 0b00100},
+	'Z':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'0':  {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1':  {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2':  {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3':  {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4':  {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5':  {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6':  {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8':  {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9':  {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'.':  {0, 0, 0, 0, 0, 0b01100, 0b01100},
+	',':  {0, 0, 0, 0, 0b01100, 0b00100, 0b01000},
+	'-':  {0, 0, 0, 0b11111, 0, 0, 0},
+	':':  {0, 0b01100, 0b01100, 0, 0b01100, 0b01100, 0},
+	'\'': {0b01100, 0b00100, 0b01000, 0, 0, 0, 0},
+	'/':  {0, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0},
+	'&':  {0b01100, 0b10010, 0b10100, 0b01000, 0b10101, 0b10010, 0b01101},
+	'(':  {0b00010, 0b00100, 0b01000, 0b01000, 0b01000, 0b00100, 0b00010},
+	')':  {0b01000, 0b00100, 0b00010, 0b00010, 0b00010, 0b00100, 0b01000},
+	'!':  {0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0, 0b00100},
+	'?':  {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0, 0b00100},
+}
@@ -0,0 +1,259 @@
+package learn
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// challenge is a small task from the daily practice bank. It grades like
+// an exercise: the hidden tests are in testdata/grader/<name>_test.go and
+// the reference solution in solutions/<name>.
+type challenge struct {
+	exercise
+	summary string
+}
+
+// challengeList is the bank, in course order.
+var challengeList = []challenge{
+	{exercise{"dedupe", 1}, "remove repeats from a slice, keeping the order"},
+	{exercise{"reverserunes", 1}, "reverse UTF-8 text in place without allocating"},
+	{exercise{"balanced", 1}, "check that brackets are balanced"},
+	{exercise{"topwords", 1}, "the k most frequent words in a text"},
+	{exercise{"lru", 3}, "a least-recently-used cache"},
+	{exercise{"workerpool", 4}, "map over jobs with a fixed number of goroutines"},
+	{exercise{"retry", 16}, "retry a call, joining the errors"},
+	{exercise{"safecounter", 19}, "a counter safe for concurrent use"},
+	{exercise{"countingwriter", 20}, "an io.Writer that counts bytes and lines"},
+}
+
+// Starter files live in testdata so that go build never sees them; the
+// challenge command copies them out on demand.
+//
+//go:embed testdata/challenges/*.go
+var challengeStarters embed.FS
+
+// runChallenge implements "go run ./cmd/learn challenge [flags] [name | list | grade [name]]".
+func runChallenge(args []string) error {
+	flags := flag.NewFlagSet("challenge", flag.ContinueOnError)
+	dir := flags.String("dir", "challenges", "folder to write challenges into")
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file to update")
+	timeout := flags.Duration("timeout", time.Minute, "time limit for grading")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn challenge [flags] [name]")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn challenge [flags] grade [name]")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn challenge list")
+		fmt.Fprintln(flags.Output(), "With no name, sets up today's challenge. grade checks today's, or the one named.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	p, err := loadProgress(*progressPath)
+	if err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	now := time.Now()
+	out := newTermRenderer(os.Stdout)
+
+	rest := flags.Args()
+	switch {
+	case len(rest) > 0 && rest[0] == "list":
+		listChallenges(out.w, p)
+		return nil
+
+	case len(rest) > 0 && rest[0] == "grade":
+		ch, err := pickChallenge(p, rest[1:], now)
+		if err != nil {
+			return err
+		}
+		if _, err := exec.LookPath("go"); err != nil {
+			return errors.New("grading needs the go command on your PATH")
+		}
+		fmt.Fprintf(os.Stderr, "grading %s...\n", ch.name)
+		r := gradeExercise(context.Background(), *dir, ch.exercise, *timeout)
+		printReportCard(out.w, []gradeResult{r})
+		// Grading takes a while: record on the progress as it is now
+		p, err = updateProgress(*progressPath, func(p *progress) error {
+			if len(rest) == 1 {
+				p.dailyChallenge(now) // keep today's pick, so solving it doesn't change it
+			}
+			p.recordChallenge(r, now)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if r.percent() == 100 {
+			fmt.Fprintf(out.w, "\nSolved! Your streak: %s.\n", plural(p.challengeStreak(now), "day", "days"))
+		}
+		return nil
+
+	case len(rest) > 1:
+		flags.Usage()
+		return errors.New("name one challenge at a time")
+	}
+
+	var ch challenge
+	_, err = updateProgress(*progressPath, func(p *progress) error {
+		ch, err = pickChallenge(p, rest, now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return startChallenge(out, *dir, ch)
+}
+
+func findChallenge(name string) (challenge, bool) {
+	for _, ch := range challengeList {
+		if ch.name == name {
+			return ch, true
+		}
+	}
+	return challenge{}, false
+}
+
+// pickChallenge returns the named challenge, or else today's.
+func pickChallenge(p *progress, names []string, now time.Time) (challenge, error) {
+	if len(names) > 0 {
+		ch, ok := findChallenge(names[0])
+		if !ok {
+			return challenge{}, fmt.Errorf("no challenge named %q (run \"go run ./cmd/learn challenge list\")", names[0])
+		}
+		return ch, nil
+	}
+	return p.dailyChallenge(now), nil
+}
+
+// dailyChallenge is today's challenge. The first call of the day picks one
+// of the challenges not solved yet, chosen by the date so that it doesn't
+// look like a fixed order, and remembers it so that solving it doesn't
+// change the pick.
+func (p *progress) dailyChallenge(now time.Time) challenge {
+	today := now.Format(dateLayout)
+	if p.Daily.Date == today {
+		if ch, ok := findChallenge(p.Daily.Name); ok {
+			return ch
+		}
+	}
+	var open []challenge
+	for _, ch := range challengeList {
+		if p.Challenges[ch.name].Solved == "" {
+			open = append(open, ch)
+		}
+	}
+	if len(open) == 0 {
+		open = challengeList // all solved: practice makes perfect
+	}
+	h := fnv.New32a()
+	io.WriteString(h, today)
+	ch := open[h.Sum32()%uint32(len(open))]
+	p.Daily = dailyPick{Date: today, Name: ch.name}
+	return ch
+}
+
+// startChallenge writes the starter file, unless the learner already has
+// one, and shows the task.
+func startChallenge(out *termRenderer, dir string, ch challenge) error {
+	task, err := exerciseTask(ch.name)
+	if err != nil {
+		return err
+	}
+	// The comment ends with how to grade it, which is said below
+	if i := slices.IndexFunc(task, func(line string) bool { return strings.HasPrefix(line, "Grade it with:") }); i >= 0 {
+		task = task[:i]
+	}
+	path := filepath.Join(dir, ch.name, ch.name+".go")
+	created, err := scaffoldChallenge(path, ch.name)
+	if err != nil {
+		return err
+	}
+
+	out.heading(fmt.Sprintf("CHALLENGE: %s (course %d)", ch.name, ch.course))
+	out.markdown(task)
+	fmt.Fprintln(out.w)
+	if created {
+		fmt.Fprintf(out.w, "Your starter file is %s\n", path)
+	} else {
+		fmt.Fprintf(out.w, "You already started it in %s\n", path)
+	}
+	grade := "go run ./cmd/learn challenge grade"
+	if dir != "challenges" {
+		grade = fmt.Sprintf("go run ./cmd/learn challenge -dir %s grade", dir)
+	}
+	fmt.Fprintf(out.w, "Grade it with: %s %s\n", grade, ch.name)
+	return nil
+}
+
+// scaffoldChallenge copies the starter file to path. It reports false,
+// and leaves the file alone, if path already exists.
+func scaffoldChallenge(path, name string) (bool, error) {
+	src, err := challengeStarters.ReadFile("testdata/challenges/" + name + ".go")
+	if err != nil {
+		return false, fmt.Errorf("challenge %s has no starter file: %w", name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.Write(src); err != nil {
+		f.Close()
+		return false, err
+	}
+	return true, f.Close()
+}
+
+func listChallenges(w io.Writer, p *progress) {
+	fmt.Fprintln(w, "CHALLENGES")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	solved := 0
+	for _, ch := range challengeList {
+		status := ""
+		switch cp := p.Challenges[ch.name]; {
+		case cp.Solved != "":
+			status = "solved " + cp.Solved
+			solved++
+		case cp.Attempts > 0:
+			status = fmt.Sprintf("best %d%%", cp.Best)
+		}
+		fmt.Fprintf(tw, "  %s\tcourse %d\t%s\t%s\n", ch.name, ch.course, status, ch.summary)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\n%d of %d solved. Start one with: go run ./cmd/learn challenge <name>\n", solved, len(challengeList))
+}
+
+// challengeStreak counts the days in a row, up to today, on which the
+// learner solved a challenge. A streak isn't broken until today is over,
+// so one that ended yesterday still counts.
+func (p *progress) challengeStreak(now time.Time) int {
+	day := now
+	if !slices.Contains(p.ChallengeDays, day.Format(dateLayout)) {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for slices.Contains(p.ChallengeDays, day.Format(dateLayout)) {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
@@ -0,0 +1,108 @@
+package learn
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestChallengeSolutionsPass grades every challenge's reference solution,
+// and checks that its starter builds but doesn't pass.
+func TestChallengeSolutionsPass(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not on PATH")
+	}
+	solved, starters := t.TempDir(), t.TempDir()
+	for _, ch := range challengeList {
+		if _, ok := findCourse(ch.course); !ok {
+			t.Errorf("%s: course %d does not exist", ch.name, ch.course)
+		}
+		if _, err := scaffoldChallenge(filepath.Join(starters, ch.name, ch.name+".go"), ch.name); err != nil {
+			t.Fatal(err)
+		}
+		src, err := referenceSolutions.ReadFile(path.Join("solutions", ch.name, ch.name+".go"))
+		if err != nil {
+			t.Fatalf("%s has no reference solution: %v", ch.name, err)
+		}
+		os.MkdirAll(filepath.Join(solved, ch.name), 0o755)
+		os.WriteFile(filepath.Join(solved, ch.name, ch.name+".go"), []byte(stripBuildTag(string(src))), 0o644)
+	}
+
+	for _, ch := range challengeList {
+		t.Run(ch.name, func(t *testing.T) {
+			t.Parallel()
+			r := gradeExercise(context.Background(), solved, ch.exercise, time.Minute)
+			if r.percent() != 100 {
+				t.Errorf("reference solution scored %d%%: failed %v %s %s", r.percent(), r.failed, r.problem, r.detail)
+			}
+			r = gradeExercise(context.Background(), starters, ch.exercise, time.Minute)
+			if r.problem != "" || r.percent() == 100 {
+				t.Errorf("starter scored %d%% (%s %s), want it to build and fail", r.percent(), r.problem, r.detail)
+			}
+		})
+	}
+}
+
+func TestDailyChallenge(t *testing.T) {
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	p := &progress{Challenges: map[string]challengeProgress{}}
+	pick := p.dailyChallenge(day)
+	if again := (&progress{}).dailyChallenge(day.Add(8 * time.Hour)); again.name != pick.name {
+		t.Errorf("the same day picked %s and %s", pick.name, again.name)
+	}
+
+	// Solving today's doesn't change it, but tomorrow's is a new one
+	p.recordChallenge(gradeResult{exercise: pick.exercise, passed: 3, total: 3}, day)
+	if got := p.dailyChallenge(day); got.name != pick.name {
+		t.Errorf("after solving it, today's challenge became %s", got.name)
+	}
+	for i := 1; i <= 30; i++ {
+		if got := p.dailyChallenge(day.AddDate(0, 0, i)); got.name == pick.name {
+			t.Fatalf("day %d picked %s again, which is solved", i, got.name)
+		}
+	}
+}
+
+func TestChallengeStreak(t *testing.T) {
+	day := time.Date(2024, 3, 10, 20, 0, 0, 0, time.UTC)
+	p := &progress{Challenges: map[string]challengeProgress{}}
+	solve := func(name string, daysAgo int) {
+		ch, _ := findChallenge(name)
+		p.recordChallenge(gradeResult{exercise: ch.exercise, passed: 1, total: 1}, day.AddDate(0, 0, -daysAgo))
+	}
+	solve("dedupe", 5)
+	solve("lru", 2)
+	solve("retry", 1)
+	if got := p.challengeStreak(day); got != 2 {
+		t.Errorf("streak = %d, want 2 (today isn't over yet)", got)
+	}
+	solve("dedupe", 0) // solved already, but practice still counts
+	if got := p.challengeStreak(day); got != 3 {
+		t.Errorf("streak = %d, want 3", got)
+	}
+	if got := p.Challenges["dedupe"]; got.Solved != "2024-03-05" || got.Attempts != 2 {
+		t.Errorf("dedupe = %+v, want first solved 2024-03-05 after 2 attempts", got)
+	}
+	if got := p.challengeStreak(day.AddDate(0, 0, 2)); got != 0 {
+		t.Errorf("streak after a missed day = %d, want 0", got)
+	}
+}
+
+func TestScaffoldChallenge(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "challenges", "dedupe", "dedupe.go")
+	if created, err := scaffoldChallenge(file, "dedupe"); err != nil || !created {
+		t.Fatalf("scaffold = %v, %v", created, err)
+	}
+	os.WriteFile(file, []byte("package dedupe // my work\n"), 0o644)
+	if created, err := scaffoldChallenge(file, "dedupe"); err != nil || created {
+		t.Errorf("second scaffold = %v, %v; want the file left alone", created, err)
+	}
+	if data, _ := os.ReadFile(file); !strings.Contains(string(data), "my work") {
+		t.Errorf("the learner's file was overwritten:\n%s", data)
+	}
+}
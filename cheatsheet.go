@@ -0,0 +1,106 @@
+package learn
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// runCheatsheet implements "go run ./cmd/learn cheatsheet [topic]". The entries
+// come from the "## Cheatsheet" section at the end of each lesson, so a
+// topic covered by several courses collects all of them.
+func runCheatsheet(args []string) error {
+	sheets, err := loadCheatsheets()
+	if err != nil {
+		return err
+	}
+	out := newTermRenderer(os.Stdout)
+	if len(args) == 0 {
+		listCheatTopics(out, sheets)
+		return nil
+	}
+
+	topic, err := findCheatTopic(sheets, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	printCheatsheet(out, topic, sheets[topic])
+	return nil
+}
+
+// cheatEntry is one course's part of a cheatsheet topic.
+type cheatEntry struct {
+	course course
+	lines  []string
+}
+
+// loadCheatsheets collects the cheatsheet topics of every course, in
+// course order.
+func loadCheatsheets() (map[string][]cheatEntry, error) {
+	sheets := make(map[string][]cheatEntry)
+	for _, c := range courses {
+		l, err := loadLesson(c.number)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range l.Cheatsheet {
+			sheets[t.Name] = append(sheets[t.Name], cheatEntry{c, t.Lines})
+		}
+	}
+	return sheets, nil
+}
+
+// findCheatTopic matches name against the topics: exactly, or as the
+// start of a single topic ("chan" for "channels").
+func findCheatTopic(sheets map[string][]cheatEntry, name string) (string, error) {
+	name = strings.ToLower(name)
+	if _, ok := sheets[name]; ok {
+		return name, nil
+	}
+	var found []string
+	for topic := range sheets {
+		if strings.HasPrefix(topic, name) {
+			found = append(found, topic)
+		}
+	}
+	slices.Sort(found)
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no cheatsheet for %q; run \"go run ./cmd/learn cheatsheet\" to list the topics", name)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("%q could be any of: %s", name, strings.Join(found, ", "))
+	}
+}
+
+func listCheatTopics(out *termRenderer, sheets map[string][]cheatEntry) {
+	topics := make([]string, 0, len(sheets))
+	for topic := range sheets {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+
+	out.heading("CHEATSHEET TOPICS")
+	tw := tabwriter.NewWriter(out.w, 0, 8, 3, ' ', 0)
+	for _, topic := range topics {
+		var from []string
+		for _, e := range sheets[topic] {
+			from = append(from, fmt.Sprint(e.course.number))
+		}
+		fmt.Fprintf(tw, "%s\tcourse %s\n", topic, strings.Join(from, ", "))
+	}
+	tw.Flush()
+	fmt.Fprintln(out.w, "\nShow one with: go run ./cmd/learn cheatsheet <topic>")
+}
+
+func printCheatsheet(out *termRenderer, topic string, entries []cheatEntry) {
+	out.banner("CHEATSHEET: " + strings.ToUpper(topic))
+	for _, e := range entries {
+		fmt.Fprintln(out.w)
+		fmt.Fprintln(out.w, out.paint(styleComment, fmt.Sprintf("course %d: %s", e.course.number, e.course.name)))
+		out.markdown(e.lines)
+	}
+}
@@ -0,0 +1,62 @@
+package learn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCheatsheetParse(t *testing.T) {
+	src := sampleLesson + "\n## Cheatsheet {#cheatsheet}\n\n### Maps\n```go\n### not a topic\n```\n### loops\nfor {}\n"
+	l, err := parseLesson(1, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Sections) != 3 || len(l.Takeaways) != 2 {
+		t.Fatalf("the cheatsheet changed the lesson: %+v", l)
+	}
+	if len(l.Cheatsheet) != 2 || l.Cheatsheet[0].Name != "maps" || len(l.Cheatsheet[0].Lines) != 3 || l.Cheatsheet[1].Name != "loops" {
+		t.Errorf("cheatsheet = %+v", l.Cheatsheet)
+	}
+
+	if _, err := parseLesson(1, sampleLesson+"\n## Cheatsheet {#cheatsheet}\n\nstray text\n"); err == nil {
+		t.Error("text before the first topic should be an error")
+	}
+}
+
+func TestCheatsheets(t *testing.T) {
+	sheets, err := loadCheatsheets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for topic, entries := range sheets {
+		for _, e := range entries {
+			if !strings.Contains(strings.Join(e.lines, "\n"), "```") {
+				t.Errorf("%s (course %d) has no code block", topic, e.course.number)
+			}
+		}
+	}
+	if got := len(sheets["testing commands"]); got != 2 {
+		t.Errorf("testing commands come from %d courses, want 2", got)
+	}
+
+	tests := []struct{ name, want, err string }{
+		{"channels", "channels", ""},
+		{"CHAN", "channels", ""},
+		{"testing", "testing", ""}, // exact beats prefix
+		{"t", "", "could be any of"},
+		{"kubernetes", "", "no cheatsheet"},
+	}
+	for _, tt := range tests {
+		got, err := findCheatTopic(sheets, tt.name)
+		if got != tt.want || (err == nil) != (tt.err == "") || (err != nil && !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("findCheatTopic(%q) = %q, %v", tt.name, got, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	printCheatsheet(&termRenderer{w: &buf, width: 80}, "select", sheets["select"])
+	if !strings.HasPrefix(buf.String(), "=== CHEATSHEET: SELECT ===\n\ncourse 4:") || !strings.Contains(buf.String(), "case <-ctx.Done():") {
+		t.Errorf("printed:\n%s", buf.String())
+	}
+}
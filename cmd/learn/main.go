@@ -0,0 +1,9 @@
+// Command learn runs the courses and the tools around them: see
+// 00-README.md.
+package main
+
+import learn "github.com/owolabijunior12/learning-golang"
+
+func main() {
+	learn.Main()
+}
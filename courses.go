@@ -0,0 +1,411 @@
+package learn
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/advanced"
+	"github.com/owolabijunior12/learning-golang/internal/courses/basics"
+	"github.com/owolabijunior12/learning-golang/internal/courses/concurrency"
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases"
+	"github.com/owolabijunior12/learning-golang/internal/courses/errorhandling"
+	"github.com/owolabijunior12/learning-golang/internal/courses/fileio"
+	"github.com/owolabijunior12/learning-golang/internal/courses/gotesting"
+	"github.com/owolabijunior12/learning-golang/internal/courses/layout"
+	"github.com/owolabijunior12/learning-golang/internal/courses/patterns"
+	"github.com/owolabijunior12/learning-golang/internal/courses/types"
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// course describes one lesson file and the function that runs it.
+type course struct {
+	number      int
+	name        string
+	file        string
+	pkg         string // the package under internal/courses it is in
+	description string
+	run         func(ctx context.Context, w io.Writer) error // writes to w; stops at the next section once ctx is done
+	requires    []int                                        // courses to finish first
+}
+
+// source is the path of the course's file, from the module root.
+func (c course) source() string {
+	return path.Join("internal/courses", c.pkg, c.file)
+}
+
+// courses lists every course in study order, which puts each course after
+// the ones it requires.
+var courses = []course{
+	{1, "BASICS", "01-basics.go", "basics", "Variables, types, control flow, operators", basics.CourseOne, nil},
+	{2, "FUNCTIONS & ERRORS", "02-functions-and-errors.go", "basics", "Functions, error handling, defer, panic/recover", basics.CourseTwo, []int{1}},
+	{3, "STRUCTS & INTERFACES", "03-structs-and-interfaces.go", "types", "Structs, methods, interfaces, composition", types.CourseThree, []int{2}},
+	{4, "GOROUTINES & CHANNELS", "04-goroutines-and-channels.go", "concurrency", "Concurrency, goroutines, channels, select", concurrency.CourseFour, []int{2, 3}},
+	{5, "FILE HANDLING", "05-file-handling.go", "fileio", "File I/O, directory operations, buffered reading, binary formats, directory walks", fileio.CourseFive, []int{2}},
+	{6, "HTTP SERVER & REST", "06-http-server.go", "web", "HTTP servers, routing, JSON, middleware", web.CourseSix, []int{2, 3}},
+	{7, "SQL DATABASES", "07-sql-database.go", "databases", "PostgreSQL, MySQL, prepared statements, transactions", databases.CourseSeven, []int{6}},
+	{8, "MONGODB", "08-mongodb-database.go", "databases", "MongoDB driver, BSON, aggregation pipelines", databases.CourseEight, []int{3}},
+	{9, "REDIS", "09-redis-database.go", "databases", "Redis, data structures, caching, pub/sub", databases.CourseNine, []int{3}},
+	{10, "TESTING", "10-testing.go", "gotesting", "Unit tests, table-driven tests, benchmarking, mocking", gotesting.CourseTen, []int{3}},
+	{11, "PROJECT STRUCTURE", "11-project-structure.go", "layout", "Directory layout, packages, modules, best practices", layout.CourseEleven, []int{10}},
+	{12, "DESIGN PATTERNS", "12-design-patterns.go", "patterns", "Middleware, DI, repositories, patterns", patterns.CourseTwelve, []int{6, 11}},
+	{13, "ADVANCED TOPICS", "13-advanced-topics.go", "advanced", "Context, profiling, reflection, optimization", advanced.CourseThirteen, []int{4}},
+	{14, "MODULES & VERSIONING", "14-modules-and-versioning.go", "layout", "Publishing a module, semantic versioning, tags", layout.CourseFourteen, []int{11}},
+	{15, "GO WORKSPACES", "15-go-workspaces.go", "layout", "go.work, multi-module repositories", layout.CourseFifteen, []int{14}},
+	{16, "ERROR HANDLING II", "16-errors-advanced.go", "errorhandling", "Wrapping, sentinel errors, errors.Is/As/Join, HTTP mapping", errorhandling.CourseSixteen, []int{2, 6}},
+	{17, "PANICS & STACK TRACES", "17-panics-and-stack-traces.go", "errorhandling", "Defer, recover, debug.Stack, recovery middleware, goroutine panics", errorhandling.CourseSeventeen, []int{6, 16}},
+	{18, "VALIDATION", "18-validation.go", "web", "Struct tags, validation rules, custom validators, field-level errors", web.CourseEighteen, []int{3, 6}},
+	{19, "CONCURRENT STORE", "19-concurrent-store.go", "web", "Data races, -race, RWMutex and sync.Map stores, benchmarks", web.CourseNineteen, []int{4, 10}},
+	{20, "IO STREAMS", "20-io-streams.go", "fileio", "io.Reader/Writer, Tee/Multi/Limit readers, Pipe, custom wrappers", fileio.CourseTwenty, []int{3, 5}},
+	{21, "HTTP/2", "21-http2.go", "web", "TLS with a self-signed certificate, ALPN, multiplexing, server push", web.CourseTwentyOne, []int{6}},
+	{22, "CACHE-ASIDE", "22-cache-aside.go", "databases", "Redis in front of SQL: cache-aside reads, TTLs, invalidation, hit rates", databases.CourseTwentyTwo, []int{7, 9}},
+	{23, "CONNECTION POOLS", "23-connection-pools.go", "databases", "db.Stats() as Prometheus metrics, a load test that exhausts the pool, MaxOpenConns", databases.CourseTwentyThree, []int{4, 7}},
+	{24, "BULK INSERTS", "24-bulk-inserts.go", "databases", "Inserting 10k rows one by one, with multi-row VALUES, in one transaction, and with COPY", databases.CourseTwentyFour, []int{7}},
+	{25, "TRANSACTIONAL OUTBOX", "25-outbox.go", "databases", "A row and its event in one transaction, a relay to the event bus, crashes and duplicates", databases.CourseTwentyFive, []int{7, 12}},
+	{26, "SOFT DELETE AND AUDIT", "26-soft-delete.go", "databases", "created_at, updated_at, deleted_at, reads that skip deleted rows, an audit log written by triggers", databases.CourseTwentySix, []int{7}},
+	{27, "FULL-TEXT SEARCH", "27-full-text-search.go", "databases", "SQLite FTS5 and bleve: stemming, ranking, snippets, safe queries, and /search on an index", databases.CourseTwentySeven, []int{6, 7}},
+	{28, "SAFE FILE WRITES", "28-safe-writes.go", "fileio", "Atomic saves with write-then-rename, fsync trade-offs, and file locks against lost updates", fileio.CourseTwentyEight, []int{4, 5}},
+	{29, "LARGE FILES", "29-large-files.go", "fileio", "Processing files bigger than memory: os.ReadFile vs bufio streaming vs mmap, with memory stats and limits", fileio.CourseTwentyNine, []int{5, 20}},
+	{30, "FAKE FILE SYSTEMS", "30-fake-filesystems.go", "fileio", "io/fs and fstest.MapFS: file helpers on an fs.FS, tested in memory, failures injected, and when a test still needs t.TempDir", fileio.CourseThirty, []int{5, 10}},
+	{31, "DETERMINISTIC TESTS", "31-deterministic-tests.go", "gotesting", "Time-dependent code tested without sleeping: an injected fake clock, testing/synctest bubbles, and exact timeouts in pkg/pool", gotesting.CourseThirtyOne, []int{4, 10}},
+	{32, "MUTATION TESTING", "32-mutation-testing.go", "gotesting", "Why 100% coverage can miss bugs: mutants made with go/ast, run through go test -overlay, killed or survived, and the boundary tests that kill them", gotesting.CourseThirtyTwo, []int{10}},
+}
+
+// runCourses runs the courses named on the command line.
+// Each argument is a course number, or "all" to run every course. Flags
+// may come before or after them.
+func runCourses(args []string) error {
+	flags := flag.NewFlagSet("courses", flag.ContinueOnError)
+	paced := flags.Bool("paced", false, "pause after each section: Enter continues, s skips the course, q quits")
+	fast := flags.Bool("fast", false, "don't wait in demos that sleep: run them on a fake clock")
+	jsonOut := flags.Bool("json", false, "print JSON events, one per line, instead of text")
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file; the time spent goes in time.json next to it")
+	langFlag := flags.String("lang", "", "language of the lesson text, e.g. fr (default: from $LANG, else English)")
+	trackName := flags.String("track", "", "follow a track (see: go run ./cmd/learn track): \"all\" is its courses in its order, no course its next one")
+	workdir := flags.String("workdir", "", "make each course's working folder in this folder (default: the system's temp folder)")
+	timeout := flags.Duration("timeout", courseTimeout, "stop a course that runs this long, as a stuck demo would, and show where it's stuck (0: no limit); -paced prompts don't count")
+	parallel := flags.Bool("parallel", false, "run the courses at the same time, each in a process of its own, and print them in order (time isn't recorded)")
+	resume := flags.Bool("resume", false, "pick up at the section where you last quit with q (same as: go run ./cmd/learn resume)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn [flags] <course number|all>...")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn --track <name> [course number|all]...")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn resume [flags]")
+		flags.PrintDefaults()
+	}
+
+	var names []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		names = append(names, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if *paced && *jsonOut {
+		return errors.New("-paced and -json can't be used together")
+	}
+	if *parallel && (*paced || *resume) {
+		return errors.New("-parallel runs courses straight through: it can't be -paced or resume")
+	}
+	lang, err := lessonLanguage(*langFlag)
+	if err != nil {
+		return err
+	}
+	var selected []course
+	var mark *bookmark
+	if *resume {
+		if len(names) > 0 || *trackName != "" || *jsonOut {
+			return errors.New("resume picks up the courses you quit: it takes no courses, -track or -json")
+		}
+		if selected, mark, err = resumeCourses(*progressPath); err != nil {
+			return err
+		}
+		*paced = true
+	} else if selected, err = selectCourses(names, *trackName, *progressPath); err != nil {
+		if errors.Is(err, errNoCourse) {
+			flags.Usage()
+		}
+		return err
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	// Ctrl+C stops the course at its next section and bookmarks it there.
+	// A second one kills the program as usual, in case a demo is stuck.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	context.AfterFunc(ctx, stop)
+	courseTimeout, workspaceRoot = *timeout, *workdir
+	defer func() { courseTimeout, workspaceRoot = 2*time.Minute, "" }()
+	if *parallel {
+		childArgs := []string{"-lang", lang, "-timeout", timeout.String(), "-workdir", *workdir}
+		if *fast {
+			childArgs = append(childArgs, "-fast")
+		}
+		if *jsonOut {
+			childArgs = append(childArgs, "-json")
+		}
+		procs, err := newCourseProcesses(childArgs)
+		if err != nil {
+			return err
+		}
+		defer procs.Close()
+		return runParallel(ctx, selected, procs.run, os.Stdout)
+	}
+
+	lessonLang = lang
+	defer func() { lessonLang = "en" }()
+	demo.Clock = demo.RealClock{Done: ctx.Done()}
+	if *fast {
+		demo.Clock = demo.NewFakeClock()
+	}
+	defer func() { demo.Clock = demo.RealClock{} }()
+	if *paced {
+		pace = newPacer(os.Stdin)
+		defer func() { pace = nil }()
+	}
+	study = newStudyTimer(*progressPath)
+	defer func() { study = nil }()
+	if *jsonOut {
+		lessonJSON = true
+		defer func() { lessonJSON = false }()
+		if err := runJSON(ctx, selected, os.Stdout); err != nil {
+			return err
+		}
+		if err := study.save(); err != nil {
+			return fmt.Errorf("saving the time log: %w", err)
+		}
+		return nil
+	}
+	if mark != nil {
+		resumeAt = mark.Section
+		defer func() { resumeAt = "" }()
+	}
+	var outcomes []courseOutcome
+	for i, c := range selected {
+		o := runCourse(ctx, c)
+		if err := study.save(); err != nil {
+			return fmt.Errorf("saving the time log: %w", err)
+		}
+		if o.quit {
+			if ctx.Err() != nil {
+				fmt.Println("\nInterrupted.")
+			}
+			return saveBookmark(*progressPath, c, o.next, selected[i+1:])
+		}
+		outcomes = append(outcomes, o)
+	}
+	if mark != nil {
+		if err := saveBookmark(*progressPath, course{}, "", nil); err != nil {
+			return err
+		}
+	}
+	if len(selected) > 1 {
+		printCourseSummary(newTermRenderer(os.Stdout), selected, outcomes)
+	}
+	var failed []string
+	for i, o := range outcomes {
+		if o.err != nil {
+			failed = append(failed, strconv.Itoa(selected[i].number))
+		}
+	}
+	return coursesFailed(failed)
+}
+
+// printCourseSummary prints how each course of a run ended, the way
+// doctor prints its checks.
+func printCourseSummary(out *termRenderer, selected []course, outcomes []courseOutcome) {
+	width := 0
+	for _, c := range selected {
+		width = max(width, len(fmt.Sprintf("%d. %s", c.number, c.name)))
+	}
+	failed := 0
+	out.heading("SUMMARY")
+	for i, o := range outcomes {
+		label, style, detail := "ok", styleOK, ""
+		switch {
+		case o.err != nil:
+			label, style, detail = "FAIL", styleFail, o.err.Error()
+			failed++
+		case o.skipped:
+			label, style = "skip", styleWarn
+		}
+		name := fmt.Sprintf("%d. %s", selected[i].number, selected[i].name)
+		fmt.Fprintf(out.w, "  %s  %-*s  %s\n", out.paint(style, fmt.Sprintf("%-4s", label)), width, name, detail)
+	}
+	fmt.Fprintln(out.w)
+	if failed > 0 {
+		fmt.Fprintf(out.w, "%s of %d failed.\n", plural(failed, "course", "courses"), len(outcomes))
+	} else {
+		fmt.Fprintf(out.w, "All %d courses ran.\n", len(outcomes))
+	}
+}
+
+// coursesFailed is the error for a run in which the courses numbered
+// failed, if any did.
+func coursesFailed(numbers []string) error {
+	if len(numbers) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s failed (%s)", plural(len(numbers), "course", "courses"), andList(numbers))
+}
+
+// runResume implements "go run ./cmd/learn resume [flags]": the courses of the last
+// paced run, from the section where the learner quit.
+func runResume(args []string) error {
+	return runCourses(append([]string{"-resume"}, args...))
+}
+
+// resumeCourses reads the bookmark left by quitting: the course to pick up,
+// and the ones after it.
+func resumeCourses(progressPath string) ([]course, *bookmark, error) {
+	p, err := loadProgress(progressPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading progress: %w", err)
+	}
+	mark := p.Bookmark
+	if mark == nil {
+		return nil, nil, errors.New("nothing to resume: quit a paced run with q, or any run with Ctrl+C, and it picks up there")
+	}
+	var selected []course
+	for _, n := range append([]int{mark.Course}, mark.Then...) {
+		if c, ok := findCourse(n); ok {
+			selected = append(selected, c)
+		}
+	}
+	return selected, mark, nil
+}
+
+// saveBookmark records where the learner quit: before section next of c,
+// with the courses in then still to come. A quit at the end of a course
+// marks the start of the next one, and a zero course clears the bookmark.
+func saveBookmark(progressPath string, c course, next string, then []course) error {
+	p, err := loadProgress(progressPath)
+	if err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	if p.Bookmark == nil && c.number == 0 {
+		return nil
+	}
+	var mark *bookmark
+	if next == "" && c.number != 0 {
+		if len(then) == 0 {
+			c = course{}
+		} else {
+			c, then = then[0], then[1:]
+		}
+	}
+	if c.number != 0 {
+		mark = &bookmark{Course: c.number, Section: next, At: time.Now()}
+		for _, t := range then {
+			mark.Then = append(mark.Then, t.number)
+		}
+	}
+	_, err = updateProgress(progressPath, func(p *progress) error {
+		p.Bookmark = mark
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if mark != nil {
+		fmt.Printf("\nBookmarked course %d. Pick up there with: go run ./cmd/learn resume\n", c.number)
+	}
+	return nil
+}
+
+var errNoCourse = errors.New("no course given")
+
+// selectCourses resolves the courses named on the command line. With a
+// track, they must be in it, "all" is the track's order, and naming none
+// picks the track's next course; following a track is recorded in the
+// progress file.
+func selectCourses(names []string, trackName, progressPath string) ([]course, error) {
+	var selected []course
+	if trackName == "" {
+		for _, name := range names {
+			more, err := courseArg(name)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, more...)
+		}
+		if len(selected) == 0 {
+			return nil, errNoCourse
+		}
+		return selected, nil
+	}
+
+	t, err := trackArg(trackName)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		more, err := t.courseArg(name)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, more...)
+	}
+	if len(selected) == 0 {
+		log, err := loadStudyLog(studyLogPath(progressPath))
+		if err != nil {
+			return nil, fmt.Errorf("reading the time log: %w", err)
+		}
+		c, ok := t.nextCourse(log)
+		if !ok {
+			fmt.Printf("You have read every course of the %s track. Its capstones: go run ./cmd/learn track %s\n", t.title, t.name)
+			return nil, nil
+		}
+		selected = []course{c}
+	}
+
+	_, err = updateProgress(progressPath, func(p *progress) error {
+		p.startTrack(t.name, time.Now())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// courseArg reads a course named on the command line: its number, or
+// "all" for every course.
+func courseArg(arg string) ([]course, error) {
+	if arg == "all" {
+		return courses, nil
+	}
+	number, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid course %q: expected a number or \"all\"", arg)
+	}
+	c, ok := findCourse(number)
+	if !ok {
+		return nil, fmt.Errorf("course %d does not exist (available: 1-%d)", number, len(courses))
+	}
+	return []course{c}, nil
+}
+
+// findCourse looks up a course by its number.
+func findCourse(number int) (course, bool) {
+	for _, c := range courses {
+		if c.number == number {
+			return c, true
+		}
+	}
+	return course{}, false
+}
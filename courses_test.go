@@ -0,0 +1,47 @@
+package learn
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/basics"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+func TestCourseFourFast(t *testing.T) {
+	demo.Clock = demo.NewFakeClock()
+	defer func() { demo.Clock = demo.RealClock{} }()
+
+	var out strings.Builder
+	c, _ := findCourse(4)
+	start := time.Now()
+	if err := runCaptured(context.Background(), c, &out); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("course 4 took %v with the fake clock", elapsed)
+	}
+	for _, want := range []string{"Operation timed out!", "Job 5: Processed", "never more than 2 at once: true", "tools.zip: checksum mismatch"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q", want)
+		}
+	}
+}
+
+func TestCourseWriter(t *testing.T) {
+	demo.Clock = demo.NewFakeClock()
+	defer func() { demo.Clock = demo.RealClock{} }()
+	t.Setenv("NO_COLOR", "1")
+
+	var out strings.Builder
+	if err := basics.CourseTwo(context.Background(), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"FUNCTIONS", "addBasics(5, 3) = 8", "[1] 42 (type: int)", "Recovered from panic: cannot divide by zero!"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("no %q in the output of course 2:\n%s", want, out.String())
+		}
+	}
+}
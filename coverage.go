@@ -0,0 +1,344 @@
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coverBlock is one line of a cover profile: a run of statements in a
+// file, and how often it ran (0 or 1 in "set" mode).
+type coverBlock struct {
+	file  string // the package's import path, then the file name
+	pos   string // startLine.startCol,endLine.endCol
+	stmts int
+	count int
+}
+
+// coverProfile is a parsed cover profile, as go test -coverprofile writes
+// it: a mode line, then one line per block.
+type coverProfile struct {
+	mode   string
+	blocks []coverBlock
+}
+
+// parseCoverProfile reads a profile. Lines look like
+//
+//	github.com/you/mod/pkg/file.go:12.34,15.2 3 1
+func parseCoverProfile(r io.Reader) (coverProfile, error) {
+	var p coverProfile
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+		if mode, ok := strings.CutPrefix(line, "mode: "); ok {
+			if p.mode != "" && p.mode != mode {
+				return coverProfile{}, fmt.Errorf("line %d: mode %s after mode %s", n, mode, p.mode)
+			}
+			p.mode = mode
+			continue
+		}
+		if p.mode == "" {
+			return coverProfile{}, fmt.Errorf("line %d: a profile starts with a mode line", n)
+		}
+		i := strings.LastIndexByte(line, ':')
+		fields := strings.Fields(line[i+1:])
+		if i < 0 || len(fields) != 3 {
+			return coverProfile{}, fmt.Errorf("line %d: %q is not a block", n, line)
+		}
+		stmts, err1 := strconv.Atoi(fields[1])
+		count, err2 := strconv.Atoi(fields[2])
+		if err := errors.Join(err1, err2); err != nil {
+			return coverProfile{}, fmt.Errorf("line %d: %w", n, err)
+		}
+		p.blocks = append(p.blocks, coverBlock{line[:i], fields[0], stmts, count})
+	}
+	return p, sc.Err()
+}
+
+// mergeCoverProfiles makes one profile of several, which must have the
+// same mode. A block in more than one, as when two test binaries cover
+// the same package, is counted once: its counts added, or in "set" mode,
+// covered if it was covered in any.
+func mergeCoverProfiles(profiles ...coverProfile) (coverProfile, error) {
+	var merged coverProfile
+	index := map[[2]string]int{}
+	for _, p := range profiles {
+		if len(p.blocks) == 0 {
+			continue
+		}
+		if merged.mode == "" {
+			merged.mode = p.mode
+		}
+		if p.mode != merged.mode {
+			return coverProfile{}, fmt.Errorf("can't merge a %s profile with a %s one", p.mode, merged.mode)
+		}
+		for _, b := range p.blocks {
+			key := [2]string{b.file, b.pos}
+			i, seen := index[key]
+			if !seen {
+				index[key] = len(merged.blocks)
+				merged.blocks = append(merged.blocks, b)
+				continue
+			}
+			if merged.mode == "set" {
+				merged.blocks[i].count = max(merged.blocks[i].count, b.count)
+			} else {
+				merged.blocks[i].count += b.count
+			}
+		}
+	}
+	return merged, nil
+}
+
+func (p coverProfile) writeTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "mode: %s\n", p.mode)
+	for _, b := range p.blocks {
+		fmt.Fprintf(bw, "%s:%s %d %d\n", b.file, b.pos, b.stmts, b.count)
+	}
+	return bw.Flush()
+}
+
+// packageCover is the statements of one package, and how many of them
+// the tests ran.
+type packageCover struct {
+	pkg            string
+	stmts, covered int
+}
+
+func (c packageCover) percent() float64 {
+	if c.stmts == 0 {
+		return 100
+	}
+	return 100 * float64(c.covered) / float64(c.stmts)
+}
+
+// byPackage adds the profile's blocks up per package, sorted by import
+// path, and over all of them.
+func (p coverProfile) byPackage() (pkgs []packageCover, total packageCover) {
+	sums := map[string]*packageCover{}
+	for _, b := range p.blocks {
+		pkg := path.Dir(b.file)
+		c := sums[pkg]
+		if c == nil {
+			c = &packageCover{pkg: pkg}
+			sums[pkg] = c
+		}
+		c.stmts += b.stmts
+		total.stmts += b.stmts
+		if b.count > 0 {
+			c.covered += b.stmts
+			total.covered += b.stmts
+		}
+	}
+	for _, c := range sums {
+		pkgs = append(pkgs, *c)
+	}
+	slices.SortFunc(pkgs, func(a, b packageCover) int { return strings.Compare(a.pkg, b.pkg) })
+	return pkgs, total
+}
+
+// printCoverage prints the coverage of each package, with the module path
+// trimmed off, marking those below minimum, then the total. It reports
+// whether the total reaches minimum.
+func printCoverage(w io.Writer, pkgs []packageCover, total packageCover, module string, minimum float64) bool {
+	fmt.Fprintln(w, "COVERAGE BY PACKAGE")
+	for _, c := range pkgs {
+		name := strings.TrimPrefix(strings.TrimPrefix(c.pkg, module), "/")
+		if name == "" {
+			name = "."
+		}
+		switch {
+		case c.stmts == 0:
+			fmt.Fprintf(w, "      -   %s  [no statements]\n", name)
+		case c.percent() < minimum:
+			fmt.Fprintf(w, "  %5.1f%%  %s  (below %.0f%%)\n", c.percent(), name, minimum)
+		default:
+			fmt.Fprintf(w, "  %5.1f%%  %s\n", c.percent(), name)
+		}
+	}
+	fmt.Fprintf(w, "TOTAL %.1f%% of %d statements", total.percent(), total.stmts)
+	ok := total.percent() >= minimum
+	switch {
+	case minimum == 0:
+		fmt.Fprintln(w)
+	case ok:
+		fmt.Fprintf(w, ", at least -min %.0f%%\n", minimum)
+	default:
+		fmt.Fprintf(w, ", below -min %.0f%%\n", minimum)
+	}
+	return ok
+}
+
+// goModule is a module of the workspace, as go list -m reports it.
+type goModule struct {
+	path, dir string
+}
+
+// workspaceModules lists the modules go.work uses, the main module first;
+// outside a workspace, just the main module.
+func workspaceModules(ctx context.Context) ([]goModule, error) {
+	out, err := exec.CommandContext(ctx, "go", "list", "-m", "-f", "{{.Path}} {{.Dir}}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list -m: %w", err)
+	}
+	var mods []goModule
+	for line := range strings.Lines(string(out)) {
+		p, dir, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok {
+			mods = append(mods, goModule{p, dir})
+		}
+	}
+	return mods, nil
+}
+
+// testModuleCover runs go test -coverprofile on every package of a module,
+// and returns its profile, and go test's output if a test failed.
+func testModuleCover(ctx context.Context, dir, profile string, short bool) (coverProfile, string, error) {
+	args := []string{"test", "-coverprofile=" + profile}
+	if short {
+		args = append(args, "-short")
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, "./...")...)
+	cmd.Dir = dir
+	out, testErr := cmd.CombinedOutput()
+
+	// A failing test still leaves the profile of what ran
+	f, err := os.Open(profile)
+	if err != nil {
+		if testErr != nil {
+			return coverProfile{}, string(out), nil
+		}
+		return coverProfile{}, "", err
+	}
+	defer f.Close()
+	p, err := parseCoverProfile(f)
+	if err != nil {
+		return coverProfile{}, "", fmt.Errorf("%s: %w", profile, err)
+	}
+	if testErr != nil {
+		return p, string(out), nil
+	}
+	return p, "", nil
+}
+
+// runCoverage implements "go run ./cmd/learn coverage [flags] [module...]".
+func runCoverage(args []string) error {
+	flags := flag.NewFlagSet("coverage", flag.ContinueOnError)
+	minimum := flags.Float64("min", 0, "fail if the total coverage is below this percentage")
+	out := flags.String("out", "coverage.out", "where to write the merged profile")
+	html := flags.String("html", "coverage.html", `where to write the HTML report ("" for none)`)
+	short := flags.Bool("short", false, "run go test -short, skipping the slow tests")
+	timeout := flags.Duration("timeout", 15*time.Minute, "how long all the tests may take")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn coverage [flags] [module folder...]")
+		fmt.Fprintln(flags.Output(), "Runs go test -coverprofile in every module of go.work (or those named),")
+		fmt.Fprintln(flags.Output(), "merges the profiles, prints the coverage of each package and writes the")
+		fmt.Fprintln(flags.Output(), "HTML report of go tool cover.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	mods, err := workspaceModules(ctx)
+	if err != nil {
+		return err
+	}
+	if len(mods) == 0 {
+		return errors.New("no Go module here: run coverage from the course folder")
+	}
+	// Package paths are shown relative to the main module's
+	root := mods[0].path
+	if flags.NArg() > 0 {
+		var named []goModule
+		for _, arg := range flags.Args() {
+			dir, err := filepath.Abs(arg)
+			if err != nil {
+				return err
+			}
+			i := slices.IndexFunc(mods, func(m goModule) bool { return m.dir == dir })
+			if i < 0 {
+				return fmt.Errorf("%s is not a module of the workspace", arg)
+			}
+			named = append(named, mods[i])
+		}
+		mods = named
+	}
+
+	tmp, err := os.MkdirTemp("", "learn-coverage-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	var profiles []coverProfile
+	var failed []string
+	for i, m := range mods {
+		start := time.Now()
+		p, testOutput, err := testModuleCover(ctx, m.dir, filepath.Join(tmp, fmt.Sprintf("%d.out", i)), *short)
+		if err != nil {
+			return fmt.Errorf("%s: %w", m.path, err)
+		}
+		status := "ok  "
+		if testOutput != "" {
+			status = "FAIL"
+			failed = append(failed, m.path)
+			fmt.Print(testOutput)
+		}
+		fmt.Printf("%s  %-60s %.1fs\n", status, m.path, time.Since(start).Seconds())
+		profiles = append(profiles, p)
+	}
+
+	merged, err := mergeCoverProfiles(profiles...)
+	if err != nil {
+		return err
+	}
+	if len(merged.blocks) == 0 {
+		return errors.New("the tests covered no statements")
+	}
+	var buf bytes.Buffer
+	if err := merged.writeTo(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	pkgs, total := merged.byPackage()
+	ok := printCoverage(os.Stdout, pkgs, total, root, *minimum)
+	fmt.Println("\nMerged profile:", *out)
+	if *html != "" {
+		// From here, so go.work resolves the import paths to files
+		cmd := exec.CommandContext(ctx, "go", "tool", "cover", "-html="+*out, "-o", *html)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("go tool cover: %v\n%s", err, output)
+		}
+		fmt.Println("HTML report:   ", *html)
+	}
+
+	switch {
+	case len(failed) > 0:
+		return fmt.Errorf("tests failed in %s", strings.Join(failed, ", "))
+	case !ok:
+		return fmt.Errorf("coverage %.1f%% is below -min %.0f%%", total.percent(), *minimum)
+	}
+	return nil
+}
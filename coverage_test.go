@@ -0,0 +1,152 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Run with: go test -run Cover
+
+const (
+	profileA = `mode: set
+example.com/m/a/a.go:3.20,5.2 2 1
+example.com/m/a/a.go:7.20,9.2 2 0
+example.com/m/b/b.go:3.20,4.2 1 0
+`
+	// Another test binary that also covers package a, as -coverpkg does
+	profileB = `mode: set
+example.com/m/a/a.go:3.20,5.2 2 0
+example.com/m/a/a.go:7.20,9.2 2 1
+example.com/m/c/c.go:3.20,8.2 5 1
+`
+)
+
+func parseProfile(t *testing.T, s string) coverProfile {
+	t.Helper()
+	p, err := parseCoverProfile(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestMergeCoverProfiles(t *testing.T) {
+	merged, err := mergeCoverProfiles(parseProfile(t, profileA), coverProfile{}, parseProfile(t, profileB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := merged.writeTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `mode: set
+example.com/m/a/a.go:3.20,5.2 2 1
+example.com/m/a/a.go:7.20,9.2 2 1
+example.com/m/b/b.go:3.20,4.2 1 0
+example.com/m/c/c.go:3.20,8.2 5 1
+`
+	if buf.String() != want {
+		t.Errorf("merged profile:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// Counts add up in count mode
+	count := func(s string) string { return strings.Replace(s, "mode: set", "mode: count", 1) }
+	merged, _ = mergeCoverProfiles(parseProfile(t, count(profileA)), parseProfile(t, count(profileB)))
+	if got := merged.blocks[0].count; got != 1 {
+		t.Errorf("count mode: first block ran %d times, want 1", got)
+	}
+	if got := merged.blocks[1].count; got != 1 {
+		t.Errorf("count mode: second block ran %d times, want 1", got)
+	}
+
+	if _, err := mergeCoverProfiles(parseProfile(t, profileA), parseProfile(t, count(profileB))); err == nil {
+		t.Error("merged a set profile with a count one")
+	}
+}
+
+func TestParseCoverProfileErrors(t *testing.T) {
+	for _, bad := range []string{
+		"example.com/m/a/a.go:3.20,5.2 2 1\n",              // no mode line
+		"mode: set\nexample.com/m/a/a.go 2 1\n",            // no position
+		"mode: set\nexample.com/m/a/a.go:3.20,5.2 two 1\n", // not a number
+		"mode: set\nmode: count\n",
+	} {
+		if _, err := parseCoverProfile(strings.NewReader(bad)); err == nil {
+			t.Errorf("parsed %q", bad)
+		}
+	}
+}
+
+func TestPrintCoverage(t *testing.T) {
+	merged, _ := mergeCoverProfiles(parseProfile(t, profileA), parseProfile(t, profileB))
+	merged.blocks = append(merged.blocks, coverBlock{"example.com/m/types.go", "1.1,2.2", 0, 0})
+	pkgs, total := merged.byPackage()
+	if total.stmts != 10 || total.covered != 9 {
+		t.Fatalf("total = %+v, want 9 of 10 statements", total)
+	}
+
+	var buf bytes.Buffer
+	if printCoverage(&buf, pkgs, total, "example.com/m", 95) {
+		t.Error("90% passed a -min of 95%")
+	}
+	want := `COVERAGE BY PACKAGE
+      -   .  [no statements]
+  100.0%  a
+    0.0%  b  (below 95%)
+  100.0%  c
+TOTAL 90.0% of 10 statements, below -min 95%
+`
+	if buf.String() != want {
+		t.Errorf("printCoverage:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if !printCoverage(&bytes.Buffer{}, pkgs, total, "example.com/m", 90) {
+		t.Error("90% failed a -min of 90%")
+	}
+}
+
+func TestTestModuleCover(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not on PATH")
+	}
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"go.mod":      "module example.com/m\n\ngo 1.25\n",
+		"a/a.go":      "package a\n\nfunc Abs(n int) int {\n\tif n < 0 {\n\t\treturn -n\n\t}\n\treturn n\n}\n",
+		"a/a_test.go": "package a\n\nimport \"testing\"\n\nfunc TestAbs(t *testing.T) {\n\tif Abs(2) != 2 {\n\t\tt.Fail()\n\t}\n}\n",
+		"b/b.go":      "package b\n\nfunc Two() int { return 2 }\n",
+		"c/c.go":      "package c\n\nfunc Three() int { return 3 }\n",
+		"c/c_test.go": "package c\n\nimport \"testing\"\n\nfunc TestThree(t *testing.T) {\n\tThree()\n\tt.Fatal(\"broken\")\n}\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, failures, err := testModuleCover(context.Background(), dir, filepath.Join(t.TempDir(), "m.out"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(failures, "broken") {
+		t.Errorf("the failing test's output is missing:\n%s", failures)
+	}
+	pkgs, _ := p.byPackage()
+	var got []string
+	for _, c := range pkgs {
+		got = append(got, fmt.Sprintf("%s %d/%d", strings.TrimPrefix(c.pkg, "example.com/m/"), c.covered, c.stmts))
+	}
+	// b has no tests; c's test failed, after running Three
+	want := []string{"a 2/3", "b 0/1", "c 1/1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("covered statements per package = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,223 @@
+package learn
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The reference solutions are behind "//go:build solutions", so they are
+// never compiled into the course or picked up by go build ./... Embedding
+// ignores build tags, so diff can still show them.
+//
+//go:embed solutions/*/*.go
+var referenceSolutions embed.FS
+
+// runDiff implements "go run ./cmd/learn diff [flags] exercise...".
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	dir := flags.String("dir", "exercises", "folder holding your solutions")
+	color := flags.String("color", "auto", "highlight changes: auto, always or never")
+	ignoreSpace := flags.Bool("w", false, "ignore differences in spaces and tabs")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn diff [flags] exercise...")
+		fmt.Fprintln(flags.Output(), "Shows how your solution differs from the reference. Spoilers!")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("name at least one exercise")
+	}
+
+	var useColor bool
+	switch *color {
+	case "always":
+		useColor = true
+	case "never":
+	case "auto":
+		useColor = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+	default:
+		return fmt.Errorf("-color must be auto, always or never, not %q", *color)
+	}
+	eq := func(a, b string) bool { return a == b }
+	if *ignoreSpace {
+		eq = func(a, b string) bool {
+			return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+		}
+	}
+
+	for _, name := range flags.Args() {
+		if _, ok := findExercise(name); !ok {
+			return fmt.Errorf("no exercise named %q", name)
+		}
+		if err := diffExercise(os.Stdout, *dir, name, eq, useColor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffExercise compares each file of the reference solution with the
+// learner's file of the same name.
+func diffExercise(w io.Writer, dir, name string, eq func(a, b string) bool, useColor bool) error {
+	refDir := path.Join("solutions", name)
+	entries, err := referenceSolutions.ReadDir(refDir)
+	if err != nil {
+		return fmt.Errorf("no reference solution for %s: %w", name, err)
+	}
+	for _, e := range entries {
+		ref, err := referenceSolutions.ReadFile(path.Join(refDir, e.Name()))
+		if err != nil {
+			return err
+		}
+		minePath := filepath.Join(dir, name, e.Name())
+		mine, err := os.ReadFile(minePath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		// A missing file diffs as empty: the whole reference is "added"
+
+		ops := diffLines(splitLines(string(mine)), splitLines(stripBuildTag(string(ref))), eq)
+		if !hasChanges(ops) {
+			fmt.Fprintf(w, "%s matches the reference solution\n", minePath)
+			continue
+		}
+		fmt.Fprintf(w, "--- %s (yours)\n+++ %s (reference)\n", minePath, path.Join(refDir, e.Name()))
+		printHunks(w, ops, 3, useColor)
+	}
+	return nil
+}
+
+// stripBuildTag removes the "//go:build solutions" line and the blank line
+// after it, which the learner's copy doesn't have.
+func stripBuildTag(src string) string {
+	if rest, ok := strings.CutPrefix(src, "//go:build solutions\n"); ok {
+		return strings.TrimPrefix(rest, "\n")
+	}
+	return src
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffOp is one line of a diff: kept (' '), only in a ('-') or only in b ('+').
+type diffOp struct {
+	kind byte
+	text string
+	a, b int // 1-based line numbers in a and b before this op
+}
+
+// diffLines finds a shortest edit from a to b through their longest common
+// subsequence of lines. It is O(len(a) * len(b)), which is fine for files
+// the size of an exercise.
+func diffLines(a, b []string, eq func(x, y string) bool) []diffOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if eq(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && eq(a[i], b[j]):
+			ops = append(ops, diffOp{' ', a[i], i + 1, j + 1})
+			i, j = i+1, j+1
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i], i + 1, j + 1})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i + 1, j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+func hasChanges(ops []diffOp) bool {
+	for _, op := range ops {
+		if op.kind != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// printHunks prints the changes in unified diff format, with context
+// unchanged lines around each change.
+func printHunks(w io.Writer, ops []diffOp, context int, useColor bool) {
+	paint := func(code, s string) string {
+		if !useColor {
+			return s
+		}
+		return "\033[" + code + "m" + s + "\033[0m"
+	}
+
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+		// Grow the hunk while the next change is within 2*context lines
+		lo, hi := max(start-context, 0), start
+		for k := start; k < len(ops) && k <= hi+2*context; k++ {
+			if ops[k].kind != ' ' {
+				hi = k
+			}
+		}
+		hi = min(hi+context, len(ops)-1)
+
+		var aLen, bLen int
+		for _, op := range ops[lo : hi+1] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintln(w, paint("36", fmt.Sprintf("@@ -%d,%d +%d,%d @@", ops[lo].a, aLen, ops[lo].b, bLen)))
+		for _, op := range ops[lo : hi+1] {
+			line := string(op.kind) + op.text
+			switch op.kind {
+			case '-':
+				line = paint("31", line)
+			case '+':
+				line = paint("32", line)
+			}
+			fmt.Fprintln(w, line)
+		}
+		start = hi + 1
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a
+// pipe or file, where color codes would be noise.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,110 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func exact(a, b string) bool { return a == b }
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "c", "x", "d"}
+	var got strings.Builder
+	for _, op := range diffLines(a, b, exact) {
+		got.WriteString(string(op.kind) + op.text + " ")
+	}
+	if want := " a -b  c +x  d "; got.String() != want {
+		t.Errorf("diff = %q, want %q", got.String(), want)
+	}
+	if hasChanges(diffLines(a, a, exact)) {
+		t.Error("identical input reported changes")
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	var a []string
+	for i := 1; i <= 20; i++ {
+		a = append(a, strings.Repeat("x", i))
+	}
+	b := append([]string{}, a...)
+	b[1] = "changed"
+	b[17] = "also changed"
+
+	var buf bytes.Buffer
+	printHunks(&buf, diffLines(a, b, exact), 2, false)
+	out := buf.String()
+	// Two changes 16 lines apart: two hunks, with unchanged lines between elided
+	if n := strings.Count(out, "@@ -"); n != 2 {
+		t.Errorf("got %d hunks, want 2:\n%s", n, out)
+	}
+	for _, want := range []string{"@@ -1,4 +1,4 @@\n", "-xx\n+changed\n", "@@ -16,5 +16,5 @@\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, " "+strings.Repeat("x", 10)+"\n") {
+		t.Errorf("line 10 is far from any change and should not be shown:\n%s", out)
+	}
+}
+
+func TestDiffIgnoreSpace(t *testing.T) {
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	ref, _ := referenceSolutions.ReadFile("solutions/reverse/reverse.go")
+	// Same code, re-indented with spaces
+	mine := strings.ReplaceAll(stripBuildTag(string(ref)), "\t", "    ")
+	writeSolution(t, dir, "reverse", mine)
+
+	eq := func(a, b string) bool {
+		return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+	}
+	if err := diffExercise(&buf, dir, "reverse", eq, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "matches the reference") {
+		t.Errorf("with -w, re-indented code should match:\n%s", buf.String())
+	}
+}
+
+// TestSolutionsPassHiddenTests grades every reference solution, so a
+// solution and its hidden tests can't drift apart.
+func TestSolutionsPassHiddenTests(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not on PATH")
+	}
+	dir := t.TempDir()
+	for _, ex := range exerciseList {
+		entries, err := referenceSolutions.ReadDir(path.Join("solutions", ex.name))
+		if err != nil {
+			t.Fatalf("%s has no reference solution: %v", ex.name, err)
+		}
+		os.MkdirAll(filepath.Join(dir, ex.name), 0o755)
+		for _, e := range entries {
+			src, _ := referenceSolutions.ReadFile(path.Join("solutions", ex.name, e.Name()))
+			// Without the tag line, go build compiles it like a learner's file
+			os.WriteFile(filepath.Join(dir, ex.name, e.Name()), []byte(stripBuildTag(string(src))), 0o644)
+
+			if _, err := os.Stat(filepath.Join("exercises", ex.name, e.Name())); err != nil {
+				t.Errorf("solution file %s has no starter file to diff against", e.Name())
+			}
+		}
+	}
+
+	for _, ex := range exerciseList {
+		t.Run(ex.name, func(t *testing.T) {
+			t.Parallel()
+			r := gradeExercise(context.Background(), dir, ex, time.Minute)
+			if r.percent() != 100 {
+				t.Errorf("reference solution scored %d%%: failed %v %s %s", r.percent(), r.failed, r.problem, r.detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package learn
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/version"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// requiredGoVersion is the go line of go.mod: older toolchains can't build
+// the course.
+const requiredGoVersion = "go1.25.1"
+
+// checkStatus is how one of the doctor's checks came out.
+type checkStatus int
+
+const (
+	checkOK   checkStatus = iota
+	checkWarn             // something a later course needs
+	checkFail             // the course won't build or run
+)
+
+// checkResult is one line of the doctor's report, with what to do about
+// anything short of ok.
+type checkResult struct {
+	name   string
+	status checkStatus
+	detail string
+	fix    string
+}
+
+// doctorEnv is what the checks look at: the machine, or a fake of it in
+// tests.
+type doctorEnv struct {
+	goEnv    func(ctx context.Context) (map[string]string, error)
+	lookPath func(file string) (string, error)
+	run      func(ctx context.Context, dir, name string, args ...string) error
+	getenv   func(key string) string
+	dial     func(addr string) error // connects and hangs up
+	listen   func(addr string) error // binds and lets go
+}
+
+func realDoctorEnv() doctorEnv {
+	return doctorEnv{
+		goEnv: func(ctx context.Context) (map[string]string, error) {
+			out, err := goCommand(ctx, ".", "env", "-json", "GOVERSION", "GOOS", "GOARCH", "GOPATH", "GOBIN", "CGO_ENABLED", "CC")
+			if err != nil {
+				return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+			}
+			env := map[string]string{}
+			return env, json.Unmarshal(out, &env)
+		},
+		lookPath: exec.LookPath,
+		run: func(ctx context.Context, dir, name string, args ...string) error {
+			cmd := exec.CommandContext(ctx, name, args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+			out, err := cmd.CombinedOutput()
+			if err != nil && len(out) > 0 {
+				return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+			}
+			return err
+		},
+		getenv: os.Getenv,
+		dial: func(addr string) error {
+			conn, err := net.DialTimeout("tcp", addr, 500*time.Millisecond)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+		listen: func(addr string) error {
+			l, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			return l.Close()
+		},
+	}
+}
+
+// coursePort is a port the courses use.
+type coursePort struct {
+	port    int
+	service string
+	course  string
+	start   string // how to get it running; empty for a port that must be free
+}
+
+// coursePorts are 8080, which the courses listen on, and the ports of the
+// databases of courses 7-9.
+func coursePorts() []coursePort {
+	ports := []coursePort{{8080, "the demo backend", "course 6", ""}}
+	for _, db := range courseDatabases {
+		ports = append(ports, coursePort{db.port, db.title, fmt.Sprintf("course %d", db.course), "go run ./cmd/learn env up " + db.name})
+	}
+	return ports
+}
+
+// raceTargets are the platforms with a race detector (go help build).
+var raceTargets = []string{
+	"linux/amd64", "linux/arm64", "linux/loong64", "linux/ppc64le", "linux/s390x",
+	"darwin/amd64", "darwin/arm64", "freebsd/amd64", "netbsd/amd64", "windows/amd64",
+}
+
+// runDoctor implements "go run ./cmd/learn doctor [flags]".
+func runDoctor(args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	timeout := flags.Duration("timeout", 2*time.Minute, "time limit for the checks that run commands")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn doctor [flags]")
+		fmt.Fprintln(flags.Output(), "Checks that this machine can build and run every course, and says how to fix what it can't.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return fmt.Errorf("doctor takes no arguments, got %q", flags.Args())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	results := realDoctorEnv().check(ctx)
+	if failed := printDoctor(newTermRenderer(os.Stdout), results); failed > 0 {
+		return fmt.Errorf("doctor: %s to fix before the courses can run", plural(failed, "problem", "problems"))
+	}
+	return nil
+}
+
+// check runs every check, in the order a learner meets what they check.
+func (e doctorEnv) check(ctx context.Context) []checkResult {
+	env, err := e.goEnv(ctx)
+	if err != nil {
+		r := checkResult{name: "Go", status: checkFail, detail: err.Error(), fix: "install Go from https://go.dev/dl/ and make sure go is on your PATH"}
+		if _, lookErr := e.lookPath("go"); lookErr != nil {
+			r.detail = "the go command isn't on your PATH"
+		}
+		return append([]checkResult{r}, e.checkServices(ctx)...)
+	}
+	results := []checkResult{checkGoVersion(env), e.checkGoBin(env)}
+	results = append(results, e.checkRace(ctx, env))
+	return append(results, e.checkServices(ctx)...)
+}
+
+func checkGoVersion(env map[string]string) checkResult {
+	r := checkResult{name: "Go", detail: fmt.Sprintf("%s (%s/%s)", env["GOVERSION"], env["GOOS"], env["GOARCH"])}
+	if !version.IsValid(env["GOVERSION"]) {
+		// A development build: assume it is new enough
+		r.status, r.detail = checkWarn, env["GOVERSION"]+": can't tell the version of a development build"
+		r.fix = "if the courses don't build, install a release from https://go.dev/dl/"
+		return r
+	}
+	if version.Compare(env["GOVERSION"], requiredGoVersion) < 0 {
+		r.status = checkFail
+		r.detail = fmt.Sprintf("%s is older than the %s go.mod asks for", env["GOVERSION"], requiredGoVersion)
+		r.fix = "install Go " + strings.TrimPrefix(requiredGoVersion, "go") + " or newer from https://go.dev/dl/ (or let go fetch it: GOTOOLCHAIN=auto)"
+	}
+	return r
+}
+
+// checkGoBin checks that programs installed with go install can be run.
+func (e doctorEnv) checkGoBin(env map[string]string) checkResult {
+	bin := env["GOBIN"]
+	name := "GOBIN"
+	if bin == "" {
+		if env["GOPATH"] == "" {
+			return checkResult{name: "GOPATH", status: checkWarn, detail: "GOPATH isn't set and has no default",
+				fix: "set one with: go env -w GOPATH=$HOME/go"}
+		}
+		name = "GOPATH"
+		bin = filepath.Join(filepath.SplitList(env["GOPATH"])[0], "bin")
+	}
+	if slices.Contains(filepath.SplitList(e.getenv("PATH")), bin) {
+		return checkResult{name: name, detail: bin + " is on your PATH"}
+	}
+	return checkResult{name: name, status: checkWarn,
+		detail: bin + " isn't on your PATH, so tools you go install (course 14) won't run by name",
+		fix:    fmt.Sprintf("add it to your shell profile: export PATH=\"$PATH:%s\"", bin)}
+}
+
+// checkRace checks that go test -race works: it needs cgo and a C
+// compiler, on a platform with a race detector. Course 19 uses it.
+func (e doctorEnv) checkRace(ctx context.Context, env map[string]string) checkResult {
+	r := checkResult{name: "Race detector", status: checkWarn}
+	target := env["GOOS"] + "/" + env["GOARCH"]
+	cc := env["CC"]
+	if cc == "" {
+		cc = "gcc"
+	}
+	switch {
+	case !slices.Contains(raceTargets, target):
+		r.detail, r.fix = "no race detector on "+target, "run course 19 on a 64-bit Linux, macOS or Windows machine"
+		return r
+	case env["CGO_ENABLED"] != "1":
+		r.detail, r.fix = "cgo is off, and -race needs it", "go env -w CGO_ENABLED=1 (with a C compiler installed)"
+		return r
+	}
+	if _, err := e.lookPath(cc); err != nil {
+		r.detail = "no C compiler (" + cc + "), and -race needs one"
+		r.fix = "install gcc: apt install gcc, or xcode-select --install on macOS"
+		return r
+	}
+
+	dir, err := os.MkdirTemp("", "learning-golang-doctor-")
+	if err != nil {
+		r.detail = err.Error()
+		return r
+	}
+	defer os.RemoveAll(dir)
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module doctor\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644)
+	if err := e.run(ctx, dir, "go", "build", "-race", "-o", os.DevNull, "."); err != nil {
+		r.status, r.detail = checkFail, "go build -race failed: "+err.Error()
+		r.fix = "check that " + cc + " works: " + cc + " --version"
+		return r
+	}
+	return checkResult{name: "Race detector", detail: "go test -race works (" + cc + ")"}
+}
+
+// checkServices checks Docker and the ports the courses use.
+func (e doctorEnv) checkServices(ctx context.Context) []checkResult {
+	var results []checkResult
+	docker := checkResult{name: "Docker", detail: "running"}
+	if _, err := e.lookPath("docker"); err != nil {
+		docker.status, docker.detail = checkWarn, "not installed; courses 7-9 run their databases in it"
+		docker.fix = "install Docker Desktop, or Docker Engine on Linux: https://docs.docker.com/get-docker/"
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := e.run(ctx, ".", "docker", "info", "--format", "{{.ServerVersion}}")
+		cancel()
+		if err != nil {
+			docker.status, docker.detail = checkWarn, "installed, but the daemon isn't running (or you can't reach it)"
+			docker.fix = "start Docker Desktop, or: sudo systemctl start docker (and add yourself to the docker group)"
+		}
+	}
+	results = append(results, docker)
+
+	for _, p := range coursePorts() {
+		results = append(results, e.checkPort(p))
+	}
+	return results
+}
+
+func (e doctorEnv) checkPort(p coursePort) checkResult {
+	r := checkResult{name: fmt.Sprintf("Port %d", p.port)}
+	addr := fmt.Sprintf("localhost:%d", p.port)
+	inUse := e.dial(addr) == nil
+	if p.start == "" {
+		if inUse {
+			r.status, r.detail = checkWarn, "in use; "+p.service+" and "+p.course+" listen on it"
+			r.fix = fmt.Sprintf("stop what is using it (lsof -i :%d), or pick another port: PORT=8081 go run ./cmd/learn", p.port)
+			return r
+		}
+		if err := e.listen(fmt.Sprintf(":%d", p.port)); err != nil {
+			r.status, r.detail = checkFail, "can't listen on it: "+err.Error()
+			r.fix = "allow Go programs to listen on local ports in your firewall"
+			return r
+		}
+		r.detail = "free for " + p.service + " and " + p.course
+		return r
+	}
+	if inUse {
+		r.detail = p.service + " is up for " + p.course
+		return r
+	}
+	r.status = checkWarn
+	r.detail = "nothing listening; " + p.course + " expects " + p.service + " here"
+	r.fix = "start it with: " + p.start
+	if err := e.listen(fmt.Sprintf(":%d", p.port)); err != nil {
+		r.status, r.detail = checkFail, "can't connect to it or listen on it: "+err.Error()
+		r.fix = "allow local connections on port " + fmt.Sprint(p.port) + " in your firewall"
+	}
+	return r
+}
+
+// printDoctor prints the report and returns how many checks failed.
+func printDoctor(out *termRenderer, results []checkResult) int {
+	labels := map[checkStatus]string{checkOK: "ok", checkWarn: "warn", checkFail: "FAIL"}
+	styles := map[checkStatus]string{checkOK: styleOK, checkWarn: styleWarn, checkFail: styleFail}
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.name))
+	}
+	var counts [3]int
+	out.heading("LEARNING-GOLANG DOCTOR")
+	for _, r := range results {
+		counts[r.status]++
+		label := fmt.Sprintf("%-4s", labels[r.status])
+		fmt.Fprintf(out.w, "  %s  %-*s  %s\n", out.paint(styles[r.status], label), width, r.name, r.detail)
+		if r.fix != "" {
+			fmt.Fprintf(out.w, "        %-*s  %s\n", width, "", out.paint(styleComment, "fix: "+r.fix))
+		}
+	}
+	fmt.Fprintln(out.w)
+	switch {
+	case counts[checkFail] > 0:
+		fmt.Fprintf(out.w, "%s, %s.\n", plural(counts[checkFail], "problem", "problems"), plural(counts[checkWarn], "warning", "warnings"))
+	case counts[checkWarn] > 0:
+		fmt.Fprintf(out.w, "No problems; %s for later courses.\n", plural(counts[checkWarn], "warning", "warnings"))
+	default:
+		fmt.Fprintln(out.w, "All good: every course can build and run here.")
+	}
+	return counts[checkFail]
+}
@@ -0,0 +1,90 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakeMachine is a doctorEnv for a machine with an old Go, no Docker and
+// only PostgreSQL running, with 8080 taken.
+func fakeMachine(ran *[]string) doctorEnv {
+	listening := map[string]bool{"localhost:5432": true, "localhost:8080": true}
+	return doctorEnv{
+		goEnv: func(context.Context) (map[string]string, error) {
+			return map[string]string{"GOVERSION": "go1.21.0", "GOOS": "linux", "GOARCH": "amd64",
+				"GOPATH": "/home/ada/go", "CGO_ENABLED": "1", "CC": "gcc"}, nil
+		},
+		lookPath: func(file string) (string, error) {
+			if file == "gcc" {
+				return "/usr/bin/gcc", nil
+			}
+			return "", errors.New("not found")
+		},
+		run: func(ctx context.Context, dir, name string, args ...string) error {
+			*ran = append(*ran, name+" "+strings.Join(args, " "))
+			return nil
+		},
+		getenv: func(string) string { return "/usr/bin:/bin" },
+		dial: func(addr string) error {
+			if listening[addr] {
+				return nil
+			}
+			return errors.New("connection refused")
+		},
+		listen: func(string) error { return nil },
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	var ran []string
+	results := fakeMachine(&ran).check(context.Background())
+	want := map[string]checkStatus{
+		"Go": checkFail, "GOPATH": checkWarn, "Race detector": checkOK, "Docker": checkWarn,
+		"Port 8080": checkWarn, "Port 5432": checkOK, "Port 3306": checkWarn, "Port 6379": checkWarn, "Port 27017": checkWarn,
+	}
+	if len(results) != len(want) {
+		t.Errorf("%d checks, want %d: %+v", len(results), len(want), results)
+	}
+	for _, r := range results {
+		if status, ok := want[r.name]; !ok || r.status != status {
+			t.Errorf("%s: status %d (%s), want %d", r.name, r.status, r.detail, status)
+		}
+		if r.status != checkOK && r.fix == "" {
+			t.Errorf("%s: %s, and no fix given", r.name, r.detail)
+		}
+	}
+	if len(ran) != 1 || !strings.HasPrefix(ran[0], "go build -race") {
+		t.Errorf("ran %q, want only the race build (Docker isn't installed)", ran)
+	}
+
+	var buf bytes.Buffer
+	if failed := printDoctor(&termRenderer{w: &buf, width: 80}, results); failed != 1 {
+		t.Errorf("printDoctor counted %d failures, want 1", failed)
+	}
+	for _, want := range []string{
+		"  FAIL  Go             go1.21.0 is older than the " + requiredGoVersion + " go.mod asks for\n",
+		"  ok    Port 5432      PostgreSQL is up for course 7\n",
+		"fix: add it to your shell profile: export PATH=\"$PATH:/home/ada/go/bin\"",
+		"fix: start it with: go run ./cmd/learn env up redis\n",
+		"1 problem, 6 warnings.",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+// TestRequiredGoVersion keeps the doctor in step with go.mod.
+func TestRequiredGoVersion(t *testing.T) {
+	mod, err := os.ReadFile("go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line := "\ngo " + strings.TrimPrefix(requiredGoVersion, "go") + "\n"; !strings.Contains(string(mod), line) {
+		t.Errorf("go.mod has no %q line: update requiredGoVersion", strings.TrimSpace(line))
+	}
+}
@@ -0,0 +1,117 @@
+package learn
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+func exportEPUB(args []string) error {
+	flags := flag.NewFlagSet("export epub", flag.ContinueOnError)
+	out := flags.String("out", "learning-golang.epub", "file to write the book to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn export epub [flags]")
+		fmt.Fprintln(flags.Output(), "Writes every course, its key takeaways and the exercises as an e-book.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	book, err := loadBook(".xhtml")
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := writeEPUB(f, book, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d courses to %s\n", len(book.Courses), *out)
+	return nil
+}
+
+// writeEPUB writes b as an EPUB 3 book: a zip of XHTML chapters, one per
+// course plus the exercises, with a package file listing them in order.
+func writeEPUB(w io.Writer, b *book, modified time.Time) error {
+	z := zip.NewWriter(w)
+
+	// The mimetype goes first and uncompressed, so readers can recognise
+	// the file without unzipping it
+	mt, err := z.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	io.WriteString(mt, "application/epub+zip")
+
+	copyFile := func(name, to string) error {
+		data, err := exportTemplates.ReadFile(path.Join("templates/epub", name))
+		if err != nil {
+			return err
+		}
+		fw, err := z.Create(to)
+		if err != nil {
+			return err
+		}
+		if path.Ext(name) == ".xml" {
+			io.WriteString(fw, xml.Header)
+		}
+		_, err = fw.Write(data)
+		return err
+	}
+	render := func(name, to string, data any) error {
+		t, err := template.ParseFS(exportTemplates, path.Join("templates/epub", name))
+		if err != nil {
+			return err
+		}
+		fw, err := z.Create(path.Join("OEBPS", to))
+		if err != nil {
+			return err
+		}
+		// Written here: html/template would escape the "<?" of the prolog
+		io.WriteString(fw, xml.Header)
+		if err := t.Execute(fw, data); err != nil {
+			return fmt.Errorf("%s: %w", to, err)
+		}
+		return nil
+	}
+
+	if err := copyFile("container.xml", "META-INF/container.xml"); err != nil {
+		return err
+	}
+	if err := copyFile("style.css", "OEBPS/style.css"); err != nil {
+		return err
+	}
+	type pageData struct {
+		Book     *book
+		Course   *bookCourse
+		Modified string
+	}
+	if err := render("content.opf", "content.opf", pageData{Book: b, Modified: modified.UTC().Format(time.RFC3339)}); err != nil {
+		return err
+	}
+	if err := render("nav.xhtml", "nav.xhtml", pageData{Book: b}); err != nil {
+		return err
+	}
+	for _, c := range b.Courses {
+		if err := render("chapter.xhtml", c.URL, pageData{Book: b, Course: c}); err != nil {
+			return err
+		}
+	}
+	if err := render("exercises.xhtml", "exercises.xhtml", pageData{Book: b}); err != nil {
+		return err
+	}
+	return z.Close()
+}
@@ -0,0 +1,137 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// With --json a course run is a stream of JSON objects, one per line,
+// instead of text:
+//
+//	{"type":"course","course":4,"title":"CONCURRENCY: GOROUTINES AND CHANNELS"}
+//	{"type":"section","course":4,"section":"timeout","title":"5. TIMEOUT PATTERN"}
+//	{"type":"text","course":4,"section":"timeout","text":"Markdown..."}
+//	{"type":"output","course":4,"section":"timeout","text":"Operation timed out!"}
+//	{"type":"takeaway","course":4,"text":"Goroutines are lightweight..."}
+//	{"type":"end","course":4}
+//
+// "output" is one line the demo printed. A course that fails ends with
+// {"type":"error","text":...} instead of "end".
+type lessonEvent struct {
+	Type    string `json:"type"`
+	Course  int    `json:"course"`
+	Section string `json:"section,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Text    string `json:"text,omitempty"`
+}
+
+// lessonJSON is set by --json.
+var lessonJSON bool
+
+// eventMarker starts the lines a lessonRun writes in JSON mode. The demo
+// output and the lesson's events share the course's writer, which keeps
+// them in order; the marker tells them apart.
+const eventMarker = "\x1e"
+
+// emit writes e if the lesson runs in JSON mode and reports whether it
+// did; if not, the caller prints text.
+func (r *lessonRun) emit(e lessonEvent) bool {
+	if !lessonJSON {
+		return false
+	}
+	e.Course = r.lesson.Number
+	if e.Type != "course" && e.Type != "takeaway" && e.Type != "end" && r.at > 0 {
+		e.Section = r.lesson.Sections[r.at].ID
+	}
+	data, _ := json.Marshal(e)
+	fmt.Fprintf(r.out.w, "%s%s\n", eventMarker, data)
+	return true
+}
+
+// runJSON runs courses in JSON mode, writing their events to w. A course
+// that fails doesn't stop the others, unless ctx is done.
+func runJSON(ctx context.Context, selected []course, w io.Writer) error {
+	var failed []string
+	for _, c := range selected {
+		ew := &eventWriter{w: w, course: c.number}
+		err := runCaptured(ctx, c, ew)
+		ew.Close()
+		if err != nil {
+			ew.event(lessonEvent{Type: "error", Text: err.Error()})
+			if ctx.Err() != nil {
+				return err
+			}
+			failed = append(failed, strconv.Itoa(c.number))
+		}
+	}
+	return coursesFailed(failed)
+}
+
+// eventWriter receives the output of a course run in JSON mode and writes
+// JSON lines to w: the lesson's own events as they are, and everything
+// else the course printed as "output" events.
+type eventWriter struct {
+	w       io.Writer
+	course  int
+	section string // the section the output belongs to
+	partial []byte
+}
+
+func (e *eventWriter) Write(p []byte) (int, error) {
+	e.partial = append(e.partial, p...)
+	for {
+		i := bytes.IndexByte(e.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if err := e.line(string(e.partial[:i])); err != nil {
+			return 0, err
+		}
+		e.partial = e.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Close writes a last line that had no newline.
+func (e *eventWriter) Close() error {
+	if len(e.partial) == 0 {
+		return nil
+	}
+	err := e.line(string(e.partial))
+	e.partial = nil
+	return err
+}
+
+func (e *eventWriter) line(s string) error {
+	// Output that didn't end its line runs into the next event
+	if i := strings.Index(s, eventMarker); i > 0 {
+		if err := e.line(s[:i]); err != nil {
+			return err
+		}
+		s = s[i:]
+	}
+	if data, ok := strings.CutPrefix(s, eventMarker); ok {
+		var ev lessonEvent
+		if json.Unmarshal([]byte(data), &ev) == nil && ev.Type == "section" {
+			e.section = ev.Section
+		}
+		_, err := fmt.Fprintln(e.w, data)
+		return err
+	}
+	return e.event(lessonEvent{Type: "output", Section: e.section, Text: s})
+}
+
+func (e *eventWriter) event(ev lessonEvent) error {
+	ev.Course = e.course
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.w, "%s\n", data)
+	return err
+}
@@ -0,0 +1,76 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLessonJSON(t *testing.T) {
+	lessonJSON = true
+	defer func() { lessonJSON = false }()
+
+	l, _ := parseLesson(1, sampleLesson)
+	sample := course{number: 1, run: func(ctx context.Context, w io.Writer) error {
+		r := newLessonRun(ctx, l, &termRenderer{w: w, width: 80})
+		r.Resume()
+		r.Section("first")
+		r.Print("(demo output)\nno newline")
+		r.Resume()
+		r.Section("second")
+		r.End()
+		return nil
+	}}
+	var buf bytes.Buffer
+	if err := runJSON(context.Background(), []course{sample}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e lessonEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("%v: %s", err, line)
+		}
+		if e.Course != 1 {
+			t.Errorf("event without its course: %s", line)
+		}
+		got = append(got, strings.TrimSpace(fmt.Sprintf("%s %s %s", e.Type, e.Section, e.Text)))
+	}
+	want := []string{
+		"text  Intro.",
+		"section first",
+		"text first Before.",
+		"output first (demo output)",
+		"output first no newline",
+		"text first After.",
+		"section second",
+		"text second ```go\n## not a heading\n<!-- output -->\n```",
+		"takeaway  One",
+		"takeaway  Two",
+		"end",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("events:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// A course that fails or panics ends with an error event, and the
+	// next one still runs
+	failing := course{number: 2, run: func(context.Context, io.Writer) error { return errors.New("disk full") }}
+	panicking := course{number: 3, run: func(context.Context, io.Writer) error { panic("boom") }}
+	buf.Reset()
+	err := runJSON(context.Background(), []course{failing, panicking, sample}, &buf)
+	if err == nil || err.Error() != "2 courses failed (2 and 3)" {
+		t.Errorf("failing courses: err = %v", err)
+	}
+	for _, want := range []string{`{"type":"error","course":2,"text":"course 2 failed: disk full"}`, `"type":"error","course":3`, `{"type":"end","course":1}`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("no %s in the events:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Amounts are whole cents. Floating point can't represent 0.10 exactly, and
+// a bank whose total drifts by a fraction of a cent per transfer is exactly
+// the bug the invariant tests are meant to catch.
+type Cents int64
+
+func (c Cents) String() string {
+	sign := ""
+	if c < 0 {
+		sign, c = "-", -c
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, c/100, c%100)
+}
+
+var (
+	ErrNoAccount         = errors.New("no such account")
+	ErrAccountExists     = errors.New("account already exists")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrSameAccount       = errors.New("cannot transfer to the same account")
+	ErrInvalidAmount     = errors.New("amount must be positive")
+	ErrClosed            = errors.New("bank is closed")
+)
+
+// Bank is implemented twice, to compare the two ways Go programs share
+// state between goroutines:
+//
+//   - MutexBank: "share memory by locking it". Each account has a mutex and
+//     a transfer locks both accounts, always in the same order.
+//   - ChannelBank: "share memory by communicating". One goroutine owns every
+//     balance; everything else sends it requests over a channel.
+//
+// Both must keep the same invariants under any number of concurrent callers:
+// money is never created or destroyed (Total never changes after the
+// accounts are opened) and no balance goes below zero.
+type Bank interface {
+	Open(id string, initial Cents) error
+	Deposit(id string, amount Cents) error
+	Transfer(from, to string, amount Cents) error
+	Balance(id string) (Cents, error)
+	// Total is the sum of all balances, taken as one consistent snapshot:
+	// no transfer is half-applied when it is read.
+	Total() Cents
+	Close()
+}
+
+// checkTransfer holds the rules both banks share, so they can only differ
+// in how they handle concurrency.
+func checkTransfer(from, to string, amount Cents) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if from == to {
+		return ErrSameAccount
+	}
+	return nil
+}
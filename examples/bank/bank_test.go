@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run with: go test -race
+// Benchmarks: go test -run '^$' -bench . -benchmem
+
+func banks() map[string]func() Bank {
+	return map[string]func() Bank{
+		"mutex":   func() Bank { return NewMutexBank() },
+		"channel": func() Bank { return NewChannelBank() },
+	}
+}
+
+// forEachBank runs test against a fresh bank of each kind.
+func forEachBank(t *testing.T, test func(t *testing.T, b Bank)) {
+	for name, newBank := range banks() {
+		t.Run(name, func(t *testing.T) {
+			b := newBank()
+			defer b.Close()
+			test(t, b)
+		})
+	}
+}
+
+func TestBankRules(t *testing.T) {
+	forEachBank(t, func(t *testing.T, b Bank) {
+		if err := b.Open("alice", 100); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Open("bob", 0); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name     string
+			from, to string
+			amount   Cents
+			want     error
+		}{
+			{"ok", "alice", "bob", 60, nil},
+			{"overdraw", "alice", "bob", 41, ErrInsufficientFunds},
+			{"exact balance", "alice", "bob", 40, nil},
+			{"zero", "bob", "alice", 0, ErrInvalidAmount},
+			{"negative", "bob", "alice", -5, ErrInvalidAmount},
+			{"same account", "bob", "bob", 1, ErrSameAccount},
+			{"unknown source", "carol", "bob", 1, ErrNoAccount},
+			{"unknown target", "bob", "carol", 1, ErrNoAccount},
+		}
+		for _, tt := range tests {
+			if err := b.Transfer(tt.from, tt.to, tt.amount); !errors.Is(err, tt.want) {
+				t.Errorf("%s: Transfer() = %v, want %v", tt.name, err, tt.want)
+			}
+		}
+
+		if got, _ := b.Balance("alice"); got != 0 {
+			t.Errorf("alice = %s, want 0.00", got)
+		}
+		if got, _ := b.Balance("bob"); got != 100 {
+			t.Errorf("bob = %s, want 1.00", got)
+		}
+		if err := b.Open("bob", 5); !errors.Is(err, ErrAccountExists) {
+			t.Errorf("reopen: %v, want ErrAccountExists", err)
+		}
+		if err := b.Deposit("bob", 50); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.Total(); got != 150 {
+			t.Errorf("total = %s, want 1.50", got)
+		}
+		if _, err := b.Balance("carol"); !errors.Is(err, ErrNoAccount) {
+			t.Errorf("Balance(carol) = %v, want ErrNoAccount", err)
+		}
+	})
+}
+
+func TestBankClosed(t *testing.T) {
+	forEachBank(t, func(t *testing.T, b Bank) {
+		b.Open("a", 10)
+		b.Open("b", 10)
+		b.Close()
+		b.Close() // twice is fine
+		if err := b.Transfer("a", "b", 1); !errors.Is(err, ErrClosed) {
+			t.Errorf("Transfer after Close = %v, want ErrClosed", err)
+		}
+	})
+}
+
+// TestInvariantsUnderContention is the heart of the exercise: lots of
+// goroutines, few accounts, and the totals must still add up exactly.
+func TestInvariantsUnderContention(t *testing.T) {
+	forEachBank(t, func(t *testing.T, b Bank) {
+		cfg := SimConfig{Accounts: 4, Initial: 1000, Workers: 16, Transfers: 2000, MaxAmount: 400, Seed: 1}
+		res, err := Simulate(b, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := CheckInvariants(b, cfg.Accounts, 4000); err != nil {
+			t.Fatal(err)
+		}
+		if res.Succeeded+res.Insufficient != cfg.Workers*cfg.Transfers {
+			t.Errorf("%d + %d transfers, want %d", res.Succeeded, res.Insufficient, cfg.Workers*cfg.Transfers)
+		}
+		if res.Succeeded == 0 || res.Insufficient == 0 {
+			t.Errorf("want both successful and refused transfers, got %s", res)
+		}
+	})
+}
+
+// TestNoDeadlock sends money both ways between the same two accounts from
+// many goroutines. Locking "from, then to" deadlocks here within
+// milliseconds; the test fails after a timeout instead of hanging forever.
+func TestNoDeadlock(t *testing.T) {
+	forEachBank(t, func(t *testing.T, b Bank) {
+		b.Open("a", 1_000_000)
+		b.Open("b", 1_000_000)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			var wg sync.WaitGroup
+			for i := range 20 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					from, to := "a", "b"
+					if i%2 == 1 {
+						from, to = to, from
+					}
+					for range 1000 {
+						b.Transfer(from, to, 1)
+					}
+				}()
+			}
+			wg.Wait()
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("transfers did not finish: deadlock?")
+		}
+		if got := b.Total(); got != 2_000_000 {
+			t.Errorf("total = %s, want 20000.00", got)
+		}
+	})
+}
+
+// TestTotalIsConsistent reads Total while transfers run. A Total that sums
+// accounts without holding them all would sometimes see money that has
+// left one account but not reached the other.
+func TestTotalIsConsistent(t *testing.T) {
+	forEachBank(t, func(t *testing.T, b Bank) {
+		for i := range 5 {
+			b.Open(accountID(i), 1000)
+		}
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		for w := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; ; i++ {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					b.Transfer(accountID((i+w)%5), accountID((i+w+1)%5), 7)
+				}
+			}()
+		}
+
+		for range 500 {
+			if got := b.Total(); got != 5000 {
+				t.Errorf("Total() = %s mid-flight, want 50.00", got)
+				break
+			}
+		}
+		close(stop)
+		wg.Wait()
+	})
+}
+
+func TestCentsString(t *testing.T) {
+	for c, want := range map[Cents]string{0: "0.00", 5: "0.05", 1234: "12.34", -250: "-2.50"} {
+		if got := c.String(); got != want {
+			t.Errorf("Cents(%d) = %q, want %q", int64(c), got, want)
+		}
+	}
+}
+
+// benchmarkTransfers runs parallel transfers across n accounts. With many
+// accounts the mutex bank's transfers rarely touch the same account and run
+// in parallel; the channel bank always goes through one goroutine.
+func benchmarkTransfers(b *testing.B, bank Bank, n int) {
+	defer bank.Close()
+	for i := range n {
+		bank.Open(accountID(i), 1<<40)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			bank.Transfer(accountID(i%n), accountID((i+1)%n), 1)
+			i++
+		}
+	})
+}
+
+func BenchmarkMutexBank_2Accounts(b *testing.B)     { benchmarkTransfers(b, NewMutexBank(), 2) }
+func BenchmarkChannelBank_2Accounts(b *testing.B)   { benchmarkTransfers(b, NewChannelBank(), 2) }
+func BenchmarkMutexBank_100Accounts(b *testing.B)   { benchmarkTransfers(b, NewMutexBank(), 100) }
+func BenchmarkChannelBank_100Accounts(b *testing.B) { benchmarkTransfers(b, NewChannelBank(), 100) }
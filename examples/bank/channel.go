@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// request is one operation for the owner goroutine. apply runs on that
+// goroutine, so it may read and write balances freely, and its error goes
+// back on reply.
+type request struct {
+	apply func(balances map[string]Cents) error
+	reply chan error
+}
+
+// ChannelBank keeps every balance in a map that only one goroutine, run,
+// ever touches. There is no mutex anywhere: the map is safe because it is
+// never shared, and operations are serialized because the goroutine
+// handles one request at a time.
+//
+// That makes every operation atomic for free, including Total, and a
+// deadlock impossible. The cost is that nothing runs in parallel: every
+// caller queues for the one goroutine.
+type ChannelBank struct {
+	requests chan request
+	done     chan struct{}
+	close    sync.Once
+}
+
+func NewChannelBank() *ChannelBank {
+	b := &ChannelBank{
+		requests: make(chan request),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *ChannelBank) run() {
+	balances := make(map[string]Cents)
+	for {
+		select {
+		case req := <-b.requests:
+			req.reply <- req.apply(balances)
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// do sends fn to the owner goroutine and waits for its result.
+func (b *ChannelBank) do(fn func(balances map[string]Cents) error) error {
+	req := request{apply: fn, reply: make(chan error, 1)}
+	select {
+	case b.requests <- req:
+		return <-req.reply
+	case <-b.done:
+		return ErrClosed
+	}
+}
+
+func (b *ChannelBank) Open(id string, initial Cents) error {
+	if initial < 0 {
+		return ErrInvalidAmount
+	}
+	return b.do(func(balances map[string]Cents) error {
+		if _, ok := balances[id]; ok {
+			return fmt.Errorf("open %s: %w", id, ErrAccountExists)
+		}
+		balances[id] = initial
+		return nil
+	})
+}
+
+func (b *ChannelBank) Deposit(id string, amount Cents) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	return b.do(func(balances map[string]Cents) error {
+		if _, ok := balances[id]; !ok {
+			return fmt.Errorf("%s: %w", id, ErrNoAccount)
+		}
+		balances[id] += amount
+		return nil
+	})
+}
+
+func (b *ChannelBank) Transfer(from, to string, amount Cents) error {
+	if err := checkTransfer(from, to, amount); err != nil {
+		return err
+	}
+	return b.do(func(balances map[string]Cents) error {
+		src, ok := balances[from]
+		if !ok {
+			return fmt.Errorf("%s: %w", from, ErrNoAccount)
+		}
+		if _, ok := balances[to]; !ok {
+			return fmt.Errorf("%s: %w", to, ErrNoAccount)
+		}
+		if src < amount {
+			return fmt.Errorf("transfer %s from %s: %w", amount, from, ErrInsufficientFunds)
+		}
+		balances[from] -= amount
+		balances[to] += amount
+		return nil
+	})
+}
+
+func (b *ChannelBank) Balance(id string) (Cents, error) {
+	var balance Cents
+	err := b.do(func(balances map[string]Cents) error {
+		var ok bool
+		if balance, ok = balances[id]; !ok {
+			return fmt.Errorf("%s: %w", id, ErrNoAccount)
+		}
+		return nil
+	})
+	return balance, err
+}
+
+func (b *ChannelBank) Total() Cents {
+	var total Cents
+	b.do(func(balances map[string]Cents) error {
+		for _, c := range balances {
+			total += c
+		}
+		return nil
+	})
+	return total
+}
+
+// Close stops the owner goroutine. Without it the goroutine would block on
+// b.requests forever: a goroutine leak. sync.Once makes a second Close safe.
+func (b *ChannelBank) Close() {
+	b.close.Do(func() { close(b.done) })
+}
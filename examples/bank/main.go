@@ -0,0 +1,64 @@
+// Command bank simulates many goroutines moving money between accounts at
+// once, against a mutex-based and a channel-based bank, and checks that no
+// money was created or lost:
+//
+//	go run ./examples/bank
+//	go run -race ./examples/bank -workers 50 -transfers 5000 -accounts 5
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	impl := flag.String("impl", "both", "bank to simulate: mutex, channel or both")
+	cfg := SimConfig{Initial: 100_00, MaxAmount: 50_00}
+	flag.IntVar(&cfg.Accounts, "accounts", 10, "number of accounts (fewer means more contention)")
+	flag.IntVar(&cfg.Workers, "workers", 20, "concurrent goroutines")
+	flag.IntVar(&cfg.Transfers, "transfers", 10_000, "transfers per goroutine")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed, to repeat a run")
+	flag.Parse()
+	cfg.Seed = uint64(*seed)
+	if cfg.Accounts < 2 || cfg.Workers < 1 {
+		fmt.Fprintln(os.Stderr, "need at least 2 accounts and 1 worker")
+		os.Exit(2)
+	}
+
+	banks := map[string]func() Bank{
+		"mutex":   func() Bank { return NewMutexBank() },
+		"channel": func() Bank { return NewChannelBank() },
+	}
+	names := []string{*impl}
+	if *impl == "both" {
+		names = []string{"mutex", "channel"}
+	}
+
+	fmt.Printf("%d workers x %d transfers between %d accounts (seed %d)\n",
+		cfg.Workers, cfg.Transfers, cfg.Accounts, *seed)
+	failed := false
+	for _, name := range names {
+		newBank, ok := banks[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown -impl %q (want mutex, channel or both)\n", name)
+			os.Exit(2)
+		}
+		b := newBank()
+		res, err := Simulate(b, cfg)
+		if err == nil {
+			err = CheckInvariants(b, cfg.Accounts, res.TotalBefore)
+		}
+		b.Close()
+
+		fmt.Printf("%-8s %s\n", name+":", res)
+		if err != nil {
+			fmt.Printf("%-8s INVARIANT BROKEN: %v\n", "", err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
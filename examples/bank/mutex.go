@@ -0,0 +1,149 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+type account struct {
+	mu      sync.Mutex
+	id      string
+	balance Cents
+}
+
+// MutexBank guards each balance with its own mutex, so transfers between
+// unrelated accounts run in parallel.
+//
+// The obvious transfer deadlocks:
+//
+//	from.mu.Lock() // goroutine 1 locks A, goroutine 2 locks B
+//	to.mu.Lock()   // 1 waits for B, 2 waits for A: forever
+//
+// when one goroutine moves money A->B while another moves B->A. The fix is
+// a global lock order: always lock the account with the smaller ID first.
+// Then both goroutines try A first, one wins, and no cycle of waiting can
+// form.
+type MutexBank struct {
+	mu       sync.RWMutex // guards the map itself, not the balances
+	accounts map[string]*account
+}
+
+func NewMutexBank() *MutexBank {
+	return &MutexBank{accounts: make(map[string]*account)}
+}
+
+func (b *MutexBank) Open(id string, initial Cents) error {
+	if initial < 0 {
+		return ErrInvalidAmount
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.accounts == nil {
+		return ErrClosed
+	}
+	if _, ok := b.accounts[id]; ok {
+		return fmt.Errorf("open %s: %w", id, ErrAccountExists)
+	}
+	b.accounts[id] = &account{id: id, balance: initial}
+	return nil
+}
+
+func (b *MutexBank) get(id string) (*account, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.accounts == nil {
+		return nil, ErrClosed
+	}
+	a, ok := b.accounts[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", id, ErrNoAccount)
+	}
+	return a, nil
+}
+
+func (b *MutexBank) Deposit(id string, amount Cents) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	a, err := b.get(id)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.balance += amount
+	return nil
+}
+
+func (b *MutexBank) Transfer(from, to string, amount Cents) error {
+	if err := checkTransfer(from, to, amount); err != nil {
+		return err
+	}
+	src, err := b.get(from)
+	if err != nil {
+		return err
+	}
+	dst, err := b.get(to)
+	if err != nil {
+		return err
+	}
+
+	first, second := src, dst
+	if second.id < first.id {
+		first, second = second, first
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+
+	// Check and debit under the same lock. Checking first and locking
+	// after would let two transfers both see enough money and both spend it.
+	if src.balance < amount {
+		return fmt.Errorf("transfer %s from %s: %w", amount, from, ErrInsufficientFunds)
+	}
+	src.balance -= amount
+	dst.balance += amount
+	return nil
+}
+
+func (b *MutexBank) Balance(id string) (Cents, error) {
+	a, err := b.get(id)
+	if err != nil {
+		return 0, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.balance, nil
+}
+
+// Total locks every account, in ID order like Transfer, before summing.
+// Summing while locking one account at a time could count money that is
+// in flight twice or not at all.
+func (b *MutexBank) Total() Cents {
+	b.mu.RLock()
+	all := make([]*account, 0, len(b.accounts))
+	for _, a := range b.accounts {
+		all = append(all, a)
+	}
+	b.mu.RUnlock()
+
+	slices.SortFunc(all, func(x, y *account) int { return cmp.Compare(x.id, y.id) })
+	for _, a := range all {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+	}
+	var total Cents
+	for _, a := range all {
+		total += a.balance
+	}
+	return total
+}
+
+func (b *MutexBank) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.accounts = nil
+}
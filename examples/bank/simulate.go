@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// SimConfig describes a simulation: Workers goroutines each make Transfers
+// random transfers between Accounts accounts.
+type SimConfig struct {
+	Accounts  int
+	Initial   Cents // opening balance of each account
+	Workers   int
+	Transfers int // per worker
+	MaxAmount Cents
+	Seed      uint64
+}
+
+// SimResult counts what happened.
+type SimResult struct {
+	Succeeded    int
+	Insufficient int // refused for lack of funds: expected, not a bug
+	Elapsed      time.Duration
+	TotalBefore  Cents
+	TotalAfter   Cents
+}
+
+func (r SimResult) String() string {
+	return fmt.Sprintf("%d transfers ok, %d refused for insufficient funds, in %s; total %s -> %s",
+		r.Succeeded, r.Insufficient, r.Elapsed.Round(time.Millisecond), r.TotalBefore, r.TotalAfter)
+}
+
+func accountID(i int) string { return fmt.Sprintf("acct-%03d", i) }
+
+// Simulate opens the accounts and hammers b with concurrent transfers. Any
+// error other than insufficient funds is returned: it means a bug.
+func Simulate(b Bank, cfg SimConfig) (SimResult, error) {
+	for i := range cfg.Accounts {
+		if err := b.Open(accountID(i), cfg.Initial); err != nil {
+			return SimResult{}, err
+		}
+	}
+	res := SimResult{TotalBefore: b.Total()}
+
+	type counts struct{ ok, insufficient int }
+	perWorker := make([]counts, cfg.Workers)
+	errs := make(chan error, cfg.Workers)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := range cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// One generator per worker: a shared *rand.Rand is not safe
+			// for concurrent use, and seeding each from the config keeps a
+			// run repeatable
+			rng := rand.New(rand.NewPCG(cfg.Seed, uint64(w)))
+			for range cfg.Transfers {
+				from := rng.IntN(cfg.Accounts)
+				to := rng.IntN(cfg.Accounts - 1)
+				if to >= from {
+					to++ // any account except from
+				}
+				amount := Cents(rng.Int64N(int64(cfg.MaxAmount))) + 1
+				err := b.Transfer(accountID(from), accountID(to), amount)
+				switch {
+				case err == nil:
+					perWorker[w].ok++
+				case errors.Is(err, ErrInsufficientFunds):
+					perWorker[w].insufficient++
+				default:
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	res.Elapsed = time.Since(start)
+	close(errs)
+	if err := <-errs; err != nil {
+		return res, err
+	}
+
+	for _, c := range perWorker {
+		res.Succeeded += c.ok
+		res.Insufficient += c.insufficient
+	}
+	res.TotalAfter = b.Total()
+	return res, nil
+}
+
+// CheckInvariants reports money created or destroyed, or a negative
+// balance.
+func CheckInvariants(b Bank, accounts int, want Cents) error {
+	var errs []error
+	if got := b.Total(); got != want {
+		errs = append(errs, fmt.Errorf("total is %s, want %s", got, want))
+	}
+	for i := range accounts {
+		bal, err := b.Balance(accountID(i))
+		if err != nil {
+			errs = append(errs, err)
+		} else if bal < 0 {
+			errs = append(errs, fmt.Errorf("%s has a negative balance: %s", accountID(i), bal))
+		}
+	}
+	return errors.Join(errs...)
+}
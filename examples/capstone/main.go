@@ -0,0 +1,54 @@
+// Command capstone is a tiny user-search tool that turns command-line
+// filters into a parameterised SQL query.
+//
+// It lives in its own module and imports pkg/querybuilder from the course
+// repository. There is no replace directive in its go.mod: the go.work file
+// at the repository root tells the go command to use the local copy of
+// pkg/querybuilder (see course 15).
+//
+// Usage:
+//
+//	go run ./examples/capstone -name alice -min-age 21 -limit 5
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/owolabijunior12/learning-golang/pkg/querybuilder"
+)
+
+func main() {
+	name := flag.String("name", "", "only users whose name contains this text")
+	minAge := flag.Int("min-age", 0, "only users at least this old")
+	limit := flag.Int("limit", 10, "maximum number of rows")
+	flag.Parse()
+
+	query, args := buildUserQuery(*name, *minAge, *limit)
+
+	fmt.Println("Query:", query)
+	fmt.Println("Args: ", args)
+}
+
+// buildUserQuery combines the optional filters into a single WHERE clause.
+func buildUserQuery(name string, minAge, limit int) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if name != "" {
+		conditions = append(conditions, "name LIKE ?")
+		args = append(args, "%"+name+"%")
+	}
+	if minAge > 0 {
+		conditions = append(conditions, "age >= ?")
+		args = append(args, minAge)
+	}
+
+	qb := querybuilder.New().Select("id, name, email, age").From("users")
+	if len(conditions) > 0 {
+		qb = qb.Where(strings.Join(conditions, " AND "), args...)
+	}
+
+	return qb.Limit(limit).Build()
+}
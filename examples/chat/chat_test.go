@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+)
+
+type testChat struct {
+	server *httptest.Server
+	redis  *miniredis.Miniredis
+	hub    *Hub
+	cancel context.CancelFunc
+}
+
+func newTestChat(t *testing.T) *testChat {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	hub := NewHub(NewRedisHistory(rdb, historyLimit), log.New(io.Discard, "", 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Run(ctx)
+
+	server := httptest.NewServer(Routes(hub))
+	t.Cleanup(func() {
+		cancel()
+		server.Close()
+	})
+	return &testChat{server: server, redis: mr, hub: hub, cancel: cancel}
+}
+
+func (tc *testChat) dial(t *testing.T, room, name string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(tc.server.URL, "http") + "/ws?room=" + room + "&name=" + name
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial %s as %s: %v", room, name, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// next reads messages until one matches, failing after a timeout
+func next(t *testing.T, conn *websocket.Conn, match func(Message) bool) Message {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg Message
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("waiting for message: %v", err)
+		}
+		if match(msg) {
+			return msg
+		}
+	}
+}
+
+func textIs(text string) func(Message) bool {
+	return func(m Message) bool { return m.Text == text }
+}
+
+func TestBroadcastWithinRoom(t *testing.T) {
+	tc := newTestChat(t)
+	alice := tc.dial(t, "go", "alice")
+	next(t, alice, textIs("alice joined"))
+	bob := tc.dial(t, "go", "bob")
+	next(t, alice, textIs("bob joined"))
+
+	alice.WriteJSON(map[string]string{"text": "  hello gophers  "})
+
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		msg := next(t, conn, func(m Message) bool { return !m.System })
+		if msg.User != "alice" || msg.Text != "hello gophers" || msg.Room != "go" {
+			t.Errorf("got %+v", msg)
+		}
+	}
+}
+
+func TestRoomsAreIsolated(t *testing.T) {
+	tc := newTestChat(t)
+	alice := tc.dial(t, "go", "alice")
+	next(t, alice, textIs("alice joined"))
+	carol := tc.dial(t, "rust", "carol")
+	next(t, carol, textIs("carol joined"))
+
+	carol.WriteJSON(map[string]string{"text": "wrong room"})
+	next(t, carol, textIs("wrong room"))
+	alice.WriteJSON(map[string]string{"text": "right room"})
+
+	// The first chat message alice sees must be her own, not carol's
+	if msg := next(t, alice, func(m Message) bool { return !m.System }); msg.Text != "right room" {
+		t.Errorf("alice received %+v from another room", msg)
+	}
+}
+
+func TestHistoryReplayedOnJoin(t *testing.T) {
+	tc := newTestChat(t)
+	alice := tc.dial(t, "go", "alice")
+	for _, text := range []string{"first", "second"} {
+		alice.WriteJSON(map[string]string{"text": text})
+		next(t, alice, textIs(text))
+	}
+
+	bob := tc.dial(t, "go", "bob")
+	if msg := next(t, bob, func(Message) bool { return true }); msg.Text != "first" {
+		t.Errorf("first message for a new client = %+v, want history", msg)
+	}
+	next(t, bob, textIs("second"))
+	next(t, bob, textIs("bob joined"))
+
+	// Stored in Redis as JSON, one list per room
+	items, err := tc.redis.List("chat:history:go")
+	if err != nil || len(items) != 2 {
+		t.Fatalf("redis history = %v, %v", items, err)
+	}
+}
+
+func TestHistoryIsCapped(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	history := NewRedisHistory(rdb, 3)
+	ctx := context.Background()
+
+	for _, text := range []string{"1", "2", "3", "4", "5"} {
+		if err := history.Append(ctx, Message{Room: "r", Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mr.RPush("chat:history:r", "not json") // corrupt entries are skipped
+
+	recent, err := history.Recent(ctx, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, m := range recent {
+		texts = append(texts, m.Text)
+	}
+	if got := strings.Join(texts, ","); got != "3,4,5" {
+		t.Errorf("recent = %s, want 3,4,5", got)
+	}
+}
+
+func TestLeaveIsAnnounced(t *testing.T) {
+	tc := newTestChat(t)
+	alice := tc.dial(t, "go", "alice")
+	next(t, alice, textIs("alice joined"))
+	bob := tc.dial(t, "go", "bob")
+	next(t, alice, textIs("bob joined"))
+
+	bob.Close()
+	next(t, alice, textIs("bob left"))
+}
+
+func TestInvalidNamesRejected(t *testing.T) {
+	tc := newTestChat(t)
+	for _, query := range []string{"room=a%20b", "name=" + strings.Repeat("x", 33), "name=%3Cscript%3E"} {
+		res, err := http.Get(tc.server.URL + "/ws?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, res.StatusCode)
+		}
+	}
+}
+
+func TestServesHTMLClient(t *testing.T) {
+	tc := newTestChat(t)
+	res, err := http.Get(tc.server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), "new WebSocket") {
+		t.Errorf("GET / = %d, body does not look like the chat client", res.StatusCode)
+	}
+}
+
+func TestHubShutdownClosesClients(t *testing.T) {
+	tc := newTestChat(t)
+	alice := tc.dial(t, "go", "alice")
+	next(t, alice, textIs("alice joined"))
+
+	tc.cancel()
+
+	alice.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := alice.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseNoStatusReceived, websocket.CloseNormalClosure) {
+		t.Errorf("expected the server to close the socket, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second    // time allowed to write one message
+	pongWait       = 60 * time.Second    // time allowed between pongs from the browser
+	pingPeriod     = (pongWait * 9) / 10 // send pings a bit more often than pongWait
+	maxMessageSize = 4096                // bytes
+	maxTextLength  = 1000                // characters per chat message
+	sendBuffer     = 64                  // queued outgoing messages per client
+	historyLimit   = 50                  // messages kept per room; must be < sendBuffer
+)
+
+// Client is one WebSocket connection. Each client runs two goroutines:
+// readPump (browser -> hub) and writePump (hub -> browser). Only writePump
+// writes to the connection, because gorilla/websocket allows one writer.
+type Client struct {
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+	room string
+	name string
+}
+
+func (c *Client) readPump() {
+	defer func() {
+		c.hub.Unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var in struct {
+			Text string `json:"text"`
+		}
+		if err := c.conn.ReadJSON(&in); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				c.hub.logger.Printf("read from %s: %v", c.name, err)
+			}
+			return
+		}
+
+		text := strings.TrimSpace(in.Text)
+		if text == "" {
+			continue
+		}
+		if len([]rune(text)) > maxTextLength {
+			text = string([]rune(text)[:maxTextLength])
+		}
+
+		msg := Message{Room: c.room, User: c.name, Text: text, SentAt: time.Now().UTC()}
+
+		// Save outside the hub goroutine so a slow Redis never stalls delivery
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		if err := c.hub.history.Append(ctx, msg); err != nil {
+			c.hub.logger.Printf("save message: %v", err)
+		}
+		cancel()
+
+		c.hub.Broadcast(msg)
+	}
+}
+
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// The hub closed the channel: say goodbye and stop
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// History keeps recent messages so people joining a room can catch up.
+type History interface {
+	Append(ctx context.Context, msg Message) error
+	Recent(ctx context.Context, room string) ([]Message, error)
+}
+
+// RedisHistory stores each room as a capped Redis list (course 9):
+// RPUSH adds the newest message, LTRIM drops the oldest beyond the limit.
+type RedisHistory struct {
+	client *redis.Client
+	limit  int64
+}
+
+func NewRedisHistory(client *redis.Client, limit int64) *RedisHistory {
+	return &RedisHistory{client: client, limit: limit}
+}
+
+func (h *RedisHistory) Append(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	// A pipeline sends both commands in one round trip; TxPipelined also
+	// wraps them in MULTI/EXEC so no reader sees the untrimmed list.
+	_, err = h.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, historyKey(msg.Room), data)
+		pipe.LTrim(ctx, historyKey(msg.Room), -h.limit, -1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("append history for %s: %w", msg.Room, err)
+	}
+	return nil
+}
+
+func (h *RedisHistory) Recent(ctx context.Context, room string) ([]Message, error) {
+	items, err := h.client.LRange(ctx, historyKey(room), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("read history for %s: %w", room, err)
+	}
+
+	messages := make([]Message, 0, len(items))
+	for _, item := range items {
+		var msg Message
+		if err := json.Unmarshal([]byte(item), &msg); err != nil {
+			continue // skip a corrupt entry rather than hide the whole history
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func historyKey(room string) string {
+	return "chat:history:" + room
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Message is what clients send and receive, as JSON.
+type Message struct {
+	Room   string    `json:"room"`
+	User   string    `json:"user"`
+	Text   string    `json:"text"`
+	System bool      `json:"system,omitempty"` // join/leave notices
+	SentAt time.Time `json:"sent_at"`
+}
+
+// Hub owns every room. Only the Run goroutine touches the rooms map; other
+// goroutines talk to it through channels, so no mutex is needed (course 4).
+type Hub struct {
+	rooms      map[string]map[*Client]bool
+	register   chan *Client
+	unregister chan *Client
+	broadcast  chan Message
+	done       chan struct{} // closed when Run returns
+	history    History
+	logger     *log.Logger
+}
+
+func NewHub(history History, logger *log.Logger) *Hub {
+	return &Hub{
+		rooms:      make(map[string]map[*Client]bool),
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		broadcast:  make(chan Message),
+		done:       make(chan struct{}),
+		history:    history,
+		logger:     logger,
+	}
+}
+
+// Run processes joins, leaves and messages until ctx is cancelled.
+func (h *Hub) Run(ctx context.Context) {
+	defer close(h.done)
+	for {
+		select {
+		case <-ctx.Done():
+			for _, clients := range h.rooms {
+				for c := range clients {
+					close(c.send)
+				}
+			}
+			h.rooms = make(map[string]map[*Client]bool)
+			return
+
+		case c := <-h.register:
+			if h.rooms[c.room] == nil {
+				h.rooms[c.room] = make(map[*Client]bool)
+			}
+			h.rooms[c.room][c] = true
+			h.deliver(systemMessage(c.room, c.name+" joined"))
+
+		case c := <-h.unregister:
+			if h.rooms[c.room][c] {
+				h.remove(c)
+				h.deliver(systemMessage(c.room, c.name+" left"))
+			}
+
+		case msg := <-h.broadcast:
+			h.deliver(msg)
+		}
+	}
+}
+
+// Register, Unregister and Broadcast hand work to Run. They give up once the
+// hub has stopped, so client goroutines never block on a dead hub.
+func (h *Hub) Register(c *Client) bool {
+	select {
+	case h.register <- c:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+func (h *Hub) Unregister(c *Client) {
+	select {
+	case h.unregister <- c:
+	case <-h.done:
+	}
+}
+
+func (h *Hub) Broadcast(msg Message) {
+	select {
+	case h.broadcast <- msg:
+	case <-h.done:
+	}
+}
+
+// deliver sends msg to everyone in its room. A client whose buffer is full is
+// too slow to keep up; it is dropped rather than blocking the whole hub.
+func (h *Hub) deliver(msg Message) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		h.logger.Printf("encode message: %v", err)
+		return
+	}
+
+	for c := range h.rooms[msg.Room] {
+		select {
+		case c.send <- data:
+		default:
+			h.logger.Printf("dropping slow client %s in %s", c.name, c.room)
+			h.remove(c)
+		}
+	}
+}
+
+func (h *Hub) remove(c *Client) {
+	delete(h.rooms[c.room], c)
+	if len(h.rooms[c.room]) == 0 {
+		delete(h.rooms, c.room)
+	}
+	close(c.send) // tells the client's writePump to stop
+}
+
+func systemMessage(room, text string) Message {
+	return Message{Room: room, User: "system", Text: text, System: true, SentAt: time.Now().UTC()}
+}
@@ -0,0 +1,62 @@
+// Command chat is a multi-room WebSocket chat server.
+//
+// It ties together goroutines and channels (course 4), HTTP (course 6) and
+// Redis (course 9):
+//
+//	docker run --name redis -d -p 6379:6379 redis:latest
+//	go run ./examples/chat
+//	open http://localhost:8083 in two browser tabs
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	addr := flag.String("addr", ":8083", "listen address")
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address for message history")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[chat] ", log.LstdFlags)
+	if err := run(*addr, *redisAddr, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(addr, redisAddr string, logger *log.Logger) error {
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer rdb.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		logger.Printf("warning: redis unavailable at %s, history is disabled: %v", redisAddr, err)
+	}
+
+	hub := NewHub(NewRedisHistory(rdb, historyLimit), logger)
+	go hub.Run(ctx)
+
+	server := &http.Server{Addr: addr, Handler: Routes(hub), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx) // hijacked WebSocket connections are closed by the hub
+	}()
+
+	logger.Printf("open http://localhost%s", addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// The default CheckOrigin rejects cross-origin pages, which is what we want:
+	// only the page served by this server may open a socket.
+}
+
+// Room and user names: letters, digits, - and _, up to 32 characters
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Routes serves the HTML client at / and the WebSocket at /ws.
+func Routes(hub *Hub) http.Handler {
+	static, _ := fs.Sub(staticFiles, "static") // cannot fail: the directory is embedded
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /ws", func(w http.ResponseWriter, r *http.Request) {
+		serveWS(hub, w, r)
+	})
+	return mux
+}
+
+// serveWS upgrades the request and joins the client to a room:
+// /ws?room=lobby&name=alice
+func serveWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	room := queryOr(r, "room", "lobby")
+	name := queryOr(r, "name", "anonymous")
+	if !validName.MatchString(room) || !validName.MatchString(name) {
+		http.Error(w, "room and name must be 1-32 letters, digits, - or _", http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already written an HTTP error
+	}
+
+	client := &Client{hub: hub, conn: conn, send: make(chan []byte, sendBuffer), room: room, name: name}
+
+	// Queue the room's history before joining, so it arrives first
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	history, err := hub.history.Recent(ctx, room)
+	cancel()
+	if err != nil {
+		hub.logger.Printf("load history: %v", err)
+	}
+	for _, msg := range history {
+		data, _ := json.Marshal(msg)
+		select {
+		case client.send <- data:
+		default: // only if historyLimit >= sendBuffer
+		}
+	}
+
+	if !hub.Register(client) {
+		conn.Close()
+		return
+	}
+	go client.writePump()
+	go client.readPump()
+}
+
+func queryOr(r *http.Request, key, fallback string) string {
+	if value := r.URL.Query().Get(key); value != "" {
+		return value
+	}
+	return fallback
+}
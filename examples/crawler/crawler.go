@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/pkg/pool"
+)
+
+// Result is one checked URL.
+type Result struct {
+	URL      string        `json:"url"`
+	FoundOn  string        `json:"found_on,omitempty"` // empty for the start URL
+	Depth    int           `json:"depth"`
+	Status   int           `json:"status,omitempty"` // 0 if the request failed
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	Links    int           `json:"links"` // links found on the page, if it was parsed
+}
+
+// Broken reports whether the link should be fixed.
+func (r Result) Broken() bool {
+	return r.Error != "" || r.Status >= 400
+}
+
+// Crawler checks every link reachable from a start URL. Pages on the start
+// URL's host are fetched and parsed up to MaxDepth; links to other hosts are
+// checked but not followed.
+type Crawler struct {
+	Client    *http.Client
+	Workers   int // concurrent requests
+	MaxDepth  int // 0 checks only the start page
+	MaxPages  int // stop scheduling after this many URLs; 0 means no limit
+	UserAgent string
+
+	robots *robotsCache
+}
+
+func NewCrawler(client *http.Client, workers, maxDepth, maxPages int) *Crawler {
+	return &Crawler{
+		Client:    client,
+		Workers:   workers,
+		MaxDepth:  maxDepth,
+		MaxPages:  maxPages,
+		UserAgent: "learning-golang-crawler/1.0",
+	}
+}
+
+// job is a URL waiting for a worker.
+type job struct {
+	url     *url.URL
+	foundOn string
+	depth   int
+}
+
+// fetched is what a worker reports back: the result plus any links to follow.
+type fetched struct {
+	job    job
+	result Result
+	links  []string
+}
+
+var errRobots = errors.New("disallowed by robots.txt")
+
+// Crawl runs until every reachable link has been checked, MaxPages is hit or
+// ctx is cancelled. On cancellation it returns the results so far along with
+// ctx.Err().
+//
+// The visited set lives in this goroutine only. Workers never touch it: they
+// receive jobs and send back what they found, on the worker pool from
+// pkg/pool, so the set needs no mutex.
+func (c *Crawler) Crawl(ctx context.Context, start string) ([]Result, error) {
+	root, err := url.Parse(start)
+	if err != nil || (root.Scheme != "http" && root.Scheme != "https") || root.Host == "" {
+		return nil, fmt.Errorf("start URL must be absolute http(s): %q", start)
+	}
+	if normalized, ok := normalizeURL(root, start); ok {
+		root, _ = url.Parse(normalized)
+	}
+	if c.robots == nil {
+		c.robots = newRobotsCache(c.Client, c.UserAgent)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := pool.New(ctx, func(ctx context.Context, j job) (fetched, error) {
+		return c.fetch(ctx, root.Host, j), nil
+	}, pool.Options{Workers: max(c.Workers, 1)})
+	defer func() {
+		// Let the workers finish, or give up, before returning
+		workers.Close()
+		for range workers.Results() {
+		}
+	}()
+
+	visited := map[string]bool{root.String(): true}
+	queue := []job{{url: root}}
+	inFlight := 0
+	var results []Result
+
+	for len(queue) > 0 || inFlight > 0 {
+		// Hand the pool all the jobs it has room for. TrySubmit never
+		// blocks: with the queue full, this goroutine must go on reading
+		// results, or the workers would wait for it while it waits for them
+		for len(queue) > 0 && workers.TrySubmit(queue[0]) == nil {
+			queue = queue[1:]
+			inFlight++
+		}
+
+		select {
+		case r := <-workers.Results():
+			inFlight--
+			f := r.Out
+			if r.Err != nil {
+				// A panic while fetching: report the URL as broken
+				f = fetched{job: r.In, result: Result{URL: r.In.url.String(), FoundOn: r.In.foundOn, Depth: r.In.depth, Error: r.Err.Error()}}
+			}
+			results = append(results, f.result)
+			for _, link := range f.links {
+				if visited[link] || (c.MaxPages > 0 && len(visited) >= c.MaxPages) {
+					continue
+				}
+				u, err := url.Parse(link)
+				if err != nil {
+					continue
+				}
+				visited[link] = true
+				queue = append(queue, job{url: u, foundOn: f.result.URL, depth: f.job.depth + 1})
+			}
+
+		case <-ctx.Done():
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// fetch checks one URL and, for an HTML page on the start host that is not
+// yet at MaxDepth, returns the links it contains.
+func (c *Crawler) fetch(ctx context.Context, rootHost string, j job) fetched {
+	f := fetched{job: j, result: Result{URL: j.url.String(), FoundOn: j.foundOn, Depth: j.depth}}
+	started := time.Now()
+	defer func() { f.result.Duration = time.Since(started) }()
+
+	if !c.robots.Allowed(ctx, j.url) {
+		f.result.Error = errRobots.Error()
+		return f
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url.String(), nil)
+	if err != nil {
+		f.result.Error = err.Error()
+		return f
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	res, err := c.Client.Do(req)
+	if err != nil {
+		f.result.Error = err.Error()
+		return f
+	}
+	defer res.Body.Close()
+	f.result.Status = res.StatusCode
+
+	follow := j.url.Host == rootHost && j.depth < c.MaxDepth && res.StatusCode == http.StatusOK && isHTML(res)
+	if !follow {
+		io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10)) // lets the connection be reused
+		return f
+	}
+	// Redirects may land on another page; resolve links against where we ended up
+	f.links = extractLinks(res.Request.URL, io.LimitReader(res.Body, 5<<20))
+	f.result.Links = len(f.links)
+	return f
+}
+
+func isHTML(res *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	return err == nil && mediaType == "text/html"
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSite serves a small HTML site. pages maps a path to its body; any other
+// path is a 404.
+func newSite(t *testing.T, robots string, pages map[string]string) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			if robots == "" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, robots)
+			return
+		}
+		hits.Add(1)
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func byURL(results []Result) map[string]Result {
+	m := make(map[string]Result, len(results))
+	for _, r := range results {
+		m[r.URL] = r
+	}
+	return m
+}
+
+func TestCrawlFindsBrokenLinks(t *testing.T) {
+	site, _ := newSite(t, "", map[string]string{
+		"/":      `<a href="/about">About</a> <a href="/missing">Gone</a> <a href="mailto:x@y.z">mail</a>`,
+		"/about": `<a href="/">Home</a> <a href="/about#team">Team</a>`,
+	})
+
+	results, err := NewCrawler(site.Client(), 4, 3, 0).Crawl(context.Background(), site.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := byURL(results)
+	if len(got) != 3 {
+		t.Fatalf("checked %d URLs, want 3 (/, /about, /missing): %+v", len(got), results)
+	}
+	missing := got[site.URL+"/missing"]
+	if !missing.Broken() || missing.Status != http.StatusNotFound || missing.FoundOn != site.URL+"/" {
+		t.Errorf("/missing = %+v, want a 404 found on /", missing)
+	}
+	if about := got[site.URL+"/about"]; about.Broken() || about.Depth != 1 || about.Links != 2 {
+		t.Errorf("/about = %+v", about)
+	}
+}
+
+func TestCrawlVisitsEachURLOnce(t *testing.T) {
+	// Every page links to every other page, plus itself
+	pages := map[string]string{}
+	var links strings.Builder
+	for i := range 10 {
+		fmt.Fprintf(&links, `<a href="/p%d">%d</a>`, i, i)
+	}
+	pages["/"] = links.String()
+	for i := range 10 {
+		pages[fmt.Sprintf("/p%d", i)] = links.String()
+	}
+	site, hits := newSite(t, "", pages)
+
+	results, err := NewCrawler(site.Client(), 8, 5, 0).Crawl(context.Background(), site.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 11 || hits.Load() != 11 {
+		t.Errorf("results = %d, requests = %d; want 11 of each", len(results), hits.Load())
+	}
+}
+
+func TestCrawlRespectsRobots(t *testing.T) {
+	site, _ := newSite(t, "User-agent: *\nDisallow: /private\nAllow: /private/ok\n", map[string]string{
+		"/":               `<a href="/private/secret">x</a> <a href="/private/ok">y</a>`,
+		"/private/secret": `never fetched`,
+		"/private/ok":     `fine`,
+	})
+
+	results, err := NewCrawler(site.Client(), 2, 2, 0).Crawl(context.Background(), site.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := byURL(results)
+	if r := got[site.URL+"/private/secret"]; r.Error != errRobots.Error() || r.Status != 0 {
+		t.Errorf("/private/secret = %+v, want skipped by robots.txt", r)
+	}
+	if r := got[site.URL+"/private/ok"]; r.Status != http.StatusOK {
+		t.Errorf("/private/ok = %+v, want fetched (Allow is more specific)", r)
+	}
+}
+
+func TestCrawlDepthAndExternalLinks(t *testing.T) {
+	external, externalHits := newSite(t, "", map[string]string{
+		"/": `<a href="/deeper">external pages are checked, not followed</a>`,
+	})
+	site, _ := newSite(t, "", map[string]string{
+		"/":    `<a href="/one">1</a> <a href="` + external.URL + `/">ext</a>`,
+		"/one": `<a href="/two">2</a>`,
+		"/two": `<a href="/three">3</a>`,
+	})
+
+	// Depth 0 is the start page; pages at depth 1 are parsed, depth 2 only checked
+	results, err := NewCrawler(http.DefaultClient, 2, 2, 0).Crawl(context.Background(), site.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := byURL(results)
+	if _, ok := got[site.URL+"/two"]; !ok {
+		t.Error("/two is linked from depth 1 and should have been checked")
+	}
+	if _, ok := got[site.URL+"/three"]; ok {
+		t.Error("/three is beyond MaxDepth and should not have been checked")
+	}
+	if externalHits.Load() != 1 {
+		t.Errorf("external site got %d requests, want 1", externalHits.Load())
+	}
+}
+
+func TestCrawlMaxPages(t *testing.T) {
+	var links strings.Builder
+	for i := range 50 {
+		fmt.Fprintf(&links, `<a href="/p%d">%d</a>`, i, i)
+	}
+	site, _ := newSite(t, "", map[string]string{"/": links.String()})
+
+	results, _ := NewCrawler(site.Client(), 4, 1, 10).Crawl(context.Background(), site.URL)
+	if len(results) != 10 {
+		t.Errorf("checked %d URLs, want MaxPages = 10", len(results))
+	}
+}
+
+func TestCrawlCancellation(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			http.NotFound(w, r)
+			return
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<a href="/slow1">1</a><a href="/slow2">2</a>`)
+			return
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	began := time.Now()
+	results, err := NewCrawler(slow.Client(), 2, 1, 0).Crawl(ctx, slow.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(began); elapsed > 2*time.Second {
+		t.Errorf("Crawl took %s to notice cancellation", elapsed)
+	}
+	if len(results) != 1 {
+		t.Errorf("partial results = %+v, want just the start page", results)
+	}
+}
+
+func TestCrawlRetriesPassingFailures(t *testing.T) {
+	var flaky atomic.Int32
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<a href="/flaky">flaky</a>`)
+		case "/flaky":
+			if flaky.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, "ok now")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	results, err := NewCrawler(newClient(0, 2), 2, 1, 0).Crawl(context.Background(), site.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := byURL(results)[site.URL+"/flaky"]; got.Status != http.StatusOK || flaky.Load() != 2 {
+		t.Errorf("/flaky: %+v after %d requests, want 200 after 2", got, flaky.Load())
+	}
+}
+
+func TestCrawlRejectsBadStartURL(t *testing.T) {
+	for _, start := range []string{"", "example.com", "ftp://example.com/", "://bad"} {
+		if _, err := NewCrawler(http.DefaultClient, 1, 1, 0).Crawl(context.Background(), start); err == nil {
+			t.Errorf("Crawl(%q) succeeded, want an error", start)
+		}
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	const robots = `
+# comments are ignored
+User-agent: BadBot
+Disallow: /
+
+User-agent: *
+User-agent: learning-golang-crawler
+Disallow: /admin
+Disallow: /tmp/   # trailing comment
+Allow: /admin/public
+Disallow:
+`
+	tests := []struct {
+		agent string
+		path  string
+		want  bool
+	}{
+		{"learning-golang-crawler/1.0", "/", true},
+		{"learning-golang-crawler/1.0", "/admin", false},
+		{"learning-golang-crawler/1.0", "/admin/users", false},
+		{"learning-golang-crawler/1.0", "/admin/public/index.html", true},
+		{"learning-golang-crawler/1.0", "/tmp/x", false},
+		{"learning-golang-crawler/1.0", "/tmpfile", true},
+		{"SomeOtherBot", "/admin", false}, // falls back to *
+		{"BadBot/2.0", "/anything", false},
+	}
+	for _, tt := range tests {
+		rules := parseRobots(strings.NewReader(robots), tt.agent)
+		if got := rules.Allowed(tt.path); got != tt.want {
+			t.Errorf("%s %s: Allowed = %v, want %v", tt.agent, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExtractLinks(t *testing.T) {
+	base, _ := url.Parse("https://example.com/docs/intro")
+	body := `<html><body>
+		<a href="setup">relative</a>
+		<a href="/faq#q1">fragment is dropped</a>
+		<a href="HTTPS://Example.COM">host is lowercased</a>
+		<a href="#top">same page</a>
+		<a href="javascript:void(0)">js</a>
+		<a>no href</a>
+		<img src="/logo.png">
+	</body></html>`
+
+	got := extractLinks(base, strings.NewReader(body))
+	want := []string{"https://example.com/docs/setup", "https://example.com/faq", "https://example.com/"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("extractLinks =\n  %v\nwant\n  %v", got, want)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	results := []Result{
+		{URL: "https://a.test/", Status: 200, Links: 2},
+		{URL: "https://a.test/gone", FoundOn: "https://a.test/", Depth: 1, Status: 404},
+	}
+	sortResults(results)
+	if results[0].Status != 404 {
+		t.Errorf("broken links should sort first, got %+v", results[0])
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, "csv", results); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 3 || rows[1][0] != "https://a.test/gone" || rows[1][3] != "404" {
+		t.Errorf("csv rows = %v, %v", rows, err)
+	}
+
+	buf.Reset()
+	if err := WriteReport(&buf, "json", results); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Result
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 2 {
+		t.Errorf("json = %s, %v", buf.String(), err)
+	}
+
+	if err := WriteReport(&buf, "xml", results); err == nil {
+		t.Error("unknown format should be an error")
+	}
+}
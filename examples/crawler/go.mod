@@ -0,0 +1,5 @@
+module github.com/owolabijunior12/learning-golang/examples/crawler
+
+go 1.25.1
+
+require golang.org/x/net v0.47.0
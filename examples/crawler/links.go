@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// extractLinks returns the absolute http(s) URLs of every <a href> in an
+// HTML document, resolved against base. Duplicates are kept; the crawler's
+// visited set deals with them.
+func extractLinks(base *url.URL, body io.Reader) []string {
+	var links []string
+	tokens := html.NewTokenizer(body)
+	for {
+		switch tokens.Next() {
+		case html.ErrorToken:
+			return links // io.EOF or malformed HTML: keep what we found
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokens.TagName()
+			if string(name) == "base" && hasAttr {
+				// <base href> changes how relative links resolve
+				if href, ok := attr(tokens, "href"); ok {
+					if u, err := base.Parse(href); err == nil {
+						base = u
+					}
+				}
+				continue
+			}
+			if string(name) != "a" || !hasAttr {
+				continue
+			}
+			if href, ok := attr(tokens, "href"); ok {
+				if link, ok := normalizeURL(base, href); ok {
+					links = append(links, link)
+				}
+			}
+		}
+	}
+}
+
+func attr(tokens *html.Tokenizer, key string) (string, bool) {
+	for {
+		k, v, more := tokens.TagAttr()
+		if string(k) == key {
+			return string(v), true
+		}
+		if !more {
+			return "", false
+		}
+	}
+}
+
+// normalizeURL resolves href against base and strips the fragment, so
+// /page and /page#section count as the same page.
+func normalizeURL(base *url.URL, href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return "", false
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false // mailto:, javascript:, tel:, ...
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Host = strings.ToLower(u.Host)
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String(), true
+}
@@ -0,0 +1,106 @@
+// Command crawler checks every link reachable from a start URL and reports
+// the broken ones.
+//
+// It is course 4 applied: a bounded worker pool, a coordinator goroutine that
+// owns the visited set, and context cancellation on Ctrl+C or -timeout. Its
+// HTTP client, from pkg/httpclient, retries failures that may pass and keeps
+// to a rate limit per host:
+//
+//	go run ./examples/crawler -depth 2 https://go.dev/
+//	go run ./examples/crawler -format json -o report.json https://go.dev/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/pkg/httpclient"
+)
+
+func main() {
+	workers := flag.Int("workers", 8, "concurrent requests")
+	depth := flag.Int("depth", 2, "how many links deep to follow on the start host")
+	maxPages := flag.Int("max", 500, "maximum number of URLs to check (0 = no limit)")
+	timeout := flag.Duration("timeout", 2*time.Minute, "give up after this long and report what was found")
+	format := flag.String("format", "csv", "report format: csv or json")
+	output := flag.String("o", "", "write the report to a file instead of stdout")
+	rate := flag.Float64("rate", 5, "requests per second to each host (0 = no limit)")
+	retries := flag.Int("retries", 2, "times to retry a request that failed with a network error, 429 or 5xx")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: crawler [flags] <start-url>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger := log.New(os.Stderr, "[crawler] ", log.LstdFlags)
+	crawler := NewCrawler(newClient(*rate, *retries), *workers, *depth, *maxPages)
+	broken, err := run(crawler, flag.Arg(0), *timeout, *format, *output, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if broken > 0 {
+		os.Exit(1) // lets CI fail the build on broken links
+	}
+}
+
+// newClient is the crawler's HTTP client: a request, retries included, may
+// take 10 seconds, and each host gets at most rate requests a second.
+func newClient(rate float64, retries int) *http.Client {
+	return httpclient.New(httpclient.Options{
+		Timeout:   10 * time.Second,
+		Retry:     httpclient.Retry{Attempts: retries + 1, Base: 250 * time.Millisecond, Max: 2 * time.Second},
+		PerSecond: rate,
+		Burst:     max(int(rate), 1),
+	})
+}
+
+func run(crawler *Crawler, start string, timeout time.Duration, format, output string, logger *log.Logger) (broken int, err error) {
+	if format != "csv" && format != "json" {
+		return 0, fmt.Errorf("unknown report format %q (want csv or json)", format)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	began := time.Now()
+	results, err := crawler.Crawl(ctx, start)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		logger.Printf("stopped early (%v); reporting partial results", err)
+	case err != nil:
+		return 0, err
+	}
+
+	sortResults(results)
+	for _, r := range results {
+		if r.Broken() {
+			broken++
+		}
+	}
+	logger.Printf("checked %d URLs in %s, %d broken", len(results), time.Since(began).Round(time.Millisecond), broken)
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return broken, err
+		}
+		defer f.Close()
+		w = f
+	}
+	return broken, WriteReport(w, format, results)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// sortResults orders broken links first, then by URL, so reports are stable
+// no matter which worker finished first.
+func sortResults(results []Result) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Broken() != results[j].Broken() {
+			return results[i].Broken()
+		}
+		return results[i].URL < results[j].URL
+	})
+}
+
+// WriteReport writes results as "csv" or "json".
+func WriteReport(w io.Writer, format string, results []Result) error {
+	switch format {
+	case "csv":
+		return writeCSV(w, results)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	default:
+		return fmt.Errorf("unknown report format %q (want csv or json)", format)
+	}
+}
+
+func writeCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"url", "found_on", "depth", "status", "error", "duration_ms", "links"})
+	for _, r := range results {
+		cw.Write([]string{
+			r.URL,
+			r.FoundOn,
+			strconv.Itoa(r.Depth),
+			strconv.Itoa(r.Status),
+			r.Error,
+			strconv.FormatInt(r.Duration.Milliseconds(), 10),
+			strconv.Itoa(r.Links),
+		})
+	}
+	cw.Flush() // csv.Writer buffers; errors surface here
+	return cw.Error()
+}
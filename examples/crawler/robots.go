@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// robotsRules holds the Allow/Disallow lines that apply to our user agent.
+// Only path prefixes are supported (no * or $ wildcards), which covers most
+// real robots.txt files.
+type robotsRules struct {
+	allow    []string
+	disallow []string
+}
+
+// Allowed applies the longest matching rule; Allow wins a tie.
+func (r *robotsRules) Allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	best, allowed := -1, true
+	for _, prefix := range r.disallow {
+		if strings.HasPrefix(path, prefix) && len(prefix) > best {
+			best, allowed = len(prefix), false
+		}
+	}
+	for _, prefix := range r.allow {
+		if strings.HasPrefix(path, prefix) && len(prefix) >= best {
+			best, allowed = len(prefix), true
+		}
+	}
+	return allowed
+}
+
+// parseRobots reads the group for userAgent, falling back to the "*" group.
+func parseRobots(r io.Reader, userAgent string) *robotsRules {
+	groups := map[string]*robotsRules{}
+	var current []string // agents named by the group being read
+	inRules := false     // a rule line ends the run of User-agent lines
+	userAgent = strings.ToLower(userAgent)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				current, inRules = nil, false
+			}
+			agent := strings.ToLower(value)
+			current = append(current, agent)
+			if groups[agent] == nil {
+				groups[agent] = &robotsRules{}
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // "Disallow:" with no path allows everything
+			}
+			for _, agent := range current {
+				if key == "allow" {
+					groups[agent].allow = append(groups[agent].allow, value)
+				} else {
+					groups[agent].disallow = append(groups[agent].disallow, value)
+				}
+			}
+		}
+	}
+
+	for agent, rules := range groups {
+		if agent != "*" && strings.Contains(userAgent, agent) {
+			return rules
+		}
+	}
+	if rules := groups["*"]; rules != nil {
+		return rules
+	}
+	return &robotsRules{}
+}
+
+// robotsCache fetches each host's robots.txt once. Workers share it, so it
+// is guarded by a mutex; a per-host sync.Once-style entry keeps two workers
+// from fetching the same file at the same time.
+type robotsCache struct {
+	client    *http.Client
+	userAgent string
+
+	mu    sync.Mutex
+	hosts map[string]*robotsEntry
+}
+
+type robotsEntry struct {
+	ready chan struct{} // closed once rules is set
+	rules *robotsRules
+}
+
+func newRobotsCache(client *http.Client, userAgent string) *robotsCache {
+	return &robotsCache{client: client, userAgent: userAgent, hosts: make(map[string]*robotsEntry)}
+}
+
+// Allowed reports whether u may be fetched. A missing or unreadable
+// robots.txt allows everything.
+func (c *robotsCache) Allowed(ctx context.Context, u *url.URL) bool {
+	host := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	entry, ok := c.hosts[host]
+	if !ok {
+		entry = &robotsEntry{ready: make(chan struct{})}
+		c.hosts[host] = entry
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		entry.rules = c.fetch(ctx, host)
+		close(entry.ready)
+	}
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return false
+	}
+	return entry.rules.Allowed(u.EscapedPath())
+}
+
+func (c *robotsCache) fetch(ctx context.Context, host string) *robotsRules {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	res, err := c.client.Do(req)
+	if err != nil {
+		return &robotsRules{}
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return &robotsRules{}
+	}
+	return parseRobots(io.LimitReader(res.Body, 512<<10), c.userAgent)
+}
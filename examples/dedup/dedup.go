@@ -0,0 +1,171 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/owolabijunior12/learning-golang/pkg/pool"
+)
+
+// Options configure Find. The zero value hashes on GOMAXPROCS workers and
+// counts empty files, which are all copies of each other.
+type Options struct {
+	Workers int   // files hashed at once; 0 means runtime.GOMAXPROCS(0)
+	MinSize int64 // smaller files are left out
+}
+
+// Group is a set of files with the same contents.
+type Group struct {
+	Hash  string   // hex SHA-256 of the contents
+	Size  int64    // of each file
+	Paths []string // sorted; Link keeps the first and links the others to it
+}
+
+// Wasted is the space the copies take beyond the first.
+func (g Group) Wasted() int64 { return g.Size * int64(len(g.Paths)-1) }
+
+// Stats count what Find looked at.
+type Stats struct {
+	Files  int   // regular files of at least MinSize
+	Bytes  int64 // their total size
+	Hashed int   // files that shared their size with another, so had to be read
+	Linked int   // files that were already hard links to another one found
+	Errors []error
+}
+
+// file is a regular file found by the walk.
+type file struct {
+	path string
+	info fs.FileInfo // for the size, and os.SameFile
+}
+
+// Find walks roots and returns the groups of files with the same contents,
+// the biggest waste first.
+//
+// Reading every file would be slow, and most files can't have a copy: only
+// files whose size matches another's are hashed, on a pool of workers from
+// pkg/pool, since hashing is reading and a disk serves several reads at
+// once. Files Find can't read are listed in Stats.Errors and left out.
+// Symbolic links are not followed, and hard links to one file count once.
+func Find(ctx context.Context, roots []string, opts Options) ([]Group, Stats, error) {
+	var stats Stats
+	bySize := make(map[int64][]file)
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				if path == root {
+					return err
+				}
+				stats.Errors = append(stats.Errors, err)
+				return nil // for a directory, skips what couldn't be read
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				stats.Errors = append(stats.Errors, err)
+				return nil
+			}
+			if info.Size() < opts.MinSize {
+				return nil
+			}
+			// A hard link to a file already found is the same file under
+			// another name: there is nothing to save by linking it again.
+			same := bySize[info.Size()]
+			if slices.ContainsFunc(same, func(f file) bool { return os.SameFile(f.info, info) }) {
+				stats.Linked++
+				return nil
+			}
+			bySize[info.Size()] = append(same, file{path, info})
+			stats.Files++
+			stats.Bytes += info.Size()
+			return nil
+		})
+		if err != nil {
+			return nil, stats, err
+		}
+	}
+
+	var candidates []file
+	for _, files := range bySize {
+		if len(files) > 1 {
+			candidates = append(candidates, files...)
+		}
+	}
+	stats.Hashed = len(candidates)
+
+	byHash := make(map[string]*Group)
+	for _, r := range pool.Map(ctx, candidates, hashFile, pool.Options{Workers: opts.Workers}) {
+		if r.Err != nil {
+			if ctx.Err() == nil {
+				stats.Errors = append(stats.Errors, r.Err)
+			}
+			continue
+		}
+		g := byHash[r.Out]
+		if g == nil {
+			g = &Group{Hash: r.Out, Size: r.In.info.Size()}
+			byHash[r.Out] = g
+		}
+		g.Paths = append(g.Paths, r.In.path)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, stats, err
+	}
+
+	var groups []Group
+	for _, g := range byHash {
+		if len(g.Paths) > 1 {
+			slices.Sort(g.Paths)
+			groups = append(groups, *g)
+		}
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		return cmp.Or(cmp.Compare(b.Wasted(), a.Wasted()), cmp.Compare(a.Paths[0], b.Paths[0]))
+	})
+	return groups, stats, nil
+}
+
+// hashFile is the pool's job: the SHA-256 of the file's contents.
+func hashFile(ctx context.Context, f file) (string, error) {
+	return hashPath(ctx, f.path)
+}
+
+func hashPath(ctx context.Context, path string) (string, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fh.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, ctxReader{ctx, fh}); err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ctxReader stops a read once ctx is done, so Ctrl+C doesn't wait for a
+// worker to finish hashing a file of several GB.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
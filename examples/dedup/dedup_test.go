@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testTree writes files, a map of slash-separated paths to contents, under
+// a temporary directory and returns it.
+func testTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// rels returns g's paths relative to root, with slashes.
+func rels(t *testing.T, root string, g Group) []string {
+	t.Helper()
+	var out []string
+	for _, p := range g.Paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, filepath.ToSlash(rel))
+	}
+	return out
+}
+
+func TestFind(t *testing.T) {
+	root := testTree(t, map[string]string{
+		"a/photo.jpg":       "0123456789",
+		"b/photo (1).jpg":   "0123456789",
+		"b/c/photo.jpg":     "0123456789",
+		"a/other.jpg":       "9876543210", // same size, other contents
+		"notes.txt":         "hello",
+		"b/notes.txt":       "hello",
+		"unique.txt":        "nothing else is this long",
+		"empty1":            "",
+		"empty2":            "",
+		"a/too-small-1.txt": "x",
+		"a/too-small-2.txt": "x",
+	})
+	// A hard link is the same file: not a copy, and nothing to save
+	if err := os.Link(filepath.Join(root, "unique.txt"), filepath.Join(root, "unique-link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("notes.txt", filepath.Join(root, "notes-link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, stats, err := Find(context.Background(), []string{root}, Options{Workers: 3, MinSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"a/photo.jpg", "b/c/photo.jpg", "b/photo (1).jpg"},
+		{"b/notes.txt", "notes.txt"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d: %+v", len(groups), len(want), groups)
+	}
+	for i, g := range groups {
+		if got := rels(t, root, g); !slices.Equal(got, want[i]) {
+			t.Errorf("group %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if g := groups[0]; g.Size != 10 || g.Wasted() != 20 || len(g.Hash) != 64 {
+		t.Errorf("first group = %+v, wasted %d", g, g.Wasted())
+	}
+	if want := (Stats{Files: 7, Bytes: 75, Hashed: 6, Linked: 1}); stats.Files != want.Files || stats.Bytes != want.Bytes ||
+		stats.Hashed != want.Hashed || stats.Linked != want.Linked || len(stats.Errors) != 0 {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestFindErrors(t *testing.T) {
+	if _, _, err := Find(context.Background(), []string{filepath.Join(t.TempDir(), "missing")}, Options{}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing root: err = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	root := testTree(t, map[string]string{"a": "same", "b": "same"})
+	if _, _, err := Find(ctx, []string{root}, Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: err = %v", err)
+	}
+}
+
+func TestLink(t *testing.T) {
+	root := testTree(t, map[string]string{"a": "same data", "b": "same data", "c/d": "same data"})
+	groups, _, err := Find(context.Background(), []string{root}, Options{})
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("Find = %+v, %v", groups, err)
+	}
+
+	freed, err := Link(context.Background(), groups[0])
+	if err != nil || freed != 18 {
+		t.Fatalf("Link = %d, %v; want 18 freed", freed, err)
+	}
+	keep, _ := os.Stat(filepath.Join(root, "a"))
+	for _, name := range []string{"b", "c/d"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		info, err := os.Stat(path)
+		if err != nil || !os.SameFile(keep, info) {
+			t.Errorf("%s isn't a link to a: %v", name, err)
+		}
+		if data, _ := os.ReadFile(path); string(data) != "same data" {
+			t.Errorf("%s = %q after linking", name, data)
+		}
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(root, ".*.dedup-*")); len(leftovers) > 0 {
+		t.Errorf("temporary links left behind: %v", leftovers)
+	}
+
+	// Linked files count once the next time
+	groups, stats, err := Find(context.Background(), []string{root}, Options{})
+	if err != nil || len(groups) != 0 || stats.Linked != 2 {
+		t.Errorf("after linking: groups %+v, stats %+v, %v", groups, stats, err)
+	}
+}
+
+func TestLinkChanged(t *testing.T) {
+	root := testTree(t, map[string]string{"a": "same data", "b": "same data", "c": "same data"})
+	groups, _, err := Find(context.Background(), []string{root}, Options{})
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("Find = %+v, %v", groups, err)
+	}
+	// b is edited between the scan and the link
+	if err := os.WriteFile(filepath.Join(root, "b"), []byte("new  data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	freed, err := Link(context.Background(), groups[0])
+	if !errors.Is(err, errChanged) || freed != 9 {
+		t.Errorf("Link = %d, %v; want 9 freed and %v", freed, err, errChanged)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "b")); string(data) != "new  data" {
+		t.Errorf("the edited file was replaced: %q", data)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	groups := []Group{{Hash: strings.Repeat("ab", 32), Size: 3 << 20, Paths: []string{"a.iso", "b.iso"}}}
+	stats := Stats{Files: 10, Bytes: 10 << 20, Hashed: 2, Linked: 1}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, groups, stats, -1); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"2 copies of 3.0 MiB, 3.0 MiB wasted  sha256:abababababab\n  a.iso\n  b.iso\n",
+		"Scanned 10 files (10.0 MiB), hashed 2, skipped 1 already linked\n1 groups of duplicates, 3.0 MiB wasted\n",
+		"Run again with -link",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	WriteReport(&buf, groups, stats, 3<<20)
+	if !strings.Contains(buf.String(), "Linked the copies: 3.0 MiB freed") {
+		t.Errorf("report after -link:\n%s", buf.String())
+	}
+}
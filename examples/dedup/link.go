@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+)
+
+// errChanged is returned for a file written to since Find hashed it.
+var errChanged = errors.New("changed since it was hashed")
+
+// Link replaces every copy in g with a hard link to g.Paths[0], so the
+// contents are stored once, and returns the space that frees. It goes on
+// past a copy it can't replace and returns all the errors together.
+//
+// Hard links share everything but the name: after Link, the copies have
+// the first file's permissions, owner and modification time, and writing
+// to one changes them all. They also have to be on one file system;
+// os.Link fails across two.
+func Link(ctx context.Context, g Group) (freed int64, err error) {
+	keep := g.Paths[0]
+	var errs []error
+	for _, path := range g.Paths[1:] {
+		if err := ctx.Err(); err != nil {
+			return freed, errors.Join(append(errs, err)...)
+		}
+		if err := replaceWithLink(ctx, keep, path, g.Hash); err != nil {
+			errs = append(errs, fmt.Errorf("linking %s: %w", path, err))
+			continue
+		}
+		freed += g.Size
+	}
+	return freed, errors.Join(errs...)
+}
+
+// replaceWithLink makes path a hard link to keep. The link is made under a
+// temporary name in path's directory and renamed over path, as course 28
+// saves a file: rename replaces path in one step, so a crash leaves the
+// copy or the link, never neither.
+func replaceWithLink(ctx context.Context, keep, path, hash string) error {
+	// The files may have changed since Find read them: link nothing that
+	// no longer holds what was hashed
+	for _, p := range []string{keep, path} {
+		got, err := hashPath(ctx, p)
+		if err != nil {
+			return err
+		}
+		if got != hash {
+			return fmt.Errorf("%s %w", p, errChanged)
+		}
+	}
+
+	tmp := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.dedup-%d", filepath.Base(path), rand.Uint32()))
+	if err := os.Link(keep, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
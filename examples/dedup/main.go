@@ -0,0 +1,80 @@
+// Command dedup finds files with the same contents under one or more
+// directories and, with -link, replaces the copies with hard links to one
+// of them.
+//
+// It is courses 4, 5 and 28 applied: a directory walk, SHA-256 hashes
+// computed on a worker pool from pkg/pool, and each copy swapped for its
+// link with a rename, so a crash never loses a file:
+//
+//	go run ./examples/dedup ~/Pictures
+//	go run ./examples/dedup -min-size 1048576 -link ~/Downloads ~/Documents
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+)
+
+func main() {
+	workers := flag.Int("workers", runtime.NumCPU(), "files hashed at once")
+	minSize := flag.Int64("min-size", 1, "ignore files smaller than this many bytes")
+	link := flag.Bool("link", false, "replace each copy with a hard link to the first file of its group")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: dedup [flags] <dir>...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger := log.New(os.Stderr, "[dedup] ", log.LstdFlags)
+	failed, err := run(flag.Args(), Options{Workers: *workers, MinSize: *minSize}, *link, logger)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// run finds the duplicates, links them if asked, and prints the report.
+// Files that can't be read or linked are logged, the run goes on without
+// them, and failed reports that there were some.
+func run(roots []string, opts Options, link bool, logger *log.Logger) (failed bool, err error) {
+	// Ctrl+C stops the walk or the hashing; nothing is linked after it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	began := time.Now()
+	groups, stats, err := Find(ctx, roots, opts)
+	if err != nil {
+		return false, err
+	}
+	for _, err := range stats.Errors {
+		logger.Print(err)
+	}
+	failed = len(stats.Errors) > 0
+	logger.Printf("hashed %d of %d files in %s", stats.Hashed, stats.Files, time.Since(began).Round(time.Millisecond))
+
+	freed := int64(-1)
+	if link {
+		freed = 0
+		for _, g := range groups {
+			n, err := Link(ctx, g)
+			freed += n
+			if err != nil {
+				logger.Print(err)
+				failed = true
+			}
+		}
+	}
+	return failed, WriteReport(os.Stdout, groups, stats, freed)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteReport lists the groups, then a summary line. freed is what -link
+// freed, or -1 if it didn't run.
+func WriteReport(out io.Writer, groups []Group, stats Stats, freed int64) error {
+	w := bufio.NewWriter(out)
+	var wasted int64
+	for _, g := range groups {
+		wasted += g.Wasted()
+		fmt.Fprintf(w, "%d copies of %s, %s wasted  sha256:%s\n", len(g.Paths), humanBytes(g.Size), humanBytes(g.Wasted()), g.Hash[:12])
+		for _, p := range g.Paths {
+			fmt.Fprintf(w, "  %s\n", p)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "Scanned %d files (%s), hashed %d", stats.Files, humanBytes(stats.Bytes), stats.Hashed)
+	if stats.Linked > 0 {
+		fmt.Fprintf(w, ", skipped %d already linked", stats.Linked)
+	}
+	fmt.Fprintf(w, "\n%d groups of duplicates, %s wasted\n", len(groups), humanBytes(wasted))
+	switch {
+	case freed >= 0:
+		fmt.Fprintf(w, "Linked the copies: %s freed\n", humanBytes(freed))
+	case len(groups) > 0:
+		fmt.Fprintln(w, "Run again with -link to replace the copies with hard links")
+	}
+	return w.Flush()
+}
+
+// humanBytes formats n with a binary unit: 1536 -> "1.5 KiB".
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `usage: expenses [-file PATH] <command> [flags]
+
+Commands:
+  add     record an expense       add -amount 12.50 -category food [-note lunch] [-date 2026-10-01]
+  list    show expenses           list [-month 2026-10 | -from DATE -to DATE] [-category food]
+  report  totals per group        report [-by category|month] [same filters as list]
+
+-file ending in .db or .sqlite uses SQLite; anything else is a JSON file.
+Run "expenses <command> -h" for a command's flags.
+`
+
+// errUsage means the arguments were wrong; main exits with status 2.
+var errUsage = errors.New("usage error")
+
+// App holds what the commands share. now is a field so tests can pin "today".
+type App struct {
+	store  Store
+	out    io.Writer
+	errOut io.Writer // flag errors and help
+	now    func() time.Time
+}
+
+// run parses the global flags, opens the store and dispatches to a command.
+func run(args []string, stdout, stderr io.Writer, now func() time.Time) error {
+	global := flag.NewFlagSet("expenses", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() { fmt.Fprint(stderr, usage) }
+	file := global.String("file", "expenses.json", "where expenses are stored")
+	if err := global.Parse(args); err != nil {
+		return errUsage
+	}
+	if global.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return errUsage
+	}
+
+	commands := map[string]func(*App, []string) error{
+		"add":    (*App).add,
+		"list":   (*App).list,
+		"report": (*App).report,
+	}
+	name, rest := global.Arg(0), global.Args()[1:]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", name, usage)
+		return errUsage
+	}
+
+	store, err := OpenStore(*file)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	app := &App{store: store, out: stdout, errOut: stderr, now: now}
+	return cmd(app, rest)
+}
+
+// newFlagSet returns a FlagSet that reports errors instead of exiting, so
+// run stays testable.
+func newFlagSet(name string, out io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("expenses "+name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	return fs
+}
+
+func (a *App) add(args []string) error {
+	fs := newFlagSet("add", a.errOut)
+	amount := fs.String("amount", "", "amount, e.g. 12.50 (required)")
+	category := fs.String("category", "", "category, e.g. food (required)")
+	note := fs.String("note", "", "optional description")
+	date := fs.String("date", "", "YYYY-MM-DD (default today)")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *amount == "" || *category == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	cents, err := parseAmount(*amount)
+	if err != nil {
+		return err
+	}
+	day := a.today()
+	if *date != "" {
+		if day, err = time.Parse(dateLayout, *date); err != nil {
+			return fmt.Errorf("invalid date %q: use YYYY-MM-DD", *date)
+		}
+	}
+
+	e, err := a.store.Add(Expense{
+		Date:     day,
+		Cents:    cents,
+		Category: strings.ToLower(strings.TrimSpace(*category)),
+		Note:     strings.TrimSpace(*note),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "added #%d: %s %s on %s\n", e.ID, formatCents(e.Cents), e.Category, e.Date.Format(dateLayout))
+	return nil
+}
+
+// filterFlags registers the date and category flags shared by list and
+// report; call the returned function after fs.Parse.
+func filterFlags(fs *flag.FlagSet) func() (Filter, error) {
+	from := fs.String("from", "", "first date to include, YYYY-MM-DD")
+	to := fs.String("to", "", "last date to include, YYYY-MM-DD")
+	month := fs.String("month", "", "a whole month, YYYY-MM (overrides -from/-to)")
+	category := fs.String("category", "", "only this category")
+
+	return func() (Filter, error) {
+		f := Filter{Category: *category}
+		var err error
+		if *month != "" {
+			f.From, f.To, err = parseMonth(*month)
+			return f, err
+		}
+		if *from != "" {
+			if f.From, err = time.Parse(dateLayout, *from); err != nil {
+				return f, fmt.Errorf("invalid -from %q: use YYYY-MM-DD", *from)
+			}
+		}
+		if *to != "" {
+			if f.To, err = time.Parse(dateLayout, *to); err != nil {
+				return f, fmt.Errorf("invalid -to %q: use YYYY-MM-DD", *to)
+			}
+		}
+		if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+			return f, errors.New("-to is before -from")
+		}
+		return f, nil
+	}
+}
+
+func (a *App) list(args []string) error {
+	fs := newFlagSet("list", a.errOut)
+	filter := filterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	f, err := filter()
+	if err != nil {
+		return err
+	}
+
+	expenses, err := a.store.List(f)
+	if err != nil {
+		return err
+	}
+	if len(expenses) == 0 {
+		fmt.Fprintln(a.out, "no expenses found")
+		return nil
+	}
+
+	// tabwriter pads each tab-separated column to line up. Amounts are
+	// right-aligned by hand (%10s) so the decimal points line up too.
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID\tDATE\tCATEGORY\t%10s\tNOTE\n", "AMOUNT")
+	var total int64
+	for _, e := range expenses {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%10s\t%s\n", e.ID, e.Date.Format(dateLayout), e.Category, formatCents(e.Cents), e.Note)
+		total += e.Cents
+	}
+	fmt.Fprintf(tw, "\t\tTOTAL\t%10s\t\n", formatCents(total))
+	return tw.Flush()
+}
+
+func (a *App) report(args []string) error {
+	fs := newFlagSet("report", a.errOut)
+	filter := filterFlags(fs)
+	by := fs.String("by", "category", "group by category or month")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	f, err := filter()
+	if err != nil {
+		return err
+	}
+
+	var key func(Expense) string
+	switch *by {
+	case "category":
+		key = func(e Expense) string { return e.Category }
+	case "month":
+		key = func(e Expense) string { return e.Date.Format("2006-01") }
+	default:
+		return fmt.Errorf("-by must be category or month, not %q", *by)
+	}
+
+	expenses, err := a.store.List(f)
+	if err != nil {
+		return err
+	}
+	if len(expenses) == 0 {
+		fmt.Fprintln(a.out, "no expenses found")
+		return nil
+	}
+
+	totals := map[string]int64{}
+	counts := map[string]int{}
+	var grand int64
+	for _, e := range expenses {
+		totals[key(e)] += e.Cents
+		counts[key(e)]++
+		grand += e.Cents
+	}
+
+	groups := make([]string, 0, len(totals))
+	for g := range totals {
+		groups = append(groups, g)
+	}
+	if *by == "month" {
+		sort.Strings(groups) // chronological
+	} else {
+		sort.Slice(groups, func(i, j int) bool { // biggest spend first
+			if totals[groups[i]] != totals[groups[j]] {
+				return totals[groups[i]] > totals[groups[j]]
+			}
+			return groups[i] < groups[j]
+		})
+	}
+
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\t%5s\t%10s\t%6s\n", strings.ToUpper(*by), "COUNT", "TOTAL", "SHARE")
+	for _, g := range groups {
+		share := float64(totals[g]) * 100 / float64(grand) // display only, so float is fine
+		fmt.Fprintf(tw, "%s\t%5d\t%10s\t%5.1f%%\n", g, counts[g], formatCents(totals[g]), share)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%5d\t%10s\t\n", len(expenses), formatCents(grand))
+	return tw.Flush()
+}
+
+// today is the current date at midnight UTC, to match dates parsed from flags.
+func (a *App) today() time.Time {
+	y, m, d := a.now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expense is one recorded purchase.
+//
+// Amounts are stored as whole cents in an int64, never as float64:
+// 0.1 + 0.2 != 0.3 in floating point, and money must add up exactly.
+type Expense struct {
+	ID       int64     `json:"id"`
+	Date     time.Time `json:"date"`
+	Cents    int64     `json:"cents"`
+	Category string    `json:"category"`
+	Note     string    `json:"note,omitempty"`
+}
+
+const dateLayout = "2006-01-02"
+
+// parseAmount turns "12", "12.5" or "12.50" into cents.
+func parseAmount(s string) (int64, error) {
+	whole, frac, hasFrac := strings.Cut(strings.TrimSpace(s), ".")
+	if !isDigits(whole) || (hasFrac && (!isDigits(frac) || len(frac) > 2)) {
+		return 0, fmt.Errorf("invalid amount %q: use a number like 12 or 12.50", s)
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("amount %q is too large", s)
+	}
+	var cents int64
+	if hasFrac {
+		if len(frac) == 1 {
+			frac += "0" // "12.5" means 12.50
+		}
+		cents, _ = strconv.ParseInt(frac, 10, 64) // two digits, cannot fail
+	}
+	total := units*100 + cents
+	if total == 0 {
+		return 0, errors.New("amount must be greater than zero")
+	}
+	return total, nil
+}
+
+// isDigits rejects signs, spaces and exponents that strconv would accept.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatCents renders 123456 as "1234.56".
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Filter selects expenses; zero fields match everything. From and To are
+// inclusive dates.
+type Filter struct {
+	From     time.Time
+	To       time.Time
+	Category string
+}
+
+func (f Filter) Match(e Expense) bool {
+	if !f.From.IsZero() && e.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && e.Date.After(f.To) {
+		return false
+	}
+	return f.Category == "" || strings.EqualFold(e.Category, f.Category)
+}
+
+// parseMonth turns "2026-10" into the first and last day of that month.
+func parseMonth(s string) (from, to time.Time, err error) {
+	from, err = time.Parse("2006-01", s)
+	if err != nil {
+		return from, to, fmt.Errorf("invalid month %q: use YYYY-MM", s)
+	}
+	return from, from.AddDate(0, 1, -1), nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"12", 1200, false},
+		{"12.5", 1250, false},
+		{"12.50", 1250, false},
+		{"0.07", 7, false},
+		{" 3.99 ", 399, false},
+		{"0", 0, true},
+		{"1.234", 0, true},
+		{"12.", 0, true},
+		{".5", 0, true},
+		{"-5", 0, true},
+		{"1.-5", 0, true},
+		{"1.+5", 0, true},
+		{"+12", 0, true},
+		{"99999999999999999999", 0, true},
+		{"abc", 0, true},
+		{"1e3", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseAmount(%q) = %d, %v; want %d, error=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFormatCents(t *testing.T) {
+	for cents, want := range map[int64]string{0: "0.00", 7: "0.07", 1250: "12.50", 123456: "1234.56", -250: "-2.50"} {
+		if got := formatCents(cents); got != want {
+			t.Errorf("formatCents(%d) = %q, want %q", cents, got, want)
+		}
+	}
+}
+
+// cli runs the command against one storage file with "today" pinned.
+type cli struct {
+	t    *testing.T
+	file string
+}
+
+func (c cli) run(args ...string) (stdout string, err error) {
+	c.t.Helper()
+	var out, errOut bytes.Buffer
+	today := func() time.Time { return time.Date(2026, 10, 15, 18, 30, 0, 0, time.UTC) }
+	err = run(append([]string{"-file", c.file}, args...), &out, &errOut, today)
+	return out.String(), err
+}
+
+func (c cli) mustRun(args ...string) string {
+	c.t.Helper()
+	out, err := c.run(args...)
+	if err != nil {
+		c.t.Fatalf("expenses %s: %v", strings.Join(args, " "), err)
+	}
+	return out
+}
+
+// Both backends must behave identically, so every scenario runs against each
+func forEachStore(t *testing.T, test func(t *testing.T, c cli)) {
+	for _, name := range []string{"expenses.json", "expenses.db"} {
+		t.Run(filepath.Ext(name), func(t *testing.T) {
+			test(t, cli{t: t, file: filepath.Join(t.TempDir(), name)})
+		})
+	}
+}
+
+func seed(c cli) {
+	c.mustRun("add", "-amount", "12.50", "-category", "Food", "-note", "lunch", "-date", "2026-10-01")
+	c.mustRun("add", "-amount", "1200", "-category", "rent", "-date", "2026-10-01")
+	c.mustRun("add", "-amount", "3.99", "-category", "food", "-date", "2026-09-15")
+	c.mustRun("add", "-amount", "40", "-category", "transport") // today
+}
+
+func TestAddAndList(t *testing.T) {
+	forEachStore(t, func(t *testing.T, c cli) {
+		if out := c.mustRun("list"); out != "no expenses found\n" {
+			t.Errorf("empty list = %q", out)
+		}
+		if out := c.mustRun("add", "-amount", "12.50", "-category", " Food ", "-note", "lunch", "-date", "2026-10-01"); out != "added #1: 12.50 food on 2026-10-01\n" {
+			t.Errorf("add = %q", out)
+		}
+		c.mustRun("add", "-amount", "1200", "-category", "rent", "-date", "2026-10-01")
+		c.mustRun("add", "-amount", "3.99", "-category", "food", "-date", "2026-09-15")
+		c.mustRun("add", "-amount", "40", "-category", "transport")
+
+		want := strings.Join([]string{
+			"ID  DATE        CATEGORY       AMOUNT  NOTE",
+			"3   2026-09-15  food             3.99  ",
+			"1   2026-10-01  food            12.50  lunch",
+			"2   2026-10-01  rent          1200.00  ",
+			"4   2026-10-15  transport       40.00  ",
+			"                TOTAL         1256.49  ",
+			"",
+		}, "\n")
+		if out := c.mustRun("list"); out != want {
+			t.Errorf("list =\n%s\nwant\n%s", out, want)
+		}
+	})
+}
+
+func TestListFilters(t *testing.T) {
+	forEachStore(t, func(t *testing.T, c cli) {
+		seed(c)
+		tests := []struct {
+			args []string
+			ids  []string // first column of each row
+		}{
+			{[]string{"-month", "2026-10"}, []string{"1", "2", "4"}},
+			{[]string{"-month", "2026-09"}, []string{"3"}},
+			{[]string{"-category", "FOOD"}, []string{"3", "1"}},
+			{[]string{"-from", "2026-10-01", "-to", "2026-10-01"}, []string{"1", "2"}},
+			{[]string{"-from", "2026-10-02"}, []string{"4"}},
+			{[]string{"-to", "2026-09-30"}, []string{"3"}},
+		}
+		for _, tt := range tests {
+			out := c.mustRun(append([]string{"list"}, tt.args...)...)
+			var ids []string
+			for _, line := range strings.Split(out, "\n") {
+				if fields := strings.Fields(line); len(fields) > 0 && fields[0] != "ID" && fields[0] != "TOTAL" {
+					ids = append(ids, fields[0])
+				}
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.ids, ",") {
+				t.Errorf("list %v: ids %v, want %v", tt.args, ids, tt.ids)
+			}
+		}
+	})
+}
+
+func TestReport(t *testing.T) {
+	forEachStore(t, func(t *testing.T, c cli) {
+		seed(c)
+
+		want := strings.Join([]string{
+			"CATEGORY   COUNT       TOTAL   SHARE",
+			"rent           1     1200.00   95.5%",
+			"transport      1       40.00    3.2%",
+			"food           2       16.49    1.3%",
+			"TOTAL          4     1256.49  ",
+			"",
+		}, "\n")
+		if out := c.mustRun("report"); out != want {
+			t.Errorf("report =\n%s\nwant\n%s", out, want)
+		}
+
+		want = strings.Join([]string{
+			"MONTH    COUNT       TOTAL   SHARE",
+			"2026-09      1        3.99   24.2%",
+			"2026-10      1       12.50   75.8%",
+			"TOTAL        2       16.49  ",
+			"",
+		}, "\n")
+		if out := c.mustRun("report", "-by", "month", "-category", "food"); out != want {
+			t.Errorf("report -by month =\n%s\nwant\n%s", out, want)
+		}
+	})
+}
+
+func TestUsageErrors(t *testing.T) {
+	c := cli{t: t, file: filepath.Join(t.TempDir(), "expenses.json")}
+	for _, args := range [][]string{
+		{},
+		{"fly"},
+		{"add"},
+		{"add", "-amount", "5"},
+		{"add", "-amount", "5", "-category", "x", "extra"},
+		{"list", "-bogus"},
+	} {
+		if _, err := c.run(args...); !errors.Is(err, errUsage) {
+			t.Errorf("%v: err = %v, want errUsage", args, err)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"add", "-amount", "1.234", "-category", "x"},
+		{"add", "-amount", "5", "-category", "x", "-date", "10/01/2026"},
+		{"list", "-month", "October"},
+		{"list", "-from", "2026-10-02", "-to", "2026-10-01"},
+		{"report", "-by", "week"},
+	} {
+		if _, err := c.run(args...); err == nil || errors.Is(err, errUsage) {
+			t.Errorf("%v: err = %v, want a validation error", args, err)
+		}
+	}
+}
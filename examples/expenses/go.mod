@@ -0,0 +1,5 @@
+module github.com/owolabijunior12/learning-golang/examples/expenses
+
+go 1.25.1
+
+require github.com/mattn/go-sqlite3 v1.14.52
@@ -0,0 +1,27 @@
+// Command expenses is a command-line expense tracker.
+//
+// It uses flag.FlagSet for subcommands, and stores data either in a JSON
+// file (course 5) or in SQLite (course 7):
+//
+//	go run ./examples/expenses add -amount 12.50 -category food -note lunch
+//	go run ./examples/expenses list -month 2026-10
+//	go run ./examples/expenses -file expenses.db report -by month
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr, time.Now)
+	switch {
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
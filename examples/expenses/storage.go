@@ -0,0 +1,191 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // registers the "sqlite3" driver
+)
+
+// Store persists expenses. List returns matches ordered by date, then ID.
+type Store interface {
+	Add(e Expense) (Expense, error)
+	List(f Filter) ([]Expense, error)
+	Close() error
+}
+
+// OpenStore picks the backend from the file extension: .db or .sqlite for
+// SQLite, anything else for JSON.
+func OpenStore(path string) (Store, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return OpenSQLiteStore(path)
+	default:
+		return &JSONStore{path: path}, nil
+	}
+}
+
+// ============ JSON FILE (course 5) ============
+
+// JSONStore keeps every expense in one human-readable JSON file. Each Add
+// reads the whole file and writes it back, which is fine for a personal
+// tracker with a few thousand entries.
+type JSONStore struct {
+	path string
+}
+
+func (s *JSONStore) load() ([]Expense, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil // no file yet: no expenses
+	}
+	if err != nil {
+		return nil, err
+	}
+	var expenses []Expense
+	if err := json.Unmarshal(data, &expenses); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	return expenses, nil
+}
+
+func (s *JSONStore) Add(e Expense) (Expense, error) {
+	expenses, err := s.load()
+	if err != nil {
+		return Expense{}, err
+	}
+	e.ID = 1
+	for _, existing := range expenses {
+		e.ID = max(e.ID, existing.ID+1)
+	}
+	expenses = append(expenses, e)
+
+	data, err := json.MarshalIndent(expenses, "", "  ")
+	if err != nil {
+		return Expense{}, err
+	}
+	// Write a temp file and rename it over the old one: a crash mid-write
+	// leaves the previous file intact instead of a truncated one
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return Expense{}, err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return Expense{}, err
+	}
+	return e, nil
+}
+
+func (s *JSONStore) List(f Filter) ([]Expense, error) {
+	expenses, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var matched []Expense
+	for _, e := range expenses {
+		if f.Match(e) {
+			matched = append(matched, e)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		if !matched[i].Date.Equal(matched[j].Date) {
+			return matched[i].Date.Before(matched[j].Date)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+	return matched, nil
+}
+
+func (s *JSONStore) Close() error { return nil }
+
+// ============ SQLITE (course 7) ============
+
+const schema = `
+CREATE TABLE IF NOT EXISTS expenses (
+	id       INTEGER PRIMARY KEY AUTOINCREMENT,
+	date     TEXT    NOT NULL, -- YYYY-MM-DD sorts and compares correctly as text
+	cents    INTEGER NOT NULL CHECK (cents > 0),
+	category TEXT    NOT NULL,
+	note     TEXT    NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS expenses_date ON expenses (date)`
+
+// SQLiteStore filters in SQL instead of loading everything into memory.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1) // one writer; also keeps ":memory:" to a single database
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate %s: %w", path, err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Add(e Expense) (Expense, error) {
+	res, err := s.db.Exec(
+		`INSERT INTO expenses (date, cents, category, note) VALUES (?, ?, ?, ?)`,
+		e.Date.Format(dateLayout), e.Cents, e.Category, e.Note,
+	)
+	if err != nil {
+		return Expense{}, fmt.Errorf("insert expense: %w", err)
+	}
+	e.ID, err = res.LastInsertId()
+	return e, err
+}
+
+func (s *SQLiteStore) List(f Filter) ([]Expense, error) {
+	// Build the WHERE clause from placeholders only; values never go into
+	// the SQL string
+	query := `SELECT id, date, cents, category, note FROM expenses WHERE 1=1`
+	var args []any
+	if !f.From.IsZero() {
+		query += ` AND date >= ?`
+		args = append(args, f.From.Format(dateLayout))
+	}
+	if !f.To.IsZero() {
+		query += ` AND date <= ?`
+		args = append(args, f.To.Format(dateLayout))
+	}
+	if f.Category != "" {
+		query += ` AND category = ? COLLATE NOCASE`
+		args = append(args, f.Category)
+	}
+	query += ` ORDER BY date, id`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var expenses []Expense
+	for rows.Next() {
+		var e Expense
+		var date string
+		if err := rows.Scan(&e.ID, &date, &e.Cents, &e.Category, &e.Note); err != nil {
+			return nil, err
+		}
+		if e.Date, err = time.Parse(dateLayout, date); err != nil {
+			return nil, fmt.Errorf("expense %d: bad date %q", e.ID, date)
+		}
+		expenses = append(expenses, e)
+	}
+	return expenses, rows.Err()
+}
+
+func (s *SQLiteStore) Close() error { return s.db.Close() }
@@ -0,0 +1,73 @@
+// Command kvstore is a tiny key-value database server.
+//
+// It speaks a line-based text protocol over TCP (see protocol.go) and keeps
+// its data in an append-only log, tying together networking, files
+// (course 5) and concurrency (course 4):
+//
+//	go run ./examples/kvstore -addr :6380 -data kv.aof
+//	printf 'SET lang go\nGET lang\nQUIT\n' | nc localhost 6380
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", ":6380", "TCP listen address")
+	dataPath := flag.String("data", "kv.aof", "append-only log file")
+	syncWrites := flag.Bool("sync", false, "fsync the log after every write")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[kvstore] ", log.LstdFlags)
+	if err := run(*addr, *dataPath, *syncWrites, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(addr, dataPath string, syncWrites bool, logger *log.Logger) error {
+	store, err := OpenStore(dataPath, syncWrites)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	// The log only grows; start each run from a compact copy
+	if err := store.Compact(); err != nil {
+		return err
+	}
+	logger.Printf("loaded %d keys from %s", len(store.Keys()), dataPath)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := NewServer(store, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		logger.Println("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Printf("shutdown: %v", err)
+		}
+	}()
+
+	logger.Printf("listening on %s", ln.Addr())
+	if err := server.Serve(ln); !errors.Is(err, ErrServerClosed) {
+		return err
+	}
+	<-shutdownDone // clients are gone; now the deferred store.Close is safe
+	return nil
+}
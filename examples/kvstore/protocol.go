@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The protocol is one command per line, one reply per line:
+//
+//	SET <key> <value>   ->  OK
+//	GET <key>           ->  VALUE <value>   |  NOT_FOUND
+//	DEL <key>           ->  DELETED         |  NOT_FOUND
+//	KEYS                ->  KEYS <n>, then n lines with one key each
+//	PING                ->  PONG
+//	QUIT                ->  BYE (and the server closes the connection)
+//
+// Anything else gets "ERR <message>". Keys contain no whitespace; a value is
+// the rest of the line and may contain spaces but not newlines. Command
+// names are case-insensitive. You can talk to the server with netcat:
+//
+//	nc localhost 6380
+
+const (
+	maxKeyLength  = 250
+	maxLineLength = 64 << 10 // bufio.Scanner's default token size
+)
+
+// Command is one parsed request line.
+type Command struct {
+	Name  string // upper-case
+	Key   string
+	Value string
+}
+
+var errEmptyCommand = errors.New("empty command")
+
+// ParseCommand parses a single line, without its trailing newline.
+func ParseCommand(line string) (Command, error) {
+	line = strings.TrimRight(line, "\r") // telnet and Windows clients send \r\n
+	name, rest, _ := strings.Cut(strings.TrimLeft(line, " "), " ")
+	cmd := Command{Name: strings.ToUpper(name)}
+
+	switch cmd.Name {
+	case "":
+		return cmd, errEmptyCommand
+	case "PING", "QUIT", "KEYS":
+		if strings.TrimSpace(rest) != "" {
+			return cmd, fmt.Errorf("%s takes no arguments", cmd.Name)
+		}
+	case "GET", "DEL":
+		cmd.Key = strings.TrimSpace(rest)
+		if strings.ContainsAny(cmd.Key, " \t") {
+			return cmd, fmt.Errorf("usage: %s <key>", cmd.Name)
+		}
+		if err := checkKey(cmd.Key); err != nil {
+			return cmd, err
+		}
+	case "SET":
+		key, value, ok := strings.Cut(rest, " ")
+		if !ok {
+			return cmd, errors.New("usage: SET <key> <value>")
+		}
+		if err := checkKey(key); err != nil {
+			return cmd, err
+		}
+		cmd.Key, cmd.Value = key, value
+	default:
+		return cmd, fmt.Errorf("unknown command %q", name)
+	}
+	return cmd, nil
+}
+
+// checkKey rejects what a log line couldn't hold: a key ends at the first
+// space, and the line at the first newline.
+func checkKey(key string) error {
+	switch {
+	case key == "":
+		return errors.New("missing key")
+	case len(key) > maxKeyLength:
+		return fmt.Errorf("key longer than %d bytes", maxKeyLength)
+	case strings.ContainsAny(key, " \t\r\n"):
+		return errors.New("key contains whitespace")
+	}
+	return nil
+}
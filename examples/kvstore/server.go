@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Server accepts TCP connections and runs one goroutine per client. The
+// Store does its own locking, so clients never coordinate with each other.
+type Server struct {
+	store       *Store
+	logger      *log.Logger
+	idleTimeout time.Duration // close clients that send nothing for this long
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closing  bool
+	wg       sync.WaitGroup // one per client goroutine
+}
+
+func NewServer(store *Store, logger *log.Logger) *Server {
+	return &Server{
+		store:       store,
+		logger:      logger,
+		idleTimeout: 5 * time.Minute,
+		conns:       make(map[net.Conn]struct{}),
+	}
+}
+
+var ErrServerClosed = errors.New("kvstore: server closed")
+
+// Serve accepts connections on ln until Shutdown is called.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.listener = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closing := s.closing
+			s.mu.Unlock()
+			if closing {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closing {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+// Shutdown stops accepting, closes every client connection and waits for
+// their goroutines to finish, or for ctx to expire.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close() // unblocks the client's pending read
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxLineLength)
+	w := bufio.NewWriter(conn)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(s.idleTimeout))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) && !errors.Is(err, os.ErrDeadlineExceeded) {
+				// Usually bufio.ErrTooLong; tell the client before hanging up
+				fmt.Fprintf(w, "ERR %v\n", err)
+				w.Flush()
+			}
+			return
+		}
+
+		quit := s.execute(w, scanner.Text())
+		// Flush once per command; a client that pipelines many commands
+		// still gets every reply in order
+		if err := w.Flush(); err != nil || quit {
+			return
+		}
+	}
+}
+
+// execute runs one command line and writes its reply. It reports whether
+// the client asked to disconnect.
+func (s *Server) execute(w io.Writer, line string) (quit bool) {
+	cmd, err := ParseCommand(line)
+	if errors.Is(err, errEmptyCommand) {
+		return false // blank lines are ignored, handy when typing into nc
+	}
+	if err != nil {
+		fmt.Fprintf(w, "ERR %v\n", err)
+		return false
+	}
+
+	switch cmd.Name {
+	case "PING":
+		io.WriteString(w, "PONG\n")
+	case "QUIT":
+		io.WriteString(w, "BYE\n")
+		return true
+	case "GET":
+		if value, ok := s.store.Get(cmd.Key); ok {
+			io.WriteString(w, "VALUE "+value+"\n")
+		} else {
+			io.WriteString(w, "NOT_FOUND\n")
+		}
+	case "SET":
+		if err := s.store.Set(cmd.Key, cmd.Value); err != nil {
+			s.logger.Printf("SET %s: %v", cmd.Key, err)
+			io.WriteString(w, "ERR write failed\n")
+			return false
+		}
+		io.WriteString(w, "OK\n")
+	case "DEL":
+		existed, err := s.store.Delete(cmd.Key)
+		switch {
+		case err != nil:
+			s.logger.Printf("DEL %s: %v", cmd.Key, err)
+			io.WriteString(w, "ERR write failed\n")
+		case existed:
+			io.WriteString(w, "DELETED\n")
+		default:
+			io.WriteString(w, "NOT_FOUND\n")
+		}
+	case "KEYS":
+		keys := s.store.Keys()
+		io.WriteString(w, "KEYS "+strconv.Itoa(len(keys))+"\n")
+		for _, k := range keys {
+			io.WriteString(w, k+"\n")
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T) (addr string, store *Store) {
+	t.Helper()
+	store, _ = openTemp(t)
+	server := NewServer(store, log.New(io.Discard, "", 0))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(ln) }()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			t.Errorf("shutdown: %v", err)
+		}
+		if err := <-served; !errors.Is(err, ErrServerClosed) {
+			t.Errorf("Serve returned %v, want ErrServerClosed", err)
+		}
+		store.Close()
+	})
+	return ln.Addr().String(), store
+}
+
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) *client {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// do sends one command and returns the first reply line
+func (c *client) do(line string) string {
+	c.t.Helper()
+	c.conn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := fmt.Fprintf(c.conn, "%s\n", line); err != nil {
+		c.t.Fatalf("send %q: %v", line, err)
+	}
+	return c.readLine()
+}
+
+func (c *client) readLine() string {
+	c.t.Helper()
+	reply, err := c.r.ReadString('\n')
+	if err != nil {
+		c.t.Fatalf("read reply: %v", err)
+	}
+	return strings.TrimSuffix(reply, "\n")
+}
+
+func TestProtocol(t *testing.T) {
+	addr, _ := startServer(t)
+	c := dial(t, addr)
+
+	steps := []struct{ send, want string }{
+		{"PING", "PONG"},
+		{"GET lang", "NOT_FOUND"},
+		{"SET lang go", "OK"},
+		{"get lang", "VALUE go"},
+		{"SET motto  share memory by communicating ", "OK"},
+		{"GET motto", "VALUE  share memory by communicating "},
+		{"SET lang go\r", "OK"}, // telnet-style line ending
+		{"DEL lang", "DELETED"},
+		{"DEL lang", "NOT_FOUND"},
+		{"\nPING", "PONG"}, // blank lines get no reply
+		{"FLY away", `ERR unknown command "FLY"`},
+		{"SET onlykey", "ERR usage: SET <key> <value>"},
+		{"GET a b", "ERR usage: GET <key>"},
+		{"GET " + strings.Repeat("k", maxKeyLength+1), "ERR key longer than 250 bytes"},
+		{"PING now", "ERR PING takes no arguments"},
+	}
+	for _, step := range steps {
+		if got := c.do(step.send); got != step.want {
+			t.Errorf("%q -> %q, want %q", step.send, got, step.want)
+		}
+	}
+}
+
+func TestKeysAndQuit(t *testing.T) {
+	addr, _ := startServer(t)
+	c := dial(t, addr)
+	c.do("SET b 2")
+	c.do("SET a 1")
+
+	if got := c.do("KEYS"); got != "KEYS 2" {
+		t.Fatalf("KEYS -> %q", got)
+	}
+	if a, b := c.readLine(), c.readLine(); a != "a" || b != "b" {
+		t.Errorf("keys = %q, %q; want sorted a, b", a, b)
+	}
+
+	if got := c.do("QUIT"); got != "BYE" {
+		t.Errorf("QUIT -> %q", got)
+	}
+	if _, err := c.r.ReadString('\n'); !errors.Is(err, io.EOF) {
+		t.Errorf("after QUIT, read = %v; want the server to close the connection", err)
+	}
+}
+
+func TestPipelinedCommands(t *testing.T) {
+	addr, _ := startServer(t)
+	c := dial(t, addr)
+
+	// Many commands in one write; replies must come back in order
+	var batch strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&batch, "SET k%d v%d\nGET k%d\n", i, i, i)
+	}
+	c.conn.SetDeadline(time.Now().Add(2 * time.Second))
+	io.WriteString(c.conn, batch.String())
+
+	for i := range 100 {
+		if got := c.readLine(); got != "OK" {
+			t.Fatalf("reply %d = %q, want OK", 2*i, got)
+		}
+		if got, want := c.readLine(), fmt.Sprintf("VALUE v%d", i); got != want {
+			t.Fatalf("reply %d = %q, want %q", 2*i+1, got, want)
+		}
+	}
+}
+
+func TestConcurrentClients(t *testing.T) {
+	addr, store := startServer(t)
+
+	const clients, perClient = 20, 50
+	var wg sync.WaitGroup
+	for n := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+			r := bufio.NewReader(conn)
+			for i := range perClient {
+				fmt.Fprintf(conn, "SET client%d-%d %d\n", n, i, i)
+				if reply, _ := r.ReadString('\n'); reply != "OK\n" {
+					t.Errorf("client %d: SET -> %q", n, reply)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(store.Keys()); got != clients*perClient {
+		t.Errorf("store has %d keys, want %d", got, clients*perClient)
+	}
+}
+
+func TestLineTooLong(t *testing.T) {
+	addr, _ := startServer(t)
+	c := dial(t, addr)
+	c.conn.SetDeadline(time.Now().Add(2 * time.Second))
+	fmt.Fprintf(c.conn, "SET big %s\n", strings.Repeat("x", maxLineLength))
+
+	if got := c.readLine(); !strings.HasPrefix(got, "ERR") {
+		t.Errorf("oversized line -> %q, want an ERR before disconnect", got)
+	}
+}
+
+func TestShutdownClosesIdleClients(t *testing.T) {
+	store, _ := openTemp(t)
+	defer store.Close()
+	server := NewServer(store, log.New(io.Discard, "", 0))
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	go server.Serve(ln)
+
+	c := dial(t, ln.Addr().String())
+	c.do("PING") // make sure the connection is being served
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown = %v, want idle clients closed promptly", err)
+	}
+	if _, err := c.r.ReadString('\n'); err == nil {
+		t.Error("client connection still open after Shutdown")
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Store is an in-memory map backed by an append-only log (AOF).
+//
+// Every SET and DEL is appended to the log before the map changes, so after
+// a crash the map can be rebuilt by replaying the file from the top. The log
+// lines use the same text as the network protocol:
+//
+//	SET greeting hello world
+//	DEL greeting
+type Store struct {
+	mu   sync.RWMutex // guards data and the log file
+	data map[string]string
+
+	path string
+	log  *os.File
+	sync bool // fsync after every write: slower, but nothing is lost on power failure
+}
+
+// OpenStore replays the log at path (creating it if needed) and opens it for
+// appending.
+func OpenStore(path string, syncWrites bool) (*Store, error) {
+	s := &Store{data: make(map[string]string), path: path, sync: syncWrites}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open log: %w", err)
+	}
+	good, err := s.replay(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	// A crash in the middle of a write leaves half a line at the end; cut it
+	// off so new entries start on a clean line
+	if err := f.Truncate(good); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("truncate log: %w", err)
+	}
+	if _, err := f.Seek(good, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	s.log = f
+	return s, nil
+}
+
+// replay applies every complete line and returns the offset just past the
+// last one.
+func (s *Store) replay(r io.Reader) (int64, error) {
+	reader := bufio.NewReader(r)
+	var offset int64
+	for lineNo := 1; ; lineNo++ {
+		line, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			return offset, nil // anything in line has no newline: a torn write
+		}
+		if err != nil {
+			return 0, fmt.Errorf("read log: %w", err)
+		}
+
+		cmd, parseErr := ParseCommand(strings.TrimSuffix(line, "\n"))
+		switch {
+		case parseErr != nil:
+			return 0, fmt.Errorf("%s line %d: %w", s.path, lineNo, parseErr)
+		case cmd.Name == "SET":
+			s.data[cmd.Key] = cmd.Value
+		case cmd.Name == "DEL":
+			delete(s.data, cmd.Key)
+		default:
+			return 0, fmt.Errorf("%s line %d: unexpected %s in log", s.path, lineNo, cmd.Name)
+		}
+		offset += int64(len(line))
+	}
+}
+
+func (s *Store) Get(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.data[key]
+	return value, ok
+}
+
+// Set checks the key as the protocol does: a key the log can't hold would
+// come back from a replay as another key, or not at all.
+func (s *Store) Set(key, value string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return errors.New("values cannot contain newlines") // they would corrupt the log
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.appendLog("SET " + key + " " + value); err != nil {
+		return err
+	}
+	s.data[key] = value
+	return nil
+}
+
+// Delete reports whether the key existed. Deleting a missing key writes
+// nothing to the log.
+func (s *Store) Delete(key string) (bool, error) {
+	if err := checkKey(key); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[key]; !ok {
+		return false, nil
+	}
+	if err := s.appendLog("DEL " + key); err != nil {
+		return false, err
+	}
+	delete(s.data, key)
+	return true, nil
+}
+
+// Keys returns every key, sorted.
+func (s *Store) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendLog must be called with mu held.
+func (s *Store) appendLog(line string) error {
+	if _, err := s.log.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("append log: %w", err)
+	}
+	if s.sync {
+		if err := s.log.Sync(); err != nil {
+			return fmt.Errorf("sync log: %w", err)
+		}
+	}
+	return nil
+}
+
+// Compact rewrites the log with one SET per live key, dropping overwritten
+// and deleted entries. The new log is written to a temp file and renamed over
+// the old one, so a crash mid-compaction leaves the old log intact.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buf bytes.Buffer
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "SET %s %s\n", k, s.data[k])
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".compact-*")
+	if err != nil {
+		return fmt.Errorf("compact: %w", err)
+	}
+	defer os.Remove(tmp.Name())              // no-op once renamed
+	if err := tmp.Chmod(0o644); err != nil { // CreateTemp uses 0600
+		tmp.Close()
+		return fmt.Errorf("compact: %w", err)
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("compact: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("compact: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		tmp.Close()
+		return fmt.Errorf("compact: %w", err)
+	}
+
+	// Keep appending to the new file
+	s.log.Close()
+	s.log = tmp
+	_, err = tmp.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.log.Close()
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTemp(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kv.aof")
+	s, err := OpenStore(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func TestStoreReplaysLog(t *testing.T) {
+	s, path := openTemp(t)
+	s.Set("lang", "go")
+	s.Set("greeting", "hello world")
+	s.Set("lang", "golang")
+	s.Delete("greeting")
+	s.Delete("never-set") // writes nothing
+	s.Close()
+
+	data, _ := os.ReadFile(path)
+	want := "SET lang go\nSET greeting hello world\nSET lang golang\nDEL greeting\n"
+	if string(data) != want {
+		t.Errorf("log =\n%s\nwant\n%s", data, want)
+	}
+
+	reopened, err := OpenStore(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if v, _ := reopened.Get("lang"); v != "golang" {
+		t.Errorf("lang = %q after replay, want golang", v)
+	}
+	if _, ok := reopened.Get("greeting"); ok {
+		t.Error("greeting was deleted but came back after replay")
+	}
+}
+
+func TestStoreRecoversFromTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kv.aof")
+	// The process died halfway through writing the second entry
+	os.WriteFile(path, []byte("SET a 1\nSET b 2"), 0o644)
+
+	s, err := OpenStore(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("b"); ok {
+		t.Error("an incomplete entry should be discarded")
+	}
+	s.Set("c", "3")
+	s.Close()
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "SET a 1\nSET c 3\n" {
+		t.Errorf("log = %q, want the torn line replaced", data)
+	}
+}
+
+func TestStoreRejectsCorruptLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kv.aof")
+	os.WriteFile(path, []byte("SET a 1\nFROB a\n"), 0o644)
+
+	_, err := OpenStore(path, false)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("err = %v, want an error pointing at line 2", err)
+	}
+}
+
+func TestStoreCompact(t *testing.T) {
+	s, path := openTemp(t)
+	for _, v := range []string{"1", "2", "3"} {
+		s.Set("counter", v)
+	}
+	s.Set("tmp", "x")
+	s.Delete("tmp")
+	s.Set("name", "gopher")
+
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	s.Set("after", "compaction") // must land in the new file
+	s.Close()
+
+	data, _ := os.ReadFile(path)
+	want := "SET counter 3\nSET name gopher\nSET after compaction\n"
+	if string(data) != want {
+		t.Errorf("compacted log =\n%s\nwant\n%s", data, want)
+	}
+	if leftovers, _ := filepath.Glob(path + ".compact-*"); len(leftovers) != 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestStoreRejectsNewlines(t *testing.T) {
+	s, _ := openTemp(t)
+	defer s.Close()
+	if err := s.Set("k", "line1\nSET evil 1"); err == nil {
+		t.Error("a value with a newline would inject a log entry; want an error")
+	}
+}
+
+func TestStoreRejectsBadKeys(t *testing.T) {
+	s, path := openTemp(t)
+	s.Set("ok", "1")
+	for _, key := range []string{"two words", "", "tab\tkey", "line\nkey"} {
+		if err := s.Set(key, "v"); err == nil {
+			t.Errorf("Set(%q) was accepted", key)
+		}
+		if _, err := s.Delete(key); err == nil {
+			t.Errorf("Delete(%q) was accepted", key)
+		}
+	}
+	s.Close()
+
+	// Only what was acknowledged comes back
+	reopened, err := OpenStore(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if keys := reopened.Keys(); strings.Join(keys, ",") != "ok" {
+		t.Errorf("keys after replay = %q, want [ok]", keys)
+	}
+	if _, ok := reopened.Get("two"); ok {
+		t.Error(`"two words" came back as "two"`)
+	}
+}
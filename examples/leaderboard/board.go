@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var errPlayerNotFound = errors.New("player not found")
+
+// Entry is a player's place on the board. Rank starts at 1 for the highest
+// score.
+type Entry struct {
+	Rank   int64  `json:"rank"`
+	Player string `json:"player"`
+	Score  int64  `json:"score"`
+}
+
+// Board is a leaderboard in one Redis sorted set (course 9): the members
+// are player names, the scores their best scores. Redis keeps the set
+// ordered, so ranks and pages cost O(log n) rather than a sort per request.
+type Board struct {
+	client *redis.Client
+	key    string
+}
+
+func NewBoard(client *redis.Client, key string) *Board {
+	return &Board{client: client, key: key}
+}
+
+// Submit records a score for player and returns their place. Only a
+// player's best score counts: ZADD GT leaves a higher existing score alone,
+// and improved reports whether this one replaced it.
+func (b *Board) Submit(ctx context.Context, player string, score int64) (entry Entry, improved bool, err error) {
+	var added *redis.IntCmd
+	var rank *redis.IntCmd
+	var best *redis.FloatCmd
+	// MULTI/EXEC, so the rank returned is the one this score earned, not
+	// one after another submission landed in between
+	_, err = b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		added = pipe.ZAddArgs(ctx, b.key, redis.ZAddArgs{GT: true, Ch: true, Members: []redis.Z{{Score: float64(score), Member: player}}})
+		best = pipe.ZScore(ctx, b.key, player)
+		rank = pipe.ZRevRank(ctx, b.key, player)
+		return nil
+	})
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("submit score for %s: %w", player, err)
+	}
+	entry = Entry{Rank: rank.Val() + 1, Player: player, Score: int64(best.Val())}
+	return entry, added.Val() == 1, nil
+}
+
+// Player returns player's place, or errPlayerNotFound.
+func (b *Board) Player(ctx context.Context, player string) (Entry, error) {
+	var rank *redis.IntCmd
+	var score *redis.FloatCmd
+	_, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		score = pipe.ZScore(ctx, b.key, player)
+		rank = pipe.ZRevRank(ctx, b.key, player)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return Entry{}, errPlayerNotFound
+	}
+	if err != nil {
+		return Entry{}, fmt.Errorf("look up %s: %w", player, err)
+	}
+	return Entry{Rank: rank.Val() + 1, Player: player, Score: int64(score.Val())}, nil
+}
+
+// Top returns up to limit entries from the offset-th highest score on, and
+// the number of players on the board.
+func (b *Board) Top(ctx context.Context, offset, limit int64) ([]Entry, int64, error) {
+	var page *redis.ZSliceCmd
+	var total *redis.IntCmd
+	_, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		page = pipe.ZRevRangeWithScores(ctx, b.key, offset, offset+limit-1)
+		total = pipe.ZCard(ctx, b.key)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("read top %d from %d: %w", limit, offset, err)
+	}
+	return entries(page.Val(), offset), total.Val(), nil
+}
+
+// Around returns player's entry with up to n entries above and below it:
+// "you are 1,204th, and these are your neighbours".
+func (b *Board) Around(ctx context.Context, player string, n int64) ([]Entry, error) {
+	rank, err := b.client.ZRevRank(ctx, b.key, player).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, errPlayerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("look up %s: %w", player, err)
+	}
+
+	// Two round trips: a submission in between can shift the window by a
+	// place, which a leaderboard can live with
+	start := max(rank-n, 0)
+	page, err := b.client.ZRevRangeWithScores(ctx, b.key, start, rank+n).Result()
+	if err != nil {
+		return nil, fmt.Errorf("read around %s: %w", player, err)
+	}
+	return entries(page, start), nil
+}
+
+// entries numbers a ZREVRANGE result that started at rank index start.
+// Equal scores are ordered by name, Z to A, as Redis sorts them.
+func entries(page []redis.Z, start int64) []Entry {
+	out := make([]Entry, len(page))
+	for i, z := range page {
+		out[i] = Entry{Rank: start + int64(i) + 1, Player: z.Member.(string), Score: int64(z.Score)}
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+// Command leaderboard serves a game leaderboard from a Redis sorted set.
+//
+//	docker run --name redis -d -p 6379:6379 redis:latest
+//	go run ./examples/leaderboard            # or -fake-redis, without Docker
+//	curl -X POST localhost:8087/scores -d '{"player":"alice","score":120}'
+//	curl 'localhost:8087/top?limit=10'
+//	curl localhost:8087/players/alice/around
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	addr := flag.String("addr", ":8087", "listen address")
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address")
+	key := flag.String("key", "leaderboard", "sorted set holding the scores")
+	fake := flag.Bool("fake-redis", false, "run an in-process fake Redis instead; scores are lost on exit")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[leaderboard] ", log.LstdFlags)
+	if *fake {
+		mr, err := miniredis.Run()
+		if err != nil {
+			logger.Fatal(err)
+		}
+		defer mr.Close()
+		*redisAddr = mr.Addr()
+	}
+	if err := run(*addr, *redisAddr, *key, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(addr, redisAddr, key string, logger *log.Logger) error {
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer rdb.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		// Every request needs Redis; start anyway and answer 503 until it is up
+		logger.Printf("warning: redis unavailable at %s: %v", redisAddr, err)
+	}
+
+	server := NewServer(NewBoard(rdb, key), logger)
+	logger.Printf("listening on %s", addr)
+	return http.ListenAndServe(addr, server.Routes())
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+const (
+	defaultLimit  = 10
+	maxLimit      = 100
+	defaultAround = 5
+	maxAround     = 50
+	// maxScore is the largest whole number a sorted-set score, a float64,
+	// holds exactly
+	maxScore = 1 << 53
+)
+
+var validPlayer = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,32}$`)
+
+// Server handles the leaderboard's HTTP API.
+type Server struct {
+	board  *Board
+	logger *log.Logger
+}
+
+func NewServer(board *Board, logger *log.Logger) *Server {
+	return &Server{board: board, logger: logger}
+}
+
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /scores", s.handleSubmit)
+	mux.HandleFunc("GET /top", s.handleTop)
+	mux.HandleFunc("GET /players/{player}", s.handlePlayer)
+	mux.HandleFunc("GET /players/{player}/around", s.handleAround)
+	return mux
+}
+
+type submitRequest struct {
+	Player string `json:"player"`
+	Score  int64  `json:"score"`
+}
+
+type submitResponse struct {
+	Entry
+	Improved bool `json:"improved"` // false if the player already had a score at least this high
+}
+
+type pageResponse struct {
+	Entries    []Entry `json:"entries"`
+	Total      int64   `json:"total"`
+	Offset     int64   `json:"offset"`
+	Limit      int64   `json:"limit"`
+	NextOffset *int64  `json:"next_offset,omitempty"` // absent on the last page
+}
+
+type aroundResponse struct {
+	Entries []Entry `json:"entries"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req submitRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid JSON"})
+		return
+	}
+	if !validPlayer.MatchString(req.Player) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "player must be 1-32 letters, digits, '.', '_' or '-'"})
+		return
+	}
+	if req.Score < 0 || req.Score > maxScore {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("score must be between 0 and %d", int64(maxScore))})
+		return
+	}
+
+	entry, improved, err := s.board.Submit(r.Context(), req.Player, req.Score)
+	if err != nil {
+		s.unavailable(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, submitResponse{Entry: entry, Improved: improved})
+}
+
+func (s *Server) handleTop(w http.ResponseWriter, r *http.Request) {
+	offset, err := queryInt(r, "offset", 0, 0, math.MaxInt32)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultLimit, 1, maxLimit)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	entries, total, err := s.board.Top(r.Context(), offset, limit)
+	if err != nil {
+		s.unavailable(w, err)
+		return
+	}
+	resp := pageResponse{Entries: entries, Total: total, Offset: offset, Limit: limit}
+	if next := offset + limit; next < total {
+		resp.NextOffset = &next
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handlePlayer(w http.ResponseWriter, r *http.Request) {
+	entry, err := s.board.Player(r.Context(), r.PathValue("player"))
+	if errors.Is(err, errPlayerNotFound) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		s.unavailable(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (s *Server) handleAround(w http.ResponseWriter, r *http.Request) {
+	n, err := queryInt(r, "n", defaultAround, 0, maxAround)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	entries, err := s.board.Around(r.Context(), r.PathValue("player"), n)
+	if errors.Is(err, errPlayerNotFound) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		s.unavailable(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, aroundResponse{Entries: entries})
+}
+
+// unavailable logs a Redis error and answers 503: the board is all in
+// Redis, so there is nothing to fall back on
+func (s *Server) unavailable(w http.ResponseWriter, err error) {
+	s.logger.Print(err)
+	writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "leaderboard is unavailable"})
+}
+
+// queryInt reads an integer query parameter, def if it is absent.
+func queryInt(r *http.Request, name string, def, lo, hi int64) (int64, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < lo || n > hi {
+		return 0, fmt.Errorf("%s must be a whole number from %d to %d", name, lo, hi)
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testEnv is a server backed by an in-process Redis
+type testEnv struct {
+	server *Server
+	redis  *miniredis.Miniredis
+	logs   *strings.Builder
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	logs := &strings.Builder{}
+	server := NewServer(NewBoard(rdb, "leaderboard"), log.New(logs, "", 0))
+	return &testEnv{server: server, redis: mr, logs: logs}
+}
+
+func (e *testEnv) do(method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	e.server.Routes().ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func (e *testEnv) submit(t *testing.T, player string, score int64) submitResponse {
+	t.Helper()
+	rec := e.do(http.MethodPost, "/scores", fmt.Sprintf(`{"player":%q,"score":%d}`, player, score))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("submit %s %d: %d %s", player, score, rec.Code, rec.Body.String())
+	}
+	var resp submitResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// seed submits players p01..pNN with scores 10, 20, ... so that pNN leads
+func (e *testEnv) seed(t *testing.T, n int) {
+	t.Helper()
+	for i := 1; i <= n; i++ {
+		e.submit(t, fmt.Sprintf("p%02d", i), int64(i*10))
+	}
+}
+
+func players(entries []Entry) []string {
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Player)
+	}
+	return names
+}
+
+func TestSubmit_KeepsBestScore(t *testing.T) {
+	env := newTestEnv(t)
+	env.submit(t, "bob", 90)
+
+	tests := []struct {
+		score        int64
+		wantScore    int64
+		wantRank     int64
+		wantImproved bool
+	}{
+		{100, 100, 1, true},
+		{80, 100, 1, false}, // a worse game doesn't lower the best
+		{100, 100, 1, false},
+		{120, 120, 1, true},
+	}
+	for _, tt := range tests {
+		got := env.submit(t, "alice", tt.score)
+		if got.Score != tt.wantScore || got.Rank != tt.wantRank || got.Improved != tt.wantImproved {
+			t.Errorf("submit %d: got %+v, want score %d rank %d improved %v",
+				tt.score, got, tt.wantScore, tt.wantRank, tt.wantImproved)
+		}
+	}
+	if got, _ := env.redis.ZScore("leaderboard", "alice"); got != 120 {
+		t.Errorf("redis score = %v, want 120", got)
+	}
+}
+
+func TestSubmit_Validation(t *testing.T) {
+	env := newTestEnv(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not JSON", `{"player":`},
+		{"no player", `{"score":1}`},
+		{"bad player", `{"player":"a b","score":1}`},
+		{"long player", `{"player":"` + strings.Repeat("a", 33) + `","score":1}`},
+		{"negative score", `{"player":"alice","score":-1}`},
+		{"huge score", `{"player":"alice","score":9007199254740993}`},
+		{"fractional score", `{"player":"alice","score":1.5}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := env.do(http.MethodPost, "/scores", tt.body); rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (%s)", rec.Code, rec.Body.String())
+			}
+		})
+	}
+	if n, _ := env.redis.ZMembers("leaderboard"); len(n) != 0 {
+		t.Errorf("rejected scores were stored: %v", n)
+	}
+}
+
+func TestTop_Pagination(t *testing.T) {
+	env := newTestEnv(t)
+	env.seed(t, 25)
+
+	tests := []struct {
+		query       string
+		wantPlayers []string
+		wantFirst   int64 // rank of the first entry
+		wantNext    *int64
+	}{
+		{"", []string{"p25", "p24", "p23", "p22", "p21", "p20", "p19", "p18", "p17", "p16"}, 1, ptr(10)},
+		{"?limit=3", []string{"p25", "p24", "p23"}, 1, ptr(3)},
+		{"?limit=3&offset=3", []string{"p22", "p21", "p20"}, 4, ptr(6)},
+		{"?limit=10&offset=20", []string{"p05", "p04", "p03", "p02", "p01"}, 21, nil},
+		{"?offset=25", nil, 0, nil},
+	}
+	for _, tt := range tests {
+		rec := env.do(http.MethodGet, "/top"+tt.query, "")
+		var page pageResponse
+		json.NewDecoder(rec.Body).Decode(&page)
+
+		if rec.Code != http.StatusOK || page.Total != 25 {
+			t.Errorf("GET /top%s: %d, total %d", tt.query, rec.Code, page.Total)
+		}
+		if got := players(page.Entries); !slices.Equal(got, tt.wantPlayers) {
+			t.Errorf("GET /top%s: players %v, want %v", tt.query, got, tt.wantPlayers)
+		}
+		if len(page.Entries) > 0 && page.Entries[0].Rank != tt.wantFirst {
+			t.Errorf("GET /top%s: first rank %d, want %d", tt.query, page.Entries[0].Rank, tt.wantFirst)
+		}
+		if (page.NextOffset == nil) != (tt.wantNext == nil) || (page.NextOffset != nil && *page.NextOffset != *tt.wantNext) {
+			t.Errorf("GET /top%s: next_offset %v, want %v", tt.query, page.NextOffset, tt.wantNext)
+		}
+	}
+}
+
+func TestTop_BadQuery(t *testing.T) {
+	env := newTestEnv(t)
+	for _, query := range []string{"?limit=0", "?limit=101", "?limit=ten", "?offset=-1"} {
+		if rec := env.do(http.MethodGet, "/top"+query, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET /top%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestTop_Empty(t *testing.T) {
+	env := newTestEnv(t)
+	rec := env.do(http.MethodGet, "/top", "")
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"entries":[],"total":0,"offset":0,"limit":10}` {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	env := newTestEnv(t)
+	env.seed(t, 5)
+
+	rec := env.do(http.MethodGet, "/players/p02", "")
+	var entry Entry
+	json.NewDecoder(rec.Body).Decode(&entry)
+	if rec.Code != http.StatusOK || entry != (Entry{Rank: 4, Player: "p02", Score: 20}) {
+		t.Errorf("GET /players/p02: %d %+v", rec.Code, entry)
+	}
+	if rec := env.do(http.MethodGet, "/players/nobody", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown player: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAround(t *testing.T) {
+	env := newTestEnv(t)
+	env.seed(t, 10)
+
+	tests := []struct {
+		path        string
+		wantPlayers []string
+		wantFirst   int64
+	}{
+		{"/players/p05/around?n=2", []string{"p07", "p06", "p05", "p04", "p03"}, 4},
+		{"/players/p10/around?n=2", []string{"p10", "p09", "p08"}, 1}, // the leader has no one above
+		{"/players/p01/around?n=2", []string{"p03", "p02", "p01"}, 8},
+		{"/players/p05/around?n=0", []string{"p05"}, 6},
+		{"/players/p05/around", []string{"p10", "p09", "p08", "p07", "p06", "p05", "p04", "p03", "p02", "p01"}, 1},
+	}
+	for _, tt := range tests {
+		rec := env.do(http.MethodGet, tt.path, "")
+		var resp aroundResponse
+		json.NewDecoder(rec.Body).Decode(&resp)
+		if got := players(resp.Entries); rec.Code != http.StatusOK || !slices.Equal(got, tt.wantPlayers) {
+			t.Errorf("GET %s: %d %v, want %v", tt.path, rec.Code, got, tt.wantPlayers)
+			continue
+		}
+		if resp.Entries[0].Rank != tt.wantFirst {
+			t.Errorf("GET %s: first rank %d, want %d", tt.path, resp.Entries[0].Rank, tt.wantFirst)
+		}
+	}
+
+	if rec := env.do(http.MethodGet, "/players/nobody/around", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown player: status = %d, want 404", rec.Code)
+	}
+	if rec := env.do(http.MethodGet, "/players/p05/around?n=51", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("n=51: status = %d, want 400", rec.Code)
+	}
+}
+
+// Equal scores are ranked by name, Z to A, as ZREVRANGE orders them
+func TestTies(t *testing.T) {
+	env := newTestEnv(t)
+	env.submit(t, "alice", 50)
+	env.submit(t, "bob", 50)
+
+	entries, _, err := env.server.board.Top(context.Background(), 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := players(entries); !slices.Equal(got, []string{"bob", "alice"}) {
+		t.Errorf("tie order = %v", got)
+	}
+}
+
+func TestRedisDown(t *testing.T) {
+	env := newTestEnv(t)
+	env.redis.Close()
+
+	for _, req := range []struct{ method, path, body string }{
+		{http.MethodPost, "/scores", `{"player":"alice","score":1}`},
+		{http.MethodGet, "/top", ""},
+		{http.MethodGet, "/players/alice", ""},
+		{http.MethodGet, "/players/alice/around", ""},
+	} {
+		if rec := env.do(req.method, req.path, req.body); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s with redis down: status = %d, want 503", req.method, req.path, rec.Code)
+		}
+	}
+	if !strings.Contains(env.logs.String(), "submit score for alice") {
+		t.Errorf("expected the Redis errors to be logged, got %q", env.logs.String())
+	}
+}
+
+// Concurrent submissions through a real HTTP server all land, and the
+// board ends up ordered by best score
+func TestEndToEnd(t *testing.T) {
+	env := newTestEnv(t)
+	ts := httptest.NewServer(env.server.Routes())
+	defer ts.Close()
+
+	done := make(chan error)
+	for i := range 20 {
+		go func() {
+			body := fmt.Sprintf(`{"player":"p%02d","score":%d}`, i%5, i)
+			res, err := http.Post(ts.URL+"/scores", "application/json", strings.NewReader(body))
+			if err == nil {
+				io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+			}
+			done <- err
+		}()
+	}
+	for range 20 {
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := http.Get(ts.URL + "/top")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var page pageResponse
+	json.NewDecoder(res.Body).Decode(&page)
+	want := []Entry{{1, "p04", 19}, {2, "p03", 18}, {3, "p02", 17}, {4, "p01", 16}, {5, "p00", 15}}
+	if !slices.Equal(page.Entries, want) {
+		t.Errorf("top = %+v, want %+v", page.Entries, want)
+	}
+}
+
+func ptr(n int64) *int64 { return &n }
@@ -0,0 +1,3 @@
+module github.com/owolabijunior12/learning-golang/examples/loadtest
+
+go 1.25.1
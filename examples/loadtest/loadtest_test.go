@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunStopsAfterRequestBudget(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1)%4 == 0 {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	stats, err := Run(context.Background(), server.Client(), Config{
+		URL:         server.URL,
+		Method:      http.MethodGet,
+		Concurrency: 5,
+		Requests:    100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Requests != 100 || hits.Load() != 100 {
+		t.Fatalf("requests = %d, server hits = %d, want 100 each", stats.Requests, hits.Load())
+	}
+	if stats.StatusCodes[200] != 75 || stats.StatusCodes[500] != 25 {
+		t.Errorf("status codes = %v, want 75 x 200 and 25 x 500", stats.StatusCodes)
+	}
+	if stats.Errors != 0 {
+		t.Errorf("errors = %d, want 0: HTTP 500 is a response, not a transport error", stats.Errors)
+	}
+	if len(stats.Latencies) != 100 {
+		t.Errorf("latencies = %d, want 100", len(stats.Latencies))
+	}
+}
+
+func TestRunStopsAfterDuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	stats, err := Run(context.Background(), server.Client(), Config{
+		URL:         server.URL,
+		Method:      http.MethodGet,
+		Concurrency: 4,
+		Duration:    200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("run took %s, want about 200ms", took)
+	}
+	if stats.Requests == 0 {
+		t.Fatal("no requests completed")
+	}
+	// requests cut off by the deadline are dropped, not counted as errors
+	if stats.Errors != 0 {
+		t.Errorf("errors = %d, want 0 (kinds: %v)", stats.Errors, stats.ErrorKinds)
+	}
+}
+
+func TestRunSendsMethodHeadersAndBody(t *testing.T) {
+	type seen struct{ method, header, body string }
+	got := make(chan seen, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- seen{r.Method, r.Header.Get("Content-Type"), string(body)}
+	}))
+	defer server.Close()
+
+	_, err := Run(context.Background(), server.Client(), Config{
+		URL:         server.URL,
+		Method:      http.MethodPost,
+		Body:        `{"title":"load"}`,
+		Headers:     http.Header{"Content-Type": {"application/json"}},
+		Concurrency: 1,
+		Requests:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := seen{http.MethodPost, "application/json", `{"title":"load"}`}
+	if s := <-got; s != want {
+		t.Errorf("server saw %+v, want %+v", s, want)
+	}
+}
+
+func TestRunCountsConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close() // nothing listens here any more
+
+	stats, err := Run(context.Background(), http.DefaultClient, Config{
+		URL:         url,
+		Method:      http.MethodGet,
+		Concurrency: 2,
+		Requests:    10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Errors != 10 || len(stats.Latencies) != 0 {
+		t.Fatalf("errors = %d, latencies = %d, want 10 and 0", stats.Errors, len(stats.Latencies))
+	}
+	if len(stats.ErrorKinds) != 1 {
+		t.Errorf("error kinds = %v, want one kind", stats.ErrorKinds)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{"duration", Config{URL: "http://localhost:8080", Concurrency: 1, Duration: time.Second}, true},
+		{"requests", Config{URL: "https://example.com/x", Concurrency: 1, Requests: 10}, true},
+		{"relative url", Config{URL: "/users", Concurrency: 1, Requests: 10}, false},
+		{"no scheme", Config{URL: "localhost:8080", Concurrency: 1, Requests: 10}, false},
+		{"zero workers", Config{URL: "http://localhost", Requests: 10}, false},
+		{"no limit", Config{URL: "http://localhost", Concurrency: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.validate(); (err == nil) != tt.ok {
+				t.Errorf("validate() = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func ms(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+func TestPercentile(t *testing.T) {
+	var samples []sample
+	for i := 100; i >= 1; i-- { // out of order on purpose
+		samples = append(samples, sample{latency: ms(i), status: 200})
+	}
+	s := newStats(samples, time.Second)
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, ms(1)},
+		{50, ms(50)},
+		{95, ms(95)},
+		{99, ms(99)},
+		{100, ms(100)},
+	}
+	for _, tt := range tests {
+		if got := s.Percentile(tt.p); got != tt.want {
+			t.Errorf("p%g = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+	if got := s.Mean(); got != 50500*time.Microsecond {
+		t.Errorf("mean = %s, want 50.5ms", got)
+	}
+	if got := s.RPS(); got != 100 {
+		t.Errorf("rps = %g, want 100", got)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	s := newStats([]sample{
+		{latency: ms(3), status: 200},
+		{latency: ms(3), status: 200},
+		{latency: ms(5), status: 200},
+		{latency: ms(20), status: 200},
+	}, time.Second)
+
+	want := []bucket{
+		{upper: ms(4), count: 2},
+		{upper: ms(8), count: 1},
+		{upper: ms(16), count: 0}, // gaps in the middle stay, so the axis is honest
+		{upper: ms(32), count: 1},
+	}
+	got := s.Histogram()
+	if len(got) != len(want) {
+		t.Fatalf("histogram = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	samples := []sample{
+		{latency: ms(2), status: 200},
+		{latency: ms(3), status: 200},
+		{latency: ms(9), status: 404},
+		{err: classify(context.DeadlineExceeded)},
+	}
+	var buf bytes.Buffer
+	newStats(samples, 2*time.Second).WriteReport(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Requests:      4 in 2s (2.0 req/s)",
+		"Errors:        1",
+		"Status codes:  200 x 2, 404 x 1",
+		"p50   3ms",
+		"p99   9ms",
+		"    1  timeout",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sample is one finished request.
+type sample struct {
+	latency time.Duration
+	status  int   // 0 if the request failed before a response arrived
+	err     error // transport error: refused, timeout, reset...
+}
+
+// Stats summarizes a run. Latencies is sorted ascending.
+type Stats struct {
+	Elapsed     time.Duration
+	Requests    int
+	Errors      int         // requests that got no HTTP response at all
+	StatusCodes map[int]int // responses by status code
+	ErrorKinds  map[string]int
+	Latencies   []time.Duration // successful responses only
+}
+
+func newStats(samples []sample, elapsed time.Duration) *Stats {
+	s := &Stats{
+		Elapsed:     elapsed,
+		Requests:    len(samples),
+		StatusCodes: make(map[int]int),
+		ErrorKinds:  make(map[string]int),
+		Latencies:   make([]time.Duration, 0, len(samples)),
+	}
+	for _, smp := range samples {
+		if smp.err != nil {
+			s.Errors++
+			s.ErrorKinds[smp.err.Error()]++
+			continue
+		}
+		s.StatusCodes[smp.status]++
+		s.Latencies = append(s.Latencies, smp.latency)
+	}
+	sort.Slice(s.Latencies, func(i, j int) bool { return s.Latencies[i] < s.Latencies[j] })
+	return s
+}
+
+// RPS is completed requests per second, including failures.
+func (s *Stats) RPS() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Requests) / s.Elapsed.Seconds()
+}
+
+// Percentile returns the latency that p percent of responses were at or
+// under, using the nearest-rank method: p50 is the median, p99 is the
+// latency only 1% of requests exceeded.
+func (s *Stats) Percentile(p float64) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(s.Latencies))))
+	rank = min(max(rank, 1), len(s.Latencies))
+	return s.Latencies[rank-1]
+}
+
+func (s *Stats) Mean() time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, l := range s.Latencies {
+		sum += l
+	}
+	return sum / time.Duration(len(s.Latencies))
+}
+
+// bucket is one histogram bar: responses with latency < upper (and at or
+// above the previous bucket's upper).
+type bucket struct {
+	upper time.Duration
+	count int
+}
+
+// Histogram groups latencies into buckets that double in width, starting at
+// 1ms. Latency is usually long-tailed, and doubling buckets show both the
+// fast bulk and the slow tail on one screen. Empty buckets at either end are
+// dropped.
+func (s *Stats) Histogram() []bucket {
+	if len(s.Latencies) == 0 {
+		return nil
+	}
+	var buckets []bucket
+	upper := time.Millisecond
+	i := 0
+	for i < len(s.Latencies) {
+		b := bucket{upper: upper}
+		for i < len(s.Latencies) && s.Latencies[i] < upper {
+			b.count++
+			i++
+		}
+		buckets = append(buckets, b)
+		upper *= 2
+	}
+	for len(buckets) > 0 && buckets[0].count == 0 {
+		buckets = buckets[1:]
+	}
+	return buckets
+}
+
+// WriteReport prints the summary, percentiles and histogram.
+func (s *Stats) WriteReport(w io.Writer) {
+	fmt.Fprintf(w, "Requests:      %d in %s (%.1f req/s)\n", s.Requests, s.Elapsed.Round(time.Millisecond), s.RPS())
+	fmt.Fprintf(w, "Errors:        %d\n", s.Errors)
+
+	codes := make([]int, 0, len(s.StatusCodes))
+	for code := range s.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	var parts []string
+	for _, code := range codes {
+		parts = append(parts, fmt.Sprintf("%d x %d", code, s.StatusCodes[code]))
+	}
+	fmt.Fprintf(w, "Status codes:  %s\n", strings.Join(parts, ", "))
+
+	if len(s.Latencies) == 0 {
+		fmt.Fprintln(w, "\nNo successful responses.")
+	} else {
+		fmt.Fprintln(w, "\nLatency:")
+		fmt.Fprintf(w, "  min   %s\n", round(s.Latencies[0]))
+		fmt.Fprintf(w, "  mean  %s\n", round(s.Mean()))
+		for _, p := range []float64{50, 95, 99} {
+			fmt.Fprintf(w, "  p%-4g %s\n", p, round(s.Percentile(p)))
+		}
+		fmt.Fprintf(w, "  max   %s\n", round(s.Latencies[len(s.Latencies)-1]))
+
+		fmt.Fprintln(w, "\nHistogram:")
+		const width = 40
+		buckets := s.Histogram()
+		most := 0
+		for _, b := range buckets {
+			most = max(most, b.count)
+		}
+		for _, b := range buckets {
+			bar := strings.Repeat("#", (b.count*width+most-1)/most) // round up so non-empty buckets show
+			fmt.Fprintf(w, "  < %-8s %7d  %s\n", b.upper, b.count, bar)
+		}
+	}
+
+	if len(s.ErrorKinds) > 0 {
+		fmt.Fprintln(w, "\nErrors by kind:")
+		kinds := make([]string, 0, len(s.ErrorKinds))
+		for k := range s.ErrorKinds {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		for _, k := range kinds {
+			fmt.Fprintf(w, "  %5d  %s\n", s.ErrorKinds[k], k)
+		}
+	}
+}
+
+// round keeps durations readable: 1.234567ms -> 1.23ms
+func round(d time.Duration) time.Duration {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond)
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond)
+	default:
+		return d.Round(time.Microsecond)
+	}
+}
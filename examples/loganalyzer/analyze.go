@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/pkg/pool"
+)
+
+// PathStats aggregates every request to one path.
+type PathStats struct {
+	Hits         int
+	ServerErrors int // 5xx responses
+	Bytes        int64
+	Timed        int // hits whose line had a request time
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// Malformed records a line that could not be parsed.
+type Malformed struct {
+	File string `json:"file,omitempty"`
+	Line int    `json:"line"` // 1-based line number in File
+	Text string `json:"text"` // truncated to keep reports small
+}
+
+// maxMalformedSamples is how many bad lines a Summary keeps as examples.
+const maxMalformedSamples = 5
+
+// Summary is the result of analyzing one or more logs.
+type Summary struct {
+	Lines     int
+	Parsed    int
+	Malformed int
+	Samples   []Malformed // the first few malformed lines
+	Bytes     int64
+	First     time.Time
+	Last      time.Time
+	Status    map[int]int
+	Paths     map[string]*PathStats
+	Latencies []time.Duration // from lines that have a request time; sorted by Finish
+}
+
+func newSummary() *Summary {
+	return &Summary{Status: make(map[int]int), Paths: make(map[string]*PathStats)}
+}
+
+func (s *Summary) add(e Entry) {
+	s.Parsed++
+	s.Bytes += e.Bytes
+	s.Status[e.Status]++
+	if s.First.IsZero() || e.Time.Before(s.First) {
+		s.First = e.Time
+	}
+	if e.Time.After(s.Last) {
+		s.Last = e.Time
+	}
+
+	p := s.Paths[e.Path]
+	if p == nil {
+		p = &PathStats{}
+		s.Paths[e.Path] = p
+	}
+	p.Hits++
+	p.Bytes += e.Bytes
+	if e.Status >= 500 {
+		p.ServerErrors++
+	}
+	if e.Latency > 0 {
+		p.Timed++
+		p.TotalLatency += e.Latency
+		p.MaxLatency = max(p.MaxLatency, e.Latency)
+		s.Latencies = append(s.Latencies, e.Latency)
+	}
+}
+
+func (s *Summary) addMalformed(lineNo int, text string) {
+	s.Malformed++
+	if len(s.Samples) < maxMalformedSamples {
+		if len(text) > 120 {
+			text = text[:120] + "..."
+		}
+		s.Samples = append(s.Samples, Malformed{Line: lineNo, Text: text})
+	}
+}
+
+// Merge folds other into s. Merging is what lets every batch be aggregated
+// on its own, with no shared map and so no lock, and combined at the end.
+func (s *Summary) Merge(other *Summary) {
+	s.Lines += other.Lines
+	s.Parsed += other.Parsed
+	s.Malformed += other.Malformed
+	s.Bytes += other.Bytes
+	if !other.First.IsZero() && (s.First.IsZero() || other.First.Before(s.First)) {
+		s.First = other.First
+	}
+	if other.Last.After(s.Last) {
+		s.Last = other.Last
+	}
+	for code, n := range other.Status {
+		s.Status[code] += n
+	}
+	for path, o := range other.Paths {
+		p := s.Paths[path]
+		if p == nil {
+			p = &PathStats{}
+			s.Paths[path] = p
+		}
+		p.Hits += o.Hits
+		p.ServerErrors += o.ServerErrors
+		p.Bytes += o.Bytes
+		p.Timed += o.Timed
+		p.TotalLatency += o.TotalLatency
+		p.MaxLatency = max(p.MaxLatency, o.MaxLatency)
+	}
+	s.Latencies = append(s.Latencies, other.Latencies...)
+
+	s.Samples = append(s.Samples, other.Samples...)
+	if len(s.Samples) > maxMalformedSamples {
+		s.Samples = s.Samples[:maxMalformedSamples]
+	}
+}
+
+// Finish sorts the latencies so Percentile can index into them. Call it
+// once, after the last Merge.
+func (s *Summary) Finish() {
+	sort.Slice(s.Latencies, func(i, j int) bool { return s.Latencies[i] < s.Latencies[j] })
+}
+
+// Percentile returns the nearest-rank percentile of the request times.
+func (s *Summary) Percentile(p float64) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(s.Latencies))))
+	rank = min(max(rank, 1), len(s.Latencies))
+	return s.Latencies[rank-1]
+}
+
+// batch is a run of consecutive lines. Sending 1000 lines per channel
+// operation instead of one keeps the channel from becoming the bottleneck.
+type batch struct {
+	firstLine int
+	lines     []string
+}
+
+const batchSize = 1000
+
+// Analyze reads r line by line and parses the lines on workers goroutines.
+//
+// One goroutine reads (a bufio.Scanner is not safe to share) and submits
+// batches to a worker pool from pkg/pool; each batch is parsed into a
+// Summary of its own, and this goroutine merges them as they come back. If
+// ctx is cancelled the reader stops early and Analyze returns what was
+// parsed so far along with ctx.Err().
+func Analyze(ctx context.Context, r io.Reader, workers int) (*Summary, error) {
+	p := pool.New(ctx, func(ctx context.Context, b batch) (*Summary, error) {
+		return parseBatch(b), nil
+	}, pool.Options{Workers: max(workers, 1)})
+
+	var readErr error
+	go func() {
+		defer p.Close()
+		sc := bufio.NewScanner(r)
+		// The default 64 KiB line limit is too small for some user agents
+		// and query strings; allow up to 1 MiB before giving up
+		sc.Buffer(make([]byte, 64*1024), 1024*1024)
+		b := batch{firstLine: 1}
+		lineNo := 0
+		for sc.Scan() {
+			lineNo++
+			b.lines = append(b.lines, sc.Text())
+			if len(b.lines) == batchSize {
+				if p.Submit(ctx, b) != nil {
+					return
+				}
+				b = batch{firstLine: lineNo + 1}
+			}
+		}
+		readErr = sc.Err()
+		if len(b.lines) > 0 {
+			p.Submit(ctx, b)
+		}
+	}()
+
+	// Batches finish in any order, so keep every batch's bad lines and the
+	// earliest of them, rather than those of whichever batch came back first
+	total := newSummary()
+	var samples []Malformed
+	var jobErr error
+	for res := range p.Results() {
+		if res.Err != nil {
+			// Cancelled before it ran, or a panic in the parser
+			if jobErr == nil && ctx.Err() == nil {
+				jobErr = fmt.Errorf("lines %d-%d: %w", res.In.firstLine, res.In.firstLine+len(res.In.lines)-1, res.Err)
+			}
+			continue
+		}
+		samples = append(samples, res.Out.Samples...)
+		sort.Slice(samples, func(i, j int) bool { return samples[i].Line < samples[j].Line })
+		samples = samples[:min(len(samples), maxMalformedSamples)]
+		res.Out.Samples = nil
+		total.Merge(res.Out)
+	}
+	total.Samples = samples
+	// Results is closed after the reader has closed the pool, so readErr is
+	// safe to read
+	if readErr != nil {
+		return total, readErr
+	}
+	if jobErr != nil {
+		return total, jobErr
+	}
+	return total, ctx.Err()
+}
+
+// parseBatch parses the lines of b into a Summary of their own.
+func parseBatch(b batch) *Summary {
+	s := newSummary()
+	for j, line := range b.lines {
+		s.Lines++
+		if e, err := ParseLine(line); err == nil {
+			s.add(e)
+		} else {
+			s.addMalformed(b.firstLine+j, line)
+		}
+	}
+	return s
+}
+
+// AnalyzeFile analyzes the log at path; "-" reads standard input. Files
+// ending in .gz are decompressed on the fly, so rotated logs (access.log.2.gz)
+// can be read without unpacking them first.
+func AnalyzeFile(ctx context.Context, path string, workers int) (*Summary, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+		if strings.HasSuffix(path, ".gz") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+	}
+
+	s, err := Analyze(ctx, r, workers)
+	for i := range s.Samples {
+		s.Samples[i].File = path
+	}
+	if err != nil && ctx.Err() == nil {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	return s, err
+}
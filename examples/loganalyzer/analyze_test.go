@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	line := `10.0.0.5 - alice [10/Oct/2026:13:55:36 +0200] "GET /users?id=1 HTTP/1.1" 200 512 "https://example.com/" "curl/8.5.0" 0.012`
+	e, err := ParseLine(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Entry{
+		RemoteAddr: "10.0.0.5",
+		User:       "alice",
+		Time:       time.Date(2026, 10, 10, 11, 55, 36, 0, time.UTC),
+		Method:     "GET",
+		Path:       "/users",
+		Status:     200,
+		Bytes:      512,
+		Referer:    "https://example.com/",
+		UserAgent:  "curl/8.5.0",
+		Latency:    12 * time.Millisecond,
+	}
+	if !e.Time.Equal(want.Time) {
+		t.Errorf("time = %s, want %s", e.Time, want.Time)
+	}
+	e.Time = want.Time
+	if e != want {
+		t.Errorf("got  %+v\nwant %+v", e, want)
+	}
+}
+
+func TestParseLineVariants(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		ok   bool
+	}{
+		{"common format", `127.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET / HTTP/1.0" 304 -`, true},
+		{"combined without time", `127.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET / HTTP/1.1" 200 10 "-" "-"`, true},
+		{"trailing spaces", `127.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET / HTTP/1.1" 200 10   `, true},
+		{"empty", ``, false},
+		{"truncated", `127.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET / HT`, false},
+		{"bad status", `127.0.0.1 - - [10/Oct/2026:13:55:36 +0000] "GET / HTTP/1.1" 2OO 10`, false},
+		{"bad date", `127.0.0.1 - - [31/Feb/2026:13:55:36 +0000] "GET / HTTP/1.1" 200 10`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLine(tt.line)
+			if (err == nil) != tt.ok {
+				t.Errorf("ParseLine() error = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+// logLines builds n lines cycling through a few paths and statuses, with a
+// malformed line at each index in bad (0-based).
+func logLines(n int, bad ...int) string {
+	isBad := make(map[int]bool)
+	for _, i := range bad {
+		isBad[i] = true
+	}
+	paths := []string{"/", "/users", "/users", "/search?q=go"}
+	var b strings.Builder
+	for i := range n {
+		if isBad[i] {
+			fmt.Fprintf(&b, "garbage %d\n", i+1)
+			continue
+		}
+		status := 200
+		if i%10 == 0 {
+			status = 500
+		}
+		fmt.Fprintf(&b, `10.0.0.1 - - [10/Oct/2026:13:%02d:%02d +0000] "GET %s HTTP/1.1" %d 100 "-" "test" 0.%03d`+"\n",
+			i/60%60, i%60, paths[i%len(paths)], status, i%100+1)
+	}
+	return b.String()
+}
+
+func TestAnalyzeAcrossWorkers(t *testing.T) {
+	// Several batches and workers, with bad lines in different batches
+	const n = 3500
+	bad := []int{3400, 7, 2100, 1500, 999, 3000}
+	s, err := Analyze(context.Background(), strings.NewReader(logLines(n, bad...)), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Finish()
+
+	if s.Lines != n || s.Parsed != n-len(bad) || s.Malformed != len(bad) {
+		t.Fatalf("lines/parsed/malformed = %d/%d/%d, want %d/%d/%d",
+			s.Lines, s.Parsed, s.Malformed, n, n-len(bad), len(bad))
+	}
+	if s.Bytes != int64(100*s.Parsed) {
+		t.Errorf("bytes = %d, want %d", s.Bytes, 100*s.Parsed)
+	}
+	if got := s.Paths["/users"].Hits; got != n/2 { // none of the bad lines are /users
+		t.Errorf("/users hits = %d, want %d", got, n/2)
+	}
+	if _, ok := s.Paths["/search?q=go"]; ok {
+		t.Error("query string was not stripped")
+	}
+	if s.Status[500]+s.Status[200] != s.Parsed {
+		t.Errorf("status counts %v do not add up to %d", s.Status, s.Parsed)
+	}
+
+	// The earliest bad lines, whichever worker saw them
+	var lines []int
+	for _, m := range s.Samples {
+		lines = append(lines, m.Line)
+	}
+	if fmt.Sprint(lines) != "[8 1000 1501 2101 3001]" {
+		t.Errorf("sample lines = %v, want [8 1000 1501 2101 3001]", lines)
+	}
+
+	if got := s.Percentile(50); got < 50*time.Millisecond || got > 51*time.Millisecond {
+		t.Errorf("p50 = %s, want 50-51ms", got)
+	}
+	if got := s.Percentile(100); got != 100*time.Millisecond {
+		t.Errorf("max = %s, want 100ms", got)
+	}
+}
+
+func TestAnalyzeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s, err := Analyze(ctx, strings.NewReader(logLines(50000)), 2)
+	if err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if s == nil || s.Lines == 50000 {
+		t.Errorf("want a partial summary, got %+v", s)
+	}
+}
+
+func TestAnalyzeFileGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log.1.gz")
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(logLines(50, 9)))
+	gz.Close()
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := AnalyzeFile(context.Background(), path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Lines != 50 || s.Malformed != 1 {
+		t.Fatalf("lines = %d, malformed = %d, want 50 and 1", s.Lines, s.Malformed)
+	}
+	if m := s.Samples[0]; m.File != path || m.Line != 10 {
+		t.Errorf("sample = %+v, want %s line 10", m, path)
+	}
+
+	if _, err := AnalyzeFile(context.Background(), filepath.Join(t.TempDir(), "missing.log"), 2); err == nil {
+		t.Error("want an error for a missing file")
+	}
+}
+
+func TestReport(t *testing.T) {
+	s, err := Analyze(context.Background(), strings.NewReader(logLines(40, 5)), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Finish()
+	r := NewReport(s, 2)
+
+	if len(r.TopPaths) != 2 || r.Paths != 3 {
+		t.Fatalf("top paths = %+v of %d, want 2 of 3", r.TopPaths, r.Paths)
+	}
+	if p := r.TopPaths[0]; p.Path != "/users" || p.Hits != 19 || p.ServerErrors != 2 {
+		t.Errorf("top path = %+v, want /users with 19 hits, 2 5xx", p)
+	}
+	if r.Status[0].Code != 200 || r.Status[1].Code != 500 {
+		t.Errorf("status = %+v, want sorted by code", r.Status)
+	}
+
+	var table bytes.Buffer
+	if err := WriteReport(&table, "table", r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"40 (39 parsed, 1 malformed)", "Top 2 of 3 paths", "/users", ":6: garbage 6"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table missing %q:\n%s", want, table.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := WriteReport(&js, "json", r); err != nil {
+		t.Fatal(err)
+	}
+	var back Report
+	if err := json.Unmarshal(js.Bytes(), &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Parsed != 39 || back.Latency == nil || back.TopPaths[0].Path != "/users" {
+		t.Errorf("json round trip = %+v", back)
+	}
+
+	if err := WriteReport(&js, "xml", r); err == nil {
+		t.Error("want an error for an unknown format")
+	}
+}
+
+func TestHumanBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:       "0 B",
+		1023:    "1023 B",
+		1536:    "1.5 KiB",
+		5 << 20: "5.0 MiB",
+		3 << 30: "3.0 GiB",
+	}
+	for n, want := range tests {
+		if got := humanBytes(n); got != want {
+			t.Errorf("humanBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Command loganalyzer summarizes Apache/nginx access logs: requests by status
+// code, the busiest paths, bytes sent and latency percentiles.
+//
+// It is courses 4, 5 and 20 applied: a bufio.Scanner feeding batches of
+// lines to a pool of parser goroutines, a regexp per line, and gzip readers
+// for rotated logs:
+//
+//	go run ./examples/loganalyzer examples/loganalyzer/testdata/access.log
+//	go run ./examples/loganalyzer -format json -top 20 /var/log/nginx/access.log*
+//	zcat access.log.3.gz | go run ./examples/loganalyzer -
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+)
+
+func main() {
+	workers := flag.Int("workers", runtime.NumCPU(), "parser goroutines")
+	format := flag.String("format", "table", "report format: table or json")
+	top := flag.Int("top", 10, "how many paths to list (0 = all)")
+	output := flag.String("o", "", "write the report to a file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: loganalyzer [flags] <file>... (- for stdin)\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger := log.New(os.Stderr, "[loganalyzer] ", log.LstdFlags)
+	if err := run(flag.Args(), *workers, *top, *format, *output, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(files []string, workers, top int, format, output string, logger *log.Logger) error {
+	if format != "table" && format != "json" {
+		return fmt.Errorf("unknown report format %q (want table or json)", format)
+	}
+
+	// Ctrl+C stops reading and reports what was parsed so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	began := time.Now()
+	total := newSummary()
+	for _, path := range files {
+		s, err := AnalyzeFile(ctx, path, workers)
+		if s != nil {
+			total.Merge(s)
+		}
+		if errors.Is(err, context.Canceled) {
+			logger.Printf("interrupted; reporting partial results")
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	total.Finish()
+	logger.Printf("read %d lines from %d file(s) in %s", total.Lines, len(files), time.Since(began).Round(time.Millisecond))
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return WriteReport(w, format, NewReport(total, top))
+}
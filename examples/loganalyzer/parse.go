@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is one parsed access-log line.
+type Entry struct {
+	RemoteAddr string
+	User       string
+	Time       time.Time
+	Method     string
+	Path       string // without the query string
+	Status     int
+	Bytes      int64
+	Referer    string
+	UserAgent  string
+	Latency    time.Duration // 0 if the line has no request time
+}
+
+// logLine matches the Apache/nginx "combined" format, which is "common" plus
+// referer and user agent, with an optional request time in seconds at the
+// end (nginx's $request_time):
+//
+//	127.0.0.1 - alice [10/Oct/2026:13:55:36 +0000] "GET /users?id=1 HTTP/1.1" 200 512 "-" "curl/8.5" 0.012
+//
+// The regexp is compiled once at package level. MustCompile panics on a bad
+// pattern, which is what you want for a constant: the program can't start.
+var logLine = regexp.MustCompile(
+	`^(\S+) \S+ (\S+) \[([^\]]+)\] "(\S+) (\S+)[^"]*" (\d{3}) (\d+|-)` +
+		`(?: "([^"]*)" "([^"]*)")?(?: (\d+(?:\.\d+)?))?\s*$`)
+
+const timeLayout = "02/Jan/2006:15:04:05 -0700"
+
+var errMalformed = errors.New("line does not match the combined log format")
+
+// ParseLine parses one line. Every malformed line returns errMalformed;
+// callers count them rather than stop, because one garbled line in a
+// gigabyte of logs shouldn't throw the rest away.
+func ParseLine(line string) (Entry, error) {
+	m := logLine.FindStringSubmatch(line)
+	if m == nil {
+		return Entry{}, errMalformed
+	}
+	t, err := time.Parse(timeLayout, m[3])
+	if err != nil {
+		return Entry{}, errMalformed
+	}
+	status, _ := strconv.Atoi(m[6]) // the regexp only lets three digits through
+	e := Entry{
+		RemoteAddr: m[1],
+		User:       dash(m[2]),
+		Time:       t,
+		Method:     m[4],
+		Path:       m[5],
+		Status:     status,
+		Referer:    dash(m[8]),
+		UserAgent:  dash(m[9]),
+	}
+	// /users?id=1 and /users?id=2 are the same endpoint
+	if i := strings.IndexByte(e.Path, '?'); i >= 0 {
+		e.Path = e.Path[:i]
+	}
+	if m[7] != "-" {
+		e.Bytes, _ = strconv.ParseInt(m[7], 10, 64)
+	}
+	if m[10] != "" {
+		secs, _ := strconv.ParseFloat(m[10], 64)
+		e.Latency = time.Duration(secs * float64(time.Second))
+	}
+	return e, nil
+}
+
+// dash turns the log format's "-" placeholder into an empty string.
+func dash(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is the Summary shaped for output: maps become sorted slices and
+// durations become milliseconds, so the JSON is stable and easy to consume
+// from other tools.
+type Report struct {
+	Lines     int           `json:"lines"`
+	Parsed    int           `json:"parsed"`
+	Malformed int           `json:"malformed"`
+	Samples   []Malformed   `json:"malformed_samples,omitempty"`
+	Bytes     int64         `json:"bytes"`
+	First     time.Time     `json:"first,omitzero"`
+	Last      time.Time     `json:"last,omitzero"`
+	Status    []StatusCount `json:"status"`
+	Latency   *LatencyStats `json:"latency_ms,omitempty"` // nil if no line had a request time
+	TopPaths  []PathReport  `json:"top_paths"`
+	Paths     int           `json:"distinct_paths"`
+}
+
+type StatusCount struct {
+	Code  int `json:"code"`
+	Count int `json:"count"`
+}
+
+type LatencyStats struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+type PathReport struct {
+	Path         string  `json:"path"`
+	Hits         int     `json:"hits"`
+	ServerErrors int     `json:"server_errors"`
+	Bytes        int64   `json:"bytes"`
+	MeanMS       float64 `json:"mean_ms,omitempty"`
+	MaxMS        float64 `json:"max_ms,omitempty"`
+}
+
+// NewReport builds a report with the top paths by hits. s must be Finished.
+func NewReport(s *Summary, top int) Report {
+	r := Report{
+		Lines:     s.Lines,
+		Parsed:    s.Parsed,
+		Malformed: s.Malformed,
+		Samples:   s.Samples,
+		Bytes:     s.Bytes,
+		First:     s.First,
+		Last:      s.Last,
+		Paths:     len(s.Paths),
+		Status:    []StatusCount{},
+		TopPaths:  []PathReport{},
+	}
+	for code, n := range s.Status {
+		r.Status = append(r.Status, StatusCount{code, n})
+	}
+	sort.Slice(r.Status, func(i, j int) bool { return r.Status[i].Code < r.Status[j].Code })
+
+	if n := len(s.Latencies); n > 0 {
+		r.Latency = &LatencyStats{
+			P50: ms(s.Percentile(50)),
+			P95: ms(s.Percentile(95)),
+			P99: ms(s.Percentile(99)),
+			Max: ms(s.Latencies[n-1]),
+		}
+	}
+
+	for path, p := range s.Paths {
+		pr := PathReport{Path: path, Hits: p.Hits, ServerErrors: p.ServerErrors, Bytes: p.Bytes, MaxMS: ms(p.MaxLatency)}
+		if p.Timed > 0 {
+			pr.MeanMS = ms(p.TotalLatency / time.Duration(p.Timed))
+		}
+		r.TopPaths = append(r.TopPaths, pr)
+	}
+	// Ties broken by path, so the same log always gives the same report
+	sort.Slice(r.TopPaths, func(i, j int) bool {
+		a, b := r.TopPaths[i], r.TopPaths[j]
+		if a.Hits != b.Hits {
+			return a.Hits > b.Hits
+		}
+		return a.Path < b.Path
+	})
+	if top > 0 && len(r.TopPaths) > top {
+		r.TopPaths = r.TopPaths[:top]
+	}
+	return r
+}
+
+// ms converts to milliseconds rounded to 0.01.
+func ms(d time.Duration) float64 {
+	return float64(d.Round(10*time.Microsecond)) / float64(time.Millisecond)
+}
+
+// WriteReport writes r as "table" or "json".
+func WriteReport(w io.Writer, format string, r Report) error {
+	switch format {
+	case "table":
+		return writeTable(w, r)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	default:
+		return fmt.Errorf("unknown report format %q (want table or json)", format)
+	}
+}
+
+func writeTable(w io.Writer, r Report) error {
+	// tabwriter pads tab-separated cells so columns line up
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Lines:\t%d (%d parsed, %d malformed)\n", r.Lines, r.Parsed, r.Malformed)
+	fmt.Fprintf(tw, "Bytes sent:\t%s\n", humanBytes(r.Bytes))
+	if !r.First.IsZero() {
+		fmt.Fprintf(tw, "Time range:\t%s - %s (%s)\n",
+			r.First.Format(time.DateTime), r.Last.Format(time.DateTime), r.Last.Sub(r.First))
+	}
+	if r.Latency != nil {
+		fmt.Fprintf(tw, "Latency:\tp50 %gms  p95 %gms  p99 %gms  max %gms\n",
+			r.Latency.P50, r.Latency.P95, r.Latency.P99, r.Latency.Max)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\nStatus codes:")
+	// AlignRight lines numbers up on their last digit. It needs a tab after
+	// the last cell too, or that cell isn't padded
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, s := range r.Status {
+		fmt.Fprintf(tw, "  %d\t%d\t%s\t\n", s.Code, s.Count, percent(s.Count, r.Parsed))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nTop %d of %d paths:\n", len(r.TopPaths), r.Paths)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  PATH\tHITS\t5XX\tBYTES\tMEAN\tMAX")
+	for _, p := range r.TopPaths {
+		fmt.Fprintf(tw, "  %s\t%d\t%d\t%s\t%s\t%s\n",
+			p.Path, p.Hits, p.ServerErrors, humanBytes(p.Bytes), msCell(p.MeanMS), msCell(p.MaxMS))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Samples) > 0 {
+		fmt.Fprintln(w, "\nMalformed lines:")
+		for _, m := range r.Samples {
+			fmt.Fprintf(w, "  %s:%d: %s\n", m.File, m.Line, m.Text)
+		}
+	}
+	return nil
+}
+
+func percent(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(100*float64(n)/float64(total), 'f', 1, 64) + "%"
+}
+
+func msCell(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + "ms"
+}
+
+// humanBytes formats n with a binary unit: 1536 -> "1.5 KiB".
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notifier is the strategy interface (course 12, pattern 5): one way of
+// delivering an event. The dispatcher picks strategies by name per event
+// and never needs to know how any of them work.
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, e Event) error
+}
+
+// PermanentError marks a failure that retrying cannot fix, like a malformed
+// address or a webhook answering 400. The dispatcher dead-letters it at
+// once instead of waiting through every retry.
+type PermanentError struct{ Err error }
+
+func (e *PermanentError) Error() string { return "permanent: " + e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+func permanent(err error) error { return &PermanentError{Err: err} }
+
+// ConsoleNotifier prints events, one line each.
+type ConsoleNotifier struct {
+	mu  sync.Mutex // workers share w; keep their lines from interleaving
+	out io.Writer
+}
+
+func NewConsoleNotifier(out io.Writer) *ConsoleNotifier {
+	return &ConsoleNotifier{out: out}
+}
+
+func (c *ConsoleNotifier) Name() string { return "console" }
+
+func (c *ConsoleNotifier) Send(ctx context.Context, e Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fprintf(c.out, "[%s] %s: %s\n", e.Type, e.ID, e.Subject)
+	return err
+}
+
+// EmailNotifier is a stub: instead of talking SMTP it writes each message
+// as an .eml file into an outbox folder, which any mail client can open.
+// Swapping in a real SMTP strategy wouldn't change anything else.
+type EmailNotifier struct {
+	outbox string
+	from   string
+}
+
+func NewEmailNotifier(outbox, from string) (*EmailNotifier, error) {
+	if err := os.MkdirAll(outbox, 0o755); err != nil {
+		return nil, err
+	}
+	return &EmailNotifier{outbox: outbox, from: from}, nil
+}
+
+func (m *EmailNotifier) Name() string { return "email" }
+
+func (m *EmailNotifier) Send(ctx context.Context, e Event) error {
+	to, err := mail.ParseAddress(e.To)
+	if err != nil {
+		return permanent(fmt.Errorf("recipient %q: %w", e.To, err))
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", e.Subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "X-Event-Id: %s\r\n\r\n", e.ID)
+	msg.WriteString(strings.ReplaceAll(e.Body, "\n", "\r\n"))
+
+	// The event ID is the file name, so a retried send overwrites instead
+	// of mailing the same person twice
+	name := filepath.Join(m.outbox, safeName(e.ID)+".eml")
+	return os.WriteFile(name, msg.Bytes(), 0o644)
+}
+
+// safeName keeps IDs like "../x" from escaping the outbox.
+func safeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// WebhookNotifier POSTs the event as JSON to a URL.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string, client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: client}
+}
+
+func (w *WebhookNotifier) Name() string { return "webhook" }
+
+func (w *WebhookNotifier) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return permanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Receivers can use this to ignore a delivery they have already seen
+	req.Header.Set("Idempotency-Key", e.ID)
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err // network trouble: worth retrying
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	switch {
+	case res.StatusCode < 300:
+		return nil
+	// 429 and 5xx mean "not now"; other 4xx mean "never", so stop trying
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500:
+		return fmt.Errorf("webhook answered %s", res.Status)
+	default:
+		return permanent(fmt.Errorf("webhook answered %s", res.Status))
+	}
+}
+
+// NewNotifier is a factory (course 12, pattern 6): it builds a strategy
+// from its name, so the set of channels can come from flags or config.
+func NewNotifier(kind string, cfg ChannelConfig) (Notifier, error) {
+	switch kind {
+	case "console":
+		return NewConsoleNotifier(cfg.Console), nil
+	case "email":
+		return NewEmailNotifier(cfg.Outbox, cfg.From)
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, errors.New("webhook channel needs a URL")
+		}
+		return NewWebhookNotifier(cfg.WebhookURL, cfg.Client), nil
+	default:
+		return nil, fmt.Errorf("unknown channel %q", kind)
+	}
+}
+
+// ChannelConfig holds what the factory needs to build each kind.
+type ChannelConfig struct {
+	Console    io.Writer
+	Outbox     string
+	From       string
+	WebhookURL string
+	Client     *http.Client
+}
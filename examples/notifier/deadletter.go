@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// DeadLetter is one delivery that failed for good.
+type DeadLetter struct {
+	Event    Event     `json:"event"`
+	Channel  string    `json:"channel"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DeadLetterFile appends failed deliveries to a JSON-lines file. Nothing is
+// lost: someone can read the file, fix the cause, and feed the events back
+// in (each line's "event" is a valid input event).
+type DeadLetterFile struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func OpenDeadLetterFile(path string) (*DeadLetterFile, error) {
+	// O_APPEND: each write lands at the end, even across restarts
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &DeadLetterFile{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (d *DeadLetterFile) Add(dl DeadLetter) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.enc.Encode(dl) // Encode writes one line, newline included
+}
+
+func (d *DeadLetterFile) Close() error {
+	return d.f.Close()
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Route sends events whose type starts with Prefix to Channels.
+type Route struct {
+	Prefix   string
+	Channels []string
+}
+
+// Router picks the channels for an event: the event's own Channels if it
+// names any, else the first matching route, else Fallback.
+type Router struct {
+	Routes   []Route
+	Fallback []string
+}
+
+func (r Router) Channels(e Event) []string {
+	if len(e.Channels) > 0 {
+		return e.Channels
+	}
+	for _, route := range r.Routes {
+		if strings.HasPrefix(e.Type, route.Prefix) {
+			return route.Channels
+		}
+	}
+	return r.Fallback
+}
+
+// DeadLetterSink receives deliveries that failed for good.
+type DeadLetterSink interface {
+	Add(DeadLetter) error
+}
+
+// Stats counts deliveries. One event sent to two channels is two
+// deliveries.
+type Stats struct {
+	Events     atomic.Int64
+	Delivered  atomic.Int64
+	Retried    atomic.Int64 // deliveries that needed more than one attempt
+	DeadLetter atomic.Int64
+	BadEvents  atomic.Int64
+}
+
+// Dispatcher consumes a queue and delivers every event to its channels.
+// Its dependencies are all interfaces passed in (course 12's dependency
+// injection), which is what lets the tests swap in fakes for every one.
+type Dispatcher struct {
+	notifiers map[string]Notifier
+	router    Router
+	retry     Retry
+	dead      DeadLetterSink
+	logger    *log.Logger
+	Stats     Stats
+}
+
+func NewDispatcher(notifiers []Notifier, router Router, retry Retry, dead DeadLetterSink, logger *log.Logger) *Dispatcher {
+	byName := make(map[string]Notifier, len(notifiers))
+	for _, n := range notifiers {
+		byName[n.Name()] = n
+	}
+	return &Dispatcher{notifiers: byName, router: router, retry: retry, dead: dead, logger: logger}
+}
+
+// Run starts workers goroutines that receive from q until it is drained
+// (io.EOF) or ctx is cancelled. An event that is mid-delivery when ctx is
+// cancelled is dead-lettered rather than lost.
+func (d *Dispatcher) Run(ctx context.Context, q Queue, workers int) error {
+	workers = max(workers, 1)
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				e, err := q.Receive(ctx)
+				var bad *BadEventError
+				switch {
+				case errors.As(err, &bad):
+					d.Stats.BadEvents.Add(1)
+					d.logger.Printf("skipping bad event: %v", err)
+					continue
+				case errors.Is(err, io.EOF), ctx.Err() != nil:
+					return
+				case err != nil:
+					errs <- err
+					return
+				}
+				d.Stats.Events.Add(1)
+				d.dispatch(ctx, e)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs // nil if no worker failed
+}
+
+// dispatch delivers e to each of its channels in turn. A failure on one
+// channel doesn't stop the others.
+func (d *Dispatcher) dispatch(ctx context.Context, e Event) {
+	for _, name := range d.router.Channels(e) {
+		n, ok := d.notifiers[name]
+		if !ok {
+			d.deadLetter(e, name, 0, errors.New("no such channel"))
+			continue
+		}
+		attempts, err := d.retry.Do(ctx, func() error { return n.Send(ctx, e) })
+		if attempts > 1 {
+			d.Stats.Retried.Add(1)
+		}
+		if err != nil {
+			d.deadLetter(e, name, attempts, err)
+			continue
+		}
+		d.Stats.Delivered.Add(1)
+	}
+}
+
+func (d *Dispatcher) deadLetter(e Event, channel string, attempts int, err error) {
+	d.Stats.DeadLetter.Add(1)
+	d.logger.Printf("dead-lettering %s via %s after %d attempt(s): %v", e.ID, channel, attempts, err)
+	dl := DeadLetter{Event: e, Channel: channel, Error: err.Error(), Attempts: attempts, FailedAt: time.Now()}
+	if err := d.dead.Add(dl); err != nil {
+		// Last resort: the log line above still has the event ID
+		d.logger.Printf("could not write dead letter for %s: %v", e.ID, err)
+	}
+}
+
+// Logged is a decorator: a Notifier that wraps another and logs every send.
+// It is the middleware pattern from course 12 applied to an interface
+// instead of http.Handler.
+func Logged(n Notifier, logger *log.Logger) Notifier {
+	return &loggedNotifier{Notifier: n, logger: logger}
+}
+
+type loggedNotifier struct {
+	Notifier // Name comes from the wrapped notifier
+	logger   *log.Logger
+}
+
+func (l *loggedNotifier) Send(ctx context.Context, e Event) error {
+	start := time.Now()
+	err := l.Notifier.Send(ctx, e)
+	status := "ok"
+	if err != nil {
+		status = err.Error()
+	}
+	l.logger.Printf("%s %s %s (%s)", l.Name(), e.ID, status, time.Since(start).Round(time.Microsecond))
+	return err
+}
+
+// WithTimeout is a decorator that bounds each send, so one slow webhook
+// can't hold a worker forever.
+func WithTimeout(n Notifier, d time.Duration) Notifier {
+	return &timeoutNotifier{Notifier: n, timeout: d}
+}
+
+type timeoutNotifier struct {
+	Notifier
+	timeout time.Duration
+}
+
+func (t *timeoutNotifier) Send(ctx context.Context, e Event) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	return t.Notifier.Send(ctx, e)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is something that happened and that someone should hear about.
+type Event struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"` // e.g. "user.signup", "alert.disk_full"
+	To       string    `json:"to,omitempty"`
+	Subject  string    `json:"subject"`
+	Body     string    `json:"body,omitempty"`
+	Channels []string  `json:"channels,omitempty"` // overrides routing if set
+	Time     time.Time `json:"time,omitzero"`
+}
+
+func (e Event) validate() error {
+	switch {
+	case e.ID == "":
+		return errors.New("event has no id")
+	case e.Type == "":
+		return fmt.Errorf("event %s has no type", e.ID)
+	}
+	return nil
+}
+
+// Queue is where events come from. Receive blocks until an event arrives
+// and returns io.EOF once the queue is drained and closed.
+type Queue interface {
+	Receive(ctx context.Context) (Event, error)
+}
+
+// ChanQueue is an in-memory queue: producers Publish, the dispatcher
+// Receives. It is what a test or a single process would use; a real system
+// would put Redis, NATS or SQS behind the same interface.
+type ChanQueue struct {
+	events chan Event
+}
+
+func NewChanQueue(size int) *ChanQueue {
+	return &ChanQueue{events: make(chan Event, size)}
+}
+
+// Publish blocks while the queue is full: back pressure on the producer.
+func (q *ChanQueue) Publish(ctx context.Context, e Event) error {
+	select {
+	case q.events <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close tells the dispatcher no more events are coming.
+func (q *ChanQueue) Close() { close(q.events) }
+
+func (q *ChanQueue) Receive(ctx context.Context) (Event, error) {
+	select {
+	case e, ok := <-q.events:
+		if !ok {
+			return Event{}, io.EOF
+		}
+		return e, nil
+	case <-ctx.Done():
+		return Event{}, ctx.Err()
+	}
+}
+
+// JSONLinesQueue reads one JSON event per line, from a file or stdin.
+// Blank lines and lines starting with # are skipped.
+type JSONLinesQueue struct {
+	mu     sync.Mutex // every worker calls Receive; a Scanner is not safe to share
+	sc     *bufio.Scanner
+	lineNo int
+}
+
+func NewJSONLinesQueue(r io.Reader) *JSONLinesQueue {
+	return &JSONLinesQueue{sc: bufio.NewScanner(r)}
+}
+
+// Receive returns a parse error for a bad line; the caller can log it and
+// keep receiving, so one bad event doesn't stop the rest.
+func (q *JSONLinesQueue) Receive(ctx context.Context) (Event, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.sc.Scan() {
+		if err := ctx.Err(); err != nil {
+			return Event{}, err
+		}
+		q.lineNo++
+		line := strings.TrimSpace(q.sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return Event{}, &BadEventError{Line: q.lineNo, Err: err}
+		}
+		if err := e.validate(); err != nil {
+			return Event{}, &BadEventError{Line: q.lineNo, Err: err}
+		}
+		return e, nil
+	}
+	if err := q.sc.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+// BadEventError is a line that could not be turned into an event.
+type BadEventError struct {
+	Line int
+	Err  error
+}
+
+func (e *BadEventError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+func (e *BadEventError) Unwrap() error { return e.Err }
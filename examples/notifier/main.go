@@ -0,0 +1,103 @@
+// Command notifier reads events from a queue and delivers each one over the
+// channels it is routed to (console, email stub, webhook), retrying
+// failures with backoff and writing what still fails to a dead-letter file:
+//
+//	go run ./examples/notifier -events examples/notifier/testdata/events.jsonl
+//	go run ./examples/notifier -webhook http://localhost:9000/hook < events.jsonl
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+func main() {
+	eventsPath := flag.String("events", "-", "JSON-lines event file (- for stdin)")
+	webhook := flag.String("webhook", "", "webhook URL; enables the webhook channel")
+	outbox := flag.String("outbox", "outbox", "folder the email stub writes .eml files to")
+	from := flag.String("from", "notifier@example.com", "email From address")
+	deadPath := flag.String("deadletter", "deadletter.jsonl", "file for deliveries that failed for good")
+	workers := flag.Int("workers", 4, "concurrent deliveries")
+	attempts := flag.Int("attempts", 4, "tries per delivery, including the first")
+	timeout := flag.Duration("timeout", 5*time.Second, "per-send timeout")
+	verbose := flag.Bool("v", false, "log every send")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "[notifier] ", log.LstdFlags)
+	cfg := ChannelConfig{
+		Console:    os.Stdout,
+		Outbox:     *outbox,
+		From:       *from,
+		WebhookURL: *webhook,
+		Client:     &http.Client{},
+	}
+	kinds := []string{"console", "email"}
+	if *webhook != "" {
+		kinds = append(kinds, "webhook")
+	}
+	retry := Retry{Attempts: *attempts, Base: 200 * time.Millisecond, Max: 5 * time.Second}
+
+	if err := run(*eventsPath, *deadPath, kinds, cfg, retry, *workers, *timeout, *verbose, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(eventsPath, deadPath string, kinds []string, cfg ChannelConfig, retry Retry, workers int, timeout time.Duration, verbose bool, logger *log.Logger) error {
+	var notifiers []Notifier
+	for _, kind := range kinds {
+		n, err := NewNotifier(kind, cfg)
+		if err != nil {
+			return err
+		}
+		n = WithTimeout(n, timeout)
+		if verbose {
+			n = Logged(n, logger)
+		}
+		notifiers = append(notifiers, n)
+	}
+
+	var in io.Reader = os.Stdin
+	if eventsPath != "-" {
+		f, err := os.Open(eventsPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	dead, err := OpenDeadLetterFile(deadPath)
+	if err != nil {
+		return err
+	}
+	defer dead.Close()
+
+	// Alerts go everywhere urgent; account mail goes by email
+	router := Router{
+		Routes: []Route{
+			{Prefix: "alert.", Channels: []string{"webhook", "console"}},
+			{Prefix: "user.", Channels: []string{"email"}},
+		},
+		Fallback: []string{"console"},
+	}
+	if cfg.WebhookURL == "" {
+		router.Routes[0].Channels = []string{"console"}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	d := NewDispatcher(notifiers, router, retry, dead, logger)
+	began := time.Now()
+	err = d.Run(ctx, NewJSONLinesQueue(in), workers)
+	logger.Printf("%d events in %s: %d delivered, %d needed retries, %d dead-lettered to %s, %d bad lines skipped",
+		d.Stats.Events.Load(), time.Since(began).Round(time.Millisecond), d.Stats.Delivered.Load(),
+		d.Stats.Retried.Load(), d.Stats.DeadLetter.Load(), deadPath, d.Stats.BadEvents.Load())
+	return err
+}
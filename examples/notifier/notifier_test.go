@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeNotifier fails its first failFirst sends with err, then succeeds.
+type fakeNotifier struct {
+	name      string
+	failFirst int
+	err       error
+
+	mu    sync.Mutex
+	calls int
+	sent  []string
+}
+
+func (f *fakeNotifier) Name() string { return f.name }
+
+func (f *fakeNotifier) Send(ctx context.Context, e Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.calls <= f.failFirst {
+		return f.err
+	}
+	f.sent = append(f.sent, e.ID)
+	return nil
+}
+
+// memDeadLetters is a DeadLetterSink that keeps everything in memory.
+type memDeadLetters struct {
+	mu   sync.Mutex
+	list []DeadLetter
+}
+
+func (m *memDeadLetters) Add(dl DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.list = append(m.list, dl)
+	return nil
+}
+
+var fastRetry = Retry{Attempts: 3, Base: time.Millisecond, Max: 2 * time.Millisecond}
+
+func quietLogger() *log.Logger { return log.New(io.Discard, "", 0) }
+
+func runEvents(t *testing.T, d *Dispatcher, events ...Event) {
+	t.Helper()
+	q := NewChanQueue(len(events))
+	for _, e := range events {
+		q.Publish(context.Background(), e)
+	}
+	q.Close()
+	if err := d.Run(context.Background(), q, 4); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	r := Router{
+		Routes: []Route{
+			{Prefix: "alert.", Channels: []string{"webhook", "console"}},
+			{Prefix: "user.", Channels: []string{"email"}},
+		},
+		Fallback: []string{"console"},
+	}
+	tests := []struct {
+		event Event
+		want  []string
+	}{
+		{Event{Type: "alert.disk_full"}, []string{"webhook", "console"}},
+		{Event{Type: "user.signup"}, []string{"email"}},
+		{Event{Type: "deploy.done"}, []string{"console"}},
+		{Event{Type: "alert.x", Channels: []string{"email"}}, []string{"email"}},
+	}
+	for _, tt := range tests {
+		if got := r.Channels(tt.event); !slices.Equal(got, tt.want) {
+			t.Errorf("Channels(%+v) = %v, want %v", tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestRetryStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	attempts, err := fastRetry.Do(context.Background(), func() error {
+		calls++
+		return permanent(errors.New("bad address"))
+	})
+	if calls != 1 || attempts != 1 || err == nil {
+		t.Errorf("calls=%d attempts=%d err=%v, want a single failed attempt", calls, attempts, err)
+	}
+}
+
+func TestRetryGivesUpAfterAttempts(t *testing.T) {
+	calls := 0
+	attempts, err := fastRetry.Do(context.Background(), func() error {
+		calls++
+		return errors.New("timeout")
+	})
+	if calls != 3 || attempts != 3 || err == nil {
+		t.Errorf("calls=%d attempts=%d err=%v, want 3 failed attempts", calls, attempts, err)
+	}
+}
+
+func TestBackoffGrowsAndIsCapped(t *testing.T) {
+	r := Retry{Base: 100 * time.Millisecond, Max: time.Second}
+	for attempt, ceiling := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 5: time.Second, 80: time.Second} {
+		for range 20 {
+			d := r.backoff(attempt)
+			if d < ceiling/2 || d > ceiling {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", attempt, d, ceiling/2, ceiling)
+			}
+		}
+	}
+}
+
+func TestDispatchRetriesThenDelivers(t *testing.T) {
+	flaky := &fakeNotifier{name: "console", failFirst: 2, err: errors.New("503")}
+	dead := &memDeadLetters{}
+	d := NewDispatcher([]Notifier{flaky}, Router{Fallback: []string{"console"}}, fastRetry, dead, quietLogger())
+
+	runEvents(t, d, Event{ID: "e1", Type: "x"})
+
+	if !slices.Equal(flaky.sent, []string{"e1"}) {
+		t.Errorf("sent %v, want [e1]", flaky.sent)
+	}
+	if d.Stats.Delivered.Load() != 1 || d.Stats.Retried.Load() != 1 || len(dead.list) != 0 {
+		t.Errorf("delivered=%d retried=%d dead=%d, want 1 1 0",
+			d.Stats.Delivered.Load(), d.Stats.Retried.Load(), len(dead.list))
+	}
+}
+
+func TestDispatchDeadLettersFailures(t *testing.T) {
+	down := &fakeNotifier{name: "webhook", failFirst: 100, err: errors.New("connection refused")}
+	ok := &fakeNotifier{name: "console"}
+	dead := &memDeadLetters{}
+	router := Router{Routes: []Route{{Prefix: "alert.", Channels: []string{"webhook", "console", "pager"}}}}
+	d := NewDispatcher([]Notifier{down, ok}, router, fastRetry, dead, quietLogger())
+
+	runEvents(t, d, Event{ID: "a1", Type: "alert.disk"})
+
+	// The webhook failing must not stop the console delivery
+	if !slices.Equal(ok.sent, []string{"a1"}) {
+		t.Errorf("console sent %v, want [a1]", ok.sent)
+	}
+	if len(dead.list) != 2 {
+		t.Fatalf("%d dead letters, want 2 (webhook and the unknown pager)", len(dead.list))
+	}
+	byChannel := map[string]DeadLetter{}
+	for _, dl := range dead.list {
+		byChannel[dl.Channel] = dl
+	}
+	if dl := byChannel["webhook"]; dl.Attempts != 3 || dl.Event.ID != "a1" {
+		t.Errorf("webhook dead letter = %+v, want 3 attempts for a1", dl)
+	}
+	if dl := byChannel["pager"]; dl.Attempts != 0 {
+		t.Errorf("pager dead letter = %+v, want 0 attempts", dl)
+	}
+}
+
+func TestRunDeliversEveryEvent(t *testing.T) {
+	n := &fakeNotifier{name: "console"}
+	d := NewDispatcher([]Notifier{n}, Router{Fallback: []string{"console"}}, fastRetry, &memDeadLetters{}, quietLogger())
+
+	var events []Event
+	for i := range 200 {
+		events = append(events, Event{ID: fmt.Sprintf("e%d", i), Type: "t"})
+	}
+	runEvents(t, d, events...)
+
+	if len(n.sent) != 200 || d.Stats.Events.Load() != 200 {
+		t.Errorf("sent %d, counted %d events, want 200", len(n.sent), d.Stats.Events.Load())
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	q := NewChanQueue(0) // never closed: only cancellation can end Run
+	d := NewDispatcher(nil, Router{}, fastRetry, &memDeadLetters{}, quietLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Run(ctx, q, 2) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run after cancel = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestJSONLinesQueueSkipsBadLines(t *testing.T) {
+	f, err := os.Open("testdata/events.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	n := &fakeNotifier{name: "console"}
+	d := NewDispatcher([]Notifier{n}, Router{Fallback: []string{"console"}}, fastRetry, &memDeadLetters{}, quietLogger())
+
+	if err := d.Run(context.Background(), NewJSONLinesQueue(f), 3); err != nil {
+		t.Fatal(err)
+	}
+	if d.Stats.Events.Load() != 7 || d.Stats.BadEvents.Load() != 2 {
+		t.Errorf("events=%d bad=%d, want 7 and 2", d.Stats.Events.Load(), d.Stats.BadEvents.Load())
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var calls atomic.Int32
+	var got Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail once to exercise the retry, then accept
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Idempotency-Key") != "w1" {
+			t.Errorf("Idempotency-Key = %q, want w1", r.Header.Get("Idempotency-Key"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	w := NewWebhookNotifier(srv.URL, srv.Client())
+	e := Event{ID: "w1", Type: "alert.x", Subject: "hello"}
+	attempts, err := fastRetry.Do(context.Background(), func() error { return w.Send(context.Background(), e) })
+	if err != nil || attempts != 2 {
+		t.Fatalf("attempts=%d err=%v, want success on the 2nd", attempts, err)
+	}
+	if got.Subject != "hello" {
+		t.Errorf("server got %+v", got)
+	}
+}
+
+func TestWebhook4xxIsPermanent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookNotifier(srv.URL, srv.Client()).Send(context.Background(), Event{ID: "w2", Type: "x"})
+	var perm *PermanentError
+	if !errors.As(err, &perm) {
+		t.Errorf("400 gave %v, want a PermanentError", err)
+	}
+}
+
+func TestEmailNotifierWritesOutbox(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewEmailNotifier(dir, "from@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Event{ID: "../escape", Type: "user.signup", To: "ada@example.com", Subject: "Hi", Body: "line 1\nline 2"}
+	if err := m.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".._escape.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"To: <ada@example.com>\r\n", "Subject: Hi\r\n", "line 1\r\nline 2"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("message missing %q:\n%s", want, data)
+		}
+	}
+
+	var perm *PermanentError
+	if err := m.Send(context.Background(), Event{ID: "x", To: "nobody"}); !errors.As(err, &perm) {
+		t.Errorf("bad address gave %v, want a PermanentError", err)
+	}
+}
+
+func TestDeadLetterFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	for _, id := range []string{"d1", "d2"} {
+		// Reopen each time: entries from an earlier run must survive
+		f, err := OpenDeadLetterFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Add(DeadLetter{Event: Event{ID: id, Type: "x"}, Channel: "email", Error: "boom", Attempts: 1})
+		f.Close()
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var ids []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var dl DeadLetter
+		if err := json.Unmarshal(sc.Bytes(), &dl); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, dl.Event.ID)
+	}
+	if !slices.Equal(ids, []string{"d1", "d2"}) {
+		t.Errorf("dead letters %v, want [d1 d2]", ids)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	slow := notifierFunc(func(ctx context.Context, e Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	err := WithTimeout(slow, 10*time.Millisecond).Send(context.Background(), Event{ID: "s"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want DeadlineExceeded", err)
+	}
+}
+
+// notifierFunc adapts a function to Notifier, like http.HandlerFunc.
+type notifierFunc func(ctx context.Context, e Event) error
+
+func (f notifierFunc) Name() string                            { return "func" }
+func (f notifierFunc) Send(ctx context.Context, e Event) error { return f(ctx, e) }
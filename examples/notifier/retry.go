@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Retry is an exponential backoff policy: wait Base, then 2x, 4x... up to
+// Max between attempts, with jitter.
+type Retry struct {
+	Attempts int // total tries, including the first
+	Base     time.Duration
+	Max      time.Duration
+}
+
+// Do calls fn until it succeeds, returns a PermanentError, runs out of
+// attempts, or ctx is done. It returns the number of attempts made and the
+// last error.
+func (r Retry) Do(ctx context.Context, fn func() error) (int, error) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return attempt, nil
+		}
+		var perm *PermanentError
+		if errors.As(err, &perm) || attempt >= r.Attempts {
+			return attempt, err
+		}
+
+		timer := time.NewTimer(r.backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// backoff is the wait after the given failed attempt. The random half
+// ("equal jitter") stops many failing deliveries from retrying in lockstep
+// and hitting a recovering server all at the same moment.
+func (r Retry) backoff(attempt int) time.Duration {
+	d := r.Base << (attempt - 1)
+	if d <= 0 || d > r.Max { // d <= 0: the shift overflowed
+		d = r.Max
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
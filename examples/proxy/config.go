@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Config is the proxy's JSON configuration file.
+type Config struct {
+	Addr   string  `json:"addr"`
+	Routes []Route `json:"routes"`
+}
+
+// Route sends every request whose path starts with Prefix to Target.
+type Route struct {
+	Prefix          string      `json:"prefix"`
+	Target          string      `json:"target"`
+	StripPrefix     bool        `json:"strip_prefix"` // /api/todos -> /todos when Prefix is /api
+	RateLimit       *RateLimit  `json:"rate_limit,omitempty"`
+	RequestHeaders  HeaderRules `json:"request_headers"`
+	ResponseHeaders HeaderRules `json:"response_headers"`
+}
+
+// RateLimit allows each client PerSecond requests per second on average,
+// with bursts of up to Burst.
+type RateLimit struct {
+	PerSecond float64 `json:"per_second"`
+	Burst     int     `json:"burst"`
+}
+
+// HeaderRules rewrite headers: Remove runs first, then Set.
+type HeaderRules struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+// LoadConfig reads and validates a config file.
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cfg Config
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields() // a typo like "strip_prefx" should fail loudly
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate reports every problem at once, so a broken config file can be
+// fixed in one go.
+func (c *Config) Validate() error {
+	if c.Addr == "" {
+		c.Addr = ":8090"
+	}
+	if len(c.Routes) == 0 {
+		return errors.New("no routes configured")
+	}
+	var errs []error
+	seen := make(map[string]bool)
+	for i, r := range c.Routes {
+		where := fmt.Sprintf("routes[%d]", i)
+		if !strings.HasPrefix(r.Prefix, "/") {
+			errs = append(errs, fmt.Errorf("%s: prefix %q must start with /", where, r.Prefix))
+		}
+		if seen[r.Prefix] {
+			errs = append(errs, fmt.Errorf("%s: duplicate prefix %q", where, r.Prefix))
+		}
+		seen[r.Prefix] = true
+		if u, err := url.Parse(r.Target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%s: target %q must be an absolute http(s) URL", where, r.Target))
+		}
+		if rl := r.RateLimit; rl != nil && (rl.PerSecond <= 0 || rl.Burst < 1) {
+			errs = append(errs, fmt.Errorf("%s: rate_limit needs per_second > 0 and burst >= 1", where))
+		}
+	}
+	return errors.Join(errs...)
+}
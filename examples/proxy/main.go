@@ -0,0 +1,72 @@
+// Command proxy is a reverse proxy that puts the other capstones behind one
+// address, with per-route rate limits and header rewriting:
+//
+//	go run ./examples/todo-api/cmd/todo-api &
+//	go run ./examples/proxy -config examples/proxy/proxy.example.json
+//	curl -i localhost:8090/api/todos
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	configPath := flag.String("config", "proxy.json", "JSON route configuration")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[proxy] ", log.LstdFlags)
+	if err := run(*configPath, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(configPath string, logger *log.Logger) error {
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	proxy, err := NewProxy(cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go proxy.PruneLimiters(ctx, time.Minute)
+
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           proxy.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		for _, r := range cfg.Routes {
+			logger.Printf("route %s -> %s", r.Prefix, r.Target)
+		}
+		logger.Printf("listening on %s", cfg.Addr)
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	case <-ctx.Done():
+		logger.Println("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+	return nil
+}
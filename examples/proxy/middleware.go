@@ -0,0 +1,123 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Middleware wraps a handler with extra behaviour. It is the same shape as
+// the todo-api capstone's middleware package, applied to proxied traffic.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middlewares so the first one listed runs first.
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// statusRecorder remembers the status code and size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the real ResponseWriter. The
+// reverse proxy needs it to flush streamed responses and to hijack the
+// connection for WebSocket upgrades; without it, wrapping the writer would
+// silently break both.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Logging logs one line per request: client, method, path, status, bytes,
+// duration and request ID.
+func Logging(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			logger.Printf("%s %s %s %d %d %v id=%s", clientIP(r), r.Method, r.URL.RequestURI(),
+				rec.status, rec.bytes, time.Since(start).Round(time.Microsecond), r.Header.Get("X-Request-Id"))
+		})
+	}
+}
+
+// RequestID gives every request an X-Request-Id header, keeping one the
+// client already sent. The header travels to the backend with the request
+// and is echoed in the response, so one ID ties together the proxy's log
+// line, the backend's log line and the client's bug report.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get("X-Request-Id")
+			if id == "" {
+				id = newRequestID()
+				r.Header.Set("X-Request-Id", id)
+			}
+			w.Header().Set("X-Request-Id", id)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
+}
+
+// Limit answers 429 Too Many Requests once a client has used up its
+// tokens in l.
+func Limit(l *Limiter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok, wait := l.Allow(clientIP(r))
+			if !ok {
+				// Retry-After is in whole seconds; round up so clients
+				// that obey it don't come back a moment too early
+				secs := int((wait + time.Second - 1) / time.Second)
+				w.Header().Set("Retry-After", strconv.Itoa(secs))
+				writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP is the address of the TCP peer. X-Forwarded-For is deliberately
+// ignored: any client can set it, so trusting it would let anyone pick
+// their own rate-limit bucket.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(`{"error":` + strconv.Quote(msg) + "}\n"))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Proxy routes each request to the backend whose prefix matches it.
+type Proxy struct {
+	routes   []*route // longest prefix first
+	limiters []*Limiter
+	logger   *log.Logger
+}
+
+type route struct {
+	Route
+	handler http.Handler
+}
+
+func NewProxy(cfg *Config, logger *log.Logger) (*Proxy, error) {
+	p := &Proxy{logger: logger}
+	for _, rc := range cfg.Routes {
+		target, err := url.Parse(rc.Target)
+		if err != nil {
+			return nil, err
+		}
+		var h http.Handler = p.reverseProxy(rc, target)
+		if rc.RateLimit != nil {
+			l := NewLimiter(rc.RateLimit.PerSecond, rc.RateLimit.Burst)
+			p.limiters = append(p.limiters, l)
+			h = Chain(h, Limit(l))
+		}
+		p.routes = append(p.routes, &route{Route: rc, handler: h})
+	}
+	// With /api and /api/admin both configured, /api/admin/users must go to
+	// /api/admin: trying longer prefixes first makes the most specific win
+	sort.Slice(p.routes, func(i, j int) bool { return len(p.routes[i].Prefix) > len(p.routes[j].Prefix) })
+	return p, nil
+}
+
+// reverseProxy builds the httputil.ReverseProxy for one route.
+//
+// Rewrite gets both the incoming request (In) and a copy that will be sent
+// to the backend (Out). Hop-by-hop headers such as Connection have already
+// been removed from Out, and SetXForwarded adds X-Forwarded-For/-Host/-Proto
+// so the backend can still see who the real client was.
+func (p *Proxy) reverseProxy(rc Route, target *url.URL) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if rc.StripPrefix {
+				pr.Out.URL.Path = stripPrefix(pr.Out.URL.Path, rc.Prefix)
+				pr.Out.URL.RawPath = ""
+			}
+			pr.SetURL(target) // also sets Out.Host to the target's host
+			pr.SetXForwarded()
+			rc.RequestHeaders.apply(pr.Out.Header)
+		},
+		ModifyResponse: func(res *http.Response) error {
+			rc.ResponseHeaders.apply(res.Header)
+			return nil
+		},
+		// The default handler answers a bare 502; log why, and answer in
+		// the same JSON shape as the proxy's other errors
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			p.logger.Printf("%s %s -> %s: %v", r.Method, r.URL.Path, rc.Target, err)
+			writeError(w, http.StatusBadGateway, "upstream unavailable")
+		},
+		// -1 flushes every write straight away, so server-sent events and
+		// other streamed responses aren't held back in a buffer
+		FlushInterval: -1,
+	}
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, rt := range p.routes {
+		if matchPrefix(r.URL.Path, rt.Prefix) {
+			rt.handler.ServeHTTP(w, r)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "no route for "+r.URL.Path)
+}
+
+// Handler is the proxy with its global middleware applied.
+func (p *Proxy) Handler() http.Handler {
+	return Chain(p, RequestID(), Logging(p.logger))
+}
+
+// PruneLimiters drops idle rate-limit buckets every interval until ctx is
+// done.
+func (p *Proxy) PruneLimiters(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, l := range p.limiters {
+				l.Prune()
+			}
+		}
+	}
+}
+
+// matchPrefix matches whole path segments: /api matches /api and /api/todos
+// but not /apis.
+func matchPrefix(path, prefix string) bool {
+	if prefix == "/" || strings.HasSuffix(prefix, "/") {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// stripPrefix removes prefix from path, keeping the result rooted.
+func stripPrefix(path, prefix string) string {
+	rest := strings.TrimPrefix(path, strings.TrimSuffix(prefix, "/"))
+	if !strings.HasPrefix(rest, "/") {
+		rest = "/" + rest
+	}
+	return rest
+}
+
+// apply removes and then sets headers.
+func (h HeaderRules) apply(header http.Header) {
+	for _, name := range h.Remove {
+		header.Del(name)
+	}
+	for name, value := range h.Set {
+		header.Set(name, value)
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backend echoes what it received as JSON, so tests can check what the
+// proxy forwarded.
+type echo struct {
+	Name    string      `json:"name"`
+	Path    string      `json:"path"`
+	Query   string      `json:"query"`
+	Host    string      `json:"host"`
+	Headers http.Header `json:"headers"`
+}
+
+func newBackend(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "backend/1.0")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(echo{name, r.URL.Path, r.URL.RawQuery, r.Host, r.Header})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newProxy(t *testing.T, routes ...Route) *httptest.Server {
+	t.Helper()
+	cfg := &Config{Routes: routes}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewProxy(cfg, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(p.Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, url string, header http.Header) (*http.Response, echo) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	var e echo
+	if res.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res, e
+}
+
+func TestRoutingLongestPrefixWins(t *testing.T) {
+	api, admin, site := newBackend(t, "api"), newBackend(t, "admin"), newBackend(t, "site")
+	proxy := newProxy(t,
+		Route{Prefix: "/", Target: site.URL},
+		Route{Prefix: "/api", Target: api.URL, StripPrefix: true},
+		Route{Prefix: "/api/admin", Target: admin.URL},
+	)
+
+	tests := []struct {
+		path, backend, forwarded, query string
+	}{
+		{"/api/todos?done=true", "api", "/todos", "done=true"},
+		{"/api", "api", "/", ""},
+		{"/api/admin/users", "admin", "/api/admin/users", ""},
+		{"/apis", "site", "/apis", ""}, // /api matches whole segments only
+		{"/index.html", "site", "/index.html", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			res, e := get(t, proxy.URL+tt.path, nil)
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d", res.StatusCode)
+			}
+			if e.Name != tt.backend || e.Path != tt.forwarded || e.Query != tt.query {
+				t.Errorf("reached %s at %s?%s, want %s at %s?%s",
+					e.Name, e.Path, e.Query, tt.backend, tt.forwarded, tt.query)
+			}
+		})
+	}
+}
+
+func TestNoRoute(t *testing.T) {
+	api := newBackend(t, "api")
+	proxy := newProxy(t, Route{Prefix: "/api", Target: api.URL})
+	res, _ := get(t, proxy.URL+"/other", nil)
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", res.StatusCode)
+	}
+}
+
+func TestHeaderRewriting(t *testing.T) {
+	api := newBackend(t, "api")
+	proxy := newProxy(t, Route{
+		Prefix: "/api",
+		Target: api.URL,
+		RequestHeaders: HeaderRules{
+			Set:    map[string]string{"X-Proxied-By": "test"},
+			Remove: []string{"Cookie"},
+		},
+		ResponseHeaders: HeaderRules{
+			Set:    map[string]string{"Access-Control-Allow-Origin": "*"},
+			Remove: []string{"Server"},
+		},
+	})
+
+	res, e := get(t, proxy.URL+"/api/x", http.Header{
+		"Cookie":       {"session=secret"},
+		"X-Request-Id": {"abc123"},
+	})
+	if got := e.Headers.Get("X-Proxied-By"); got != "test" {
+		t.Errorf("backend X-Proxied-By = %q, want test", got)
+	}
+	if got := e.Headers.Get("Cookie"); got != "" {
+		t.Errorf("backend Cookie = %q, want it removed", got)
+	}
+	if got := e.Headers.Get("X-Forwarded-For"); got != "127.0.0.1" {
+		t.Errorf("backend X-Forwarded-For = %q, want 127.0.0.1", got)
+	}
+	if got := e.Headers.Get("X-Request-Id"); got != "abc123" {
+		t.Errorf("backend X-Request-Id = %q, want the client's abc123", got)
+	}
+	if !strings.HasPrefix(api.URL, "http://"+e.Host) {
+		t.Errorf("backend Host = %q, want the backend's own host", e.Host)
+	}
+
+	if got := res.Header.Get("Server"); got != "" {
+		t.Errorf("response Server = %q, want it removed", got)
+	}
+	if got := res.Header.Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("response Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := res.Header.Get("X-Request-Id"); got != "abc123" {
+		t.Errorf("response X-Request-Id = %q, want abc123", got)
+	}
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	api := newBackend(t, "api")
+	proxy := newProxy(t, Route{Prefix: "/", Target: api.URL})
+	res, e := get(t, proxy.URL+"/", nil)
+	id := res.Header.Get("X-Request-Id")
+	if len(id) != 16 || e.Headers.Get("X-Request-Id") != id {
+		t.Errorf("response id %q, backend id %q: want the same 16 hex chars", id, e.Headers.Get("X-Request-Id"))
+	}
+}
+
+func TestRateLimitPerRoute(t *testing.T) {
+	api, site := newBackend(t, "api"), newBackend(t, "site")
+	proxy := newProxy(t,
+		Route{Prefix: "/api", Target: api.URL, RateLimit: &RateLimit{PerSecond: 0.5, Burst: 3}},
+		Route{Prefix: "/", Target: site.URL},
+	)
+
+	for i := range 3 {
+		if res, _ := get(t, proxy.URL+"/api/x", nil); res.StatusCode != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200 within the burst", i+1, res.StatusCode)
+		}
+	}
+	res, _ := get(t, proxy.URL+"/api/x", nil)
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429 after the burst", res.StatusCode)
+	}
+	if got := res.Header.Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	// other routes have their own budget
+	if res, _ := get(t, proxy.URL+"/page", nil); res.StatusCode != http.StatusOK {
+		t.Errorf("unlimited route: status = %d, want 200", res.StatusCode)
+	}
+}
+
+func TestBackendDown(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	proxy := newProxy(t, Route{Prefix: "/", Target: down.URL})
+
+	res, _ := get(t, proxy.URL+"/", nil)
+	if res.StatusCode != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", res.StatusCode)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter(2, 2) // 2 per second, bursts of 2
+	l.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d refused within the burst", i+1)
+		}
+	}
+	ok, wait := l.Allow("a")
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("Allow = %v, %s; want refused, 500ms", ok, wait)
+	}
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("a different client was refused")
+	}
+
+	now = now.Add(500 * time.Millisecond) // one token earned
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("refused after refilling")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("allowed with an empty bucket")
+	}
+
+	now = now.Add(time.Hour) // tokens are capped at burst, not 7200
+	for range 2 {
+		l.Allow("a")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("bucket held more than burst tokens")
+	}
+
+	now = now.Add(time.Second) // a and b have both refilled
+	l.Prune()
+	if l.Len() != 0 {
+		t.Errorf("Len = %d after Prune, want 0", l.Len())
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig("proxy.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":8090" || len(cfg.Routes) != 3 || cfg.Routes[0].RateLimit.Burst != 10 {
+		t.Errorf("example config = %+v", cfg)
+	}
+
+	write := func(body string) string {
+		path := filepath.Join(t.TempDir(), "proxy.json")
+		os.WriteFile(path, []byte(body), 0o644)
+		return path
+	}
+	bad := []struct{ name, body, want string }{
+		{"unknown field", `{"routes":[{"prefix":"/","target":"http://x","strip_prefx":true}]}`, "unknown field"},
+		{"no routes", `{"addr":":1"}`, "no routes"},
+		{"relative prefix", `{"routes":[{"prefix":"api","target":"http://x"}]}`, "must start with /"},
+		{"bad target", `{"routes":[{"prefix":"/","target":"localhost:8081"}]}`, "absolute http(s) URL"},
+		{"duplicate", `{"routes":[{"prefix":"/","target":"http://x"},{"prefix":"/","target":"http://y"}]}`, "duplicate"},
+		{"bad limit", `{"routes":[{"prefix":"/","target":"http://x","rate_limit":{"per_second":1}}]}`, "burst"},
+	}
+	for _, tt := range bad {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(write(tt.body))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
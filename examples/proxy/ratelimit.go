@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket: it holds up to burst tokens, refills at rate
+// tokens per second, and each request spends one. A client that has been
+// quiet can burst, but its long-run average can't exceed rate.
+//
+// Instead of a goroutine adding tokens on a ticker, the bucket refills
+// lazily: on each request it adds the tokens earned since the last one.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps one bucket per key (the client IP). It is the course 19
+// pattern: a map behind a mutex, with the lock held only for map and
+// arithmetic work.
+type Limiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time // swapped in tests
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	return &Limiter{
+		rate:    perSecond,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow spends a token for key. If none is left it returns false and how
+// long until one will be, for the Retry-After header.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// Prune forgets clients whose buckets have refilled completely; a full
+// bucket is the same as no bucket. Without it the map grows with every
+// client IP ever seen.
+func (l *Limiter) Prune() {
+	now := l.now()
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Len is the number of clients being tracked.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
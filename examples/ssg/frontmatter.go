@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FrontMatter is the metadata block at the top of a page:
+//
+//	---
+//	title: Goroutines and channels
+//	description: Lightweight threads and how they talk
+//	order: 4
+//	draft: true
+//	---
+//
+// Real generators use YAML here. This one reads only flat "key: value"
+// lines, which covers what lesson notes need without a YAML dependency.
+type FrontMatter struct {
+	Title       string
+	Description string
+	Order       int // position in the index
+	Draft       bool
+	Params      map[string]string // any other keys, for templates
+
+	hasOrder bool // pages without an order sort after all the others
+}
+
+const fence = "---"
+
+// parseFrontMatter splits src into its front matter and the Markdown body.
+// A file that doesn't start with "---" has no front matter, and all of it is
+// body.
+func parseFrontMatter(src []byte) (FrontMatter, []byte, error) {
+	fm := FrontMatter{Params: make(map[string]string)}
+	if !bytes.HasPrefix(src, []byte(fence+"\n")) && !bytes.HasPrefix(src, []byte(fence+"\r\n")) {
+		return fm, src, nil
+	}
+
+	pos := 0
+	nextLine := func() (string, bool) {
+		if pos >= len(src) {
+			return "", false
+		}
+		line := src[pos:]
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i]
+		}
+		pos += len(line) + 1
+		return strings.TrimRight(string(line), "\r"), true
+	}
+
+	nextLine() // the opening fence
+	for lineNo := 2; ; lineNo++ {
+		raw, ok := nextLine()
+		if !ok {
+			break
+		}
+		line := strings.TrimSpace(raw)
+		if line == fence {
+			return fm, src[min(pos, len(src)):], nil
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fm, nil, fmt.Errorf("front matter line %d: want 'key: value', got %q", lineNo, line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if err := fm.set(key, value); err != nil {
+			return fm, nil, fmt.Errorf("front matter line %d: %w", lineNo, err)
+		}
+	}
+	return fm, nil, fmt.Errorf("front matter is not closed with %q", fence)
+}
+
+func (fm *FrontMatter) set(key, value string) error {
+	switch key {
+	case "title":
+		fm.Title = value
+	case "description":
+		fm.Description = value
+	case "order":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("order must be a whole number, got %q", value)
+		}
+		fm.Order, fm.hasOrder = n, true
+	case "draft":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("draft must be true or false, got %q", value)
+		}
+		fm.Draft = b
+	default:
+		fm.Params[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+module github.com/owolabijunior12/learning-golang/examples/ssg
+
+go 1.25.1
+
+require github.com/yuin/goldmark v1.7.13
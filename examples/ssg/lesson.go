@@ -0,0 +1,114 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The course lessons are Go files (01-basics.go ... ) written as literate
+// programs: top-level // comments are the prose, banners like
+//
+//	// ============ 2. CHANNEL BASICS ============
+//
+// split the sections, and everything else is code. lessonToMarkdown turns
+// one into Markdown so it can go through the same pipeline as the notes.
+
+var (
+	lessonFile    = regexp.MustCompile(`^\d+-[\w-]+\.go$`)
+	numberPrefix  = regexp.MustCompile(`^(\d+)-`)
+	courseTitle   = regexp.MustCompile(`^// COURSE (\d+): (.+)$`)
+	sectionBanner = regexp.MustCompile(`^// =+ (.+?) =+$`)
+)
+
+// isLesson reports whether name looks like a course file, e.g.
+// "04-goroutines-and-channels.go" but not its _test.go.
+func isLesson(name string) bool {
+	return lessonFile.MatchString(name) && !strings.HasSuffix(name, "_test.go")
+}
+
+// lessonToMarkdown converts a lesson's source. The front matter comes from
+// the "// COURSE N: TITLE" line if there is one, and from the file name
+// otherwise.
+func lessonToMarkdown(name string, src []byte) (FrontMatter, []byte) {
+	fm := FrontMatter{Title: titleFromFile(name), Params: map[string]string{"source": name}}
+	fm.Order, fm.hasOrder = orderFromFile(name)
+
+	var md, code strings.Builder
+	flushCode := func() {
+		c := strings.Trim(code.String(), "\n")
+		code.Reset()
+		if c != "" {
+			md.WriteString("\n```go\n" + c + "\n```\n\n")
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+	inRaw := false // inside a `raw string`, where // is text, not a comment
+	for _, line := range skipHeader(lines) {
+		switch {
+		case inRaw || !strings.HasPrefix(line, "//"):
+			code.WriteString(line + "\n")
+			if strings.Count(line, "`")%2 == 1 {
+				inRaw = !inRaw
+			}
+		case courseTitle.MatchString(line):
+			m := courseTitle.FindStringSubmatch(line)
+			fm.Order, _ = strconv.Atoi(m[1])
+			fm.Title = m[2]
+		case sectionBanner.MatchString(line):
+			flushCode()
+			md.WriteString("## " + sectionBanner.FindStringSubmatch(line)[1] + "\n\n")
+		default:
+			flushCode()
+			text := strings.TrimPrefix(line, "//")
+			text = strings.TrimPrefix(text, " ")
+			md.WriteString(text + "\n")
+		}
+	}
+	flushCode()
+	return fm, []byte(md.String())
+}
+
+// skipHeader drops the package clause, imports and build tags: every lesson
+// has them and they say nothing about the topic.
+func skipHeader(lines []string) []string {
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "", strings.HasPrefix(line, "package "), strings.HasPrefix(line, "//go:build"):
+		case line == "import (":
+			for i < len(lines) && strings.TrimSpace(lines[i]) != ")" {
+				i++
+			}
+		case strings.HasPrefix(line, "import "):
+		default:
+			return lines[i:]
+		}
+	}
+	return nil
+}
+
+// orderFromFile reads the number in front of names like "04-goroutines.md".
+func orderFromFile(name string) (int, bool) {
+	m := numberPrefix.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+// titleFromFile turns "02-functions-and-errors.go" into "Functions and errors".
+func titleFromFile(name string) string {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	if m := numberPrefix.FindString(base); m != "" {
+		base = base[len(m):]
+	}
+	base = strings.ReplaceAll(base, "-", " ")
+	if base == "" {
+		return name
+	}
+	return strings.ToUpper(base[:1]) + base[1:]
+}
@@ -0,0 +1,42 @@
+// Command ssg is a static site generator: it turns a folder of Markdown
+// notes into a small HTML site with an index page.
+//
+// With -lessons it also renders the course's own NN-name.go files, so from
+// the repository root this builds the whole course as a website:
+//
+//	go run ./examples/ssg -src . -lessons -out _site -serve :8084
+//	go run ./examples/ssg -src notes -out public -templates mytemplates
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	var opts Options
+	flag.StringVar(&opts.Src, "src", ".", "folder of Markdown files")
+	flag.StringVar(&opts.Out, "out", "_site", "output folder")
+	flag.StringVar(&opts.Templates, "templates", "", "folder with layout.html, page.html, index.html (default: built in)")
+	flag.StringVar(&opts.SiteTitle, "title", "Learning Go", "site title")
+	flag.BoolVar(&opts.Lessons, "lessons", false, "also render NN-name.go course files")
+	flag.BoolVar(&opts.Drafts, "drafts", false, "include pages marked draft: true")
+	serve := flag.String("serve", "", "after building, serve the site on this address, e.g. :8084")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "[ssg] ", log.LstdFlags)
+	began := time.Now()
+	pages, err := Build(opts)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	logger.Printf("built %d pages into %s in %s", len(pages), opts.Out, time.Since(began).Round(time.Millisecond))
+
+	if *serve != "" {
+		logger.Printf("serving http://localhost%s", *serve)
+		logger.Fatal(http.ListenAndServe(*serve, http.FileServer(http.Dir(opts.Out))))
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// markdown is configured once and shared; a goldmark.Markdown is safe for
+// concurrent use. GFM adds tables, strikethrough and autolinks, and
+// AutoHeadingID gives every heading an id so sections can be linked to.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// renderMarkdown converts src to HTML. Relative links to other source files
+// are pointed at their rendered pages (rewrite maps "02-functions.go" to
+// "02-functions.html"), so links between notes keep working on the site.
+// It also returns the text of the first top-level heading, for pages that
+// have no title in their front matter.
+func renderMarkdown(src []byte, rewrite map[string]string) (html []byte, h1 string, err error) {
+	doc := markdown.Parser().Parse(text.NewReader(src))
+
+	// Parsing gives an abstract syntax tree; walking it before rendering is
+	// how goldmark lets you change a document, rather than editing HTML.
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Link:
+			n.Destination = rewriteLink(n.Destination, rewrite)
+		case *ast.Heading:
+			if n.Level == 1 && h1 == "" {
+				h1 = plainText(n, src)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), h1, nil
+}
+
+// rewriteLink maps "GO-QUICK-REFERENCE.md#maps" to
+// "GO-QUICK-REFERENCE.html#maps". External and unknown links are untouched.
+func rewriteLink(dest []byte, rewrite map[string]string) []byte {
+	u, err := url.Parse(string(dest))
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return dest
+	}
+	target, ok := rewrite[path.Clean(strings.TrimPrefix(u.Path, "./"))]
+	if !ok {
+		return dest
+	}
+	u.Path = target
+	return []byte(u.String())
+}
+
+// plainText concatenates the text inside n, dropping formatting: the
+// heading "Using `go test`" becomes "Using go test".
+func plainText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			b.Write(t.Segment.Value(src))
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Page is one rendered document, as seen by the templates.
+type Page struct {
+	FrontMatter
+	Source  string        // file name in the source folder
+	URL     string        // output file name, e.g. "04-goroutines.html"
+	Content template.HTML // rendered body; template.HTML marks it as safe to insert unescaped
+	Prev    *Page
+	Next    *Page
+}
+
+// Options control one build.
+type Options struct {
+	Src       string // folder of Markdown notes
+	Out       string // output folder, created if missing
+	Templates string // folder overriding the built-in templates; "" uses the built-ins
+	Lessons   bool   // also render the NN-name.go course files found in Src
+	Drafts    bool   // include pages marked draft: true
+	SiteTitle string
+}
+
+// The built-in templates and stylesheet are compiled into the binary with
+// go:embed, so the generator works from any directory.
+//
+//go:embed templates
+var builtin embed.FS
+
+// Build renders every page in opts.Src into opts.Out, plus index.html and
+// style.css, and returns the pages in index order.
+func Build(opts Options) ([]*Page, error) {
+	if filepath.Clean(opts.Src) == filepath.Clean(opts.Out) {
+		return nil, errors.New("output folder must differ from the source folder")
+	}
+	tmpl, assets, err := loadTemplates(opts.Templates)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := loadPages(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found in %s", opts.Src)
+	}
+	for i, p := range pages {
+		if i > 0 {
+			p.Prev = pages[i-1]
+		}
+		if i < len(pages)-1 {
+			p.Next = pages[i+1]
+		}
+	}
+
+	if err := os.MkdirAll(opts.Out, 0o755); err != nil {
+		return nil, err
+	}
+	site := struct {
+		Title string
+		Pages []*Page
+		Built time.Time
+	}{opts.SiteTitle, pages, time.Now()}
+
+	for _, p := range pages {
+		data := struct {
+			Site any
+			Page *Page
+		}{site, p}
+		if err := render(tmpl, "page.html", filepath.Join(opts.Out, p.URL), data); err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Source, err)
+		}
+	}
+	if err := render(tmpl, "index.html", filepath.Join(opts.Out, "index.html"), struct{ Site any }{site}); err != nil {
+		return nil, fmt.Errorf("index: %w", err)
+	}
+	css, err := fs.ReadFile(assets, "style.css")
+	if errors.Is(err, fs.ErrNotExist) {
+		css, err = builtin.ReadFile("templates/style.css") // custom templates may reuse ours
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pages, os.WriteFile(filepath.Join(opts.Out, "style.css"), css, 0o644)
+}
+
+// loadTemplates parses layout.html, page.html and index.html from dir, or
+// from the built-in set. Each template shares the layout, which defines
+// the page chrome and calls {{template "content" .}}.
+func loadTemplates(dir string) (map[string]*template.Template, fs.FS, error) {
+	var fsys fs.FS
+	if dir == "" {
+		sub, err := fs.Sub(builtin, "templates")
+		if err != nil {
+			return nil, nil, err
+		}
+		fsys = sub
+	} else {
+		fsys = os.DirFS(dir)
+	}
+
+	set := make(map[string]*template.Template)
+	for _, name := range []string{"page.html", "index.html"} {
+		t, err := template.ParseFS(fsys, "layout.html", name)
+		if err != nil {
+			return nil, nil, err
+		}
+		set[name] = t
+	}
+	return set, fsys, nil
+}
+
+func render(tmpl map[string]*template.Template, name, path string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := tmpl[name].ExecuteTemplate(f, "layout.html", data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close() // a failed Close can mean the data never reached the disk
+}
+
+// loadPages reads and renders every page in opts.Src (not its subfolders),
+// sorted by order, then title. A page's order comes from its front matter
+// or a number in front of its file name; pages with neither go last.
+func loadPages(opts Options) ([]*Page, error) {
+	entries, err := os.ReadDir(opts.Src)
+	if err != nil {
+		return nil, err
+	}
+
+	// First pass: which files become which pages, so links can be rewritten
+	rewrite := make(map[string]string)
+	var sources []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !(strings.HasSuffix(name, ".md") || opts.Lessons && isLesson(name)) {
+			continue
+		}
+		sources = append(sources, name)
+		rewrite[name] = strings.TrimSuffix(name, filepath.Ext(name)) + ".html"
+	}
+
+	var pages []*Page
+	for _, name := range sources {
+		src, err := os.ReadFile(filepath.Join(opts.Src, name))
+		if err != nil {
+			return nil, err
+		}
+		var fm FrontMatter
+		var body []byte
+		if strings.HasSuffix(name, ".go") {
+			fm, body = lessonToMarkdown(name, src)
+		} else if fm, body, err = parseFrontMatter(src); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		} else if !fm.hasOrder {
+			// "03-maps.md" sorts third, like the course files
+			fm.Order, fm.hasOrder = orderFromFile(name)
+		}
+		if fm.Draft && !opts.Drafts {
+			continue
+		}
+
+		html, h1, err := renderMarkdown(body, rewrite)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if fm.Title == "" {
+			fm.Title = h1
+		}
+		if fm.Title == "" {
+			fm.Title = titleFromFile(name)
+		}
+		pages = append(pages, &Page{
+			FrontMatter: fm,
+			Source:      name,
+			URL:         rewrite[name],
+			Content:     template.HTML(html),
+		})
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		a, b := pages[i], pages[j]
+		if a.hasOrder != b.hasOrder {
+			return a.hasOrder
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return a.Title < b.Title
+	})
+	return pages, nil
+}
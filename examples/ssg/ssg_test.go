@@ -0,0 +1,240 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	src := "---\ntitle: \"Goroutines\"\norder: 4\ndraft: true\n# a comment\nlevel: beginner\n---\n# Body\n"
+	fm, body, err := parseFrontMatter([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.Title != "Goroutines" || fm.Order != 4 || !fm.hasOrder || !fm.Draft || fm.Params["level"] != "beginner" {
+		t.Errorf("front matter = %+v", fm)
+	}
+	if string(body) != "# Body\n" {
+		t.Errorf("body = %q, want %q", body, "# Body\n")
+	}
+
+	// Windows line endings
+	fm, body, err = parseFrontMatter([]byte("---\r\ntitle: x\r\n---\r\nbody"))
+	if err != nil || fm.Title != "x" || string(body) != "body" {
+		t.Errorf("CRLF: title %q, body %q, err %v", fm.Title, body, err)
+	}
+
+	// No front matter: everything is body
+	_, body, err = parseFrontMatter([]byte("# Just markdown\n---\n"))
+	if err != nil || string(body) != "# Just markdown\n---\n" {
+		t.Errorf("no front matter: body %q, err %v", body, err)
+	}
+}
+
+func TestParseFrontMatterErrors(t *testing.T) {
+	tests := map[string]string{
+		"not closed":  "---\ntitle: x\n",
+		"no colon":    "---\njust words\n---\n",
+		"bad order":   "---\norder: first\n---\n",
+		"bad draft":   "---\ndraft: maybe\n---\n",
+		"empty value": "---\norder:\n---\n",
+	}
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := parseFrontMatter([]byte(src)); err == nil {
+				t.Error("want an error")
+			}
+		})
+	}
+}
+
+const lesson = `package main
+
+import (
+	"fmt"
+)
+
+// COURSE 4: GOROUTINES
+// Topics covered:
+// 1. Goroutines
+
+// ============ 1. SIMPLE GOROUTINE ============
+// greet prints a greeting
+func greet() {
+	// indented comments stay in the code
+	fmt.Print(` + "`" + `
+// not a comment: this is inside a raw string
+` + "`" + `)
+}
+`
+
+func TestLessonToMarkdown(t *testing.T) {
+	fm, md := lessonToMarkdown("04-goroutines.go", []byte(lesson))
+	if fm.Title != "GOROUTINES" || fm.Order != 4 || fm.Params["source"] != "04-goroutines.go" {
+		t.Errorf("front matter = %+v", fm)
+	}
+	want := "Topics covered:\n1. Goroutines\n" +
+		"## 1. SIMPLE GOROUTINE\n\n" +
+		"greet prints a greeting\n" +
+		"\n```go\nfunc greet() {\n\t// indented comments stay in the code\n\tfmt.Print(`\n" +
+		"// not a comment: this is inside a raw string\n`)\n}\n```\n\n"
+	if string(md) != want {
+		t.Errorf("markdown:\n%s\nwant:\n%s", md, want)
+	}
+
+	// Without a COURSE line the title comes from the file name
+	fm, _ = lessonToMarkdown("01-basics.go", []byte("package main\n\nfunc main() {}\n"))
+	if fm.Title != "Basics" || fm.Order != 1 {
+		t.Errorf("front matter = %+v, want Basics, order 1", fm)
+	}
+}
+
+func TestIsLesson(t *testing.T) {
+	for name, want := range map[string]bool{
+		"04-goroutines-and-channels.go":    true,
+		"19-concurrent-store_test.go":      false,
+		"courses.go":                       false,
+		"main.go":                          false,
+		"04-goroutines-and-channels.go.md": false,
+	} {
+		if got := isLesson(name); got != want {
+			t.Errorf("isLesson(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	src := "# Using `go test`\n\n" +
+		"See [errors](02-errors.go#wrapping), [notes](./notes.md), " +
+		"[other](missing.md) and [Go](https://go.dev/notes.md).\n\n" +
+		"| a | b |\n|---|---|\n| 1 | 2 |\n"
+	html, h1, err := renderMarkdown([]byte(src), map[string]string{
+		"02-errors.go": "02-errors.html",
+		"notes.md":     "notes.html",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != "Using go test" {
+		t.Errorf("h1 = %q, want %q", h1, "Using go test")
+	}
+	for _, want := range []string{
+		`<h1 id="using-go-test">`,
+		`href="02-errors.html#wrapping"`,
+		`href="notes.html"`,
+		`href="missing.md"`,
+		`href="https://go.dev/notes.md"`,
+		`<table>`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("html missing %s:\n%s", want, html)
+		}
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	src, out := t.TempDir(), filepath.Join(t.TempDir(), "site")
+	writeFiles(t, src, map[string]string{
+		"02-maps.md":   "# Maps\n\nBack to [slices](01-slices.md).\n",
+		"01-slices.md": "---\ndescription: Growable arrays\n---\n# Slices\n",
+		"extra.md":     "# Extra\n",
+		"wip.md":       "---\ntitle: Work in progress\ndraft: true\n---\n",
+		"03-types.go":  "package main\n\n// COURSE 3: TYPES\n\nvar x int\n",
+		"notes.txt":    "not markdown",
+	})
+
+	pages, err := Build(Options{Src: src, Out: out, SiteTitle: "Test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, p := range pages {
+		titles = append(titles, p.Title)
+	}
+	// numbered pages first, then the rest; drafts and .go files left out
+	if got := strings.Join(titles, ", "); got != "Slices, Maps, Extra" {
+		t.Errorf("pages = %s, want Slices, Maps, Extra", got)
+	}
+
+	maps := read(t, filepath.Join(out, "02-maps.html"))
+	for _, want := range []string{
+		"<title>Maps - Test</title>",
+		`<a href="01-slices.html">slices</a>`,
+		`class="prev" href="01-slices.html"`,
+		`class="next" href="extra.html"`,
+	} {
+		if !strings.Contains(maps, want) {
+			t.Errorf("02-maps.html missing %s", want)
+		}
+	}
+	index := read(t, filepath.Join(out, "index.html"))
+	if !strings.Contains(index, "Growable arrays") || strings.Contains(index, "Work in progress") {
+		t.Errorf("index.html:\n%s", index)
+	}
+	if _, err := os.Stat(filepath.Join(out, "style.css")); err != nil {
+		t.Error(err)
+	}
+
+	// -lessons and -drafts
+	pages, err = Build(Options{Src: src, Out: out, Lessons: true, Drafts: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 5 || pages[2].Title != "TYPES" {
+		t.Errorf("with lessons and drafts: %d pages, third is %q", len(pages), pages[2].Title)
+	}
+}
+
+func TestBuildCustomTemplates(t *testing.T) {
+	src, tmpl, out := t.TempDir(), t.TempDir(), t.TempDir()
+	writeFiles(t, src, map[string]string{"a.md": "# A\n"})
+	writeFiles(t, tmpl, map[string]string{
+		"layout.html": `{{block "content" .}}{{end}}`,
+		"page.html":   `{{define "content"}}PAGE {{.Page.Title}}{{end}}`,
+		"index.html":  `{{define "content"}}INDEX {{len .Site.Pages}}{{end}}`,
+	})
+
+	if _, err := Build(Options{Src: src, Out: out, Templates: tmpl}); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, filepath.Join(out, "a.html")); got != "PAGE A" {
+		t.Errorf("a.html = %q", got)
+	}
+	if got := read(t, filepath.Join(out, "index.html")); got != "INDEX 1" {
+		t.Errorf("index.html = %q", got)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Build(Options{Src: dir, Out: dir}); err == nil {
+		t.Error("want an error when src and out are the same folder")
+	}
+	if _, err := Build(Options{Src: dir, Out: t.TempDir()}); err == nil {
+		t.Error("want an error for a folder with no pages")
+	}
+	writeFiles(t, dir, map[string]string{"bad.md": "---\norder: x\n---\n"})
+	if _, err := Build(Options{Src: dir, Out: t.TempDir()}); err == nil || !strings.Contains(err.Error(), "bad.md") {
+		t.Errorf("err = %v, want it to name bad.md", err)
+	}
+}
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/service"
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/todo"
+)
+
+// Response is the JSON envelope for every endpoint, as in course 6.
+type Response struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// TodoHandler serves the /todos endpoints.
+type TodoHandler struct {
+	svc *service.TodoService
+}
+
+type createRequest struct {
+	Title string `json:"title"`
+}
+
+// updateRequest uses pointers so "not sent" differs from "false" or ""
+type updateRequest struct {
+	Title *string `json:"title"`
+	Done  *bool   `json:"done"`
+}
+
+const maxBodyBytes = 1 << 20
+
+func (h *TodoHandler) List(w http.ResponseWriter, r *http.Request) {
+	todos, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Response{Success: true, Data: todos})
+}
+
+func (h *TodoHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+
+	t, err := h.svc.Create(r.Context(), req.Title)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, Response{Success: true, Message: "todo created", Data: t})
+}
+
+func (h *TodoHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Response{Success: true, Data: t})
+}
+
+func (h *TodoHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req updateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+
+	t, err := h.svc.Update(r.Context(), id, req.Title, req.Done)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Response{Success: true, Message: "todo updated", Data: t})
+}
+
+func (h *TodoHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		writeJSON(w, http.StatusBadRequest, Response{Error: "invalid todo id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errors.New("invalid JSON: " + err.Error())
+	}
+	return nil
+}
+
+// writeError maps domain errors to status codes. Unknown errors become a
+// generic 500 so internal details never reach the client.
+func writeError(w http.ResponseWriter, err error) {
+	var validationErr *todo.ValidationError
+
+	switch {
+	case errors.Is(err, todo.ErrNotFound):
+		writeJSON(w, http.StatusNotFound, Response{Error: err.Error()})
+	case errors.As(err, &validationErr):
+		writeJSON(w, http.StatusBadRequest, Response{Error: "validation failed", Data: validationErr})
+	default:
+		writeJSON(w, http.StatusInternalServerError, Response{Error: "internal server error"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/database"
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/service"
+)
+
+// newTestServer wires the real router, service and an in-memory database
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	db, err := database.Open(":memory:")
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	svc := service.NewTodoService(database.NewTodoRepository(db))
+	server := httptest.NewServer(NewRouter(svc, log.New(io.Discard, "", 0)))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func do(t *testing.T, method, url, body string) (int, Response) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var resp Response
+	if res.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s %s: decode response: %v", method, url, err)
+		}
+	}
+	return res.StatusCode, resp
+}
+
+func TestTodoAPI(t *testing.T) {
+	server := newTestServer(t)
+	todos := server.URL + "/todos"
+
+	steps := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		wantStatus int
+	}{
+		{"create", http.MethodPost, todos, `{"title":"Learn Go"}`, http.StatusCreated},
+		{"create invalid", http.MethodPost, todos, `{"title":"  "}`, http.StatusBadRequest},
+		{"create unknown field", http.MethodPost, todos, `{"title":"x","owner":"me"}`, http.StatusBadRequest},
+		{"create bad json", http.MethodPost, todos, `{`, http.StatusBadRequest},
+		{"get", http.MethodGet, todos + "/1", "", http.StatusOK},
+		{"get missing", http.MethodGet, todos + "/99", "", http.StatusNotFound},
+		{"get bad id", http.MethodGet, todos + "/abc", "", http.StatusBadRequest},
+		{"mark done", http.MethodPatch, todos + "/1", `{"done":true}`, http.StatusOK},
+		{"list", http.MethodGet, todos, "", http.StatusOK},
+		{"delete", http.MethodDelete, todos + "/1", "", http.StatusNoContent},
+		{"delete again", http.MethodDelete, todos + "/1", "", http.StatusNotFound},
+		{"wrong method", http.MethodPut, todos + "/1", `{}`, http.StatusMethodNotAllowed},
+	}
+
+	for _, step := range steps {
+		// Steps share one server and run in order, so no t.Run parallelism here
+		req, _ := http.NewRequest(step.method, step.url, strings.NewReader(step.body))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != step.wantStatus {
+			t.Errorf("%s: status = %d, want %d", step.name, res.StatusCode, step.wantStatus)
+		}
+	}
+}
+
+func TestTodoAPI_ResponseBodies(t *testing.T) {
+	server := newTestServer(t)
+
+	status, resp := do(t, http.MethodPost, server.URL+"/todos", `{"title":"Ship it"}`)
+	if status != http.StatusCreated || !resp.Success {
+		t.Fatalf("create: %d %+v", status, resp)
+	}
+	created := resp.Data.(map[string]interface{})
+	if created["title"] != "Ship it" || created["done"] != false {
+		t.Errorf("create returned %v", created)
+	}
+
+	_, resp = do(t, http.MethodPatch, server.URL+"/todos/1", `{"title":"Ship it today"}`)
+	if updated := resp.Data.(map[string]interface{}); updated["title"] != "Ship it today" {
+		t.Errorf("update returned %v", updated)
+	}
+
+	_, resp = do(t, http.MethodGet, server.URL+"/todos", "")
+	if list := resp.Data.([]interface{}); len(list) != 1 {
+		t.Errorf("list returned %d todos, want 1", len(list))
+	}
+
+	status, resp = do(t, http.MethodPost, server.URL+"/todos", `{"title":""}`)
+	if status != http.StatusBadRequest || resp.Error != "validation failed" {
+		t.Errorf("validation: %d %+v", status, resp)
+	}
+	if field := resp.Data.(map[string]interface{})["field"]; field != "title" {
+		t.Errorf("validation error field = %v, want title", field)
+	}
+}
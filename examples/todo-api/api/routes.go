@@ -0,0 +1,30 @@
+// Package api wires HTTP routes to the todo service.
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/middleware"
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/service"
+)
+
+// NewRouter returns the complete HTTP handler for the API.
+func NewRouter(svc *service.TodoService, logger *log.Logger) http.Handler {
+	h := &TodoHandler{svc: svc}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", health)
+	mux.HandleFunc("GET /todos", h.List)
+	mux.HandleFunc("POST /todos", h.Create)
+	mux.HandleFunc("GET /todos/{id}", h.Get)
+	mux.HandleFunc("PATCH /todos/{id}", h.Update)
+	mux.HandleFunc("DELETE /todos/{id}", h.Delete)
+
+	// Recover is listed first so it also catches panics in Logging
+	return middleware.Chain(mux, middleware.Recover(logger), middleware.Logging(logger))
+}
+
+func health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Response{Success: true, Message: "ok"})
+}
@@ -0,0 +1,70 @@
+// Command todo-api serves a TODO list over a JSON REST API backed by SQLite.
+//
+//	go run ./cmd/todo-api
+//	curl -X POST localhost:8081/todos -d '{"title":"learn Go"}'
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/api"
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/config"
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/database"
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/service"
+)
+
+func main() {
+	logger := log.New(os.Stdout, "[todo-api] ", log.LstdFlags)
+	if err := run(logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+// run keeps main small: it builds the app and returns instead of exiting,
+// so deferred cleanup always happens.
+func run(logger *log.Logger) error {
+	cfg := config.Load()
+
+	db, err := database.Open(cfg.DBPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	svc := service.NewTodoService(database.NewTodoRepository(db))
+	server := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           api.NewRouter(svc, logger),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// Shut down cleanly on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Printf("listening on %s (db: %s)", cfg.Addr, cfg.DBPath)
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	case <-ctx.Done():
+		logger.Println("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+	return nil
+}
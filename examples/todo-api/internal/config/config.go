@@ -0,0 +1,27 @@
+// Package config loads settings from the environment.
+package config
+
+import (
+	"os"
+)
+
+// Config holds everything the server needs to start.
+type Config struct {
+	Addr   string // TODO_ADDR, default ":8081"
+	DBPath string // TODO_DB, default "todo.db"
+}
+
+// Load reads the configuration, falling back to defaults for unset variables.
+func Load() Config {
+	return Config{
+		Addr:   getEnv("TODO_ADDR", ":8081"),
+		DBPath: getEnv("TODO_DB", "todo.db"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,40 @@
+// Package database opens the SQLite database and stores todos in it.
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3" // registers the "sqlite3" driver
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS todos (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	title      TEXT     NOT NULL,
+	done       BOOLEAN  NOT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL
+)`
+
+// Open connects to the database at path and creates the schema.
+// Use ":memory:" for a throwaway database in tests.
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+
+	// SQLite allows one writer at a time, and every connection to ":memory:"
+	// gets its own empty database - so use a single connection.
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	return db, nil
+}
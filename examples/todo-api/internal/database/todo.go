@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/todo"
+)
+
+// TodoRepository stores todos in SQLite.
+type TodoRepository struct {
+	db *sql.DB
+}
+
+func NewTodoRepository(db *sql.DB) *TodoRepository {
+	return &TodoRepository{db: db}
+}
+
+func (r *TodoRepository) Create(ctx context.Context, t todo.Todo) (todo.Todo, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO todos (title, done, created_at) VALUES (?, ?, ?)`,
+		t.Title, t.Done, t.CreatedAt)
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("insert todo: %w", err)
+	}
+
+	t.ID, err = result.LastInsertId()
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("insert todo: %w", err)
+	}
+	return t, nil
+}
+
+func (r *TodoRepository) Get(ctx context.Context, id int64) (todo.Todo, error) {
+	var t todo.Todo
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, title, done, created_at FROM todos WHERE id = ?`, id).
+		Scan(&t.ID, &t.Title, &t.Done, &t.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return todo.Todo{}, todo.ErrNotFound
+	}
+	if err != nil {
+		return todo.Todo{}, fmt.Errorf("get todo %d: %w", id, err)
+	}
+	return t, nil
+}
+
+func (r *TodoRepository) List(ctx context.Context) ([]todo.Todo, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, title, done, created_at FROM todos ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos := []todo.Todo{} // encode as [] rather than null when empty
+	for rows.Next() {
+		var t todo.Todo
+		if err := rows.Scan(&t.ID, &t.Title, &t.Done, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("list todos: %w", err)
+		}
+		todos = append(todos, t)
+	}
+	return todos, rows.Err()
+}
+
+func (r *TodoRepository) Update(ctx context.Context, t todo.Todo) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE todos SET title = ?, done = ? WHERE id = ?`, t.Title, t.Done, t.ID)
+	if err != nil {
+		return fmt.Errorf("update todo %d: %w", t.ID, err)
+	}
+	return requireRow(result)
+}
+
+func (r *TodoRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM todos WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete todo %d: %w", id, err)
+	}
+	return requireRow(result)
+}
+
+// requireRow turns "no rows affected" into todo.ErrNotFound
+func requireRow(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return todo.ErrNotFound
+	}
+	return nil
+}
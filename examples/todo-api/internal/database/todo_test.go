@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/todo"
+)
+
+func newTestRepo(t *testing.T) *TodoRepository {
+	t.Helper()
+	db, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewTodoRepository(db)
+}
+
+func TestTodoRepository_CRUD(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	created, err := repo.Create(ctx, todo.Todo{Title: "Learn SQL", CreatedAt: now})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.ID == 0 {
+		t.Fatal("Create did not assign an ID")
+	}
+
+	got, err := repo.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Title != "Learn SQL" || got.Done || !got.CreatedAt.Equal(now) {
+		t.Errorf("Get = %+v", got)
+	}
+
+	got.Done = true
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	list, err := repo.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 1 || !list[0].Done {
+		t.Errorf("List = %+v", list)
+	}
+
+	if err := repo.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Get(ctx, created.ID); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTodoRepository_NotFound(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	if err := repo.Update(ctx, todo.Todo{ID: 42, Title: "x"}); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("Update: err = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(ctx, 42); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("Delete: err = %v, want ErrNotFound", err)
+	}
+
+	list, err := repo.List(ctx)
+	if err != nil || list == nil || len(list) != 0 {
+		t.Errorf("List on empty table = %v, %v; want empty non-nil slice", list, err)
+	}
+}
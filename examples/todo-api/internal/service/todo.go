@@ -0,0 +1,75 @@
+// Package service holds the business rules for todos. It depends on a
+// Repository interface, not on SQLite, so it can be tested with a fake.
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/todo"
+)
+
+// Repository is the storage the service needs.
+type Repository interface {
+	Create(ctx context.Context, t todo.Todo) (todo.Todo, error)
+	Get(ctx context.Context, id int64) (todo.Todo, error)
+	List(ctx context.Context) ([]todo.Todo, error)
+	Update(ctx context.Context, t todo.Todo) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// TodoService implements the todo use cases.
+type TodoService struct {
+	repo Repository
+	now  func() time.Time // replaced in tests
+}
+
+func NewTodoService(repo Repository) *TodoService {
+	return &TodoService{repo: repo, now: time.Now}
+}
+
+func (s *TodoService) Create(ctx context.Context, title string) (todo.Todo, error) {
+	if err := todo.ValidateTitle(title); err != nil {
+		return todo.Todo{}, err
+	}
+	return s.repo.Create(ctx, todo.Todo{
+		Title:     strings.TrimSpace(title),
+		CreatedAt: s.now().UTC(),
+	})
+}
+
+func (s *TodoService) Get(ctx context.Context, id int64) (todo.Todo, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *TodoService) List(ctx context.Context) ([]todo.Todo, error) {
+	return s.repo.List(ctx)
+}
+
+// Update changes the fields that are set. A nil field is left as it is.
+func (s *TodoService) Update(ctx context.Context, id int64, title *string, done *bool) (todo.Todo, error) {
+	t, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return todo.Todo{}, err
+	}
+
+	if title != nil {
+		if err := todo.ValidateTitle(*title); err != nil {
+			return todo.Todo{}, err
+		}
+		t.Title = strings.TrimSpace(*title)
+	}
+	if done != nil {
+		t.Done = *done
+	}
+
+	if err := s.repo.Update(ctx, t); err != nil {
+		return todo.Todo{}, err
+	}
+	return t, nil
+}
+
+func (s *TodoService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
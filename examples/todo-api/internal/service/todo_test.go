@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/examples/todo-api/internal/todo"
+)
+
+// fakeRepo keeps todos in a map so the service can be tested without SQLite
+type fakeRepo struct {
+	todos  map[int64]todo.Todo
+	nextID int64
+	err    error // returned by every method when set
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{todos: make(map[int64]todo.Todo)}
+}
+
+func (f *fakeRepo) Create(_ context.Context, t todo.Todo) (todo.Todo, error) {
+	if f.err != nil {
+		return todo.Todo{}, f.err
+	}
+	f.nextID++
+	t.ID = f.nextID
+	f.todos[t.ID] = t
+	return t, nil
+}
+
+func (f *fakeRepo) Get(_ context.Context, id int64) (todo.Todo, error) {
+	if f.err != nil {
+		return todo.Todo{}, f.err
+	}
+	t, ok := f.todos[id]
+	if !ok {
+		return todo.Todo{}, todo.ErrNotFound
+	}
+	return t, nil
+}
+
+func (f *fakeRepo) List(context.Context) ([]todo.Todo, error) {
+	var list []todo.Todo
+	for id := int64(1); id <= f.nextID; id++ {
+		if t, ok := f.todos[id]; ok {
+			list = append(list, t)
+		}
+	}
+	return list, f.err
+}
+
+func (f *fakeRepo) Update(_ context.Context, t todo.Todo) error {
+	if _, ok := f.todos[t.ID]; !ok {
+		return todo.ErrNotFound
+	}
+	f.todos[t.ID] = t
+	return f.err
+}
+
+func (f *fakeRepo) Delete(_ context.Context, id int64) error {
+	if _, ok := f.todos[id]; !ok {
+		return todo.ErrNotFound
+	}
+	delete(f.todos, id)
+	return f.err
+}
+
+func newTestService() (*TodoService, *fakeRepo) {
+	repo := newFakeRepo()
+	svc := NewTodoService(repo)
+	svc.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return svc, repo
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		wantTitle string
+		wantErr   bool
+	}{
+		{"valid", "Buy milk", "Buy milk", false},
+		{"trims spaces", "  Buy milk  ", "Buy milk", false},
+		{"empty", "", "", true},
+		{"only spaces", "   ", "", true},
+		{"too long", string(make([]byte, todo.MaxTitleLength+1)), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService()
+			got, err := svc.Create(context.Background(), tt.title)
+
+			if tt.wantErr {
+				var validationErr *todo.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("err = %v, want a ValidationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Title != tt.wantTitle || got.ID == 0 || got.CreatedAt.Year() != 2024 {
+				t.Errorf("got %+v", got)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	svc, _ := newTestService()
+	ctx := context.Background()
+	created, _ := svc.Create(ctx, "Write tests")
+
+	done := true
+	got, err := svc.Update(ctx, created.ID, nil, &done)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Done || got.Title != "Write tests" {
+		t.Errorf("only Done should change, got %+v", got)
+	}
+
+	empty := ""
+	if _, err := svc.Update(ctx, created.ID, &empty, nil); err == nil {
+		t.Error("expected a validation error for an empty title")
+	}
+
+	if _, err := svc.Update(ctx, 99, nil, &done); !errors.Is(err, todo.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRepositoryErrorsPassThrough(t *testing.T) {
+	svc, repo := newTestService()
+	repo.err = errors.New("disk full")
+
+	if _, err := svc.Create(context.Background(), "anything"); err != repo.err {
+		t.Errorf("err = %v, want %v", err, repo.err)
+	}
+}
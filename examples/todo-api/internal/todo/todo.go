@@ -0,0 +1,44 @@
+// Package todo holds the domain model shared by every layer of the API.
+package todo
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a todo with the given ID does not exist.
+var ErrNotFound = errors.New("todo not found")
+
+// Todo is a single item on the list.
+type Todo struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Done      bool      `json:"done"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ValidationError reports a field that failed validation.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// MaxTitleLength is the longest title the API accepts.
+const MaxTitleLength = 200
+
+// ValidateTitle checks a title before it is stored.
+func ValidateTitle(title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return &ValidationError{Field: "title", Message: "is required"}
+	}
+	if len([]rune(title)) > MaxTitleLength {
+		return &ValidationError{Field: "title", Message: "must be at most 200 characters"}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// The alphabet order is fixed: changing it would break every existing link.
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var errInvalidCode = errors.New("invalid short code")
+
+// encodeBase62 turns a database ID into a short code: 125 -> "21"
+func encodeBase62(n uint64) string {
+	if n == 0 {
+		return string(base62Alphabet[0])
+	}
+
+	var buf [11]byte // 62^11 > 2^64, so 11 characters always fit
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(buf[i:])
+}
+
+// decodeBase62 is the inverse of encodeBase62. It accepts only the codes
+// encodeBase62 makes: "01" would decode to 1 too, and open the same link
+// under a second code with clicks of its own.
+func decodeBase62(code string) (uint64, error) {
+	if code == "" || len(code) > 11 {
+		return 0, errInvalidCode
+	}
+	if len(code) > 1 && code[0] == base62Alphabet[0] {
+		return 0, errInvalidCode
+	}
+
+	var n uint64
+	for i := 0; i < len(code); i++ {
+		digit := strings.IndexByte(base62Alphabet, code[i])
+		if digit < 0 {
+			return 0, errInvalidCode
+		}
+		next := n*62 + uint64(digit)
+		if next/62 != n { // overflowed uint64
+			return 0, errInvalidCode
+		}
+		n = next
+	}
+	return n, nil
+}
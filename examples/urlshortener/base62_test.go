@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodeBase62(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{0, "0"},
+		{1, "1"},
+		{61, "Z"},
+		{62, "10"},
+		{125, "21"},
+		{math.MaxUint64, "lYGhA16ahyf"},
+	}
+
+	for _, tt := range tests {
+		if got := encodeBase62(tt.n); got != tt.want {
+			t.Errorf("encodeBase62(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestBase62RoundTrip(t *testing.T) {
+	for _, n := range []uint64{0, 1, 61, 62, 3843, 3844, 1 << 40, math.MaxUint64} {
+		got, err := decodeBase62(encodeBase62(n))
+		if err != nil || got != n {
+			t.Errorf("round trip %d: got %d, %v", n, got, err)
+		}
+	}
+}
+
+func TestDecodeBase62_Invalid(t *testing.T) {
+	for _, code := range []string{"", "abc-", "a b", "é", "lYGhA16ahyg", "zzzzzzzzzzzz", "00", "01", "0Z", "00000000001"} {
+		if _, err := decodeBase62(code); err != errInvalidCode {
+			t.Errorf("decodeBase62(%q) err = %v, want errInvalidCode", code, err)
+		}
+	}
+}
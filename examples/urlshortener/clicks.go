@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ClickCounter counts redirects per short code. Redirects are the hot path,
+// so counts live in Redis (course 9) instead of an UPDATE per click in SQLite.
+type ClickCounter interface {
+	Incr(ctx context.Context, code string) error
+	Count(ctx context.Context, code string) (int64, error)
+}
+
+// RedisClickCounter stores each count under "clicks:<code>".
+type RedisClickCounter struct {
+	client *redis.Client
+}
+
+func NewRedisClickCounter(client *redis.Client) *RedisClickCounter {
+	return &RedisClickCounter{client: client}
+}
+
+func (c *RedisClickCounter) Incr(ctx context.Context, code string) error {
+	return c.client.Incr(ctx, clickKey(code)).Err()
+}
+
+func (c *RedisClickCounter) Count(ctx context.Context, code string) (int64, error) {
+	n, err := c.client.Get(ctx, clickKey(code)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil // never clicked
+	}
+	return n, err
+}
+
+func clickKey(code string) string {
+	return "clicks:" + code
+}
@@ -0,0 +1,16 @@
+module github.com/owolabijunior12/learning-golang/examples/urlshortener
+
+go 1.25.1
+
+require (
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/mattn/go-sqlite3 v1.14.52
+	github.com/redis/go-redis/v9 v9.22.0
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+)
@@ -0,0 +1,56 @@
+// Command urlshortener turns long URLs into short codes.
+//
+// Links are stored in SQLite, redirect counts in Redis:
+//
+//	docker run --name redis -d -p 6379:6379 redis:latest
+//	go run ./examples/urlshortener
+//	curl -X POST localhost:8082/shorten -d '{"url":"https://go.dev/doc/"}'
+//	curl -i localhost:8082/1
+//	curl localhost:8082/stats/1
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	addr := flag.String("addr", ":8082", "listen address")
+	dbPath := flag.String("db", "links.db", "SQLite database file")
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address")
+	baseURL := flag.String("base-url", "http://localhost:8082", "prefix for generated short links")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[shortener] ", log.LstdFlags)
+	if err := run(*addr, *dbPath, *redisAddr, *baseURL, logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+func run(addr, dbPath, redisAddr, baseURL string, logger *log.Logger) error {
+	links, err := OpenLinkStore(dbPath)
+	if err != nil {
+		return err
+	}
+	defer links.Close()
+
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer rdb.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		// Redirects still work without Redis; only click counts are lost
+		logger.Printf("warning: redis unavailable at %s: %v", redisAddr, err)
+	}
+
+	server := NewServer(links, NewRedisClickCounter(rdb), baseURL, logger)
+	logger.Printf("listening on %s", addr)
+	return http.ListenAndServe(addr, server.Routes())
+}
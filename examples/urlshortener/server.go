@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Server handles the shortener's HTTP API.
+type Server struct {
+	links   *LinkStore
+	clicks  ClickCounter
+	baseURL string // prefix for short links, e.g. "http://localhost:8082"
+	logger  *log.Logger
+}
+
+func NewServer(links *LinkStore, clicks ClickCounter, baseURL string, logger *log.Logger) *Server {
+	return &Server{
+		links:   links,
+		clicks:  clicks,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		logger:  logger,
+	}
+}
+
+func (s *Server) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /shorten", s.handleShorten)
+	mux.HandleFunc("GET /stats/{code}", s.handleStats)
+	mux.HandleFunc("GET /{code}", s.handleRedirect)
+	return mux
+}
+
+type shortenRequest struct {
+	URL string `json:"url"`
+}
+
+type shortenResponse struct {
+	Code     string `json:"code"`
+	ShortURL string `json:"short_url"`
+	URL      string `json:"url"`
+}
+
+type statsResponse struct {
+	Code      string    `json:"code"`
+	URL       string    `json:"url"`
+	Clicks    int64     `json:"clicks"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleShorten(w http.ResponseWriter, r *http.Request) {
+	var req shortenRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 8<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid JSON"})
+		return
+	}
+	if err := validateTarget(req.URL); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	link, err := s.links.Create(r.Context(), req.URL)
+	if err != nil {
+		s.logger.Printf("shorten %q: %v", req.URL, err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "internal server error"})
+		return
+	}
+
+	code := encodeBase62(link.ID)
+	writeJSON(w, http.StatusCreated, shortenResponse{
+		Code:     code,
+		ShortURL: s.baseURL + "/" + code,
+		URL:      link.URL,
+	})
+}
+
+func (s *Server) handleRedirect(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	link, ok := s.lookup(w, r, code)
+	if !ok {
+		return
+	}
+
+	// A Redis outage must not break redirects - log it and carry on
+	if err := s.clicks.Incr(r.Context(), code); err != nil {
+		s.logger.Printf("count click for %s: %v", code, err)
+	}
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+	link, ok := s.lookup(w, r, code)
+	if !ok {
+		return
+	}
+
+	clicks, err := s.clicks.Count(r.Context(), code)
+	if err != nil {
+		s.logger.Printf("read clicks for %s: %v", code, err)
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "click counts are unavailable"})
+		return
+	}
+	writeJSON(w, http.StatusOK, statsResponse{
+		Code:      code,
+		URL:       link.URL,
+		Clicks:    clicks,
+		CreatedAt: link.CreatedAt,
+	})
+}
+
+// lookup resolves a code to its link, writing a 404 or 500 when it can't
+func (s *Server) lookup(w http.ResponseWriter, r *http.Request, code string) (Link, bool) {
+	id, err := decodeBase62(code)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: errLinkNotFound.Error()})
+		return Link{}, false
+	}
+
+	link, err := s.links.Get(r.Context(), id)
+	if errors.Is(err, errLinkNotFound) {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+		return Link{}, false
+	}
+	if err != nil {
+		s.logger.Printf("lookup %s: %v", code, err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "internal server error"})
+		return Link{}, false
+	}
+	return link, true
+}
+
+// validateTarget accepts absolute http and https URLs only. Anything else
+// (javascript:, file:, relative paths) could be abused through a redirect.
+func validateTarget(raw string) error {
+	if raw == "" {
+		return errors.New("url is required")
+	}
+	if len(raw) > 2048 {
+		return errors.New("url is too long")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testEnv is a server backed by in-memory SQLite and an in-process Redis
+type testEnv struct {
+	server *Server
+	redis  *miniredis.Miniredis
+	logs   *strings.Builder
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	links, err := OpenLinkStore(":memory:")
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { links.Close() })
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	logs := &strings.Builder{}
+	server := NewServer(links, NewRedisClickCounter(rdb), "http://sho.rt/", log.New(logs, "", 0))
+	return &testEnv{server: server, redis: mr, logs: logs}
+}
+
+func (e *testEnv) do(method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	e.server.Routes().ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func (e *testEnv) shorten(t *testing.T, target string) shortenResponse {
+	t.Helper()
+	rec := e.do(http.MethodPost, "/shorten", `{"url":"`+target+`"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("shorten %s: %d %s", target, rec.Code, rec.Body.String())
+	}
+	var resp shortenResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestShorten(t *testing.T) {
+	env := newTestEnv(t)
+
+	first := env.shorten(t, "https://go.dev/doc/")
+	second := env.shorten(t, "https://go.dev/blog/")
+
+	if first.Code != "1" || second.Code != "2" {
+		t.Errorf("codes = %q, %q; want 1, 2", first.Code, second.Code)
+	}
+	if first.ShortURL != "http://sho.rt/1" {
+		t.Errorf("short_url = %q", first.ShortURL)
+	}
+}
+
+func TestShorten_Invalid(t *testing.T) {
+	env := newTestEnv(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"bad json", `{`},
+		{"missing url", `{}`},
+		{"relative", `{"url":"/local/path"}`},
+		{"javascript", `{"url":"javascript:alert(1)"}`},
+		{"no host", `{"url":"https://"}`},
+		{"too long", `{"url":"https://example.com/` + strings.Repeat("a", 2048) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := env.do(http.MethodPost, "/shorten", tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (%s)", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRedirectCountsClicks(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.shorten(t, "https://go.dev/")
+
+	for i := 0; i < 3; i++ {
+		rec := env.do(http.MethodGet, "/"+link.Code, "")
+		if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://go.dev/" {
+			t.Fatalf("redirect: %d Location=%q", rec.Code, rec.Header().Get("Location"))
+		}
+	}
+
+	rec := env.do(http.MethodGet, "/stats/"+link.Code, "")
+	var stats statsResponse
+	json.NewDecoder(rec.Body).Decode(&stats)
+	if rec.Code != http.StatusOK || stats.Clicks != 3 || stats.URL != "https://go.dev/" {
+		t.Errorf("stats: %d %+v", rec.Code, stats)
+	}
+
+	if got, _ := env.redis.Get("clicks:" + link.Code); got != "3" {
+		t.Errorf("redis clicks key = %q, want 3", got)
+	}
+
+	// The same ID with a leading zero is no code at all, so it can't open
+	// the link and count its clicks apart
+	for _, path := range []string{"/0" + link.Code, "/stats/0" + link.Code} {
+		if rec := env.do(http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s: status = %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func TestStats_NoClicksYet(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.shorten(t, "https://go.dev/")
+
+	rec := env.do(http.MethodGet, "/stats/"+link.Code, "")
+	var stats statsResponse
+	json.NewDecoder(rec.Body).Decode(&stats)
+	if stats.Clicks != 0 || stats.CreatedAt.IsZero() {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestUnknownCodes(t *testing.T) {
+	env := newTestEnv(t)
+
+	for _, path := range []string{"/9", "/not-base62!", "/stats/9", "/stats/%2F"} {
+		if rec := env.do(http.MethodGet, path, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s: status = %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func TestRedisDown(t *testing.T) {
+	env := newTestEnv(t)
+	link := env.shorten(t, "https://go.dev/")
+	env.redis.Close()
+
+	// Redirects keep working...
+	if rec := env.do(http.MethodGet, "/"+link.Code, ""); rec.Code != http.StatusFound {
+		t.Errorf("redirect with redis down: status = %d, want 302", rec.Code)
+	}
+	if !strings.Contains(env.logs.String(), "count click") {
+		t.Errorf("expected the click failure to be logged, got %q", env.logs.String())
+	}
+
+	// ...but stats cannot be served
+	if rec := env.do(http.MethodGet, "/stats/"+link.Code, ""); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("stats with redis down: status = %d, want 503", rec.Code)
+	}
+}
+
+func TestDatabaseErrors(t *testing.T) {
+	env := newTestEnv(t)
+	env.server.links.Close()
+
+	if rec := env.do(http.MethodPost, "/shorten", `{"url":"https://go.dev/"}`); rec.Code != http.StatusInternalServerError {
+		t.Errorf("shorten with closed db: status = %d, want 500", rec.Code)
+	}
+	if rec := env.do(http.MethodGet, "/1", ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("redirect with closed db: status = %d, want 500", rec.Code)
+	}
+	if !strings.Contains(env.logs.String(), "sql: database is closed") {
+		t.Errorf("expected database errors to be logged, got %q", env.logs.String())
+	}
+}
+
+func TestLinkStore_NotFound(t *testing.T) {
+	links, err := OpenLinkStore(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer links.Close()
+
+	if _, err := links.Get(context.Background(), 1); !errors.Is(err, errLinkNotFound) {
+		t.Errorf("err = %v, want errLinkNotFound", err)
+	}
+}
+
+func TestOpenLinkStore_BadPath(t *testing.T) {
+	if _, err := OpenLinkStore("/nonexistent-dir/links.db"); err == nil {
+		t.Error("expected an error for a path that cannot be created")
+	}
+}
+
+// An end-to-end check through a real HTTP server: the client must not follow
+// the redirect so the 302 itself can be inspected.
+func TestEndToEnd(t *testing.T) {
+	env := newTestEnv(t)
+	ts := httptest.NewServer(env.server.Routes())
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL+"/shorten", "application/json", strings.NewReader(`{"url":"https://example.com/a"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var link shortenResponse
+	json.NewDecoder(res.Body).Decode(&link)
+	res.Body.Close()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err = client.Get(ts.URL + "/" + link.Code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusFound || res.Header.Get("Location") != "https://example.com/a" {
+		t.Errorf("got %d Location=%q", res.StatusCode, res.Header.Get("Location"))
+	}
+}
+
+func TestRun_BadDatabase(t *testing.T) {
+	err := run(":0", "/nonexistent-dir/links.db", "localhost:0", "http://sho.rt", log.New(io.Discard, "", 0))
+	if err == nil {
+		t.Error("run should fail when the database cannot be opened")
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // registers the "sqlite3" driver
+)
+
+var errLinkNotFound = errors.New("link not found")
+
+// Link is a stored short URL.
+type Link struct {
+	ID        uint64
+	URL       string
+	CreatedAt time.Time
+}
+
+// LinkStore persists links in SQLite. The short code is the base62 form of
+// the row ID, so it never has to be stored.
+type LinkStore struct {
+	db *sql.DB
+}
+
+func OpenLinkStore(path string) (*LinkStore, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1) // one writer; also keeps ":memory:" to a single database
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS links (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		url        TEXT     NOT NULL,
+		created_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create links table: %w", err)
+	}
+	return &LinkStore{db: db}, nil
+}
+
+func (s *LinkStore) Create(ctx context.Context, url string) (Link, error) {
+	link := Link{URL: url, CreatedAt: time.Now().UTC()}
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO links (url, created_at) VALUES (?, ?)`, link.URL, link.CreatedAt)
+	if err != nil {
+		return Link{}, fmt.Errorf("insert link: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return Link{}, fmt.Errorf("insert link: %w", err)
+	}
+	link.ID = uint64(id)
+	return link, nil
+}
+
+func (s *LinkStore) Get(ctx context.Context, id uint64) (Link, error) {
+	link := Link{ID: id}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT url, created_at FROM links WHERE id = ?`, int64(id)).Scan(&link.URL, &link.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Link{}, errLinkNotFound
+	}
+	if err != nil {
+		return Link{}, fmt.Errorf("get link %d: %w", id, err)
+	}
+	return link, nil
+}
+
+func (s *LinkStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Event is what clients receive, as JSON: one message published on a Redis
+// channel.
+type Event struct {
+	Channel    string    `json:"channel"`
+	Payload    string    `json:"payload"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// Bridge fans messages from Redis channels out to WebSocket clients. Like
+// the chat hub, only the Run goroutine touches the clients map; the HTTP
+// handlers and the clients' goroutines talk to it through channels
+// (course 4).
+type Bridge struct {
+	pubsub     *redis.PubSub
+	channels   []string
+	clients    map[*Client]bool
+	register   chan *Client
+	unregister chan *Client
+	done       chan struct{}  // closed when Run stops accepting clients
+	pumps      sync.WaitGroup // the clients' read and write goroutines
+	connected  atomic.Int64   // len(clients), for other goroutines to read
+	logger     *log.Logger
+}
+
+// Subscribe subscribes to channels and waits for Redis to confirm, so that
+// nothing published once it returns is missed.
+func Subscribe(ctx context.Context, client *redis.Client, channels []string, logger *log.Logger) (*Bridge, error) {
+	pubsub := client.Subscribe(ctx, channels...)
+	for range channels {
+		if _, err := pubsub.ReceiveTimeout(ctx, 2*time.Second); err != nil {
+			pubsub.Close()
+			return nil, fmt.Errorf("subscribe to %v: %w", channels, err)
+		}
+	}
+	return &Bridge{
+		pubsub:     pubsub,
+		channels:   channels,
+		clients:    make(map[*Client]bool),
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		done:       make(chan struct{}),
+		logger:     logger,
+	}, nil
+}
+
+// Serves reports whether clients may subscribe to channel.
+func (b *Bridge) Serves(channel string) bool {
+	return slices.Contains(b.channels, channel)
+}
+
+// Clients reports how many clients are connected.
+func (b *Bridge) Clients() int {
+	return int(b.connected.Load())
+}
+
+// Run delivers messages until ctx is cancelled. Then it unsubscribes, sends
+// every client a close frame, and returns once their goroutines have
+// finished.
+func (b *Bridge) Run(ctx context.Context) {
+	// go-redis reconnects and resubscribes by itself if the connection
+	// drops; what was published in between is lost, as Pub/Sub never
+	// stores messages
+	messages := b.pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			b.shutdown()
+			return
+
+		case c := <-b.register:
+			b.clients[c] = true
+			b.connected.Add(1)
+			b.pumps.Go(c.writePump)
+			b.pumps.Go(c.readPump)
+
+		case c := <-b.unregister:
+			if b.clients[c] {
+				b.remove(c)
+			}
+
+		case msg, ok := <-messages:
+			if !ok {
+				b.shutdown() // the subscription was closed under us
+				return
+			}
+			b.deliver(Event{Channel: msg.Channel, Payload: msg.Payload, ReceivedAt: time.Now().UTC()})
+		}
+	}
+}
+
+// shutdown runs on the Run goroutine: after close(b.done), Register and
+// Unregister no longer wait for it, so the pumps can finish
+func (b *Bridge) shutdown() {
+	if err := b.pubsub.Close(); err != nil {
+		b.logger.Printf("unsubscribe: %v", err)
+	}
+	b.logger.Printf("closing %d clients", len(b.clients))
+	for c := range b.clients {
+		b.remove(c)
+	}
+	close(b.done)
+	b.pumps.Wait()
+}
+
+// Register and Unregister hand clients to Run. Register reports false once
+// the bridge is shutting down.
+func (b *Bridge) Register(c *Client) bool {
+	select {
+	case b.register <- c:
+		return true
+	case <-b.done:
+		return false
+	}
+}
+
+func (b *Bridge) Unregister(c *Client) {
+	select {
+	case b.unregister <- c:
+	case <-b.done:
+	}
+}
+
+// deliver queues ev for every client of its channel. A client whose
+// buffer is full can't keep up; it is dropped rather than holding up the
+// others.
+func (b *Bridge) deliver(ev Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		b.logger.Printf("encode event: %v", err)
+		return
+	}
+	for c := range b.clients {
+		if c.channel != ev.Channel {
+			continue
+		}
+		select {
+		case c.send <- data:
+		default:
+			b.logger.Printf("dropping slow client %s on %s", c.conn.RemoteAddr(), c.channel)
+			b.remove(c)
+		}
+	}
+}
+
+func (b *Bridge) remove(c *Client) {
+	delete(b.clients, c)
+	b.connected.Add(-1)
+	close(c.send) // tells the client's writePump to say goodbye and stop
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
+)
+
+type testBridge struct {
+	server  *httptest.Server
+	redis   *miniredis.Miniredis
+	bridge  *Bridge
+	cancel  context.CancelFunc
+	stopped chan struct{} // closed when Run returns
+}
+
+func newTestBridge(t *testing.T, channels ...string) *testBridge {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bridge, err := Subscribe(ctx, rdb, channels, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		bridge.Run(ctx)
+		close(stopped)
+	}()
+
+	server := httptest.NewServer(Routes(bridge))
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+		server.Close()
+	})
+	return &testBridge{server: server, redis: mr, bridge: bridge, cancel: cancel, stopped: stopped}
+}
+
+// dial connects to channel and waits until the bridge has registered the
+// client, so that nothing published afterwards is missed
+func (tb *testBridge) dial(t *testing.T, channel string) *websocket.Conn {
+	t.Helper()
+	before := tb.bridge.Clients()
+	url := "ws" + strings.TrimPrefix(tb.server.URL, "http") + "/ws?channel=" + channel
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial %s: %v", channel, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	waitFor(t, func() bool { return tb.bridge.Clients() == before+1 })
+	return conn
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func next(t *testing.T, conn *websocket.Conn) Event {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var ev Event
+	if err := conn.ReadJSON(&ev); err != nil {
+		t.Fatalf("waiting for an event: %v", err)
+	}
+	return ev
+}
+
+func TestFanOut(t *testing.T) {
+	tb := newTestBridge(t, "news", "alerts")
+	alice := tb.dial(t, "news")
+	bob := tb.dial(t, "news")
+	carol := tb.dial(t, "alerts")
+
+	tb.redis.Publish("news", "hello")
+	tb.redis.Publish("alerts", "disk full")
+	tb.redis.Publish("news", "second")
+
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		for _, want := range []string{"hello", "second"} {
+			if ev := next(t, conn); ev.Channel != "news" || ev.Payload != want || ev.ReceivedAt.IsZero() {
+				t.Errorf("got %+v, want %q on news", ev, want)
+			}
+		}
+	}
+	// carol's first event is from her channel, not news
+	if ev := next(t, carol); ev.Channel != "alerts" || ev.Payload != "disk full" {
+		t.Errorf("carol got %+v", ev)
+	}
+}
+
+func TestPublishedThroughGoRedis(t *testing.T) {
+	tb := newTestBridge(t, "news")
+	conn := tb.dial(t, "") // the first channel by default
+
+	rdb := redis.NewClient(&redis.Options{Addr: tb.redis.Addr()})
+	defer rdb.Close()
+	if n, err := rdb.Publish(context.Background(), "news", `{"id":7}`).Result(); err != nil || n != 1 {
+		t.Fatalf("PUBLISH reached %d subscribers, %v; want the bridge", n, err)
+	}
+	if ev := next(t, conn); ev.Payload != `{"id":7}` {
+		t.Errorf("got %+v", ev)
+	}
+}
+
+func TestUnknownChannel(t *testing.T) {
+	tb := newTestBridge(t, "news")
+	res, err := http.Get(tb.server.URL + "/ws?channel=secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", res.StatusCode)
+	}
+}
+
+func TestClientLeaves(t *testing.T) {
+	tb := newTestBridge(t, "news")
+	alice := tb.dial(t, "news")
+	bob := tb.dial(t, "news")
+
+	alice.Close()
+	waitFor(t, func() bool { return tb.bridge.Clients() == 1 })
+
+	tb.redis.Publish("news", "still here")
+	if ev := next(t, bob); ev.Payload != "still here" {
+		t.Errorf("bob got %+v", ev)
+	}
+}
+
+func TestShutdownClosesClients(t *testing.T) {
+	tb := newTestBridge(t, "news")
+	conns := []*websocket.Conn{tb.dial(t, "news"), tb.dial(t, "news")}
+
+	tb.cancel()
+	select {
+	case <-tb.stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	// Run returned only after every client got a close frame
+	for _, conn := range conns {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, _, err := conn.ReadMessage()
+		if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Errorf("expected a going-away close frame, got %v", err)
+		}
+	}
+	if tb.bridge.Clients() != 0 {
+		t.Errorf("%d clients left after shutdown", tb.bridge.Clients())
+	}
+	waitFor(t, func() bool { return tb.redis.PubSubNumSub("news")["news"] == 0 })
+
+	// A latecomer is turned away with a close frame too
+	url := "ws" + strings.TrimPrefix(tb.server.URL, "http") + "/ws"
+	late, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer late.Close()
+	late.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := late.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("latecomer: got %v", err)
+	}
+}
+
+func TestSubscribeWithRedisDown(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer rdb.Close()
+	mr.Close()
+
+	if _, err := Subscribe(context.Background(), rdb, []string{"news"}, log.New(io.Discard, "", 0)); err == nil {
+		t.Error("Subscribe succeeded with Redis down")
+	}
+}
+
+func TestRunShutsDownOnCancel(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() { errc <- run(ctx, "localhost:0", mr.Addr(), []string{"news"}, log.New(io.Discard, "", 0)) }()
+
+	waitFor(t, func() bool { return mr.PubSubNumSub("news")["news"] == 1 })
+	cancel()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Errorf("run = %v, want a clean shutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return after cancel")
+	}
+}
+
+func TestServesHTMLClient(t *testing.T) {
+	tb := newTestBridge(t, "news")
+	res, err := http.Get(tb.server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), "new WebSocket") {
+		t.Errorf("GET / = %d, body does not look like the client", res.StatusCode)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second    // time allowed to write one message
+	pongWait       = 60 * time.Second    // time allowed between pongs from the browser
+	pingPeriod     = (pongWait * 9) / 10 // send pings a bit more often than pongWait
+	maxMessageSize = 512                 // bytes; clients only listen
+	sendBuffer     = 64                  // queued outgoing events per client
+)
+
+// Client is one WebSocket connection, listening to one channel. Only
+// writePump writes to the connection, because gorilla/websocket allows one
+// writer; readPump is there to answer pings and notice the browser leaving.
+type Client struct {
+	bridge  *Bridge
+	conn    *websocket.Conn
+	send    chan []byte
+	channel string
+}
+
+func (c *Client) readPump() {
+	defer func() {
+		c.bridge.Unregister(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	// The bridge is one-way: anything the browser sends is read and dropped
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				c.bridge.logger.Printf("read from %s: %v", c.conn.RemoteAddr(), err)
+			}
+			return
+		}
+	}
+}
+
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case data, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// The bridge closed the channel: it is shutting down, or
+				// this client fell behind
+				c.conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "bridge closed the stream"))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Command wsbridge streams messages published on Redis channels to
+// browsers over WebSockets.
+//
+// It ties together goroutines and channels (course 4), HTTP (course 6) and
+// Redis Pub/Sub (course 9):
+//
+//	docker run --name redis -d -p 6379:6379 redis:latest
+//	go run ./examples/wsbridge -channels news,alerts
+//	open http://localhost:8088, then
+//	docker exec redis redis-cli PUBLISH news "hello"
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func main() {
+	addr := flag.String("addr", ":8088", "listen address")
+	redisAddr := flag.String("redis", "localhost:6379", "Redis address")
+	channels := flag.String("channels", "news", "comma-separated Redis channels to bridge")
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[wsbridge] ", log.LstdFlags)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, *addr, *redisAddr, strings.Split(*channels, ","), logger); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+// run serves until ctx is cancelled, then shuts down in order: stop
+// accepting connections, unsubscribe, close every socket with a close
+// frame, and return once all of it has finished.
+func run(ctx context.Context, addr, redisAddr string, channels []string, logger *log.Logger) error {
+	rdb := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer rdb.Close()
+
+	// Unlike the chat history, the bridge is nothing without Redis
+	bridge, err := Subscribe(ctx, rdb, channels, logger)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Go(func() { bridge.Run(ctx) })
+
+	server := &http.Server{Addr: addr, Handler: Routes(bridge), ReadHeaderTimeout: 5 * time.Second}
+	wg.Go(func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// Shutdown doesn't wait for hijacked WebSocket connections; the
+		// bridge closes those
+		server.Shutdown(shutdownCtx)
+	})
+
+	logger.Printf("bridging %v; open http://localhost%s", channels, addr)
+	err = server.ListenAndServe()
+	cancel() // if the listener failed, stop the bridge too
+	wg.Wait()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logger.Print("all clients closed, bye")
+	return nil
+}
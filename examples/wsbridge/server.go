@@ -0,0 +1,56 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// The default CheckOrigin rejects cross-origin pages: only the page
+	// served here may open a socket.
+}
+
+// Routes serves the HTML client at / and the event stream at /ws.
+func Routes(bridge *Bridge) http.Handler {
+	static, _ := fs.Sub(staticFiles, "static") // cannot fail: the directory is embedded
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /ws", func(w http.ResponseWriter, r *http.Request) {
+		serveWS(bridge, w, r)
+	})
+	return mux
+}
+
+// serveWS upgrades the request and streams one channel to it:
+// /ws?channel=news, or the first channel the bridge serves.
+func serveWS(bridge *Bridge, w http.ResponseWriter, r *http.Request) {
+	channel := r.URL.Query().Get("channel")
+	if channel == "" {
+		channel = bridge.channels[0]
+	}
+	if !bridge.Serves(channel) {
+		http.Error(w, "unknown channel "+channel, http.StatusNotFound)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already written an HTTP error
+	}
+
+	client := &Client{bridge: bridge, conn: conn, send: make(chan []byte, sendBuffer), channel: channel}
+	if !bridge.Register(client) {
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "bridge is shutting down"))
+		conn.Close()
+	}
+}
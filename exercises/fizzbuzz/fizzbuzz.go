@@ -0,0 +1,14 @@
+// Exercise fizzbuzz (course 1: basics)
+//
+// FizzBuzz returns the numbers 1..n as strings, except that multiples of 3
+// are "Fizz", multiples of 5 are "Buzz" and multiples of both are
+// "FizzBuzz". FizzBuzz(5) is ["1" "2" "Fizz" "4" "Buzz"]; n < 1 gives an
+// empty slice.
+//
+// Grade it with: go run ./cmd/learn grade fizzbuzz
+package fizzbuzz
+
+func FizzBuzz(n int) []string {
+	// TODO: loop, %, and strconv.Itoa
+	return nil
+}
@@ -0,0 +1,14 @@
+// Exercise parallelsum (course 4: goroutines and channels)
+//
+// Sum adds up nums using the given number of goroutines, each summing one
+// chunk and sending its partial sum back on a channel. workers < 1 means 1,
+// and more workers than numbers must still work. Check your solution with
+// go test -race too: the grader only checks the answers.
+//
+// Grade it with: go run ./cmd/learn grade parallelsum
+package parallelsum
+
+func Sum(nums []int, workers int) int {
+	// TODO: split nums into chunks, one goroutine per chunk
+	return 0
+}
@@ -0,0 +1,12 @@
+// Exercise reverse (course 1: basics)
+//
+// Reverse returns s with its characters in reverse order. Characters, not
+// bytes: Reverse("héllo") is "olléh", and "日本" becomes "本日".
+//
+// Grade it with: go run ./cmd/learn grade reverse
+package reverse
+
+func Reverse(s string) string {
+	// TODO: a string is bytes; []rune(s) is characters
+	return s
+}
@@ -0,0 +1,25 @@
+// Exercise safedivide (course 2: functions and errors)
+//
+// Divide returns a / b. Dividing by zero must not panic: return
+// ErrDivideByZero instead, so callers can check it with errors.Is.
+//
+// ParseAndDivide parses two decimal strings and divides them. A bad number
+// returns an error that wraps the strconv error (use %w) and names the
+// input that was wrong.
+//
+// Grade it with: go run ./cmd/learn grade safedivide
+package safedivide
+
+import "errors"
+
+var ErrDivideByZero = errors.New("division by zero")
+
+func Divide(a, b int) (int, error) {
+	// TODO
+	return 0, nil
+}
+
+func ParseAndDivide(a, b string) (int, error) {
+	// TODO: strconv.Atoi, then Divide
+	return 0, nil
+}
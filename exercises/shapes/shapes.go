@@ -0,0 +1,32 @@
+// Exercise shapes (course 3: structs and interfaces)
+//
+// Give Rect and Circle Area and Perimeter methods so both satisfy Shape,
+// then write Largest, which returns the shape with the biggest area (nil
+// for an empty slice).
+//
+// Grade it with: go run ./cmd/learn grade shapes
+package shapes
+
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+type Rect struct {
+	Width, Height float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+func (r Rect) Area() float64      { return 0 } // TODO
+func (r Rect) Perimeter() float64 { return 0 } // TODO
+
+func (c Circle) Area() float64      { return 0 } // TODO: math.Pi
+func (c Circle) Perimeter() float64 { return 0 } // TODO
+
+func Largest(shapes []Shape) Shape {
+	// TODO
+	return nil
+}
@@ -0,0 +1,17 @@
+// Exercise wordcount (course 20: io streams)
+//
+// CountWords reads r to the end and counts each word, lowercased, with
+// surrounding punctuation trimmed ("Go," and "go" are the same word). It
+// must work on input far bigger than memory, so read it as a stream
+// (bufio.Scanner with bufio.ScanWords) rather than io.ReadAll. A read
+// error is returned.
+//
+// Grade it with: go run ./cmd/learn grade wordcount
+package wordcount
+
+import "io"
+
+func CountWords(r io.Reader) (map[string]int, error) {
+	// TODO
+	return nil, nil
+}
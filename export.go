@@ -0,0 +1,447 @@
+package learn
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"html"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The templates and stylesheets of each format are compiled in, so export
+// works from any directory.
+//
+//go:embed templates
+var exportTemplates embed.FS
+
+// The course sources, so each section of the site can show its demo code.
+//
+//go:embed internal/courses/*/[0-9][0-9]-*.go
+var courseSources embed.FS
+
+// exporters are the formats "go run ./cmd/learn export FORMAT" can write.
+var exporters = map[string]func(args []string) error{
+	"html": exportHTML,
+	"epub": exportEPUB,
+	"pdf":  exportPDF,
+}
+
+// runExport implements "go run ./cmd/learn export format [flags]".
+func runExport(args []string) error {
+	if len(args) == 0 || exporters[args[0]] == nil {
+		fmt.Fprintln(os.Stderr, "usage: go run ./cmd/learn export format [flags]")
+		fmt.Fprintln(os.Stderr, "formats: html, epub, pdf")
+		if len(args) == 0 {
+			return errors.New("name a format to export")
+		}
+		return fmt.Errorf("unknown export format %q", args[0])
+	}
+	return exporters[args[0]](args[1:])
+}
+
+func exportHTML(args []string) error {
+	flags := flag.NewFlagSet("export html", flag.ContinueOnError)
+	out := flags.String("out", "site", "folder to write the site to")
+	withNotes := flags.Bool("notes", false, "include your notes (go run ./cmd/learn note) next to the sections")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn export html [flags]")
+		fmt.Fprintln(flags.Output(), "Writes every course, its key takeaways and the exercises as a static website.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	book, err := loadBook(".html")
+	if err != nil {
+		return err
+	}
+	if *withNotes {
+		if err := exportNotes(book); err != nil {
+			return err
+		}
+	}
+	if err := writeSite(*out, book); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d courses to %s; open %s\n", len(book.Courses), *out, filepath.Join(*out, "index.html"))
+	return nil
+}
+
+// book is the whole course as data, ready for a template.
+type book struct {
+	Title     string
+	Courses   []*bookCourse
+	Exercises []*bookExercise
+}
+
+type bookCourse struct {
+	Number      int
+	Name        string
+	Description string
+	Title       string // from the lesson, e.g. "GOROUTINES AND CHANNELS"
+	File        string // the course's Go file
+	URL         string // page name, e.g. "04-goroutines-and-channels.html"
+	Intro       template.HTML
+	Sections    []bookSection
+	Takeaways   []template.HTML
+	Exercises   []*bookExercise
+	Notes       []bookNote // the learner's, on the course as a whole
+	Prev, Next  *bookCourse
+
+	// The same text as Markdown, for formats that aren't HTML
+	intro     []string
+	takeaways []string
+}
+
+type bookSection struct {
+	ID, Title string
+	Body      template.HTML
+	Demo      template.HTML // the code the course runs in this section
+	Notes     []bookNote
+
+	text []string
+	demo string
+}
+
+type bookExercise struct {
+	Name   string
+	Course *bookCourse
+	Task   template.HTML // from the comment at the top of the exercise file
+
+	task []string
+}
+
+// loadBook reads every course's lesson and every exercise's task. Course
+// pages are named after the course file, with the extension ext.
+func loadBook(ext string) (*book, error) {
+	b := &book{Title: "Complete Go Developer Learning Course"}
+	for _, c := range courses {
+		l, err := loadLesson(c.number)
+		if err != nil {
+			return nil, err
+		}
+		src, err := courseSources.ReadFile(c.source())
+		if err != nil {
+			return nil, err
+		}
+		demos, err := sectionDemos(c.source(), src)
+		if err != nil {
+			return nil, err
+		}
+		bc := &bookCourse{
+			Number:      c.number,
+			Name:        c.name,
+			Description: c.description,
+			Title:       l.Title,
+			File:        c.file,
+			URL:         strings.TrimSuffix(c.file, ".go") + ext,
+			intro:       joinParts(l.Sections[0].Parts),
+			takeaways:   l.Takeaways,
+		}
+		bc.Intro = markdownHTML(bc.intro)
+		for _, s := range l.Sections[1:] {
+			bs := bookSection{ID: s.ID, Title: s.Title, text: joinParts(s.Parts), demo: demos[s.ID]}
+			bs.Body = markdownHTML(bs.text)
+			if bs.demo != "" {
+				bs.Demo = markdownHTML([]string{"```go", bs.demo, "```"})
+			}
+			bc.Sections = append(bc.Sections, bs)
+		}
+		for _, t := range l.Takeaways {
+			bc.Takeaways = append(bc.Takeaways, inlineHTML(t))
+		}
+		if n := len(b.Courses); n > 0 {
+			bc.Prev, b.Courses[n-1].Next = b.Courses[n-1], bc
+		}
+		b.Courses = append(b.Courses, bc)
+	}
+
+	for _, ex := range exerciseList {
+		task, err := exerciseTask(ex.name)
+		if err != nil {
+			return nil, err
+		}
+		be := &bookExercise{Name: ex.name, Task: markdownHTML(task), task: task}
+		for _, bc := range b.Courses {
+			if bc.Number == ex.course {
+				be.Course = bc
+				bc.Exercises = append(bc.Exercises, be)
+			}
+		}
+		b.Exercises = append(b.Exercises, be)
+	}
+	return b, nil
+}
+
+// joinParts puts a section's text back together. The demo output that
+// goes between the parts only exists when the course runs.
+func joinParts(parts [][]string) []string {
+	var lines []string
+	for _, p := range parts {
+		lines = append(append(lines, p...), "")
+	}
+	return lines
+}
+
+// sectionDemos finds the code a course function runs in each section: the
+// statements after l.Section("id"), up to the next section or l.End().
+func sectionDemos(file string, src []byte) (map[string]string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	// lessonCall returns the method name of an l.xxx(...) statement
+	lessonCall := func(stmt ast.Stmt) (name string, call *ast.CallExpr) {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			return "", nil
+		}
+		call, ok = expr.X.(*ast.CallExpr)
+		if !ok {
+			return "", nil
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", nil
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != "l" {
+			return "", nil
+		}
+		return sel.Sel.Name, call
+	}
+
+	demos := make(map[string]string)
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		id, start := "", token.NoPos
+		for _, stmt := range fn.Body.List {
+			name, call := lessonCall(stmt)
+			if name != "Section" && name != "End" {
+				continue
+			}
+			if id != "" {
+				demos[id] = demoText(src[fset.Position(start).Offset:fset.Position(stmt.Pos()).Offset])
+			}
+			id, start = "", stmt.End()
+			if name == "Section" {
+				id, _ = strconv.Unquote(call.Args[0].(*ast.BasicLit).Value)
+			}
+		}
+	}
+	return demos, nil
+}
+
+// demoText tidies a slice of a course function for display: no
+// l.Resume() calls, one less tab of indentation, no blank lines at the ends.
+func demoText(src []byte) string {
+	var lines []string
+	for _, line := range strings.Split(string(src), "\n") {
+		if strings.TrimSpace(line) == "l.Resume()" {
+			continue
+		}
+		lines = append(lines, strings.TrimPrefix(line, "\t"))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// exerciseTask returns the task description of an exercise: the comment
+// above its package clause, minus the "Exercise name (course N)" title.
+// It is read from the reference solution, which is embedded and has the
+// same comment as the starter file - the learner's copy may be half
+// edited.
+func exerciseTask(name string) ([]string, error) {
+	src, err := referenceSolutions.ReadFile(path.Join("solutions", name, name+".go"))
+	if err != nil {
+		return nil, fmt.Errorf("exercise %s: %w", name, err)
+	}
+	var task []string
+	for i, line := range splitLines(stripBuildTag(string(src))) {
+		text, ok := strings.CutPrefix(line, "//")
+		if !ok {
+			break
+		}
+		if i > 0 {
+			task = append(task, strings.TrimPrefix(text, " "))
+		}
+	}
+	return task, nil
+}
+
+// writeSite renders the index, one page per course, the exercise list and
+// the stylesheet into dir.
+func writeSite(dir string, b *book) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	page := func(name, file string, data any) error {
+		t, err := template.ParseFS(exportTemplates, "templates/html/layout.html", "templates/html/"+name)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(filepath.Join(dir, file))
+		if err != nil {
+			return err
+		}
+		if err := t.ExecuteTemplate(f, "layout.html", data); err != nil {
+			f.Close()
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		return f.Close()
+	}
+
+	type pageData struct {
+		Book   *book
+		Course *bookCourse
+	}
+	if err := page("index.html", "index.html", pageData{Book: b}); err != nil {
+		return err
+	}
+	for _, c := range b.Courses {
+		if err := page("course.html", c.URL, pageData{b, c}); err != nil {
+			return err
+		}
+	}
+	if err := page("exercises.html", "exercises.html", pageData{Book: b}); err != nil {
+		return err
+	}
+	css, err := fs.ReadFile(exportTemplates, "templates/html/style.css")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "style.css"), css, 0o644)
+}
+
+var listLineRE = regexp.MustCompile(`^\s*(\d+\.|[-*•])\s+(.*)$`)
+
+// markdownHTML converts lesson Markdown to HTML. Lesson prose is written
+// line by line, so a paragraph keeps its line breaks; a paragraph made
+// only of list items becomes a list.
+func markdownHTML(lines []string) template.HTML {
+	var b strings.Builder
+	var block []string
+	flush := func() {
+		if len(block) > 0 {
+			writeBlockHTML(&b, block)
+			block = nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		rest, ok := strings.CutPrefix(lines[i], "```")
+		if !ok {
+			if strings.TrimSpace(lines[i]) == "" {
+				flush()
+			} else {
+				block = append(block, lines[i])
+			}
+			continue
+		}
+		flush()
+		var code []string
+		for i++; i < len(lines) && !strings.HasPrefix(lines[i], "```"); i++ {
+			code = append(code, lines[i])
+		}
+		writeCodeHTML(&b, strings.TrimSpace(rest), strings.Join(code, "\n"))
+	}
+	flush()
+	return template.HTML(b.String())
+}
+
+func writeBlockHTML(b *strings.Builder, lines []string) {
+	if m := mdHeadingRE.FindStringSubmatch(lines[0]); m != nil {
+		fmt.Fprintf(b, "<h4>%s</h4>\n", inlineHTML(m[1]))
+		if lines = lines[1:]; len(lines) == 0 {
+			return
+		}
+	}
+
+	ordered, items := true, make([]string, 0, len(lines))
+	for _, line := range lines {
+		m := listLineRE.FindStringSubmatch(line)
+		if m == nil {
+			items = nil
+			break
+		}
+		ordered = ordered && strings.HasSuffix(m[1], ".")
+		items = append(items, m[2])
+	}
+	if len(items) > 1 {
+		tag, start := "ul", ""
+		if ordered {
+			tag = "ol"
+			if n, _ := strconv.Atoi(strings.TrimSuffix(listLineRE.FindStringSubmatch(lines[0])[1], ".")); n > 1 {
+				start = fmt.Sprintf(" start=\"%d\"", n)
+			}
+		}
+		fmt.Fprintf(b, "<%s%s>\n", tag, start)
+		for _, item := range items {
+			fmt.Fprintf(b, "<li>%s</li>\n", inlineHTML(item))
+		}
+		fmt.Fprintf(b, "</%s>\n", tag)
+		return
+	}
+
+	b.WriteString(`<p class="lines">`)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(string(inlineHTML(line)))
+	}
+	b.WriteString("</p>\n")
+}
+
+func writeCodeHTML(b *strings.Builder, lang, src string) {
+	if lang == "" {
+		fmt.Fprintf(b, "<pre><code>%s</code></pre>\n", html.EscapeString(src))
+		return
+	}
+	fmt.Fprintf(b, "<pre><code class=\"language-%s\">", html.EscapeString(lang))
+	if lang == "go" {
+		for _, sp := range highlightGo(src) {
+			writeSpanHTML(b, sp)
+		}
+	} else {
+		b.WriteString(html.EscapeString(src))
+	}
+	b.WriteString("</code></pre>\n")
+}
+
+// inlineHTML converts one line of prose, with its **bold** and `code`.
+func inlineHTML(line string) template.HTML {
+	var b strings.Builder
+	for _, sp := range inlineSpans(line) {
+		switch sp.style {
+		case styleBold:
+			fmt.Fprintf(&b, "<strong>%s</strong>", html.EscapeString(sp.text))
+		case styleCode:
+			fmt.Fprintf(&b, "<code>%s</code>", html.EscapeString(sp.text))
+		default:
+			b.WriteString(html.EscapeString(sp.text))
+		}
+	}
+	return template.HTML(b.String())
+}
+
+func writeSpanHTML(b *strings.Builder, sp span) {
+	if sp.style == "" {
+		b.WriteString(html.EscapeString(sp.text))
+		return
+	}
+	fmt.Fprintf(b, "<span class=\"%s\">%s</span>", sp.style, html.EscapeString(sp.text))
+}
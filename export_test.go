@@ -0,0 +1,227 @@
+package learn
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Run with: go test -run Export
+
+func TestExportMarkdownHTML(t *testing.T) {
+	md := []string{
+		"Use **errors.Is** <not ==>",
+		"second line",
+		"",
+		"1. one",
+		"2. two",
+		"",
+		"```go",
+		"x := 1 // one",
+		"```",
+	}
+	got := string(markdownHTML(md))
+	for _, want := range []string{
+		"<p class=\"lines\">Use <strong>errors.Is</strong> &lt;not ==&gt;\nsecond line</p>",
+		"<ol>\n<li>one</li>\n<li>two</li>\n</ol>",
+		"x := <span class=\"number\">1</span> <span class=\"comment\">// one</span>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestExportSectionDemos(t *testing.T) {
+	src := `package x
+
+func CourseX(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 1)
+
+	l.Section("first")
+	x := 1
+	fmt.Println(x)
+	l.Resume()
+
+	l.Section("prose-only")
+
+	l.Section("last")
+	if x > 0 {
+		l.Resume()
+	}
+	l.End()
+}
+`
+	demos, err := sectionDemos("x.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := demos["first"], "x := 1\nfmt.Println(x)"; got != want {
+		t.Errorf("first = %q, want %q", got, want)
+	}
+	if got := demos["prose-only"]; got != "" {
+		t.Errorf("prose-only = %q, want no code", got)
+	}
+	// Blocks are kept whole, minus the l.Resume() calls
+	if got := demos["last"]; !strings.HasPrefix(got, "if x > 0 {") {
+		t.Errorf("last = %q", got)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	b, err := loadBook(".html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := writeSite(dir, b); err != nil {
+		t.Fatal(err)
+	}
+
+	index := readSiteFile(t, filepath.Join(dir, "index.html"))
+	for _, c := range b.Courses {
+		if !strings.Contains(index, `href="`+c.URL+`"`) {
+			t.Errorf("index does not link to %s", c.URL)
+		}
+		page := readSiteFile(t, filepath.Join(dir, c.URL))
+		if !strings.Contains(page, `id="takeaways"`) {
+			t.Errorf("%s has no key takeaways", c.URL)
+		}
+	}
+
+	basics := readSiteFile(t, filepath.Join(dir, "01-basics.html"))
+	for _, want := range []string{`<section id="variables">`, `href="exercises.html#reverse"`, `href="02-functions-and-errors.html"`} {
+		if !strings.Contains(basics, want) {
+			t.Errorf("01-basics.html missing %s", want)
+		}
+	}
+	exercises := readSiteFile(t, filepath.Join(dir, "exercises.html"))
+	for _, ex := range exerciseList {
+		if !strings.Contains(exercises, `<section id="`+ex.name+`">`) {
+			t.Errorf("exercises.html missing %s", ex.name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "style.css")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExportEPUB(t *testing.T) {
+	b, err := loadBook(".xhtml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := writeEPUB(&buf, b, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := z.File[0]; f.Name != "mimetype" || f.Method != zip.Store {
+		t.Errorf("first entry is %s (method %d), want an uncompressed mimetype", f.Name, f.Method)
+	}
+
+	files := make(map[string]string)
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		files[f.Name] = string(data)
+
+		// E-book readers reject anything that isn't well-formed XML
+		if ext := filepath.Ext(f.Name); ext == ".xhtml" || ext == ".opf" || ext == ".xml" {
+			d := xml.NewDecoder(bytes.NewReader(data))
+			for {
+				if _, err := d.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Errorf("%s: %v", f.Name, err)
+					break
+				}
+			}
+		}
+	}
+	opf := files["OEBPS/content.opf"]
+	for _, c := range b.Courses {
+		if _, ok := files["OEBPS/"+c.URL]; !ok || !strings.Contains(opf, `href="`+c.URL+`"`) {
+			t.Errorf("%s missing from the book or its manifest", c.URL)
+		}
+	}
+}
+
+func TestExportPDF(t *testing.T) {
+	b, err := loadBook(".html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := writePDF(&buf, b, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("not a PDF file")
+	}
+
+	// Every cross-reference entry must point at the start of its object
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	xref, _ := strconv.Atoi(string(m[1]))
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	count, _ := strconv.Atoi(strings.TrimPrefix(lines[1], "0 "))
+	for i := 1; i < count; i++ {
+		off, _ := strconv.Atoi(lines[2+i][:10])
+		if want := fmt.Sprintf("%d 0 obj\n", i); !bytes.HasPrefix(pdf[off:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q", i, pdf[off:off+10])
+		}
+	}
+
+	// Title, contents, at least a page per course, exercises
+	pages := bytes.Count(pdf, []byte("/Type /Page "))
+	if pages < len(b.Courses)+3 {
+		t.Errorf("%d pages for %d courses", pages, len(b.Courses))
+	}
+	if n := bytes.Count(pdf, []byte("/Parent 6 0 R")); n != len(b.Courses)+1 {
+		t.Errorf("%d bookmarks, want one per course plus exercises", n)
+	}
+}
+
+func TestExportPDFText(t *testing.T) {
+	if got := pdfString(`a (b) \ é → ✓ 日`); got != `(a \(b\) \\ \351 -> + ?)` {
+		t.Errorf("pdfString = %s", got)
+	}
+	lines := wrapText(fontRegular, 10, 60, 40, "one two three four five")
+	for i, line := range lines {
+		limit := 40.0
+		if i == 0 {
+			limit = 60
+		}
+		if w := textWidth(fontRegular, 10, line); w > limit {
+			t.Errorf("line %q is %.1fpt wide, over %.0f", line, w, limit)
+		}
+	}
+	if strings.Join(lines, " ") != "one two three four five" {
+		t.Errorf("wrapping lost words: %q", lines)
+	}
+}
+
+func readSiteFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
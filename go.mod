@@ -1,3 +1,67 @@
 module github.com/owolabijunior12/learning-golang
 
 go 1.25.1
+
+require (
+	github.com/blevesearch/bleve/v2 v2.6.1
+	github.com/lib/pq v1.12.3
+	github.com/mailru/easyjson v0.9.2
+	github.com/mattn/go-sqlite3 v1.14.52
+	github.com/owolabijunior12/learning-golang/pkg/middleware v0.0.0-00010101000000-000000000000
+	github.com/owolabijunior12/learning-golang/pkg/pipeline v0.0.0-00010101000000-000000000000
+	github.com/owolabijunior12/learning-golang/pkg/pool v0.0.0-00010101000000-000000000000
+	github.com/owolabijunior12/learning-golang/pkg/querybuilder v0.0.0-00010101000000-000000000000
+	github.com/owolabijunior12/learning-golang/pkg/redlock v0.0.0-00010101000000-000000000000
+	github.com/redis/go-redis/v9 v9.22.0
+	go.mongodb.org/mongo-driver/v2 v2.9.1
+	golang.org/x/sync v0.21.0
+	golang.org/x/sys v0.46.0
+	google.golang.org/protobuf v1.36.12
+)
+
+require (
+	github.com/RoaringBitmap/roaring/v2 v2.14.5 // indirect
+	github.com/bits-and-blooms/bitset v1.24.2 // indirect
+	github.com/blevesearch/bleve_index_api v1.4.1 // indirect
+	github.com/blevesearch/geo v0.2.6 // indirect
+	github.com/blevesearch/go-faiss v1.1.5 // indirect
+	github.com/blevesearch/go-porterstemmer v1.0.3 // indirect
+	github.com/blevesearch/gtreap v0.1.1 // indirect
+	github.com/blevesearch/mmap-go v1.2.0 // indirect
+	github.com/blevesearch/scorch_segment_api/v2 v2.4.10 // indirect
+	github.com/blevesearch/segment v0.9.1 // indirect
+	github.com/blevesearch/snowballstem v0.9.0 // indirect
+	github.com/blevesearch/upsidedown_store_api v1.0.2 // indirect
+	github.com/blevesearch/vellum v1.2.0 // indirect
+	github.com/blevesearch/zapx/v11 v11.4.3 // indirect
+	github.com/blevesearch/zapx/v12 v12.4.3 // indirect
+	github.com/blevesearch/zapx/v13 v13.4.3 // indirect
+	github.com/blevesearch/zapx/v14 v14.4.3 // indirect
+	github.com/blevesearch/zapx/v15 v15.4.3 // indirect
+	github.com/blevesearch/zapx/v16 v16.3.4 // indirect
+	github.com/blevesearch/zapx/v17 v17.2.3 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/golang/snappy v1.0.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/json-iterator/go v0.0.0-20171115153421-f7279a603ede // indirect
+	github.com/klauspost/compress v1.19.2 // indirect
+	github.com/mschoch/smat v0.2.0 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.2.0 // indirect
+	github.com/xdg-go/stringprep v1.0.4 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	go.etcd.io/bbolt v1.4.0 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/crypto v0.53.0 // indirect
+	golang.org/x/text v0.39.0 // indirect
+)
+
+replace github.com/owolabijunior12/learning-golang/pkg/middleware => ./pkg/middleware
+
+replace github.com/owolabijunior12/learning-golang/pkg/pipeline => ./pkg/pipeline
+
+replace github.com/owolabijunior12/learning-golang/pkg/pool => ./pkg/pool
+
+replace github.com/owolabijunior12/learning-golang/pkg/querybuilder => ./pkg/querybuilder
+
+replace github.com/owolabijunior12/learning-golang/pkg/redlock => ./pkg/redlock
@@ -0,0 +1,332 @@
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// exercise is one task in exercises/<name>, graded by the hidden tests in
+// testdata/grader/<name>_test.go.
+type exercise struct {
+	name   string
+	course int
+}
+
+// exerciseList is every exercise, in course order.
+var exerciseList = []exercise{
+	{"fizzbuzz", 1},
+	{"reverse", 1},
+	{"safedivide", 2},
+	{"shapes", 3},
+	{"parallelsum", 4},
+	{"wordcount", 20},
+}
+
+// The hidden tests are compiled into the binary and only written out next
+// to a copy of the learner's code, so the exercises folder never has them.
+//
+//go:embed testdata/grader/*_test.go
+var hiddenTests embed.FS
+
+// gradeResult is the outcome of grading one exercise.
+type gradeResult struct {
+	exercise exercise
+	passed   int
+	total    int
+	failed   []string // names of the hidden tests that failed
+	problem  string   // set when the tests could not run at all
+	detail   string   // compiler output behind a problem
+}
+
+func (r gradeResult) percent() int {
+	if r.total == 0 {
+		return 0
+	}
+	return r.passed * 100 / r.total
+}
+
+// runGrade implements "go run ./cmd/learn grade [flags] [exercise...]".
+func runGrade(args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ContinueOnError)
+	dir := fs.String("dir", "exercises", "folder holding your solutions")
+	progressPath := fs.String("progress", defaultProgressPath(), "progress file to update")
+	timeout := fs.Duration("timeout", time.Minute, "time limit per exercise")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: go run ./cmd/learn grade [flags] [exercise...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	selected, err := selectExercises(fs.Args())
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		return errors.New("grade needs the go command on your PATH")
+	}
+
+	var results []gradeResult
+	for _, ex := range selected {
+		fmt.Fprintf(os.Stderr, "grading %s...\n", ex.name)
+		results = append(results, gradeExercise(context.Background(), *dir, ex, *timeout))
+	}
+	printReportCard(os.Stdout, results)
+
+	now := time.Now()
+	_, err = updateProgress(*progressPath, func(p *progress) error {
+		for _, r := range results {
+			p.recordGrade(r, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nProgress saved to %s\n", *progressPath)
+	return nil
+}
+
+// selectExercises returns the named exercises, or all of them.
+func selectExercises(names []string) ([]exercise, error) {
+	if len(names) == 0 {
+		return exerciseList, nil
+	}
+	var selected []exercise
+	for _, name := range names {
+		ex, ok := findExercise(name)
+		if !ok {
+			return nil, fmt.Errorf("no exercise named %q (run \"go run ./cmd/learn grade -h\" or look in exercises/)", name)
+		}
+		selected = append(selected, ex)
+	}
+	return selected, nil
+}
+
+func findExercise(name string) (exercise, bool) {
+	for _, ex := range exerciseList {
+		if ex.name == name {
+			return ex, true
+		}
+	}
+	return exercise{}, false
+}
+
+// gradeExercise copies the learner's solution and the hidden tests into a
+// temporary module, compiles it and runs the tests.
+func gradeExercise(ctx context.Context, dir string, ex exercise, timeout time.Duration) gradeResult {
+	r := gradeResult{exercise: ex}
+	tests, err := hiddenTests.ReadFile("testdata/grader/" + ex.name + "_test.go")
+	if err != nil {
+		r.problem = "no hidden tests: " + err.Error()
+		return r
+	}
+	names := testNames(tests)
+	r.total = len(names)
+
+	work, err := os.MkdirTemp("", "grade-"+ex.name+"-")
+	if err != nil {
+		r.problem = err.Error()
+		return r
+	}
+	defer os.RemoveAll(work)
+
+	if err := copySolution(filepath.Join(dir, ex.name), work); err != nil {
+		r.problem = err.Error()
+		return r
+	}
+	// No dependencies, so the module needs nothing but a name
+	gomod := "module grade/" + ex.name + "\n\ngo 1.22\n"
+	if err := os.WriteFile(filepath.Join(work, "go.mod"), []byte(gomod), 0o644); err != nil {
+		r.problem = err.Error()
+		return r
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Compile on its own first: "your code doesn't build" is a more
+	// useful message than a failing test binary
+	if out, err := goCommand(ctx, work, "build", "."); err != nil {
+		r.problem, r.detail = "compile error", string(out)
+		return r
+	}
+	if err := os.WriteFile(filepath.Join(work, "hidden_test.go"), tests, 0o644); err != nil {
+		r.problem = err.Error()
+		return r
+	}
+
+	out, err := goCommand(ctx, work, "test", "-json", "-count=1", "-timeout="+timeout.String(), ".")
+	passed, buildOutput := parseTestEvents(out)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		r.problem = fmt.Sprintf("timed out after %s (an infinite loop or a deadlock?)", timeout)
+		return r
+	}
+	if err != nil && len(passed) == 0 && buildOutput != "" {
+		// The code builds but not against the tests: a changed signature
+		r.problem, r.detail = "tests don't compile against your code", buildOutput
+		return r
+	}
+	for _, name := range names {
+		if passed[name] {
+			r.passed++
+		} else {
+			r.failed = append(r.failed, name)
+		}
+	}
+	return r
+}
+
+// copySolution copies the non-test .go files of an exercise. The learner's
+// own tests are left behind so they can't clash with the hidden ones.
+func copySolution(from, to string) error {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return fmt.Errorf("solution not found: %w", err)
+	}
+	copied := 0
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(from, name))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(to, name), data, 0o644); err != nil {
+			return err
+		}
+		copied++
+	}
+	if copied == 0 {
+		return fmt.Errorf("solution not found: no .go files in %s", from)
+	}
+	return nil
+}
+
+// goCommand runs the go tool in dir, isolated from any go.work or GOFLAGS
+// the learner has set.
+func goCommand(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+	return cmd.CombinedOutput()
+}
+
+var testFuncRE = regexp.MustCompile(`(?m)^func (Test\w*)\(t \*testing\.T\)`)
+
+// testNames lists the top-level tests declared in a test file. The score
+// is out of these, so a panic that stops the run early still counts the
+// tests that never ran as failed.
+func testNames(src []byte) []string {
+	var names []string
+	for _, m := range testFuncRE.FindAllSubmatch(src, -1) {
+		names = append(names, string(m[1]))
+	}
+	return names
+}
+
+// testEvent is one line of "go test -json" output.
+type testEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// parseTestEvents returns which top-level tests passed, and any build
+// output (compile errors in the test binary).
+func parseTestEvents(out []byte) (map[string]bool, string) {
+	passed := map[string]bool{}
+	var build strings.Builder
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Bytes()
+		var e testEvent
+		if len(line) == 0 || line[0] != '{' || json.Unmarshal(line, &e) != nil {
+			// Older go versions print build errors as plain text
+			build.Write(line)
+			build.WriteByte('\n')
+			continue
+		}
+		switch {
+		case e.Action == "build-output":
+			build.WriteString(e.Output)
+		case e.Action == "pass" && e.Test != "" && !strings.Contains(e.Test, "/"):
+			passed[e.Test] = true
+		}
+	}
+	return passed, build.String()
+}
+
+// printReportCard prints scores grouped by course, then the overall
+// score, then the compiler output for any exercise that didn't build.
+func printReportCard(w io.Writer, results []gradeResult) {
+	fmt.Fprintln(w, "REPORT CARD")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var complete, percentSum int
+	for i := 0; i < len(results); {
+		// Results arrive in course order; take this course's run of them
+		number := results[i].exercise.course
+		j, topicSum := i, 0
+		for ; j < len(results) && results[j].exercise.course == number; j++ {
+			topicSum += results[j].percent()
+		}
+		name := fmt.Sprintf("COURSE %d", number)
+		if c, ok := findCourse(number); ok {
+			name = c.name
+		}
+		fmt.Fprintf(tw, "%2d. %s\t\t%3d%%\n", number, name, topicSum/(j-i))
+
+		for _, r := range results[i:j] {
+			percentSum += r.percent()
+			if r.percent() == 100 {
+				complete++
+			}
+			status := "pass"
+			switch {
+			case r.problem != "":
+				status = r.problem
+			case len(r.failed) > 0:
+				status = "failed: " + strings.Join(r.failed, ", ")
+			}
+			fmt.Fprintf(tw, "    %s\t%d/%d tests\t%3d%%\t%s\n", r.exercise.name, r.passed, r.total, r.percent(), status)
+		}
+		i = j
+	}
+	tw.Flush()
+	if len(results) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nOVERALL: %d/%d exercises complete, %d%%\n", complete, len(results), percentSum/len(results))
+
+	for _, r := range results {
+		if r.detail != "" {
+			fmt.Fprintf(w, "\n%s: %s\n%s\n", r.exercise.name, r.problem, indent(r.detail, 10))
+		}
+	}
+}
+
+// indent returns the first n lines of s, indented.
+func indent(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = append(lines[:n], "...")
+	}
+	return "    " + strings.Join(lines, "\n    ")
+}
@@ -0,0 +1,170 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Run with: go test -run 'Grade|Progress'
+
+func writeSolution(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name, name+".go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGradeExercise(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not on PATH")
+	}
+	dir := t.TempDir()
+	writeSolution(t, dir, "fizzbuzz", `package fizzbuzz
+
+import "strconv"
+
+func FizzBuzz(n int) []string {
+	var out []string
+	for i := 1; i <= n; i++ {
+		switch {
+		case i%15 == 0:
+			out = append(out, "FizzBuzz")
+		case i%3 == 0:
+			out = append(out, "Fizz")
+		case i%5 == 0:
+			out = append(out, "Buzz")
+		default:
+			out = append(out, strconv.Itoa(i))
+		}
+	}
+	return out
+}
+`)
+	// Byte-wise reversal: right for ASCII, wrong for "héllo"
+	writeSolution(t, dir, "reverse", `package reverse
+
+func Reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+`)
+	writeSolution(t, dir, "safedivide", "package safedivide\n\nfunc Divide(a, b int) (int, error) { return a / b }\n")
+
+	tests := []struct {
+		name    string
+		passed  int
+		failed  []string
+		problem string
+	}{
+		{"fizzbuzz", 4, nil, ""},
+		{"reverse", 2, []string{"TestReverseUnicode"}, ""},
+		{"safedivide", 0, nil, "compile error"},
+		{"shapes", 0, nil, "solution not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex, _ := findExercise(tt.name)
+			r := gradeExercise(context.Background(), dir, ex, time.Minute)
+			if r.passed != tt.passed || !slices.Equal(r.failed, tt.failed) || !strings.HasPrefix(r.problem, tt.problem) {
+				t.Errorf("got passed=%d failed=%v problem=%q; want %d %v %q",
+					r.passed, r.failed, r.problem, tt.passed, tt.failed, tt.problem)
+			}
+		})
+	}
+}
+
+func TestGradeExercisesHaveHiddenTests(t *testing.T) {
+	for _, ex := range exerciseList {
+		src, err := hiddenTests.ReadFile("testdata/grader/" + ex.name + "_test.go")
+		if err != nil {
+			t.Errorf("%s: %v", ex.name, err)
+			continue
+		}
+		if len(testNames(src)) == 0 {
+			t.Errorf("%s: hidden test file declares no tests", ex.name)
+		}
+		if _, ok := findCourse(ex.course); !ok {
+			t.Errorf("%s: course %d does not exist", ex.name, ex.course)
+		}
+		if _, err := os.Stat(filepath.Join("exercises", ex.name)); err != nil {
+			t.Errorf("%s: no starter code: %v", ex.name, err)
+		}
+	}
+}
+
+func TestGradeParseTestEvents(t *testing.T) {
+	out := `{"Action":"run","Test":"TestA"}
+{"Action":"pass","Test":"TestA"}
+{"Action":"run","Test":"TestB"}
+{"Action":"pass","Test":"TestB/sub"}
+{"Action":"fail","Test":"TestB"}
+{"Action":"build-output","Output":"./x.go:3:1: undefined: Foo\n"}
+# plain text from an older go
+`
+	passed, build := parseTestEvents([]byte(out))
+	if !passed["TestA"] || passed["TestB"] || passed["TestB/sub"] {
+		t.Errorf("passed = %v, want only TestA", passed)
+	}
+	if !strings.Contains(build, "undefined: Foo") || !strings.Contains(build, "plain text") {
+		t.Errorf("build output = %q", build)
+	}
+}
+
+func TestGradeReportCard(t *testing.T) {
+	results := []gradeResult{
+		{exercise: exercise{"fizzbuzz", 1}, passed: 4, total: 4},
+		{exercise: exercise{"reverse", 1}, passed: 1, total: 2, failed: []string{"TestReverseUnicode"}},
+		{exercise: exercise{"shapes", 3}, total: 4, problem: "compile error", detail: "./shapes.go:9: syntax error"},
+	}
+	var buf bytes.Buffer
+	printReportCard(&buf, results)
+	out := buf.String()
+	for _, want := range []string{
+		"1. BASICS", "75%", // (100 + 50) / 2
+		"failed: TestReverseUnicode",
+		"3. STRUCTS & INTERFACES",
+		"OVERALL: 1/3 exercises complete, 50%",
+		"./shapes.go:9: syntax error",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report card missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestProgressRecordGrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "progress.json")
+	p, err := loadProgress(path)
+	if err != nil {
+		t.Fatalf("missing file should load as empty progress: %v", err)
+	}
+
+	ex := exercise{"reverse", 1}
+	p.recordGrade(gradeResult{exercise: ex, passed: 3, total: 3}, time.Now())
+	p.recordGrade(gradeResult{exercise: ex, passed: 1, total: 3}, time.Now())
+	if err := p.save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err = loadProgress(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := p.Exercises["reverse"]
+	if got.Score != 33 || got.Best != 100 || got.Attempts != 2 || got.Course != 1 {
+		t.Errorf("progress = %+v, want score 33, best 100, 2 attempts, course 1", got)
+	}
+}
@@ -0,0 +1,146 @@
+package learn
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Translations of the lessons live in lessons/<lang>/, named like the
+// English files: lessons/fr/01-basics.md translates lessons/01-basics.md.
+// A translation may cover only part of a lesson. Its sections replace the
+// English ones with the same {#id}, and anything it leaves out, or leaves
+// empty, is shown in English. The course code doesn't change, so a
+// translated section must have as many output points as the English one.
+
+// lessonLang is the language lessons are shown in. Like pace, it is only
+// changed for runs from the command line (--lang).
+var lessonLang = "en"
+
+// lessonLanguages lists the languages there are lessons in, English first.
+func lessonLanguages() []string {
+	langs := []string{"en"}
+	entries, _ := lessonFiles.ReadDir("lessons")
+	for _, e := range entries {
+		if e.IsDir() {
+			langs = append(langs, e.Name())
+		}
+	}
+	return langs
+}
+
+// lessonLanguage picks the language for a run: the --lang flag if given,
+// else the locale in the environment. Asking for a language there are no
+// lessons in is an error; a locale without lessons just means English.
+func lessonLanguage(flagValue string) (string, error) {
+	langs := lessonLanguages()
+	if flagValue != "" {
+		lang := strings.ToLower(flagValue)
+		if !slices.Contains(langs, lang) {
+			return "", fmt.Errorf("no lessons in %q (available: %s)", flagValue, strings.Join(langs, ", "))
+		}
+		return lang, nil
+	}
+	if lang := localeLanguage(os.Getenv); slices.Contains(langs, lang) {
+		return lang, nil
+	}
+	return "en", nil
+}
+
+// localeLanguage reads the language of the POSIX locale: LC_ALL, then
+// LC_MESSAGES, then LANG. "fr_FR.UTF-8" is "fr"; "C" and "POSIX" are
+// English.
+func localeLanguage(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := getenv(name)
+		if locale == "" {
+			continue
+		}
+		if locale == "C" || locale == "POSIX" || strings.HasPrefix(locale, "C.") {
+			return "en"
+		}
+		lang, _, _ := strings.Cut(locale, "_")
+		lang, _, _ = strings.Cut(lang, ".")
+		return strings.ToLower(lang)
+	}
+	return "en"
+}
+
+// errNoLesson means a lesson file doesn't exist, as for a course that
+// hasn't been translated.
+var errNoLesson = errors.New("no lesson file")
+
+// readLesson finds and parses dir/NN-*.md.
+func readLesson(dir string, number int) (*lesson, error) {
+	matches, err := fs.Glob(lessonFiles, fmt.Sprintf("%s/%02d-*.md", dir, number))
+	if err != nil {
+		return nil, err
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("course %d: %w in %s", number, errNoLesson, dir)
+	case 1:
+	default:
+		return nil, fmt.Errorf("want one lesson file for course %d in %s, found %d", number, dir, len(matches))
+	}
+	src, err := lessonFiles.ReadFile(matches[0])
+	if err != nil {
+		return nil, err
+	}
+	text, err := expandCode(number, string(src))
+	if err != nil {
+		return nil, err
+	}
+	return parseLesson(number, text)
+}
+
+// translateLesson lays a translation over the English lesson, section by
+// section.
+func translateLesson(en, tr *lesson, lang string) (*lesson, error) {
+	l := *en
+	l.Title = tr.Title
+	l.Sections = slices.Clone(en.Sections)
+	for _, ts := range tr.Sections {
+		i := slices.IndexFunc(l.Sections, func(s lessonSection) bool { return s.ID == ts.ID })
+		if i < 0 {
+			return nil, fmt.Errorf("lesson %d (%s): section %q isn't in the English lesson", en.Number, lang, ts.ID)
+		}
+		if blankParts(ts.Parts) {
+			continue
+		}
+		if len(ts.Parts) != len(l.Sections[i].Parts) {
+			return nil, fmt.Errorf("lesson %d (%s): section %q has %d output points, the English one %d",
+				en.Number, lang, ts.ID, len(ts.Parts)-1, len(l.Sections[i].Parts)-1)
+		}
+		if ts.ID == "" {
+			ts.Title = l.Sections[i].Title
+		}
+		l.Sections[i] = ts
+	}
+	if len(tr.Takeaways) > 0 {
+		l.Takeaways = tr.Takeaways
+	}
+	l.Cheatsheet = slices.Clone(en.Cheatsheet)
+	for _, tt := range tr.Cheatsheet {
+		i := slices.IndexFunc(l.Cheatsheet, func(t cheatTopic) bool { return t.Name == tt.Name })
+		if i < 0 {
+			return nil, fmt.Errorf("lesson %d (%s): cheatsheet topic %q isn't in the English lesson", en.Number, lang, tt.Name)
+		}
+		l.Cheatsheet[i] = tt
+	}
+	return &l, nil
+}
+
+// blankParts reports whether a section has no text, only output points at
+// most: a translation that leaves it to English.
+func blankParts(parts [][]string) bool {
+	for _, p := range parts {
+		if strings.TrimSpace(strings.Join(p, "")) != "" {
+			return false
+		}
+	}
+	return true
+}
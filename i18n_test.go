@@ -0,0 +1,106 @@
+package learn
+
+import (
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTranslateLesson(t *testing.T) {
+	en, err := parseLesson(1, sampleLesson)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The intro and the second section are left to English
+	tr, err := parseLesson(1, "# EXEMPLE\n\n"+
+		"## 1. PREMIER {#first}\n\nAvant.\n\n<!-- output -->\n\nAprès.\n\n"+
+		"## 2. DEUXIÈME {#second}\n\n"+
+		"## Points clés {#takeaways}\n\n1. Un\n2. Deux\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := translateLesson(en, tr, "fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Title != "EXEMPLE" || !slices.Equal(l.Takeaways, []string{"Un", "Deux"}) {
+		t.Errorf("title %q, takeaways %q", l.Title, l.Takeaways)
+	}
+	if got := strings.Join(l.Sections[0].Parts[0], " "); !strings.Contains(got, "Intro.") {
+		t.Errorf("intro = %q, want the English one", got)
+	}
+	if s := l.Sections[1]; s.Title != "1. PREMIER" || !slices.Contains(s.Parts[1], "Après.") {
+		t.Errorf("first section = %+v, want the translation", s)
+	}
+	if s := l.Sections[2]; s.Title != "2. SECOND" {
+		t.Errorf("second section = %+v, want the English one", s)
+	}
+	if en.Sections[1].Title != "1. FIRST" {
+		t.Error("translating changed the English lesson")
+	}
+
+	for _, bad := range []string{
+		"# T\n\n## 1. PREMIER {#first}\n\nAvant, sans sortie.\n",
+		"# T\n\n## 9. NEUF {#ninth}\n\nTexte.\n",
+	} {
+		tr, err := parseLesson(1, bad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := translateLesson(en, tr, "fr"); err == nil {
+			t.Errorf("translation %q should be refused", bad)
+		}
+	}
+}
+
+// TestTranslations checks that every translation fits its English lesson.
+func TestTranslations(t *testing.T) {
+	for _, lang := range lessonLanguages()[1:] {
+		files, err := fs.Glob(lessonFiles, "lessons/"+lang+"/*.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range files {
+			c := slices.IndexFunc(courses, func(c course) bool {
+				return strings.TrimSuffix(c.file, ".go") == strings.TrimSuffix(path.Base(file), ".md")
+			})
+			if c < 0 {
+				t.Errorf("%s: no course of that name", file)
+				continue
+			}
+			lessonLang = lang
+			_, err := loadLesson(courses[c].number)
+			lessonLang = "en"
+			if err != nil {
+				t.Errorf("%s: %v", file, err)
+			}
+		}
+	}
+}
+
+func TestLocaleLanguage(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{nil, "en"},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, "fr"},
+		{map[string]string{"LANG": "pt_BR"}, "pt"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_ALL": "C"}, "en"},
+		{map[string]string{"LANG": "en_US.UTF-8", "LC_MESSAGES": "es_ES.UTF-8"}, "es"},
+		{map[string]string{"LANG": "C.UTF-8"}, "en"},
+	}
+	for _, tt := range tests {
+		if got := localeLanguage(func(k string) string { return tt.env[k] }); got != tt.want {
+			t.Errorf("localeLanguage(%v) = %q, want %q", tt.env, got, tt.want)
+		}
+	}
+	if _, err := lessonLanguage("xx"); err == nil {
+		t.Error("lessonLanguage accepted a language with no lessons")
+	}
+	if lang, err := lessonLanguage("EN"); err != nil || lang != "en" {
+		t.Errorf("lessonLanguage(EN) = %q, %v", lang, err)
+	}
+}
@@ -0,0 +1,348 @@
+package advanced
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 13: ADVANCED TOPICS
+// Topics covered:
+// 1. Context and cancellation
+// 2. Performance optimization
+// 3. Memory management
+// 4. Reflection
+// 5. Type assertions and type switches
+// 6. Unsafe package (use with caution!)
+// 7. Build tags
+// 8. Profiling
+// 9. Serialization costs: encoding/json, easyjson and protobuf, benchmarked
+//    in the serialization package
+
+// The lesson shows the code of the functions and types below (see
+// "<!-- code: name -->" in lessons/13-advanced-topics.md), so it compiles
+// with the course. They are never run.
+
+// User is what the performance code makes a slice of.
+type User struct {
+	Name string
+	Age  int
+}
+
+// ============ 1. CONTEXT AND CANCELLATION ============
+
+// doWork stands for any work that gives up once ctx is done.
+func doWork(ctx context.Context) error {
+	select {
+	case <-time.After(time.Second):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func contextCancellation(ch <-chan int) error {
+	// Context propagates cancellation and timeouts through goroutines
+
+	// Timeout context
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Use in function
+	if err := doWork(ctx); err != nil {
+		fmt.Println("Work cancelled or timed out:", err)
+	}
+
+	// Cancellable context
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// Can cancel with cancel()
+	}()
+
+	// Value context, with a key type of its own so that no other
+	// package's values collide with it
+	type userIDKey struct{}
+	ctx = context.WithValue(ctx, userIDKey{}, 123)
+	userID := ctx.Value(userIDKey{}).(int)
+	fmt.Println("user", userID)
+
+	// Always check context before blocking operations
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case result := <-ch:
+		// Process result
+		fmt.Println(result)
+	}
+	return nil
+}
+
+// ============ 2. PERFORMANCE OPTIMIZATION ============
+
+func performance(dst io.Writer, src io.Reader) error {
+	// 1. Pre-allocate slices if size is known
+	users := make([]User, 0, 1000) // Capacity 1000
+	for i := 0; i < 1000; i++ {
+		users = append(users, User{Name: fmt.Sprint("user", i)})
+	}
+
+	// 2. Use strings.Builder for concatenation
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "item%d ", i)
+	}
+	result := sb.String() // Much faster than +
+	fmt.Println(len(users), len(result))
+
+	// 3. Avoid unnecessary allocations
+	// Bad:  return []int{1, 2, 3}[0]
+	// Good: x := 1; return x
+
+	// 4. Use sync.Pool for object reuse
+	pool := &sync.Pool{
+		New: func() any {
+			return new(bytes.Buffer)
+		},
+	}
+	buf := pool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer pool.Put(buf)
+
+	// 5. Use io.Copy for large data
+	_, err := io.Copy(dst, src) // Streams it through a small buffer
+	return err
+}
+
+// ============ 3. MEMORY MANAGEMENT ============
+
+func memoryManagement() error {
+	// 1. Avoid pointer chains
+	// Bad:  *****User
+	// Good: User or *User
+
+	// 2. Close resources explicitly
+	file, err := os.Open("file.txt")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// 3. Clear slices/maps when done
+	cache := map[string]string{}
+	// ... use cache ...
+	cache = nil // Allow GC to collect
+	fmt.Println(len(cache))
+
+	// 4. Don't hold references longer than needed
+	process := func(data []byte) {
+		// Use data
+		// data reference gone at function end
+	}
+	process(make([]byte, 1<<20))
+
+	// 5. Use value types for small structs
+	type Point struct {
+		X, Y int // 16 bytes - pass by value
+	}
+
+	// 6. Use pointers for large structs
+	type Config struct {
+		// Many fields...
+	} // Pass *Config, not Config
+
+	return nil
+}
+
+// ============ 4. REFLECTION ============
+
+func reflection(someFunc, arg1, arg2 any) {
+	// Get type information at runtime
+	var x any = "hello"
+	t := reflect.TypeOf(x)  // Type
+	v := reflect.ValueOf(x) // Value
+
+	fmt.Println(t.Name())   // string
+	fmt.Println(v.String()) // hello
+
+	// Inspect struct fields
+	type User struct {
+		Name string
+		Age  int
+	}
+
+	u := User{"Alice", 30}
+	typ := reflect.TypeOf(u)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		value := reflect.ValueOf(u).Field(i)
+		fmt.Printf("%s: %v\n", field.Name, value)
+	}
+
+	// Dynamic function calls
+	fn := reflect.ValueOf(someFunc)
+	results := fn.Call([]reflect.Value{
+		reflect.ValueOf(arg1),
+		reflect.ValueOf(arg2),
+	})
+	fmt.Println(results)
+
+	// Caution:
+	// - Reflection is slower than direct code
+	// - Can be hard to understand
+	// - Use sparingly - only when necessary
+}
+
+// ============ 5. TYPE ASSERTIONS AND SWITCHES ============
+
+func typeAssertions(jsonStr string) {
+	// Type assertion
+	var i any = "hello"
+
+	// Check and convert
+	s := i.(string) // Panics if wrong type
+
+	// Safe check
+	s, ok := i.(string)
+	if ok {
+		fmt.Println("String:", s)
+	}
+
+	// Type switch
+	switch v := i.(type) {
+	case string:
+		fmt.Printf("String: %v\n", v)
+	case int:
+		fmt.Printf("Integer: %v\n", v)
+	case float64:
+		fmt.Printf("Float: %v\n", v)
+	default:
+		fmt.Printf("Unknown: %T\n", v)
+	}
+
+	// Common pattern: JSON to struct
+	var data map[string]any
+	json.Unmarshal([]byte(jsonStr), &data)
+
+	if name, ok := data["name"].(string); ok {
+		fmt.Println(name) // Use name
+	}
+	if age, ok := data["age"].(float64); ok {
+		fmt.Println(age) // Use age (JSON numbers are float64)
+	}
+}
+
+// ============ 8. PROFILING ============
+
+func profiling() error {
+	// CPU profiling
+	f, err := os.Create("cpu.prof")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := pprof.StartCPUProfile(f); err != nil {
+		return err
+	}
+	defer pprof.StopCPUProfile()
+
+	// ... run code to profile ...
+
+	// Memory profiling
+	mem, err := os.Create("mem.prof")
+	if err != nil {
+		return err
+	}
+	defer mem.Close()
+	if err := pprof.WriteHeapProfile(mem); err != nil {
+		return err
+	}
+
+	// HTTP pprof: with import _ "net/http/pprof", the default mux
+	// serves the profiles at http://localhost:6060/debug/pprof
+	go func() {
+		log.Println(http.ListenAndServe("localhost:6060", nil))
+	}()
+	return nil
+}
+
+// ============ CACHING STRATEGIES ============
+
+// Cache is a simple in-memory cache.
+type Cache struct {
+	sync.RWMutex
+	data map[string]any
+}
+
+func (c *Cache) Get(key string) (any, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	val, ok := c.data[key]
+	return val, ok
+}
+
+// CacheEntry is a value with an expiry time, for a TTL cache.
+type CacheEntry struct {
+	Value     any
+	ExpiresAt time.Time
+}
+
+// ============ GOROUTINE MANAGEMENT ============
+
+func goroutineManagement(work func(ctx context.Context)) {
+	// 1. Avoid goroutine leaks
+	// Bad:
+	//	for {
+	//		go work() // Infinite goroutines!
+	//	}
+	//
+	// Good: every goroutine can be told to stop
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go work(ctx) // returns once ctx is done
+
+	// 2. Limit concurrent goroutines
+	semaphore := make(chan struct{}, 10) // Max 10
+
+	for i := 0; i < 100; i++ {
+		go func() {
+			semaphore <- struct{}{}        // Acquire
+			defer func() { <-semaphore }() // Release
+
+			work(ctx) // Actual work
+		}()
+	}
+
+	// 3. Monitor goroutines
+	fmt.Println(runtime.NumGoroutine())
+
+	// 4. Use WaitGroup for cleanup
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func CourseThirteen(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 13)
+	return nil
+}
@@ -0,0 +1,72 @@
+// Package serialization is the benchmark suite of course 13's
+// "Serialization costs": the User of course 6 and the Product of course 8,
+// encoded with encoding/json, with the marshalers easyjson generates, and
+// as protocol buffers with proto.Marshal. The generated code is committed,
+// so the benchmarks need neither generator; to change the models, install
+// both and run go generate:
+//
+//	go install github.com/mailru/easyjson/easyjson@v0.9.2
+//	go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.12
+//	go generate ./internal/courses/advanced/serialization
+//
+// protoc itself comes from https://github.com/protocolbuffers/protobuf/releases.
+package serialization
+
+//go:generate easyjson -no_std_marshalers models.go
+//go:generate sh -c "cd pb && protoc --go_out=paths=source_relative:. models.proto"
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/advanced/serialization/pb"
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases"
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+)
+
+// easyjson generates methods, and only for types of the package it runs
+// in, so these are web.User and databases.Product under names of this
+// package: the same fields and tags, and converting is free. With
+// -no_std_marshalers it leaves out MarshalJSON, which would have
+// encoding/json call the generated code and measure it twice.
+
+// User is a web.User, with the easyjson marshaler.
+//
+//easyjson:json
+type User web.User
+
+// Product is a databases.Product, with the easyjson marshaler.
+//
+//easyjson:json
+type Product databases.Product
+
+// Users is a page of users, as GET /users returns.
+//
+//easyjson:json
+type Users []User
+
+// UserProto converts u to its protocol buffer message.
+func UserProto(u web.User) *pb.User {
+	return &pb.User{
+		Id:      int64(u.ID),
+		Name:    u.Name,
+		Email:   u.Email,
+		Age:     int32(u.Age),
+		Version: int64(u.Version),
+	}
+}
+
+// ProductProto converts p to its protocol buffer message.
+func ProductProto(p databases.Product) *pb.Product {
+	msg := &pb.Product{
+		Id:       p.ID,
+		Name:     p.Name,
+		Price:    p.Price,
+		Category: p.Category,
+		InStock:  p.InStock,
+		Tags:     p.Tags,
+	}
+	if !p.CreatedAt.IsZero() {
+		msg.CreatedAt = timestamppb.New(p.CreatedAt)
+	}
+	return msg
+}
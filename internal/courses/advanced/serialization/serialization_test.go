@@ -0,0 +1,182 @@
+package serialization
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mailru/easyjson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/advanced/serialization/pb"
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases"
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+)
+
+// Benchmarks: go test -run '^$' -bench Encode -benchmem ./internal/courses/advanced/serialization
+
+// Models the benchmarks encode: the user and product the API sends, and a
+// page of users like GET /users returns.
+var (
+	benchUser    = web.User{ID: 42, Name: "Alice Johnson", Email: "alice.johnson@example.com", Age: 30, Version: 7}
+	benchProduct = databases.Product{
+		ID: "65f1c0ffee42", Name: "Mechanical Keyboard", Price: 129.99, Category: "electronics",
+		InStock: true, Tags: []string{"keyboard", "mechanical", "usb-c"},
+		CreatedAt: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+	}
+	benchUsers = func() []web.User {
+		users := make([]web.User, 100)
+		for i := range users {
+			users[i] = benchUser
+			users[i].ID = i + 1
+			users[i].Name = fmt.Sprintf("User %d", i+1)
+		}
+		return users
+	}()
+)
+
+// easyjson must write what encoding/json does, or the benchmarks would
+// compare different work.
+func TestEasyJSONMatchesEncodingJSON(t *testing.T) {
+	users := []web.User{
+		benchUser,
+		{},
+		{ID: -1, Name: `"quoted" \ back`, Email: "tab\tnew\nline", Age: 150},
+		{Name: "<b>Tom & Jerry</b>", Email: "émile@exämple.com"},
+	}
+	for _, u := range users {
+		want, err := json.Marshal(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := easyjson.Marshal(User(u)); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("easyjson.Marshal(%+v) = %s, %v\nwant %s", u, got, err, want)
+		}
+	}
+
+	products := []databases.Product{
+		benchProduct,
+		{},
+		{Price: 0.1, Tags: []string{}},
+		{Price: 1234.5, CreatedAt: time.Date(2024, 3, 1, 9, 30, 0, 123456789, time.FixedZone("", 3600))},
+	}
+	for _, p := range products {
+		want, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := easyjson.Marshal(Product(p)); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("easyjson.Marshal(%+v) = %s, %v\nwant %s", p, got, err, want)
+		}
+	}
+
+	// The one difference: easyjson formats floats with %g, so a round
+	// million is 1e+06, where encoding/json writes digits up to 1e21. Both
+	// decode to the same float.
+	got, _ := easyjson.Marshal(Product{Price: 1e6})
+	want, _ := json.Marshal(databases.Product{Price: 1e6})
+	if !bytes.Contains(got, []byte(`"Price":1e+06`)) || !bytes.Contains(want, []byte(`"Price":1000000`)) {
+		t.Errorf("1e6 = %s from easyjson, %s from encoding/json", got, want)
+	}
+}
+
+func TestProtoRoundTrip(t *testing.T) {
+	data, err := proto.Marshal(UserProto(benchUser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u pb.User
+	if err := proto.Unmarshal(data, &u); err != nil {
+		t.Fatal(err)
+	}
+	got := web.User{ID: int(u.Id), Name: u.Name, Email: u.Email, Age: int(u.Age), Version: int(u.Version)}
+	if got != benchUser {
+		t.Errorf("user round trip = %+v, want %+v", got, benchUser)
+	}
+
+	data, err = proto.Marshal(ProductProto(benchProduct))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p pb.Product
+	if err := proto.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Id != benchProduct.ID || p.Name != benchProduct.Name || p.Price != benchProduct.Price ||
+		p.Category != benchProduct.Category || p.InStock != benchProduct.InStock ||
+		!slices.Equal(p.Tags, benchProduct.Tags) || !p.CreatedAt.AsTime().Equal(benchProduct.CreatedAt) {
+		t.Errorf("product round trip = %v, want %+v", &p, benchProduct)
+	}
+
+	// Zero values aren't sent at all
+	if data, err := proto.Marshal(ProductProto(databases.Product{})); err != nil || len(data) != 0 {
+		t.Errorf("empty product = %x, %v; want no bytes", data, err)
+	}
+}
+
+// benchEncode runs encode, which returns the encoded bytes, and reports the
+// message size and the messages per second alongside -benchmem's counts.
+func benchEncode(b *testing.B, encode func() ([]byte, error)) {
+	var buf []byte
+	var err error
+	for b.Loop() {
+		if buf, err = encode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(buf)), "B/msg")
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "msgs/s")
+}
+
+// Each library through the call an API handler makes, from the repo's own
+// type: protobuf's time includes converting it to the generated message.
+func BenchmarkEncodeUser(b *testing.B) {
+	b.Run("encoding-json", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) { return json.Marshal(benchUser) })
+	})
+	b.Run("easyjson", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) { return easyjson.Marshal(User(benchUser)) })
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) { return proto.Marshal(UserProto(benchUser)) })
+	})
+}
+
+func BenchmarkEncodeProduct(b *testing.B) {
+	b.Run("encoding-json", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) { return json.Marshal(benchProduct) })
+	})
+	b.Run("easyjson", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) { return easyjson.Marshal(Product(benchProduct)) })
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) { return proto.Marshal(ProductProto(benchProduct)) })
+	})
+}
+
+func BenchmarkEncodeUserList(b *testing.B) {
+	b.Run("encoding-json", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) { return json.Marshal(benchUsers) })
+	})
+	b.Run("easyjson", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) {
+			users := make(Users, len(benchUsers))
+			for i, u := range benchUsers {
+				users[i] = User(u)
+			}
+			return easyjson.Marshal(users)
+		})
+	})
+	b.Run("protobuf", func(b *testing.B) {
+		benchEncode(b, func() ([]byte, error) {
+			list := &pb.UserList{Users: make([]*pb.User, len(benchUsers))}
+			for i, u := range benchUsers {
+				list.Users[i] = UserProto(u)
+			}
+			return proto.Marshal(list)
+		})
+	})
+}
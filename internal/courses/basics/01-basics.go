@@ -0,0 +1,250 @@
+package basics
+
+import (
+	"context"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// This is the first course file - Learn Go Basics
+// Topics covered:
+// 1. Package declaration and imports
+// 2. Variables and constants
+// 3. Data types (int, string, float, bool, arrays, slices, maps)
+// 4. Type conversion
+// 5. Control flow (if/else, loops)
+// 6. Operators
+
+// Demonstrating constants
+const (
+	// Untyped constants - Go determines type when used
+	Pi           = 3.14159
+	Name         = "Go Programming"
+	BirthdayYear = 2009 // Go was created in 2009
+)
+
+// Variables with explicit types
+var (
+	// Global variables can be declared this way
+	globalCounter int    = 0
+	globalName    string = "Global Variable"
+	isProduction  bool   = true
+)
+
+func CourseOne(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 1)
+
+	l.Section("variables")
+
+	// Method 1: Declare with var keyword
+	var age int
+	age = 25
+	l.Printf("age (with var): %v (type: %T)\n", age, age)
+
+	// Method 2: Short declaration (only inside functions)
+	name := "Alice"
+	l.Printf("name (with :=): %v (type: %T)\n", name, name)
+
+	// Method 3: Multiple variables
+	var x, y, z int = 1, 2, 3
+	l.Printf("x=%v, y=%v, z=%v\n", x, y, z)
+
+	// Method 4: Blank identifier (discard value)
+	_, count := divideWithRemainder(10, 3)
+	l.Printf("Remainder of 10/3: %v\n", count)
+
+	l.Section("data-types")
+
+	// Integers - multiple sizes
+	var int8Var int8 = 127 // Range: -128 to 127
+	var int64Var int64 = 9223372036854775807
+	var uint32Var uint32 = 4294967295 // Unsigned, range: 0 to 4294967295
+	l.Printf("int8: %v, int64: %v, uint32: %v\n", int8Var, int64Var, uint32Var)
+
+	// Floating point
+	var floatNum float32 = 3.14
+	var doubleNum float64 = 3.14159265359
+	l.Printf("float32: %v, float64: %v\n", floatNum, doubleNum)
+
+	// Strings
+	simpleString := "Hello, Go!"
+	multilineString := `This is a
+raw string that
+preserves formatting`
+	l.Printf("Simple: %v\nMultiline:\n%v\n\n", simpleString, multilineString)
+
+	// Boolean
+	isProgrammer := true
+	l.Printf("Is Programmer: %v\n", isProgrammer)
+
+	l.Section("arrays-slices")
+
+	// Arrays - fixed size
+	var fruits [3]string = [3]string{"Apple", "Banana", "Orange"}
+	l.Printf("Array: %v, length: %v\n", fruits, len(fruits))
+
+	// Array shorthand
+	numbers := [5]int{1, 2, 3, 4, 5}
+	l.Printf("Numbers array: %v\n", numbers)
+
+	// Slices - dynamic size (MORE commonly used than arrays)
+	var colors []string = []string{"Red", "Green", "Blue"}
+	l.Printf("Slice: %v, length: %v, capacity: %v\n", colors, len(colors), cap(colors))
+
+	// Slice operations
+	colors = append(colors, "Yellow") // Add element
+	l.Printf("After append: %v\n", colors)
+
+	subSlice := colors[1:3] // Get elements from index 1 to 3 (exclusive)
+	l.Printf("Subslice [1:3]: %v\n", subSlice)
+
+	// Create slice with make (specify length and capacity)
+	emptySlice := make([]int, 5, 10) // length=5, capacity=10
+	l.Printf("Empty slice: %v, len=%v, cap=%v\n", emptySlice, len(emptySlice), cap(emptySlice))
+
+	l.Section("maps")
+
+	// Declare and initialize map
+	capitals := map[string]string{
+		"USA":    "Washington",
+		"France": "Paris",
+		"Japan":  "Tokyo",
+	}
+	l.Printf("Capitals: %v\n", capitals)
+
+	// Add to map
+	capitals["Brazil"] = "Brasília"
+	l.Printf("After adding Brazil: %v\n", capitals)
+
+	// Access value
+	l.Printf("Capital of France: %v\n", capitals["France"])
+
+	// Check if key exists
+	value, exists := capitals["Italy"]
+	l.Printf("Italy capital: %v, exists: %v\n", value, exists)
+
+	// Delete from map
+	delete(capitals, "USA")
+	l.Printf("After deleting USA: %v\n", capitals)
+
+	l.Section("type-conversion")
+
+	intValue := 42
+	floatValue := float64(intValue)
+	l.Printf("Int to Float: %v (type: %T)\n", floatValue, floatValue)
+
+	stringValue := "Hello"
+	byteSlice := []byte(stringValue)
+	l.Printf("String to bytes: %v\n", byteSlice)
+
+	back := string([]byte{72, 101, 108, 108, 111})
+	l.Printf("Bytes to string: %v\n", back)
+
+	l.Section("control-flow-if")
+
+	temperature := 25
+
+	if temperature < 0 {
+		l.Println("Freezing!")
+	} else if temperature < 15 {
+		l.Println("Cold")
+	} else if temperature < 25 {
+		l.Println("Warm")
+	} else {
+		l.Println("Hot!")
+	}
+
+	// If with initialization (variable scope limited to if block)
+	if score := 85; score >= 90 {
+		l.Println("Grade: A")
+	} else if score >= 80 {
+		l.Println("Grade: B")
+	} else {
+		l.Println("Grade: C or lower")
+	}
+	// l.Println(score) // ERROR: score not defined here
+
+	l.Section("loops")
+
+	// For loop - traditional style
+	l.Print("Traditional for loop (0-4): ")
+	for i := 0; i < 5; i++ {
+		l.Printf("%v ", i)
+	}
+	l.Println()
+
+	// For loop - while style
+	counter := 0
+	l.Print("While-style loop: ")
+	for counter < 5 {
+		l.Printf("%v ", counter)
+		counter++
+	}
+	l.Println()
+
+	// For loop - infinite (with break)
+	l.Print("Infinite loop with break: ")
+	loopCount := 0
+	for {
+		if loopCount >= 3 {
+			break
+		}
+		l.Printf("%v ", loopCount)
+		loopCount++
+	}
+	l.Println()
+
+	// Range loop - iterating over slice
+	words := []string{"Go", "is", "awesome"}
+	l.Print("Range over slice: ")
+	for i, word := range words {
+		l.Printf("[%v]=%v ", i, word)
+	}
+	l.Println()
+
+	// Range loop - iterating over map
+	l.Println("Range over map:")
+	person := map[string]string{
+		"name": "John",
+		"city": "New York",
+		"job":  "Developer",
+	}
+	// A map's order is random on every run: sort the keys for a fixed one
+	for _, key := range slices.Sorted(maps.Keys(person)) {
+		l.Printf("  %v: %v\n", key, person[key])
+	}
+
+	l.Section("operators")
+
+	a, b := 10, 3
+
+	// Arithmetic operators
+	l.Printf("Addition: %v + %v = %v\n", a, b, a+b)
+	l.Printf("Subtraction: %v - %v = %v\n", a, b, a-b)
+	l.Printf("Multiplication: %v * %v = %v\n", a, b, a*b)
+	l.Printf("Division: %v / %v = %v\n", a, b, a/b)
+	l.Printf("Modulo: %v %% %v = %v\n", a, b, a%b)
+
+	// Comparison operators
+	l.Printf("Equal: %v == %v = %v\n", a, b, a == b)
+	l.Printf("Not equal: %v != %v = %v\n", a, b, a != b)
+	l.Printf("Greater: %v > %v = %v\n", a, b, a > b)
+	l.Printf("Less: %v < %v = %v\n", a, b, a < b)
+
+	// Logical operators
+	x1, x2 := true, false
+	l.Printf("AND: %v && %v = %v\n", x1, x2, x1 && x2)
+	l.Printf("OR: %v || %v = %v\n", x1, x2, x1 || x2)
+	l.Printf("NOT: !%v = %v\n", x1, !x1)
+
+	l.End()
+	return nil
+}
+
+// Helper function to demonstrate blank identifier usage
+func divideWithRemainder(dividend, divisor int) (int, int) {
+	return dividend / divisor, dividend % divisor
+}
@@ -0,0 +1,279 @@
+package basics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 2: FUNCTIONS AND ERROR HANDLING
+// Topics covered:
+// 1. Function declaration and parameters
+// 2. Multiple return values
+// 3. Named return values
+// 4. Error handling (the Go way)
+// 5. Variadic functions
+// 6. Defer statement
+// 7. Panic and recover
+// 8. Function types and higher-order functions
+
+// ============ 1. BASIC FUNCTION ============
+// Function with parameters and single return value
+func addBasics(a, b int) int {
+	return a + b
+}
+
+// ============ 2. MULTIPLE RETURN VALUES ============
+// This is very common in Go - especially for returning (value, error)
+func divideBasics(dividend, divisor float64) (float64, error) {
+	if divisor == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return dividend / divisor, nil
+}
+
+// ============ 3. NAMED RETURN VALUES ============
+// Return values can be named - useful for documentation
+// Variables are pre-declared and zero-initialized
+func calculateArea(width, height float64) (area float64, perimeter float64) {
+	area = width * height
+	perimeter = 2 * (width + height)
+	return // naked return - returns named values (use sparingly)
+}
+
+// ============ 4. VARIADIC FUNCTIONS ============
+// Accept variable number of arguments
+func sum(numbers ...int) int {
+	total := 0
+	for _, num := range numbers {
+		total += num
+	}
+	return total
+}
+
+// ============ 5. VARIADIC WITH MULTIPLE TYPES ============
+func printAll(out *demo.Printer, args ...interface{}) {
+	for i, arg := range args {
+		out.Printf("[%d] %v (type: %T)\n", i, arg, arg)
+	}
+}
+
+// ============ 6. FUNCTION TYPES ============
+// Functions can be assigned to variables and passed around
+func multiply(a, b int) int {
+	return a * b
+}
+
+// Function that takes another function as parameter
+func applyOperation(x, y int, operation func(int, int) int) int {
+	return operation(x, y)
+}
+
+// Function that returns a function
+func makeMultiplier(factor int) func(int) int {
+	return func(x int) int {
+		return x * factor
+	}
+}
+
+// ============ 7. ERROR HANDLING ============
+// Custom error type
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Implement the error interface
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("validation error in %s: %s", e.Field, e.Message)
+}
+
+// Function with comprehensive error handling
+func ValidateAge(age int) error {
+	if age < 0 {
+		return ValidationError{
+			Field:   "age",
+			Message: "age cannot be negative",
+		}
+	}
+	if age > 150 {
+		return ValidationError{
+			Field:   "age",
+			Message: "age is unrealistic",
+		}
+	}
+	return nil
+}
+
+// ============ 8. STRING TO INT CONVERSION WITH ERROR ============
+func stringToInt(s string) (int, error) {
+	num, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert '%s' to int: %w", s, err)
+	}
+	return num, nil
+}
+
+// ============ 9. DEFER STATEMENT ============
+// Defer schedules a function to run at the end of current function
+func demonstrateDefer(out *demo.Printer) {
+	out.Println("Start of function")
+
+	defer out.Println("This runs last (deferred 1st)")
+	defer out.Println("This runs second last (deferred 2nd)")
+	defer out.Println("This runs third last (deferred 3rd)")
+
+	out.Println("Middle of function")
+}
+
+// Real-world defer example - resource cleanup
+func readFile(out *demo.Printer, filename string) (string, error) {
+	out.Printf("Opening file: %s\n", filename)
+	// In real code, you'd open a file here
+
+	// Defer ensures cleanup happens even if error occurs
+	defer func() {
+		out.Printf("Closing file: %s\n", filename)
+	}()
+
+	// Simulate reading file
+	return "file contents", nil
+}
+
+// ============ 10. PANIC AND RECOVER ============
+// Only use panic for truly exceptional circumstances!
+func safeDivide(out *demo.Printer, a, b int) int {
+	defer func() {
+		if r := recover(); r != nil {
+			out.Println("Recovered from panic:", r)
+		}
+	}()
+
+	if b == 0 {
+		panic("cannot divide by zero!")
+	}
+
+	return a / b
+}
+
+// ============ 11. CLOSURE EXAMPLE ============
+func counter() func() int {
+	count := 0
+	return func() int {
+		count++
+		return count
+	}
+}
+
+// ============ MAIN FUNCTION ============
+func CourseTwo(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 2)
+
+	l.Section("basic-functions")
+	result := addBasics(5, 3)
+	l.Printf("addBasics(5, 3) = %v\n", result)
+
+	l.Section("multiple-return-values")
+	quotient, err := divideBasics(10, 2)
+	if err != nil {
+		l.Printf("Error: %v\n", err)
+	} else {
+		l.Printf("10 / 2 = %v\n", quotient)
+	}
+
+	quotient, err = divideBasics(10, 0)
+	if err != nil {
+		l.Printf("Error: %v\n", err)
+	}
+
+	l.Section("named-return-values")
+	a, p := calculateArea(5, 4)
+	l.Printf("Rectangle 5x4: Area = %v, Perimeter = %v\n", a, p)
+
+	l.Section("variadic-functions")
+	l.Printf("sum(1, 2, 3) = %v\n", sum(1, 2, 3))
+	l.Printf("sum(1, 2, 3, 4, 5) = %v\n", sum(1, 2, 3, 4, 5))
+	l.Printf("sum() = %v\n", sum()) // Works even with no arguments
+
+	// Passing slice as variadic
+	numbers := []int{10, 20, 30}
+	l.Printf("sum(slice...) = %v\n", sum(numbers...))
+
+	l.Section("variadic-multiple-types")
+	printAll(l.Printer, "Go", 42, true, 3.14, []string{"a", "b"})
+
+	l.Section("function-types")
+
+	// Assign function to variable
+	var operation func(int, int) int = multiply
+	l.Printf("operation(4, 5) = %v\n", operation(4, 5))
+
+	// Pass function as argument
+	result = applyOperation(6, 7, addBasics)
+	l.Printf("applyOperation(6, 7, addBasics) = %v\n", result)
+
+	result = applyOperation(6, 7, multiply)
+	l.Printf("applyOperation(6, 7, multiply) = %v\n", result)
+
+	// Return function from function
+	double := makeMultiplier(2)
+	triple := makeMultiplier(3)
+	l.Printf("double(5) = %v\n", double(5))
+	l.Printf("triple(5) = %v\n", triple(5))
+
+	l.Section("error-handling")
+
+	testAges := []int{25, -5, 200, 45}
+	for _, age := range testAges {
+		if err := ValidateAge(age); err != nil {
+			l.Printf("❌ Age %d: %v\n", age, err)
+		} else {
+			l.Printf("✓ Age %d: Valid\n", age)
+		}
+	}
+
+	l.Section("string-int-conversion")
+
+	testStrings := []string{"42", "abc", "-10", "0"}
+	for _, str := range testStrings {
+		num, err := stringToInt(str)
+		if err != nil {
+			l.Printf("❌ '%s': %v\n", str, err)
+		} else {
+			l.Printf("✓ '%s': %d\n", str, num)
+		}
+	}
+
+	l.Section("defer-statement")
+	demonstrateDefer(l.Printer)
+	l.Println()
+
+	// Real-world defer example
+	content, err := readFile(l.Printer, "data.txt")
+	if err == nil {
+		l.Printf("Read: %s\n", content)
+	}
+
+	l.Section("panic-recover")
+	result = safeDivide(l.Printer, 10, 2)
+	l.Printf("safeDivide(10, 2) = %v\n", result)
+
+	result = safeDivide(l.Printer, 10, 0) // Will panic but recover
+
+	l.Section("closure")
+	counter1 := counter()
+	l.Printf("counter1(): %v\n", counter1())
+	l.Printf("counter1(): %v\n", counter1())
+	l.Printf("counter1(): %v\n", counter1())
+
+	counter2 := counter() // Separate counter
+	l.Printf("counter2(): %v\n", counter2())
+	l.Printf("counter2(): %v\n", counter2())
+
+	l.End()
+	return nil
+}
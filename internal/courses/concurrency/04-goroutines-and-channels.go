@@ -0,0 +1,531 @@
+package concurrency
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/pkg/pipeline"
+	"github.com/owolabijunior12/learning-golang/pkg/pool"
+	"golang.org/x/sync/errgroup"
+)
+
+// COURSE 4: CONCURRENCY - GOROUTINES AND CHANNELS
+// Topics covered:
+// 1. Goroutines (lightweight threads)
+// 2. Channels (safe communication between goroutines)
+// 3. Channel operations (send, receive, close)
+// 4. Channel directions (send-only, receive-only)
+// 5. Select statement (multiplexing)
+// 6. Buffered vs unbuffered channels
+// 7. Worker pools
+// 8. errgroup: parallel downloads with a limit and checksums
+// 9. Timeouts and context
+// 10. Fan-out/fan-in pipelines with pkg/pipeline
+//
+// The demos sleep with clock.Sleep and clock.After (see clock.go). They
+// are time.Sleep and time.After, unless the course runs with --fast.
+
+// ============ 1. SIMPLE GOROUTINE ============
+func greet(out *demo.Printer, name string) {
+	for i := 1; i <= 3; i++ {
+		out.Printf("Hello %s (iteration %d)\n", name, i)
+		demo.Clock.Sleep(100 * time.Millisecond)
+	}
+}
+
+// ============ 2. CHANNEL BASICS ============
+// Send numbers from 1 to n through a channel
+func generateNumbers(out *demo.Printer, n int, ch chan int) {
+	for i := 1; i <= n; i++ {
+		out.Printf("Generating: %d\n", i)
+		ch <- i // send
+		demo.Clock.Sleep(100 * time.Millisecond)
+	}
+	close(ch) // always close channels when done
+}
+
+// Read from channel and process
+func processNumbers(out *demo.Printer, ch chan int) {
+	for num := range ch { // receives until channel is closed
+		out.Printf("Processing: %d, Square: %d\n", num, num*num)
+	}
+}
+
+// ============ 3. BUFFERED CHANNELS ============
+// Can hold multiple values without blocking
+func bufferedChannelDemo(out *demo.Printer) {
+	ch := make(chan int, 3) // capacity of 3
+
+	ch <- 10
+	ch <- 20
+	ch <- 30
+
+	out.Printf("Value 1: %d\n", <-ch)
+	out.Printf("Value 2: %d\n", <-ch)
+	out.Printf("Value 3: %d\n", <-ch)
+}
+
+// ============ 4. SELECT STATEMENT ============
+// Wait for multiple channel operations
+func receiveFromMultiple(out *demo.Printer, ch1, ch2 chan string) {
+	for i := 0; i < 4; i++ {
+		select {
+		case msg := <-ch1:
+			out.Printf("From ch1: %s\n", msg)
+		case msg := <-ch2:
+			out.Printf("From ch2: %s\n", msg)
+		}
+	}
+}
+
+// ============ 5. TIMEOUT WITH SELECT ============
+func fetchWithTimeout(out *demo.Printer, ch chan string) {
+	select {
+	case result := <-ch:
+		out.Printf("Got result: %s\n", result)
+	case <-demo.Clock.After(2 * time.Second):
+		out.Println("Operation timed out!")
+	}
+}
+
+// ============ 6. WORKER POOL PATTERN ============
+type Job struct {
+	ID   int
+	Data string
+}
+
+type Result struct {
+	Job    Job
+	Output string
+}
+
+func worker(out *demo.Printer, id int, jobs <-chan Job, results chan<- Result) {
+	for job := range jobs {
+		out.Printf("Worker %d processing job %d\n", id, job.ID)
+		demo.Clock.Sleep(500 * time.Millisecond)
+
+		results <- Result{
+			Job:    job,
+			Output: fmt.Sprintf("Processed: %s", job.Data),
+		}
+	}
+}
+
+// process is worker's job as a pool.Func, for pkg/pool: the pool runs the
+// workers and the channels, so it only handles one job. It gives up when
+// ctx is done, which is what makes the pool's JobTimeout work.
+func process(ctx context.Context, job Job) (Result, error) {
+	if job.Data == "" {
+		panic(fmt.Sprintf("job %d has no data", job.ID))
+	}
+	select {
+	case <-demo.Clock.After(500 * time.Millisecond):
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+	return Result{Job: job, Output: fmt.Sprintf("Processed: %s", job.Data)}, nil
+}
+
+// ============ 7. ERRGROUP: A PARALLEL DOWNLOADER ============
+// A sync.WaitGroup waits for goroutines, and that's all. errgroup.Group
+// (golang.org/x/sync/errgroup) also returns the first error one of them
+// returned, cancels the others' context when one fails, and can cap how
+// many run at once.
+
+// download is a file to fetch, with the SHA-256 it should have.
+type download struct {
+	Name   string
+	SHA256 string // hex, as in a SHA256SUMS file
+}
+
+// downloadAll fetches files from baseURL, at most limit at once, and checks
+// each one's checksum. The first failure cancels the downloads still
+// running, and is returned. progress, if not nil, is called as each file's
+// bytes arrive, from the downloading goroutines.
+func downloadAll(ctx context.Context, client *http.Client, baseURL string, files []download, limit int, progress func(name string, done, total int64)) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(limit) // g.Go waits while limit downloads are running
+	for _, f := range files {
+		g.Go(func() error {
+			return downloadFile(ctx, client, baseURL+"/files/"+f.Name, f, progress)
+		})
+	}
+	return g.Wait()
+}
+
+// downloadFile fetches one file, hashing it as it arrives. A real
+// downloader would also save it: io.Copy(io.MultiWriter(file, hash), body).
+func downloadFile(ctx context.Context, client *http.Client, url string, f download, progress func(name string, done, total int64)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", f.Name, resp.Status)
+	}
+
+	hash := sha256.New()
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{r: resp.Body, report: func(done int64) { progress(f.Name, done, resp.ContentLength) }}
+	}
+	if _, err := io.Copy(hash, body); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != f.SHA256 {
+		return fmt.Errorf("%s: checksum mismatch: got %.12s..., want %.12s...", f.Name, got, f.SHA256)
+	}
+	return nil
+}
+
+// progressReader reports how many bytes have been read through it so far.
+type progressReader struct {
+	r      io.Reader
+	done   int64
+	report func(done int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.report(p.done)
+	}
+	return n, err
+}
+
+// fetchChecksums reads a SHA256SUMS file: a hex hash and a name per line.
+func fetchChecksums(ctx context.Context, client *http.Client, url string) ([]download, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var files []download
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		sum, name, ok := strings.Cut(sc.Text(), "  ")
+		if !ok {
+			return nil, fmt.Errorf("SHA256SUMS: bad line %q", sc.Text())
+		}
+		files = append(files, download{Name: name, SHA256: sum})
+	}
+	return files, sc.Err()
+}
+
+// fileServer is the demo's download site: a few generated files and their
+// SHA256SUMS. It sends them slowly, a chunk every 100ms, as a far-away
+// server would, and counts how many downloads it serves at once.
+type fileServer struct {
+	*httptest.Server
+	files    map[string][]byte
+	busy     atomic.Int32
+	mostBusy atomic.Int32
+}
+
+func newFileServer() *fileServer {
+	s := &fileServer{files: map[string][]byte{}}
+	sums := new(strings.Builder)
+	for _, f := range []struct {
+		name     string
+		size     int
+		tampered bool
+	}{
+		{"go1.txt", 48 << 10, false},
+		{"gopher.png", 96 << 10, false},
+		{"spec.html", 64 << 10, false},
+		{"tools.zip", 32 << 10, true}, // changed after its checksum was published
+	} {
+		data := make([]byte, f.size)
+		for i := range data {
+			data[i] = f.name[i%len(f.name)] + byte(i/len(f.name))
+		}
+		fmt.Fprintf(sums, "%x  %s\n", sha256.Sum256(data), f.name)
+		if f.tampered {
+			data[len(data)/2] ^= 0xff
+		}
+		s.files[f.name] = data
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /SHA256SUMS", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, sums.String())
+	})
+	mux.HandleFunc("GET /files/{name}", s.serveFile)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *fileServer) serveFile(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.files[r.PathValue("name")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	busy := s.busy.Add(1)
+	defer s.busy.Add(-1)
+	for most := s.mostBusy.Load(); busy > most && !s.mostBusy.CompareAndSwap(most, busy); most = s.mostBusy.Load() {
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	rc := http.NewResponseController(w)
+	for len(data) > 0 {
+		chunk := data[:min(16<<10, len(data))]
+		data = data[len(chunk):]
+		if _, err := w.Write(chunk); err != nil {
+			return
+		}
+		rc.Flush()
+		select {
+		case <-demo.Clock.After(100 * time.Millisecond):
+		case <-r.Context().Done(): // the download was cancelled
+			return
+		}
+	}
+}
+
+// ============ 8. PRODUCER-CONSUMER PATTERN ============
+func producer(out *demo.Printer, ch chan<- int, count int) {
+	for i := 1; i <= count; i++ {
+		out.Printf("Producing: %d\n", i)
+		ch <- i
+		demo.Clock.Sleep(200 * time.Millisecond)
+	}
+	close(ch)
+}
+
+func consumer(out *demo.Printer, ch <-chan int) {
+	for value := range ch {
+		out.Printf("Consuming: %d\n", value)
+	}
+}
+
+// ============ 9. FAN-OUT FAN-IN PATTERN ============
+
+// fanOut and fanIn are fan-out and fan-in by hand.
+//
+// Deprecated: use pipeline.FanOut and pipeline.FanIn. These can't be
+// cancelled: once the reader stops early, their goroutines block on a send
+// forever.
+func fanOut(out *demo.Printer, input <-chan int, numWorkers int) []<-chan int {
+	channels := make([]<-chan int, numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		ch := make(chan int)
+		go func(id int, ch chan<- int) {
+			for val := range input {
+				out.Printf("Worker %d received: %d\n", id, val)
+				ch <- val * val
+			}
+			close(ch)
+		}(i, ch)
+		channels[i] = ch
+	}
+	return channels
+}
+
+// Deprecated: use pipeline.FanIn.
+func fanIn(channels ...<-chan int) <-chan int {
+	out := make(chan int)
+	var wg sync.WaitGroup
+
+	for _, ch := range channels {
+		wg.Add(1)
+		go func(c <-chan int) {
+			defer wg.Done()
+			for val := range c {
+				out <- val
+			}
+		}(ch)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// ============ COURSE FOUR MAIN FUNCTION ============
+func CourseFour(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 4)
+
+	l.Section("basic-goroutines")
+
+	// Without goroutines - sequential execution
+	l.Println("Sequential (takes 3 seconds):")
+	greet(l.Printer, "Alice")
+
+	// With goroutines - concurrent execution
+	l.Println("Concurrent (takes ~1 second):")
+	go greet(l.Printer, "Bob")
+	go greet(l.Printer, "Charlie")
+	demo.Clock.Sleep(1 * time.Second) // Give goroutines time to complete
+
+	l.Section("unbuffered-channels")
+
+	ch := make(chan int) // unbuffered
+
+	go generateNumbers(l.Printer, 3, ch)
+	processNumbers(l.Printer, ch)
+
+	l.Section("buffered-channels")
+	bufferedChannelDemo(l.Printer)
+
+	l.Section("select-statement")
+
+	ch1 := make(chan string)
+	ch2 := make(chan string)
+
+	go func() {
+		demo.Clock.Sleep(100 * time.Millisecond)
+		ch1 <- "Message from ch1"
+		ch1 <- "Another from ch1"
+	}()
+
+	go func() {
+		demo.Clock.Sleep(200 * time.Millisecond)
+		ch2 <- "Message from ch2"
+		ch2 <- "Another from ch2"
+	}()
+
+	receiveFromMultiple(l.Printer, ch1, ch2)
+
+	l.Section("timeout")
+
+	slowChannel := make(chan string)
+	go func() {
+		demo.Clock.Sleep(3 * time.Second)
+		slowChannel <- "This will timeout"
+	}()
+
+	fetchWithTimeout(l.Printer, slowChannel)
+
+	l.Section("worker-pool")
+
+	jobs := make(chan Job, 5)
+	results := make(chan Result, 5)
+
+	// Start 3 workers
+	for w := 1; w <= 3; w++ {
+		go worker(l.Printer, w, jobs, results)
+	}
+
+	// Submit jobs
+	for j := 1; j <= 5; j++ {
+		jobs <- Job{ID: j, Data: fmt.Sprintf("Job %d data", j)}
+	}
+	close(jobs)
+
+	// Collect results
+	l.Println("Results:")
+	for i := 0; i < 5; i++ {
+		result := <-results
+		l.Printf("  Job %d: %s\n", result.Job.ID, result.Output)
+	}
+	l.Resume()
+
+	// The same jobs on pkg/pool, plus one that panics. Map returns the
+	// results in the order of the jobs.
+	poolJobs := []Job{{1, "Job 1 data"}, {2, "Job 2 data"}, {3, ""}, {4, "Job 4 data"}, {5, "Job 5 data"}}
+	for _, r := range pool.Map(ctx, poolJobs, process, pool.Options{Workers: 3, JobTimeout: 2 * time.Second}) {
+		if r.Err != nil {
+			l.Printf("  Job %d failed: %v\n", r.In.ID, r.Err)
+			continue
+		}
+		l.Printf("  Job %d: %s\n", r.In.ID, r.Out.Output)
+	}
+
+	l.Section("errgroup")
+
+	server := newFileServer()
+	defer server.Close()
+	files, err := fetchChecksums(ctx, server.Client(), server.URL+"/SHA256SUMS")
+	if err != nil {
+		return err
+	}
+
+	// Print each file's progress at every quarter. The callback runs on the
+	// downloading goroutines, so the map needs the mutex.
+	var mu sync.Mutex
+	shown := map[string]int64{}
+	progress := func(name string, done, total int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		for quarter := shown[name] + 1; quarter <= 4 && done*4 >= total*quarter; quarter++ {
+			l.Printf("  %-10s %3d%% of %d KiB\n", name, quarter*25, total>>10)
+			shown[name] = quarter
+		}
+	}
+
+	good := files[:3] // all but tools.zip
+	l.Printf("Downloading %d files, at most 2 at once:\n", len(good))
+	if err := downloadAll(ctx, server.Client(), server.URL, good, 2, progress); err != nil {
+		return err
+	}
+	l.Printf("All checksums match; never more than 2 at once: %v\n", server.mostBusy.Load() <= 2)
+	l.Resume()
+
+	l.Println("Downloading all of them, tools.zip too:")
+	err = downloadAll(ctx, server.Client(), server.URL, files, 2, nil)
+	l.Printf("Error: %v\n", err)
+	l.Resume()
+
+	l.Section("producer-consumer")
+
+	producerCh := make(chan int)
+	go producer(l.Printer, producerCh, 5)
+	consumer(l.Printer, producerCh)
+
+	l.Section("fan-out-fan")
+
+	// Two workers read the same channel, and their results are merged back
+	// into one. pipeline closes each channel once its senders are done, and
+	// stops every goroutine if one fails or ctx is cancelled.
+	p := pipeline.New(ctx)
+	input := pipeline.From(p, pipeline.Slice(1, 2, 3, 4))
+	branches := pipeline.FanOut(p, input, 2)
+	for i, branch := range branches {
+		branches[i] = pipeline.Then(p, branch, func(ctx context.Context, n int) (int, error) {
+			l.Printf("Worker %d received: %d\n", i+1, n)
+			return n * n, nil
+		})
+	}
+	squares, err := pipeline.Collect(p, pipeline.FanIn(p, branches...))
+	if err != nil {
+		return err
+	}
+	slices.Sort(squares)
+	l.Printf("Squared results from workers: %v\n", squares)
+
+	l.End()
+	return nil
+}
+
+// Helper types and functions for concurrency patterns
+type workerResult struct {
+	data chan int
+}
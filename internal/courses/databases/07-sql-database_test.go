@@ -0,0 +1,525 @@
+//go:build cgo
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // registers the "sqlite3" driver; needs cgo
+	"github.com/owolabijunior12/learning-golang/internal/fixtures"
+)
+
+// Run with: go test ./internal/courses/databases
+// The tests use in-memory SQLite databases, so they need no server.
+// TestFixtures loads its rows from the files in testdata/fixtures.
+
+// newBank returns a database with Alice holding 100.00 and Bob 50.00.
+func newBank(t testing.TB) (db *sql.DB, alice, bob int64) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to ":memory:" is a separate, empty database, so
+	// keep to one. It also serialises the transactions, as SQLite would.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if err := createAccounts(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	if alice, err = openAccount(ctx, db, "Alice", 100_00); err != nil {
+		t.Fatal(err)
+	}
+	if bob, err = openAccount(ctx, db, "Bob", 50_00); err != nil {
+		t.Fatal(err)
+	}
+	return db, alice, bob
+}
+
+// balances returns the balances of the given accounts.
+func balances(t testing.TB, db *sql.DB, ids ...int64) []int64 {
+	t.Helper()
+	var got []int64
+	for _, id := range ids {
+		cents, err := balance(context.Background(), db, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, cents)
+	}
+	return got
+}
+
+// newUsers returns a database with three users, the way the course's
+// SQLDatabase would hold them.
+func newUsers(t *testing.T) *SQLDatabase {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetMaxOpenConns(1) // see newBank
+	d := &SQLDatabase{conn: conn}
+	t.Cleanup(func() { d.Close() })
+
+	ctx := context.Background()
+	if err := d.CreateTable(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []DBUser{
+		{Name: "Charlie", Email: "charlie@example.com", Age: 30},
+		{Name: "Alice", Email: "alice@example.com", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 25},
+	} {
+		if _, err := d.InsertUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return d
+}
+
+// names returns the users' names, in order.
+func names(users []DBUser) []string {
+	var list []string
+	for _, u := range users {
+		list = append(list, u.Name)
+	}
+	return list
+}
+
+func TestUserQueries(t *testing.T) {
+	d := newUsers(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query func(ctx context.Context) ([]DBUser, error)
+		want  []string
+	}{
+		{"all, by id", d.GetAllUsers, []string{"Charlie", "Alice", "Bob"}},
+		{"by age, by name", func(ctx context.Context) ([]DBUser, error) { return d.GetUsersByAge(ctx, 30) }, []string{"Alice", "Charlie"}},
+		{"by ids", func(ctx context.Context) ([]DBUser, error) { return d.GetUsersByIDs(ctx, 3, 1, 99) }, []string{"Charlie", "Bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := tt.query(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(users); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if u, err := d.GetUserByID(ctx, 2); err != nil || u.Name != "Alice" {
+		t.Errorf("GetUserByID(2) = %+v, %v", u, err)
+	}
+	if n, err := d.CountUsers(ctx); err != nil || n != 3 {
+		t.Errorf("CountUsers() = %d, %v", n, err)
+	}
+	// The builder rejects "IN ()" before it reaches the database
+	if _, err := d.GetUsersByIDs(ctx); err == nil {
+		t.Error("GetUsersByIDs() with no IDs should fail")
+	}
+}
+
+// TestFixtures loads its data from testdata/fixtures rather than
+// inserting it in code. The subtests share one database, as tests against
+// a real server do: each loads the fixtures it needs, and Load empties
+// the tables again when the subtest ends, so the next starts clean.
+func TestFixtures(t *testing.T) {
+	conn, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetMaxOpenConns(1) // see newBank
+	d := &SQLDatabase{conn: conn}
+	t.Cleanup(func() { d.Close() })
+	ctx := context.Background()
+	if err := d.CreateTable(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := createAccounts(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("users by age", func(t *testing.T) {
+		fixtures.Load(t, conn, "testdata/fixtures/users.yaml")
+		users, err := d.GetUsersByAge(ctx, 30)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := names(users), []string{"Alice", "Charlie"}; !slices.Equal(got, want) {
+			t.Errorf("GetUsersByAge(30) = %v, want %v", got, want)
+		}
+		if u, err := d.GetUserByID(ctx, 4); err != nil || u.Name != "Dana O'Brien" {
+			t.Errorf("GetUserByID(4) = %+v, %v", u, err)
+		}
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		fixtures.Load(t, conn, "testdata/fixtures/users.yaml", "testdata/fixtures/accounts.json")
+		if err := transfer(ctx, conn, 1, 3, 30_00); err != nil {
+			t.Fatal(err)
+		}
+		if got := balances(t, conn, 1, 2, 3); !slices.Equal(got, []int64{70_00, 50_00, 30_00}) {
+			t.Errorf("balances = %v, want [7000 5000 3000]", got)
+		}
+	})
+
+	// Both subtests are over, and have left nothing behind
+	for _, table := range []string{"users", "accounts"} {
+		var n int
+		if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&n); err != nil || n != 0 {
+			t.Errorf("%s: %d rows, %v; want the fixtures gone", table, n, err)
+		}
+	}
+}
+
+func TestInsertUsers(t *testing.T) {
+	d := newUsers(t)
+	ctx := context.Background()
+
+	ids, err := d.InsertUsers(ctx,
+		DBUser{Name: "Dave", Email: "dave@example.com", Age: 40},
+		DBUser{Name: "Erin", Email: "erin@example.com", Age: 28})
+	if err != nil || !slices.Equal(ids, []int{4, 5}) {
+		t.Fatalf("InsertUsers = %v, %v; want [4 5]", ids, err)
+	}
+
+	// The second email is taken, so the first insert is rolled back too
+	if _, err := d.InsertUsers(ctx,
+		DBUser{Name: "Frank", Email: "frank@example.com"},
+		DBUser{Name: "Alice 2", Email: "alice@example.com"}); err == nil {
+		t.Error("InsertUsers with a taken email succeeded")
+	}
+	// And a cancelled context inserts nothing
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := d.InsertUsers(cancelled, DBUser{Name: "Gina", Email: "gina@example.com"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("InsertUsers with a cancelled context: err = %v, want context.Canceled", err)
+	}
+	if n, _ := d.CountUsers(ctx); n != 5 {
+		t.Errorf("%d users after the failed inserts, want 5", n)
+	}
+}
+
+// slowCount is enough rows for CountTo to take minutes, so only
+// cancellation can make it return in time.
+const slowCount = 1_000_000_000
+
+func TestCountToTimeout(t *testing.T) {
+	d := newUsers(t)
+	if n, err := d.CountTo(context.Background(), 1000); err != nil || n != 1000 {
+		t.Fatalf("CountTo(1000) = %d, %v", n, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := d.CountTo(ctx, slowCount)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if took := time.Since(start); took > 2*time.Second {
+		t.Errorf("the query stopped %v after the deadline", took)
+	}
+}
+
+func TestCountHandler(t *testing.T) {
+	d := newUsers(t)
+	handler := countHandler(d, 50*time.Millisecond, slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		query    string
+		wantCode int
+		wantBody string
+	}{
+		{"n=1000", http.StatusOK, "1000\n"},
+		{"n=abc", http.StatusBadRequest, "n must be a positive number\n"},
+		{"n=0", http.StatusBadRequest, "n must be a positive number\n"},
+		{fmt.Sprint("n=", slowCount), http.StatusGatewayTimeout, "the query took too long\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest("GET", "/count?"+tt.query, nil))
+			if rec.Code != tt.wantCode || rec.Body.String() != tt.wantBody {
+				t.Errorf("got %d %q, want %d %q", rec.Code, rec.Body.String(), tt.wantCode, tt.wantBody)
+			}
+		})
+	}
+}
+
+// A client that hangs up stops the query: the handler returns long before
+// the count or the timeout would have finished.
+func TestCountHandlerClientGone(t *testing.T) {
+	d := newUsers(t)
+	var logs strings.Builder // read only after the handler has returned
+	handler := countHandler(d, time.Minute, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	returned := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(returned)
+		handler(w, r)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", fmt.Sprint(server.URL, "/count?n=", slowCount), nil)
+	if _, err := http.DefaultClient.Do(req); err == nil {
+		t.Fatal("the request finished; it should have timed out on the client")
+	}
+
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler kept running after the client left")
+	}
+	if !strings.Contains(logs.String(), "client gone") {
+		t.Errorf("log = %q, want the cancellation logged", logs.String())
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	db, alice, bob := newBank(t)
+	if err := transfer(context.Background(), db, alice, bob, 30_00); err != nil {
+		t.Fatal(err)
+	}
+	if got := balances(t, db, alice, bob); got[0] != 70_00 || got[1] != 80_00 {
+		t.Errorf("balances = %v, want [7000 8000]", got)
+	}
+}
+
+// A failed transfer must leave every balance as it was.
+func TestTransferRollsBack(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		cents    int64
+		wantErr  error // nil: any error will do
+	}{
+		{name: "insufficient funds", from: "alice", to: "bob", cents: 100_01, wantErr: ErrInsufficientFunds},
+		{name: "unknown sender", from: "nobody", to: "bob", cents: 1_00, wantErr: ErrNoAccount},
+		// The debit succeeds and must be undone when the credit fails
+		{name: "unknown recipient", from: "alice", to: "nobody", cents: 1_00, wantErr: ErrNoAccount},
+		{name: "zero amount", from: "alice", to: "bob", cents: 0},
+		{name: "negative amount", from: "alice", to: "bob", cents: -5_00},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, alice, bob := newBank(t)
+			ids := map[string]int64{"alice": alice, "bob": bob, "nobody": 999}
+
+			err := transfer(context.Background(), db, ids[tt.from], ids[tt.to], tt.cents)
+			if err == nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got := balances(t, db, alice, bob); got[0] != 100_00 || got[1] != 50_00 {
+				t.Errorf("balances = %v after a failed transfer, want them unchanged", got)
+			}
+		})
+	}
+}
+
+func TestTransferCancelledContext(t *testing.T) {
+	db, alice, bob := newBank(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := transfer(ctx, db, alice, bob, 10_00); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if got := balances(t, db, alice, bob); got[0] != 100_00 || got[1] != 50_00 {
+		t.Errorf("balances = %v, want them unchanged", got)
+	}
+}
+
+// Many transfers at once: money is neither created nor lost, and no
+// balance goes below zero.
+func TestTransferConcurrent(t *testing.T) {
+	db, alice, bob := newBank(t)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			from, to := alice, bob
+			if i%2 == 1 {
+				from, to = bob, alice
+			}
+			err := transfer(context.Background(), db, from, to, 7_00)
+			if err != nil && !errors.Is(err, ErrInsufficientFunds) {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	got := balances(t, db, alice, bob)
+	if got[0]+got[1] != 150_00 || got[0] < 0 || got[1] < 0 {
+		t.Errorf("balances = %v, want two non-negative balances totalling 15000", got)
+	}
+}
+
+func Example_transfer() {
+	ctx := context.Background()
+	db, _ := sql.Open("sqlite3", ":memory:")
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	createAccounts(ctx, db)
+	alice, _ := openAccount(ctx, db, "Alice", 100_00)
+	bob, _ := openAccount(ctx, db, "Bob", 50_00)
+
+	fmt.Println(transfer(ctx, db, alice, bob, 30_00))
+	fmt.Println(transfer(ctx, db, alice, bob, 500_00))
+
+	a, _ := balance(ctx, db, alice)
+	b, _ := balance(ctx, db, bob)
+	fmt.Printf("Alice %d.%02d, Bob %d.%02d\n", a/100, a%100, b/100, b%100)
+	// Output:
+	// <nil>
+	// transfer 50000 cents from 1 (balance 7000): insufficient funds
+	// Alice 70.00, Bob 80.00
+}
+
+// newContacts returns a database with one contact with every column set,
+// one with none of the NULLable ones, and one with an empty phone, which
+// is not NULL. The IDs are 1, 2 and 3.
+func newContacts(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1) // see newBank
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if err := createContacts(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []nullContact{
+		{Name: "Alice", Phone: sql.NullString{String: "555-0100", Valid: true}, Age: sql.NullInt64{Int64: 30, Valid: true}},
+		{Name: "Bob"},
+		{Name: "Carol", Phone: sql.NullString{String: "", Valid: true}, Age: sql.NullInt64{Int64: 0, Valid: true}},
+	} {
+		if _, err := addContact(ctx, db, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestNullTypes(t *testing.T) {
+	db := newContacts(t)
+	tests := []struct {
+		id   int64
+		want nullContact
+	}{
+		{1, nullContact{"Alice", sql.NullString{String: "555-0100", Valid: true}, sql.NullInt64{Int64: 30, Valid: true}}},
+		{2, nullContact{Name: "Bob"}},
+		{3, nullContact{"Carol", sql.NullString{Valid: true}, sql.NullInt64{Valid: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.Name, func(t *testing.T) {
+			got, err := getNullContact(context.Background(), db, tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullPointers(t *testing.T) {
+	db := newContacts(t)
+	tests := []struct {
+		id    int64
+		name  string
+		phone *string
+		age   *int64
+	}{
+		{1, "Alice", ptr("555-0100"), ptr[int64](30)},
+		{2, "Bob", nil, nil},
+		{3, "Carol", ptr(""), ptr[int64](0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getPtrContact(context.Background(), db, tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != tt.name || !equalPtr(got.Phone, tt.phone) || !equalPtr(got.Age, tt.age) {
+				t.Errorf("got %s, %s, %s; want %s, %s, %s",
+					got.Name, show(got.Phone), show(got.Age), tt.name, show(tt.phone), show(tt.age))
+			}
+		})
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	db := newContacts(t)
+	tests := []struct {
+		id    int64
+		name  string
+		phone string
+		age   int64
+	}{
+		{1, "Alice", "555-0100", 30},
+		{2, "Bob", "unknown", 0},
+		// An empty phone is not NULL, so COALESCE keeps it
+		{3, "Carol", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, phone, age, err := contactOrDefaults(context.Background(), db, tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != tt.name || phone != tt.phone || age != tt.age {
+				t.Errorf("got %q, %q, %d; want %q, %q, %d", name, phone, age, tt.name, tt.phone, tt.age)
+			}
+		})
+	}
+}
+
+// The reason for all of the above: a NULL doesn't fit in a string.
+func TestNullIntoPlainValue(t *testing.T) {
+	db := newContacts(t)
+	var phone string
+	err := db.QueryRow("SELECT phone FROM contacts WHERE id = 2").Scan(&phone)
+	if err == nil || !strings.Contains(err.Error(), "NULL") {
+		t.Errorf("scanning NULL into a string: err = %v, want a NULL conversion error", err)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func equalPtr[T comparable](a, b *T) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+func show[T any](p *T) string {
+	if p == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*p)
+}
@@ -0,0 +1,497 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/patterns"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// COURSE 8: MONGODB AND NOSQL DATABASES
+// Topics covered:
+// 1. MongoDB connection
+// 2. BSON and document structure
+// 3. CRUD operations
+// 4. Filtering and querying
+// 5. Aggregation pipeline
+// 6. Indexes
+// 7. Error handling
+// 8. Best practices
+// 9. A user repository
+// 10. Change streams
+
+// Note: Requires "go.mongodb.org/mongo-driver/v2/mongo"
+
+// ============ 1. DOCUMENT MODEL ============
+type Product struct {
+	ID        string    `bson:"_id,omitempty"`
+	Name      string    `bson:"name"`
+	Price     float64   `bson:"price"`
+	Category  string    `bson:"category"`
+	InStock   bool      `bson:"inStock"`
+	Tags      []string  `bson:"tags"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+type Order struct {
+	ID        string    `bson:"_id,omitempty"`
+	UserID    string    `bson:"userId"`
+	Products  []string  `bson:"products"`
+	Total     float64   `bson:"total"`
+	Status    string    `bson:"status"` // pending, shipped, delivered
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// ============ 2. CONNECTION PATTERN ============
+// These functions compile with the course but need a MongoDB server, so
+// they are never run; the lesson shows the code of the mongo* ones (see
+// "<!-- code: name -->" in lessons/08-mongodb-database.md).
+func connectMongo(uri string) (*mongo.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.Ping(ctx, nil)
+	return client, err
+}
+
+// ============ 3. MONGODB OPERATIONS ============
+
+// INSERT DOCUMENT
+func insertProduct(collection *mongo.Collection, product Product) (any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := collection.InsertOne(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.InsertedID, nil
+}
+
+// INSERT MULTIPLE
+func insertProducts(collection *mongo.Collection, products []Product) ([]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := collection.InsertMany(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+	return result.InsertedIDs, nil
+}
+
+// FIND ONE DOCUMENT
+func findProductByName(collection *mongo.Collection, name string) (*Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var product Product
+	err := collection.FindOne(ctx, bson.M{"name": name}).Decode(&product)
+	return &product, err
+}
+
+// FIND MULTIPLE DOCUMENTS
+func findByCategory(collection *mongo.Collection, category string) ([]Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{"category": category})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var products []Product
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// UPDATE DOCUMENT
+func updateProduct(collection *mongo.Collection, id bson.ObjectID, update bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": update},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("document not found")
+	}
+
+	return nil
+}
+
+// DELETE DOCUMENT
+func deleteProduct(collection *mongo.Collection, id bson.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("document not found")
+	}
+
+	return nil
+}
+
+// ============ 4. ADVANCED QUERIES ============
+func findExpensive(ctx context.Context, collection *mongo.Collection) (*mongo.Cursor, error) {
+	// FILTERING
+	return collection.Find(ctx, bson.M{
+		"price": bson.M{"$gt": 100}, // greater than
+	})
+}
+
+func findElectronicsOrBooks(ctx context.Context, collection *mongo.Collection) (*mongo.Cursor, error) {
+	// COMPLEX FILTERS
+	opts := options.Find().SetLimit(10).SetSort(bson.D{{Key: "price", Value: -1}})
+
+	return collection.Find(ctx, bson.M{
+		"$or": []bson.M{
+			{"category": "electronics"},
+			{"category": "books"},
+		},
+	}, opts)
+}
+
+// ============ 5. CRUD, AGGREGATION, INDEXES, TRANSACTIONS ============
+func mongoCRUD(ctx context.Context, mongoURI string) error {
+	// Connect
+	client, err := mongo.Connect(options.Client().ApplyURI(mongoURI))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+
+	// Get collection
+	collection := client.Database("learn").Collection("products")
+
+	// INSERT
+	inserted, err := collection.InsertOne(ctx, Product{
+		Name:  "Laptop",
+		Price: 999.99,
+		Tags:  []string{"electronics", "computers"},
+	})
+	if err != nil {
+		return err
+	}
+	id := inserted.InsertedID
+
+	// FIND ONE
+	var product Product
+	err = collection.FindOne(ctx, bson.M{"name": "Laptop"}).Decode(&product)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("no laptop: %w", err)
+	}
+
+	// FIND MULTIPLE
+	cursor, err := collection.Find(ctx, bson.M{"price": bson.M{"$gt": 100}})
+	if err != nil {
+		return err
+	}
+	var products []Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return err
+	}
+
+	// UPDATE
+	updated, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"price": 1099.99}},
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Println("matched", updated.MatchedCount, "modified", updated.ModifiedCount)
+
+	// DELETE
+	deleted, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	fmt.Println("deleted", deleted.DeletedCount)
+	return nil
+}
+
+func mongoAggregation(ctx context.Context, collection *mongo.Collection) ([]bson.M, error) {
+	pipeline := mongo.Pipeline{
+		// Find products over $100
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "price", Value: bson.D{{Key: "$gt", Value: 100}}},
+		}}},
+
+		// Group by category and calculate stats
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$category"},
+			{Key: "avgPrice", Value: bson.D{{Key: "$avg", Value: "$price"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "maxPrice", Value: bson.D{{Key: "$max", Value: "$price"}}},
+		}}},
+
+		// Sort by count descending
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var results []bson.M
+	err = cursor.All(ctx, &results)
+	return results, err
+}
+
+func mongoIndexes(ctx context.Context, collection *mongo.Collection) error {
+	// Index on the name field
+	byName := mongo.IndexModel{
+		Keys: bson.D{{Key: "name", Value: 1}},
+	}
+
+	// Compound index
+	byCategoryAndPrice := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "category", Value: 1},
+			{Key: "price", Value: -1},
+		},
+	}
+
+	// Unique index
+	uniqueEmail := mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{byName, byCategoryAndPrice, uniqueEmail})
+	return err
+}
+
+func mongoTransaction(ctx context.Context, client *mongo.Client, order Order, filter, update bson.M) error {
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// All succeed or all roll back
+	orders := client.Database("learn").Collection("orders")
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		// Operation 1
+		if _, err := orders.InsertOne(ctx, order); err != nil {
+			return nil, err
+		}
+
+		// Operation 2
+		return orders.UpdateOne(ctx, filter, update)
+	})
+	return err
+}
+
+// ============ 6. A USER REPOSITORY ============
+
+// mongoUser is how a User is stored: MongoDB makes the _id, an ObjectID,
+// which the rest of the application sees as its 24-digit hex string.
+type mongoUser struct {
+	ID    bson.ObjectID `bson:"_id,omitempty"`
+	Name  string        `bson:"name"`
+	Email string        `bson:"email"`
+	Age   int           `bson:"age"`
+}
+
+func (d mongoUser) user() User {
+	return User{ID: d.ID.Hex(), Name: d.Name, Email: d.Email, Age: d.Age}
+}
+
+// MongoUsers is the UserRepository (see users.go) on a MongoDB collection.
+type MongoUsers struct {
+	users *mongo.Collection
+}
+
+// NewMongoUsers creates the collection's unique index on email at startup,
+// so duplicates fail in the database, where two requests can't race past
+// a check. Creating an index that already exists does nothing.
+func NewMongoUsers(ctx context.Context, users *mongo.Collection) (*MongoUsers, error) {
+	_, err := users.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create email index: %w", err)
+	}
+	return &MongoUsers{users: users}, nil
+}
+
+func (m *MongoUsers) Create(ctx context.Context, u User) (User, error) {
+	result, err := m.users.InsertOne(ctx, mongoUser{Name: u.Name, Email: u.Email, Age: u.Age})
+	if mongo.IsDuplicateKeyError(err) {
+		return User{}, fmt.Errorf("create %s: %w", u.Email, ErrEmailTaken)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("create %s: %w", u.Email, err)
+	}
+	u.ID = result.InsertedID.(bson.ObjectID).Hex()
+	return u, nil
+}
+
+func (m *MongoUsers) Get(ctx context.Context, id string) (User, error) {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return User{}, fmt.Errorf("get user %q: %w", id, ErrUserNotFound)
+	}
+	var doc mongoUser
+	err = m.users.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return User{}, fmt.Errorf("get user %q: %w", id, ErrUserNotFound)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("get user %q: %w", id, err)
+	}
+	return doc.user(), nil
+}
+
+func (m *MongoUsers) Update(ctx context.Context, u User) error {
+	oid, err := bson.ObjectIDFromHex(u.ID)
+	if err != nil {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrUserNotFound)
+	}
+	result, err := m.users.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{
+		"name":  u.Name,
+		"email": u.Email,
+		"age":   u.Age,
+	}})
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrEmailTaken)
+	}
+	if err != nil {
+		return fmt.Errorf("update user %q: %w", u.ID, err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrUserNotFound)
+	}
+	return nil
+}
+
+func (m *MongoUsers) Delete(ctx context.Context, id string) error {
+	oid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("delete user %q: %w", id, ErrUserNotFound)
+	}
+	result, err := m.users.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return fmt.Errorf("delete user %q: %w", id, err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("delete user %q: %w", id, ErrUserNotFound)
+	}
+	return nil
+}
+
+func (m *MongoUsers) List(ctx context.Context) ([]User, error) {
+	cursor, err := m.users.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "email", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	var docs []mongoUser
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	var users []User
+	for _, d := range docs {
+		users = append(users, d.user())
+	}
+	return users, nil
+}
+
+// ============ 7. CHANGE STREAMS ============
+
+// userChange is the part of a change event mongoWatch reads. FullDocument
+// is the user after the change; a delete has none.
+type userChange struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID bson.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument *mongoUser `bson:"fullDocument"`
+}
+
+// String is the message the event bus carries: "insert 65f0... alice@example.com".
+func (c userChange) String() string {
+	if c.FullDocument == nil {
+		return c.OperationType + " " + c.DocumentKey.ID.Hex()
+	}
+	return c.OperationType + " " + c.DocumentKey.ID.Hex() + " " + c.FullDocument.Email
+}
+
+// mongoOpenWatch opens a change stream on users. Changes made after it
+// returns are in the stream; open it before the writes you want to see.
+func mongoOpenWatch(ctx context.Context, users *mongo.Collection) (*mongo.ChangeStream, error) {
+	// Only the operations on documents; drops and renames end the stream
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "operationType", Value: bson.D{{Key: "$in", Value: bson.A{"insert", "update", "replace", "delete"}}}},
+		}}},
+	}
+	// Updates carry only the changed fields; UpdateLookup adds the whole
+	// document as it is now
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	return users.Watch(ctx, pipeline, opts)
+}
+
+// mongoWatch feeds every change in stream to the event bus (course 12's
+// Subject) until ctx is done, and closes stream.
+func mongoWatch(ctx context.Context, stream *mongo.ChangeStream, bus *patterns.Subject) error {
+	defer stream.Close(context.WithoutCancel(ctx))
+
+	for stream.Next(ctx) { // blocks until the next change
+		var change userChange
+		if err := stream.Decode(&change); err != nil {
+			return err
+		}
+		bus.Notify(change.String())
+
+		// To pick up where it left off after a restart, a service saves
+		// stream.ResumeToken() and reopens with SetResumeAfter(token)
+	}
+	if ctx.Err() != nil {
+		return nil // stopped on purpose
+	}
+	return stream.Err()
+}
+
+// liveDemo is set by mongo_watch.go, which is only built with -tags mongo:
+// change streams need a replica set, which not every MongoDB has.
+var liveDemo func(ctx context.Context, w io.Writer) error
+
+// ============ COURSE EIGHT MAIN FUNCTION ============
+func CourseEight(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 8)
+	if liveDemo != nil {
+		return liveDemo(ctx, w)
+	}
+	return nil
+}
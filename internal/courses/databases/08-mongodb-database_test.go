@@ -0,0 +1,85 @@
+package databases
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Run with: go test -run Change ./internal/courses/databases
+// These decode change events as the server sends them, so they need no
+// server; the live stream is in mongo_watch.go (go run -tags mongo ./cmd/learn 8).
+
+func TestUserChangeDecode(t *testing.T) {
+	id, err := bson.ObjectIDFromHex("65f0c0ffee00000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "Alice"}, {Key: "email", Value: "alice@example.com"}, {Key: "age", Value: 30}}
+
+	tests := []struct {
+		name  string
+		event bson.D
+		want  string
+	}{
+		{
+			name: "insert",
+			event: bson.D{
+				{Key: "_id", Value: bson.D{{Key: "_data", Value: "8265F0"}}}, // the resume token
+				{Key: "operationType", Value: "insert"},
+				{Key: "documentKey", Value: bson.D{{Key: "_id", Value: id}}},
+				{Key: "fullDocument", Value: alice},
+				{Key: "ns", Value: bson.D{{Key: "db", Value: "learn"}, {Key: "coll", Value: "users"}}},
+			},
+			want: "insert 65f0c0ffee00000000000001 alice@example.com",
+		},
+		{
+			name: "update with the looked-up document",
+			event: bson.D{
+				{Key: "operationType", Value: "update"},
+				{Key: "documentKey", Value: bson.D{{Key: "_id", Value: id}}},
+				{Key: "updateDescription", Value: bson.D{{Key: "updatedFields", Value: bson.D{{Key: "age", Value: 31}}}}},
+				{Key: "fullDocument", Value: alice},
+			},
+			want: "update 65f0c0ffee00000000000001 alice@example.com",
+		},
+		{
+			// The document was deleted before the lookup
+			name: "update of a gone document",
+			event: bson.D{
+				{Key: "operationType", Value: "update"},
+				{Key: "documentKey", Value: bson.D{{Key: "_id", Value: id}}},
+				{Key: "fullDocument", Value: nil},
+			},
+			want: "update 65f0c0ffee00000000000001",
+		},
+		{
+			name: "delete",
+			event: bson.D{
+				{Key: "operationType", Value: "delete"},
+				{Key: "documentKey", Value: bson.D{{Key: "_id", Value: id}}},
+			},
+			want: "delete 65f0c0ffee00000000000001",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := bson.Marshal(tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var change userChange
+			if err := bson.Unmarshal(raw, &change); err != nil {
+				t.Fatal(err)
+			}
+			if got := change.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if change.FullDocument != nil {
+				if u := change.FullDocument.user(); u.Name != "Alice" || u.Age != 30 {
+					t.Errorf("FullDocument = %+v", u)
+				}
+			}
+		})
+	}
+}
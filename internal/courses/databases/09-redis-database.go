@@ -0,0 +1,435 @@
+package databases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/pkg/redlock"
+	"github.com/redis/go-redis/v9"
+)
+
+// COURSE 9: REDIS - IN-MEMORY DATA STORE
+// Topics covered:
+// 1. Redis basics
+// 2. Data structures (strings, lists, sets, hashes, sorted sets)
+// 3. Key-value operations
+// 4. Expiration and TTL
+// 5. Transactions
+// 6. Pub/Sub
+// 7. Connection pooling
+// 8. Distributed locks
+// 9. Best practices
+
+// Note: Requires "github.com/redis/go-redis/v9"
+
+// The functions below compile with the course but need a Redis server, so
+// they are never run; the lesson shows the code of the redis* ones (see
+// "<!-- code: name -->" in lessons/09-redis-database.md). Each command's
+// result is printed with its error, which is redis.Nil for a missing key.
+
+// ============ REDIS CONNECTION PATTERN ============
+
+func connectRedis(addr string) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.Ping(ctx).Result()
+	return client, err
+}
+
+func redisConnect() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: os.Getenv("REDIS_ADDR"), // localhost:6379
+		DB:   0,
+	})
+}
+
+// ============ STRINGS ============
+
+func redisStrings(ctx context.Context, client *redis.Client) {
+	// SET key value
+	client.Set(ctx, "name", "Alice", 0)
+
+	// SET with expiration
+	client.Set(ctx, "session", "token123", 24*time.Hour)
+
+	// GET value
+	val, err := client.Get(ctx, "name").Result()
+	fmt.Println(val, err)
+
+	// EXISTS - check if key exists
+	exists, err := client.Exists(ctx, "name").Result()
+	fmt.Println(exists, err)
+
+	// DEL - delete key
+	deleted, err := client.Del(ctx, "name").Result()
+	fmt.Println(deleted, err)
+
+	// INCR/DECR - atomic increment/decrement
+	count, err := client.Incr(ctx, "counter").Result()
+	count, err = client.Decr(ctx, "counter").Result()
+	count, err = client.IncrBy(ctx, "counter", 5).Result()
+	fmt.Println(count, err)
+
+	// APPEND - append to string
+	length, err := client.Append(ctx, "name", "Smith").Result()
+	fmt.Println(length, err)
+
+	// STRLEN - get string length
+	length, err = client.StrLen(ctx, "name").Result()
+	fmt.Println(length, err)
+
+	// GETRANGE - get substring
+	substr, err := client.GetRange(ctx, "name", 0, 2).Result()
+	fmt.Println(substr, err)
+
+	// SETRANGE - set substring
+	length, err = client.SetRange(ctx, "name", 0, "Bob").Result()
+	fmt.Println(length, err)
+}
+
+// ============ LISTS ============
+
+func redisLists(ctx context.Context, client *redis.Client) {
+	// LPUSH - push to left
+	length, err := client.LPush(ctx, "queue", "task1", "task2").Result()
+
+	// RPUSH - push to right
+	length, err = client.RPush(ctx, "queue", "task3").Result()
+	fmt.Println(length, err)
+
+	// LPOP - pop from left
+	value, err := client.LPop(ctx, "queue").Result()
+	fmt.Println(value, err)
+
+	// RPOP - pop from right
+	value, err = client.RPop(ctx, "queue").Result()
+	fmt.Println(value, err)
+
+	// LLEN - get list length
+	length, err = client.LLen(ctx, "queue").Result()
+	fmt.Println(length, err)
+
+	// LRANGE - get range
+	values, err := client.LRange(ctx, "queue", 0, -1).Result()
+	fmt.Println(values, err)
+
+	// LTRIM - trim list
+	err = client.LTrim(ctx, "queue", 0, 10).Err()
+	fmt.Println(err)
+
+	// LINDEX - get element at index
+	value, err = client.LIndex(ctx, "queue", 0).Result()
+	fmt.Println(value, err)
+
+	// LREM - remove elements
+	removed, err := client.LRem(ctx, "queue", 1, "task1").Result()
+	fmt.Println(removed, err)
+}
+
+// ============ SETS ============
+
+func redisSets(ctx context.Context, client *redis.Client) {
+	// SADD - add to set
+	added, err := client.SAdd(ctx, "tags", "go", "rust", "python").Result()
+	fmt.Println(added, err)
+
+	// SMEMBERS - get all members
+	members, err := client.SMembers(ctx, "tags").Result()
+	fmt.Println(members, err)
+
+	// SCARD - set cardinality (size)
+	count, err := client.SCard(ctx, "tags").Result()
+	fmt.Println(count, err)
+
+	// SISMEMBER - check membership
+	is, err := client.SIsMember(ctx, "tags", "go").Result()
+	fmt.Println(is, err)
+
+	// SREM - remove from set
+	removed, err := client.SRem(ctx, "tags", "go").Result()
+	fmt.Println(removed, err)
+
+	// SUNION - union of sets
+	union, err := client.SUnion(ctx, "set1", "set2").Result()
+	fmt.Println(union, err)
+
+	// SINTER - intersection
+	inter, err := client.SInter(ctx, "set1", "set2").Result()
+	fmt.Println(inter, err)
+
+	// SDIFF - difference
+	diff, err := client.SDiff(ctx, "set1", "set2").Result()
+	fmt.Println(diff, err)
+
+	// SPOP - remove and return random member
+	member, err := client.SPop(ctx, "tags").Result()
+	fmt.Println(member, err)
+}
+
+// ============ HASHES ============
+
+func redisHashes(ctx context.Context, client *redis.Client) {
+	// HSET - set hash fields
+	created, err := client.HSet(ctx, "user:1", "name", "Alice", "age", 30).Result()
+	fmt.Println(created, err)
+
+	// HGET - get single field
+	name, err := client.HGet(ctx, "user:1", "name").Result()
+	fmt.Println(name, err)
+
+	// HGETALL - get all fields, as a map[string]string
+	all, err := client.HGetAll(ctx, "user:1").Result()
+	fmt.Println(all, err)
+
+	// HEXISTS - check if field exists
+	exists, err := client.HExists(ctx, "user:1", "name").Result()
+	fmt.Println(exists, err)
+
+	// HDEL - delete field
+	deleted, err := client.HDel(ctx, "user:1", "age").Result()
+	fmt.Println(deleted, err)
+
+	// HLEN - get number of fields
+	count, err := client.HLen(ctx, "user:1").Result()
+	fmt.Println(count, err)
+
+	// HKEYS - get all keys
+	keys, err := client.HKeys(ctx, "user:1").Result()
+	fmt.Println(keys, err)
+
+	// HVALS - get all values
+	values, err := client.HVals(ctx, "user:1").Result()
+	fmt.Println(values, err)
+
+	// HINCRBY - increment field
+	newAge, err := client.HIncrBy(ctx, "user:1", "age", 1).Result()
+	fmt.Println(newAge, err)
+}
+
+// ============ SORTED SETS ============
+
+func redisSortedSets(ctx context.Context, client *redis.Client) {
+	// ZADD - add to sorted set
+	added, err := client.ZAdd(ctx, "leaderboard", redis.Z{
+		Score:  100,
+		Member: "alice",
+	}, redis.Z{
+		Score:  95,
+		Member: "bob",
+	}).Result()
+	fmt.Println(added, err)
+
+	// ZRANGE - get range by rank
+	members, err := client.ZRange(ctx, "leaderboard", 0, -1).Result()
+	fmt.Println(members, err)
+
+	// ZREVRANGE - get range in reverse
+	members, err = client.ZRevRange(ctx, "leaderboard", 0, -1).Result()
+	fmt.Println(members, err)
+
+	// ZRANGE with scores
+	items, err := client.ZRangeWithScores(ctx, "leaderboard", 0, -1).Result()
+	fmt.Println(items, err)
+
+	// ZCARD - get sorted set size
+	count, err := client.ZCard(ctx, "leaderboard").Result()
+	fmt.Println(count, err)
+
+	// ZSCORE - get score of member
+	score, err := client.ZScore(ctx, "leaderboard", "alice").Result()
+	fmt.Println(score, err)
+
+	// ZRANK - get rank of member
+	rank, err := client.ZRank(ctx, "leaderboard", "alice").Result()
+	fmt.Println(rank, err)
+
+	// ZREM - remove member
+	removed, err := client.ZRem(ctx, "leaderboard", "bob").Result()
+	fmt.Println(removed, err)
+
+	// ZINCRBY - increment score
+	newScore, err := client.ZIncrBy(ctx, "leaderboard", 5, "alice").Result()
+	fmt.Println(newScore, err)
+
+	// ZCOUNT - count in score range
+	count, err = client.ZCount(ctx, "leaderboard", "90", "100").Result()
+	fmt.Println(count, err)
+}
+
+// ============ KEYS ============
+
+func redisKeys(ctx context.Context, client *redis.Client) {
+	// KEYS - find keys by pattern
+	keys, err := client.Keys(ctx, "user:*").Result()
+	fmt.Println(keys, err)
+
+	// SCAN - iterative key scan (better for large databases)
+	keys = nil
+	iter := client.Scan(ctx, 0, "user:*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	fmt.Println(keys, iter.Err())
+
+	// EXPIRE - set key expiration
+	ok, err := client.Expire(ctx, "key", 1*time.Hour).Result()
+	fmt.Println(ok, err)
+
+	// PEXPIRE - expire in milliseconds
+	ok, err = client.PExpire(ctx, "key", 1000*time.Millisecond).Result()
+	fmt.Println(ok, err)
+
+	// TTL - get remaining time to live
+	ttl, err := client.TTL(ctx, "key").Result()
+	fmt.Println(ttl, err)
+
+	// PERSIST - remove expiration
+	ok, err = client.Persist(ctx, "key").Result()
+	fmt.Println(ok, err)
+
+	// TYPE - get key type
+	keyType, err := client.Type(ctx, "key").Result()
+	fmt.Println(keyType, err)
+
+	// RENAME - rename key
+	status, err := client.Rename(ctx, "old", "new").Result()
+	fmt.Println(status, err)
+}
+
+// ============ TRANSACTIONS ============
+
+func redisTransactions(ctx context.Context, client *redis.Client) error {
+	// MULTI/EXEC
+	pipe := client.TxPipeline()
+
+	pipe.Set(ctx, "key1", "value1", 0)
+	pipe.Set(ctx, "key2", "value2", 0)
+	pipe.Get(ctx, "key1")
+
+	cmds, err := pipe.Exec(ctx)
+	fmt.Println(cmds, err)
+
+	// WATCH - optimistic locking: Exec fails if counter changes meanwhile
+	return client.Watch(ctx, func(tx *redis.Tx) error {
+		// Get current value
+		current, err := tx.Get(ctx, "counter").Int()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		// Use transaction
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, "counter", current+1, 0)
+			return nil
+		})
+		return err
+	}, "counter")
+}
+
+// ============ PUB/SUB ============
+
+func redisPubSub(ctx context.Context, client *redis.Client) error {
+	// SUBSCRIBE
+	pubsub := client.Subscribe(ctx, "events")
+
+	// PUBLISH
+	err := client.Publish(ctx, "events", "event data").Err()
+	if err != nil {
+		return err
+	}
+
+	// Pattern subscribe
+	if err := pubsub.PSubscribe(ctx, "events:*"); err != nil {
+		return err
+	}
+
+	go func() {
+		time.Sleep(time.Second)
+		pubsub.Unsubscribe(ctx, "events") // UNSUBSCRIBE
+		pubsub.Close()                    // ends the loop below
+	}()
+
+	for msg := range pubsub.Channel() {
+		fmt.Printf("Channel: %s, Message: %s\n", msg.Channel, msg.Payload)
+	}
+	return nil
+}
+
+// ============ PIPELINING ============
+
+func redisPipelining(ctx context.Context, client *redis.Client) error {
+	// Send multiple commands at once
+	pipe := client.Pipeline()
+
+	for i := 0; i < 100; i++ {
+		pipe.Set(ctx, fmt.Sprintf("key:%d", i), i, 0)
+	}
+
+	_, err := pipe.Exec(ctx)
+
+	// Significantly faster than individual commands
+	return err
+}
+
+// ============ SCRIPTING ============
+
+func redisScripting(ctx context.Context, client *redis.Client) (int64, error) {
+	// Lua scripting for atomic operations
+	script := redis.NewScript(`
+if redis.call('exists', KEYS[1]) == 1 then
+	return redis.call('incr', KEYS[1])
+else
+	redis.call('set', KEYS[1], 1)
+	return 1
+end
+`)
+
+	return script.Run(ctx, client, []string{"counter"}).Int64()
+}
+
+// ============ DISTRIBUTED LOCKS ============
+
+// redisNaiveLock is the lock that looks right and isn't: the key holds no
+// token, so the DEL can delete a lock someone else now holds.
+func redisNaiveLock(ctx context.Context, client *redis.Client) error {
+	ok, err := client.SetNX(ctx, "lock:report", 1, 10*time.Second).Result()
+	if err != nil || !ok {
+		return err // held by someone else
+	}
+	defer client.Del(ctx, "lock:report") // whose lock is it by now?
+
+	return sendReport(ctx) // takes longer than 10s, or the process pauses...
+}
+
+// redisLock uses pkg/redlock: SET NX PX with a random token, and a release
+// that deletes the key only while it still holds that token.
+func redisLock(ctx context.Context, client *redis.Client) error {
+	lock, err := redlock.New(client).TryObtain(ctx, "lock:report", 10*time.Second)
+	if errors.Is(err, redlock.ErrNotObtained) {
+		return nil // another process is sending it
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Release(context.WithoutCancel(ctx)) // ErrNotHeld if it expired first
+
+	return sendReport(ctx)
+}
+
+func sendReport(ctx context.Context) error { return nil }
+
+// ============ COURSE NINE MAIN FUNCTION ============
+func CourseNine(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 9)
+	return nil
+}
@@ -0,0 +1,404 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/redis/go-redis/v9"
+)
+
+// COURSE 22: CACHE-ASIDE - REDIS IN FRONT OF SQL
+// Topics covered:
+// 1. The cache-aside pattern: the application reads the cache, and on a
+//    miss the database, and fills the cache itself
+// 2. Expiry with a TTL
+// 3. Invalidating on update and delete
+// 4. Hit and miss metrics
+// 5. Carrying on when the cache is down
+//
+// The users live in SQLite (course 7's SQLDatabase, in memory), so SQLite's
+// driver and cgo are needed. The cache is Redis when REDIS_ADDR is set (see
+// course 9), and otherwise an in-memory stand-in with the same commands.
+
+// ============ 1. THE CACHE ============
+
+// errCacheMiss is what a cache returns for a key it doesn't hold, like
+// redis.Nil.
+var errCacheMiss = errors.New("cache miss")
+
+// userCache is the part of Redis the pattern needs: GET, SET with an
+// expiry, and DEL.
+type userCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+}
+
+// redisCache is a userCache on a Redis server.
+type redisCache struct {
+	client *redis.Client
+}
+
+func (c redisCache) Get(ctx context.Context, key string) (string, error) {
+	v, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", errCacheMiss
+	}
+	return v, err
+}
+
+func (c redisCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c redisCache) Del(ctx context.Context, keys ...string) error {
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// memoryCache stands in for Redis when there is none: a map of values with
+// their expiry times, behind a mutex. Its clock only moves when
+// FastForward moves it (as miniredis's does), so expiry can be shown
+// without waiting.
+type memoryCache struct {
+	mu     sync.Mutex
+	now    time.Time
+	values map[string]memoryValue
+}
+
+type memoryValue struct {
+	value   string
+	expires time.Time // zero: never
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{now: time.Unix(0, 0), values: make(map[string]memoryValue)}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[key]
+	if !ok || (!v.expires.IsZero() && !c.now.Before(v.expires)) {
+		delete(c.values, key)
+		return "", errCacheMiss
+	}
+	return v.value, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v := memoryValue{value: value}
+	if ttl > 0 {
+		v.expires = c.now.Add(ttl)
+	}
+	c.values[key] = v
+	return nil
+}
+
+func (c *memoryCache) Del(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+	return nil
+}
+
+// FastForward moves the cache's clock on by d.
+func (c *memoryCache) FastForward(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// downCache is a cache whose server can't be reached.
+type downCache struct{}
+
+var errCacheDown = errors.New("dial tcp 127.0.0.1:6379: connect: connection refused")
+
+func (downCache) Get(context.Context, string) (string, error)              { return "", errCacheDown }
+func (downCache) Set(context.Context, string, string, time.Duration) error { return errCacheDown }
+func (downCache) Del(context.Context, ...string) error                     { return errCacheDown }
+
+// ============ 2. CACHE-ASIDE READS ============
+
+// CachedUsers reads users through a cache in front of the database.
+type CachedUsers struct {
+	db    *SQLDatabase
+	cache userCache
+	ttl   time.Duration
+
+	hits, misses, errors atomic.Int64
+}
+
+// CacheStats count what happened to reads.
+type CacheStats struct {
+	Hits   int64 // answered from the cache
+	Misses int64 // answered by the database
+	Errors int64 // cache commands that failed
+}
+
+// HitRate is the share of reads the cache answered.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func NewCachedUsers(db *SQLDatabase, cache userCache, ttl time.Duration) *CachedUsers {
+	return &CachedUsers{db: db, cache: cache, ttl: ttl}
+}
+
+func userKey(id int) string { return "cache:user:" + strconv.Itoa(id) }
+
+// GetUserByID looks in the cache first. On a miss it reads the database
+// and stores the user with a TTL, so the next read is a hit. A cache that
+// fails is counted and skipped: it only makes reads faster, so the
+// database answers instead.
+func (c *CachedUsers) GetUserByID(ctx context.Context, id int) (*DBUser, error) {
+	key := userKey(id)
+	cached, err := c.cache.Get(ctx, key)
+	if err == nil {
+		var user DBUser
+		if json.Unmarshal([]byte(cached), &user) == nil {
+			c.hits.Add(1)
+			return &user, nil
+		}
+		// Unreadable (written by an older version?): read it again
+	} else if !errors.Is(err, errCacheMiss) {
+		c.errors.Add(1)
+	}
+
+	c.misses.Add(1)
+	user, err := c.db.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.Set(ctx, key, string(data), c.ttl); err != nil {
+		c.errors.Add(1)
+	}
+	return user, nil
+}
+
+// ============ 3. INVALIDATION ============
+
+// UpdateUser writes the database, then deletes the cached copy; the next
+// read caches the new row. Deleting is safer than writing the new value to
+// the cache: two updates racing could leave the older one there, while a
+// delete can only cause one extra miss.
+func (c *CachedUsers) UpdateUser(ctx context.Context, id int, user DBUser) error {
+	if err := c.db.UpdateUser(ctx, id, user); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, id)
+}
+
+// DeleteUser deletes the user, then its cached copy.
+func (c *CachedUsers) DeleteUser(ctx context.Context, id int) error {
+	if err := c.db.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	return c.invalidate(ctx, id)
+}
+
+func (c *CachedUsers) invalidate(ctx context.Context, id int) error {
+	if err := c.cache.Del(ctx, userKey(id)); err != nil {
+		c.errors.Add(1)
+		// The write happened; only the cache is behind, until the TTL
+		return fmt.Errorf("user %d saved, but the cached copy may be stale for up to %v: %w", id, c.ttl, err)
+	}
+	return nil
+}
+
+// ============ 4. METRICS ============
+
+// Stats returns the counts so far.
+func (c *CachedUsers) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Errors: c.errors.Load()}
+}
+
+func (s CacheStats) String() string {
+	return fmt.Sprintf("%d hits, %d misses (%.0f%% hit rate), %d cache errors", s.Hits, s.Misses, 100*s.HitRate(), s.Errors)
+}
+
+// ============ SETUP ============
+
+// openUsersDB returns course 7's database, in memory, with users in it.
+func openUsersDB(ctx context.Context, users ...DBUser) (*SQLDatabase, error) {
+	conn, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("this course needs SQLite, whose driver needs cgo (CGO_ENABLED=1): %w", err)
+	}
+	// Every connection to ":memory:" is a separate, empty database
+	conn.SetMaxOpenConns(1)
+	db := &SQLDatabase{conn: conn}
+	if err := db.CreateTable(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	for _, u := range users {
+		if _, err := db.InsertUser(ctx, u); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// openCache connects to Redis at REDIS_ADDR, or returns the stand-in.
+func openCache(ctx context.Context) (userCache, string) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		return newMemoryCache(), "the in-memory stand-in (set REDIS_ADDR to use Redis)"
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return newMemoryCache(), fmt.Sprintf("the in-memory stand-in (Redis at %s: %v)", addr, err)
+	}
+	return redisCache{client}, "Redis at " + addr
+}
+
+// wait lets d pass for the cache: the stand-in jumps, Redis takes its time.
+func wait(cache userCache, d time.Duration) {
+	if m, ok := cache.(*memoryCache); ok {
+		m.FastForward(d)
+		return
+	}
+	demo.Clock.Sleep(d)
+}
+
+// ============ COURSE TWENTY-TWO MAIN FUNCTION ============
+func CourseTwentyTwo(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 22)
+
+	l.Section("cache-aside")
+	db, err := openUsersDB(ctx,
+		DBUser{Name: "Alice", Email: "alice@example.com", Age: 30},
+		DBUser{Name: "Bob", Email: "bob@example.com", Age: 25},
+		DBUser{Name: "Carol", Email: "carol@example.com", Age: 35},
+		DBUser{Name: "Dave", Email: "dave@example.com", Age: 41},
+		DBUser{Name: "Erin", Email: "erin@example.com", Age: 28},
+	)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	cache, name := openCache(ctx)
+	l.Printf("Users: 5 rows in SQLite. Cache: %s\n", name)
+	if rc, ok := cache.(redisCache); ok {
+		defer rc.client.Close()
+	}
+	// Start from an empty cache, even on a Redis used before
+	for id := 1; id <= 5; id++ {
+		cache.Del(ctx, userKey(id))
+	}
+	users := NewCachedUsers(db, cache, 2*time.Second)
+	l.Resume()
+
+	// read prints a user and where the read was answered
+	read := func(id int) {
+		misses := users.Stats().Misses
+		user, err := users.GetUserByID(ctx, id)
+		from := "cache"
+		if users.Stats().Misses > misses {
+			from = "SQL, then cached"
+		}
+		if err != nil {
+			l.Printf("  user %d: %v (from SQL, nothing cached)\n", id, err)
+			return
+		}
+		l.Printf("  user %d: %s, %d (from %s)\n", id, user.Name, user.Age, from)
+	}
+
+	l.Section("reads")
+	read(1)
+	read(1)
+	read(2)
+	read(1)
+	l.Printf("%v\n", users.Stats())
+	l.Resume()
+
+	l.Section("ttl")
+	wait(cache, 3*time.Second)
+	l.Println("3 seconds later, past the 2s TTL:")
+	read(1)
+	l.Resume()
+
+	l.Section("invalidation")
+	l.Println("Alice turns 31, through CachedUsers:")
+	if err := users.UpdateUser(ctx, 1, DBUser{Name: "Alice", Email: "alice@example.com", Age: 31}); err != nil {
+		return err
+	}
+	read(1)
+	read(1)
+	l.Println("Bob is deleted:")
+	if err := users.DeleteUser(ctx, 2); err != nil {
+		return err
+	}
+	read(2)
+	l.Resume()
+
+	l.Println("Alice turns 32, but straight in the database:")
+	if err := db.UpdateUser(ctx, 1, DBUser{Name: "Alice", Email: "alice@example.com", Age: 32}); err != nil {
+		return err
+	}
+	read(1)
+	wait(cache, 3*time.Second)
+	l.Println("After the TTL:")
+	read(1)
+	l.Resume()
+
+	l.Section("metrics")
+	// A day of traffic in small: 200 reads, 100ms apart, where Alice is
+	// asked for most and Erin least (Bob is gone)
+	traffic := NewCachedUsers(db, cache, 2*time.Second)
+	rng := rand.New(rand.NewPCG(22, 22))
+	ids := []int{1, 3, 4, 5}
+	perUser := map[int]int{}
+	for range 200 {
+		id := ids[min(int(rng.ExpFloat64()), len(ids)-1)]
+		perUser[id]++
+		if _, err := traffic.GetUserByID(ctx, id); err != nil {
+			return err
+		}
+		wait(cache, 100*time.Millisecond)
+	}
+	l.Printf("Reads per user: 1: %d, 3: %d, 4: %d, 5: %d\n", perUser[1], perUser[3], perUser[4], perUser[5])
+	l.Printf("%v\n", traffic.Stats())
+	l.Resume()
+
+	l.Section("cache-down")
+	down := NewCachedUsers(db, downCache{}, 2*time.Second)
+	for range 3 {
+		if _, err := down.GetUserByID(ctx, 1); err != nil {
+			return err
+		}
+	}
+	l.Printf("3 reads with Redis down: %v\n", down.Stats())
+	err = down.UpdateUser(ctx, 3, DBUser{Name: "Carol", Email: "carol@example.com", Age: 36})
+	l.Printf("Update: %v\n", err)
+	l.Resume()
+
+	l.End()
+	return nil
+}
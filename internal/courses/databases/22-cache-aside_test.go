@@ -0,0 +1,144 @@
+//go:build cgo
+
+package databases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Run with: go test -run 'CacheAside|MemoryCache' ./internal/courses/databases
+// The database is in-memory SQLite and the cache the in-memory stand-in.
+
+func newCachedUsers(t *testing.T, cache userCache) (*CachedUsers, *SQLDatabase) {
+	t.Helper()
+	db, err := openUsersDB(context.Background(),
+		DBUser{Name: "Alice", Email: "alice@example.com", Age: 30},
+		DBUser{Name: "Bob", Email: "bob@example.com", Age: 25},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewCachedUsers(db, cache, time.Minute), db
+}
+
+func TestCacheAsideReads(t *testing.T) {
+	ctx := context.Background()
+	users, _ := newCachedUsers(t, newMemoryCache())
+
+	for range 3 {
+		user, err := users.GetUserByID(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != "Alice" || user.Age != 30 {
+			t.Errorf("got %+v", user)
+		}
+	}
+	if _, err := users.GetUserByID(ctx, 99); err == nil {
+		t.Error("GetUserByID(99) found a user")
+	}
+	if got, want := users.Stats(), (CacheStats{Hits: 2, Misses: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCacheAsideExpiry(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryCache()
+	users, db := newCachedUsers(t, cache)
+
+	users.GetUserByID(ctx, 1)
+	// Changed behind the cache's back: stale until the TTL
+	if err := db.UpdateUser(ctx, 1, DBUser{Name: "Alice", Email: "alice@example.com", Age: 31}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		after   time.Duration
+		wantAge int
+	}{
+		{59 * time.Second, 30},
+		{time.Second, 31},
+	}
+	for _, tt := range tests {
+		cache.FastForward(tt.after)
+		user, err := users.GetUserByID(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Age != tt.wantAge {
+			t.Errorf("after another %v: age %d, want %d", tt.after, user.Age, tt.wantAge)
+		}
+	}
+}
+
+func TestCacheAsideInvalidation(t *testing.T) {
+	ctx := context.Background()
+	users, _ := newCachedUsers(t, newMemoryCache())
+
+	users.GetUserByID(ctx, 1)
+	users.GetUserByID(ctx, 2)
+	if err := users.UpdateUser(ctx, 1, DBUser{Name: "Alice", Email: "alice@example.com", Age: 31}); err != nil {
+		t.Fatal(err)
+	}
+	if user, err := users.GetUserByID(ctx, 1); err != nil || user.Age != 31 {
+		t.Errorf("after UpdateUser: %+v, %v; want age 31", user, err)
+	}
+	if err := users.DeleteUser(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if user, err := users.GetUserByID(ctx, 2); err == nil {
+		t.Errorf("after DeleteUser: %+v", user)
+	}
+	if err := users.UpdateUser(ctx, 2, DBUser{Name: "Bob", Email: "bob@example.com"}); err == nil {
+		t.Error("updating a deleted user succeeded")
+	}
+}
+
+func TestCacheAsideCacheDown(t *testing.T) {
+	ctx := context.Background()
+	users, _ := newCachedUsers(t, downCache{})
+
+	user, err := users.GetUserByID(ctx, 1)
+	if err != nil || user.Name != "Alice" {
+		t.Fatalf("read with the cache down: %+v, %v", user, err)
+	}
+	if s := users.Stats(); s.Misses != 1 || s.Errors != 2 {
+		t.Errorf("Stats() = %+v, want 1 miss and 2 errors (GET and SET)", s)
+	}
+	err = users.UpdateUser(ctx, 1, DBUser{Name: "Alice", Email: "alice@example.com", Age: 31})
+	if !errors.Is(err, errCacheDown) {
+		t.Errorf("UpdateUser = %v, want the cache's error", err)
+	}
+	// The write itself happened
+	if user, _ := users.GetUserByID(ctx, 1); user == nil || user.Age != 31 {
+		t.Errorf("after the update: %+v", user)
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := newMemoryCache()
+	c.Set(ctx, "ttl", "a", time.Second)
+	c.Set(ctx, "forever", "b", 0)
+
+	c.FastForward(999 * time.Millisecond)
+	if v, err := c.Get(ctx, "ttl"); v != "a" || err != nil {
+		t.Errorf("before the TTL: %q, %v", v, err)
+	}
+	c.FastForward(time.Millisecond)
+	if _, err := c.Get(ctx, "ttl"); !errors.Is(err, errCacheMiss) {
+		t.Errorf("at the TTL: err = %v, want errCacheMiss", err)
+	}
+	c.FastForward(time.Hour)
+	if v, _ := c.Get(ctx, "forever"); v != "b" {
+		t.Errorf("a key without a TTL expired")
+	}
+	c.Del(ctx, "forever", "missing")
+	if _, err := c.Get(ctx, "forever"); !errors.Is(err, errCacheMiss) {
+		t.Errorf("after Del: err = %v", err)
+	}
+}
@@ -0,0 +1,331 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 23: CONNECTION POOLS - WATCHING AND TUNING database/sql
+// Topics covered:
+// 1. What db.Stats() reports about the pool
+// 2. Sampling the stats and serving them as Prometheus metrics
+// 3. A load test that exhausts the pool
+// 4. Tuning MaxOpenConns, and not holding connections
+//
+// The database is SQLite in memory, so SQLite's driver and cgo are needed;
+// the pool is database/sql's and behaves the same for every driver.
+
+// ============ 1. SAMPLING THE POOL ============
+
+// PoolMetrics samples db.Stats() every interval and serves the latest
+// sample as Prometheus metrics. A scrape every 15 seconds would miss a
+// pool that is full for two, so it also keeps the most connections seen
+// in use between scrapes.
+type PoolMetrics struct {
+	db   *sql.DB
+	name string // the db_name label
+
+	mu        sync.Mutex
+	last      sql.DBStats
+	peakInUse int // since the last scrape
+}
+
+func NewPoolMetrics(db *sql.DB, name string) *PoolMetrics {
+	return &PoolMetrics{db: db, name: name}
+}
+
+// Sample reads db.Stats() now and records it.
+func (m *PoolMetrics) Sample() sql.DBStats {
+	s := m.db.Stats()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.last = s
+	m.peakInUse = max(m.peakInUse, s.InUse)
+	return s
+}
+
+// Run samples every interval until ctx is done.
+func (m *PoolMetrics) Run(ctx context.Context, interval time.Duration) {
+	for {
+		m.Sample()
+		select {
+		case <-ctx.Done():
+			return
+		case <-demo.Clock.After(interval):
+		}
+	}
+}
+
+// ServeHTTP serves the metrics in Prometheus' text format, for a scrape of
+// /metrics.
+func (m *PoolMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics and starts a new peak.
+func (m *PoolMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	s, peak := m.last, m.peakInUse
+	m.peakInUse = s.InUse
+	m.mu.Unlock()
+	return writePoolMetrics(w, m.name, s, peak)
+}
+
+// ============ 2. PROMETHEUS METRICS ============
+
+// poolMetric is one metric in the exposition format. The names are those
+// of client_golang's collectors.NewDBStatsCollector, so dashboards made for
+// it work.
+type poolMetric struct {
+	name, kind, help string
+	value            func(s sql.DBStats, peak int) float64
+}
+
+var poolMetrics = []poolMetric{
+	{"go_sql_max_open_connections", "gauge", "Maximum number of open connections to the database.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.MaxOpenConnections) }},
+	{"go_sql_open_connections", "gauge", "The number of established connections both in use and idle.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.OpenConnections) }},
+	{"go_sql_in_use_connections", "gauge", "The number of connections currently in use.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.InUse) }},
+	{"go_sql_in_use_connections_peak", "gauge", "The most connections in use in a sample since the last scrape.",
+		func(_ sql.DBStats, peak int) float64 { return float64(peak) }},
+	{"go_sql_idle_connections", "gauge", "The number of idle connections.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.Idle) }},
+	{"go_sql_wait_count_total", "counter", "The total number of connections waited for.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.WaitCount) }},
+	{"go_sql_wait_duration_seconds_total", "counter", "The total time blocked waiting for a new connection.",
+		func(s sql.DBStats, _ int) float64 { return s.WaitDuration.Seconds() }},
+	{"go_sql_max_idle_closed_total", "counter", "The total number of connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.MaxIdleClosed) }},
+	{"go_sql_max_idle_time_closed_total", "counter", "The total number of connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.MaxIdleTimeClosed) }},
+	{"go_sql_max_lifetime_closed_total", "counter", "The total number of connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats, _ int) float64 { return float64(s.MaxLifetimeClosed) }},
+}
+
+// writePoolMetrics writes s as Prometheus metrics labelled db_name=name.
+// Counters only go up and end in _total; Prometheus' rate() turns them
+// into per-second numbers.
+func writePoolMetrics(w io.Writer, name string, s sql.DBStats, peakInUse int) (int64, error) {
+	var written int64
+	for _, m := range poolMetrics {
+		n, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s{db_name=%q} %g\n",
+			m.name, m.help, m.name, m.kind, m.name, name, m.value(s, peakInUse))
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// ============ 3. A LOAD TEST ============
+
+// loadTest is a burst of concurrent requests on one pool.
+type loadTest struct {
+	Requests int           // all started at once
+	Hold     time.Duration // how long each keeps its connection
+	Timeout  time.Duration // each request's deadline, waiting included
+}
+
+// loadResult is what a load test saw.
+type loadResult struct {
+	OK, TimedOut int
+	Stats        sql.DBStats // the pool's, afterwards
+}
+
+// Run warms db up, sends the requests to it and waits for them all.
+func (lt loadTest) Run(ctx context.Context, db *sql.DB) (loadResult, error) {
+	if err := warmUp(ctx, db); err != nil {
+		return loadResult{}, err
+	}
+	var (
+		mu     sync.Mutex
+		result loadResult
+		wg     sync.WaitGroup
+	)
+	for range lt.Requests {
+		wg.Go(func() {
+			err := slowRequest(ctx, db, lt.Hold, lt.Timeout)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.TimedOut++
+			} else {
+				result.OK++
+			}
+		})
+	}
+	wg.Wait()
+	result.Stats = db.Stats()
+	return result, nil
+}
+
+// warmUp opens as many connections as db may have and leaves them idle, as
+// in a service that has been running for a while. Opening a connection
+// costs far more than a query, and isn't what the load test measures.
+func warmUp(ctx context.Context, db *sql.DB) error {
+	n := db.Stats().MaxOpenConnections
+	db.SetMaxIdleConns(n)
+	var conns []*sql.Conn
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+	for range n {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		conns = append(conns, conn)
+	}
+	return nil
+}
+
+// slowRequest is a handler that holds its connection too long: it opens a
+// transaction, reads, and does slow work (a call to another service, say)
+// before it commits.
+func slowRequest(ctx context.Context, db *sql.DB, hold, timeout time.Duration) error {
+	ctx, cancel := withClockTimeout(ctx, timeout) // context.WithTimeout, on the demo clock
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil) // waits here while the pool is full
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var one int
+	if err := tx.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return err
+	}
+	demo.Clock.Sleep(hold) // the slow work, connection still held
+	return tx.Commit()
+}
+
+// withClockTimeout is context.WithTimeout with the deadline on demo.Clock,
+// so the load test gives the same results when the course runs --fast.
+func withClockTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-demo.Clock.After(d):
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// openPool opens an in-memory SQLite pool of at most maxOpen connections.
+// Each connection is a database of its own, which the load test, reading
+// no tables, doesn't mind.
+func openPool(maxOpen int) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("this course needs SQLite, whose driver needs cgo (CGO_ENABLED=1): %w", err)
+	}
+	db.SetMaxOpenConns(maxOpen)
+	return db, nil
+}
+
+// ============ COURSE TWENTY-THREE MAIN FUNCTION ============
+func CourseTwentyThree(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 23)
+
+	l.Section("stats")
+	db, err := openPool(2)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := db.PingContext(ctx); err != nil {
+		return err
+	}
+	s := db.Stats()
+	l.Printf("After Ping: %d open, %d in use, %d idle (max open %d)\n", s.OpenConnections, s.InUse, s.Idle, s.MaxOpenConnections)
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	s = db.Stats()
+	l.Printf("Holding a Conn: %d open, %d in use, %d idle\n", s.OpenConnections, s.InUse, s.Idle)
+	conn.Close()
+	l.Resume()
+
+	l.Section("exhaustion")
+	test := loadTest{Requests: 20, Hold: 20 * time.Millisecond, Timeout: 50 * time.Millisecond}
+	l.Printf("%d requests at once, each holding a connection %v, with a %v timeout:\n", test.Requests, test.Hold, test.Timeout)
+	if err := warmUp(ctx, db); err != nil {
+		return err
+	}
+	metrics := NewPoolMetrics(db, "users")
+	sampling, stopSampling := context.WithCancel(ctx)
+	sampled := make(chan struct{})
+	go func() {
+		metrics.Run(sampling, 5*time.Millisecond)
+		close(sampled)
+	}()
+	done := make(chan loadResult)
+	go func() {
+		result, _ := test.Run(ctx, db) // warmed up already
+		done <- result
+	}()
+
+	// Prometheus scrapes 10ms in, and again once it is over
+	demo.Clock.Sleep(10 * time.Millisecond)
+	metrics.Sample()
+	var during, after strings.Builder
+	metrics.WriteTo(&during)
+	result := <-done
+	stopSampling()
+	<-sampled
+	metrics.Sample()
+	metrics.WriteTo(&after)
+
+	l.Printf("  MaxOpenConns 2: %d ok, %d timed out, %d waited for a connection\n",
+		result.OK, result.TimedOut, result.Stats.WaitCount)
+	l.Resume()
+
+	l.Section("metrics")
+	l.Print(during.String())
+	l.Resume()
+
+	// The idle and open counts afterwards depend on which requests were
+	// cancelled mid-transaction, so only these two are shown
+	for line := range strings.Lines(after.String()) {
+		if strings.HasPrefix(line, "go_sql_in_use_connections") {
+			l.Print(line)
+		}
+	}
+	l.Resume()
+
+	l.Section("tuning")
+	for _, maxOpen := range []int{5, 10, 20} {
+		tuned, err := openPool(maxOpen)
+		if err != nil {
+			return err
+		}
+		result, err := test.Run(ctx, tuned)
+		tuned.Close()
+		if err != nil {
+			return err
+		}
+		l.Printf("  MaxOpenConns %d: %d ok, %d timed out, %d waited for a connection\n",
+			maxOpen, result.OK, result.TimedOut, result.Stats.WaitCount)
+	}
+	l.Resume()
+
+	l.End()
+	return nil
+}
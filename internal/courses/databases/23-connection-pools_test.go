@@ -0,0 +1,193 @@
+//go:build cgo
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Run with: go test -run Pool ./internal/courses/databases
+// The pools are in-memory SQLite; the load tests take a fraction of a
+// second of real time.
+
+func TestWritePoolMetrics(t *testing.T) {
+	s := sql.DBStats{
+		MaxOpenConnections: 10, OpenConnections: 7, InUse: 4, Idle: 3,
+		WaitCount: 12, WaitDuration: 1500 * time.Millisecond, MaxIdleClosed: 5,
+	}
+	var out strings.Builder
+	if _, err := writePoolMetrics(&out, "users", s, 9); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`go_sql_max_open_connections{db_name="users"} 10`,
+		`go_sql_open_connections{db_name="users"} 7`,
+		`go_sql_in_use_connections{db_name="users"} 4`,
+		`go_sql_in_use_connections_peak{db_name="users"} 9`,
+		`go_sql_idle_connections{db_name="users"} 3`,
+		`go_sql_wait_count_total{db_name="users"} 12`,
+		`go_sql_wait_duration_seconds_total{db_name="users"} 1.5`,
+		`go_sql_max_idle_closed_total{db_name="users"} 5`,
+		`go_sql_max_lifetime_closed_total{db_name="users"} 0`,
+		"# TYPE go_sql_wait_count_total counter",
+		"# TYPE go_sql_in_use_connections gauge",
+	} {
+		if !strings.Contains(out.String(), want+"\n") {
+			t.Errorf("missing %q", want)
+		}
+	}
+
+	// Every line is a comment or a sample Prometheus can parse
+	sample := regexp.MustCompile(`^[a-z_]+\{db_name="[^"]*"\} [0-9.e+-]+$`)
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		if !strings.HasPrefix(line, "# HELP ") && !strings.HasPrefix(line, "# TYPE ") && !sample.MatchString(line) {
+			t.Errorf("not in the exposition format: %q", line)
+		}
+	}
+}
+
+func scrape(t *testing.T, url string) string {
+	t.Helper()
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	return string(body)
+}
+
+// The peak survives until a scrape has reported it, even when the pool is
+// quiet again by the time of the scrape.
+func TestPoolMetricsPeak(t *testing.T) {
+	db, err := openPool(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	metrics := NewPoolMetrics(db, "users")
+	server := httptest.NewServer(metrics)
+	defer server.Close()
+
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for range 3 {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+	if s := metrics.Sample(); s.InUse != 3 {
+		t.Fatalf("InUse = %d with three Conns held", s.InUse)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+	metrics.Sample()
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"first scrape", []string{`go_sql_in_use_connections{db_name="users"} 0`, `go_sql_in_use_connections_peak{db_name="users"} 3`}},
+		{"second scrape", []string{`go_sql_in_use_connections_peak{db_name="users"} 0`, `go_sql_idle_connections{db_name="users"} 2`}},
+	}
+	for _, tt := range tests {
+		body := scrape(t, server.URL)
+		for _, want := range tt.want {
+			if !strings.Contains(body, want+"\n") {
+				t.Errorf("%s: missing %q in\n%s", tt.name, want, body)
+			}
+		}
+	}
+}
+
+func TestPoolMetricsRun(t *testing.T) {
+	db, err := openPool(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	metrics := NewPoolMetrics(db, "users")
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		metrics.Run(ctx, time.Millisecond)
+		close(stopped)
+	}()
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Run samples on its own: the peak shows up without calling Sample
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(time.Millisecond) {
+		var out strings.Builder
+		metrics.WriteTo(&out)
+		if strings.Contains(out.String(), `go_sql_in_use_connections_peak{db_name="users"} 1`) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Run never sampled the held connection")
+		}
+	}
+	conn.Close()
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestLoadTestExhaustion(t *testing.T) {
+	test := loadTest{Requests: 20, Hold: 50 * time.Millisecond, Timeout: 200 * time.Millisecond}
+	tests := []struct {
+		maxOpen     int
+		wantTimeout bool
+	}{
+		// 20 requests of 50ms on 2 connections need 500ms: most time out
+		{2, true},
+		// One connection each: nobody waits
+		{20, false},
+	}
+	for _, tt := range tests {
+		db, err := openPool(tt.maxOpen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		got, err := test.Run(context.Background(), db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.OK+got.TimedOut != test.Requests {
+			t.Errorf("MaxOpenConns %d: %d ok + %d timed out, want %d requests", tt.maxOpen, got.OK, got.TimedOut, test.Requests)
+		}
+		if tt.wantTimeout {
+			if got.TimedOut == 0 || got.Stats.WaitCount == 0 || got.Stats.WaitDuration == 0 {
+				t.Errorf("MaxOpenConns %d: %+v, want timeouts and waits", tt.maxOpen, got)
+			}
+		} else if got.TimedOut != 0 || got.Stats.WaitCount != 0 {
+			t.Errorf("MaxOpenConns %d: %+v, want no timeouts or waits", tt.maxOpen, got)
+		}
+		if got.Stats.MaxOpenConnections != tt.maxOpen {
+			t.Errorf("MaxOpenConnections = %d, want %d", got.Stats.MaxOpenConnections, tt.maxOpen)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 24: BULK INSERTS - LOADING MANY ROWS FAST
+// Topics covered:
+// 1. One INSERT per row, each in a transaction of its own
+// 2. Multi-row INSERT ... VALUES, in chunks under the parameter limit
+// 3. One transaction, with the statement prepared once for all the rows
+// 4. Choosing how many rows go in a statement
+// 5. COPY, PostgreSQL's bulk load (go run -tags postgres ./cmd/learn 24)
+//
+// The database is a SQLite file in a temporary directory rather than one in
+// memory: committing to a file costs what it costs on a real server, and
+// that cost is the lesson. SQLite's driver needs cgo.
+
+// bulkRows is how many users each strategy inserts.
+const bulkRows = 10_000
+
+// bulkUsers makes n users with distinct emails.
+func bulkUsers(n int) []DBUser {
+	users := make([]DBUser, n)
+	for i := range users {
+		users[i] = DBUser{
+			Name:  fmt.Sprintf("User %05d", i),
+			Email: fmt.Sprintf("user%05d@example.com", i),
+			Age:   18 + i%60,
+		}
+	}
+	return users
+}
+
+// ============ 1. ONE BY ONE ============
+
+// insertOneByOne runs one INSERT per user. With no transaction around it,
+// each INSERT is a transaction of its own, and each commit waits for the
+// database to write its log to disk.
+func insertOneByOne(ctx context.Context, db *sql.DB, users []DBUser) error {
+	for _, u := range users {
+		if _, err := db.ExecContext(ctx, `INSERT INTO users (name, email, age) VALUES (?, ?, ?)`, u.Name, u.Email, u.Age); err != nil {
+			return fmt.Errorf("insert %s: %w", u.Email, err)
+		}
+	}
+	return nil
+}
+
+// ============ 2. MULTI-ROW VALUES ============
+
+// sqliteMaxParams is the most ? a SQLite statement may have
+// (SQLITE_MAX_VARIABLE_NUMBER; 999 before SQLite 3.32). PostgreSQL's limit
+// is 65535 and MySQL's 65535 too.
+const sqliteMaxParams = 32766
+
+// insertMultiRow inserts rowsPerStatement users per statement:
+//
+//	INSERT INTO users (name, email, age) VALUES (?, ?, ?), (?, ?, ?), ...
+//
+// One round trip and one commit per chunk instead of per row. The chunks
+// are separate statements: if one fails, the chunks before it stay.
+func insertMultiRow(ctx context.Context, db *sql.DB, users []DBUser, rowsPerStatement int) error {
+	const columns = 3
+	if rowsPerStatement < 1 || rowsPerStatement*columns > sqliteMaxParams {
+		return fmt.Errorf("%d rows per statement: need 1 to %d", rowsPerStatement, sqliteMaxParams/columns)
+	}
+	for chunk := range slices.Chunk(users, rowsPerStatement) {
+		query := `INSERT INTO users (name, email, age) VALUES ` + valuesList(len(chunk), columns)
+		args := make([]any, 0, len(chunk)*columns)
+		for _, u := range chunk {
+			args = append(args, u.Name, u.Email, u.Age)
+		}
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("insert %s to %s: %w", chunk[0].Email, chunk[len(chunk)-1].Email, err)
+		}
+	}
+	return nil
+}
+
+// valuesList returns rows groups of columns placeholders: "(?, ?), (?, ?)"
+// for 2 and 2. PostgreSQL numbers them instead: ($1, $2), ($3, $4).
+func valuesList(rows, columns int) string {
+	row := "(" + strings.Repeat("?, ", columns-1) + "?)"
+	var b strings.Builder
+	for i := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(row)
+	}
+	return b.String()
+}
+
+// ============ 3. ONE TRANSACTION ============
+
+// insertInTx is course 7's InsertUsers: one transaction, one prepared
+// statement executed per user, and a single commit at the end. All the
+// users are stored, or none.
+func insertInTx(ctx context.Context, db *sql.DB, users []DBUser) error {
+	_, err := (&SQLDatabase{conn: db}).InsertUsers(ctx, users...)
+	return err
+}
+
+// ============ 4. TIMING THEM ============
+
+// timeInsert empties the users table, runs insert and checks every user
+// arrived. It times with the wall clock, not demo.Clock: the point is how
+// long the database really takes.
+func timeInsert(ctx context.Context, db *sql.DB, users []DBUser, insert func(context.Context, *sql.DB, []DBUser) error) (time.Duration, error) {
+	if _, err := db.ExecContext(ctx, `DELETE FROM users`); err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	if err := insert(ctx, db, users); err != nil {
+		return 0, err
+	}
+	took := time.Since(start)
+
+	n, err := (&SQLDatabase{conn: db}).CountUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if n != len(users) {
+		return 0, fmt.Errorf("inserted %d users, want %d", n, len(users))
+	}
+	return took, nil
+}
+
+// timing formats n rows in d as "10000 rows in 12ms (833333 rows/s)".
+func timing(n int, d time.Duration) string {
+	return fmt.Sprintf("%d rows in %v (%.0f rows/s)", n, d.Round(time.Millisecond/10), float64(n)/d.Seconds())
+}
+
+// openBulkDB creates a users table in a new SQLite file in dir. WAL with
+// synchronous=NORMAL is the usual setting for SQLite in a service: a
+// commit appends to the log without waiting for it to reach the disk, but
+// still costs far more than a row.
+func openBulkDB(ctx context.Context, dir string) (*sql.DB, error) {
+	dsn := filepath.Join(dir, "bulk.db") + "?_journal_mode=WAL&_synchronous=NORMAL"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("this course needs SQLite, whose driver needs cgo (CGO_ENABLED=1): %w", err)
+	}
+	// SQLite has one writer at a time; one connection avoids "database is locked"
+	db.SetMaxOpenConns(1)
+	if err := (&SQLDatabase{conn: db}).CreateTable(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// ============ 5. COPY ============
+
+// copyUsers loads users with PostgreSQL's COPY, the way lib/pq does it
+// through database/sql (pq.CopyIn("users", "name", "email", "age") builds
+// the same statement). Each Exec with arguments queues a row; the Exec
+// without any sends what is left, and Commit ends the load. Rows go to
+// the server as one stream, with no statement to parse or plan per row.
+func copyUsers(ctx context.Context, db *sql.DB, users []DBUser) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `COPY users (name, email, age) FROM STDIN`)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		if _, err := stmt.ExecContext(ctx, u.Name, u.Email, u.Age); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// copyDemo is set by postgres_copy.go, which is only built with
+// -tags postgres: it needs a PostgreSQL server and the lib/pq driver.
+var copyDemo func(ctx context.Context, w io.Writer, users []DBUser) error
+
+// ============ COURSE TWENTY-FOUR MAIN FUNCTION ============
+func CourseTwentyFour(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 24)
+
+	dir, err := os.MkdirTemp("", "learn-bulk-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	db, err := openBulkDB(ctx, dir)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	users := bulkUsers(bulkRows)
+
+	l.Section("one-by-one")
+	took, err := timeInsert(ctx, db, users, insertOneByOne)
+	if err != nil {
+		return err
+	}
+	l.Printf("One by one: %s\n", timing(len(users), took))
+	l.Resume()
+
+	l.Section("multi-row")
+	took, err = timeInsert(ctx, db, users, func(ctx context.Context, db *sql.DB, users []DBUser) error {
+		return insertMultiRow(ctx, db, users, 500)
+	})
+	if err != nil {
+		return err
+	}
+	l.Printf("500 rows per INSERT: %s\n", timing(len(users), took))
+	l.Println("Too many placeholders:", insertMultiRow(ctx, db, users, 20_000))
+	l.Resume()
+
+	l.Section("transaction")
+	took, err = timeInsert(ctx, db, users, insertInTx)
+	if err != nil {
+		return err
+	}
+	l.Printf("One transaction: %s\n", timing(len(users), took))
+	l.Resume()
+
+	l.Section("batch-size")
+	for _, rows := range []int{1, 10, 100, 1000, 10_000} {
+		took, err := timeInsert(ctx, db, users, func(ctx context.Context, db *sql.DB, users []DBUser) error {
+			return insertMultiRow(ctx, db, users, rows)
+		})
+		if err != nil {
+			return err
+		}
+		l.Printf("  %5d rows per INSERT: %s\n", rows, timing(len(users), took))
+	}
+	l.Resume()
+
+	l.Section("copy")
+	if copyDemo == nil {
+		l.Println("COPY needs PostgreSQL; run: go run -tags postgres ./cmd/learn 24")
+	} else if err := copyDemo(ctx, l.Printer, users); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.End()
+	return nil
+}
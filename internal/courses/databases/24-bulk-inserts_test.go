@@ -0,0 +1,124 @@
+//go:build cgo
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Run with: go test -run Bulk ./internal/courses/databases
+// Each strategy loads into a SQLite file in the test's temporary directory.
+
+func TestBulkInserts(t *testing.T) {
+	ctx := context.Background()
+	db, err := openBulkDB(ctx, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	users := bulkUsers(2500)
+
+	type strategy struct {
+		name   string
+		insert func(context.Context, *sql.DB, []DBUser) error
+	}
+	tests := []strategy{
+		{"one by one", insertOneByOne},
+		{"one transaction", insertInTx},
+	}
+	// Chunks of one row, uneven chunks, and a single chunk
+	for _, rows := range []int{1, 7, 500, 2500} {
+		tests = append(tests, strategy{fmt.Sprintf("%d rows per INSERT", rows), func(ctx context.Context, db *sql.DB, users []DBUser) error {
+			return insertMultiRow(ctx, db, users, rows)
+		}})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// timeInsert empties the table and fails unless every row arrived
+			if _, err := timeInsert(ctx, db, users, tt.insert); err != nil {
+				t.Fatal(err)
+			}
+			var name string
+			var age int
+			if err := db.QueryRowContext(ctx, `SELECT name, age FROM users WHERE email = ?`, "user02499@example.com").Scan(&name, &age); err != nil {
+				t.Fatal(err)
+			}
+			if name != "User 02499" || age != 18+2499%60 {
+				t.Errorf("last user = %q, %d", name, age)
+			}
+		})
+	}
+}
+
+func TestInsertMultiRowLimit(t *testing.T) {
+	ctx := context.Background()
+	db, err := openBulkDB(ctx, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// 10923 rows of 3 columns need 32769 placeholders, past SQLite's 32766
+	for _, rows := range []int{0, 10_923} {
+		if err := insertMultiRow(ctx, db, bulkUsers(3), rows); err == nil {
+			t.Errorf("%d rows per statement: no error", rows)
+		}
+	}
+	// The most that fit, in a full chunk and a chunk of one
+	users := bulkUsers(10_923)
+	if _, err := timeInsert(ctx, db, users, func(ctx context.Context, db *sql.DB, users []DBUser) error {
+		return insertMultiRow(ctx, db, users, 10_922)
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+// A duplicate email fails its chunk; the chunks before it stay.
+func TestInsertMultiRowPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	db, err := openBulkDB(ctx, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	users := bulkUsers(10)
+	users[7].Email = users[0].Email
+	if err := insertMultiRow(ctx, db, users, 5); err == nil || !strings.Contains(err.Error(), "user00005@example.com to user00009@example.com") {
+		t.Errorf("err = %v, want the second chunk to fail", err)
+	}
+	if n, err := (&SQLDatabase{conn: db}).CountUsers(ctx); err != nil || n != 5 {
+		t.Errorf("CountUsers = %d, %v; want the first chunk's 5", n, err)
+	}
+
+	// In one transaction, the same rows are all or nothing
+	if _, err := db.ExecContext(ctx, `DELETE FROM users`); err != nil {
+		t.Fatal(err)
+	}
+	if err := insertInTx(ctx, db, users); err == nil {
+		t.Error("insertInTx: no error for a duplicate email")
+	}
+	if n, err := (&SQLDatabase{conn: db}).CountUsers(ctx); err != nil || n != 0 {
+		t.Errorf("CountUsers = %d, %v; want 0 after the rollback", n, err)
+	}
+}
+
+func TestValuesList(t *testing.T) {
+	tests := []struct {
+		rows, columns int
+		want          string
+	}{
+		{1, 1, "(?)"},
+		{1, 3, "(?, ?, ?)"},
+		{2, 2, "(?, ?), (?, ?)"},
+	}
+	for _, tt := range tests {
+		if got := valuesList(tt.rows, tt.columns); got != tt.want {
+			t.Errorf("valuesList(%d, %d) = %q, want %q", tt.rows, tt.columns, got, tt.want)
+		}
+	}
+}
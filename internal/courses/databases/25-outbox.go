@@ -0,0 +1,417 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/patterns"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/redis/go-redis/v9"
+)
+
+// COURSE 25: TRANSACTIONAL OUTBOX - EVENTS THAT MATCH THE DATABASE
+// Topics covered:
+// 1. The dual write: saving a row and publishing an event, and what a
+//    failure between the two does
+// 2. The outbox table, written in the same transaction as the row
+// 3. A relay that publishes the outbox and marks what it sent
+// 4. Crashes and outages: nothing is lost, some things arrive twice
+// 5. Idempotent consumers, which make twice harmless
+//
+// The database is SQLite in memory (course 7's users table), so SQLite's
+// driver and cgo are needed. Events go to course 12's Subject, an
+// in-process bus; redisPublisher sends them to Redis instead (course 9).
+
+// ============ 1. EVENTS ============
+
+// outboxEvent is something that happened, to be published. ID is the
+// outbox row's, so it is unique and grows in the order events were
+// written: consumers use it to spot events they have seen.
+type outboxEvent struct {
+	ID      int64
+	Topic   string // e.g. "user.created"
+	Payload string // JSON
+}
+
+// String is the event as a bus message: "7 user.created {...}".
+func (e outboxEvent) String() string {
+	return fmt.Sprintf("%d %s %s", e.ID, e.Topic, e.Payload)
+}
+
+// parseEvent reads a message String wrote.
+func parseEvent(message string) (outboxEvent, error) {
+	id, rest, _ := strings.Cut(message, " ")
+	topic, payload, ok := strings.Cut(rest, " ")
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || !ok {
+		return outboxEvent{}, fmt.Errorf("not an event: %q", message)
+	}
+	return outboxEvent{ID: n, Topic: topic, Payload: payload}, nil
+}
+
+// publisher sends events to whoever listens. Publish returning nil means
+// the broker has the event; an error means it may or may not.
+type publisher interface {
+	Publish(ctx context.Context, e outboxEvent) error
+}
+
+// busPublisher publishes to course 12's Subject, in this process.
+type busPublisher struct {
+	bus *patterns.Subject
+}
+
+func (p busPublisher) Publish(ctx context.Context, e outboxEvent) error {
+	p.bus.Notify(e.String())
+	return nil
+}
+
+// redisPublisher publishes with Redis' PUBLISH, to the channel named after
+// the topic, for consumers in other processes.
+type redisPublisher struct {
+	client *redis.Client
+}
+
+func (p redisPublisher) Publish(ctx context.Context, e outboxEvent) error {
+	return p.client.Publish(ctx, e.Topic, e.String()).Err()
+}
+
+// brokerDown is a broker that can't be reached.
+type brokerDown struct{}
+
+var errBrokerDown = errors.New("dial tcp 127.0.0.1:6379: connect: connection refused")
+
+func (brokerDown) Publish(context.Context, outboxEvent) error { return errBrokerDown }
+
+// lostAck publishes, then fails as if the broker's reply never came back.
+// The sender can't tell this from a failure, so it must send again.
+type lostAck struct {
+	publisher
+}
+
+var errLostAck = errors.New("read tcp 127.0.0.1:6379: i/o timeout")
+
+func (p lostAck) Publish(ctx context.Context, e outboxEvent) error {
+	if err := p.publisher.Publish(ctx, e); err != nil {
+		return err
+	}
+	return errLostAck
+}
+
+// ============ 2. THE DUAL WRITE ============
+
+// createUserThenPublish is the obvious way, and it is wrong: the insert
+// and the publish are two writes to two systems, and nothing makes both
+// happen. If publishing fails, or the process dies in between, the user
+// exists and the event is gone. Publishing first is no better: then a
+// failed insert leaves an event about a user who doesn't exist.
+func createUserThenPublish(ctx context.Context, db *sql.DB, pub publisher, u DBUser) (int64, error) {
+	result, err := db.ExecContext(ctx, `INSERT INTO users (name, email, age) VALUES (?, ?, ?)`, u.Name, u.Email, u.Age)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	u.ID = int(id)
+	payload, err := json.Marshal(u)
+	if err != nil {
+		return 0, err
+	}
+	// The user is committed; if this fails, the event is lost
+	if err := pub.Publish(ctx, outboxEvent{Topic: "user.created", Payload: string(payload)}); err != nil {
+		return id, fmt.Errorf("user %d saved, event lost: %w", id, err)
+	}
+	return id, nil
+}
+
+// ============ 3. THE OUTBOX ============
+
+// createOutbox makes the outbox table next to users. published_at is
+// NULL until the relay has published the event.
+func createOutbox(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		topic TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		published_at TIMESTAMP
+	)`)
+	return err
+}
+
+// createUserWithEvent inserts the user and its user.created event in one
+// transaction: both are saved, or neither. Nothing is published yet; the
+// relay does that.
+func createUserWithEvent(ctx context.Context, db *sql.DB, u DBUser) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO users (name, email, age) VALUES (?, ?, ?)`, u.Name, u.Email, u.Age)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	u.ID = int(id)
+	if err := addEvent(ctx, tx, "user.created", u); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// addEvent writes an event to the outbox, in the caller's transaction.
+func addEvent(ctx context.Context, tx *sql.Tx, topic string, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO outbox (topic, payload) VALUES (?, ?)`, topic, payload)
+	return err
+}
+
+// pendingEvents counts the events not published yet.
+func pendingEvents(ctx context.Context, db *sql.DB) (int, error) {
+	var n int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM outbox WHERE published_at IS NULL`).Scan(&n)
+	return n, err
+}
+
+// ============ 4. THE RELAY ============
+
+// Relay publishes the outbox: it reads the events not published yet, in
+// the order they were written, publishes each and marks it published.
+// Only one relay should run per outbox, or events arrive out of order.
+type Relay struct {
+	db    *sql.DB
+	pub   publisher
+	batch int // events per RunOnce
+}
+
+func NewRelay(db *sql.DB, pub publisher) *Relay {
+	return &Relay{db: db, pub: pub, batch: 100}
+}
+
+// RunOnce publishes up to a batch of events and returns how many. It stops
+// at the first that fails to publish, so the events after it wait and
+// keep their order; the next RunOnce tries it again.
+//
+// If the relay dies after publishing an event and before marking it, the
+// event is published again: delivery is at least once.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, topic, payload FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT ?`, r.batch)
+	if err != nil {
+		return 0, err
+	}
+	var events []outboxEvent
+	for rows.Next() {
+		var e outboxEvent
+		if err := rows.Scan(&e.ID, &e.Topic, &e.Payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for i, e := range events {
+		if err := r.pub.Publish(ctx, e); err != nil {
+			return i, fmt.Errorf("publish event %d: %w", e.ID, err)
+		}
+		if _, err := r.db.ExecContext(ctx, `UPDATE outbox SET published_at = CURRENT_TIMESTAMP WHERE id = ?`, e.ID); err != nil {
+			return i, fmt.Errorf("mark event %d published: %w", e.ID, err)
+		}
+	}
+	return len(events), nil
+}
+
+// Run calls RunOnce every interval until ctx is done. A failure is passed
+// to onError, if it isn't nil, and tried again next time: the events are
+// safe in the outbox meanwhile.
+func (r *Relay) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	for {
+		if _, err := r.RunOnce(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-demo.Clock.After(interval):
+		}
+	}
+}
+
+// ============ 5. IDEMPOTENT CONSUMERS ============
+
+// consumer is an Observer of the bus that handles each event once, however
+// often it arrives, by remembering the IDs it has handled. A consumer
+// writing to its own database would store them there, in the transaction
+// that handles the event.
+type consumer struct {
+	handle func(outboxEvent)
+
+	mu         sync.Mutex
+	seen       map[int64]bool
+	duplicates int
+}
+
+func newConsumer(handle func(outboxEvent)) *consumer {
+	return &consumer{handle: handle, seen: make(map[int64]bool)}
+}
+
+func (c *consumer) Update(message string) {
+	e, err := parseEvent(message)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	if c.seen[e.ID] {
+		c.duplicates++
+		c.mu.Unlock()
+		return
+	}
+	c.seen[e.ID] = true
+	c.mu.Unlock()
+	c.handle(e)
+}
+
+// Duplicates is how many events arrived again and were skipped.
+func (c *consumer) Duplicates() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.duplicates
+}
+
+// openOutboxDB opens an in-memory database with course 7's users table and
+// an outbox.
+func openOutboxDB(ctx context.Context) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("this course needs SQLite, whose driver needs cgo (CGO_ENABLED=1): %w", err)
+	}
+	// Every connection to ":memory:" is a separate, empty database
+	db.SetMaxOpenConns(1)
+	if err := (&SQLDatabase{conn: db}).CreateTable(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := createOutbox(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// ============ COURSE TWENTY-FIVE MAIN FUNCTION ============
+func CourseTwentyFive(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 25)
+
+	db, err := openOutboxDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	bus := patterns.NewSubject()
+	// The welcome-mail service: it sends one mail per new user
+	mails := 0
+	mailer := newConsumer(func(e outboxEvent) {
+		var u DBUser
+		json.Unmarshal([]byte(e.Payload), &u)
+		mails++
+		l.Printf("  mailer: event %d, welcome mail to %s\n", e.ID, u.Email)
+	})
+	bus.Subscribe(mailer)
+	users := &SQLDatabase{conn: db}
+
+	l.Section("dual-write")
+	_, err = createUserThenPublish(ctx, db, busPublisher{bus}, DBUser{Name: "Alice", Email: "alice@example.com", Age: 30})
+	l.Printf("Alice, broker up: %v\n", errOrOK(err))
+	_, err = createUserThenPublish(ctx, db, brokerDown{}, DBUser{Name: "Bob", Email: "bob@example.com", Age: 25})
+	l.Printf("Bob, broker down: %v\n", errOrOK(err))
+	n, err := users.CountUsers(ctx)
+	if err != nil {
+		return err
+	}
+	l.Printf("%d users saved, %d welcome mails sent. Bob's is lost for good.\n", n, mails)
+	l.Resume()
+
+	l.Section("outbox")
+	for _, u := range []DBUser{
+		{Name: "Carol", Email: "carol@example.com", Age: 35},
+		{Name: "Dave", Email: "dave@example.com", Age: 41},
+	} {
+		id, err := createUserWithEvent(ctx, db, u)
+		if err != nil {
+			return err
+		}
+		l.Printf("Created user %d, %s, and its event in one transaction\n", id, u.Name)
+	}
+	_, err = createUserWithEvent(ctx, db, DBUser{Name: "Carol again", Email: "carol@example.com"})
+	l.Printf("A second carol@example.com: %v\n", err)
+	pending, err := pendingEvents(ctx, db)
+	if err != nil {
+		return err
+	}
+	l.Printf("Events waiting in the outbox: %d (the failed insert left none)\n", pending)
+	l.Resume()
+
+	l.Section("relay")
+	relay := NewRelay(db, busPublisher{bus})
+	sent, err := relay.RunOnce(ctx)
+	if err != nil {
+		return err
+	}
+	l.Printf("Relay published %d events\n", sent)
+	if sent, err = relay.RunOnce(ctx); err != nil {
+		return err
+	}
+	l.Printf("Run again: %d events; marked events are not sent again\n", sent)
+	l.Resume()
+
+	l.Section("crashes")
+	l.Println("The broker goes down; Erin signs up anyway:")
+	if _, err := createUserWithEvent(ctx, db, DBUser{Name: "Erin", Email: "erin@example.com", Age: 28}); err != nil {
+		return err
+	}
+	_, err = NewRelay(db, brokerDown{}).RunOnce(ctx)
+	pending, _ = pendingEvents(ctx, db)
+	l.Printf("  relay: %v; %d event waiting\n", err, pending)
+	l.Println("The broker is back, but its reply to the relay is lost:")
+	_, err = NewRelay(db, lostAck{busPublisher{bus}}).RunOnce(ctx)
+	pending, _ = pendingEvents(ctx, db)
+	l.Printf("  relay: %v; %d event still waiting\n", err, pending)
+	l.Println("The relay restarts, and publishes what is not marked:")
+	if _, err := NewRelay(db, busPublisher{bus}).RunOnce(ctx); err != nil {
+		return err
+	}
+	pending, _ = pendingEvents(ctx, db)
+	l.Printf("  %d events waiting; %d welcome mails; the mailer skipped %d duplicate\n", pending, mails, mailer.Duplicates())
+	l.Resume()
+
+	l.End()
+	return nil
+}
+
+// errOrOK is err, or "ok" if there is none.
+func errOrOK(err error) any {
+	if err == nil {
+		return "ok"
+	}
+	return err
+}
@@ -0,0 +1,255 @@
+//go:build cgo
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/patterns"
+	"github.com/redis/go-redis/v9"
+)
+
+// Run with: go test -run Outbox ./internal/courses/databases
+// Each test has its own in-memory database. TestOutboxRedis needs a
+// server at REDIS_ADDR:
+//
+//	eval "$(go run ./cmd/learn env up redis)"
+
+// outboxFixture is a database with an outbox, a bus, and a consumer on it
+// that records the emails of the users it was told about.
+type outboxFixture struct {
+	db   *sql.DB
+	bus  *patterns.Subject
+	got  *consumer
+	mu   sync.Mutex
+	seen []string
+}
+
+func newOutbox(t *testing.T) *outboxFixture {
+	t.Helper()
+	db, err := openOutboxDB(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	f := &outboxFixture{db: db, bus: patterns.NewSubject()}
+	f.got = newConsumer(func(e outboxEvent) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.seen = append(f.seen, e.Payload)
+	})
+	f.bus.Subscribe(f.got)
+	return f
+}
+
+func (f *outboxFixture) create(t *testing.T, emails ...string) {
+	t.Helper()
+	for _, email := range emails {
+		if _, err := createUserWithEvent(context.Background(), f.db, DBUser{Name: email, Email: email}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// delivered returns the emails in the events the consumer handled, in
+// order.
+func (f *outboxFixture) delivered(t *testing.T) []string {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var emails []string
+	for _, payload := range f.seen {
+		var u DBUser
+		if err := json.Unmarshal([]byte(payload), &u); err != nil {
+			t.Fatal(err)
+		}
+		emails = append(emails, u.Email)
+	}
+	return emails
+}
+
+func (f *outboxFixture) pending(t *testing.T) int {
+	t.Helper()
+	n, err := pendingEvents(context.Background(), f.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestOutboxEventFormat(t *testing.T) {
+	e := outboxEvent{ID: 42, Topic: "user.created", Payload: `{"ID":1,"Name":"Alice Smith"}`}
+	got, err := parseEvent(e.String())
+	if err != nil || got != e {
+		t.Errorf("parseEvent(%q) = %+v, %v; want %+v", e.String(), got, err, e)
+	}
+	for _, bad := range []string{"", "hello", "x user.created {}", "7 user.created"} {
+		if _, err := parseEvent(bad); err == nil {
+			t.Errorf("parseEvent(%q): no error", bad)
+		}
+	}
+}
+
+func TestOutboxDualWriteLosesEvents(t *testing.T) {
+	f := newOutbox(t)
+	_, err := createUserThenPublish(context.Background(), f.db, brokerDown{}, DBUser{Name: "Bob", Email: "bob@example.com"})
+	if !errors.Is(err, errBrokerDown) {
+		t.Fatalf("err = %v, want the broker's error", err)
+	}
+	// The user is there, and nothing will ever publish the event
+	if n, _ := (&SQLDatabase{conn: f.db}).CountUsers(context.Background()); n != 1 || f.pending(t) != 0 {
+		t.Errorf("%d users, %d pending events; want 1 and 0", n, f.pending(t))
+	}
+}
+
+// A failed insert leaves no event, and a failed event no user.
+func TestOutboxAtomicWrite(t *testing.T) {
+	f := newOutbox(t)
+	f.create(t, "alice@example.com")
+	if _, err := createUserWithEvent(context.Background(), f.db, DBUser{Name: "Alice 2", Email: "alice@example.com"}); err == nil {
+		t.Fatal("duplicate email: no error")
+	}
+	if got := f.pending(t); got != 1 {
+		t.Errorf("%d pending events, want Alice's only", got)
+	}
+
+	// The event fails after the user is inserted: the user goes too
+	if _, err := f.db.Exec(`DROP TABLE outbox`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := createUserWithEvent(context.Background(), f.db, DBUser{Name: "Bob", Email: "bob@example.com"}); err == nil {
+		t.Fatal("no outbox table: no error")
+	}
+	var n int
+	if err := f.db.QueryRow(`SELECT COUNT(*) FROM users WHERE email = 'bob@example.com'`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("Bob saved without his event: %d, %v", n, err)
+	}
+}
+
+func TestOutboxRelay(t *testing.T) {
+	f := newOutbox(t)
+	f.create(t, "a@example.com", "b@example.com", "c@example.com")
+	relay := NewRelay(f.db, busPublisher{f.bus})
+	relay.batch = 2
+
+	for _, want := range []int{2, 1, 0} {
+		if n, err := relay.RunOnce(context.Background()); err != nil || n != want {
+			t.Errorf("RunOnce = %d, %v; want %d", n, err, want)
+		}
+	}
+	if got, want := f.delivered(t), []string{"a@example.com", "b@example.com", "c@example.com"}; !slices.Equal(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	if f.pending(t) != 0 || f.got.Duplicates() != 0 {
+		t.Errorf("%d pending, %d duplicates; want none", f.pending(t), f.got.Duplicates())
+	}
+}
+
+// While the broker is down, events wait in order; none is lost.
+func TestOutboxBrokerDown(t *testing.T) {
+	f := newOutbox(t)
+	f.create(t, "a@example.com", "b@example.com")
+	n, err := NewRelay(f.db, brokerDown{}).RunOnce(context.Background())
+	if n != 0 || !errors.Is(err, errBrokerDown) {
+		t.Errorf("RunOnce = %d, %v; want 0 and the broker's error", n, err)
+	}
+	f.create(t, "c@example.com")
+	if f.pending(t) != 3 {
+		t.Errorf("%d pending, want 3", f.pending(t))
+	}
+
+	if _, err := NewRelay(f.db, busPublisher{f.bus}).RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f.delivered(t), []string{"a@example.com", "b@example.com", "c@example.com"}; !slices.Equal(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+}
+
+// The broker gets the event, but the relay never hears so: the event is
+// sent again after a restart, and the consumer handles it once.
+func TestOutboxLostAck(t *testing.T) {
+	f := newOutbox(t)
+	f.create(t, "a@example.com", "b@example.com")
+	n, err := NewRelay(f.db, lostAck{busPublisher{f.bus}}).RunOnce(context.Background())
+	if n != 0 || !errors.Is(err, errLostAck) {
+		t.Errorf("RunOnce = %d, %v; want 0 and the timeout", n, err)
+	}
+	// a@ was delivered; b@ waits behind it
+	if got := f.delivered(t); !slices.Equal(got, []string{"a@example.com"}) || f.pending(t) != 2 {
+		t.Errorf("delivered %v with %d pending; want a@ and 2", got, f.pending(t))
+	}
+
+	if _, err := NewRelay(f.db, busPublisher{f.bus}).RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := f.delivered(t), []string{"a@example.com", "b@example.com"}; !slices.Equal(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	if f.got.Duplicates() != 1 {
+		t.Errorf("%d duplicates, want a@'s second delivery", f.got.Duplicates())
+	}
+}
+
+// Events written while no relay runs, as when the service died right after
+// committing, go out when one starts.
+func TestOutboxRelayRestart(t *testing.T) {
+	f := newOutbox(t)
+	f.create(t, "a@example.com", "b@example.com")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		NewRelay(f.db, busPublisher{f.bus}).Run(ctx, time.Millisecond, func(err error) { t.Error(err) })
+		close(stopped)
+	}()
+	for deadline := time.Now().Add(2 * time.Second); f.pending(t) > 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Run never published the outbox")
+		}
+	}
+	cancel()
+	<-stopped
+	if got := f.delivered(t); len(got) != 2 {
+		t.Errorf("delivered %v, want both", got)
+	}
+}
+
+func TestOutboxRedis(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set; see the comment at the top of this file")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub := client.Subscribe(ctx, "user.created")
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil { // the subscription's confirmation
+		t.Fatal(err)
+	}
+
+	f := newOutbox(t)
+	f.create(t, "a@example.com")
+	if _, err := NewRelay(f.db, redisPublisher{client}).RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := sub.ReceiveMessage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := parseEvent(msg.Payload)
+	if err != nil || e.Topic != "user.created" || e.ID != 1 {
+		t.Errorf("received %q: %+v, %v", msg.Payload, e, err)
+	}
+}
@@ -0,0 +1,472 @@
+package databases
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/pkg/querybuilder"
+)
+
+// COURSE 26: SOFT DELETE AND AUDIT COLUMNS - KEEPING WHAT CHANGED
+// Topics covered:
+// 1. created_at and updated_at, set on every write
+// 2. Soft delete: deleted_at instead of DELETE, and reads that skip it
+// 3. A unique email among the live users only, with a partial index
+// 4. Restoring a deleted user, and when that fails
+// 5. An audit log written by triggers, which no write can bypass
+// 6. Purging: deleting for real once rows are old enough
+//
+// The database is SQLite in memory, so SQLite's driver and cgo are needed.
+// The triggers are SQLite's; PostgreSQL and MySQL write them differently,
+// but the tables and queries are the same.
+
+// ============ 1. THE SCHEMA ============
+
+// createAuditedTable makes course 7's users table with three more columns.
+// deleted_at is NULL while the user is live. Email is unique among live
+// users only, by a partial index rather than a UNIQUE column, so a deleted
+// user's email can sign up again.
+func createAuditedTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT NOT NULL,
+		age INTEGER,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL,
+		deleted_at TIMESTAMP
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS users_live_email ON users (email) WHERE deleted_at IS NULL`)
+	return err
+}
+
+// createAuditLog makes the audit_log table and the triggers that fill it:
+// one row per insert, update, soft delete, restore and purge of a user,
+// with the user before and after as JSON. The database writes it, so a
+// change made outside the repository, from a console or another service,
+// is logged too.
+func createAuditLog(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		action TEXT NOT NULL,
+		before TEXT,
+		after TEXT,
+		at TIMESTAMP NOT NULL
+	);
+	CREATE TRIGGER IF NOT EXISTS users_audit_insert AFTER INSERT ON users
+	BEGIN
+		INSERT INTO audit_log (user_id, action, after, at)
+		VALUES (NEW.id, 'insert', json_object('name', NEW.name, 'email', NEW.email, 'age', NEW.age), NEW.updated_at);
+	END;
+	CREATE TRIGGER IF NOT EXISTS users_audit_update AFTER UPDATE ON users
+	BEGIN
+		INSERT INTO audit_log (user_id, action, before, after, at)
+		VALUES (NEW.id,
+			CASE
+				WHEN OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN 'delete'
+				WHEN OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN 'restore'
+				ELSE 'update'
+			END,
+			json_object('name', OLD.name, 'email', OLD.email, 'age', OLD.age),
+			json_object('name', NEW.name, 'email', NEW.email, 'age', NEW.age),
+			NEW.updated_at);
+	END;
+	CREATE TRIGGER IF NOT EXISTS users_audit_purge AFTER DELETE ON users
+	BEGIN
+		INSERT INTO audit_log (user_id, action, before, at)
+		VALUES (OLD.id, 'purge', json_object('name', OLD.name, 'email', OLD.email, 'age', OLD.age), CURRENT_TIMESTAMP);
+	END`)
+	return err
+}
+
+// ============ 2. THE REPOSITORY ============
+
+// AuditedUsers is a UserRepository (see users.go) that keeps every user it
+// ever had. Delete only sets deleted_at, and every read skips such rows,
+// so to the application a deleted user is gone, as with SQLUsers. The
+// rows stay for Record, Restore and the audit log until Purge.
+type AuditedUsers struct {
+	db  *sql.DB
+	now func() time.Time // replaced in tests
+}
+
+// UserRecord is a user with its audit columns. DeletedAt is nil while the
+// user is live.
+type UserRecord struct {
+	User
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// AuditEntry is one row of the audit log. Before and After are the user as
+// JSON, "" where there is none: an insert has no before, a purge no after.
+type AuditEntry struct {
+	Action string // insert, update, delete, restore or purge
+	Before string
+	After  string
+	At     time.Time
+}
+
+// NewAuditedUsers creates the users table, its index, the audit log and
+// its triggers, unless they exist.
+func NewAuditedUsers(ctx context.Context, db *sql.DB) (*AuditedUsers, error) {
+	if err := createAuditedTable(ctx, db); err != nil {
+		return nil, fmt.Errorf("create users table: %w", err)
+	}
+	if err := createAuditLog(ctx, db); err != nil {
+		return nil, fmt.Errorf("create audit log: %w", err)
+	}
+	return &AuditedUsers{db: db, now: time.Now}, nil
+}
+
+// liveUsers starts every query the application reads users with. Forgetting
+// the deleted_at condition in one of them brings deleted users back, which
+// is why it is written once, here.
+func liveUsers(columns string) *querybuilder.Builder {
+	return querybuilder.New().Select(columns).From("users").Where("deleted_at IS NULL")
+}
+
+func (s *AuditedUsers) Create(ctx context.Context, u User) (User, error) {
+	now := s.now().UTC()
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO users (name, email, age, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		u.Name, u.Email, u.Age, now, now)
+	if isUniqueViolation(err) {
+		return User{}, fmt.Errorf("create %s: %w", u.Email, ErrEmailTaken)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("create %s: %w", u.Email, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return User{}, fmt.Errorf("create %s: %w", u.Email, err)
+	}
+	u.ID = strconv.FormatInt(id, 10)
+	return u, nil
+}
+
+func (s *AuditedUsers) Get(ctx context.Context, id string) (User, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return User{}, fmt.Errorf("get user %q: %w", id, ErrUserNotFound)
+	}
+	query, args := liveUsers("name, email, age").Where("id = ?", n).Build()
+
+	u := User{ID: id}
+	err = s.db.QueryRowContext(ctx, query, args...).Scan(&u.Name, &u.Email, &u.Age)
+	if errors.Is(err, sql.ErrNoRows) {
+		return User{}, fmt.Errorf("get user %q: %w", id, ErrUserNotFound)
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("get user %q: %w", id, err)
+	}
+	return u, nil
+}
+
+func (s *AuditedUsers) Update(ctx context.Context, u User) error {
+	n, err := strconv.ParseInt(u.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrUserNotFound)
+	}
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE users SET name = ?, email = ?, age = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`,
+		u.Name, u.Email, u.Age, s.now().UTC(), n)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrEmailTaken)
+	}
+	return rowChanged(result, err, "update", u.ID)
+}
+
+// Delete marks the user deleted. Deleting a deleted user is
+// ErrUserNotFound, as it is for SQLUsers.
+func (s *AuditedUsers) Delete(ctx context.Context, id string) error {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("delete user %q: %w", id, ErrUserNotFound)
+	}
+	now := s.now().UTC()
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE users SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`, now, now, n)
+	return rowChanged(result, err, "delete", id)
+}
+
+func (s *AuditedUsers) List(ctx context.Context) ([]User, error) {
+	query, _ := liveUsers("id, name, email, age").OrderBy("email").Build()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var u User
+		var id int64
+		if err := rows.Scan(&id, &u.Name, &u.Email, &u.Age); err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		u.ID = strconv.FormatInt(id, 10)
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	return users, nil
+}
+
+// Record returns the user with its audit columns, deleted or not: it is
+// for support staff and admin pages, not for the application's reads.
+func (s *AuditedUsers) Record(ctx context.Context, id string) (UserRecord, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return UserRecord{}, fmt.Errorf("get record %q: %w", id, ErrUserNotFound)
+	}
+	r := UserRecord{User: User{ID: id}}
+	err = s.db.QueryRowContext(ctx,
+		`SELECT name, email, age, created_at, updated_at, deleted_at FROM users WHERE id = ?`, n,
+	).Scan(&r.Name, &r.Email, &r.Age, &r.CreatedAt, &r.UpdatedAt, &r.DeletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return UserRecord{}, fmt.Errorf("get record %q: %w", id, ErrUserNotFound)
+	}
+	if err != nil {
+		return UserRecord{}, fmt.Errorf("get record %q: %w", id, err)
+	}
+	return r, nil
+}
+
+// Restore undeletes a user. It fails with ErrUserNotFound unless the user
+// is deleted, and with ErrEmailTaken if someone signed up with the email
+// since.
+func (s *AuditedUsers) Restore(ctx context.Context, id string) error {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return fmt.Errorf("restore user %q: %w", id, ErrUserNotFound)
+	}
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE users SET deleted_at = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL`, s.now().UTC(), n)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("restore user %q: %w", id, ErrEmailTaken)
+	}
+	return rowChanged(result, err, "restore", id)
+}
+
+// Purge deletes the users deleted before cutoff for real, and returns how
+// many. Until then a user asking "undo" can have their account back; after
+// it, only the audit log remembers them.
+func (s *AuditedUsers) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM users WHERE deleted_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("purge users: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// History returns the audit log of a user, oldest first. It outlives the
+// user: a purged user's history ends with "purge".
+func (s *AuditedUsers) History(ctx context.Context, id string) ([]AuditEntry, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("history of %q: %w", id, ErrUserNotFound)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT action, COALESCE(before, ''), COALESCE(after, ''), at FROM audit_log WHERE user_id = ? ORDER BY id`, n)
+	if err != nil {
+		return nil, fmt.Errorf("history of %q: %w", id, err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.Action, &e.Before, &e.After, &e.At); err != nil {
+			return nil, fmt.Errorf("history of %q: %w", id, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("history of %q: %w", id, err)
+	}
+	return entries, nil
+}
+
+// ============ 3. A CLOCK FOR THE COURSE ============
+
+// manualClock is a clock that moves only when told to, so the course's
+// timestamps are the same on every run.
+type manualClock struct {
+	t time.Time
+}
+
+func (c *manualClock) now() time.Time          { return c.t }
+func (c *manualClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// openAuditedUsers opens an in-memory database with AuditedUsers on it,
+// on a manualClock.
+func openAuditedUsers(ctx context.Context) (*AuditedUsers, *manualClock, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, nil, fmt.Errorf("this course needs SQLite, whose driver needs cgo (CGO_ENABLED=1): %w", err)
+	}
+	// Every connection to ":memory:" is a separate, empty database
+	db.SetMaxOpenConns(1)
+	users, err := NewAuditedUsers(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	clock := &manualClock{t: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)}
+	users.now = clock.now
+	return users, clock, nil
+}
+
+// printRecord prints a user's audit columns.
+func printRecord(w io.Writer, r UserRecord) {
+	deleted := "live"
+	if r.DeletedAt != nil {
+		deleted = "deleted " + r.DeletedAt.Format(time.DateTime)
+	}
+	fmt.Fprintf(w, "  %s %-5s %-17s created %s, updated %s, %s\n", r.ID, r.Name, r.Email,
+		r.CreatedAt.Format(time.DateTime), r.UpdatedAt.Format(time.DateTime), deleted)
+}
+
+// printUsers prints what List returns: the live users.
+func printUsers(ctx context.Context, w io.Writer, users UserRepository) error {
+	list, err := users.List(ctx)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, u := range list {
+		names = append(names, u.ID+" "+u.Name)
+	}
+	fmt.Fprintf(w, "  List: %s\n", strings.Join(names, ", "))
+	return nil
+}
+
+// ============ COURSE TWENTY-SIX MAIN FUNCTION ============
+func CourseTwentySix(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 26)
+
+	users, clock, err := openAuditedUsers(ctx)
+	if err != nil {
+		return err
+	}
+	defer users.db.Close()
+
+	l.Section("columns")
+	alice, err := users.Create(ctx, User{Name: "Alice", Email: "alice@example.com", Age: 30})
+	if err != nil {
+		return err
+	}
+	clock.advance(2 * time.Hour)
+	bob, err := users.Create(ctx, User{Name: "Bob", Email: "bob@example.com", Age: 25})
+	if err != nil {
+		return err
+	}
+	clock.advance(24 * time.Hour)
+	bob.Age = 26
+	if err := users.Update(ctx, bob); err != nil {
+		return err
+	}
+	l.Println("Alice signs up, Bob two hours later, and a day after that Bob has a birthday:")
+	for _, id := range []string{alice.ID, bob.ID} {
+		r, err := users.Record(ctx, id)
+		if err != nil {
+			return err
+		}
+		printRecord(l.Printer, r)
+	}
+	l.Resume()
+
+	l.Section("soft-delete")
+	clock.advance(24 * time.Hour)
+	if err := users.Delete(ctx, bob.ID); err != nil {
+		return err
+	}
+	l.Println("Bob deletes his account:")
+	_, err = users.Get(ctx, bob.ID)
+	l.Printf("  Get(%s): %v\n", bob.ID, err)
+	if err := printUsers(ctx, l.Printer, users); err != nil {
+		return err
+	}
+	r, err := users.Record(ctx, bob.ID)
+	if err != nil {
+		return err
+	}
+	l.Println("The row is still there:")
+	printRecord(l.Printer, r)
+	l.Println("Bob changes his mind the next day:")
+	clock.advance(24 * time.Hour)
+	l.Printf("  Restore(%s): %v\n", bob.ID, errOrOK(users.Restore(ctx, bob.ID)))
+	if err := printUsers(ctx, l.Printer, users); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.Section("unique")
+	clock.advance(24 * time.Hour)
+	if err := users.Delete(ctx, bob.ID); err != nil {
+		return err
+	}
+	l.Println("Bob deletes his account again, and someone signs up with his email:")
+	robert, err := users.Create(ctx, User{Name: "Robert", Email: "bob@example.com", Age: 52})
+	l.Printf("  Create(Robert, bob@example.com): user %s, %v\n", robert.ID, errOrOK(err))
+	_, err = users.Create(ctx, User{Name: "Rob", Email: "bob@example.com"})
+	l.Printf("  Create(Rob, bob@example.com): %v\n", err)
+	l.Printf("  Restore(%s): %v\n", bob.ID, users.Restore(ctx, bob.ID))
+	l.Resume()
+
+	l.Section("audit")
+	clock.advance(time.Hour)
+	// A fix made by hand, outside the repository
+	if _, err := users.db.ExecContext(ctx, `UPDATE users SET name = 'Alice Smith', updated_at = ? WHERE id = ?`, clock.now(), alice.ID); err != nil {
+		return err
+	}
+	for _, id := range []string{alice.ID, bob.ID} {
+		history, err := users.History(ctx, id)
+		if err != nil {
+			return err
+		}
+		l.Printf("Audit log of user %s:\n", id)
+		for _, e := range history {
+			l.Printf("  %s %-7s %s\n", e.At.Format(time.DateTime), e.Action, cmp.Or(e.After, e.Before))
+		}
+	}
+	l.Resume()
+
+	l.Section("purge")
+	clock.advance(30 * 24 * time.Hour)
+	purged, err := users.Purge(ctx, clock.now().Add(-30*24*time.Hour))
+	if err != nil {
+		return err
+	}
+	l.Printf("Purge of users deleted over 30 days ago: %d\n", purged)
+	_, err = users.Record(ctx, bob.ID)
+	l.Printf("  Record(%s): %v\n", bob.ID, err)
+	history, err := users.History(ctx, bob.ID)
+	if err != nil {
+		return err
+	}
+	var actions []string
+	for _, e := range history {
+		actions = append(actions, e.Action)
+	}
+	l.Printf("  audit log of user %s: %s\n", bob.ID, strings.Join(actions, ", "))
+	l.Resume()
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,203 @@
+//go:build cgo
+
+package databases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Run with: go test -run Audited ./internal/courses/databases
+// AuditedUsers keeps the UserRepository contract too; TestAuditedUsers in
+// users_sqlite_test.go runs it.
+
+func newAudited(t *testing.T) (*AuditedUsers, *manualClock) {
+	t.Helper()
+	users, clock, err := openAuditedUsers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { users.db.Close() })
+	return users, clock
+}
+
+func record(t *testing.T, users *AuditedUsers, id string) UserRecord {
+	t.Helper()
+	r, err := users.Record(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func actions(t *testing.T, users *AuditedUsers, id string) []string {
+	t.Helper()
+	history, err := users.History(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range history {
+		names = append(names, e.Action)
+	}
+	return names
+}
+
+func TestAuditedTimestamps(t *testing.T) {
+	ctx := context.Background()
+	users, clock := newAudited(t)
+	created := clock.now()
+	a, err := users.Create(ctx, User{Name: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := record(t, users, a.ID)
+	if !r.CreatedAt.Equal(created) || !r.UpdatedAt.Equal(created) || r.DeletedAt != nil {
+		t.Errorf("after Create: %+v; want created and updated at %v, not deleted", r, created)
+	}
+
+	clock.advance(time.Hour)
+	a.Age = 31
+	if err := users.Update(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	r = record(t, users, a.ID)
+	if !r.CreatedAt.Equal(created) || !r.UpdatedAt.Equal(clock.now()) {
+		t.Errorf("after Update: created %v, updated %v; want %v and %v", r.CreatedAt, r.UpdatedAt, created, clock.now())
+	}
+}
+
+func TestAuditedSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	users, clock := newAudited(t)
+	a, _ := users.Create(ctx, User{Name: "Alice", Email: "alice@example.com"})
+	b, _ := users.Create(ctx, User{Name: "Bob", Email: "bob@example.com"})
+
+	clock.advance(time.Hour)
+	if err := users.Delete(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.Get(ctx, b.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Get of a deleted user: err = %v, want ErrUserNotFound", err)
+	}
+	if err := users.Update(ctx, b); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Update of a deleted user: err = %v, want ErrUserNotFound", err)
+	}
+	if list, err := users.List(ctx); err != nil || len(list) != 1 || list[0].ID != a.ID {
+		t.Errorf("List = %v, %v; want Alice only", list, err)
+	}
+	// The row is still there
+	if r := record(t, users, b.ID); r.DeletedAt == nil || !r.DeletedAt.Equal(clock.now()) || r.Name != "Bob" {
+		t.Errorf("Record of a deleted user = %+v, want Bob deleted at %v", r, clock.now())
+	}
+}
+
+func TestAuditedRestore(t *testing.T) {
+	ctx := context.Background()
+	users, _ := newAudited(t)
+	b, _ := users.Create(ctx, User{Name: "Bob", Email: "bob@example.com"})
+
+	if err := users.Restore(ctx, b.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Restore of a live user: err = %v, want ErrUserNotFound", err)
+	}
+	users.Delete(ctx, b.ID)
+	if err := users.Restore(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := users.Get(ctx, b.ID); err != nil || got != b {
+		t.Errorf("Get after Restore = %+v, %v; want %+v", got, err, b)
+	}
+
+	// The email was taken while Bob was away
+	users.Delete(ctx, b.ID)
+	if _, err := users.Create(ctx, User{Name: "Robert", Email: "bob@example.com"}); err != nil {
+		t.Fatalf("Create with a deleted user's email: %v", err)
+	}
+	if err := users.Restore(ctx, b.ID); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("Restore with the email taken: err = %v, want ErrEmailTaken", err)
+	}
+	if r := record(t, users, b.ID); r.DeletedAt == nil {
+		t.Error("the failed Restore brought Bob back")
+	}
+}
+
+func TestAuditedPurge(t *testing.T) {
+	ctx := context.Background()
+	users, clock := newAudited(t)
+	old, _ := users.Create(ctx, User{Name: "Old", Email: "old@example.com"})
+	recent, _ := users.Create(ctx, User{Name: "Recent", Email: "recent@example.com"})
+	live, _ := users.Create(ctx, User{Name: "Live", Email: "live@example.com"})
+	users.Delete(ctx, old.ID)
+	clock.advance(48 * time.Hour)
+	users.Delete(ctx, recent.ID)
+
+	n, err := users.Purge(ctx, clock.now().Add(-24*time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v; want 1", n, err)
+	}
+	if _, err := users.Record(ctx, old.ID); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Record of a purged user: err = %v, want ErrUserNotFound", err)
+	}
+	for _, id := range []string{recent.ID, live.ID} {
+		record(t, users, id)
+	}
+	if got, want := actions(t, users, old.ID), []string{"insert", "delete", "purge"}; !slices.Equal(got, want) {
+		t.Errorf("history of a purged user = %v, want %v", got, want)
+	}
+}
+
+func TestAuditedHistory(t *testing.T) {
+	ctx := context.Background()
+	users, clock := newAudited(t)
+	a, _ := users.Create(ctx, User{Name: "Alice", Email: "alice@example.com", Age: 30})
+	clock.advance(time.Hour)
+	a.Age = 31
+	users.Update(ctx, a)
+	clock.advance(time.Hour)
+	users.Delete(ctx, a.ID)
+	clock.advance(time.Hour)
+	users.Restore(ctx, a.ID)
+
+	history, err := users.History(ctx, a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := clock.now().Add(-3 * time.Hour)
+	want := []AuditEntry{
+		{"insert", "", `{"name":"Alice","email":"alice@example.com","age":30}`, start},
+		{"update", `{"name":"Alice","email":"alice@example.com","age":30}`, `{"name":"Alice","email":"alice@example.com","age":31}`, start.Add(time.Hour)},
+		{"delete", `{"name":"Alice","email":"alice@example.com","age":31}`, `{"name":"Alice","email":"alice@example.com","age":31}`, start.Add(2 * time.Hour)},
+		{"restore", `{"name":"Alice","email":"alice@example.com","age":31}`, `{"name":"Alice","email":"alice@example.com","age":31}`, start.Add(3 * time.Hour)},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("history = %+v, want %d entries", history, len(want))
+	}
+	for i, e := range history {
+		if e.Action != want[i].Action || e.Before != want[i].Before || e.After != want[i].After || !e.At.Equal(want[i].At) {
+			t.Errorf("entry %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+}
+
+// The triggers log writes the repository never saw, and a write that fails
+// leaves no entry.
+func TestAuditedTriggers(t *testing.T) {
+	ctx := context.Background()
+	users, _ := newAudited(t)
+	a, _ := users.Create(ctx, User{Name: "Alice", Email: "alice@example.com"})
+	users.Create(ctx, User{Name: "Bob", Email: "bob@example.com"})
+
+	if _, err := users.db.ExecContext(ctx, `UPDATE users SET name = 'Alice Smith' WHERE id = ?`, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	a.Email = "bob@example.com"
+	if err := users.Update(ctx, a); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("Update to a taken email: err = %v", err)
+	}
+	if got, want := actions(t, users, a.ID), []string{"insert", "update"}; !slices.Equal(got, want) {
+		t.Errorf("history = %v, want %v", got, want)
+	}
+}
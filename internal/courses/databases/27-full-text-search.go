@@ -0,0 +1,407 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 27: FULL-TEXT SEARCH - FINDING WORDS, NOT SUBSTRINGS
+// Topics covered:
+// 1. What strings.Contains gets wrong: substrings, word forms, order, rank
+// 2. SQLite FTS5: an index of words, kept in sync by triggers
+// 3. The query language, and turning what users type into a safe query
+// 4. Ranking with bm25, and snippets of where the words are
+// 5. bleve, a search index in Go, with fuzzy matching
+// 6. Serving it: course 6's /search on a full-text index
+//
+// FTS5 is part of SQLite, but the go-sqlite3 driver only compiles it in
+// with a build tag:
+//
+//	go run -tags sqlite_fts5 ./cmd/learn 27
+//
+// Without it, the course says so and skips to the end.
+
+// ============ 1. THE CATALOGUE ============
+
+// product is what the shop sells, and what its customers search.
+type product struct {
+	ID          int64
+	Name        string
+	Description string
+}
+
+var catalogue = []product{
+	{1, "Trail running shoes", "Light shoes for running on rough trails, with a grippy sole."},
+	{2, "Road running shoes", "Cushioned shoes for long runs on the road."},
+	{3, "Wireless mouse", "A quiet mouse with a USB receiver and a two-year battery."},
+	{4, "Mechanical keyboard", "Tactile switches, a wireless mode and a USB-C cable."},
+	{5, "Shoehorn", "A long steel shoehorn, for putting on shoes without bending."},
+	{6, "Smart watch", "Tracks your runs, your heart rate and your sleep."},
+	{7, "Running socks", "Padded socks that keep feet dry on a run."},
+	{8, "Art print", "A framed print of a mountain trail at dawn."},
+	{9, "USB charger", "Charges a phone, a watch and a mouse at once."},
+	{10, "Trail map", "A waterproof map of the mountain trails."},
+}
+
+// containsSearch is how course 6's /search started: the products whose
+// name contains q, in any case. It finds substrings rather than words,
+// knows nothing of "run" and "running", and reads every product, every
+// time.
+func containsSearch(products []product, q string) []product {
+	var found []product
+	for _, p := range products {
+		if strings.Contains(strings.ToLower(p.Name), strings.ToLower(q)) {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// ============ 2. AN FTS5 INDEX ============
+
+// createProducts makes the products table and an FTS5 index of it.
+// products_fts is an external content table: it holds the index only, and
+// reads the text from products by rowid. The porter tokenizer stems words,
+// so "runs", "running" and "run" are one word to it. The triggers keep the
+// index in step with the table, in the same transaction as each write.
+func createProducts(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS products (
+		id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL
+	);
+	CREATE VIRTUAL TABLE IF NOT EXISTS products_fts USING fts5(
+		name, description,
+		content='products', content_rowid='id',
+		tokenize='porter unicode61'
+	);
+	CREATE TRIGGER IF NOT EXISTS products_fts_insert AFTER INSERT ON products
+	BEGIN
+		INSERT INTO products_fts (rowid, name, description) VALUES (NEW.id, NEW.name, NEW.description);
+	END;
+	CREATE TRIGGER IF NOT EXISTS products_fts_delete AFTER DELETE ON products
+	BEGIN
+		INSERT INTO products_fts (products_fts, rowid, name, description) VALUES ('delete', OLD.id, OLD.name, OLD.description);
+	END;
+	CREATE TRIGGER IF NOT EXISTS products_fts_update AFTER UPDATE ON products
+	BEGIN
+		INSERT INTO products_fts (products_fts, rowid, name, description) VALUES ('delete', OLD.id, OLD.name, OLD.description);
+		INSERT INTO products_fts (rowid, name, description) VALUES (NEW.id, NEW.name, NEW.description);
+	END`)
+	if err != nil && strings.Contains(err.Error(), "no such module: fts5") {
+		return errNoFTS5
+	}
+	return err
+}
+
+// errNoFTS5 is what createProducts returns when SQLite was built without
+// FTS5.
+var errNoFTS5 = errors.New("this SQLite has no FTS5: build with -tags sqlite_fts5")
+
+func addProducts(ctx context.Context, db *sql.DB, products ...product) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, p := range products {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO products (id, name, description) VALUES (?, ?, ?)`,
+			p.ID, p.Name, p.Description); err != nil {
+			return fmt.Errorf("add product %d: %w", p.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ============ 3. SEARCHING ============
+
+// productHit is a product that matched, with a snippet of the text around
+// the words that did, marked [like this].
+type productHit struct {
+	product
+	Snippet string
+}
+
+// searchProducts runs an FTS5 query, best match first. bm25 scores a match
+// higher the more often the query's words are in it and the rarer they are
+// in the rest; the weights count a word in the name ten times one in the
+// description. bm25 is lower for better matches, so the order is ascending.
+func searchProducts(ctx context.Context, db *sql.DB, query string, limit int) ([]productHit, error) {
+	rows, err := db.QueryContext(ctx, `
+	SELECT p.id, p.name, p.description, snippet(products_fts, 1, '[', ']', '...', 6)
+	FROM products_fts JOIN products p ON p.id = products_fts.rowid
+	WHERE products_fts MATCH ?
+	ORDER BY bm25(products_fts, 10.0, 1.0)
+	LIMIT ?`, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search %q: %w", query, err)
+	}
+	defer rows.Close()
+
+	var hits []productHit
+	for rows.Next() {
+		var h productHit
+		if err := rows.Scan(&h.ID, &h.Name, &h.Description, &h.Snippet); err != nil {
+			return nil, fmt.Errorf("search %q: %w", query, err)
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search %q: %w", query, err)
+	}
+	return hits, nil
+}
+
+// ftsQuery turns what a user typed into an FTS5 query that can't be a
+// syntax error: each word becomes a quoted string, so AND, NOT, quotes,
+// stars and colons mean nothing, and the last word is also a prefix, so
+// results come while the user is still typing. The words must all match,
+// in any order. An empty result means there is nothing to search for.
+func ftsQuery(input string) string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '\''
+	}) {
+		terms = append(terms, `"`+word+`"`)
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
+
+// ============ 4. BLEVE ============
+// In bleve_search.go.
+
+// ============ 5. SERVING IT ============
+
+// createUserIndex indexes users' names and emails for course 6's /search,
+// with the user's ID as the rowid. Unlike products_fts it keeps its own
+// copy of the text, because the users live in course 6's store, not in
+// SQLite.
+func createUserIndex(ctx context.Context, db *sql.DB, users []web.User) error {
+	if _, err := db.ExecContext(ctx, `CREATE VIRTUAL TABLE IF NOT EXISTS users_fts USING fts5(name, email, tokenize='porter unicode61')`); err != nil {
+		return err
+	}
+	for _, u := range users {
+		if _, err := db.ExecContext(ctx, `INSERT INTO users_fts (rowid, name, email) VALUES (?, ?, ?)`, u.ID, u.Name, u.Email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ftsUserSearch is a web.UserSearch on users_fts: it finds the IDs there,
+// best first, and the users in the store.
+func ftsUserSearch(db *sql.DB, store *web.RepositoryUserStore) web.UserSearch {
+	return func(ctx context.Context, q string) ([]web.User, error) {
+		query := ftsQuery(q)
+		if query == "" {
+			return store.List(), nil
+		}
+		rows, err := db.QueryContext(ctx, `SELECT rowid FROM users_fts WHERE users_fts MATCH ? ORDER BY rank LIMIT 20`, query)
+		if err != nil {
+			return nil, fmt.Errorf("search users: %w", err)
+		}
+		defer rows.Close()
+		var found []web.User
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				return nil, fmt.Errorf("search users: %w", err)
+			}
+			// A user deleted from the store since it was indexed is skipped
+			if u, ok := store.Get(id); ok {
+				found = append(found, u)
+			}
+		}
+		return found, rows.Err()
+	}
+}
+
+// openSearchDB opens an in-memory database with the catalogue in
+// products and products_fts.
+func openSearchDB(ctx context.Context) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("this course needs SQLite, whose driver needs cgo (CGO_ENABLED=1): %w", err)
+	}
+	// Every connection to ":memory:" is a separate, empty database
+	db.SetMaxOpenConns(1)
+	if err := createProducts(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := addProducts(ctx, db, catalogue...); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// printSearches runs each query and prints what it finds.
+func printSearches(ctx context.Context, w io.Writer, db *sql.DB, queries ...string) {
+	for _, q := range queries {
+		hits, err := searchProducts(ctx, db, q, 5)
+		printHits(w, q, hits, err)
+	}
+}
+
+// printHits prints search results, one per line.
+func printHits(w io.Writer, query string, hits []productHit, err error) {
+	fmt.Fprintf(w, "%s:\n", query)
+	if err != nil {
+		fmt.Fprintf(w, "  %v\n", err)
+		return
+	}
+	if len(hits) == 0 {
+		fmt.Fprintln(w, "  (nothing)")
+	}
+	for _, h := range hits {
+		fmt.Fprintf(w, "  %-2d %-20s %s\n", h.ID, h.Name, h.Snippet)
+	}
+}
+
+// demoUserStore is course 6's users, and two more to search for.
+func demoUserStore() *web.RepositoryUserStore {
+	store := web.NewDemoUsers()
+	store.Create(web.User{Name: "Alicia Keys", Email: "alicia@example.com", Age: 44})
+	store.Create(web.User{Name: "Bob Marley", Email: "bob.marley@example.com", Age: 36})
+	return store
+}
+
+// serveUserSearch puts course 6's /search on search, and prints what each
+// query returns.
+func serveUserSearch(ctx context.Context, w io.Writer, search web.UserSearch, queries ...string) error {
+	server := httptest.NewServer(web.SearchHandler(search))
+	defer server.Close()
+	for _, q := range queries {
+		req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/search?q="+url.QueryEscape(q), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "text/plain")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "GET /search?q=%s\n%s", url.QueryEscape(q), body)
+	}
+	return nil
+}
+
+// noFTS5 is what the course prints instead of the sections that need
+// FTS5, when it isn't compiled in.
+const noFTS5 = "FTS5 isn't compiled in; run: go run -tags sqlite_fts5 ./cmd/learn 27"
+
+// ============ COURSE TWENTY-SEVEN MAIN FUNCTION ============
+func CourseTwentySeven(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 27)
+
+	l.Section("contains")
+	for _, q := range []string{"running shoe", "shoes running", "shoe", "art", "runs"} {
+		var names []string
+		for _, p := range containsSearch(catalogue, q) {
+			names = append(names, p.Name)
+		}
+		l.Printf("%-14q %s\n", q, strings.Join(names, ", "))
+	}
+	l.Resume()
+
+	// Without FTS5, db stays nil, and each section says so
+	db, err := openSearchDB(ctx)
+	if err != nil && !errors.Is(err, errNoFTS5) {
+		return err
+	}
+	if db != nil {
+		defer db.Close()
+	}
+
+	l.Section("fts5")
+	if db == nil {
+		l.Println(noFTS5)
+	} else {
+		printSearches(ctx, l.Printer, db, "running shoes", "shoes running", "shoe", "art", "runs")
+		if _, err := db.ExecContext(ctx, `UPDATE products SET name = 'Trail runner' WHERE id = 1`); err != nil {
+			return err
+		}
+		hits, err := searchProducts(ctx, db, "runner", 5)
+		printHits(l.Printer, "runner, after renaming product 1", hits, err)
+	}
+	l.Resume()
+
+	l.Section("queries")
+	if db == nil {
+		l.Println(noFTS5)
+	} else {
+		printSearches(ctx, l.Printer, db, `"running shoes"`, `trail*`, `mouse NOT wireless`, `watch OR keyboard`, `name:trail`)
+		l.Println("What users type isn't a query:")
+		for _, input := range []string{`heart-rate`, `"wireless`, `dry socks)`} {
+			_, err := searchProducts(ctx, db, input, 5)
+			l.Printf("  %-10s %v\n", input, err)
+		}
+		l.Println("ftsQuery makes it one:")
+		for _, input := range []string{`heart-rate`, `"wireless`, `dry socks)`, `trail ma`} {
+			query := ftsQuery(input)
+			hits, err := searchProducts(ctx, db, query, 5)
+			printHits(l.Printer, fmt.Sprintf("  %s -> %s", input, query), hits, err)
+		}
+	}
+	l.Resume()
+
+	l.Section("ranking")
+	if db == nil {
+		l.Println(noFTS5)
+	} else {
+		printSearches(ctx, l.Printer, db, "trail", "mouse")
+	}
+	l.Resume()
+
+	l.Section("bleve")
+	if err := bleveSearchDemo(ctx, l.Printer, catalogue); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.Section("endpoint")
+	store := demoUserStore()
+	queries := []string{"bob", "ali", "marley example.com", `"charlie`}
+	if db == nil {
+		l.Println(noFTS5)
+	} else if err := createUserIndex(ctx, db, store.List()); err != nil {
+		return err
+	} else if err := serveUserSearch(ctx, l.Printer, ftsUserSearch(db, store), queries...); err != nil {
+		return err
+	}
+	l.Println("The same /search on a bleve index:")
+	index, err := newBleveUserIndex(store.List())
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+	if err := serveUserSearch(ctx, l.Printer, bleveUserSearch(index, store), append(queries, "alcia")...); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.End()
+	return nil
+}
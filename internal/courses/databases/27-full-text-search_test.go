@@ -0,0 +1,178 @@
+//go:build cgo
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+)
+
+// Run with: go test -tags sqlite_fts5 -run Search ./internal/courses/databases
+// Without the tag SQLite has no FTS5: TestSearchQuery runs, the rest skip.
+
+func newSearchDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := openSearchDB(context.Background())
+	if errors.Is(err, errNoFTS5) {
+		t.Skip("FTS5 needs -tags sqlite_fts5")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// searchIDs returns the IDs of the products query finds, best first.
+func searchIDs(t *testing.T, db *sql.DB, query string) []int64 {
+	t.Helper()
+	hits, err := searchProducts(context.Background(), db, query, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+func TestSearchQuery(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"running shoes", `"running" "shoes"*`},
+		{"  trail   ", `"trail"*`},
+		{`"wireless`, `"wireless"*`},
+		{"mouse NOT wireless", `"mouse" "NOT" "wireless"*`},
+		{"name:trail", `"name" "trail"*`},
+		{"usb-c", `"usb-c"*`},
+		{"o'brien", `"o'brien"*`},
+		{"crème brûlée", `"crème" "brûlée"*`},
+		{`* ( ) : " ^ +`, ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ftsQuery(tt.input); got != tt.want {
+			t.Errorf("ftsQuery(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSearchProducts(t *testing.T) {
+	db := newSearchDB(t)
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		// Stems: runs, running and run are one word
+		{"runs", []int64{2, 7, 1, 6}},
+		// Words, in any order, and not parts of words
+		{"shoes running", []int64{2, 1}},
+		{"art", []int64{8}},
+		// A word in the name counts for more than one in the description
+		{"trail", []int64{10, 1, 8}},
+		{`"running shoes"`, []int64{2, 1}},
+		{`"shoes running"`, nil},
+		{"nothing", nil},
+	}
+	for _, tt := range tests {
+		if got := searchIDs(t, db, tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("search %s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	hits, err := searchProducts(context.Background(), db, "mouse", 1)
+	if err != nil || len(hits) != 1 || hits[0].Snippet != "A quiet [mouse] with a USB..." {
+		t.Errorf("search mouse, limit 1 = %+v, %v", hits, err)
+	}
+}
+
+// The triggers keep products_fts in step with products.
+func TestSearchTriggers(t *testing.T) {
+	db := newSearchDB(t)
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `UPDATE products SET name = 'Trail runner' WHERE id = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if got := searchIDs(t, db, "runner"); !slices.Equal(got, []int64{1}) {
+		t.Errorf("after renaming: runner finds %v, want product 1", got)
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM products WHERE id = 3`); err != nil {
+		t.Fatal(err)
+	}
+	if got := searchIDs(t, db, "mouse"); !slices.Equal(got, []int64{9}) {
+		t.Errorf("after deleting the mouse: mouse finds %v, want the charger only", got)
+	}
+	if err := addProducts(ctx, db, product{ID: 11, Name: "Gaming mouse", Description: "Wired, with six buttons."}); err != nil {
+		t.Fatal(err)
+	}
+	if got := searchIDs(t, db, "mouse"); !slices.Equal(got, []int64{11, 9}) {
+		t.Errorf("after adding a mouse: mouse finds %v, want 11 and 9", got)
+	}
+}
+
+// Whatever a user types, ftsQuery makes a query FTS5 accepts.
+func TestSearchUserInput(t *testing.T) {
+	db := newSearchDB(t)
+	for _, input := range []string{`"wireless`, "dry socks)", "heart-rate", "socks AND", "NEAR(", "a:b", "-", "'", "*", "o'brien", "ünïcödé"} {
+		if _, err := searchProducts(context.Background(), db, input, 10); err == nil {
+			t.Logf("%q is a valid query as it is", input)
+		}
+		query := ftsQuery(input)
+		if query == "" {
+			continue
+		}
+		if _, err := searchProducts(context.Background(), db, query, 10); err != nil {
+			t.Errorf("ftsQuery(%q) = %s: %v", input, query, err)
+		}
+	}
+	if got := searchIDs(t, db, ftsQuery("dry socks)")); !slices.Equal(got, []int64{7}) {
+		t.Errorf("dry socks) finds %v, want the socks", got)
+	}
+}
+
+func TestSearchEndpoint(t *testing.T) {
+	db := newSearchDB(t)
+	store := web.NewDemoUsers()
+	store.Create(web.User{Name: "Alicia Keys", Email: "alicia@example.com", Age: 44})
+	if err := createUserIndex(context.Background(), db, store.List()); err != nil {
+		t.Fatal(err)
+	}
+	store.Delete(2, 1) // Bob leaves after the index was built
+	handler := web.SearchHandler(ftsUserSearch(db, store))
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/search?q=ali", []string{"Alice", "Alicia Keys"}},
+		{"/search?q=ali&minAge=40", []string{"Alicia Keys"}},
+		{"/search?q=keys+alicia", []string{"Alicia Keys"}},
+		{"/search?q=%22charlie", []string{"Charlie"}},
+		{"/search?q=bob", nil},
+		{"/search?name=charlie", []string{"Charlie"}},
+		{"/search", []string{"Alice", "Charlie", "Alicia Keys"}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("Accept", "text/plain")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		var names []string
+		for line := range strings.Lines(w.Body.String()) {
+			if _, rest, ok := strings.Cut(line, " Name:"); ok {
+				name, _, _ := strings.Cut(rest, " Email:")
+				names = append(names, name)
+			}
+		}
+		if w.Code != 200 || !slices.Equal(names, tt.want) {
+			t.Errorf("%s = %d %v, want %v", tt.path, w.Code, names, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package databases
+
+// The bleve part of course 27. bleve is pure Go, so unlike FTS5 it needs
+// no build tag.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/lang/en"
+	"github.com/blevesearch/bleve/v2/search/query"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+)
+
+// newBleveIndex indexes the products in memory (bleve.New would keep the
+// index in a directory). The English analyzer lower-cases, drops stop
+// words and stems, as FTS5's porter tokenizer does.
+func newBleveIndex(products []product) (bleve.Index, error) {
+	mapping := bleve.NewIndexMapping()
+	mapping.DefaultAnalyzer = en.AnalyzerName
+	index, err := bleve.NewMemOnly(mapping)
+	if err != nil {
+		return nil, err
+	}
+	batch := index.NewBatch()
+	for _, p := range products {
+		if err := batch.Index(strconv.FormatInt(p.ID, 10), p); err != nil {
+			index.Close()
+			return nil, err
+		}
+	}
+	if err := index.Batch(batch); err != nil {
+		index.Close()
+		return nil, err
+	}
+	return index, nil
+}
+
+// searchBleve finds text in the products' names and descriptions, a match
+// in the name counting ten times one in the description, as
+// searchProducts weighs them. fuzziness is how many letters may differ: 1
+// or 2 forgive a typo.
+func searchBleve(ctx context.Context, index bleve.Index, text string, fuzziness int) (*bleve.SearchResult, error) {
+	name := bleve.NewMatchQuery(text)
+	name.SetField("Name")
+	name.SetBoost(10)
+	name.SetFuzziness(fuzziness)
+	description := bleve.NewMatchQuery(text)
+	description.SetField("Description")
+	description.SetFuzziness(fuzziness)
+
+	req := bleve.NewSearchRequestOptions(bleve.NewDisjunctionQuery(name, description), 5, 0, false)
+	req.Fields = []string{"Name"}
+	req.Highlight = bleve.NewHighlight()
+	req.Highlight.AddField("Description")
+	return index.SearchInContext(ctx, req)
+}
+
+func bleveSearchDemo(ctx context.Context, w io.Writer, products []product) error {
+	index, err := newBleveIndex(products)
+	if err != nil {
+		return err
+	}
+	defer index.Close()
+
+	for _, q := range []struct {
+		text      string
+		fuzziness int
+	}{
+		{"runs", 0},
+		{"shoes running", 0},
+		{"wirless", 0},
+		{"wirless", 1},
+		{"keybaord", 2},
+	} {
+		res, err := searchBleve(ctx, index, q.text, q.fuzziness)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s, fuzziness %d:\n", q.text, q.fuzziness)
+		if len(res.Hits) == 0 {
+			fmt.Fprintln(w, "  (nothing)")
+		}
+		for _, hit := range res.Hits {
+			fmt.Fprintf(w, "  %-2s %-20v %5.2f %s\n", hit.ID, hit.Fields["Name"], hit.Score,
+				strings.Join(hit.Fragments["Description"], " "))
+		}
+	}
+	return nil
+}
+
+// newBleveUserIndex indexes users' names and emails for course 6's /search,
+// as createUserIndex does in FTS5. The standard analyzer lower-cases and
+// splits words but doesn't stem: "alicia" stays "alicia", so a prefix of
+// it still matches.
+func newBleveUserIndex(users []web.User) (bleve.Index, error) {
+	index, err := bleve.NewMemOnly(bleve.NewIndexMapping())
+	if err != nil {
+		return nil, err
+	}
+	batch := index.NewBatch()
+	for _, u := range users {
+		// bleve names the fields after their json tags: name and email
+		if err := batch.Index(strconv.Itoa(u.ID), u); err != nil {
+			index.Close()
+			return nil, err
+		}
+	}
+	if err := index.Batch(batch); err != nil {
+		index.Close()
+		return nil, err
+	}
+	return index, nil
+}
+
+// bleveUserSearch is a web.UserSearch on a bleve index of the users: each
+// word must start a word of the name or email, or be a typo away from a
+// word of the name. Ties go by ID, so the same search lists the same
+// users in the same order.
+func bleveUserSearch(index bleve.Index, store *web.RepositoryUserStore) web.UserSearch {
+	return func(ctx context.Context, q string) ([]web.User, error) {
+		var each []query.Query
+		for _, word := range strings.Fields(strings.ToLower(q)) {
+			// Prefix queries aren't analyzed: drop the quotes and brackets
+			// the index has none of
+			word = strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+			if word == "" {
+				continue
+			}
+			name := bleve.NewPrefixQuery(word)
+			name.SetField("name")
+			email := bleve.NewPrefixQuery(word)
+			email.SetField("email")
+			typo := bleve.NewFuzzyQuery(word)
+			typo.SetField("name")
+			each = append(each, bleve.NewDisjunctionQuery(name, email, typo))
+		}
+		if len(each) == 0 {
+			return store.List(), nil
+		}
+		req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(each...), 20, 0, false)
+		req.SortBy([]string{"-_score", "_id"})
+		res, err := index.SearchInContext(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("search users: %w", err)
+		}
+		var found []web.User
+		for _, hit := range res.Hits {
+			id, err := strconv.Atoi(hit.ID)
+			if err != nil {
+				return nil, fmt.Errorf("search users: %w", err)
+			}
+			// A user deleted from the store since it was indexed is skipped
+			if u, ok := store.Get(id); ok {
+				found = append(found, u)
+			}
+		}
+		return found, nil
+	}
+}
@@ -0,0 +1,91 @@
+package databases
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+)
+
+func TestBleveSearch(t *testing.T) {
+	index, err := newBleveIndex(catalogue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+
+	tests := []struct {
+		text      string
+		fuzziness int
+		want      []string
+	}{
+		// Stems, as FTS5's porter tokenizer has them
+		{"runs", 0, []string{"7", "2", "1", "6"}},
+		{"shoes running", 0, []string{"2", "1", "7", "5", "6"}},
+		// A typo is one edit from the stem, or it's nothing
+		{"wirless", 0, nil},
+		{"wirless", 1, []string{"3", "4"}},
+		{"wireles", 2, nil},
+		{"keybaord", 2, []string{"4"}},
+	}
+	for _, tt := range tests {
+		res, err := searchBleve(context.Background(), index, tt.text, tt.fuzziness)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, hit := range res.Hits {
+			ids = append(ids, hit.ID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("search %s, fuzziness %d = %v, want %v", tt.text, tt.fuzziness, ids, tt.want)
+		}
+	}
+}
+
+func TestBleveSearchEndpoint(t *testing.T) {
+	store := web.NewDemoUsers()
+	store.Create(web.User{Name: "Alicia Keys", Email: "alicia@example.com", Age: 44})
+	index, err := newBleveUserIndex(store.List())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer index.Close()
+	store.Delete(2, 1) // Bob leaves after the index was built
+	handler := web.SearchHandler(bleveUserSearch(index, store))
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/search?q=ali", []string{"Alice", "Alicia Keys"}},
+		{"/search?q=ali&minAge=40", []string{"Alicia Keys"}},
+		{"/search?q=keys+alicia", []string{"Alicia Keys"}},
+		{"/search?q=alcia", []string{"Alicia Keys"}},
+		{"/search?q=example.com+charl", []string{"Charlie"}},
+		{"/search?q=%22charlie", []string{"Charlie"}},
+		{"/search?q=bob", nil},
+		{"/search?name=charlie", []string{"Charlie"}},
+		{"/search?q=%22", []string{"Alice", "Charlie", "Alicia Keys"}},
+		{"/search", []string{"Alice", "Charlie", "Alicia Keys"}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("Accept", "text/plain")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		var names []string
+		for line := range strings.Lines(w.Body.String()) {
+			if _, rest, ok := strings.Cut(line, " Name:"); ok {
+				name, _, _ := strings.Cut(rest, " Email:")
+				names = append(names, name)
+			}
+		}
+		if w.Code != 200 || !slices.Equal(names, tt.want) {
+			t.Errorf("%s = %d %v, want %v", tt.path, w.Code, names, tt.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+//go:build cgo
+
+package databases_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases"
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases/repotest"
+)
+
+// sharedDB is one SQLite database, in a file, for every test in the
+// package. TestMain makes its schema once; a test that uses it writes
+// through inTx, so nothing it writes outlives it.
+var sharedDB *sql.DB
+
+// containers start the servers that tagged test files need, once for the
+// package: with -tags postgres, main_postgres_test.go adds PostgreSQL.
+// Each returns a function that stops what it started.
+var containers []func(ctx context.Context) (stop func(), err error)
+
+// TestMain runs instead of the tests, and runs them with m.Run: the setup
+// before it and the teardown after happen once for the whole package, not
+// once per test. The work is in setUp, whose defers run before os.Exit,
+// which skips them.
+func TestMain(m *testing.M) {
+	os.Exit(setUp(m))
+}
+
+func setUp(m *testing.M) int {
+	ctx := context.Background()
+	dir, err := os.MkdirTemp("", "databases-test-*")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	sharedDB, err = sql.Open("sqlite3", filepath.Join(dir, "shared.db"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer sharedDB.Close()
+	// The schema, once: NewSQLUsers creates the users table
+	if _, err := databases.NewSQLUsers(ctx, sharedDB); err != nil {
+		fmt.Fprintln(os.Stderr, "provisioning the shared database:", err)
+		return 1
+	}
+
+	for _, start := range containers {
+		stop, err := start(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer stop()
+	}
+	return m.Run()
+}
+
+// inTx begins a transaction on db and rolls it back when the test ends,
+// so whatever the test wrote is gone before the next one starts, however
+// it ended. Rolling back is faster than deleting the rows, and can't miss
+// a table.
+//
+// SQLite lets one transaction write at a time, so tests that use
+// sharedDB don't call t.Parallel: a second writer would wait for the
+// first to end, which is at the end of its test.
+func inTx(t *testing.T, db *sql.DB) *sql.Tx {
+	t.Helper()
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// The contract again, on one database the cases share, each in a
+// transaction of its own. The table already exists, so NewSQLUsers's
+// CREATE TABLE IF NOT EXISTS does nothing.
+func TestSQLUsersInTx(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) databases.UserRepository {
+		users, err := databases.NewSQLUsers(context.Background(), inTx(t, sharedDB))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return users
+	})
+}
+
+func TestRollbackIsolation(t *testing.T) {
+	ctx := context.Background()
+	count := func() int {
+		var n int
+		if err := sharedDB.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	t.Run("writes", func(t *testing.T) {
+		users, err := databases.NewSQLUsers(ctx, inTx(t, sharedDB))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := users.Create(ctx, databases.User{Name: "Alice", Email: "alice@example.com"}); err != nil {
+			t.Fatal(err)
+		}
+		if list, err := users.List(ctx); err != nil || len(list) != 1 {
+			t.Errorf("inside the transaction, List = %v, %v; want Alice", list, err)
+		}
+	})
+
+	// The subtest's cleanup rolled its transaction back
+	if n := count(); n != 0 {
+		t.Errorf("%d users left in the shared database, want 0", n)
+	}
+}
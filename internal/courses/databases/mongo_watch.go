@@ -0,0 +1,113 @@
+//go:build mongo
+
+package databases
+
+// The live part of course 8, run with:
+//
+//	eval "$(go run ./cmd/learn env up mongo)"
+//	go run -tags mongo ./cmd/learn 8
+//
+// It is behind a build tag because change streams only work on a replica
+// set; the MongoDB in compose.yaml is a one-node one.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/patterns"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func init() {
+	liveDemo = watchDemo
+}
+
+// changePrinter is an Observer that prints what the bus delivers and
+// counts it, so the demo knows when every change has arrived.
+type changePrinter struct {
+	w    io.Writer
+	mu   sync.Mutex
+	seen int
+	all  chan struct{} // closed after want messages
+	want int
+}
+
+func (p *changePrinter) Update(message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, "  bus: %s\n", message)
+	p.seen++
+	if p.seen == p.want {
+		close(p.all)
+	}
+}
+
+func watchDemo(ctx context.Context, w io.Writer) error {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		return errors.New(`MONGODB_URI not set; run: eval "$(go run ./cmd/learn env up mongo)"`)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.WithoutCancel(ctx))
+
+	// A collection of its own, dropped afterwards
+	coll := client.Database("learn").Collection("users_watch_demo")
+	defer coll.Drop(context.WithoutCancel(ctx))
+	users, err := NewMongoUsers(ctx, coll)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "\n=== Change streams, live ===")
+	stream, err := mongoOpenWatch(ctx, coll)
+	if err != nil {
+		return fmt.Errorf("watch (change streams need a replica set): %w", err)
+	}
+
+	bus := patterns.NewSubject()
+	printer := &changePrinter{w: w, want: 3, all: make(chan struct{})}
+	bus.Subscribe(printer)
+
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	watched := make(chan error, 1)
+	go func() { watched <- mongoWatch(watchCtx, stream, bus) }()
+
+	// Three writes through the repository; the stream sees each one
+	alice, err := users.Create(ctx, User{Name: "Alice", Email: "alice@example.com", Age: 30})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "created", alice.Email)
+	alice.Email = "alicia@example.com"
+	if err := users.Update(ctx, alice); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "updated to", alice.Email)
+	if err := users.Delete(ctx, alice.ID); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "deleted", alice.ID)
+
+	select {
+	case <-printer.all:
+	case err := <-watched:
+		return fmt.Errorf("stream ended early: %v", err)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	stopWatching()
+	return <-watched
+}
@@ -0,0 +1,93 @@
+//go:build postgres
+
+package databases
+
+// The PostgreSQL part of course 24, run with:
+//
+//	eval "$(go run ./cmd/learn env up postgres)"
+//	go run -tags postgres ./cmd/learn 24
+//
+// It is behind a build tag because it needs a server. lib/pq, whose COPY
+// support database/sql can reach, is in go.mod, so go vet -tags postgres
+// checks it without one.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	_ "github.com/lib/pq" // registers the "postgres" driver
+)
+
+func init() {
+	copyDemo = postgresCopyDemo
+}
+
+// insertInTxPostgres is insertInTx with PostgreSQL's numbered placeholders.
+func insertInTxPostgres(ctx context.Context, db *sql.DB, users []DBUser) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO users (name, email, age) VALUES ($1, $2, $3)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, u := range users {
+		if _, err := stmt.ExecContext(ctx, u.Name, u.Email, u.Age); err != nil {
+			return fmt.Errorf("insert %s: %w", u.Email, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func postgresCopyDemo(ctx context.Context, w io.Writer, users []DBUser) error {
+	url := os.Getenv("POSTGRES_URL")
+	if url == "" {
+		return errors.New(`POSTGRES_URL not set; run: eval "$(go run ./cmd/learn env up postgres)"`)
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	// A temporary table belongs to its connection, so keep to one. It
+	// hides any users table for this session and goes when it ends.
+	db.SetMaxOpenConns(1)
+	if _, err := db.ExecContext(ctx, `
+	CREATE TEMP TABLE users (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		email TEXT UNIQUE NOT NULL,
+		age INTEGER
+	)`); err != nil {
+		return err
+	}
+
+	for _, strategy := range []struct {
+		name   string
+		insert func(context.Context, *sql.DB, []DBUser) error
+	}{
+		{"One transaction", insertInTxPostgres},
+		{"COPY", copyUsers},
+	} {
+		if _, err := db.ExecContext(ctx, `TRUNCATE users`); err != nil {
+			return err
+		}
+		start := time.Now()
+		if err := strategy.insert(ctx, db, users); err != nil {
+			return fmt.Errorf("%s: %w", strategy.name, err)
+		}
+		fmt.Fprintf(w, "PostgreSQL, %s: %s\n", strategy.name, timing(len(users), time.Since(start)))
+	}
+	return nil
+}
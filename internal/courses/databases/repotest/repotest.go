@@ -0,0 +1,181 @@
+// Package repotest is the contract every databases.UserRepository keeps,
+// as a test suite any backend can run, the way net/http/httptest and
+// testing/fstest serve their packages:
+//
+//	func TestSQLUsers(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) databases.UserRepository {
+//			... // a new, empty repository
+//		})
+//	}
+//
+// A backend that passes can stand in for any other: memory in tests, SQL
+// or MongoDB in production. The suite checks behaviour through the
+// interface only: what Create returns, what Get finds afterwards, and which
+// errors errors.Is recognises.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases"
+)
+
+// Factory returns a new, empty repository. Run calls it once per case, so
+// the cases don't see each other's users; it may use t for t.Cleanup and
+// t.Fatal.
+type Factory func(t *testing.T) databases.UserRepository
+
+// Run runs the contract against the repositories newRepo makes, each case
+// as a subtest.
+func Run(t *testing.T, newRepo Factory) {
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.test(t, newRepo(t))
+		})
+	}
+}
+
+var (
+	alice = databases.User{Name: "Alice", Email: "alice@example.com", Age: 30}
+	bob   = databases.User{Name: "Bob", Email: "bob@example.com", Age: 25}
+)
+
+var cases = []struct {
+	name string
+	test func(t *testing.T, repo databases.UserRepository)
+}{
+	{"create and get", testCreateAndGet},
+	{"emails are unique", testUniqueEmails},
+	{"update", testUpdate},
+	{"delete", testDelete},
+	{"unknown IDs", testUnknownIDs},
+	{"list by email", testList},
+}
+
+func create(t *testing.T, repo databases.UserRepository, u databases.User) databases.User {
+	t.Helper()
+	created, err := repo.Create(context.Background(), u)
+	if err != nil {
+		t.Fatalf("Create(%s): %v", u.Email, err)
+	}
+	return created
+}
+
+func testCreateAndGet(t *testing.T, repo databases.UserRepository) {
+	a := create(t, repo, databases.User{ID: "ignored", Name: alice.Name, Email: alice.Email, Age: alice.Age})
+	b := create(t, repo, bob)
+	if a.ID == "" || a.ID == "ignored" || a.ID == b.ID {
+		t.Errorf("IDs %q and %q: want two new, different IDs", a.ID, b.ID)
+	}
+
+	got, err := repo.Get(context.Background(), a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (databases.User{ID: a.ID, Name: "Alice", Email: "alice@example.com", Age: 30}); got != want {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+}
+
+func testUniqueEmails(t *testing.T, repo databases.UserRepository) {
+	ctx := context.Background()
+	create(t, repo, alice)
+	b := create(t, repo, bob)
+
+	if _, err := repo.Create(ctx, databases.User{Name: "Alice 2", Email: alice.Email}); !errors.Is(err, databases.ErrEmailTaken) {
+		t.Errorf("Create with a taken email: err = %v, want ErrEmailTaken", err)
+	}
+	b.Email = alice.Email
+	if err := repo.Update(ctx, b); !errors.Is(err, databases.ErrEmailTaken) {
+		t.Errorf("Update to a taken email: err = %v, want ErrEmailTaken", err)
+	}
+	// The failed Update changed nothing
+	if got, err := repo.Get(ctx, b.ID); err != nil || got.Email != bob.Email {
+		t.Errorf("after the failed Update: %+v, %v; want Bob's email kept", got, err)
+	}
+	b.Email, b.Age = bob.Email, 26 // keeping one's own email is fine
+	if err := repo.Update(ctx, b); err != nil {
+		t.Errorf("Update keeping the email: %v", err)
+	}
+}
+
+func testUpdate(t *testing.T, repo databases.UserRepository) {
+	ctx := context.Background()
+	a := create(t, repo, alice)
+	a.Name, a.Email, a.Age = "Alicia", "alicia@example.com", 31
+	if err := repo.Update(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := repo.Get(ctx, a.ID); err != nil || got != a {
+		t.Errorf("after Update: %+v, %v; want %+v", got, err, a)
+	}
+	// The old email is free again
+	create(t, repo, alice)
+}
+
+func testDelete(t *testing.T, repo databases.UserRepository) {
+	ctx := context.Background()
+	a := create(t, repo, alice)
+	if err := repo.Delete(ctx, a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(ctx, a.ID); !errors.Is(err, databases.ErrUserNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrUserNotFound", err)
+	}
+	if err := repo.Delete(ctx, a.ID); !errors.Is(err, databases.ErrUserNotFound) {
+		t.Errorf("second Delete: err = %v, want ErrUserNotFound", err)
+	}
+	// The email is free again
+	create(t, repo, alice)
+}
+
+func testUnknownIDs(t *testing.T, repo databases.UserRepository) {
+	ctx := context.Background()
+	gone := create(t, repo, alice)
+	repo.Delete(ctx, gone.ID)
+
+	// One the backend made, and ones it never could have
+	for _, id := range []string{gone.ID, "", "nope", "-1", "65f0c0ffee0000000000000z"} {
+		if _, err := repo.Get(ctx, id); !errors.Is(err, databases.ErrUserNotFound) {
+			t.Errorf("Get(%q): err = %v, want ErrUserNotFound", id, err)
+		}
+		if err := repo.Update(ctx, databases.User{ID: id, Name: "X", Email: "x@example.com"}); !errors.Is(err, databases.ErrUserNotFound) {
+			t.Errorf("Update(%q): err = %v, want ErrUserNotFound", id, err)
+		}
+		if err := repo.Delete(ctx, id); !errors.Is(err, databases.ErrUserNotFound) {
+			t.Errorf("Delete(%q): err = %v, want ErrUserNotFound", id, err)
+		}
+	}
+}
+
+func testList(t *testing.T, repo databases.UserRepository) {
+	ctx := context.Background()
+	if users, err := repo.List(ctx); err != nil || len(users) != 0 {
+		t.Fatalf("List of an empty repository = %v, %v", users, err)
+	}
+	for _, u := range []databases.User{
+		{Name: "Carol", Email: "carol@example.com"},
+		alice,
+		bob,
+	} {
+		create(t, repo, u)
+	}
+
+	users, err := repo.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, u := range users {
+		names = append(names, u.Name)
+		if u.ID == "" {
+			t.Errorf("%s listed without an ID", u.Name)
+		}
+	}
+	if want := []string{"Alice", "Bob", "Carol"}; !slices.Equal(names, want) {
+		t.Errorf("List = %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build cgo
+
+package databases
+
+import _ "github.com/mattn/go-sqlite3" // registers the "sqlite3" driver courses 22 to 27 use; needs cgo
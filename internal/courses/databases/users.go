@@ -0,0 +1,124 @@
+package databases
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// User is a user as a UserRepository stores it. Its ID is a string because
+// each backend makes its own: a counter in memory, a row ID in SQL, an
+// ObjectID in MongoDB.
+type User struct {
+	ID    string
+	Name  string
+	Email string
+	Age   int
+}
+
+// UserRepository stores users; the application depends on it rather than
+// on a database, and picks a backend at startup (course 12's repository
+// pattern). MemoryUsers, SQLUsers (course 7), MongoUsers (course 8) and
+// AuditedUsers (course 26) implement it, and one contract test suite,
+// repotest.Run, holds them all to the same behaviour.
+type UserRepository interface {
+	// Create stores u, whose ID is ignored, and returns it with the ID the
+	// backend gave it. It fails with ErrEmailTaken if another user has the
+	// email.
+	Create(ctx context.Context, u User) (User, error)
+	Get(ctx context.Context, id string) (User, error)
+	// Update replaces the user with u.ID.
+	Update(ctx context.Context, u User) error
+	Delete(ctx context.Context, id string) error
+	// List returns every user, by email.
+	List(ctx context.Context) ([]User, error)
+}
+
+// Repository errors, the same from every backend. Check for them with
+// errors.Is. An ID a backend could never have made, like "abc" for SQL,
+// is ErrUserNotFound too.
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email already taken")
+)
+
+// MemoryUsers is a UserRepository in a map, for tests and for trying
+// things out. It is safe for concurrent use.
+type MemoryUsers struct {
+	mu     sync.RWMutex
+	users  map[string]User
+	lastID int
+}
+
+func NewMemoryUsers() *MemoryUsers {
+	return &MemoryUsers{users: make(map[string]User)}
+}
+
+func (m *MemoryUsers) Create(ctx context.Context, u User) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.emailTaken(u.Email, "") {
+		return User{}, fmt.Errorf("create %s: %w", u.Email, ErrEmailTaken)
+	}
+	m.lastID++
+	u.ID = strconv.Itoa(m.lastID)
+	m.users[u.ID] = u
+	return u, nil
+}
+
+func (m *MemoryUsers) Get(ctx context.Context, id string) (User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, ok := m.users[id]
+	if !ok {
+		return User{}, fmt.Errorf("get user %q: %w", id, ErrUserNotFound)
+	}
+	return u, nil
+}
+
+func (m *MemoryUsers) Update(ctx context.Context, u User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[u.ID]; !ok {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrUserNotFound)
+	}
+	if m.emailTaken(u.Email, u.ID) {
+		return fmt.Errorf("update user %q: %w", u.ID, ErrEmailTaken)
+	}
+	m.users[u.ID] = u
+	return nil
+}
+
+func (m *MemoryUsers) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[id]; !ok {
+		return fmt.Errorf("delete user %q: %w", id, ErrUserNotFound)
+	}
+	delete(m.users, id)
+	return nil
+}
+
+func (m *MemoryUsers) List(ctx context.Context) ([]User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.SortedFunc(maps.Values(m.users), func(a, b User) int {
+		return cmp.Compare(a.Email, b.Email)
+	}), nil
+}
+
+// emailTaken reports whether a user other than except has email, as the
+// unique index does in the databases.
+func (m *MemoryUsers) emailTaken(email, except string) bool {
+	for id, u := range m.users {
+		if u.Email == email && id != except {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+//go:build cgo
+
+package databases_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases"
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases/repotest"
+)
+
+// SQLUsers keeps the contract on a fresh in-memory SQLite database for
+// each case; sqlite_cgo.go registers the driver.
+func TestSQLUsers(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) databases.UserRepository {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1) // every connection to :memory: is a database of its own
+		t.Cleanup(func() { db.Close() })
+
+		users, err := databases.NewSQLUsers(context.Background(), db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return users
+	})
+}
+
+// AuditedUsers deletes softly, and keeps the contract all the same: to
+// the application, a deleted user is gone and its email free.
+func TestAuditedUsers(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) databases.UserRepository {
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+
+		users, err := databases.NewAuditedUsers(context.Background(), db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return users
+	})
+}
@@ -0,0 +1,58 @@
+package databases_test
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases"
+	"github.com/owolabijunior12/learning-golang/internal/courses/databases/repotest"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Run with: go test -run Users ./internal/courses/databases
+// Every backend runs the same contract, repotest.Run, from outside the
+// package, as an application would use it. The MongoDB backend runs only
+// with a server, at MONGODB_URI:
+//
+//	eval "$(go run ./cmd/learn env up mongo)"
+
+func TestMemoryUsers(t *testing.T) {
+	repotest.Run(t, func(*testing.T) databases.UserRepository { return databases.NewMemoryUsers() })
+}
+
+func TestMongoUsers(t *testing.T) {
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("MONGODB_URI not set; see the comment at the top of this file")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Fatalf("no MongoDB at %s: %v", uri, err)
+	}
+
+	// A database of its own, dropped afterwards, so the test never touches
+	// real data
+	db := client.Database("learn_test_" + rand.Text()[:8])
+	t.Cleanup(func() { db.Drop(context.Background()) })
+
+	n := 0
+	repotest.Run(t, func(t *testing.T) databases.UserRepository {
+		n++
+		users, err := databases.NewMongoUsers(context.Background(), db.Collection(fmt.Sprintf("users%d", n)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return users
+	})
+}
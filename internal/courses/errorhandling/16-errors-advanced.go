@@ -0,0 +1,257 @@
+package errorhandling
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/basics"
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 16: ERROR HANDLING II - WRAPPING, SENTINELS, errors.Is/As
+// Topics covered:
+// 1. Wrapping errors with %w and walking the chain
+// 2. Sentinel errors
+// 3. errors.Is
+// 4. errors.As with custom error types
+// 5. Custom types with Unwrap()
+// 6. errors.Join for multiple errors
+// 7. Mapping domain errors to HTTP status codes
+// 8. When to use panic (and when not to)
+
+// This course continues where course 2 stopped: ValidationError and
+// validateAge from course 2 and the users map from course 6 are reused here.
+
+// ============ 1. SENTINEL ERRORS ============
+// Sentinel errors are package-level values that callers compare against.
+// By convention their names start with Err.
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrEmailTaken   = errors.New("email already registered")
+)
+
+// ============ 2. WRAPPING WITH %w ============
+// Each layer adds context but keeps the original error inside.
+func findUserRecord(id int) (web.User, error) {
+	user, ok := web.Users.Get(id)
+	if !ok {
+		return web.User{}, fmt.Errorf("find user %d: %w", id, ErrUserNotFound)
+	}
+	return user, nil
+}
+
+func loadUserProfile(id int) (web.User, error) {
+	user, err := findUserRecord(id)
+	if err != nil {
+		return web.User{}, fmt.Errorf("load profile: %w", err)
+	}
+	return user, nil
+}
+
+// printErrorChain walks the chain one Unwrap at a time
+func printErrorChain(out *demo.Printer, err error) {
+	for depth := 0; err != nil; depth++ {
+		out.Printf("  %s%v\n", strings.Repeat("  ", depth), err)
+		err = errors.Unwrap(err)
+	}
+}
+
+// ============ 3. CUSTOM ERROR TYPE WITH UNWRAP ============
+// QueryError carries extra data (the query) and still exposes its cause.
+type QueryError struct {
+	Query string
+	Err   error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query %q: %v", e.Query, e.Err)
+}
+
+// Unwrap lets errors.Is and errors.As look inside
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+func lookupEmail(id int) (string, error) {
+	query := "SELECT email FROM users WHERE id = ?"
+	user, ok := web.Users.Get(id)
+	if !ok {
+		return "", &QueryError{Query: query, Err: sql.ErrNoRows}
+	}
+	return user.Email, nil
+}
+
+// ============ 4. errors.Join ============
+// Report every validation problem at once instead of stopping at the first.
+func validateRegistration(name, email string, age int) error {
+	var errs []error
+
+	if strings.TrimSpace(name) == "" {
+		errs = append(errs, basics.ValidationError{Field: "name", Message: "name is required"})
+	}
+	if !strings.Contains(email, "@") {
+		errs = append(errs, basics.ValidationError{Field: "email", Message: "email must contain @"})
+	}
+	if err := basics.ValidateAge(age); err != nil { // from course 2
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...) // nil if errs is empty
+}
+
+func registerUser(name, email string, age int) error {
+	if err := validateRegistration(name, email, age); err != nil {
+		return fmt.Errorf("register user: %w", err)
+	}
+
+	for _, u := range web.Users.List() {
+		if strings.EqualFold(u.Email, email) {
+			return fmt.Errorf("register %s: %w", email, ErrEmailTaken)
+		}
+	}
+	return nil
+}
+
+// ============ 5. MAPPING ERRORS TO HTTP STATUS CODES ============
+// Handlers should not inspect error strings - they ask what KIND of error it is.
+func statusForError(err error) int {
+	var validationErr basics.ValidationError
+	var fieldErrs web.FieldErrors // course 18
+
+	switch {
+	case errors.Is(err, ErrUserNotFound), errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, ErrEmailTaken):
+		return http.StatusConflict
+	case errors.As(err, &validationErr), errors.As(err, &fieldErrs):
+		return http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError sends the APIResponse envelope from course 6 with the mapped status.
+// Internal errors are hidden from the client - they may contain private details.
+func writeError(w http.ResponseWriter, err error) {
+	status := statusForError(err)
+	message := err.Error()
+	if status == http.StatusInternalServerError {
+		message = "internal server error"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(web.APIResponse{
+		Success: false,
+		Error:   message,
+	})
+}
+
+// ============ 6. PANIC AT THE BOUNDARY ============
+// Panics are for programmer mistakes, not for expected failures.
+// A boundary (HTTP handler, goroutine, plugin call) may turn them into errors.
+func runSafely(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("recovered from panic: %v", r)
+		}
+	}()
+	fn()
+	return nil
+}
+
+// ============ COURSE SIXTEEN MAIN FUNCTION ============
+func CourseSixteen(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 16)
+
+	l.Section("wrapping")
+
+	_, err := loadUserProfile(42)
+	l.Printf("Error: %v\n", err)
+	l.Println("Chain (outermost first):")
+	printErrorChain(l.Printer, err)
+
+	// %v would flatten the cause into text - the chain is lost
+	flattened := fmt.Errorf("load profile: %v", ErrUserNotFound)
+	l.Printf("Wrapped with %%w, errors.Is finds the sentinel: %v\n", errors.Is(err, ErrUserNotFound))
+	l.Printf("Formatted with %%v, errors.Is finds the sentinel: %v\n", errors.Is(flattened, ErrUserNotFound))
+
+	l.Section("sentinel-errors-errors")
+
+	l.Printf("err == ErrUserNotFound:          %v (the wrapper is a different value)\n", err == ErrUserNotFound)
+	l.Printf("errors.Is(err, ErrUserNotFound): %v (searches the whole chain)\n", errors.Is(err, ErrUserNotFound))
+
+	if _, err := loadUserProfile(1); err == nil {
+		l.Println("User 1 loaded without error")
+	}
+
+	l.Section("errors-as")
+
+	_, err = lookupEmail(99)
+	wrapped := fmt.Errorf("send newsletter: %w", err)
+
+	var queryErr *QueryError
+	if errors.As(wrapped, &queryErr) {
+		l.Printf("Failed query: %s\n", queryErr.Query)
+	}
+	l.Printf("Still a sql.ErrNoRows underneath: %v\n", errors.Is(wrapped, sql.ErrNoRows))
+
+	l.Section("errors-join")
+
+	err = registerUser("", "not-an-email", -5)
+	l.Printf("Error:\n%v\n", err)
+
+	var validationErr basics.ValidationError
+	if errors.As(err, &validationErr) {
+		l.Printf("First validation error is for field %q\n", validationErr.Field)
+	}
+
+	err = registerUser("Alicia", "alice@example.com", 30)
+	l.Printf("Duplicate email: %v (is ErrEmailTaken: %v)\n", err, errors.Is(err, ErrEmailTaken))
+
+	l.Section("http-status-mapping")
+
+	_, notFound := loadUserProfile(42)
+	_, noRows := lookupEmail(42)
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{"user not found", notFound},
+		{"no rows", noRows},
+		{"email taken", registerUser("Bob", "bob@example.com", 25)},
+		{"validation", registerUser("", "x", 20)},
+		{"timeout", fmt.Errorf("fetch orders: %w", context.DeadlineExceeded)},
+		{"unexpected", errors.New("disk on fire")},
+	}
+
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		writeError(rec, c.err)
+		l.Printf("%-15s -> %d %s\n", c.name, rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+
+	l.Section("when-panic")
+
+	err = runSafely(func() {
+		var profiles map[string]web.User
+		profiles["alice"] = web.User{} // assignment to nil map - a programmer error
+	})
+	l.Printf("Boundary turned the panic into an error: %v\n", err)
+
+	err = runSafely(func() {})
+	l.Printf("No panic: err = %v\n", err)
+
+	l.End()
+	return nil
+}
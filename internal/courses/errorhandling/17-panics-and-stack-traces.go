@@ -0,0 +1,225 @@
+package errorhandling
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/pkg/middleware"
+)
+
+// COURSE 17: DEFER, PANIC, AND STACK TRACES IN PRODUCTION
+// Topics covered:
+// 1. Defer ordering and argument evaluation
+// 2. recover() rules - where it works and where it doesn't
+// 3. Capturing stack traces with debug.Stack
+// 4. Panic-recovery HTTP middleware with structured 500 responses
+// 5. Panics inside goroutines
+// 6. Re-panicking and http.ErrAbortHandler
+// 7. Testing the recovery path (see 17-panics-and-stack-traces_test.go)
+
+// ============ 1. DEFER SEMANTICS ============
+// Arguments of a deferred call are evaluated when defer runs, not when the call runs
+func deferArguments(out *demo.Printer) {
+	x := 1
+	defer out.Printf("deferred x = %d (evaluated at defer time)\n", x)
+	x = 2
+	out.Printf("current x  = %d\n", x)
+}
+
+// A deferred closure can change named results - this is how recover returns errors
+func deferChangesResult() (result string) {
+	defer func() {
+		result = "changed by defer"
+	}()
+	return "original"
+}
+
+// ============ 2. RECOVER RULES ============
+// recover only stops a panic when called DIRECTLY by a deferred function
+func recoverTooDeep() (recovered bool) {
+	defer func() {
+		recovered = helperThatRecovers() // recover() inside a nested call returns nil
+		if !recovered {
+			recover() // stop the panic so the demo can continue
+		}
+	}()
+	panic("boom")
+}
+
+func helperThatRecovers() bool {
+	return recover() != nil
+}
+
+// ============ 3. STACK TRACES ============
+// PanicReport is what we keep about a panic: its value and where it happened.
+type PanicReport struct {
+	Value interface{}
+	Stack string
+}
+
+// capturePanic runs fn and returns a report if it panicked
+func capturePanic(fn func()) (report *PanicReport) {
+	defer func() {
+		if r := recover(); r != nil {
+			// debug.Stack() must be called inside the deferred function,
+			// while the panicking frames are still on the stack
+			report = &PanicReport{Value: r, Stack: string(debug.Stack())}
+		}
+	}()
+	fn()
+	return nil
+}
+
+func indexOutOfRange() {
+	numbers := []int{1, 2, 3}
+	i := 5
+	fmt.Println(numbers[i])
+}
+
+// ============ 4. RECOVERY MIDDLEWARE ============
+// recoverWithStack turns panics into structured 500 responses and logs the
+// full stack trace so the bug can be found later. middleware.Recover in
+// pkg/middleware is the same idea, packaged for reuse.
+func recoverWithStack(logger *log.Logger) middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+
+				// http.ErrAbortHandler is how handlers deliberately abort a
+				// response - net/http expects to see it, so pass it on
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				logger.Printf("panic serving %s %s: %v\n%s", r.Method, r.URL.Path, rec, debug.Stack())
+
+				// Clients get a generic message - never the panic value or stack
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(web.APIResponse{
+					Success: false,
+					Message: "the server hit an unexpected error",
+					Error:   "internal server error",
+				})
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Handler with a bug: it panics for ID 0
+func buggyUserHandler(w http.ResponseWriter, r *http.Request) {
+	var user *web.User
+	if r.URL.Query().Get("id") != "0" {
+		u, _ := web.Users.Get(1)
+		user = &u
+	}
+	fmt.Fprintf(w, "Hello, %s", user.Name) // nil pointer dereference when id=0
+}
+
+// ============ 5. PANICS IN GOROUTINES ============
+// A recover in main does NOT catch a panic from another goroutine - an
+// unrecovered goroutine panic crashes the whole program. Every goroutine
+// that might panic needs its own deferred recover.
+func goSafely(wg *sync.WaitGroup, errs chan<- error, fn func()) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				errs <- fmt.Errorf("goroutine panicked: %v", r)
+			}
+		}()
+		fn()
+	}()
+}
+
+// ============ COURSE SEVENTEEN MAIN FUNCTION ============
+func CourseSeventeen(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 17)
+
+	l.Section("defer-semantics")
+	deferArguments(l.Printer)
+	l.Printf("deferChangesResult() = %q\n", deferChangesResult())
+
+	l.Section("recover-rules")
+	l.Printf("recover() outside a panic returns: %v\n", recover())
+	l.Printf("recover() in a helper called by defer stopped the panic: %v\n", recoverTooDeep())
+	l.Resume()
+
+	l.Section("stack-traces")
+	report := capturePanic(indexOutOfRange)
+	l.Printf("Panic value: %v\n", report.Value)
+	l.Println("Stack trace (first lines):")
+	for _, line := range firstLines(report.Stack, 8) {
+		l.Printf("  %s\n", line)
+	}
+	l.Printf("Frame that panicked is in the trace: %v\n", strings.Contains(report.Stack, "errorhandling.indexOutOfRange"))
+
+	l.Section("recovery-middleware")
+
+	var logs strings.Builder
+	logger := log.New(&logs, "[panic] ", 0)
+	handler := recoverWithStack(logger)(http.HandlerFunc(buggyUserHandler))
+
+	for _, id := range []string{"1", "0"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?id="+id, nil))
+		l.Printf("GET /users?id=%s -> %d %s\n", id, rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+	l.Println("Server log (first lines):")
+	for _, line := range firstLines(logs.String(), 4) {
+		l.Printf("  %s\n", line)
+	}
+
+	l.Section("panics-goroutines")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	goSafely(&wg, errs, func() { l.Println("worker 1 finished") })
+	goSafely(&wg, errs, func() { panic("worker 2 hit a bug") })
+	goSafely(&wg, errs, func() {
+		var m map[string]int
+		m["x"]++
+	})
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		l.Printf("collected: %v\n", err)
+	}
+
+	l.Section("production-checklist")
+
+	l.End()
+	return nil
+}
+
+// firstLines returns at most n non-empty lines of s
+func firstLines(s string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line == "" {
+			continue
+		}
+		if len(lines) == n {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
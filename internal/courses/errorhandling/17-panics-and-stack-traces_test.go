@@ -0,0 +1,143 @@
+package errorhandling
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+)
+
+func TestRecoverWithStack(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		wantStatus int
+		wantLog    bool
+	}{
+		{
+			name: "no panic passes through",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			},
+			wantStatus: http.StatusOK,
+			wantLog:    false,
+		},
+		{
+			name: "string panic becomes 500",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic("something broke")
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantLog:    true,
+		},
+		{
+			name:       "runtime error becomes 500",
+			handler:    buggyUserHandler,
+			wantStatus: http.StatusInternalServerError,
+			wantLog:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs strings.Builder
+			handler := recoverWithStack(log.New(&logs, "", 0))(tt.handler)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?id=0", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := logs.Len() > 0; got != tt.wantLog {
+				t.Errorf("logged = %v, want %v (log: %q)", got, tt.wantLog, logs.String())
+			}
+		})
+	}
+}
+
+func TestRecoverWithStack_ResponseAndLog(t *testing.T) {
+	var logs strings.Builder
+	handler := recoverWithStack(log.New(&logs, "", 0))(http.HandlerFunc(buggyUserHandler))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?id=0", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var resp web.APIResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	if resp.Success || resp.Error != "internal server error" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	// The client must not see internals, the log must
+	if strings.Contains(rec.Body.String(), "nil pointer") {
+		t.Errorf("panic value leaked to client: %s", rec.Body.String())
+	}
+	for _, want := range []string{"GET /users", "nil pointer dereference", "buggyUserHandler"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log does not contain %q:\n%s", want, logs.String())
+		}
+	}
+}
+
+func TestRecoverWithStack_RepanicsErrAbortHandler(t *testing.T) {
+	handler := recoverWithStack(log.New(&strings.Builder{}, "", 0))(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		},
+	))
+
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", r)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	t.Error("expected ErrAbortHandler to be re-panicked")
+}
+
+func TestCapturePanic(t *testing.T) {
+	if report := capturePanic(func() {}); report != nil {
+		t.Errorf("capturePanic(no panic) = %+v, want nil", report)
+	}
+
+	report := capturePanic(indexOutOfRange)
+	if report == nil {
+		t.Fatal("capturePanic did not report the panic")
+	}
+	if !strings.Contains(report.Stack, "indexOutOfRange") {
+		t.Errorf("stack does not include the panicking function:\n%s", report.Stack)
+	}
+}
+
+func TestGoSafely(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+
+	goSafely(&wg, errs, func() {})
+	goSafely(&wg, errs, func() { panic("worker failed") })
+	wg.Wait()
+	close(errs)
+
+	var got []error
+	for err := range errs {
+		got = append(got, err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(got), got)
+	}
+	if !strings.Contains(got[0].Error(), "worker failed") {
+		t.Errorf("error = %v, want it to mention the panic value", got[0])
+	}
+}
@@ -0,0 +1,867 @@
+package fileio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 5: FILE HANDLING AND I/O
+// Topics covered:
+// 1. Reading files
+// 2. Writing files
+// 3. Appending to files
+// 4. Reading line by line
+// 5. File information
+// 6. Directory operations
+// 7. Copying files
+// 8. Working with paths
+// 9. Buffered I/O
+// 10. Binary data: encoding/binary and byte order
+// 11. Fixed-size records and reading one at an offset
+// 12. A small file format with a header and a checksum
+// 13. Walking a directory tree with filepath.WalkDir
+// 14. Finding files by pattern, and skipping directories
+// 15. Symbolic links
+// 16. Walking in parallel with a worker pool
+
+// ============ 1. READ ENTIRE FILE ============
+func readFileContents(filename string) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ============ 2. WRITE FILE ============
+func writeToFile(filename string, content string) error {
+	// Create or truncate file
+	return os.WriteFile(filename, []byte(content), 0644)
+}
+
+// ============ 3. APPEND TO FILE ============
+func appendToFile(filename string, content string) error {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(content)
+	return err
+}
+
+// ============ 4. READ LINE BY LINE ============
+func readLineByLine(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// ============ 5. READ WITH BUFFER ============
+func readWithBuffer(filename string, bufferSize int) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, bufferSize)
+	var result strings.Builder
+
+	for {
+		chunk, err := reader.ReadString('\n')
+		result.WriteString(chunk)
+
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+	}
+
+	return result.String(), nil
+}
+
+// ============ 6. FILE INFORMATION ============
+func getFileInfo(out *demo.Printer, filename string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	out.Printf("Filename: %s\n", info.Name())
+	out.Printf("Size: %d bytes\n", info.Size())
+	out.Printf("Modified: %v\n", info.ModTime())
+	out.Printf("Is Directory: %v\n", info.IsDir())
+	out.Printf("Permissions: %v\n", info.Mode())
+
+	return nil
+}
+
+// ============ 7. CHECK IF FILE EXISTS ============
+func fileExists(filename string) bool {
+	_, err := os.Stat(filename)
+	return err == nil
+}
+
+// ============ 8. LIST DIRECTORY CONTENTS ============
+func listDirectory(dirPath string) ([]string, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		files = append(files, entry.Name())
+	}
+
+	return files, nil
+}
+
+// ============ 9. CREATE DIRECTORY ============
+func createDirectory(dirPath string) error {
+	return os.MkdirAll(dirPath, 0755)
+}
+
+// ============ 10. COPY FILE ============
+func copyFile(src, dst string) error {
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	destination, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer destination.Close()
+
+	_, err = io.Copy(destination, source)
+	return err
+}
+
+// ============ 11. DELETE FILE ============
+func deleteFile(filename string) error {
+	return os.Remove(filename)
+}
+
+// ============ 12. WORK WITH PATHS ============
+func pathOperations(out *demo.Printer, filePath string) {
+	out.Printf("Full path: %s\n", filePath)
+	out.Printf("Directory: %s\n", filepath.Dir(filePath))
+	out.Printf("Filename: %s\n", filepath.Base(filePath))
+	out.Printf("Extension: %s\n", filepath.Ext(filePath))
+
+	// Join paths correctly for the OS
+	newPath := filepath.Join(".", "data", "file.txt")
+	out.Printf("Joined path: %s\n", newPath)
+}
+
+// ============ 13. CSV-LIKE FILE OPERATIONS ============
+func parseCSVFile(filename string) ([][]string, error) {
+	lines, err := readLineByLine(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	for _, line := range lines {
+		fields := strings.Split(line, ",")
+		records = append(records, fields)
+	}
+
+	return records, nil
+}
+
+// ============ 14. BYTE ORDER ============
+// A number bigger than a byte can be written most significant byte first
+// (big-endian, the order of network protocols) or least significant first
+// (little-endian, the order of x86 and ARM). A format picks one, and both
+// sides must use it.
+func byteOrders(out *demo.Printer, v uint32) {
+	big := binary.BigEndian.AppendUint32(nil, v)
+	little := binary.LittleEndian.AppendUint32(nil, v)
+	out.Printf("%#08x big-endian:    % x\n", v, big)
+	out.Printf("%#08x little-endian: % x\n", v, little)
+	out.Printf("Little-endian bytes read as big-endian: %#08x\n", binary.BigEndian.Uint32(little))
+}
+
+// ============ 15. FIXED-SIZE RECORDS ============
+// reading has only fixed-size fields, so encoding/binary can write it as
+// is: 20 bytes, no padding, whatever the platform. int and string have no
+// fixed size and are refused.
+type reading struct {
+	Sensor uint16
+	Flags  uint16
+	Time   int64 // Unix seconds
+	Value  float64
+}
+
+var readingSize = binary.Size(reading{})
+
+func writeReadings(filename string, readings []reading) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// binary.Write takes a slice of fixed-size values as well as one
+	if err := binary.Write(file, binary.LittleEndian, readings); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// readReadingAt reads the i-th record without reading those before it:
+// every record is readingSize bytes, so it starts at i*readingSize.
+func readReadingAt(file *os.File, i int) (reading, error) {
+	buf := make([]byte, readingSize)
+	if _, err := file.ReadAt(buf, int64(i*readingSize)); err != nil {
+		return reading{}, err
+	}
+	var r reading
+	_, err := binary.Decode(buf, binary.LittleEndian, &r)
+	return r, err
+}
+
+// ============ 16. A FILE FORMAT ============
+// A readings file is a header, the records, and a CRC-32 of everything
+// before it:
+//
+//	magic "RDNG" | version uint16 | count uint32 | count × reading | crc32 uint32
+//
+// The magic number tells a readings file from any other, the version lets
+// the format change, and the checksum catches a file that was damaged or
+// cut short. Everything is little-endian.
+type readingsHeader struct {
+	Magic   [4]byte
+	Version uint16
+	Count   uint32
+}
+
+const readingsVersion = 1
+
+var readingsMagic = [4]byte{'R', 'D', 'N', 'G'}
+
+var (
+	errNotReadings = errors.New("not a readings file")
+	errVersion     = errors.New("unsupported readings file version")
+	errChecksum    = errors.New("readings file checksum mismatch")
+)
+
+func writeReadingsFile(filename string, readings []reading) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	buf := bufio.NewWriter(file)
+	sum := crc32.NewIEEE()
+	w := io.MultiWriter(buf, sum) // the checksum sees every byte written
+
+	header := readingsHeader{Magic: readingsMagic, Version: readingsVersion, Count: uint32(len(readings))}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.LittleEndian, readings); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.LittleEndian, sum.Sum32()); err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// readReadingsFile checks the header before trusting the count, and the
+// checksum before returning anything. A file cut short is
+// io.ErrUnexpectedEOF.
+func readReadingsFile(filename string) ([]reading, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := bufio.NewReader(file)
+	sum := crc32.NewIEEE()
+	r := io.TeeReader(buf, sum) // the checksum sees every byte read
+
+	var header readingsHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, unexpectedEOF(filename, err)
+	}
+	if header.Magic != readingsMagic {
+		return nil, fmt.Errorf("%s: %w", filename, errNotReadings)
+	}
+	if header.Version != readingsVersion {
+		return nil, fmt.Errorf("%s: version %d: %w", filename, header.Version, errVersion)
+	}
+
+	// Don't allocate Count records up front: a damaged header could ask
+	// for four billion
+	var readings []reading
+	for range header.Count {
+		var rec reading
+		if err := binary.Read(r, binary.LittleEndian, &rec); err != nil {
+			return nil, unexpectedEOF(filename, err)
+		}
+		readings = append(readings, rec)
+	}
+
+	var want uint32
+	if err := binary.Read(buf, binary.LittleEndian, &want); err != nil {
+		return nil, unexpectedEOF(filename, err)
+	}
+	if sum.Sum32() != want {
+		return nil, fmt.Errorf("%s: %w", filename, errChecksum)
+	}
+	return readings, nil
+}
+
+// unexpectedEOF reports a file that ends before the format says it should.
+func unexpectedEOF(filename string, err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("%s: %w", filename, err)
+}
+
+// ============ 17. WALK A DIRECTORY TREE ============
+// filepath.WalkDir calls fn for root and everything under it, in lexical
+// order, each directory before what is in it. The fs.DirEntry it passes
+// comes from reading the directory, so the name and type cost nothing;
+// Info stats the file, and is only worth calling when the size or time is
+// needed. dirSizes adds up the files' sizes in each directory and those
+// under it, as du does.
+func dirSizes(root string) (map[string]int64, error) {
+	root = filepath.Clean(root)
+	sizes := map[string]int64{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err // stop; return nil instead to skip what can't be read
+		}
+		if d.IsDir() {
+			sizes[path] += 0 // listed even when empty
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil // symlinks, sockets, devices
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		for dir := path; dir != root; {
+			dir = filepath.Dir(dir)
+			sizes[dir] += info.Size()
+		}
+		return nil
+	})
+	return sizes, err
+}
+
+// ============ 18. FIND FILES ============
+// findFiles returns the files under root whose names match pattern, such
+// as "*.go" (see filepath.Match), without looking inside the directories
+// named in skip. Returning filepath.SkipDir for a directory skips it and
+// everything in it; filepath.SkipAll would end the walk.
+func findFiles(root, pattern string, skip ...string) ([]string, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err // filepath.ErrBadPattern, before walking anything
+	}
+	var found []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && slices.Contains(skip, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ok, _ := filepath.Match(pattern, d.Name()); ok {
+			found = append(found, path)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// ============ 19. SYMBOLIC LINKS ============
+// WalkDir reports a symlink as a symlink and never follows it, so a link
+// back up the tree can't send it round in circles. walkFollowingLinks
+// follows links to directories too, and so has to remember the real path
+// of every directory it has been in: a directory reached a second way,
+// through a link or a loop, is walked once.
+func walkFollowingLinks(root string) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		real, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			return err
+		}
+		if seen[real] {
+			return nil
+		}
+		seen[real] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			info, err := os.Stat(path) // Stat follows links; Lstat and DirEntry don't
+			if err != nil {
+				continue // a link to nothing
+			}
+			if info.IsDir() {
+				if err := walk(path); err != nil {
+					return err
+				}
+			} else {
+				files = append(files, path)
+			}
+		}
+		return nil
+	}
+	return files, walk(root)
+}
+
+// ============ 20. WALKING IN PARALLEL ============
+// lineCounts counts the lines of the files under root whose names match
+// pattern, with workers goroutines reading files. Walking is quick: it
+// only reads directories. Reading the files is where the time goes, so
+// one goroutine walks and sends the paths down a channel, and a pool of
+// workers reads them, as in course 4's worker pools.
+func lineCounts(root, pattern string, workers int) (map[string]int, error) {
+	type count struct {
+		path  string
+		lines int
+		err   error
+	}
+	paths := make(chan string)
+	counts := make(chan count)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for path := range paths {
+				lines, err := readLineByLine(path)
+				counts <- count{path, len(lines), err}
+			}
+		})
+	}
+
+	var walkErr error
+	go func() {
+		walkErr = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ok, _ := filepath.Match(pattern, d.Name()); ok && d.Type().IsRegular() {
+				paths <- path
+			}
+			return nil
+		})
+		close(paths)
+		wg.Wait()
+		close(counts) // after walkErr is set, so reading it below is safe
+	}()
+
+	result := map[string]int{}
+	var errs []error
+	for c := range counts {
+		if c.err != nil {
+			errs = append(errs, c.err)
+			continue
+		}
+		result[c.path] = c.lines
+	}
+	return result, errors.Join(append(errs, walkErr)...)
+}
+
+// makeTree creates the files under root, with their directories.
+func makeTree(root string, files map[string]string) error {
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := createDirectory(filepath.Dir(path)); err != nil {
+			return err
+		}
+		if err := writeToFile(path, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============ COURSE FIVE MAIN FUNCTION ============
+func CourseFive(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 5)
+
+	// Relative to the working directory: the runner gives each course run
+	// a fresh one, which it deletes afterwards
+	tempDir := "./temp"
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", tempDir, err)
+	}
+	defer os.RemoveAll(tempDir) // Cleanup after demo
+
+	l.Section("write-file")
+
+	testFile := filepath.Join(tempDir, "test.txt")
+	content := "Hello, Go!\nThis is a test file.\nWelcome to file handling!"
+
+	err := writeToFile(testFile, content)
+	if err != nil {
+		return err
+	}
+	l.Printf("✓ File written: %s\n\n", testFile)
+
+	l.Section("read-entire-file")
+
+	data, err := readFileContents(testFile)
+	if err != nil {
+		return err
+	}
+	l.Printf("File contents:\n%s\n\n", data)
+
+	l.Section("read-line-line")
+
+	lines, err := readLineByLine(testFile)
+	if err != nil {
+		return err
+	}
+	l.Println("Lines:")
+	for i, line := range lines {
+		l.Printf("  Line %d: %s\n", i+1, line)
+	}
+	l.Println()
+
+	l.Section("append-file")
+
+	appendContent := "\nAppended line 1\nAppended line 2"
+	err = appendToFile(testFile, appendContent)
+	if err != nil {
+		return err
+	}
+	l.Printf("✓ Content appended\n")
+	updatedData, err := readFileContents(testFile)
+	if err != nil {
+		return err
+	}
+	l.Printf("Updated contents:\n%s\n\n", updatedData)
+
+	l.Section("file-information")
+
+	if err := getFileInfo(l.Printer, testFile); err != nil {
+		return err
+	}
+
+	l.Section("check-if-file")
+
+	exists := fileExists(testFile)
+	l.Printf("File exists: %v\n", exists)
+
+	notExists := fileExists("nonexistent.txt")
+	l.Printf("Nonexistent file exists: %v\n", notExists)
+
+	l.Section("create-directory")
+
+	newDir := filepath.Join(tempDir, "subdir", "nested")
+	if err := createDirectory(newDir); err != nil {
+		return err
+	}
+	l.Printf("✓ Directory created: %s\n\n", newDir)
+
+	l.Section("list-directory")
+
+	files, err := listDirectory(tempDir)
+	if err != nil {
+		return err
+	}
+	l.Printf("Contents of %s:\n", tempDir)
+	for _, file := range files {
+		l.Printf("  - %s\n", file)
+	}
+	l.Println()
+
+	l.Section("copy-file")
+
+	copiedFile := filepath.Join(tempDir, "test_copy.txt")
+	if err := copyFile(testFile, copiedFile); err != nil {
+		return err
+	}
+	l.Printf("✓ File copied from %s to %s\n", testFile, copiedFile)
+
+	exists = fileExists(copiedFile)
+	l.Printf("Copied file exists: %v\n\n", exists)
+
+	l.Section("path-operations")
+
+	examplePath := "/home/user/documents/report.pdf"
+	pathOperations(l.Printer, examplePath)
+
+	l.Section("csv-like-file")
+
+	csvFile := filepath.Join(tempDir, "data.csv")
+	csvContent := `Name,Age,City
+Alice,30,New York
+Bob,25,Los Angeles
+Charlie,35,Chicago`
+
+	if err := writeToFile(csvFile, csvContent); err != nil {
+		return err
+	}
+
+	records, err := parseCSVFile(csvFile)
+	if err != nil {
+		return err
+	}
+	l.Println("CSV Data:")
+	for i, record := range records {
+		l.Printf("  Row %d: %v\n", i+1, record)
+	}
+	l.Println()
+
+	l.Section("delete-file")
+
+	if err := deleteFile(copiedFile); err != nil {
+		return err
+	}
+	l.Printf("✓ File deleted: %s\n", copiedFile)
+	exists = fileExists(copiedFile)
+	l.Printf("File exists after deletion: %v\n\n", exists)
+
+	l.Section("byte-order")
+
+	byteOrders(l.Printer, 0x01020304)
+	l.Resume()
+
+	l.Section("fixed-records")
+
+	readings := []reading{
+		{Sensor: 1, Time: 1700000000, Value: 21.5},
+		{Sensor: 2, Time: 1700000060, Value: 19.25},
+		{Sensor: 1, Flags: 1, Time: 1700000120, Value: -3},
+	}
+	recordsFile := filepath.Join(tempDir, "readings.bin")
+	if err := writeReadings(recordsFile, readings); err != nil {
+		return err
+	}
+	info, err := os.Stat(recordsFile)
+	if err != nil {
+		return err
+	}
+	l.Printf("%d readings of %d bytes: %d bytes\n", len(readings), readingSize, info.Size())
+
+	file, err := os.Open(recordsFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	third, err := readReadingAt(file, 2)
+	if err != nil {
+		return err
+	}
+	l.Printf("Reading 2, at offset %d: %+v\n", 2*readingSize, third)
+	if _, err := readReadingAt(file, 3); err != nil {
+		l.Printf("Reading 3: %v\n", err)
+	}
+	l.Resume()
+
+	l.Section("file-format")
+
+	formatFile := filepath.Join(tempDir, "readings.rdng")
+	if err := writeReadingsFile(formatFile, readings); err != nil {
+		return err
+	}
+	raw, err := os.ReadFile(formatFile)
+	if err != nil {
+		return err
+	}
+	l.Printf("Header: % x\n", raw[:binary.Size(readingsHeader{})])
+	l.Printf("Checksum: % x (%d bytes in all)\n", raw[len(raw)-4:], len(raw))
+
+	back, err := readReadingsFile(formatFile)
+	if err != nil {
+		return err
+	}
+	l.Printf("Read back %d readings, the same: %v\n", len(back), slices.Equal(back, readings))
+
+	damaged := bytes.Clone(raw)
+	damaged[20] ^= 0x01 // flip one bit in the first reading
+	for _, bad := range []struct {
+		name string
+		data []byte
+	}{
+		{"one bit flipped", damaged},
+		{"cut short", raw[:len(raw)-10]},
+		{"a text file", []byte(content)},
+	} {
+		name := filepath.Join(tempDir, "bad.rdng")
+		if err := os.WriteFile(name, bad.data, 0644); err != nil {
+			return err
+		}
+		_, err := readReadingsFile(name)
+		l.Printf("%s: %v\n", bad.name, err)
+	}
+	l.Resume()
+
+	l.Section("walk-dir")
+
+	project := filepath.Join(tempDir, "project")
+	err = makeTree(project, map[string]string{
+		"go.mod":                       "module example.com/project\n",
+		"main.go":                      "package main\n\nfunc main() {\n\tweb.Serve()\n}\n",
+		"README.md":                    "# Project\n\nA small web service.\n",
+		"internal/store/store.go":      "package store\n\ntype Store struct{}\n",
+		"internal/store/store_test.go": "package store\n",
+		"internal/web/web.go":          "package web\n\nfunc Serve() {}\n",
+		"internal/web/static/app.css":  "body { margin: 0; }\n",
+		".git/HEAD":                    "ref: refs/heads/main\n",
+		"vendor/lib/lib.go":            "package lib\n\n// vendored\n",
+	})
+	if err != nil {
+		return err
+	}
+	sizes, err := dirSizes(project)
+	if err != nil {
+		return err
+	}
+	for _, dir := range slices.Sorted(maps.Keys(sizes)) {
+		rel, _ := filepath.Rel(tempDir, dir)
+		l.Printf("  %4d  %s\n", sizes[dir], filepath.ToSlash(rel))
+	}
+	l.Resume()
+
+	l.Section("find-files")
+
+	for _, q := range []struct {
+		pattern string
+		skip    []string
+	}{
+		{"*.go", nil},
+		{"*.go", []string{".git", "vendor"}},
+		{"*_test.go", nil},
+		{"*.[ch]ss", nil},
+		{"[", nil},
+	} {
+		query := q.pattern
+		if len(q.skip) > 0 {
+			query += ", skipping " + strings.Join(q.skip, " and ")
+		}
+		found, err := findFiles(project, q.pattern, q.skip...)
+		if err != nil {
+			l.Printf("%s: %v\n", query, err)
+			continue
+		}
+		l.Printf("%s:\n", query)
+		for _, f := range found {
+			rel, _ := filepath.Rel(project, f)
+			l.Printf("  %s\n", filepath.ToSlash(rel))
+		}
+	}
+	globbed, err := filepath.Glob(filepath.Join(project, "internal", "*", "*.go"))
+	if err != nil {
+		return err
+	}
+	l.Printf("Glob internal/*/*.go: %d files\n", len(globbed))
+	l.Resume()
+
+	l.Section("symlinks")
+
+	shared := filepath.Join(tempDir, "shared")
+	if err := makeTree(shared, map[string]string{"util.go": "package shared\n"}); err != nil {
+		return err
+	}
+	// shared is outside the project, loop leads back up to it
+	links := []struct{ target, name string }{
+		{filepath.Join("..", "shared"), filepath.Join(project, "shared")},
+		{filepath.Join("..", ".."), filepath.Join(project, "internal", "web", "loop")},
+	}
+	for _, link := range links {
+		if err := os.Symlink(link.target, link.name); err != nil {
+			l.Printf("No symlinks here (%v): on Windows they need Developer Mode\n", err)
+			break
+		}
+	}
+	err = filepath.WalkDir(project, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type()&fs.ModeSymlink != 0 {
+			target, _ := os.Readlink(path)
+			rel, _ := filepath.Rel(project, path)
+			l.Printf("WalkDir: %s is a link to %s, not followed\n", filepath.ToSlash(rel), filepath.ToSlash(target))
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	followed, err := walkFollowingLinks(project)
+	if err != nil {
+		return err
+	}
+	l.Printf("Following links: %d files, shared/util.go among them: %v\n",
+		len(followed), slices.Contains(followed, filepath.Join(project, "shared", "util.go")))
+	l.Resume()
+
+	l.Section("parallel-walk")
+
+	for _, workers := range []int{1, 4} {
+		counts, err := lineCounts(project, "*.go", workers)
+		if err != nil {
+			return err
+		}
+		total := 0
+		for _, n := range counts {
+			total += n
+		}
+		l.Printf("%d worker(s): %d lines in %d Go files\n", workers, total, len(counts))
+	}
+	l.Resume()
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,682 @@
+package fileio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// Run with: go test ./internal/courses/fileio -v
+//
+// Course 10 uses these tests as its worked example: each helper gets a
+// table of cases, every case a fresh t.TempDir, and the error paths are
+// checked with errors.Is rather than by matching messages.
+
+// writeFiles creates the named files under dir, with their contents.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// unreadable creates a file its owner can't read, or skips the test where
+// permissions don't stop anyone: as root, or on Windows.
+func unreadable(t *testing.T, dir string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("file permissions don't apply here")
+	}
+	path := filepath.Join(dir, "secret.txt")
+	if err := os.WriteFile(path, []byte("secret\n"), 0o000); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadFileContents(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		read    string
+		want    string
+		wantErr error
+	}{
+		{"text", map[string]string{"a.txt": "Hello\nGo\n"}, "a.txt", "Hello\nGo\n", nil},
+		{"empty", map[string]string{"a.txt": ""}, "a.txt", "", nil},
+		{"missing file", nil, "nope.txt", "", fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			got, err := readFileContents(filepath.Join(dir, tt.read))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("permission denied", func(t *testing.T) {
+		if _, err := readFileContents(unreadable(t, t.TempDir())); !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("err = %v, want %v", err, fs.ErrPermission)
+		}
+	})
+}
+
+func TestWriteAndAppendFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		existed string // "" for no file before
+		write   string
+		appends []string
+		want    string
+	}{
+		{"write creates", "", "one\n", nil, "one\n"},
+		{"write truncates", "old contents\n", "new\n", nil, "new\n"},
+		{"append adds", "", "one\n", []string{"two\n", "three\n"}, "one\ntwo\nthree\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out.txt")
+			if tt.existed != "" {
+				writeFiles(t, filepath.Dir(path), map[string]string{"out.txt": tt.existed})
+			}
+			if err := writeToFile(path, tt.write); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.appends {
+				if err := appendToFile(path, s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got, _ := os.ReadFile(path); string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("append creates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "log.txt")
+		if err := appendToFile(path, "first\n"); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := os.ReadFile(path); string(got) != "first\n" {
+			t.Errorf("got %q", got)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "no", "such", "dir.txt")
+		if err := writeToFile(path, "x"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("writeToFile: err = %v, want %v", err, fs.ErrNotExist)
+		}
+		if err := appendToFile(path, "x"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("appendToFile: err = %v, want %v", err, fs.ErrNotExist)
+		}
+	})
+}
+
+func TestReadLineByLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"lines", "one\ntwo\nthree\n", []string{"one", "two", "three"}},
+		{"no final newline", "one\ntwo", []string{"one", "two"}},
+		{"windows line endings", "one\r\ntwo\r\n", []string{"one", "two"}},
+		{"blank lines kept", "one\n\nthree\n", []string{"one", "", "three"}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"in.txt": tt.content})
+			got, err := readLineByLine(filepath.Join(dir, "in.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("line too long", func(t *testing.T) {
+		// bufio.Scanner gives up on lines over 64KB rather than return
+		// half of one
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"in.txt": strings.Repeat("x", 70_000) + "\n"})
+		if _, err := readLineByLine(filepath.Join(dir, "in.txt")); err == nil {
+			t.Error("want an error for a 70KB line")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := readLineByLine(filepath.Join(t.TempDir(), "nope.txt")); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("err = %v, want %v", err, fs.ErrNotExist)
+		}
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		if _, err := readLineByLine(unreadable(t, t.TempDir())); !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("err = %v, want %v", err, fs.ErrPermission)
+		}
+	})
+}
+
+func TestReadWithBuffer(t *testing.T) {
+	long := strings.Repeat("abcdefgh", 100) + "\n" // longer than any buffer below
+	tests := []struct {
+		name       string
+		content    string
+		bufferSize int
+	}{
+		{"small buffer", "one\ntwo\n", 16},
+		{"line longer than the buffer", long + "short\n", 16},
+		{"no final newline", "one\ntwo", 4096},
+		{"empty", "", 4096},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"in.txt": tt.content})
+			got, err := readWithBuffer(filepath.Join(dir, "in.txt"), tt.bufferSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.content {
+				t.Errorf("got %q, want the whole file back", got)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := readWithBuffer(filepath.Join(t.TempDir(), "nope.txt"), 16); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("err = %v, want %v", err, fs.ErrNotExist)
+		}
+	})
+}
+
+func TestFileInfoAndExists(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "12345"})
+
+	tests := []struct {
+		name       string
+		path       string
+		exists     bool
+		wantOutput []string
+	}{
+		{"file", filepath.Join(dir, "a.txt"), true, []string{"Filename: a.txt", "Size: 5 bytes", "Is Directory: false"}},
+		{"directory", dir, true, []string{"Is Directory: true"}},
+		{"missing", filepath.Join(dir, "nope.txt"), false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fileExists(tt.path); got != tt.exists {
+				t.Errorf("fileExists = %v, want %v", got, tt.exists)
+			}
+
+			var out strings.Builder
+			err := getFileInfo(demo.NewPrinter(&out), tt.path)
+			if !tt.exists {
+				if !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("getFileInfo: err = %v, want %v", err, fs.ErrNotExist)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("no %q in:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestDirectories(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		list    string
+		want    []string
+		wantErr error
+	}{
+		{"files and folders, sorted", map[string]string{"b.txt": "", "a.txt": "", "sub/c.txt": ""}, ".", []string{"a.txt", "b.txt", "sub"}, nil},
+		{"not recursive", map[string]string{"sub/c.txt": ""}, "sub", []string{"c.txt"}, nil},
+		{"empty", nil, ".", nil, nil},
+		{"missing", nil, "nope", nil, fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			got, err := listDirectory(filepath.Join(dir, tt.list))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("create nested", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "a", "b", "c")
+		for range 2 { // MkdirAll is fine with a folder that exists
+			if err := createDirectory(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			t.Errorf("%s isn't a directory (%v)", path, err)
+		}
+	})
+
+	t.Run("create over a file", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"taken": ""})
+		if err := createDirectory(filepath.Join(dir, "taken", "sub")); err == nil {
+			t.Error("want an error making a folder inside a file")
+		}
+	})
+}
+
+func TestCopyFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		src     string
+		dst     string
+		want    string
+		wantErr error
+	}{
+		{"copy", map[string]string{"src.txt": "data\n"}, "src.txt", "dst.txt", "data\n", nil},
+		{"overwrites", map[string]string{"src.txt": "new", "dst.txt": "old and longer"}, "src.txt", "dst.txt", "new", nil},
+		{"binary", map[string]string{"src.bin": "\x00\xff\x00"}, "src.bin", "dst.bin", "\x00\xff\x00", nil},
+		{"missing source", nil, "nope.txt", "dst.txt", "", fs.ErrNotExist},
+		{"missing destination folder", map[string]string{"src.txt": "data"}, "src.txt", "no/dst.txt", "", fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			err := copyFile(filepath.Join(dir, tt.src), filepath.Join(dir, tt.dst))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got, _ := os.ReadFile(filepath.Join(dir, tt.dst)); string(got) != tt.want {
+				t.Errorf("copy holds %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("permission denied", func(t *testing.T) {
+		dir := t.TempDir()
+		err := copyFile(unreadable(t, dir), filepath.Join(dir, "copy.txt"))
+		if !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("err = %v, want %v", err, fs.ErrPermission)
+		}
+		if fileExists(filepath.Join(dir, "copy.txt")) {
+			t.Error("a failed copy left a destination file")
+		}
+	})
+}
+
+func TestDeleteFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.txt": "x"})
+	path := filepath.Join(dir, "a.txt")
+
+	if err := deleteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if fileExists(path) {
+		t.Error("the file is still there")
+	}
+	if err := deleteFile(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("deleting it again: err = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestPathOperations(t *testing.T) {
+	var out strings.Builder
+	pathOperations(demo.NewPrinter(&out), filepath.Join("data", "reports", "q1.csv"))
+	for _, want := range []string{
+		"Directory: " + filepath.Join("data", "reports"),
+		"Filename: q1.csv",
+		"Extension: .csv",
+		"Joined path: " + filepath.Join("data", "file.txt"),
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("no %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestParseCSVFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    [][]string
+	}{
+		{"rows", "name,age\nAlice,30\n", [][]string{{"name", "age"}, {"Alice", "30"}}},
+		{"ragged rows", "a,b,c\nd\n", [][]string{{"a", "b", "c"}, {"d"}}},
+		{"empty fields", "a,,c\n", [][]string{{"a", "", "c"}}},
+		// A plain split knows nothing of quoting: encoding/csv does
+		{"quotes not understood", "\"Smith, J\",42\n", [][]string{{"\"Smith", " J\"", "42"}}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"in.csv": tt.content})
+			got, err := parseCSVFile(filepath.Join(dir, "in.csv"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := parseCSVFile(filepath.Join(t.TempDir(), "nope.csv")); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("err = %v, want %v", err, fs.ErrNotExist)
+		}
+	})
+}
+
+func TestByteOrders(t *testing.T) {
+	var buf strings.Builder
+	byteOrders(demo.NewPrinter(&buf), 0x01020304)
+	for _, want := range []string{"big-endian:    01 02 03 04", "little-endian: 04 03 02 01", "big-endian: 0x04030201"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output has no %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+var testReadings = []reading{
+	{Sensor: 1, Time: 1700000000, Value: 21.5},
+	{Sensor: 2, Time: 1700000060, Value: 19.25},
+	{Sensor: 1, Flags: 1, Time: 1700000120, Value: -3},
+}
+
+func TestReadings(t *testing.T) {
+	if readingSize != 20 {
+		t.Errorf("readingSize = %d, want 20", readingSize)
+	}
+	name := filepath.Join(t.TempDir(), "readings.bin")
+	if err := writeReadings(name, testReadings); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != len(testReadings)*readingSize {
+		t.Errorf("file is %d bytes, want %d", len(data), len(testReadings)*readingSize)
+	}
+	// Little-endian: the first sensor ID is 01 00
+	if data[0] != 1 || data[1] != 0 {
+		t.Errorf("file starts % x, want 01 00", data[:2])
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// Out of order, to show no record depends on those before it
+	for _, i := range []int{2, 0, 1} {
+		got, err := readReadingAt(file, i)
+		if err != nil || got != testReadings[i] {
+			t.Errorf("readReadingAt(%d) = %+v, %v, want %+v", i, got, err, testReadings[i])
+		}
+	}
+	if _, err := readReadingAt(file, 3); !errors.Is(err, io.EOF) {
+		t.Errorf("readReadingAt past the end: err = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestReadingsFile(t *testing.T) {
+	for _, readings := range [][]reading{testReadings, nil} {
+		name := filepath.Join(t.TempDir(), "readings.rdng")
+		if err := writeReadingsFile(name, readings); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readReadingsFile(name)
+		if err != nil || !slices.Equal(got, readings) {
+			t.Errorf("read back %d readings = %+v, %v", len(readings), got, err)
+		}
+	}
+
+	name := filepath.Join(t.TempDir(), "readings.rdng")
+	if err := writeReadingsFile(name, testReadings); err != nil {
+		t.Fatal(err)
+	}
+	good, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// change returns a copy of the file with one byte changed
+	change := func(i int, b byte) []byte {
+		data := bytes.Clone(good)
+		data[i] = b
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"bad magic", change(0, 'X'), errNotReadings},
+		{"newer version", change(4, 2), errVersion},
+		{"damaged record", change(30, good[30]^0xff), errChecksum},
+		{"damaged checksum", change(len(good)-1, good[len(good)-1]^0xff), errChecksum},
+		// A count larger than the file: the records run out first
+		{"count too large", change(9, 0xff), io.ErrUnexpectedEOF},
+		{"no checksum", good[:len(good)-4], io.ErrUnexpectedEOF},
+		{"half a record", good[:25], io.ErrUnexpectedEOF},
+		{"half a header", good[:5], io.ErrUnexpectedEOF},
+		{"empty", nil, io.ErrUnexpectedEOF},
+		{"text", []byte("Hello, Go!\n"), errNotReadings},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "bad.rdng")
+			if err := os.WriteFile(name, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readReadingsFile(name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != nil {
+				t.Errorf("returned %d readings with the error", len(got))
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := readReadingsFile(filepath.Join(t.TempDir(), "nope.rdng")); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("err = %v, want %v", err, fs.ErrNotExist)
+		}
+	})
+}
+
+// testTree is a small project to walk.
+var testTree = map[string]string{
+	"main.go":                 "package main\n\nfunc main() {}\n",
+	"README.md":               "# Project\n",
+	"internal/store/store.go": "package store\n",
+	"internal/store/empty.go": "",
+	".git/HEAD":               "ref: refs/heads/main\n",
+	"vendor/lib/lib.go":       "package lib\n// vendored\n",
+}
+
+// rels returns paths relative to root, with slashes, to compare on any OS.
+func rels(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	var out []string
+	for _, p := range paths {
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, filepath.ToSlash(rel))
+	}
+	return out
+}
+
+func TestDirSizes(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, testTree)
+	if err := os.Mkdir(filepath.Join(root, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sizes, err := dirSizes(root + string(filepath.Separator)) // an unclean root works too
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{
+		".":              29 + 10 + 14 + 21 + 24,
+		".git":           21,
+		"empty":          0,
+		"internal":       14,
+		"internal/store": 14,
+		"vendor":         24,
+		"vendor/lib":     24,
+	}
+	got := map[string]int64{}
+	for dir, size := range sizes {
+		rel, _ := filepath.Rel(root, dir)
+		got[filepath.ToSlash(rel)] = size
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("sizes = %v, want %v", got, want)
+	}
+
+	if _, err := dirSizes(filepath.Join(root, "nope")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing root: err = %v, want %v", err, fs.ErrNotExist)
+	}
+}
+
+func TestFindFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, testTree)
+	tests := []struct {
+		pattern string
+		skip    []string
+		want    []string
+	}{
+		{"*.go", nil, []string{"internal/store/empty.go", "internal/store/store.go", "main.go", "vendor/lib/lib.go"}},
+		{"*.go", []string{"vendor", ".git"}, []string{"internal/store/empty.go", "internal/store/store.go", "main.go"}},
+		// skip names a directory at any depth, but never the root
+		{"*.go", []string{"store", filepath.Base(root)}, []string{"main.go", "vendor/lib/lib.go"}},
+		{"HEAD", nil, []string{".git/HEAD"}},
+		{"s*.go", nil, []string{"internal/store/store.go"}},
+		{"*.txt", nil, nil},
+	}
+	for _, tt := range tests {
+		found, err := findFiles(root, tt.pattern, tt.skip...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rels(t, root, found); !slices.Equal(got, tt.want) {
+			t.Errorf("findFiles(%q, skip %v) = %v, want %v", tt.pattern, tt.skip, got, tt.want)
+		}
+	}
+
+	if _, err := findFiles(root, "[", nil...); !errors.Is(err, filepath.ErrBadPattern) {
+		t.Errorf("bad pattern: err = %v, want %v", err, filepath.ErrBadPattern)
+	}
+}
+
+func TestWalkFollowingLinks(t *testing.T) {
+	tmp := t.TempDir()
+	root := filepath.Join(tmp, "project")
+	writeFiles(t, root, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	writeFiles(t, tmp, map[string]string{"shared/c.txt": "c"})
+	links := map[string]string{
+		"shared":   filepath.Join("..", "shared"),
+		"sub/loop": "..",
+		"sub/also": ".",
+		"dangling": "nowhere",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Skipf("can't create symlinks here: %v", err)
+		}
+	}
+
+	found, err := findFiles(root, "*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rels(t, root, found), []string{"a.txt", "sub/b.txt"}; !slices.Equal(got, want) {
+		t.Errorf("WalkDir, not following links: %v, want %v", got, want)
+	}
+
+	files, err := walkFollowingLinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each directory once, through the first path that reaches it
+	if got, want := rels(t, root, files), []string{"a.txt", "shared/c.txt", "sub/b.txt"}; !slices.Equal(got, want) {
+		t.Errorf("following links: %v, want %v", got, want)
+	}
+}
+
+func TestLineCounts(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, testTree)
+	want := map[string]int{"main.go": 3, "internal/store/store.go": 1, "internal/store/empty.go": 0, "vendor/lib/lib.go": 2}
+	for _, workers := range []int{1, 3, 10} {
+		counts, err := lineCounts(root, "*.go", workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]int{}
+		for path, n := range counts {
+			got[rels(t, root, []string{path})[0]] = n
+		}
+		if !maps.Equal(got, want) {
+			t.Errorf("%d workers: %v, want %v", workers, got, want)
+		}
+	}
+
+	if _, err := lineCounts(filepath.Join(root, "nope"), "*.go", 2); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing root: err = %v, want %v", err, fs.ErrNotExist)
+	}
+}
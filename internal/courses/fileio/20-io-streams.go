@@ -0,0 +1,240 @@
+package fileio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 20: STREAMS AND THE io INTERFACES
+// Topics covered:
+// 1. The io.Reader and io.Writer contracts
+// 2. Writing a custom Reader
+// 3. Wrapping readers and writers (counting, progress)
+// 4. io.TeeReader - read once, use twice
+// 5. io.MultiWriter and io.MultiReader
+// 6. io.LimitReader - capping untrusted input
+// 7. io.Pipe - connecting a writer to a reader across goroutines
+// 8. Composing a pipeline (JSON -> gzip -> count)
+
+// Course 3 listed io.Reader and io.Writer as common interfaces. This course
+// shows why they matter: small pieces that snap together.
+
+// ============ 1. THE CONTRACTS ============
+// io.Reader: Read fills p with up to len(p) bytes and returns how many it read.
+//   - n > 0 and err != nil can happen together: use the n bytes, then handle err
+//   - io.EOF means "no more data", not failure
+// io.Writer: Write writes all of p or returns an error explaining why not.
+
+// readInChunks calls Read by hand to show the loop io.ReadAll and io.Copy hide
+func readInChunks(r io.Reader, size int) ([]string, error) {
+	var chunks []string
+	buf := make([]byte, size)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			chunks = append(chunks, string(buf[:n])) // always process n bytes first
+		}
+		if err == io.EOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+	}
+}
+
+// ============ 2. A CUSTOM READER ============
+// rot13Reader wraps another reader and decodes ROT13 as data flows through.
+type rot13Reader struct {
+	r io.Reader
+}
+
+func (rr rot13Reader) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	for i := 0; i < n; i++ {
+		p[i] = rot13(p[i])
+	}
+	return n, err
+}
+
+func rot13(b byte) byte {
+	switch {
+	case b >= 'a' && b <= 'z':
+		return 'a' + (b-'a'+13)%26
+	case b >= 'A' && b <= 'Z':
+		return 'A' + (b-'A'+13)%26
+	}
+	return b
+}
+
+// ============ 3. WRAPPERS ============
+// CountingWriter counts the bytes that pass through to the wrapped writer.
+type CountingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	return &CountingWriter{w: w}
+}
+
+func (cw *CountingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func (cw *CountingWriter) Count() int64 {
+	return cw.n
+}
+
+// progressReader reports how far through a stream of known size we are.
+type progressReader struct {
+	r        io.Reader
+	total    int64
+	read     int64
+	onUpdate func(read, total int64)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	pr.read += int64(n)
+	if n > 0 {
+		pr.onUpdate(pr.read, pr.total)
+	}
+	return n, err
+}
+
+// ============ 4. io.Pipe ============
+// streamUsersJSON encodes users straight into a pipe. The caller reads the
+// JSON as it is produced - nothing is buffered in full.
+func streamUsersJSON(list []web.User) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		enc := json.NewEncoder(pw)
+		for _, u := range list {
+			if err := enc.Encode(u); err != nil {
+				pw.CloseWithError(err) // the reader's next Read returns err
+				return
+			}
+		}
+		pw.Close() // the reader sees io.EOF
+	}()
+	return pr
+}
+
+// ============ 5. A PIPELINE ============
+// gzipStream compresses everything from src into dst and reports the sizes
+// of the input and the output.
+func gzipStream(dst io.Writer, src io.Reader) (in, out int64, err error) {
+	counted := NewCountingWriter(dst)
+	zw := gzip.NewWriter(counted)
+
+	in, err = io.Copy(zw, src)
+	if err != nil {
+		return in, counted.Count(), err
+	}
+	// Close flushes the last compressed block - forgetting it truncates the output
+	if err := zw.Close(); err != nil {
+		return in, counted.Count(), err
+	}
+	return in, counted.Count(), nil
+}
+
+// ============ COURSE TWENTY MAIN FUNCTION ============
+func CourseTwenty(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 20)
+
+	l.Section("read-loop")
+	chunks, _ := readInChunks(strings.NewReader("streams move data in pieces"), 8)
+	l.Printf("Read in 8-byte chunks: %q\n", chunks)
+
+	l.Section("custom-reader")
+	secret := strings.NewReader("Lbh penpxrq gur pbqr!")
+	io.Copy(l.Printer, rot13Reader{r: secret})
+	l.Println()
+	l.Resume()
+
+	l.Section("counting-progress")
+	payload := strings.Repeat("go ", 2000)
+	progress := &progressReader{
+		r:     strings.NewReader(payload),
+		total: int64(len(payload)),
+		onUpdate: func(read, total int64) {
+			l.Printf("  progress: %4d/%d bytes (%3d%%)\n", read, total, read*100/total)
+		},
+	}
+	counter := NewCountingWriter(io.Discard)
+	io.CopyBuffer(counter, progress, make([]byte, 2048))
+	l.Printf("CountingWriter saw %d bytes\n", counter.Count())
+
+	l.Section("io-teereader")
+	hasher := sha256.New()
+	var saved bytes.Buffer
+	upload := strings.NewReader("file contents from an upload")
+	io.Copy(&saved, io.TeeReader(upload, hasher)) // one pass: saved AND hashed
+	l.Printf("Saved %d bytes, sha256 %x...\n", saved.Len(), hasher.Sum(nil)[:8])
+
+	l.Section("multiwriter-multireader")
+	var logFile bytes.Buffer
+	logCounter := NewCountingWriter(&logFile)
+	logOut := io.MultiWriter(l.Printer, logCounter) // like the shell's tee
+	fmt.Fprintln(logOut, "  [log] server started")
+	fmt.Fprintln(logOut, "  [log] listening on :8080")
+	l.Printf("Also captured %d bytes for the log file\n", logCounter.Count())
+
+	combined := io.MultiReader(strings.NewReader("header\n"), strings.NewReader("body\n"), strings.NewReader("footer\n"))
+	all, _ := io.ReadAll(combined)
+	l.Printf("MultiReader joined three readers: %q\n", all)
+
+	l.Section("io-limitreader")
+	huge := strings.NewReader(strings.Repeat("A", 1<<20)) // 1 MB from a client
+	limited, _ := io.ReadAll(io.LimitReader(huge, 16))
+	l.Printf("Read only %d bytes: %s\n", len(limited), limited)
+	l.Resume()
+
+	l.Section("io-pipe")
+	dec := json.NewDecoder(streamUsersJSON(web.Users.List()))
+	for {
+		var u web.User
+		err := dec.Decode(&u)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("decoding the user stream: %w", err)
+		}
+		l.Printf("  decoded user %d: %s\n", u.ID, u.Name)
+	}
+
+	l.Section("pipeline")
+	var archive bytes.Buffer
+	in, out, err := gzipStream(&archive, streamUsersJSON(web.Users.List()))
+	if err != nil {
+		return fmt.Errorf("compressing the user stream: %w", err)
+	}
+	l.Printf("Compressed %d bytes of JSON into %d bytes\n", in, out)
+
+	zr, err := gzip.NewReader(&archive)
+	if err != nil {
+		return fmt.Errorf("reading the archive back: %w", err)
+	}
+	restored, err := io.ReadAll(zr)
+	if err != nil {
+		return fmt.Errorf("reading the archive back: %w", err)
+	}
+	l.Printf("Round trip restored %d bytes\n", len(restored))
+
+	l.End()
+	return nil
+}
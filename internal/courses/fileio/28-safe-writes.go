@@ -0,0 +1,333 @@
+package fileio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/internal/safefile"
+)
+
+// COURSE 28: SAFE FILE WRITES
+// Topics covered:
+// 1. Torn writes: what a crash in the middle of os.WriteFile leaves
+// 2. Write a temporary file, then rename it over the real one
+// 3. fsync: what it guarantees and what it costs
+// 4. Lost updates: two programs saving the same file
+// 5. Advisory file locks around read-change-write
+//
+// The learner's progress file is saved the way this course ends up doing
+// it, with internal/safefile.
+
+// errCrash stands for the program dying in the middle of a save: the
+// write functions below stop after crashAfter bytes, as a kill -9 or a
+// power cut would stop them, and clean nothing up.
+var errCrash = errors.New("crashed")
+
+// ============ 1. TORN WRITES ============
+// writeInPlace is os.WriteFile with a crash switch: it truncates the file
+// first, so from then on the old contents are gone and the new ones
+// aren't all there yet. crashAfter < 0 doesn't crash.
+func writeInPlace(name string, data []byte, crashAfter int) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if crashAfter >= 0 && crashAfter < len(data) {
+		f.Write(data[:crashAfter])
+		return errCrash
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// ============ 2. WRITE, THEN RENAME ============
+// writeAtomic writes the new contents to a temporary file in the same
+// directory, flushes it to disk, and renames it over name. rename(2)
+// replaces the directory entry in one step, so name is always either the
+// old file or the new one. internal/safefile.WriteFile is this, without
+// the crash switch, and removing the temporary file when a save fails.
+func writeAtomic(name string, data []byte, crashAfter int) error {
+	dir := filepath.Dir(name)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer tmp.Close()
+	if crashAfter >= 0 && crashAfter < len(data) {
+		tmp.Write(data[:crashAfter])
+		return errCrash // the temporary file stays behind; name is untouched
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil { // the data is on disk before the rename
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return err
+	}
+	// The rename is a change to the directory: flush it too, or a power
+	// cut can undo it
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// scores is the file the demos save: quiz scores by course, like a small
+// progress file.
+type scores map[string]int
+
+func loadScores(name string) (scores, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	s := scores{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(name), err)
+	}
+	return s, nil
+}
+
+func (s scores) save(name string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return safefile.WriteFile(name, data, 0644)
+}
+
+// printFile shows a file's contents, and says so if it's gone.
+func printFile(out *demo.Printer, name string) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		out.Printf("  %s: %v\n", filepath.Base(name), err)
+		return
+	}
+	out.Printf("  %s: %q\n", filepath.Base(name), data)
+}
+
+// listDir shows what is in dir, the temporary files included, with a *
+// for the random part of their names.
+func listDir(out *demo.Printer, dir string) {
+	names, _ := listDirectory(dir)
+	for i, name := range names {
+		if before, _, ok := strings.Cut(name, ".tmp-"); ok {
+			names[i] = before + ".tmp-*"
+		}
+	}
+	out.Printf("  in the directory: %s\n", strings.Join(names, ", "))
+}
+
+// ============ 3. FSYNC ============
+// Write returns once the data is in the kernel's page cache; the kernel
+// writes it to disk later, within about 30 seconds on Linux. Sync (fsync)
+// waits until the disk has it. timeSaves times n saves of data with save.
+func timeSaves(name string, data []byte, n int, save func(string, []byte) error) (time.Duration, error) {
+	start := time.Now()
+	for range n {
+		if err := save(name, data); err != nil {
+			return 0, err
+		}
+	}
+	return time.Since(start), nil
+}
+
+// renameNoSync is writeAtomic without either Sync: atomic while the
+// machine stays up, but after a power cut the file can be empty.
+func renameNoSync(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// ============ 4. LOST UPDATES ============
+// runner is one program using the scores file: it reads the file, changes
+// its copy, and saves it. Two runners that read before either saves each
+// save a copy without the other's change, and the second save wins.
+type runner struct {
+	name   string
+	scores scores
+}
+
+func (r *runner) read(out *demo.Printer, file string) error {
+	s, err := loadScores(file)
+	if err != nil {
+		return err
+	}
+	r.scores = s
+	out.Printf("  %-5s reads  %v\n", r.name, s)
+	return nil
+}
+
+func (r *runner) record(out *demo.Printer, file, course string, score int) error {
+	r.scores[course] = score
+	out.Printf("  %-5s saves  %v\n", r.name, r.scores)
+	return r.scores.save(file)
+}
+
+// ============ 5. FILE LOCKS ============
+// updateScores is the fix: hold a lock from the read to the save, so no
+// one else reads in between. The lock is on a file of its own: the
+// rename replaces the scores file, and a lock on the old one would lock
+// nothing. Locks are advisory: they keep out the programs that take them,
+// and no one else.
+func updateScores(file string, change func(scores)) error {
+	lock, err := safefile.Obtain(file + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	s, err := loadScores(file)
+	if err != nil {
+		return err
+	}
+	change(s)
+	return s.save(file)
+}
+
+// ============ COURSE TWENTY-EIGHT MAIN FUNCTION ============
+func CourseTwentyEight(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 28)
+
+	// Relative to the working directory, as in course 5
+	dir := "./safe"
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "scores.json")
+	before := []byte(`{"course 1":80,"course 2":90}`)
+	after := []byte(`{"course 1":80,"course 2":90,"course 3":100}`)
+
+	l.Section("torn")
+	if err := writeInPlace(file, before, -1); err != nil {
+		return err
+	}
+	printFile(l.Printer, file)
+	err := writeInPlace(file, after, 20)
+	l.Printf("Saving course 3 in place: %v\n", err)
+	printFile(l.Printer, file)
+	if _, err := loadScores(file); err != nil {
+		l.Printf("  next run: %v\n", err)
+	}
+	l.Resume()
+
+	l.Section("rename")
+	if err := writeAtomic(file, before, -1); err != nil {
+		return err
+	}
+	err = writeAtomic(file, after, 20)
+	l.Printf("Saving course 3 atomically: %v\n", err)
+	printFile(l.Printer, file)
+	listDir(l.Printer, dir)
+	if err := writeAtomic(file, after, -1); err != nil {
+		return err
+	}
+	l.Println("Saving again, without a crash:")
+	printFile(l.Printer, file)
+	l.Resume()
+
+	l.Section("fsync")
+	const n = 100
+	for _, s := range []struct {
+		name string
+		save func(string, []byte) error
+	}{
+		{"os.WriteFile", func(name string, data []byte) error { return os.WriteFile(name, data, 0644) }},
+		{"rename, no fsync", renameNoSync},
+		{"safefile.WriteFile", func(name string, data []byte) error { return safefile.WriteFile(name, data, 0644) }},
+	} {
+		took, err := timeSaves(file, after, n, s.save)
+		if err != nil {
+			return err
+		}
+		l.Printf("  %-20s %d saves in %v\n", s.name, n, took.Round(time.Microsecond))
+	}
+	l.Resume()
+
+	l.Section("lost-update")
+	if err := scores(map[string]int{"course 1": 80}).save(file); err != nil {
+		return err
+	}
+	quiz, grade := &runner{name: "quiz"}, &runner{name: "grade"}
+	if err := quiz.read(l.Printer, file); err != nil {
+		return err
+	}
+	if err := grade.read(l.Printer, file); err != nil {
+		return err
+	}
+	if err := quiz.record(l.Printer, file, "course 2", 90); err != nil {
+		return err
+	}
+	if err := grade.record(l.Printer, file, "exercise 1", 100); err != nil {
+		return err
+	}
+	printFile(l.Printer, file)
+	l.Resume()
+
+	l.Section("lock")
+	if err := scores(map[string]int{"course 1": 80}).save(file); err != nil {
+		return err
+	}
+	held, err := safefile.Obtain(file + ".lock")
+	if err != nil {
+		return err
+	}
+	l.Println("  quiz has the lock")
+	_, err = safefile.TryObtain(file + ".lock")
+	l.Printf("  grade tries it: %v\n", err)
+
+	// grade waits for the lock in a goroutine, as another program would
+	done := make(chan error)
+	go func() {
+		done <- updateScores(file, func(s scores) { s["exercise 1"] = 100 })
+	}()
+	s, err := loadScores(file)
+	if err != nil {
+		return err
+	}
+	s["course 2"] = 90
+	if err := s.save(file); err != nil {
+		return err
+	}
+	l.Println("  quiz saves and releases the lock")
+	if err := held.Release(); err != nil {
+		return err
+	}
+	if err := <-done; err != nil {
+		return err
+	}
+	l.Println("  grade gets the lock, reads, saves")
+	printFile(l.Printer, file)
+	l.Resume()
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,64 @@
+package fileio
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteCrash(t *testing.T) {
+	old, next := []byte(`{"a":1}`), []byte(`{"a":1,"b":2}`)
+	tests := []struct {
+		name  string
+		write func(string, []byte, int) error
+		want  string // what a crash leaves in the file
+	}{
+		{"in place", writeInPlace, `{"a":1,`},
+		{"atomic", writeAtomic, `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "scores.json")
+			if err := tt.write(name, old, -1); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.write(name, next, 7); !errors.Is(err, errCrash) {
+				t.Fatalf("err = %v, want %v", err, errCrash)
+			}
+			if got, _ := os.ReadFile(name); string(got) != tt.want {
+				t.Errorf("after the crash: %q, want %q", got, tt.want)
+			}
+			if err := tt.write(name, next, -1); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(name); string(got) != string(next) {
+				t.Errorf("after saving again: %q, want %q", got, next)
+			}
+		})
+	}
+}
+
+func TestUpdateScores(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "scores.json")
+	if err := (scores{}).save(name); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Go(func() {
+			if err := updateScores(name, func(s scores) { s[string(rune('a'+i))] = i }); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+	s, err := loadScores(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 20 {
+		t.Errorf("%d of 20 updates saved: %v", len(s), s)
+	}
+}
@@ -0,0 +1,274 @@
+package fileio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 29: LARGE FILES
+// Topics covered:
+// 1. Generating a large file with a buffered writer
+// 2. os.ReadFile: memory as big as the file
+// 3. Streaming with bufio: memory as big as a line
+// 4. mmap: letting the kernel page the file in
+// 5. Memory limits: GOMEMLIMIT and debug.SetMemoryLimit
+
+// LargeFileSize is how big a file the course generates, in bytes. The
+// snapshot tests make it smaller.
+var LargeFileSize int64 = 256 << 20
+
+// ============ 1. GENERATING A LARGE FILE ============
+// generateLog writes an access log of about size bytes, one request a
+// line:
+//
+//	2024-03-01T12:00:00Z GET /users/42 200 1532
+//
+// Through a bufio.Writer, a line costs a copy into the buffer, and the
+// file gets one write per 1 MB instead of one per line. The seed makes
+// the file the same every time.
+func generateLog(name string, size int64) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriterSize(f, 1<<20)
+	rng := rand.New(rand.NewPCG(1, 2))
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	methods := []string{"GET", "GET", "GET", "POST", "PUT", "DELETE"}
+	statuses := []int{200, 200, 200, 200, 201, 304, 404, 500, 503}
+
+	var line []byte
+	for written, i := int64(0), 0; written < size; i++ {
+		line = start.Add(time.Duration(i)*time.Millisecond).AppendFormat(line[:0], time.RFC3339)
+		line = append(line, ' ')
+		line = append(line, methods[rng.IntN(len(methods))]...)
+		line = append(line, " /users/"...)
+		line = strconv.AppendInt(line, int64(rng.IntN(100000)), 10)
+		line = append(line, ' ')
+		line = strconv.AppendInt(line, int64(statuses[rng.IntN(len(statuses))]), 10)
+		line = append(line, ' ')
+		line = strconv.AppendInt(line, int64(rng.IntN(50000)), 10)
+		line = append(line, '\n')
+		n, err := w.Write(line)
+		if err != nil {
+			return err
+		}
+		written += int64(n)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// logStats is what the course works out from the log, the same way
+// whichever way the file is read.
+type logStats struct {
+	Lines  int
+	Errors int   // 5xx responses
+	Bytes  int64 // response bytes
+}
+
+// add counts one line. It reads the last two fields from the end, without
+// splitting the line into a slice of fields: on hundreds of MB, that is
+// millions of allocations saved.
+func (s *logStats) add(line []byte) {
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return
+	}
+	s.Lines++
+	rest, size, _ := cutLast(line)
+	_, status, _ := cutLast(rest)
+	if len(status) == 3 && status[0] == '5' {
+		s.Errors++
+	}
+	n, _ := strconv.ParseInt(string(size), 10, 64) // no allocation: the compiler spots the conversion
+	s.Bytes += n
+}
+
+// cutLast splits b around its last space.
+func cutLast(b []byte) (before, after []byte, found bool) {
+	if i := bytes.LastIndexByte(b, ' '); i >= 0 {
+		return b[:i], b[i+1:], true
+	}
+	return nil, b, false
+}
+
+// ============ 2. os.ReadFile ============
+// readAllStats reads the whole file into memory, then goes over it. Simple,
+// and fine for a config file; for a log of 10 GB it needs 10 GB.
+func readAllStats(name string) (logStats, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return logStats{}, err
+	}
+	var s logStats
+	for line := range bytes.Lines(data) {
+		s.add(line)
+	}
+	return s, nil
+}
+
+// ============ 3. STREAMING ============
+// scanStats reads the file a buffer at a time: the memory it needs is the
+// buffer, however big the file. A line longer than the buffer's maximum
+// is an error (bufio.ErrTooLong), so the maximum is set with Buffer.
+func scanStats(name string) (logStats, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return logStats{}, err
+	}
+	defer f.Close()
+
+	var s logStats
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for sc.Scan() {
+		s.add(sc.Bytes()) // only valid until the next Scan: copy it to keep it
+	}
+	return s, sc.Err()
+}
+
+// ============ 4. MMAP ============
+// mmapStats maps the file into memory and reads it as one []byte, like
+// readAllStats, but the kernel reads each page from the file only when it
+// is touched, and can drop it again under memory pressure. It is set in
+// largefile_mmap.go, on systems with mmap.
+var mmapStats func(name string) (logStats, error)
+
+// ============ 5. MEASURING MEMORY ============
+// memUse is what a run of one of the readers cost.
+type memUse struct {
+	peak uint64 // most heap in use at once, above what was in use before
+	gcs  uint32 // garbage collections during the run
+	took time.Duration
+}
+
+// measure runs fn, sampling the heap every millisecond to catch its peak.
+// runtime.ReadMemStats stops the world briefly; fine for a demo, not for a
+// production hot path, where runtime/metrics is cheaper.
+func measure(fn func() error) (memUse, error) {
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	done, peak := make(chan struct{}), make(chan uint64)
+	go func() {
+		var m runtime.MemStats
+		var most uint64
+		tick := time.NewTicker(time.Millisecond)
+		defer tick.Stop()
+		for {
+			runtime.ReadMemStats(&m)
+			most = max(most, m.HeapAlloc)
+			select {
+			case <-done:
+				peak <- most
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+
+	start := time.Now()
+	err := fn()
+	took := time.Since(start)
+	close(done)
+	most := <-peak
+
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	return memUse{peak: most - min(most, before.HeapAlloc), gcs: after.NumGC - before.NumGC, took: took}, err
+}
+
+// mb formats a number of bytes in MB.
+func mb[T int64 | uint64](n T) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
+
+// runReader runs one way of reading the log and prints what it found and
+// what it cost.
+func runReader(out *demo.Printer, label, name string, read func(string) (logStats, error)) error {
+	var s logStats
+	use, err := measure(func() (err error) {
+		s, err = read(name)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	out.Printf("%-12s %d lines, %d errors, %s served\n", label, s.Lines, s.Errors, mb(s.Bytes))
+	out.Printf("%-12s heap peak %s, %d GCs, %v\n", "", mb(use.peak), use.gcs, use.took.Round(time.Millisecond))
+	return nil
+}
+
+// ============ COURSE TWENTY-NINE MAIN FUNCTION ============
+func CourseTwentyNine(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 29)
+
+	// Relative to the working directory, as in course 5
+	name := "access.log"
+	defer os.Remove(name)
+
+	l.Section("generate")
+	start := time.Now()
+	if err := generateLog(name, LargeFileSize); err != nil {
+		return err
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return err
+	}
+	l.Printf("Wrote %s (%d bytes) in %v\n", mb(info.Size()), info.Size(), time.Since(start).Round(time.Millisecond))
+	l.Resume()
+
+	l.Section("readfile")
+	if err := runReader(l.Printer, "os.ReadFile", name, readAllStats); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.Section("stream")
+	if err := runReader(l.Printer, "bufio", name, scanStats); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.Section("mmap")
+	if mmapStats == nil {
+		l.Println("mmap isn't available on this system")
+	} else if err := runReader(l.Printer, "mmap", name, mmapStats); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.Section("limits")
+	limit := LargeFileSize / 4
+	old := debug.SetMemoryLimit(limit)
+	defer debug.SetMemoryLimit(old)
+	l.Printf("debug.SetMemoryLimit(%s), a quarter of the file:\n", mb(limit))
+	if err := runReader(l.Printer, "os.ReadFile", name, readAllStats); err != nil {
+		return err
+	}
+	if err := runReader(l.Printer, "bufio", name, scanStats); err != nil {
+		return err
+	}
+	l.Resume()
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,47 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogStatsAdd(t *testing.T) {
+	var s logStats
+	for _, line := range []string{
+		"2024-03-01T12:00:00Z GET /users/1 200 1500\n",
+		"2024-03-01T12:00:00Z GET /users/2 503 20\r\n",
+		"\n",
+		"2024-03-01T12:00:00Z PUT /users/3 404 7",
+	} {
+		s.add([]byte(line))
+	}
+	if want := (logStats{Lines: 3, Errors: 1, Bytes: 1527}); s != want {
+		t.Errorf("stats = %+v, want %+v", s, want)
+	}
+}
+
+func TestLogReaders(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "access.log")
+	if err := generateLog(name, 64<<10); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(name)
+	if err != nil || info.Size() < 64<<10 {
+		t.Fatalf("generated %v, %v; want at least 64 KB", info, err)
+	}
+
+	want, err := readAllStats(name)
+	if err != nil || want.Lines == 0 || want.Errors == 0 {
+		t.Fatalf("readAllStats = %+v, %v", want, err)
+	}
+	readers := map[string]func(string) (logStats, error){"scan": scanStats}
+	if mmapStats != nil {
+		readers["mmap"] = mmapStats
+	}
+	for label, read := range readers {
+		if got, err := read(name); err != nil || got != want {
+			t.Errorf("%s = %+v, %v; want %+v", label, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package fileio
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing/fstest"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 30: FAKE FILE SYSTEMS
+// Topics covered:
+// 1. io/fs: a file system as an interface, and os.DirFS
+// 2. Course 5's helpers rewritten on fs.FS
+// 3. fstest.MapFS: a file system in memory, for tests
+// 4. Failing on purpose: an fs.FS that returns errors
+// 5. fstest.TestFS: checking an fs.FS implementation
+// 6. What a fake can't show, and when a test needs t.TempDir
+
+// ============ 1. THE HELPERS ON fs.FS ============
+// Course 5's helpers take an OS path, so their tests have to make real
+// files. These take an fs.FS and a path in it instead: the same code reads
+// a directory on disk (os.DirFS), files compiled into the program
+// (embed.FS), a zip (zip.Reader) or a map in memory (fstest.MapFS).
+//
+// Paths in an fs.FS are always slash-separated and relative, with no "."
+// or ".." in them (fs.ValidPath); "." is the root. So it is path, not
+// path/filepath, that joins and matches them, on Windows too.
+
+// readLinesFS is readLineByLine on an fs.FS.
+func readLinesFS(fsys fs.FS, name string) ([]string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	return lines, sc.Err()
+}
+
+// dirSizesFS is dirSizes on an fs.FS: fs.WalkDir in place of
+// filepath.WalkDir, path.Dir in place of filepath.Dir.
+func dirSizesFS(fsys fs.FS, root string) (map[string]int64, error) {
+	sizes := map[string]int64{}
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			sizes[p] += 0
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		for dir := p; dir != root; {
+			dir = path.Dir(dir)
+			sizes[dir] += info.Size()
+		}
+		return nil
+	})
+	return sizes, err
+}
+
+// findFilesFS is findFiles on an fs.FS.
+func findFilesFS(fsys fs.FS, root, pattern string, skip ...string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	var found []string
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && slices.Contains(skip, d.Name()) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ok, _ := path.Match(pattern, d.Name()); ok {
+			found = append(found, p)
+		}
+		return nil
+	})
+	return found, err
+}
+
+// ============ 2. fstest.MapFS ============
+// projectFiles is the project of course 5's walk, as a MapFS: a map from
+// paths to files. Directories are implied by the paths in it.
+var projectFiles = fstest.MapFS{
+	"go.mod":                       {Data: []byte("module example.com/project\n")},
+	"main.go":                      {Data: []byte("package main\n\nfunc main() {\n\tweb.Serve()\n}\n")},
+	"README.md":                    {Data: []byte("# Project\n\nA small web service.\n")},
+	"internal/store/store.go":      {Data: []byte("package store\n\ntype Store struct{}\n")},
+	"internal/store/store_test.go": {Data: []byte("package store\n")},
+	"internal/web/web.go":          {Data: []byte("package web\n\nfunc Serve() {}\n")},
+	"vendor/lib/lib.go":            {Data: []byte("package lib\n\n// vendored\n")},
+}
+
+// ============ 3. FAILING ON PURPOSE ============
+// failFS is an fs.FS that fails to open one path with err, and opens
+// everything else from the fs.FS inside it. On disk, a read error takes a
+// chmod that root ignores, or a disk going bad; here it takes a field.
+//
+// It embeds the fs.FS interface, not a MapFS, so only Open is promoted:
+// fs.ReadDir, fs.Stat and fs.WalkDir see no ReadDir or Stat method and go
+// through Open, where the failure is.
+type failFS struct {
+	fs.FS
+	name string
+	err  error
+}
+
+func (f failFS) Open(name string) (fs.File, error) {
+	if name == f.name {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: f.err}
+	}
+	return f.FS.Open(name)
+}
+
+// ============ COURSE THIRTY MAIN FUNCTION ============
+func CourseThirty(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 30)
+
+	l.Section("dirfs")
+	tempDir, err := os.MkdirTemp("", "course30-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+	files := map[string]string{}
+	for name, f := range projectFiles {
+		files[name] = string(f.Data)
+	}
+	if err := makeTree(tempDir, files); err != nil {
+		return err
+	}
+
+	disk := os.DirFS(tempDir)
+	found, err := findFilesFS(disk, ".", "*.go", "vendor")
+	if err != nil {
+		return err
+	}
+	l.Printf("os.DirFS, *.go skipping vendor: %s\n", strings.Join(found, " "))
+	lines, err := readLinesFS(disk, "main.go")
+	if err != nil {
+		return err
+	}
+	l.Printf("os.DirFS, main.go: %d lines\n", len(lines))
+	for _, name := range []string{"../etc/passwd", "/etc/passwd", "internal/../main.go"} {
+		_, err := disk.Open(name)
+		l.Printf("Open(%q): %v\n", name, err)
+	}
+	l.Resume()
+
+	l.Section("mapfs")
+	found, err = findFilesFS(projectFiles, ".", "*.go", "vendor")
+	if err != nil {
+		return err
+	}
+	l.Printf("MapFS, *.go skipping vendor: %s\n", strings.Join(found, " "))
+	lines, err = readLinesFS(projectFiles, "main.go")
+	if err != nil {
+		return err
+	}
+	l.Printf("MapFS, main.go: %d lines\n", len(lines))
+	sizes, err := dirSizesFS(projectFiles, ".")
+	if err != nil {
+		return err
+	}
+	for _, dir := range slices.Sorted(maps.Keys(sizes)) {
+		l.Printf("  %4d  %s\n", sizes[dir], dir)
+	}
+	l.Resume()
+
+	l.Section("errors")
+	denied := failFS{projectFiles, "main.go", fs.ErrPermission}
+	_, err = readLinesFS(denied, "main.go")
+	l.Printf("readLinesFS: %v (errors.Is ErrPermission: %v)\n", err, errors.Is(err, fs.ErrPermission))
+	unreadable := failFS{projectFiles, "internal/web", errors.New("input/output error")}
+	_, err = dirSizesFS(unreadable, ".")
+	l.Printf("dirSizesFS: %v\n", err)
+	l.Resume()
+
+	l.Section("testfs")
+	if err := fstest.TestFS(projectFiles, "main.go", "internal/store/store.go"); err != nil {
+		return err
+	}
+	l.Println("fstest.TestFS(projectFiles, ...): ok")
+	// failFS lists go.mod in its directory, then won't open it: TestFS
+	// reports every such inconsistency, after a first line of its own
+	err = fstest.TestFS(failFS{projectFiles, "go.mod", fs.ErrPermission}, "main.go")
+	report := strings.Split(err.Error(), "\n")
+	l.Printf("fstest.TestFS(failFS, ...): %s\n", report[1])
+	l.Resume()
+
+	l.Section("limits")
+	fake := fstest.MapFS{
+		"secret.txt": {Data: []byte("top secret\n"), Mode: 0o000},
+		"README.md":  {Data: []byte("upper\n")},
+		"readme.md":  {Data: []byte("lower\n")},
+	}
+	lines, err = readLinesFS(fake, "secret.txt")
+	l.Printf("MapFS, a file with mode 0000: %q, %v\n", lines, err)
+	names, _ := fs.Glob(fake, "*.md")
+	l.Printf("MapFS, README.md and readme.md: %d files, %v\n", len(names), names)
+	_, canWrite := fs.FS(fake).(interface {
+		WriteFile(string, []byte, fs.FileMode) error
+	})
+	l.Printf("fs.FS has a way to write: %v\n", canWrite)
+	l.Resume()
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,100 @@
+package fileio
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFindFilesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.go":         {},
+		"a/a.go":          {},
+		"a/a_test.go":     {},
+		"a/notes.txt":     {},
+		"vendor/v/v.go":   {},
+		".git/hooks/x.go": {},
+	}
+	tests := []struct {
+		pattern string
+		skip    []string
+		want    []string
+	}{
+		{"*.go", nil, []string{".git/hooks/x.go", "a/a.go", "a/a_test.go", "main.go", "vendor/v/v.go"}},
+		{"*.go", []string{".git", "vendor"}, []string{"a/a.go", "a/a_test.go", "main.go"}},
+		{"*_test.go", nil, []string{"a/a_test.go"}},
+		{"*.md", nil, nil},
+	}
+	for _, tt := range tests {
+		got, err := findFilesFS(fsys, ".", tt.pattern, tt.skip...)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("findFilesFS(%q, skip %q) = %q, %v; want %q", tt.pattern, tt.skip, got, err, tt.want)
+		}
+	}
+	if _, err := findFilesFS(fsys, ".", "["); !errors.Is(err, path.ErrBadPattern) {
+		t.Errorf("bad pattern: err = %v", err)
+	}
+}
+
+func TestDirSizesFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a/one":   {Data: make([]byte, 10)},
+		"a/b/two": {Data: make([]byte, 5)},
+		"empty":   {Mode: fs.ModeDir},
+	}
+	got, err := dirSizesFS(fsys, ".")
+	want := map[string]int64{".": 15, "a": 15, "a/b": 5, "empty": 0}
+	if err != nil || !maps.Equal(got, want) {
+		t.Errorf("dirSizesFS = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestReadLinesFSErrors(t *testing.T) {
+	fsys := failFS{fstest.MapFS{"a.txt": {Data: []byte("one\ntwo\n")}}, "a.txt", fs.ErrPermission}
+	if _, err := readLinesFS(fsys, "a.txt"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("unreadable file: err = %v, want %v", err, fs.ErrPermission)
+	}
+	if _, err := readLinesFS(fsys, "missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want %v", err, fs.ErrNotExist)
+	}
+	if lines, err := readLinesFS(fsys.FS, "a.txt"); err != nil || !slices.Equal(lines, []string{"one", "two"}) {
+		t.Errorf("readLinesFS = %q, %v", lines, err)
+	}
+}
+
+// The helpers on a real directory: what os.DirFS does that a MapFS
+// doesn't, such as following a symlink.
+func TestHelpersOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	if err := makeTree(dir, map[string]string{"a/one.go": "package a\n", "b.go": "package b\n"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("a", filepath.Join(dir, "link")); err != nil {
+		t.Skip("no symlinks here:", err)
+	}
+
+	fsys := os.DirFS(dir)
+	// WalkDir doesn't follow the link; Open, through the OS, does
+	got, err := findFilesFS(fsys, ".", "*.go")
+	if want := []string{"a/one.go", "b.go"}; err != nil || !slices.Equal(got, want) {
+		t.Errorf("findFilesFS = %q, %v; want %q", got, err, want)
+	}
+	if lines, err := readLinesFS(fsys, "link/one.go"); err != nil || len(lines) != 1 {
+		t.Errorf("reading through the link = %q, %v", lines, err)
+	}
+	if err := fstest.TestFS(fsys, "a/one.go", "b.go"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProjectFiles(t *testing.T) {
+	if err := fstest.TestFS(projectFiles, "main.go", "internal/web/web.go", "vendor/lib/lib.go"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package fileio
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+)
+
+// The mmap part of course 29, on systems that have mmap(2). Elsewhere,
+// Windows included, mmapStats stays nil and the course says so.
+
+func init() {
+	mmapStats = mmapLogStats
+}
+
+func mmapLogStats(name string) (logStats, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return logStats{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return logStats{}, err
+	}
+	if info.Size() == 0 {
+		return logStats{}, nil // mmap refuses a length of 0
+	}
+
+	// The mapping outlives Close; Munmap ends it. Reading data after that,
+	// or after the file shrinks, is a SIGBUS, not an error.
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return logStats{}, err
+	}
+	defer syscall.Munmap(data)
+
+	var s logStats
+	for line := range bytes.Lines(data) {
+		s.add(line)
+	}
+	return s, nil
+}
@@ -0,0 +1,197 @@
+package gotesting
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 10: TESTING IN GO
+// Topics covered:
+// 1. Unit testing basics
+// 2. Table-driven tests
+// 3. Subtests
+// 4. Benchmarking
+// 5. Mocking and stubs
+// 6. Test coverage
+// 7. Integration testing
+// 8. Best practices
+
+// ============ 1. FUNCTIONS TO TEST ============
+func addTest(a, b int) int {
+	return a + b
+}
+
+func divideTest(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return a / b, nil
+}
+
+func isEven(n int) bool {
+	return n%2 == 0
+}
+
+// ============ 2. BASIC TEST ============
+// File: mypackage_test.go
+// func TestAdd(t *testing.T) {
+//	result := add(2, 3)
+//	expected := 5
+//
+//	if result != expected {
+//		t.Errorf("add(2, 3) = %d, want %d", result, expected)
+//	}
+// }
+
+// ============ 3. TABLE-DRIVEN TESTS (RECOMMENDED) ============
+// func TestAddTableDriven(t *testing.T) {
+//	tests := []struct {
+//		name     string
+//		a, b     int
+//		expected int
+//	}{
+//		{"positive numbers", 2, 3, 5},
+//		{"negative numbers", -2, -3, -5},
+//		{"mixed", 5, -3, 2},
+//		{"zero", 0, 0, 0},
+//	}
+//
+//	for _, tt := range tests {
+//		t.Run(tt.name, func(t *testing.T) {
+//			result := add(tt.a, tt.b)
+//			if result != tt.expected {
+//				t.Errorf("add(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+//			}
+//		})
+//	}
+// }
+
+// ============ 4. TEST WITH ERRORS ============
+// func TestDivide(t *testing.T) {
+//	tests := []struct {
+//		name      string
+//		a, b      float64
+//		expected  float64
+//		shouldErr bool
+//	}{
+//		{"normal division", 10, 2, 5, false},
+//		{"division by zero", 10, 0, 0, true},
+//		{"decimal result", 10, 3, 3.333..., false},
+//	}
+//
+//	for _, tt := range tests {
+//		t.Run(tt.name, func(t *testing.T) {
+//			result, err := divide(tt.a, tt.b)
+//
+//			if tt.shouldErr && err == nil {
+//				t.Error("expected error, got nil")
+//			}
+//
+//			if !tt.shouldErr && err != nil {
+//				t.Errorf("unexpected error: %v", err)
+//			}
+//
+//			if !tt.shouldErr && result != tt.expected {
+//				t.Errorf("divide(%.0f, %.0f) = %.0f, want %.0f", tt.a, tt.b, result, tt.expected)
+//			}
+//		})
+//	}
+// }
+
+// ============ 5. BENCHMARKING ============
+// func BenchmarkAdd(b *testing.B) {
+//	for i := 0; i < b.N; i++ {
+//		add(2, 3)
+//	}
+// }
+//
+// func BenchmarkIsEven(b *testing.B) {
+//	for i := 0; i < b.N; i++ {
+//		isEven(i)
+//	}
+// }
+
+// Run with: go test -bench=.
+
+// ============ 6. MOCKING PATTERN ============
+type TestDatabase interface {
+	GetUser(id int) (string, error)
+}
+
+type MockDatabase struct {
+	GetUserFunc func(id int) (string, error)
+}
+
+func (m *MockDatabase) GetUser(id int) (string, error) {
+	return m.GetUserFunc(id)
+}
+
+func getUserName(db TestDatabase, id int) (string, error) {
+	return db.GetUser(id)
+}
+
+// func TestGetUserName(t *testing.T) {
+//	mock := &MockDatabase{
+//		GetUserFunc: func(id int) (string, error) {
+//			if id == 1 {
+//				return "Alice", nil
+//			}
+//			return "", fmt.Errorf("user not found")
+//		},
+//	}
+//
+//	name, err := getUserName(mock, 1)
+//	if name != "Alice" || err != nil {
+//		t.Errorf("expected Alice, got %s", name)
+//	}
+// }
+
+// ============ 7. SETUP/TEARDOWN ============
+// func TestWithSetupTeardown(t *testing.T) {
+//	// Setup
+//	tempDir := t.TempDir() // Creates temporary directory
+//
+//	// Test code
+//	// ... use tempDir ...
+//
+//	// Teardown (automatic - TempDir cleans up)
+// }
+
+// ============ 8. HELPERS ============
+// func assertEqual(t *testing.T, got, want interface{}) {
+//	t.Helper() // Report error at caller's line
+//	if got != want {
+//		t.Errorf("got %v, want %v", got, want)
+//	}
+// }
+
+// ============ 9. CONCURRENT TESTING ============
+// func TestConcurrent(t *testing.T) {
+//	var wg sync.WaitGroup
+//
+//	for i := 0; i < 10; i++ {
+//		wg.Add(1)
+//		go func(id int) {
+//			defer wg.Done()
+//			// Test code
+//		}(i)
+//	}
+//
+//	wg.Wait()
+// }
+
+// ============ COURSE 10 MAIN FUNCTION ============
+func CourseTen(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 10)
+	return nil
+}
+
+// Example test for documentation
+func ExampleAdd() {
+	result := addTest(2, 3)
+	fmt.Println(result)
+	// Output: 5
+}
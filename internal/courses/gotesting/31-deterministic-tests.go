@@ -0,0 +1,161 @@
+package gotesting
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 31: DETERMINISTIC TESTS
+// Topics covered:
+// 1. Why tests that sleep are slow and flaky
+// 2. Injecting a clock: a fake one the test moves by hand
+// 3. testing/synctest: fake time for every goroutine in a bubble
+// 4. The worker pool's timeouts, tested to the nanosecond
+
+// ============ 1. A TTL CACHE ============
+// ttlCache keeps each value for ttl. Get treats an expired value as
+// missing, and a janitor goroutine deletes expired values every sweep, so
+// the memory they hold is freed even if nobody asks for them again.
+//
+// Time comes from now: time.Now in a program, a fake clock's Now in a
+// test. The janitor's ticker is time.NewTicker all the same, which is
+// where injecting a clock stops being enough.
+type ttlCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu    sync.Mutex
+	items map[string]ttlItem
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type ttlItem struct {
+	value   string
+	expires time.Time
+}
+
+func newTTLCache(ttl, sweep time.Duration, now func() time.Time) *ttlCache {
+	c := &ttlCache{ttl: ttl, now: now, items: map[string]ttlItem{}, stop: make(chan struct{}), done: make(chan struct{})}
+	go c.janitor(sweep)
+	return c
+}
+
+func (c *ttlCache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = ttlItem{value, c.now().Add(c.ttl)}
+}
+
+func (c *ttlCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	it, ok := c.items[key]
+	if !ok || !c.now().Before(it.expires) {
+		return "", false
+	}
+	return it.value, true
+}
+
+// Len counts the values stored, expired ones the janitor hasn't deleted
+// yet included.
+func (c *ttlCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Close stops the janitor and waits for it to return.
+func (c *ttlCache) Close() {
+	close(c.stop)
+	<-c.done
+}
+
+func (c *ttlCache) janitor(sweep time.Duration) {
+	defer close(c.done)
+	tick := time.NewTicker(sweep)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			c.mu.Lock()
+			now := c.now()
+			for key, it := range c.items {
+				if !now.Before(it.expires) {
+					delete(c.items, key)
+				}
+			}
+			c.mu.Unlock()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// ============ 2. A FAKE CLOCK ============
+// fakeClock is a clock that only moves when Advance moves it. Pass its
+// Now where the code takes a func() time.Time.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// ============ COURSE THIRTY-ONE MAIN FUNCTION ============
+func CourseThirtyOne(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 31)
+
+	l.Section("sleep")
+	// A test of expiry on the real clock has to wait the TTL out, and a
+	// margin on top for a slow machine
+	c := newTTLCache(30*time.Millisecond, time.Minute, time.Now)
+	start := time.Now()
+	c.Set("user:1", "ada")
+	time.Sleep(40 * time.Millisecond)
+	_, ok := c.Get("user:1")
+	l.Printf("After sleeping 40ms, user:1 found: %v; the test took at least 40ms: %v\n", ok, time.Since(start) >= 40*time.Millisecond)
+	c.Close()
+	l.Resume()
+
+	l.Section("fake-clock")
+	clock := newFakeClock()
+	c = newTTLCache(30*time.Second, time.Minute, clock.Now)
+	c.Set("user:1", "ada")
+	clock.Advance(30*time.Second - time.Nanosecond)
+	_, ok = c.Get("user:1")
+	l.Printf("At 30s minus 1ns: found %v\n", ok)
+	clock.Advance(time.Nanosecond)
+	_, ok = c.Get("user:1")
+	l.Printf("At 30s:           found %v\n", ok)
+	clock.Advance(time.Hour)
+	// The janitor sweeps every minute of real time, not fake time
+	l.Printf("An hour later on the fake clock, still stored: %d value(s)\n", c.Len())
+	c.Close()
+	l.Resume()
+
+	l.Section("synctest")
+	l.Section("pool")
+
+	l.End()
+	return nil
+}
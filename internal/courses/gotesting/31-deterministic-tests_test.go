@@ -0,0 +1,54 @@
+package gotesting
+
+import (
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestTTLCacheFakeClock(t *testing.T) {
+	clock := newFakeClock()
+	c := newTTLCache(30*time.Second, time.Hour, clock.Now)
+	defer c.Close()
+
+	c.Set("a", "1")
+	clock.Advance(30*time.Second - time.Nanosecond)
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Errorf("just before the TTL: Get = %q, %v", v, ok)
+	}
+	clock.Advance(time.Nanosecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("found at the TTL")
+	}
+
+	// Setting a key again starts its TTL again
+	c.Set("a", "2")
+	clock.Advance(20 * time.Second)
+	if v, ok := c.Get("a"); !ok || v != "2" {
+		t.Errorf("after setting again: Get = %q, %v", v, ok)
+	}
+}
+
+// In a synctest bubble, time.Now, time.Sleep and the janitor's ticker all
+// run on the bubble's fake clock, which jumps ahead whenever every
+// goroutine in the bubble is blocked. The test reads like one on the real
+// clock, sleeps included, and takes microseconds.
+func TestTTLCacheJanitor(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c := newTTLCache(30*time.Second, time.Minute, time.Now)
+		defer c.Close()
+
+		c.Set("a", "1")
+		time.Sleep(59 * time.Second)
+		synctest.Wait() // until the janitor is blocked again
+		if _, ok := c.Get("a"); ok || c.Len() != 1 {
+			t.Errorf("at 59s: Get found it %v, Len %d; want expired but not yet swept", ok, c.Len())
+		}
+
+		time.Sleep(time.Second)
+		synctest.Wait() // the ticker fired at 60s; let the sweep finish
+		if c.Len() != 0 {
+			t.Errorf("at 60s: Len %d, want the sweep to have deleted it", c.Len())
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package gotesting
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/internal/mutate"
+)
+
+// COURSE 32: MUTATION TESTING
+// Topics covered:
+// 1. What coverage measures, and what it doesn't
+// 2. Mutants: small bugs, made on purpose, one at a time
+// 3. Running the tests against each mutant: killed or survived
+// 4. Killing the survivors with boundary tests
+// 5. Equivalent mutants, cost, and where mutation testing pays
+
+// ============ 1. THE TARGET ============
+// discountPackage is a package of its own, in testdata so the go command
+// leaves it alone: BulkDiscount, and two tests of it. The course copies it
+// to a temporary module and runs go test there, on the function as it is
+// and on each mutant.
+//
+//go:embed testdata/discount
+var discountPackage embed.FS
+
+// writeDiscountModule copies discountPackage into dir as a module, and
+// returns the path of the file to mutate.
+func writeDiscountModule(dir string) (string, error) {
+	sub, err := fs.Sub(discountPackage, "testdata/discount")
+	if err != nil {
+		return "", err
+	}
+	if err := os.CopyFS(dir, sub); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module discount\n\ngo 1.25\n"), 0o644); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "discount.go"), nil
+}
+
+var coverageRE = regexp.MustCompile(`coverage: .*`)
+
+// ============ 2. SCORING ============
+// killedBy counts the mutants that test caught.
+func killedBy(results []mutate.Result, test string) int {
+	n := 0
+	for _, r := range results {
+		if slices.Contains(r.Failed, test) {
+			n++
+		}
+	}
+	return n
+}
+
+// ============ COURSE THIRTY-TWO MAIN FUNCTION ============
+func CourseThirtyTwo(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 32)
+
+	// Everything below runs the go command, which is there when the course
+	// runs with go run, but not always next to a built binary
+	if _, err := exec.LookPath("go"); err != nil {
+		return fmt.Errorf("course 32 runs go test, and the go command isn't on your PATH: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "course32-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	file, err := writeDiscountModule(dir)
+	if err != nil {
+		return err
+	}
+
+	l.Section("coverage")
+	cmd := exec.CommandContext(ctx, "go", "test", "-count=1", "-cover", "-run", "^TestCovered$", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("go test: %v\n%s", err, out)
+	}
+	l.Printf("go test -cover -run TestCovered: %s\n", coverageRE.Find(out))
+	l.Resume()
+
+	l.Section("mutants")
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	mutants, err := mutate.Generate("discount.go", src, "BulkDiscount")
+	if err != nil {
+		return err
+	}
+	for _, m := range mutants {
+		line := bytes.Split(m.Source(), []byte("\n"))[m.Line-1]
+		l.Printf("%-20s %s\n", m, bytes.TrimSpace(line))
+	}
+	l.Resume()
+
+	l.Section("run")
+	results, err := mutate.Run(ctx, file, mutants)
+	if err != nil {
+		return err
+	}
+	status := func(r mutate.Result, test string) string {
+		if slices.Contains(r.Failed, test) {
+			return "killed"
+		}
+		return "survived"
+	}
+	l.Printf("%-20s %-12s %s\n", "MUTANT", "TestCovered", "TestBoundaries")
+	for _, r := range results {
+		l.Printf("%-20s %-12s %s\n", r.Mutant, status(r, "TestCovered"), status(r, "TestBoundaries"))
+	}
+	l.Resume()
+
+	l.Section("score")
+	for _, test := range []string{"TestCovered", "TestBoundaries"} {
+		n := killedBy(results, test)
+		l.Printf("%-15s kills %d of %d mutants: a mutation score of %d%%\n", test, n, len(results), 100*n/len(results))
+	}
+	l.Resume()
+
+	l.Section("limits")
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,15 @@
+// Package discount is what course 32 mutates: a function with two
+// boundaries, and two tests of it.
+package discount
+
+// BulkDiscount is the percentage off an order of qty items: 10% from 10
+// items, 20% from 100.
+func BulkDiscount(qty int) int {
+	switch {
+	case qty >= 100:
+		return 20
+	case qty >= 10:
+		return 10
+	}
+	return 0
+}
@@ -0,0 +1,21 @@
+package discount
+
+import "testing"
+
+// TestCovered runs every line of BulkDiscount: 100% coverage.
+func TestCovered(t *testing.T) {
+	for qty, want := range map[int]int{5: 0, 50: 10, 500: 20} {
+		if got := BulkDiscount(qty); got != want {
+			t.Errorf("BulkDiscount(%d) = %d, want %d", qty, got, want)
+		}
+	}
+}
+
+// TestBoundaries checks each side of each boundary.
+func TestBoundaries(t *testing.T) {
+	for qty, want := range map[int]int{9: 0, 10: 10, 99: 10, 100: 20} {
+		if got := BulkDiscount(qty); got != want {
+			t.Errorf("BulkDiscount(%d) = %d, want %d", qty, got, want)
+		}
+	}
+}
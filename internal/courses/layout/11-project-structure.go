@@ -0,0 +1,24 @@
+package layout
+
+import (
+	"context"
+	"io"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 11: PROJECT STRUCTURE AND BEST PRACTICES
+// Topics covered:
+// 1. Directory organization
+// 2. Package naming
+// 3. Module setup (go.mod, go.sum)
+// 4. Dependency management
+// 5. Configuration management
+// 6. Logging
+// 7. Error handling patterns
+// 8. Code organization patterns
+
+func CourseEleven(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 11)
+	return nil
+}
@@ -0,0 +1,61 @@
+package layout
+
+import (
+	"context"
+	"io"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/pkg/querybuilder"
+)
+
+// COURSE 14: SEMANTIC VERSIONING AND PUBLISHING A GO MODULE
+// Topics covered:
+// 1. Extracting reusable code into its own module
+// 2. Designing a small public API
+// 3. Doc comments and runnable Example functions
+// 4. Semantic versioning rules (v0, v1, v2+)
+// 5. Tagging a module that lives in a subdirectory
+// 6. Importing the published module from another project
+// 7. Local development with replace
+// 8. Retracting a bad release
+
+// The QueryBuilder from course 12 now lives in pkg/querybuilder with its own
+// go.mod. This course imports it like any third-party dependency.
+
+// ============ COURSE FOURTEEN MAIN FUNCTION ============
+func CourseFourteen(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 14)
+
+	l.Section("extracting-module")
+
+	l.Section("public-api")
+
+	l.Section("using-module")
+
+	query, args := querybuilder.New().
+		Select("id, name, email").
+		From("users").
+		Where("age > ?", 18).
+		Limit(10).
+		Build()
+
+	l.Printf("Query: %s\n", query)
+	l.Printf("Args:  %v\n", args)
+
+	l.Section("doc-examples")
+
+	l.Section("semantic-versioning")
+
+	l.Section("tagging-release")
+
+	l.Section("importing-from-another")
+
+	l.Section("local-development-replace")
+
+	l.Section("retracting-bad-release")
+
+	l.Section("best-practices")
+
+	l.End()
+	return nil
+}
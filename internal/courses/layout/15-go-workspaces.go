@@ -0,0 +1,30 @@
+package layout
+
+import (
+	"context"
+	"io"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 15: GO WORKSPACES AND MULTI-MODULE REPOSITORIES
+// Topics covered:
+// 1. Why a repository may contain several modules
+// 2. The go.work file
+// 3. go work init / use / sync
+// 4. Developing across modules without replace directives
+// 5. Running commands in a workspace
+// 6. Turning the workspace off (GOWORK=off)
+// 7. What to commit and what not to commit
+
+// This repository is itself a workspace:
+//   .                   - the course module (this file)
+//   ./pkg/querybuilder  - the library extracted in course 14
+//   ./pkg/middleware    - the HTTP middleware from courses 6 and 12
+//   ./examples/capstone - a separate program that imports the library
+
+// ============ COURSE FIFTEEN MAIN FUNCTION ============
+func CourseFifteen(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 15)
+	return nil
+}
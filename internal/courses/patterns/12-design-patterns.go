@@ -0,0 +1,311 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 12: MIDDLEWARE, DESIGN PATTERNS, AND ADVANCED PATTERNS
+// Topics covered:
+// 1. Middleware patterns
+// 2. Dependency injection
+// 3. Repository pattern
+// 4. Service layer pattern
+// 5. Builder pattern
+// 6. Observer pattern
+// 7. Strategy pattern
+// 8. Factory pattern
+
+import (
+	"sync"
+)
+
+// ============ 1. MIDDLEWARE PATTERN ============
+// The middleware that used to live here, together with course 6's, was
+// extracted into its own module, github.com/owolabijunior12/learning-golang/pkg/middleware.
+// A middleware is a func(http.Handler) http.Handler: it wraps a handler
+// and adds behaviour before and after it, and Chain stacks several.
+// pkg/httpclient does the same on the client side, around RoundTrippers.
+
+// ============ 2. DEPENDENCY INJECTION ============
+type Logger interface {
+	Log(msg string)
+}
+
+type ConsoleLogger struct{}
+
+func (l *ConsoleLogger) Log(msg string) {
+	fmt.Println(msg)
+}
+
+type UserRepository interface {
+	GetUser(id int) (string, error)
+}
+
+type MockRepository struct {
+	logger Logger
+}
+
+func (r *MockRepository) GetUser(id int) (string, error) {
+	r.logger.Log("Getting user " + fmt.Sprint(id))
+	return "User", nil
+}
+
+// Service receives dependencies (injection)
+type UserService struct {
+	repo   UserRepository
+	logger Logger
+}
+
+func NewUserService(repo UserRepository, logger Logger) *UserService {
+	return &UserService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *UserService) GetUser(id int) (string, error) {
+	s.logger.Log(fmt.Sprintf("UserService.GetUser(%d)", id))
+	return s.repo.GetUser(id)
+}
+
+// ============ 3. REPOSITORY PATTERN ============
+// Repository errors. Check for them with errors.Is.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrExists   = errors.New("already exists")
+)
+
+// Repository is an in-memory store for items of type T keyed by ID. Being
+// generic, a Repository[User, int] only accepts and returns Users: no
+// interface{} and no type assertions. It is safe for concurrent use.
+type Repository[T any, ID comparable] struct {
+	mu    sync.RWMutex
+	items map[ID]T
+	idOf  func(T) ID
+}
+
+// NewRepository returns an empty Repository. idOf reads an item's ID,
+// e.g. func(u User) int { return u.ID }.
+func NewRepository[T any, ID comparable](idOf func(T) ID) *Repository[T, ID] {
+	return &Repository[T, ID]{
+		items: make(map[ID]T),
+		idOf:  idOf,
+	}
+}
+
+// Create stores a new item. It fails with ErrExists if the ID is taken.
+func (r *Repository[T, ID]) Create(item T) error {
+	id := r.idOf(item)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[id]; ok {
+		return fmt.Errorf("create %v: %w", id, ErrExists)
+	}
+	r.items[id] = item
+	return nil
+}
+
+func (r *Repository[T, ID]) Get(id ID) (T, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	item, ok := r.items[id]
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("get %v: %w", id, ErrNotFound)
+	}
+	return item, nil
+}
+
+// Update replaces the stored item with the same ID.
+func (r *Repository[T, ID]) Update(item T) error {
+	id := r.idOf(item)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[id]; !ok {
+		return fmt.Errorf("update %v: %w", id, ErrNotFound)
+	}
+	r.items[id] = item
+	return nil
+}
+
+func (r *Repository[T, ID]) Delete(id ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[id]; !ok {
+		return fmt.Errorf("delete %v: %w", id, ErrNotFound)
+	}
+	delete(r.items, id)
+	return nil
+}
+
+// List returns a copy of all items in no particular order, like ranging
+// over a map. Sort the result if the order matters.
+func (r *Repository[T, ID]) List() []T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]T, 0, len(r.items))
+	for _, item := range r.items {
+		list = append(list, item)
+	}
+	return list
+}
+
+// ============ 4. BUILDER PATTERN ============
+// The QueryBuilder that used to live here was extracted into its own module,
+// github.com/owolabijunior12/learning-golang/pkg/querybuilder (see course 14).
+// It is a good example of the builder pattern: every step returns the builder
+// so calls can be chained, and Build() produces the final value.
+
+// ============ 5. STRATEGY PATTERN ============
+type PaymentStrategy interface {
+	Pay(amount float64) error
+}
+
+type CreditCardPayment struct {
+	cardNumber string
+}
+
+func (c *CreditCardPayment) Pay(amount float64) error {
+	fmt.Printf("Paid %.2f with credit card\n", amount)
+	return nil
+}
+
+type PayPalPayment struct {
+	email string
+}
+
+func (p *PayPalPayment) Pay(amount float64) error {
+	fmt.Printf("Paid %.2f with PayPal (%s)\n", amount, p.email)
+	return nil
+}
+
+type PaymentProcessor struct {
+	strategy PaymentStrategy
+}
+
+func NewPaymentProcessor(strategy PaymentStrategy) *PaymentProcessor {
+	return &PaymentProcessor{strategy: strategy}
+}
+
+func (pp *PaymentProcessor) Process(amount float64) error {
+	return pp.strategy.Pay(amount)
+}
+
+// ============ 6. FACTORY PATTERN ============
+type Vehicle interface {
+	Drive() string
+}
+
+type Car struct{}
+
+func (c *Car) Drive() string {
+	return "Driving car"
+}
+
+type Bicycle struct{}
+
+func (b *Bicycle) Drive() string {
+	return "Riding bicycle"
+}
+
+type VehicleFactory struct{}
+
+func (vf *VehicleFactory) Create(vehicleType string) Vehicle {
+	switch vehicleType {
+	case "car":
+		return &Car{}
+	case "bicycle":
+		return &Bicycle{}
+	default:
+		return nil
+	}
+}
+
+// ============ 7. OBSERVER PATTERN ============
+type Observer interface {
+	Update(message string)
+}
+
+// Subject is safe for concurrent use. Observers are called outside its
+// lock, so an observer may Subscribe or Unsubscribe from inside Update.
+type Subject struct {
+	mu        sync.RWMutex
+	observers []Observer
+}
+
+func NewSubject() *Subject {
+	return &Subject{
+		observers: make([]Observer, 0),
+	}
+}
+
+func (s *Subject) Subscribe(obs Observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observers = append(s.observers, obs)
+}
+
+// Unsubscribe removes obs, compared by identity, so observers should be
+// pointers. Removing an observer that isn't subscribed does nothing.
+func (s *Subject) Unsubscribe(obs Observer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, o := range s.observers {
+		if o == obs {
+			// Build a new slice: a Notify in progress keeps the old one
+			s.observers = append(s.observers[:i:i], s.observers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Subject) Notify(message string) {
+	s.mu.RLock()
+	observers := s.observers
+	s.mu.RUnlock()
+	for _, obs := range observers {
+		obs.Update(message)
+	}
+}
+
+type ConcreteObserver struct {
+	name string
+}
+
+func (co *ConcreteObserver) Update(message string) {
+	fmt.Printf("%s received: %s\n", co.name, message)
+}
+
+// ============ 8. SINGLETON PATTERN ============
+type DatabaseConnection struct {
+	connectionString string
+}
+
+var (
+	instance *DatabaseConnection
+	once     sync.Once
+)
+
+// GetDatabaseConnection returns the one shared connection. sync.Once makes
+// it safe to call from many goroutines at once: the first call creates the
+// instance and every other call, concurrent or later, waits for and gets
+// that same instance. Later connection strings are ignored.
+func GetDatabaseConnection(connectionString string) *DatabaseConnection {
+	once.Do(func() {
+		instance = &DatabaseConnection{
+			connectionString: connectionString,
+		}
+	})
+	return instance
+}
+
+// ============ COURSE TWELVE MAIN FUNCTION ============
+func CourseTwelve(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 12)
+	return nil
+}
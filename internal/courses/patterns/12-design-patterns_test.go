@@ -0,0 +1,375 @@
+package patterns
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// Run with: go test ./internal/courses/patterns -v
+// The Example functions double as documentation: go doc shows them, and
+// go test checks that they still print their // Output.
+
+// fakeLogger keeps what is logged, for checking.
+type fakeLogger struct{ lines []string }
+
+func (l *fakeLogger) Log(msg string) { l.lines = append(l.lines, msg) }
+
+func TestUserServiceInjection(t *testing.T) {
+	logger := &fakeLogger{}
+	service := NewUserService(&MockRepository{logger: logger}, logger)
+
+	name, err := service.GetUser(7)
+	if err != nil || name != "User" {
+		t.Fatalf("GetUser(7) = %q, %v", name, err)
+	}
+	// Both the service and the repository log through the injected logger
+	if want := []string{"UserService.GetUser(7)", "Getting user 7"}; !slices.Equal(logger.lines, want) {
+		t.Errorf("logged %q, want %q", logger.lines, want)
+	}
+}
+
+type book struct {
+	ISBN  string
+	Title string
+}
+
+func newBooks() *Repository[book, string] {
+	return NewRepository(func(b book) string { return b.ISBN })
+}
+
+func TestRepository(t *testing.T) {
+	books := newBooks()
+	if err := books.Create(book{"111", "The Go Programming Language"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		op   func() error
+		want error
+	}{
+		{"create duplicate", func() error { return books.Create(book{"111", "Copy"}) }, ErrExists},
+		{"create", func() error { return books.Create(book{"222", "Learning Go"}) }, nil},
+		{"update", func() error { return books.Update(book{"222", "Learning Go, 2nd ed."}) }, nil},
+		{"update missing", func() error { return books.Update(book{"999", "Nope"}) }, ErrNotFound},
+		{"delete", func() error { return books.Delete("111") }, nil},
+		{"delete again", func() error { return books.Delete("111") }, ErrNotFound},
+		{"get deleted", func() error { _, err := books.Get("111"); return err }, ErrNotFound},
+	}
+	// The steps share one repository, so they run in order
+	for _, tt := range tests {
+		if err := tt.op(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	got, err := books.Get("222")
+	if err != nil || got.Title != "Learning Go, 2nd ed." {
+		t.Errorf("Get(222) = %+v, %v; want the updated book", got, err)
+	}
+	if list := books.List(); len(list) != 1 || list[0] != got {
+		t.Errorf("List() = %+v, want just %+v", list, got)
+	}
+}
+
+func TestRepositoryListIsACopy(t *testing.T) {
+	books := newBooks()
+	books.Create(book{"111", "Original"})
+	books.List()[0].Title = "Changed"
+	if b, _ := books.Get("111"); b.Title != "Original" {
+		t.Errorf("changing the List result changed the repository: %+v", b)
+	}
+}
+
+// Run with -race to check the locking.
+func TestRepositoryConcurrent(t *testing.T) {
+	type item struct{ ID int }
+	repo := NewRepository(func(i item) int { return i.ID })
+
+	const workers, perWorker = 10, 100
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for i := range perWorker {
+				id := w*perWorker + i
+				if err := repo.Create(item{id}); err != nil {
+					t.Error(err)
+				}
+				repo.Get(id)
+				repo.Update(item{id})
+				repo.List()
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := len(repo.List()); n != workers*perWorker {
+		t.Errorf("got %d items, want %d", n, workers*perWorker)
+	}
+}
+
+// fakePayment records the amounts it is asked to pay and fails with err.
+type fakePayment struct {
+	paid []float64
+	err  error
+}
+
+func (p *fakePayment) Pay(amount float64) error {
+	p.paid = append(p.paid, amount)
+	return p.err
+}
+
+func TestPaymentProcessor(t *testing.T) {
+	declined := errors.New("card declined")
+	tests := []struct {
+		name    string
+		amounts []float64
+		err     error
+	}{
+		{"pays each amount", []float64{10, 2.5}, nil},
+		{"returns the strategy's error", []float64{99}, declined},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := &fakePayment{err: tt.err}
+			processor := NewPaymentProcessor(strategy)
+			for _, amount := range tt.amounts {
+				if err := processor.Process(amount); !errors.Is(err, tt.err) {
+					t.Errorf("Process(%v) = %v, want %v", amount, err, tt.err)
+				}
+			}
+			if !slices.Equal(strategy.paid, tt.amounts) {
+				t.Errorf("paid %v, want %v", strategy.paid, tt.amounts)
+			}
+		})
+	}
+
+	// The real strategies never fail
+	for _, s := range []PaymentStrategy{&CreditCardPayment{cardNumber: "4111111111111111"}, &PayPalPayment{email: "ada@example.com"}} {
+		if err := NewPaymentProcessor(s).Process(1); err != nil {
+			t.Errorf("%T: %v", s, err)
+		}
+	}
+}
+
+func TestVehicleFactory(t *testing.T) {
+	tests := []struct {
+		kind string
+		want string // "" for no vehicle
+	}{
+		{"car", "Driving car"},
+		{"bicycle", "Riding bicycle"},
+		{"plane", ""},
+		{"", ""},
+		{"Car", ""}, // names are case-sensitive
+	}
+	var factory VehicleFactory
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			v := factory.Create(tt.kind)
+			if tt.want == "" {
+				if v != nil {
+					t.Errorf("Create(%q) = %T, want nil", tt.kind, v)
+				}
+				return
+			}
+			if v == nil {
+				t.Fatalf("Create(%q) = nil", tt.kind)
+			}
+			if got := v.Drive(); got != tt.want {
+				t.Errorf("Drive() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// inbox is an Observer that keeps its messages.
+type inbox struct{ messages []string }
+
+func (i *inbox) Update(message string) { i.messages = append(i.messages, message) }
+
+func TestSubject(t *testing.T) {
+	subject := NewSubject()
+	subject.Notify("nobody listening") // no observers is fine
+
+	a, b := &inbox{}, &inbox{}
+	subject.Subscribe(a)
+	subject.Notify("first")
+	subject.Subscribe(b)
+	subject.Notify("second")
+
+	// An observer gets what is sent after it subscribes, in order
+	if want := []string{"first", "second"}; !slices.Equal(a.messages, want) {
+		t.Errorf("a got %q, want %q", a.messages, want)
+	}
+	if want := []string{"second"}; !slices.Equal(b.messages, want) {
+		t.Errorf("b got %q, want %q", b.messages, want)
+	}
+}
+
+func TestSubjectUnsubscribe(t *testing.T) {
+	subject := NewSubject()
+	a, b, c := &inbox{}, &inbox{}, &inbox{}
+	subject.Subscribe(a)
+	subject.Subscribe(b)
+	subject.Subscribe(c)
+
+	subject.Unsubscribe(b)
+	subject.Unsubscribe(b)        // already gone: no-op
+	subject.Unsubscribe(&inbox{}) // never subscribed: no-op
+	subject.Notify("hello")
+
+	if len(a.messages) != 1 || len(b.messages) != 0 || len(c.messages) != 1 {
+		t.Errorf("got a=%q b=%q c=%q, want only a and c notified", a.messages, b.messages, c.messages)
+	}
+}
+
+// oneShot is an Observer that unsubscribes itself after its first message.
+type oneShot struct {
+	inbox
+	subject *Subject
+}
+
+func (o *oneShot) Update(message string) {
+	o.inbox.Update(message)
+	o.subject.Unsubscribe(o)
+}
+
+func TestSubjectUnsubscribeDuringNotify(t *testing.T) {
+	subject := NewSubject()
+	first, after := &oneShot{subject: subject}, &inbox{}
+	subject.Subscribe(first)
+	subject.Subscribe(after)
+
+	subject.Notify("one") // must not deadlock or skip after
+	subject.Notify("two")
+
+	if want := []string{"one"}; !slices.Equal(first.messages, want) {
+		t.Errorf("first got %q, want %q", first.messages, want)
+	}
+	if want := []string{"one", "two"}; !slices.Equal(after.messages, want) {
+		t.Errorf("after got %q, want %q", after.messages, want)
+	}
+}
+
+// counter is an Observer that is safe to notify from many goroutines.
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *counter) Update(string) {
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+}
+
+// Run with -race to check Subject's locking.
+func TestSubjectConcurrent(t *testing.T) {
+	subject := NewSubject()
+	stays := &counter{}
+	subject.Subscribe(stays)
+
+	const workers = 50
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			churn := &counter{}
+			subject.Subscribe(churn)
+			subject.Notify("tick")
+			subject.Unsubscribe(churn)
+		})
+	}
+	wg.Wait()
+
+	if stays.n != workers {
+		t.Errorf("subscribed observer got %d messages, want %d", stays.n, workers)
+	}
+	if len(subject.observers) != 1 {
+		t.Errorf("%d observers left, want 1", len(subject.observers))
+	}
+}
+
+func TestGetDatabaseConnectionConcurrent(t *testing.T) {
+	const callers = 100
+	conns := make([]*DatabaseConnection, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Go(func() {
+			conns[i] = GetDatabaseConnection(fmt.Sprintf("postgres://db%d", i))
+		})
+	}
+	wg.Wait()
+
+	for i, c := range conns {
+		if c == nil || c != conns[0] {
+			t.Fatalf("caller %d got %p, caller 0 got %p: want one shared instance", i, c, conns[0])
+		}
+	}
+	// Later calls get the same instance, whatever they ask for
+	if c := GetDatabaseConnection("mysql://other"); c != conns[0] {
+		t.Errorf("later call got a new instance %+v", c)
+	}
+}
+
+func ExampleRepository() {
+	type user struct {
+		ID   int
+		Name string
+	}
+	users := NewRepository(func(u user) int { return u.ID })
+
+	users.Create(user{ID: 1, Name: "Alice"})
+	users.Update(user{ID: 1, Name: "Alicia"})
+
+	u, _ := users.Get(1) // u is a user: no type assertion needed
+	fmt.Println(u.Name)
+
+	_, err := users.Get(2)
+	fmt.Println(err, errors.Is(err, ErrNotFound))
+	// Output:
+	// Alicia
+	// get 2: not found true
+}
+
+func ExamplePaymentProcessor() {
+	// The same processor code, with the payment method swapped in
+	for _, strategy := range []PaymentStrategy{
+		&CreditCardPayment{cardNumber: "4111111111111111"},
+		&PayPalPayment{email: "ada@example.com"},
+	} {
+		NewPaymentProcessor(strategy).Process(25)
+	}
+	// Output:
+	// Paid 25.00 with credit card
+	// Paid 25.00 with PayPal (ada@example.com)
+}
+
+func ExampleVehicleFactory() {
+	var factory VehicleFactory
+	for _, kind := range []string{"car", "bicycle", "plane"} {
+		if v := factory.Create(kind); v != nil {
+			fmt.Println(v.Drive())
+		} else {
+			fmt.Println("no vehicle of kind", kind)
+		}
+	}
+	// Output:
+	// Driving car
+	// Riding bicycle
+	// no vehicle of kind plane
+}
+
+func ExampleSubject() {
+	news := NewSubject()
+	news.Subscribe(&ConcreteObserver{name: "Alice"})
+	news.Subscribe(&ConcreteObserver{name: "Bob"})
+
+	news.Notify("Go 1.25 released")
+	// Output:
+	// Alice received: Go 1.25 released
+	// Bob received: Go 1.25 released
+}
@@ -0,0 +1,264 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/internal/geometry"
+)
+
+// COURSE 3: STRUCTS AND INTERFACES
+// Topics covered:
+// 1. Struct definition and initialization
+// 2. Struct fields and visibility
+// 3. Receiver functions (methods)
+// 4. Pointer receivers
+// 5. Interfaces
+// 6. Type assertion
+// 7. Embedding (composition)
+// 8. Value vs pointer semantics
+
+// ============ 1. BASIC STRUCT ============
+type Person struct {
+	Name string
+	Age  int
+	City string
+}
+
+// ============ 2-5. SHAPES ============
+// Rectangle (methods, value and pointer receivers), the Shape interface and
+// the Circle and Triangle that satisfy it live in their own package,
+// internal/geometry, where they are tested. Types from another package are
+// used with its name in front: geometry.Rectangle{Width: 5, Height: 10}.
+
+// ============ 6. READER INTERFACE (common in Go) ============
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+type Writer interface {
+	Write(p []byte) (n int, err error)
+}
+
+// ReadWriter combines multiple interfaces
+type ReadWriter interface {
+	Reader
+	Writer
+}
+
+// ============ 7. EMBEDDING (COMPOSITION) ============
+type VehicleComp struct {
+	Brand string
+	Year  int
+}
+
+// CarComp embeds VehicleComp (inherits its fields and methods)
+type CarComp struct {
+	VehicleComp
+	Model string
+	Doors int
+}
+
+func (v VehicleComp) Display() string {
+	return fmt.Sprintf("%d %s", v.Year, v.Brand)
+}
+
+// ============ 8. EMPTY INTERFACE ============
+// interface{} can hold any type - useful for generic data
+type DataStore struct {
+	Data map[string]interface{}
+}
+
+func (db *DataStore) Store(key string, value interface{}) {
+	if db.Data == nil {
+		db.Data = make(map[string]interface{})
+	}
+	db.Data[key] = value
+}
+
+func (db *DataStore) Retrieve(key string) (interface{}, bool) {
+	value, exists := db.Data[key]
+	return value, exists
+}
+
+// ============ 9. FUNCTION THAT TAKES INTERFACE ============
+// This function works with ANY type that implements Reader
+func ProcessData(out *demo.Printer, r Reader) {
+	buffer := make([]byte, 10)
+	n, _ := r.Read(buffer)
+	out.Printf("Read %d bytes\n", n)
+}
+
+// ============ 10. TYPE ASSERTION ============
+// Type assertion is used to extract concrete type from interface
+func PrintInterface(out *demo.Printer, data interface{}) {
+	switch v := data.(type) {
+	case string:
+		out.Printf("String: %s\n", v)
+	case int:
+		out.Printf("Integer: %d\n", v)
+	case float64:
+		out.Printf("Float: %.2f\n", v)
+	case Person:
+		out.Printf("Person: %s, Age: %d\n", v.Name, v.Age)
+	default:
+		out.Printf("Unknown type: %T\n", v)
+	}
+}
+
+// ============ 11. CUSTOM STRINGER INTERFACE ============
+type Animal struct {
+	Name   string
+	Type   string
+	Sounds []string
+}
+
+// Implementing the Stringer interface (fmt.Stringer)
+// When you print an object with %v, it calls String() if available
+func (a Animal) String() string {
+	return fmt.Sprintf("%s (a %s) says: %v", a.Name, a.Type, a.Sounds)
+}
+
+// ============ COURSE THREE MAIN FUNCTION ============
+func CourseThree(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 3)
+
+	l.Section("struct-basics")
+
+	// Method 1: Declare and initialize with field names
+	person1 := Person{
+		Name: "Alice",
+		Age:  30,
+		City: "New York",
+	}
+	l.Printf("Person 1: %+v\n", person1) // %+v includes field names
+
+	// Method 2: Declare and initialize with positional args
+	person2 := Person{"Bob", 25, "Los Angeles"}
+	l.Printf("Person 2: %v\n", person2)
+
+	// Method 3: Initialize without values (zero-initialized)
+	person3 := Person{}
+	l.Printf("Person 3 (zero values): %v\n", person3)
+
+	// Field access
+	l.Printf("Person 1 name: %s\n", person1.Name)
+
+	l.Section("methods")
+
+	rect := geometry.Rectangle{Width: 5, Height: 10}
+	l.Printf("Rectangle: %v x %v\n", rect.Width, rect.Height)
+	l.Printf("Area: %.2f\n", rect.Area())
+	l.Printf("Perimeter: %.2f\n", rect.Perimeter())
+
+	l.Section("value-vs-pointer")
+
+	rect2 := geometry.Rectangle{Width: 2, Height: 3}
+	l.Printf("Original: %v x %v\n", rect2.Width, rect2.Height)
+
+	// This creates a copy, doesn't modify original
+	rect2.Scale(2)
+	l.Printf("After Scale(2): %v x %v\n", rect2.Width, rect2.Height)
+
+	l.Section("interfaces")
+
+	// Different shapes implementing same interface
+	circle := geometry.Circle{Radius: 3}
+	rectangle := geometry.Rectangle{Width: 4, Height: 5}
+	triangle := geometry.Triangle{SideA: 3, SideB: 4, SideC: 5}
+
+	shapes := []geometry.Shape{circle, rectangle, triangle}
+
+	l.Println("All shapes and their properties:")
+	for i, shape := range shapes {
+		l.Printf("[%d] Area: %.2f, Perimeter: %.2f\n", i, shape.Area(), shape.Perimeter())
+	}
+
+	l.Section("embedding")
+
+	car := CarComp{
+		VehicleComp: VehicleComp{Brand: "Toyota", Year: 2022},
+		Model:       "Camry",
+		Doors:       4,
+	}
+
+	l.Printf("Car Model: %s\n", car.Model)
+	l.Printf("Vehicle Info: %s\n", car.Display()) // Inherited method
+	l.Printf("Full Info: %d %s %s\n", car.Year, car.Brand, car.Model)
+
+	l.Section("empty-interface")
+
+	db := &DataStore{}
+	db.Store("name", "Charlie")
+	db.Store("age", 35)
+	db.Store("salary", 75000.50)
+	db.Store("active", true)
+
+	keys := []string{"name", "age", "salary", "active"}
+	l.Println("Database contents:")
+	for _, key := range keys {
+		value, _ := db.Retrieve(key)
+		l.Printf("  %s: %v (type: %T)\n", key, value, value)
+	}
+
+	l.Section("type-assertion")
+
+	testData := []interface{}{
+		"Hello",
+		42,
+		3.14,
+		Person{Name: "David", Age: 28, City: "Chicago"},
+	}
+
+	l.Println("Type assertion examples:")
+	for _, data := range testData {
+		PrintInterface(l.Printer, data)
+	}
+
+	l.Section("stringer-interface")
+
+	dog := Animal{
+		Name:   "Rex",
+		Type:   "Dog",
+		Sounds: []string{"Woof", "Bark", "Growl"},
+	}
+
+	cat := Animal{
+		Name:   "Whiskers",
+		Type:   "Cat",
+		Sounds: []string{"Meow", "Purr", "Hiss"},
+	}
+
+	// When using %v with objects that implement Stringer, it uses String() method
+	l.Printf("%v\n", dog)
+	l.Printf("%v\n", cat)
+
+	l.Section("interface-satisfaction")
+
+	// Check if type implements interface (compile-time check)
+	// This line ensures Circle implements Shape, fails at compile if it doesn't
+	var _ geometry.Shape = circle
+	var _ geometry.Shape = rectangle
+	var _ geometry.Shape = triangle
+
+	// You can also do this with pointer receivers
+	var _ geometry.Shape = &rectangle
+
+	l.Resume()
+
+	l.Section("multiple-interfaces")
+
+	// An object can satisfy multiple interfaces
+	multiShapes := []geometry.Shape{circle, rectangle}
+	l.Printf("Multiple shapes: %d shapes satisfy Shape interface\n", len(multiShapes))
+
+	// But they don't all implement Reader interface
+	// (we don't have Read methods defined)
+
+	l.Section("common-interfaces")
+
+	l.End()
+	return nil
+}
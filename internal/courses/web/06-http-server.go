@@ -0,0 +1,616 @@
+package web
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/internal/respond"
+	"github.com/owolabijunior12/learning-golang/pkg/middleware"
+)
+
+// COURSE 6: HTTP SERVERS AND REST APIs
+// Topics covered:
+// 1. HTTP server basics
+// 2. Request and response handling
+// 3. Routing
+// 4. JSON encoding/decoding
+// 5. Query parameters
+// 6. URL parameters
+// 7. Updates, deletes and optimistic concurrency
+// 8. Form data
+// 9. Headers
+// 10. Middleware patterns
+// 11. Status codes
+// 12. Streaming responses
+
+// ============ 1. REQUEST/RESPONSE TYPES ============
+// validate tags are checked by validateStruct (course 18)
+type User struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name" validate:"required,min=2,max=50"`
+	Email string `json:"email" validate:"required,email"`
+	Age   int    `json:"age" validate:"min=0,max=150"`
+	// Version counts the updates, for optimistic concurrency: see
+	// RepositoryUserStore (course 19)
+	Version int `json:"version"`
+}
+
+// APIResponse is the body of every response. Writing it, as JSON or as
+// plain text, and turning errors into it is the job of internal/respond.
+type APIResponse = respond.Envelope
+
+// ============ 2. SIMPLE HANDLER ============
+func helloHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintf(w, "Hello, %s!\n", r.URL.Query().Get("name"))
+}
+
+// ============ 3. JSON RESPONSE HANDLER ============
+// jsonHandler encodes JSON itself, to show how it's done. The handlers
+// after it leave that to respond.OK and respond.Error, which also let the
+// client ask for plain text instead.
+func jsonHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	response := APIResponse{
+		Success: true,
+		Message: "JSON response successful",
+		Data: map[string]string{
+			"version": "1.0",
+			"status":  "running",
+		},
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// ============ 4. GET USER BY ID ============
+// In-memory database for demo. Handlers run concurrently, so the users live
+// in a concurrency-safe store (course 19) built on the generic repository
+// from course 12.
+var Users = NewDemoUsers()
+
+// NewDemoUsers is a store holding the three demo users.
+func NewDemoUsers() *RepositoryUserStore {
+	return NewRepositoryUserStore(map[int]User{
+		1: {ID: 1, Name: "Alice", Email: "alice@example.com", Age: 30},
+		2: {ID: 2, Name: "Bob", Email: "bob@example.com", Age: 25},
+		3: {ID: 3, Name: "Charlie", Email: "charlie@example.com", Age: 35},
+	})
+}
+
+func getUserHandler(w http.ResponseWriter, r *http.Request) {
+	// The router only calls us for "GET /users/{id}", so the ID is there
+	id, err := pathInt(r, "id")
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	user, exists := Users.Get(id)
+	if !exists {
+		respond.Error(w, r, ErrUserNotFound)
+		return
+	}
+	respond.OK(w, r, http.StatusOK, "User found", user)
+}
+
+// pathInt returns the wildcard called name in the route's pattern, e.g. the
+// {id} of "GET /users/{id}", as an int.
+func pathInt(r *http.Request, name string) (int, error) {
+	v := r.PathValue(name)
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s %q is not a number", respond.ErrInvalid, name, v)
+	}
+	return n, nil
+}
+
+// ============ 5. CREATE USER (POST) ============
+func createUserHandler(w http.ResponseWriter, r *http.Request) {
+	var user User
+	if err := decodeJSON(r, &user); err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	// Validate before storing - see course 18
+	if err := validateStruct(user); err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	// The store assigns the new ID
+	user = Users.Create(user)
+	respond.OK(w, r, http.StatusCreated, "User created", user)
+}
+
+// decodeJSON decodes the request body into v. Unknown fields are an error:
+// a typo in a field name would otherwise be silently dropped.
+func decodeJSON(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", respond.ErrInvalid, err)
+	}
+	return nil
+}
+
+// ============ 6. LIST ALL USERS ============
+func listUsersHandler(w http.ResponseWriter, r *http.Request) {
+	respond.OK(w, r, http.StatusOK, "Users retrieved", Users.List())
+}
+
+// ============ 7. UPDATE USER (PUT/PATCH) ============
+// PUT replaces the whole user, PATCH only the fields it sends. Both need
+// the version the client last read: if the user changed since, the update
+// is refused with 409 Conflict and the current user, instead of silently
+// overwriting someone else's change.
+func updateUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt(r, "id")
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	var user User
+	if err := decodeJSON(r, &user); err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	user.ID = id // the path decides which user, not the body
+
+	if err := validateStruct(user); err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	writeUpdate(w, r, user)
+}
+
+// userPatch is a PATCH body. A nil field is left as it is.
+type userPatch struct {
+	Name    *string `json:"name"`
+	Email   *string `json:"email"`
+	Age     *int    `json:"age"`
+	Version int     `json:"version"`
+}
+
+func patchUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt(r, "id")
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	var patch userPatch
+	if err := decodeJSON(r, &patch); err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	user, exists := Users.Get(id)
+	if !exists {
+		respond.Error(w, r, ErrUserNotFound)
+		return
+	}
+	// If someone updates the user between Get and Update, the version
+	// check in Update still catches it: the patch keeps the client's version
+	user.Version = patch.Version
+	if patch.Name != nil {
+		user.Name = *patch.Name
+	}
+	if patch.Email != nil {
+		user.Email = *patch.Email
+	}
+	if patch.Age != nil {
+		user.Age = *patch.Age
+	}
+
+	if err := validateStruct(user); err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	writeUpdate(w, r, user)
+}
+
+// writeUpdate stores user and replies with the result. A conflict comes
+// with the current user, so the client can reapply its change to it and
+// retry with the new version.
+func writeUpdate(w http.ResponseWriter, r *http.Request, user User) {
+	updated, err := Users.Update(user)
+	if errors.Is(err, ErrVersionConflict) {
+		err = respond.WithData(err, updated)
+	}
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	respond.OK(w, r, http.StatusOK, "User updated", updated)
+}
+
+// ============ 8. DELETE USER ============
+// DELETE /users/{id}?version=N deletes the user only if it is still at
+// version N; without ?version it deletes whatever is there.
+func deleteUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := pathInt(r, "id")
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	version := 0
+	if v := r.URL.Query().Get("version"); v != "" {
+		if version, err = strconv.Atoi(v); err != nil || version < 1 {
+			respond.Error(w, r, fmt.Errorf("%w: version %q is not a positive number", respond.ErrInvalid, v))
+			return
+		}
+	}
+
+	current, err := Users.Delete(id, version)
+	if errors.Is(err, ErrVersionConflict) {
+		err = respond.WithData(err, current)
+	}
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ============ 9. QUERY PARAMETERS ============
+// UserSearch finds the users matching a search, best match first.
+// searchNames is the simple one; course 27 makes one from a full-text
+// index, which ranks, stems and doesn't scan every user.
+type UserSearch func(ctx context.Context, q string) ([]User, error)
+
+// searchNames finds the users whose name contains every word of q, in any
+// case, by ID.
+func searchNames(ctx context.Context, q string) ([]User, error) {
+	words := strings.Fields(strings.ToLower(q))
+	var found []User
+	for _, user := range Users.List() {
+		name := strings.ToLower(user.Name)
+		if !slices.ContainsFunc(words, func(w string) bool { return !strings.Contains(name, w) }) {
+			found = append(found, user)
+		}
+	}
+	return found, nil
+}
+
+// SearchHandler is GET /search?q=...&minAge=...&maxAge=...: the users
+// search finds for q, of an age in the range. name is what q used to be
+// called, for older clients.
+func SearchHandler(search UserSearch) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Get query parameters
+		q := cmp.Or(r.URL.Query().Get("q"), r.URL.Query().Get("name"))
+		minAge := r.URL.Query().Get("minAge")
+		maxAge := r.URL.Query().Get("maxAge")
+
+		var minAgeInt, maxAgeInt int = 0, 150
+		if minAge != "" {
+			minAgeInt, _ = strconv.Atoi(minAge)
+		}
+		if maxAge != "" {
+			maxAgeInt, _ = strconv.Atoi(maxAge)
+		}
+
+		found, err := search(r.Context(), q)
+		if err != nil {
+			respond.Error(w, r, err)
+			return
+		}
+		var results []User
+		for _, user := range found {
+			if user.Age >= minAgeInt && user.Age <= maxAgeInt {
+				results = append(results, user)
+			}
+		}
+
+		respond.OK(w, r, http.StatusOK, fmt.Sprintf("Found %d users", len(results)), results)
+	}
+}
+
+// ============ 10. FORM DATA ============
+// GET /form shows the form, POST /form receives it: the router picks the
+// handler by method, so neither has to check r.Method.
+func formHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, `<form method="post">
+			Name: <input type="text" name="name"><br>
+			Email: <input type="email" name="email"><br>
+			<input type="submit" value="Submit">
+		</form>`)
+}
+
+func submitFormHandler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	name := r.FormValue("name")
+	email := r.FormValue("email")
+
+	respond.OK(w, r, http.StatusOK, "Form received", map[string]string{
+		"name":  name,
+		"email": email,
+	})
+}
+
+// ============ 11. REQUEST HEADERS ============
+func headersHandler(w http.ResponseWriter, r *http.Request) {
+	headers := make(map[string]string)
+	for key, values := range r.Header {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+
+	respond.OK(w, r, http.StatusOK, "Request headers", headers)
+}
+
+// ============ 12. REQUEST BODY ============
+func echoBytesHandler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	respond.OK(w, r, http.StatusOK, "Echo", map[string]interface{}{
+		"received": string(body),
+		"length":   len(body),
+	})
+}
+
+// ============ 13. MIDDLEWARE PATTERN ============
+// Request IDs, logging, CORS and panic recovery come from pkg/middleware.
+// Auth depends on the application, so it is written here.
+
+// Auth middleware (simple example)
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if token != "Bearer valid-token" {
+			respond.Error(w, r, fmt.Errorf("%w: missing or invalid bearer token", respond.ErrUnauthorized))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ============ 14. THE SERVER ============
+// newServer routes the handlers above and wraps them in middleware.
+//
+// Since Go 1.22 a ServeMux pattern can name a method and wildcards:
+// "GET /users/{id}" matches GET (and HEAD) of /users/1 and hands the
+// handler "1" as r.PathValue("id"). A path whose pattern exists for other
+// methods only gets 405 Method Not Allowed, with an Allow header listing
+// them; "/{$}" matches "/" alone, where "/" would match every path.
+//
+// Every request gets an ID first, so everything after can log it: the
+// access log, Recover's stack traces and, through ContextHandler, any slog
+// record logged with r.Context(). The ID is also in the X-Request-Id
+// response header, for the client to quote when something goes wrong.
+// Recover comes next so it also catches panics in the middleware after it.
+func newServer(logger *slog.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", helloHandler)
+	mux.HandleFunc("GET /json", jsonHandler)
+	mux.HandleFunc("GET /users", listUsersHandler)
+	mux.HandleFunc("POST /users", createUserHandler)
+	mux.HandleFunc("GET /users/{id}", getUserHandler)
+	mux.HandleFunc("PUT /users/{id}", updateUserHandler)
+	mux.HandleFunc("PATCH /users/{id}", patchUserHandler)
+	mux.HandleFunc("DELETE /users/{id}", deleteUserHandler)
+	mux.HandleFunc("GET /search", SearchHandler(searchNames))
+	mux.HandleFunc("GET /form", formHandler)
+	mux.HandleFunc("POST /form", submitFormHandler)
+	mux.HandleFunc("GET /headers", headersHandler)
+	mux.HandleFunc("POST /echo", echoBytesHandler)
+	mux.HandleFunc("GET /events", eventsHandler)
+	mux.HandleFunc("GET /export.csv", exportCSVHandler)
+	mux.Handle("GET /protected", authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond.OK(w, r, http.StatusOK, "Protected resource", nil)
+	})))
+
+	logger = slog.New(middleware.ContextHandler(logger.Handler()))
+	return middleware.Chain(mux,
+		middleware.RequestID(),
+		middleware.Recover(slog.NewLogLogger(logger.Handler(), slog.LevelError)),
+		middleware.Slog(logger),
+	)
+}
+
+// ============ 15. CALLING OTHER SERVICES ============
+// newServiceClient is the client a handler uses to call other services. It
+// sends the request ID along, so their logs can be matched with ours - as
+// long as each request is built with the incoming request's context:
+//
+//	req, err := http.NewRequestWithContext(r.Context(), "GET", url, nil)
+//	resp, err := client.Do(req)
+//
+// The timeout matters too: http.DefaultClient waits forever.
+func newServiceClient() *http.Client {
+	return &http.Client{
+		Transport: middleware.PropagateRequestID(nil),
+		Timeout:   5 * time.Second,
+	}
+}
+
+// ============ 16. STREAMING RESPONSES ============
+// The handlers above build the whole body, then encode it in one go. That
+// is fine for a user or two, but an export of a million rows would sit in
+// memory first, and a client waiting for news would hear nothing until
+// the handler returned.
+//
+// A ResponseWriter streams anyway: net/http sends the body in chunks
+// (Transfer-Encoding: chunked, when there's no Content-Length) each time
+// its buffer of a few KB fills up. So write as you go instead of building
+// the body first, and Flush when the client should see something now.
+
+// eventInterval is the time between two events. Tests shorten it.
+var eventInterval = time.Second
+
+// eventsHandler sends ?count= lines (5 by default), one per eventInterval,
+// flushing each so the client gets it at once. Server-sent events and
+// progress reports work like this.
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	count, err := queryInt(r, "count", 5, 100)
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	// ResponseController finds the Flush method even through middleware
+	// that wraps the ResponseWriter, as long as the wrapper has Unwrap
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff") // or browsers buffer it to sniff the type
+
+	ticker := time.NewTicker(eventInterval)
+	defer ticker.Stop()
+	for i := 1; i <= count; i++ {
+		fmt.Fprintf(w, "event %d of %d\n", i, count)
+		if err := rc.Flush(); err != nil {
+			return // this ResponseWriter can't flush, or the client is gone
+		}
+		if i == count {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return // the client hung up: stop working for nobody
+		}
+	}
+}
+
+// exportCSVHandler streams ?rows= generated users (100000 by default) as
+// CSV. Each row goes straight to the ResponseWriter, through the small
+// buffer of the csv.Writer, so memory use is the same for 10 rows or 10
+// million. Once the first chunk is out the status is sent too: an error
+// after that can only cut the response short, not turn it into a 500.
+func exportCSVHandler(w http.ResponseWriter, r *http.Request) {
+	rows, err := queryInt(r, "rows", 100_000, 10_000_000)
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="users.csv"`)
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "name", "email", "age"})
+	for i := 1; i <= rows; i++ {
+		id := strconv.Itoa(i)
+		err := cw.Write([]string{id, "user" + id, "user" + id + "@example.com", strconv.Itoa(18 + i%60)})
+		if err != nil {
+			return // a write failed: the client is gone
+		}
+	}
+	cw.Flush()
+}
+
+// queryInt reads the query parameter name as an int from 1 to max, or
+// returns def if it is missing.
+func queryInt(r *http.Request, name string, def, max int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > max {
+		return 0, fmt.Errorf("%w: %s must be a number from 1 to %d", respond.ErrInvalid, name, max)
+	}
+	return n, nil
+}
+
+// ============ 17. CONSUMING A STREAM ============
+// client.Do returns as soon as the status and headers have arrived; the
+// body is read from the connection as the client reads resp.Body. Reading
+// it line by line or row by row handles each piece as it comes, in memory
+// that doesn't grow with the body - io.ReadAll would undo both.
+//
+// Don't give a streaming client an http.Client Timeout: it limits the
+// whole exchange, body included, and would cut a long stream off. Cancel
+// the context instead.
+
+// readEvents calls url and hands each line to f as soon as it arrives.
+func readEvents(ctx context.Context, client *http.Client, url string, f func(line string)) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		f(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// csvAges reads a CSV export row by row and returns how many users it has
+// and their average age.
+func csvAges(ctx context.Context, client *http.Client, url string) (users int, avgAge float64, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	cr := csv.NewReader(resp.Body)
+	cr.ReuseRecord = true // one slice for every row, since we keep none
+	if _, err := cr.Read(); err != nil {
+		return 0, 0, fmt.Errorf("reading the header: %w", err)
+	}
+	total := 0
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("row %d: %w", users+1, err)
+		}
+		age, err := strconv.Atoi(row[3])
+		if err != nil {
+			return 0, 0, fmt.Errorf("row %d: age: %w", users+1, err)
+		}
+		users++
+		total += age
+	}
+	if users == 0 {
+		return 0, 0, nil
+	}
+	return users, float64(total) / float64(users), nil
+}
+
+// ============ COURSE SIX MAIN FUNCTION (Demo, not executed) ============
+// Note: This demonstrates setup only. To actually run a server:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+//	http.ListenAndServe(":8080", newServer(logger))
+func CourseSix(ctx context.Context, w io.Writer) error {
+	demo.Print(ctx, w, 6)
+	return nil
+}
@@ -0,0 +1,282 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/internal/respond"
+)
+
+// COURSE 18: VALIDATING REQUEST PAYLOADS
+// Topics covered:
+// 1. Why decoding JSON is not the same as validating it
+// 2. Field-level errors that clients can act on
+// 3. Struct tags and reading them with reflect
+// 4. A rule registry for tag-driven validation
+// 5. Custom validators (new rules and struct-level checks)
+// 6. Wiring validation into createUserHandler from course 6
+
+// ============ 1. FIELD-LEVEL ERRORS ============
+// FieldError describes one invalid field, named the way the client sent it.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors collects every invalid field so the client can fix them all at once.
+type FieldErrors []FieldError
+
+func (fe FieldErrors) Error() string {
+	parts := make([]string, len(fe))
+	for i, e := range fe {
+		parts[i] = e.Field + ": " + e.Message
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// Unwrap makes FieldErrors a respond.ErrValidation, and ErrorData sends the
+// list along, so respond.Error answers 400 with one entry per field.
+func (fe FieldErrors) Unwrap() error          { return respond.ErrValidation }
+func (fe FieldErrors) ErrorData() interface{} { return fe }
+
+// ============ 2. VALIDATION RULES ============
+// ValidationRule checks one field. param is the text after "=" in the tag
+// (e.g. "2" for min=2). It returns an empty string when the value is valid.
+type ValidationRule func(value reflect.Value, param string) string
+
+var validationRules = map[string]ValidationRule{
+	"required": ruleRequired,
+	"min":      ruleMin,
+	"max":      ruleMax,
+	"email":    ruleEmail,
+	"oneof":    ruleOneOf,
+}
+
+// registerRule adds a custom rule that can then be used in validate tags
+func registerRule(name string, rule ValidationRule) {
+	validationRules[name] = rule
+}
+
+func ruleRequired(value reflect.Value, _ string) string {
+	if value.IsZero() {
+		return "is required"
+	}
+	if value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" {
+		return "is required"
+	}
+	return ""
+}
+
+// min and max compare the length of strings and the value of numbers
+func ruleMin(value reflect.Value, param string) string {
+	limit, _ := strconv.Atoi(param)
+	switch value.Kind() {
+	case reflect.String:
+		if len([]rune(value.String())) < limit {
+			return fmt.Sprintf("must be at least %d characters", limit)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < int64(limit) {
+			return fmt.Sprintf("must be at least %d", limit)
+		}
+	}
+	return ""
+}
+
+func ruleMax(value reflect.Value, param string) string {
+	limit, _ := strconv.Atoi(param)
+	switch value.Kind() {
+	case reflect.String:
+		if len([]rune(value.String())) > limit {
+			return fmt.Sprintf("must be at most %d characters", limit)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() > int64(limit) {
+			return fmt.Sprintf("must be at most %d", limit)
+		}
+	}
+	return ""
+}
+
+func ruleEmail(value reflect.Value, _ string) string {
+	email := value.String()
+	if email == "" {
+		return "" // leave empty values to "required"
+	}
+	at := strings.Index(email, "@")
+	if at < 1 || at != strings.LastIndex(email, "@") || !strings.Contains(email[at:], ".") {
+		return "must be a valid email address"
+	}
+	return ""
+}
+
+// oneof takes space-separated choices: oneof=admin editor viewer
+func ruleOneOf(value reflect.Value, param string) string {
+	for _, choice := range strings.Fields(param) {
+		if fmt.Sprint(value.Interface()) == choice {
+			return ""
+		}
+	}
+	return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+}
+
+// ============ 3. STRUCT-LEVEL VALIDATION ============
+// Some checks need several fields at once (password confirmation, date
+// ranges). Types implement this interface to add them.
+type selfValidator interface {
+	Validate() FieldErrors
+}
+
+// ============ 4. THE VALIDATOR ============
+// validateStruct checks every field with a validate tag, then calls Validate
+// if the type implements selfValidator. It returns FieldErrors or nil.
+func validateStruct(v interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validateStruct: expected a struct, got %s", value.Kind()))
+	}
+
+	var errs FieldErrors
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+
+		for _, spec := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(spec, "=")
+			rule, ok := validationRules[name]
+			if !ok {
+				// A typo in a tag is a programmer error, not bad input
+				panic(fmt.Sprintf("validateStruct: unknown rule %q on %s.%s", name, t.Name(), field.Name))
+			}
+			if msg := rule(value.Field(i), param); msg != "" {
+				errs = append(errs, FieldError{Field: jsonFieldName(field), Message: msg})
+				break // one message per field is enough
+			}
+		}
+	}
+
+	if sv, ok := v.(selfValidator); ok {
+		errs = append(errs, sv.Validate()...)
+	}
+
+	if len(errs) == 0 {
+		return nil // a nil FieldErrors inside an error would not be == nil
+	}
+	return errs
+}
+
+// jsonFieldName reports errors with the name the client used
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// ============ 5. CUSTOM VALIDATORS ============
+// A custom rule: usernames that would confuse support staff
+func ruleNotReserved(value reflect.Value, _ string) string {
+	switch strings.ToLower(value.String()) {
+	case "admin", "root", "support":
+		return "is reserved"
+	}
+	return ""
+}
+
+// SignupRequest uses built-in rules, a custom rule and a struct-level check
+type SignupRequest struct {
+	Username        string `json:"username" validate:"required,min=3,max=20,notreserved"`
+	Email           string `json:"email" validate:"required,email"`
+	Password        string `json:"password" validate:"required,min=8"`
+	ConfirmPassword string `json:"confirm_password" validate:"required"`
+	Plan            string `json:"plan" validate:"oneof=free pro team"`
+}
+
+func (s SignupRequest) Validate() FieldErrors {
+	if s.ConfirmPassword != "" && s.Password != s.ConfirmPassword {
+		return FieldErrors{{Field: "confirm_password", Message: "must match password"}}
+	}
+	return nil
+}
+
+// ============ 6. HTTP RESPONSES ============
+// Handlers pass the error from validateStruct to respond.Error, which
+// sends a 400 with one entry per invalid field:
+// {"success":false,"message":"Bad Request","data":[{"field":"email","message":"..."}],"error":"validation failed: ...","code":"validation_failed"}
+
+// ============ COURSE EIGHTEEN MAIN FUNCTION ============
+func CourseEighteen(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 18)
+
+	l.Section("decoding-not-validating")
+	var decoded User
+	err := json.Unmarshal([]byte(`{"name":"","email":"nope","age":-4}`), &decoded)
+	l.Printf("json.Unmarshal error: %v\n", err)
+	l.Printf("Decoded user: %+v\n", decoded)
+	l.Resume()
+
+	l.Section("struct-tags")
+	userType := reflect.TypeOf(User{})
+	for i := 0; i < userType.NumField(); i++ {
+		field := userType.Field(i)
+		l.Printf("%-7s json=%-9q validate=%q\n", field.Name, field.Tag.Get("json"), field.Tag.Get("validate"))
+	}
+
+	l.Section("field-level-errors")
+	err = validateStruct(decoded)
+	if fieldErrs, ok := err.(FieldErrors); ok {
+		for _, fe := range fieldErrs {
+			l.Printf("  %-6s %s\n", fe.Field, fe.Message)
+		}
+	}
+	l.Printf("Valid user passes: err = %v\n", validateStruct(User{Name: "Dana", Email: "dana@example.com", Age: 41}))
+
+	l.Section("custom-validators")
+	registerRule("notreserved", ruleNotReserved)
+
+	signup := SignupRequest{
+		Username:        "admin",
+		Email:           "admin@example.com",
+		Password:        "hunter22",
+		ConfirmPassword: "hunter23",
+		Plan:            "enterprise",
+	}
+	l.Printf("Error: %v\n", validateStruct(signup))
+
+	signup.Username, signup.ConfirmPassword, signup.Plan = "dana", "hunter22", "pro"
+	l.Printf("Fixed request: err = %v\n", validateStruct(signup))
+
+	l.Section("wired-into-user")
+	// On a store of its own, so the IDs are the same however often this runs
+	saved := Users
+	Users = NewDemoUsers()
+	defer func() { Users = saved }()
+	bodies := []string{
+		`{"name":"Eve","email":"eve@example.com","age":29}`,
+		`{"name":"E","email":"eve.example.com","age":200}`,
+		`{"name":"Eve","email":"eve@example.com","age":29,"admin":true}`,
+	}
+	for _, body := range bodies {
+		rec := httptest.NewRecorder()
+		createUserHandler(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)))
+		l.Printf("POST %s\n  -> %d %s\n", body, rec.Code, strings.TrimSpace(rec.Body.String()))
+	}
+
+	l.Section("checklist")
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,309 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/patterns"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+	"github.com/owolabijunior12/learning-golang/internal/respond"
+)
+
+// COURSE 19: A CONCURRENCY-SAFE IN-MEMORY STORE
+// Topics covered:
+// 1. Why the course 6 users map was a data race
+// 2. Finding races with the race detector (-race)
+// 3. A store protected by sync.RWMutex
+// 4. A store built on sync.Map
+// 5. Benchmarking both (see 19-concurrent-store_test.go)
+// 6. Swapping the store into the HTTP handlers
+
+// ============ 1. THE RACE ============
+// net/http serves every request on its own goroutine. Course 6 read and wrote
+// a plain map from its handlers, so two requests at once could corrupt it.
+// Go detects concurrent map writes and kills the program:
+//
+//	fatal error: concurrent map writes
+//
+// racyCounter shows the same bug without crashing: increments get lost.
+// runtime.Gosched between the read and the write lets other goroutines run
+// there, so the lost updates show up even on a single CPU.
+func racyCounter(goroutines, increments int) int {
+	var wg sync.WaitGroup
+	count := 0
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				current := count // read
+				runtime.Gosched()
+				count = current + 1 // write - may overwrite another goroutine's update
+			}
+		}()
+	}
+	wg.Wait()
+	return count
+}
+
+// ============ 2. THE STORE INTERFACE ============
+// UserStore is what the HTTP handlers need. Both implementations below are
+// safe to use from many goroutines.
+type UserStore interface {
+	Get(id int) (User, bool)
+	List() []User
+	Create(user User) User
+}
+
+// ============ 3. MUTEX STORE ============
+// MutexUserStore guards a map with a sync.RWMutex: many readers or one writer.
+type MutexUserStore struct {
+	mu     sync.RWMutex
+	users  map[int]User
+	nextID int
+}
+
+func NewMutexUserStore(seed map[int]User) *MutexUserStore {
+	s := &MutexUserStore{users: make(map[int]User, len(seed))}
+	for id, user := range seed {
+		s.users[id] = user
+		if id > s.nextID {
+			s.nextID = id
+		}
+	}
+	return s
+}
+
+func (s *MutexUserStore) Get(id int) (User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	return user, ok
+}
+
+// List returns a copy sorted by ID - callers never see the internal map
+func (s *MutexUserStore) List() []User {
+	s.mu.RLock()
+	list := make([]User, 0, len(s.users))
+	for _, user := range s.users {
+		list = append(list, user)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Create assigns the next ID. Reading nextID and writing the map happen under
+// one lock; len(users)+1 would hand out duplicate IDs after a delete.
+func (s *MutexUserStore) Create(user User) User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	user.ID = s.nextID
+	s.users[user.ID] = user
+	return user
+}
+
+// ============ 4. sync.Map STORE ============
+// SyncMapUserStore uses sync.Map, which is optimised for keys that are written
+// once and read many times, or for goroutines working on disjoint keys.
+type SyncMapUserStore struct {
+	users  sync.Map // int -> User
+	nextID atomic.Int64
+}
+
+func NewSyncMapUserStore(seed map[int]User) *SyncMapUserStore {
+	s := &SyncMapUserStore{}
+	for id, user := range seed {
+		s.users.Store(id, user)
+		if int64(id) > s.nextID.Load() {
+			s.nextID.Store(int64(id))
+		}
+	}
+	return s
+}
+
+func (s *SyncMapUserStore) Get(id int) (User, bool) {
+	value, ok := s.users.Load(id)
+	if !ok {
+		return User{}, false
+	}
+	return value.(User), true // sync.Map stores interface{} - type assert on the way out
+}
+
+func (s *SyncMapUserStore) List() []User {
+	var list []User
+	s.users.Range(func(_, value interface{}) bool {
+		list = append(list, value.(User))
+		return true
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func (s *SyncMapUserStore) Create(user User) User {
+	user.ID = int(s.nextID.Add(1))
+	s.users.Store(user.ID, user)
+	return user
+}
+
+// ============ 5. LOAD TEST ============
+// hammerStore runs readers and writers against a store at the same time.
+// Every 10th operation is a write.
+func hammerStore(store UserStore, goroutines, opsPerGoroutine int) time.Duration {
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < opsPerGoroutine; i++ {
+				if i%10 == 0 {
+					store.Create(User{Name: fmt.Sprintf("user-%d-%d", g, i)})
+				} else {
+					store.Get(i%3 + 1)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// ============ 6. THE HANDLERS' STORE ============
+// RepositoryUserStore is the store the HTTP handlers use. The generic
+// repository from course 12 does the storing and locking; this adds what
+// the handlers rely on: server-assigned IDs, a List sorted by ID, and
+// versioned updates and deletes.
+//
+// Every user carries a Version, 1 when created and one more on each update.
+// A client sends back the version it last read, and Update or Delete fail
+// with ErrVersionConflict if someone else has changed the user since:
+// optimistic concurrency, which never loses an update and never holds a
+// lock while the client decides what to write.
+type RepositoryUserStore struct {
+	repo   *patterns.Repository[User, int]
+	nextID atomic.Int64
+
+	// mu makes checking the version and writing one step. The repository
+	// locks each call on its own, which isn't enough for that.
+	mu sync.Mutex
+}
+
+// The errors wrap the kinds from internal/respond, which turns them into
+// 404 and 409 responses.
+var (
+	ErrUserNotFound    = fmt.Errorf("user %w", respond.ErrNotFound)
+	ErrVersionConflict = fmt.Errorf("%w: user was changed by someone else", respond.ErrConflict)
+)
+
+func NewRepositoryUserStore(seed map[int]User) *RepositoryUserStore {
+	s := &RepositoryUserStore{repo: patterns.NewRepository(func(u User) int { return u.ID })}
+	for id, user := range seed {
+		user.ID, user.Version = id, 1
+		s.repo.Create(user)
+		if int64(id) > s.nextID.Load() {
+			s.nextID.Store(int64(id))
+		}
+	}
+	return s
+}
+
+func (s *RepositoryUserStore) Get(id int) (User, bool) {
+	user, err := s.repo.Get(id)
+	return user, err == nil
+}
+
+func (s *RepositoryUserStore) List() []User {
+	list := s.repo.List()
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Create never fails with ErrExists: each ID is handed out once.
+func (s *RepositoryUserStore) Create(user User) User {
+	user.ID, user.Version = int(s.nextID.Add(1)), 1
+	s.repo.Create(user)
+	return user
+}
+
+// Update replaces the user with user.ID if it is still at user.Version, and
+// returns it with its new version. On ErrVersionConflict it also returns
+// the current user, so the caller can show what changed.
+func (s *RepositoryUserStore) Update(user User) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, err := s.repo.Get(user.ID)
+	if err != nil {
+		return User{}, fmt.Errorf("update user %d: %w", user.ID, ErrUserNotFound)
+	}
+	if user.Version != current.Version {
+		return current, fmt.Errorf("update user %d at version %d, now %d: %w", user.ID, user.Version, current.Version, ErrVersionConflict)
+	}
+	user.Version++
+	if err := s.repo.Update(user); err != nil {
+		return User{}, fmt.Errorf("update user %d: %w", user.ID, err)
+	}
+	return user, nil
+}
+
+// Delete removes the user with the given ID if it is still at version.
+// Version 0 deletes whatever version is stored.
+func (s *RepositoryUserStore) Delete(id, version int) (User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, err := s.repo.Get(id)
+	if err != nil {
+		return User{}, fmt.Errorf("delete user %d: %w", id, ErrUserNotFound)
+	}
+	if version != 0 && version != current.Version {
+		return current, fmt.Errorf("delete user %d at version %d, now %d: %w", id, version, current.Version, ErrVersionConflict)
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return User{}, fmt.Errorf("delete user %d: %w", id, err)
+	}
+	return current, nil
+}
+
+// ============ COURSE NINETEEN MAIN FUNCTION ============
+func CourseNineteen(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 19)
+
+	l.Section("race")
+	got := racyCounter(8, 1000)
+	l.Printf("8 goroutines x 1000 increments = %d (expected 8000)\n", got)
+	l.Resume()
+
+	l.Section("race-detector")
+
+	l.Section("mutex-store")
+	seed := map[int]User{1: {ID: 1, Name: "Alice"}, 2: {ID: 2, Name: "Bob"}, 3: {ID: 3, Name: "Charlie"}}
+	mutexStore := NewMutexUserStore(seed)
+	elapsed := hammerStore(mutexStore, 8, 10000)
+	l.Printf("8 goroutines, 80000 mixed ops: %d users, no race, %v\n", len(mutexStore.List()), elapsed.Round(time.Millisecond))
+
+	l.Section("sync-map-store")
+	syncStore := NewSyncMapUserStore(seed)
+	elapsed = hammerStore(syncStore, 8, 10000)
+	l.Printf("8 goroutines, 80000 mixed ops: %d users, no race, %v\n", len(syncStore.List()), elapsed.Round(time.Millisecond))
+
+	l.Section("benchmarks")
+
+	l.Section("http-handlers")
+	l.Printf("users store has %d users: ", len(Users.List()))
+	for _, u := range Users.List() {
+		l.Printf("%s ", u.Name)
+	}
+
+	l.End()
+	return nil
+}
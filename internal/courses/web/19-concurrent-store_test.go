@@ -0,0 +1,210 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Run with: go test -race -run UserStore
+// Benchmarks: go test -run '^$' -bench UserStore -benchmem
+
+func seedUsers() map[int]User {
+	return map[int]User{
+		1: {ID: 1, Name: "Alice"},
+		2: {ID: 2, Name: "Bob"},
+		3: {ID: 3, Name: "Charlie"},
+	}
+}
+
+func TestUserStore_ConcurrentCreate(t *testing.T) {
+	stores := map[string]UserStore{
+		"mutex":      NewMutexUserStore(seedUsers()),
+		"syncmap":    NewSyncMapUserStore(seedUsers()),
+		"repository": NewRepositoryUserStore(seedUsers()),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			const goroutines, perGoroutine = 10, 100
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < perGoroutine; i++ {
+						store.Create(User{Name: fmt.Sprintf("user-%d-%d", g, i)})
+						store.Get(1)
+						store.List()
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			list := store.List()
+			if want := 3 + goroutines*perGoroutine; len(list) != want {
+				t.Fatalf("got %d users, want %d", len(list), want)
+			}
+
+			// IDs must be unique and List must be sorted
+			for i := 1; i < len(list); i++ {
+				if list[i].ID <= list[i-1].ID {
+					t.Fatalf("IDs not unique and sorted at %d: %d after %d", i, list[i].ID, list[i-1].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestUserStore_GetAndCreate(t *testing.T) {
+	stores := map[string]UserStore{
+		"mutex":      NewMutexUserStore(seedUsers()),
+		"syncmap":    NewSyncMapUserStore(seedUsers()),
+		"repository": NewRepositoryUserStore(seedUsers()),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if user, ok := store.Get(2); !ok || user.Name != "Bob" {
+				t.Errorf("Get(2) = %+v, %v; want Bob", user, ok)
+			}
+			if _, ok := store.Get(99); ok {
+				t.Error("Get(99) found a user that does not exist")
+			}
+
+			created := store.Create(User{ID: 1, Name: "Dana"}) // client IDs are ignored
+			if created.ID != 4 {
+				t.Errorf("Create assigned ID %d, want 4", created.ID)
+			}
+			if user, _ := store.Get(1); user.Name != "Alice" {
+				t.Errorf("Create overwrote user 1: %+v", user)
+			}
+		})
+	}
+}
+
+// Many goroutines update the same user from the version they all read:
+// exactly one wins, the rest get ErrVersionConflict.
+func TestRepositoryUserStore_ConcurrentUpdate(t *testing.T) {
+	store := NewRepositoryUserStore(seedUsers())
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	wins := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.Update(User{ID: 1, Name: fmt.Sprintf("Alice %d", i), Version: 1})
+			switch {
+			case err == nil:
+				mu.Lock()
+				wins++
+				mu.Unlock()
+			case !errors.Is(err, ErrVersionConflict):
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if wins != 1 {
+		t.Errorf("%d updates from version 1 succeeded, want 1", wins)
+	}
+	if user, _ := store.Get(1); user.Version != 2 {
+		t.Errorf("version = %d after one update, want 2", user.Version)
+	}
+}
+
+func TestRepositoryUserStore_UpdateAndDelete(t *testing.T) {
+	store := NewRepositoryUserStore(seedUsers())
+	if created := store.Create(User{Name: "Dave"}); created.Version != 1 {
+		t.Errorf("created at version %d, want 1", created.Version)
+	}
+
+	updated, err := store.Update(User{ID: 2, Name: "Robert", Version: 1})
+	if err != nil || updated.Version != 2 {
+		t.Fatalf("Update = %+v, %v", updated, err)
+	}
+	if current, err := store.Update(User{ID: 2, Name: "Bobby", Version: 1}); !errors.Is(err, ErrVersionConflict) || current.Name != "Robert" {
+		t.Errorf("stale Update = %+v, %v; want the current user and ErrVersionConflict", current, err)
+	}
+	if _, err := store.Update(User{ID: 99, Version: 1}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Update of a missing user: %v", err)
+	}
+
+	if _, err := store.Delete(2, 1); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("stale Delete: %v", err)
+	}
+	if _, err := store.Delete(2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Delete(2, 0); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("second Delete: %v", err)
+	}
+	if _, ok := store.Get(2); ok {
+		t.Error("user 2 still there after Delete")
+	}
+}
+
+// The handlers share the package-level store; -race fails this test if
+// they touch it without synchronisation.
+func TestUserHandlers_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"name":"Load %d","email":"load%d@example.com","age":30}`, i, i)
+			rec := httptest.NewRecorder()
+			createUserHandler(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body)))
+			if rec.Code != http.StatusCreated {
+				t.Errorf("create: status %d: %s", rec.Code, rec.Body.String())
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			listUsersHandler(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("list: status %d", rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// benchmarkUserStore runs a read-heavy mix: 1 write for every readsPerWrite reads
+func benchmarkUserStore(b *testing.B, store UserStore, readsPerWrite int) {
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i%(readsPerWrite+1) == 0 {
+				store.Create(User{Name: "bench"})
+			} else {
+				store.Get(i%3 + 1)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMutexUserStore_ReadHeavy(b *testing.B) {
+	benchmarkUserStore(b, NewMutexUserStore(seedUsers()), 99)
+}
+
+func BenchmarkSyncMapUserStore_ReadHeavy(b *testing.B) {
+	benchmarkUserStore(b, NewSyncMapUserStore(seedUsers()), 99)
+}
+
+func BenchmarkMutexUserStore_WriteHeavy(b *testing.B) {
+	benchmarkUserStore(b, NewMutexUserStore(seedUsers()), 1)
+}
+
+func BenchmarkSyncMapUserStore_WriteHeavy(b *testing.B) {
+	benchmarkUserStore(b, NewSyncMapUserStore(seedUsers()), 1)
+}
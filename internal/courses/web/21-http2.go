@@ -0,0 +1,389 @@
+package web
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// COURSE 21: HTTP/2
+// Topics covered:
+// 1. A self-signed certificate for local TLS
+// 2. Serving HTTP/2 over TLS
+// 3. Which protocol was negotiated, and how to tell
+// 4. Multiplexing: many requests on one connection
+// 5. Server push, and why it's gone
+// 6. Inspecting HTTP/2 from outside
+
+// HTTP/2 keeps HTTP's methods, headers and status codes, and changes how
+// they travel: binary frames, compressed headers, and many requests at once
+// on one TCP connection. net/http speaks it for you: handlers don't change.
+
+// ============ 1. A SELF-SIGNED CERTIFICATE ============
+// Browsers and Go's client only speak HTTP/2 over TLS, so a local server
+// needs a certificate. A real one comes from a CA such as Let's Encrypt;
+// for development, sign one yourself and tell the client to trust it.
+
+// selfSignedCert returns a certificate for hosts (names or IP addresses),
+// valid for validFor from now, and a pool holding it for clients to trust.
+// The key is made fresh every time and never written to disk.
+func selfSignedCert(validFor time.Duration, hosts ...string) (tls.Certificate, *x509.CertPool, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("generating the key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("generating the serial number: %w", err)
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"learning-golang dev"}},
+		NotBefore:    now.Add(-time.Minute), // some slack for clocks that are a little behind
+		NotAfter:     now.Add(validFor),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		// It signs itself, so it is its own CA
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	// Clients check the host they dialled against these, not the Subject
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("signing the certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool, nil
+}
+
+// ============ 2. SERVING HTTP/2 ============
+// http.Server speaks HTTP/2 on TLS connections by default. Protocols says
+// so explicitly. During the TLS handshake client and server agree on one of
+// them through ALPN (application-layer protocol negotiation): "h2" for
+// HTTP/2, "http/1.1" otherwise.
+
+// tlsServer is a server listening on a free port of 127.0.0.1.
+type tlsServer struct {
+	URL    string
+	Pool   *x509.CertPool // trusts the server's certificate
+	server *http.Server
+}
+
+// startTLSServer serves handler over TLS with a fresh self-signed
+// certificate, speaking the given protocols. Close stops it.
+func startTLSServer(handler http.Handler, protocols *http.Protocols) (*tlsServer, error) {
+	cert, pool, err := selfSignedCert(24*time.Hour, "127.0.0.1", "localhost")
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		TLSConfig:         &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12},
+		Protocols:         protocols,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go server.ServeTLS(ln, "", "") // the certificate is in TLSConfig
+	return &tlsServer{URL: "https://" + ln.Addr().String(), Pool: pool, server: server}, nil
+}
+
+func (s *tlsServer) Close() error {
+	return s.server.Close()
+}
+
+// http2Protocols allows HTTP/1.1 and HTTP/2; http1Protocols only HTTP/1.1.
+func http2Protocols() *http.Protocols {
+	p := new(http.Protocols)
+	p.SetHTTP1(true)
+	p.SetHTTP2(true)
+	return p
+}
+
+func http1Protocols() *http.Protocols {
+	p := new(http.Protocols)
+	p.SetHTTP1(true)
+	return p
+}
+
+// newTLSClient returns a client that trusts pool and offers protocols.
+// A Transport with a TLSClientConfig of its own only tries HTTP/2 when
+// told to, which Protocols does.
+func newTLSClient(pool *x509.CertPool, protocols *http.Protocols) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+			Protocols:       protocols,
+		},
+		Timeout: 10 * time.Second,
+	}
+}
+
+// ============ 3. WHICH PROTOCOL? ============
+// On the server, r.Proto is "HTTP/2.0" or "HTTP/1.1", and r.TLS tells what
+// ALPN picked. On the client, resp.Proto and resp.TLS say the same.
+
+// protoHandler reports how the request arrived.
+func protoHandler(w http.ResponseWriter, r *http.Request) {
+	alpn := "none"
+	if r.TLS != nil && r.TLS.NegotiatedProtocol != "" {
+		alpn = r.TLS.NegotiatedProtocol
+	}
+	fmt.Fprintf(w, "server saw %s (ALPN %q)", r.Proto, alpn)
+}
+
+// fetchProto asks the server how it sees the request, and says how the
+// client sees the response.
+func fetchProto(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("client got %s, %s", resp.Proto, body), nil
+}
+
+// ============ 4. MULTIPLEXING ============
+// HTTP/1.1 answers one request at a time per connection, so a client
+// sending ten at once opens up to ten connections, each with its own TCP
+// and TLS handshake. HTTP/2 sends them all as streams on one connection,
+// their frames interleaved.
+
+// multiplexResult is what concurrentRequests saw.
+type multiplexResult struct {
+	Requests    int
+	InFlight    int // the most requests the server was handling at once
+	Connections int // distinct TCP connections they arrived on
+}
+
+// concurrentRequests makes n requests at once and counts the connections
+// they used. The server holds every request until all n have arrived (or
+// a second has passed), so they really are in flight together.
+func concurrentRequests(n int, serverProtocols, clientProtocols *http.Protocols) (multiplexResult, error) {
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxIn    int
+		conns    = map[string]bool{} // remote address -> seen
+		all      = make(chan struct{})
+		arrived  int
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		conns[r.RemoteAddr] = true
+		inFlight++
+		maxIn = max(maxIn, inFlight)
+		if arrived++; arrived == n {
+			close(all)
+		}
+		mu.Unlock()
+
+		select {
+		case <-all:
+		case <-time.After(time.Second):
+		}
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	})
+
+	server, err := startTLSServer(handler, serverProtocols)
+	if err != nil {
+		return multiplexResult{}, err
+	}
+	defer server.Close()
+	client := newTLSClient(server.Pool, clientProtocols)
+	defer client.CloseIdleConnections()
+
+	// A first request opens the connection; without it the n requests
+	// below would race to open one each before any knew about HTTP/2
+	resp, err := client.Get(server.URL + "/warm-up")
+	if err != nil {
+		return multiplexResult{}, err
+	}
+	resp.Body.Close()
+	mu.Lock()
+	arrived, maxIn, conns = 0, 0, map[string]bool{}
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.Get(fmt.Sprintf("%s/item/%d", server.URL, i))
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp.Body.Close()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	if err := errors.Join(drain(errs)...); err != nil {
+		return multiplexResult{}, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return multiplexResult{Requests: n, InFlight: maxIn, Connections: len(conns)}, nil
+}
+
+func drain(errs <-chan error) []error {
+	var list []error
+	for err := range errs {
+		list = append(list, err)
+	}
+	return list
+}
+
+// ============ 5. SERVER PUSH ============
+// HTTP/2 let a server send responses nobody had asked for yet - the CSS a
+// page was about to request - through http.Pusher. In practice it often
+// sent things the browser already had cached, and browsers have dropped
+// it; Go's own client turns it off. Preload hints (a Link header, or 103
+// Early Hints) do the job instead: the browser decides what to fetch.
+
+// pushHandler tries to push /style.css and reports what happened.
+func pushHandler(w http.ResponseWriter, r *http.Request) {
+	pusher, ok := w.(http.Pusher)
+	if !ok {
+		fmt.Fprintf(w, "%s: this ResponseWriter can't push at all", r.Proto)
+		return
+	}
+	if err := pusher.Push("/style.css", nil); err != nil {
+		// The client said no: its SETTINGS_ENABLE_PUSH is 0
+		fmt.Fprintf(w, "%s: push refused: %v", r.Proto, err)
+		return
+	}
+	fmt.Fprintf(w, "%s: pushed /style.css", r.Proto)
+}
+
+// ============ 6. INSPECTING HTTP/2 ============
+// From outside the program (see the lesson):
+//   curl -v --http2 -k https://localhost:8443/   shows "ALPN: server accepted h2"
+//   GODEBUG=http2debug=1 go run .                logs every frame net/http sends
+//   the browser's network tab                     has a Protocol column ("h2")
+
+// ============ COURSE TWENTY-ONE MAIN FUNCTION ============
+func CourseTwentyOne(ctx context.Context, w io.Writer) error {
+	l := demo.Start(ctx, w, 21)
+
+	l.Section("self-signed-cert")
+	cert, pool, err := selfSignedCert(24*time.Hour, "localhost", "127.0.0.1")
+	if err != nil {
+		return err
+	}
+	leaf := cert.Leaf
+	l.Printf("Organization: %s\n", leaf.Subject.Organization[0])
+	l.Printf("Valid for:    %v %v\n", leaf.DNSNames, leaf.IPAddresses)
+	l.Printf("Lifetime:     %v (plus a minute of slack)\n", leaf.NotAfter.Sub(leaf.NotBefore)-time.Minute)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: pool})
+	l.Printf("Trusted for localhost by its own pool: %v\n", err == nil)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "localhost"})
+	l.Printf("Trusted by the system roots: %v\n", err == nil)
+	l.Resume()
+
+	l.Section("serving-h2")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", protoHandler)
+	mux.HandleFunc("/push", pushHandler)
+	h2, err := startTLSServer(mux, http2Protocols())
+	if err != nil {
+		return err
+	}
+	defer h2.Close()
+	l.Println("Serving HTTP/1.1 and HTTP/2 over TLS on 127.0.0.1")
+
+	l.Section("negotiated-protocol")
+	for _, c := range []struct {
+		name      string
+		protocols *http.Protocols
+	}{
+		{"HTTP/2 client", http2Protocols()},
+		{"HTTP/1.1-only client", http1Protocols()},
+	} {
+		client := newTLSClient(h2.Pool, c.protocols)
+		got, err := fetchProto(client, h2.URL)
+		if err != nil {
+			return err
+		}
+		l.Printf("%-21s %s\n", c.name+":", got)
+		client.CloseIdleConnections()
+	}
+	l.Resume()
+
+	l.Section("multiplexing")
+	for _, c := range []struct {
+		name      string
+		protocols *http.Protocols
+	}{
+		{"HTTP/2", http2Protocols()},
+		{"HTTP/1.1", http1Protocols()},
+	} {
+		res, err := concurrentRequests(10, c.protocols, c.protocols)
+		if err != nil {
+			return err
+		}
+		l.Printf("%-9s %d requests, %d in flight at once, over %d connection(s)\n", c.name+":", res.Requests, res.InFlight, res.Connections)
+	}
+	l.Resume()
+
+	l.Section("server-push")
+	for _, protocols := range []*http.Protocols{http2Protocols(), http1Protocols()} {
+		client := newTLSClient(h2.Pool, protocols)
+		resp, err := client.Get(h2.URL + "/push")
+		if err != nil {
+			return err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		client.CloseIdleConnections()
+		l.Printf("%s\n", body)
+	}
+	l.Resume()
+
+	l.Section("inspecting")
+
+	l.End()
+	return nil
+}
@@ -0,0 +1,123 @@
+package web
+
+import (
+	"crypto/x509"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Run with: go test -run 'SelfSigned|Negotiated|Multiplex|Push'
+
+func TestSelfSignedCert(t *testing.T) {
+	cert, pool, err := selfSignedCert(time.Hour, "localhost", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		if _, err := cert.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: pool}); err != nil {
+			t.Errorf("not valid for %s: %v", host, err)
+		}
+	}
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: pool}); err == nil {
+		t.Error("valid for a host it wasn't made for")
+	}
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: x509.NewCertPool()}); err == nil {
+		t.Error("trusted without its pool")
+	}
+}
+
+func TestNegotiatedProtocol(t *testing.T) {
+	tests := []struct {
+		name           string
+		server, client *http.Protocols
+		want           string
+	}{
+		{"both speak HTTP/2", http2Protocols(), http2Protocols(), `client got HTTP/2.0, server saw HTTP/2.0 (ALPN "h2")`},
+		{"client speaks HTTP/1.1 only", http2Protocols(), http1Protocols(), "client got HTTP/1.1, server saw HTTP/1.1"},
+		{"server speaks HTTP/1.1 only", http1Protocols(), http2Protocols(), `client got HTTP/1.1, server saw HTTP/1.1 (ALPN "http/1.1")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := startTLSServer(http.HandlerFunc(protoHandler), tt.server)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer server.Close()
+			client := newTLSClient(server.Pool, tt.client)
+			defer client.CloseIdleConnections()
+
+			got, err := fetchProto(client, server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUntrustedCertificateRefused(t *testing.T) {
+	server, err := startTLSServer(http.HandlerFunc(protoHandler), http2Protocols())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	// The system roots don't know the certificate
+	client := newTLSClient(nil, http2Protocols())
+	if _, err := fetchProto(client, server.URL); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("err = %v, want a certificate error", err)
+	}
+}
+
+func TestMultiplexing(t *testing.T) {
+	tests := []struct {
+		name      string
+		protocols *http.Protocols
+		conns     int
+	}{
+		{"HTTP/2", http2Protocols(), 1},
+		{"HTTP/1.1", http1Protocols(), 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := concurrentRequests(8, tt.protocols, tt.protocols)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := multiplexResult{Requests: 8, InFlight: 8, Connections: tt.conns}
+			if got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestPushRefused(t *testing.T) {
+	server, err := startTLSServer(http.HandlerFunc(pushHandler), http2Protocols())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	for _, tt := range []struct {
+		protocols *http.Protocols
+		want      string
+	}{
+		{http2Protocols(), "HTTP/2.0: push refused"},
+		{http1Protocols(), "HTTP/1.1: this ResponseWriter can't push"},
+	} {
+		client := newTLSClient(server.Pool, tt.protocols)
+		got, err := fetchProto(client, server.URL)
+		client.CloseIdleConnections()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package demo
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Clock is what the demos use instead of calling time.Sleep and
+// time.After directly, so --fast (and the tests) can run them without
+// waiting.
+var Clock interface {
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+} = RealClock{}
+
+// RealClock is the time package, except that once Done is closed (on
+// Ctrl+C) every wait ends at once, so a demo hurries on to where it stops.
+type RealClock struct {
+	Done <-chan struct{}
+}
+
+func (c RealClock) Sleep(d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-c.Done:
+	}
+}
+
+func (c RealClock) After(d time.Duration) <-chan time.Time {
+	if c.Done == nil {
+		return time.After(d)
+	}
+	ch := make(chan time.Time, 1)
+	go func() {
+		c.Sleep(d)
+		ch <- time.Now()
+	}()
+	return ch
+}
+
+// FakeClock keeps virtual time. Timers fire in deadline order, but without
+// waiting: whenever no new timer has been started for a moment (so the
+// goroutines woken last have had a chance to run), virtual time jumps to
+// the earliest deadline and every timer due then fires.
+//
+// A demo run this way prints the same things in the same order as in real
+// time, in milliseconds.
+type FakeClock struct {
+	quiet time.Duration // how long the timers must be left alone before one fires
+
+	mu      sync.Mutex
+	now     time.Time
+	timers  []fakeTimer
+	changed bool // a timer was started since the last check
+	running bool // the goroutine firing timers is running
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func NewFakeClock() *FakeClock {
+	return &FakeClock{quiet: 2 * time.Millisecond, now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := fakeTimer{at: c.now.Add(max(d, 0)), ch: make(chan time.Time, 1)}
+	// Timers due at the same moment fire in the order they were started
+	i, _ := slices.BinarySearchFunc(c.timers, t.at, func(t fakeTimer, at time.Time) int {
+		if t.at.After(at) {
+			return 1
+		}
+		return -1
+	})
+	c.timers = slices.Insert(c.timers, i, t)
+	c.changed = true
+	if !c.running {
+		c.running = true
+		go c.fire()
+	}
+	return t.ch
+}
+
+// fire advances virtual time until no timers are left.
+func (c *FakeClock) fire() {
+	for {
+		time.Sleep(c.quiet)
+		c.mu.Lock()
+		if c.changed {
+			c.changed = false
+			c.mu.Unlock()
+			continue
+		}
+		if len(c.timers) == 0 {
+			c.running = false
+			c.mu.Unlock()
+			return
+		}
+		c.now = c.timers[0].at
+		for len(c.timers) > 0 && !c.timers[0].at.After(c.now) {
+			c.timers[0].ch <- c.now
+			c.timers = c.timers[1:]
+		}
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,63 @@
+package demo
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFakeClockOrder(t *testing.T) {
+	c := NewFakeClock()
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	for _, s := range []struct {
+		name string
+		d    time.Duration
+	}{{"slow", time.Hour}, {"fast", time.Second}, {"middle", time.Minute}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Sleep(s.d)
+			mu.Lock()
+			order = append(order, s.name)
+			mu.Unlock()
+		}()
+	}
+
+	start := time.Now()
+	wg.Wait()
+	if got := strings.Join(order, " "); got != "fast middle slow" {
+		t.Errorf("woke in order %q", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleeping took %v of real time", elapsed)
+	}
+
+	// A timeout longer than the work loses the race, as it would for real
+	work := make(chan string)
+	go func() {
+		c.Sleep(time.Second)
+		work <- "done"
+	}()
+	select {
+	case <-work:
+	case <-c.After(2 * time.Second):
+		t.Error("timed out before the work finished")
+	}
+}
+
+func TestRealClockStops(t *testing.T) {
+	done := make(chan struct{})
+	c := RealClock{Done: done}
+	close(done) // Ctrl+C
+	start := time.Now()
+	c.Sleep(time.Hour)
+	<-c.After(time.Hour)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waiting after Ctrl+C took %v", elapsed)
+	}
+}
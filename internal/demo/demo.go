@@ -0,0 +1,55 @@
+// Package demo is what a course's code sees of the program running it: the
+// lesson it prints section by section as its demos run, the Printer the
+// demos print with, and the Clock they wait on.
+package demo
+
+import (
+	"context"
+	"io"
+)
+
+// Run is a course's lesson as it runs. The course prints its demo output
+// with l.Printf and friends, and moves through the lesson with l.Section
+// and l.Resume; helpers that print take l.Printer.
+type Run struct {
+	*Printer
+	text Text
+}
+
+// Text is the lesson text a Run prints around the demos' output.
+type Text interface {
+	Section(id string) // finishes the current section and starts section id
+	Resume()           // prints the text up to the section's next output point
+	End()              // prints the rest of the lesson and the takeaways
+	Rest()             // prints every section left, for a lesson with no demos
+}
+
+// NewRun is a run that prints the demos with p and the lesson with text.
+func NewRun(p *Printer, text Text) *Run {
+	return &Run{Printer: p, text: text}
+}
+
+func (r *Run) Section(id string) { r.text.Section(id) }
+func (r *Run) Resume()           { r.text.Resume() }
+func (r *Run) End()              { r.text.End() }
+
+// Start starts the lesson of course number, printing to w. Once ctx is
+// done, the course stops at its next section. The program sets it to
+// print the lesson text; until then, as in a course's own tests, a run
+// prints the demos' output alone.
+var Start = func(ctx context.Context, w io.Writer, number int) *Run {
+	return NewRun(NewPrinter(w), noText{})
+}
+
+// Print prints the whole lesson of course number, for a course that has
+// no demos to run.
+func Print(ctx context.Context, w io.Writer, number int) {
+	Start(ctx, w, number).text.Rest()
+}
+
+type noText struct{}
+
+func (noText) Section(string) {}
+func (noText) Resume()        {}
+func (noText) End()           {}
+func (noText) Rest()          {}
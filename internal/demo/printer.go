@@ -0,0 +1,56 @@
+package demo
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Printer is where a course prints its demo output: fmt's Print, Printf
+// and Println, but writing to the course's writer instead of os.Stdout, so
+// a test can hand a course a buffer and read what it printed. The demos
+// print from goroutines of their own, so it is safe for concurrent use.
+type Printer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewPrinter(w io.Writer) *Printer {
+	return &Printer{w: w}
+}
+
+func (p *Printer) Print(a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprint(p.w, a...)
+}
+
+func (p *Printer) Printf(format string, a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, format, a...)
+}
+
+func (p *Printer) Println(a ...any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.w, a...)
+}
+
+// Write makes a Printer an io.Writer too, for the demos that need one,
+// such as a logger's output.
+func (p *Printer) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.w.Write(b)
+}
+
+// Redirect sends everything printed from now on to w, and returns where it
+// went before.
+func (p *Printer) Redirect(w io.Writer) io.Writer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old := p.w
+	p.w = w
+	return old
+}
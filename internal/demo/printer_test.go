@@ -0,0 +1,33 @@
+package demo
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	var buf strings.Builder
+	p := NewPrinter(&buf)
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Printf("line %d\n", i)
+		}()
+	}
+	wg.Wait()
+	if got := strings.Count(buf.String(), "\n"); got != 4 {
+		t.Errorf("%d lines from 4 goroutines:\n%s", got, buf.String())
+	}
+
+	old := p.Redirect(io.Discard)
+	p.Println("hidden")
+	p.Redirect(old)
+	p.Print("shown")
+	if out := buf.String(); strings.Contains(out, "hidden") || !strings.HasSuffix(out, "shown") {
+		t.Errorf("redirected output:\n%s", out)
+	}
+}
@@ -0,0 +1,182 @@
+// Package fixtures loads test data into a SQL database from files, and
+// takes it out again when the test ends.
+//
+// A fixture file holds the rows of one table, which it is named after:
+// testdata/fixtures/users.yaml fills users. Each row maps columns to
+// values, in YAML (the subset internal/yaml reads) or JSON:
+//
+//   - id: 1
+//     name: Alice
+//     email: alice@example.com
+//     age: 30
+//
+// The rows are in the files rather than in each test's setup code, so
+// tests share one readable picture of the data, and a test that needs
+// Alice can refer to her by the ID the file gives her.
+//
+// Load inserts the files in the order given, so list a table before the
+// tables whose foreign keys point at it. When the test ends it empties
+// the tables in the reverse order. That matters when tests share a
+// database, as they do against a real server: each test starts from the
+// fixtures and leaves nothing behind for the next.
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/yaml"
+)
+
+// Table is the rows of one table, as a fixture file gives them.
+type Table struct {
+	Name string
+	Rows []map[string]any
+}
+
+// identifier is what a table or column name may be: fixtures name them
+// in SQL, where they can't be placeholders.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Read reads a fixture file: a .yaml, .yml or .json file holding a list
+// of rows.
+func Read(path string) (Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Table{}, err
+	}
+	ext := filepath.Ext(path)
+	t := Table{Name: strings.TrimSuffix(filepath.Base(path), ext)}
+	if !identifier.MatchString(t.Name) {
+		return Table{}, fmt.Errorf("%s: %q is not a table name", path, t.Name)
+	}
+
+	var doc any
+	switch strings.ToLower(ext) {
+	case ".json":
+		// As numbers, so that 30 stays an integer rather than 30.0
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&doc)
+	case ".yaml", ".yml":
+		doc, err = yaml.Parse(data)
+	default:
+		return Table{}, fmt.Errorf("%s: a fixture file must be .json, .yaml or .yml", path)
+	}
+	if err != nil {
+		return Table{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	rows, ok := doc.([]any)
+	if !ok && doc != nil {
+		return Table{}, fmt.Errorf("%s: want a list of rows", path)
+	}
+	for i, r := range rows {
+		row, ok := r.(map[string]any)
+		if !ok {
+			return Table{}, fmt.Errorf("%s: row %d is not a mapping of columns to values", path, i+1)
+		}
+		for column, v := range row {
+			if !identifier.MatchString(column) {
+				return Table{}, fmt.Errorf("%s: row %d: %q is not a column name", path, i+1, column)
+			}
+			if n, ok := v.(json.Number); ok {
+				row[column] = jsonNumber(n)
+				continue
+			}
+			if _, nested := v.(map[string]any); nested {
+				return Table{}, fmt.Errorf("%s: row %d: %s is not a single value", path, i+1, column)
+			}
+			if _, nested := v.([]any); nested {
+				return Table{}, fmt.Errorf("%s: row %d: %s is not a single value", path, i+1, column)
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, nil
+}
+
+// jsonNumber is n as an int if it is whole, and a float64 otherwise, as
+// YAML's numbers are.
+func jsonNumber(n json.Number) any {
+	if i, err := strconv.Atoi(n.String()); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+// Insert adds the tables' rows, in order, in one transaction: all of
+// them, or none if one fails. The statements use ? placeholders, as SQLite
+// and MySQL do.
+func Insert(ctx context.Context, db *sql.DB, tables ...Table) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, t := range tables {
+		for i, row := range t.Rows {
+			// Sorted, so the same row always makes the same statement
+			columns := slices.Sorted(maps.Keys(row))
+			args := make([]any, len(columns))
+			for j, c := range columns {
+				args[j] = row[c]
+			}
+			query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", t.Name,
+				strings.Join(columns, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+			if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("fixture %s, row %d: %w", t.Name, i+1, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// Truncate deletes every row of the tables, last first. It uses DELETE,
+// which every database has; TRUNCATE is faster on a server that has it.
+func Truncate(ctx context.Context, db *sql.DB, tables ...Table) error {
+	for _, t := range slices.Backward(tables) {
+		if _, err := db.ExecContext(ctx, "DELETE FROM "+t.Name); err != nil {
+			return fmt.Errorf("truncate %s: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// Load reads the fixture files and inserts them into db, and empties their
+// tables again when the test and its subtests are over. The tables must
+// exist already. It fails the test if a file can't be read or inserted.
+func Load(t testing.TB, db *sql.DB, paths ...string) []Table {
+	t.Helper()
+	var tables []Table
+	for _, path := range paths {
+		table, err := Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, table)
+	}
+
+	if err := Insert(context.Background(), db, tables...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := Truncate(context.Background(), db, tables...); err != nil {
+			t.Error(err)
+		}
+	})
+	return tables
+}
@@ -0,0 +1,121 @@
+//go:build cgo
+
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // registers the "sqlite3" driver; needs cgo
+)
+
+// write writes a fixture file into a temporary directory and returns its
+// path.
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1) // every connection to :memory: is a database of its own
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT UNIQUE, score REAL)`); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func count(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// The same rows read the same from YAML and JSON.
+func TestRead(t *testing.T) {
+	want := Table{Name: "users", Rows: []map[string]any{
+		{"id": 1, "name": "Alice", "email": "alice@example.com", "score": 9.5},
+		{"id": 2, "name": "Bob", "email": nil, "score": 7},
+	}}
+	files := []string{
+		write(t, "users.yaml", "- id: 1\n  name: Alice\n  email: alice@example.com\n  score: 9.5\n- id: 2\n  name: Bob\n  email: ~\n  score: 7\n"),
+		write(t, "users.json", `[{"id": 1, "name": "Alice", "email": "alice@example.com", "score": 9.5}, {"id": 2, "name": "Bob", "email": null, "score": 7}]`),
+	}
+	for _, path := range files {
+		got, err := Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Read(%s) = %#v, want %#v", filepath.Base(path), got, want)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name, content, err string
+	}{
+		{"users.csv", "id,name\n", "must be .json, .yaml or .yml"},
+		{"my-users.yaml", "- id: 1\n", "not a table name"},
+		{"users.yaml", "id: 1\n", "want a list of rows"},
+		{"users.yaml", "- 1\n", "row 1 is not a mapping"},
+		{"users.yaml", "- id: 1\n  name; DROP TABLE users: x\n", `"name; DROP TABLE users" is not a column name`},
+		{"users.yaml", "- id: 1\n  tags:\n    - a\n", "tags is not a single value"},
+		{"users.json", `[{"id": 1,}]`, "invalid character"},
+	}
+	for _, tt := range tests {
+		_, err := Read(write(t, tt.name, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Read(%s %q) = %v, want %q", tt.name, tt.content, err, tt.err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	db := newDB(t)
+	path := write(t, "users.yaml", "- id: 7\n  name: Alice\n- id: 8\n  name: Bob\n")
+
+	t.Run("loaded", func(t *testing.T) {
+		Load(t, db, path)
+		var name string
+		if err := db.QueryRow(`SELECT name FROM users WHERE id = 8`).Scan(&name); err != nil || name != "Bob" {
+			t.Errorf("user 8 = %q, %v", name, err)
+		}
+	})
+	if n := count(t, db); n != 0 {
+		t.Errorf("%d users after the subtest, want none", n)
+	}
+}
+
+// A row that fails leaves none of the others in the table.
+func TestInsertIsAllOrNothing(t *testing.T) {
+	db := newDB(t)
+	users, err := Read(write(t, "users.yaml", "- name: Alice\n  email: a@example.com\n- name: Bob\n  email: a@example.com\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Insert(context.Background(), db, users); err == nil || !strings.Contains(err.Error(), "fixture users, row 2") {
+		t.Errorf("Insert = %v, want row 2 to fail", err)
+	}
+	if n := count(t, db); n != 0 {
+		t.Errorf("%d users after a failed Insert, want none", n)
+	}
+}
@@ -0,0 +1,90 @@
+// Package geometry has the shapes course 3 uses to show methods and
+// interfaces: Rectangle, Circle and Triangle, which all satisfy Shape.
+package geometry
+
+import (
+	"errors"
+	"math"
+)
+
+// Shape is anything with an area and a perimeter.
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+// ============ RECTANGLE ============
+type Rectangle struct {
+	Width  float64
+	Height float64
+}
+
+// Method with value receiver (works on a copy, cannot modify)
+func (r Rectangle) Area() float64 {
+	return r.Width * r.Height
+}
+
+func (r Rectangle) Perimeter() float64 {
+	return 2 * (r.Width + r.Height)
+}
+
+// Method with pointer receiver (can modify the original)
+func (r *Rectangle) Scale(factor float64) {
+	r.Width *= factor
+	r.Height *= factor
+}
+
+// ============ CIRCLE ============
+type Circle struct {
+	Radius float64
+}
+
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+// ============ TRIANGLE ============
+// ErrNotATriangle is returned by NewTriangle for sides that cannot close.
+var ErrNotATriangle = errors.New("sides break the triangle inequality")
+
+// Triangle is given by the lengths of its three sides.
+type Triangle struct {
+	SideA, SideB, SideC float64
+}
+
+// NewTriangle returns the triangle with the given sides, or
+// ErrNotATriangle if they cannot form one.
+func NewTriangle(a, b, c float64) (Triangle, error) {
+	t := Triangle{SideA: a, SideB: b, SideC: c}
+	if !t.Valid() {
+		return Triangle{}, ErrNotATriangle
+	}
+	return t, nil
+}
+
+// Valid reports whether the sides form a triangle: all positive, and none
+// longer than the other two together (the triangle inequality). Sides
+// where one equals the sum of the others give a flat triangle of area 0.
+func (t Triangle) Valid() bool {
+	a, b, c := t.SideA, t.SideB, t.SideC
+	return a > 0 && b > 0 && c > 0 && a <= b+c && b <= a+c && c <= a+b
+}
+
+// Area uses Heron's formula: sqrt(s(s-a)(s-b)(s-c)), where s is half the
+// perimeter. It returns NaN for sides that are not Valid.
+func (t Triangle) Area() float64 {
+	if !t.Valid() {
+		return math.NaN()
+	}
+	s := t.Perimeter() / 2
+	// Rounding can push a flat triangle's product just below zero
+	return math.Sqrt(max(0, s*(s-t.SideA)*(s-t.SideB)*(s-t.SideC)))
+}
+
+func (t Triangle) Perimeter() float64 {
+	return t.SideA + t.SideB + t.SideC
+}
@@ -0,0 +1,112 @@
+package geometry
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// Floats pick up rounding errors, so compare within a tolerance, never with ==.
+const tolerance = 1e-9
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) <= tolerance*max(1, math.Abs(a), math.Abs(b))
+}
+
+func TestShapes(t *testing.T) {
+	tests := []struct {
+		name      string
+		shape     Shape
+		area      float64
+		perimeter float64
+	}{
+		{"rectangle", Rectangle{Width: 4, Height: 5}, 20, 18},
+		{"square", Rectangle{Width: 1.5, Height: 1.5}, 2.25, 6},
+		{"empty rectangle", Rectangle{}, 0, 0},
+		{"unit circle", Circle{Radius: 1}, math.Pi, 2 * math.Pi},
+		{"circle", Circle{Radius: 3}, 9 * math.Pi, 6 * math.Pi},
+		{"3-4-5 triangle", Triangle{SideA: 3, SideB: 4, SideC: 5}, 6, 12},
+		{"equilateral triangle", Triangle{SideA: 2, SideB: 2, SideC: 2}, math.Sqrt(3), 6},
+		{"flat triangle", Triangle{SideA: 1, SideB: 2, SideC: 3}, 0, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.shape.Area(); !approxEqual(got, tt.area) {
+				t.Errorf("Area() = %v, want %v", got, tt.area)
+			}
+			if got := tt.shape.Perimeter(); !approxEqual(got, tt.perimeter) {
+				t.Errorf("Perimeter() = %v, want %v", got, tt.perimeter)
+			}
+		})
+	}
+}
+
+func TestRectangleScale(t *testing.T) {
+	r := Rectangle{Width: 2, Height: 3}
+	r.Scale(2)
+	if r != (Rectangle{Width: 4, Height: 6}) {
+		t.Errorf("after Scale(2): %+v", r)
+	}
+}
+
+func TestNewTriangle(t *testing.T) {
+	tests := []struct {
+		a, b, c float64
+		err     error
+	}{
+		{3, 4, 5, nil},
+		{1, 2, 3, nil}, // flat, but still a triangle
+		{1, 2, 4, ErrNotATriangle},
+		{4, 1, 2, ErrNotATriangle},
+		{0, 1, 1, ErrNotATriangle},
+		{-3, 4, 5, ErrNotATriangle},
+	}
+	for _, tt := range tests {
+		tri, err := NewTriangle(tt.a, tt.b, tt.c)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("NewTriangle(%v, %v, %v) error = %v, want %v", tt.a, tt.b, tt.c, err, tt.err)
+		}
+		if err != nil && !math.IsNaN(Triangle{tt.a, tt.b, tt.c}.Area()) {
+			t.Errorf("Area of invalid sides %v, %v, %v should be NaN", tt.a, tt.b, tt.c)
+		}
+		if err == nil && tri.Perimeter() != tt.a+tt.b+tt.c {
+			t.Errorf("NewTriangle(%v, %v, %v) = %+v", tt.a, tt.b, tt.c, tri)
+		}
+	}
+}
+
+// TestTriangleProperties checks random triangles against facts that hold
+// for every triangle, rather than against hand-worked answers.
+func TestTriangleProperties(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2)) // fixed seed: failures can be reproduced
+	for range 1000 {
+		a, b := 0.1+rng.Float64()*100, 0.1+rng.Float64()*100
+		// The triangle inequality: the third side lies strictly between |a-b| and a+b
+		lo, hi := math.Abs(a-b), a+b
+		c := lo + (hi-lo)*(0.01+0.98*rng.Float64())
+		tri := Triangle{SideA: a, SideB: b, SideC: c}
+
+		if !tri.Valid() {
+			t.Fatalf("%+v should be valid", tri)
+		}
+		area := tri.Area()
+		// Area = ab*sin(C)/2 and sin(C) <= 1
+		if area <= 0 || area > a*b/2*(1+tolerance) {
+			t.Fatalf("%+v: area %v not in (0, %v]", tri, area, a*b/2)
+		}
+		// The order of the sides doesn't matter
+		if other := (Triangle{SideA: c, SideB: a, SideC: b}).Area(); !approxEqual(area, other) {
+			t.Fatalf("%+v: area %v, rotated %v", tri, area, other)
+		}
+		// Scaling every side by k scales the area by k²
+		if scaled := (Triangle{SideA: 2 * a, SideB: 2 * b, SideC: 2 * c}).Area(); !approxEqual(scaled, 4*area) {
+			t.Fatalf("%+v: doubled area %v, want %v", tri, scaled, 4*area)
+		}
+
+		// A third side longer than the other two together can't close
+		if (Triangle{SideA: a, SideB: b, SideC: hi * 1.01}).Valid() {
+			t.Fatalf("sides %v, %v, %v should break the triangle inequality", a, b, hi*1.01)
+		}
+	}
+}
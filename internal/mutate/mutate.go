@@ -0,0 +1,244 @@
+// Package mutate is a small mutation tester. It makes one change at a
+// time to a function - a comparison turned around, a constant off by one -
+// and runs the package's tests against each changed copy, a mutant. A
+// test that fails has caught the change: the mutant is killed. If every
+// test still passes, the mutant survived, and the tests can't tell the
+// function from a broken one. Course 32 shows it at work.
+//
+//	mutants, err := mutate.Generate("discount.go", src, "BulkDiscount")
+//	...
+//	results, err := mutate.Run(ctx, "discount.go", mutants)
+//	for _, r := range results {
+//		fmt.Println(r.Mutant, r.Killed())
+//	}
+//
+// The mutants are never written over the source: go test builds them from
+// an overlay (go help build, -overlay), a file that maps the source file
+// to a mutated copy in a temporary directory.
+package mutate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A Mutant is the file with one change in the function.
+type Mutant struct {
+	Line     int
+	From, To string // the operator or constant, before and after
+	src      []byte
+}
+
+// Source is the whole file, changed.
+func (m Mutant) Source() []byte {
+	return m.src
+}
+
+func (m Mutant) String() string {
+	return fmt.Sprintf("line %d: %s → %s", m.Line, m.From, m.To)
+}
+
+// flips are the comparisons each comparison is turned into: the one that
+// moves the boundary (< and <=), and the opposite.
+var flips = map[token.Token][]token.Token{
+	token.LSS: {token.LEQ, token.GEQ},
+	token.LEQ: {token.LSS, token.GTR},
+	token.GTR: {token.GEQ, token.LEQ},
+	token.GEQ: {token.GTR, token.LSS},
+	token.EQL: {token.NEQ},
+	token.NEQ: {token.EQL},
+}
+
+// Generate returns the mutants of the function funcName in the Go file
+// src: each comparison flipped, and each integer constant compared with
+// made one more and one less. filename is only for positions.
+func Generate(filename string, src []byte, funcName string) ([]Mutant, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var fn *ast.FuncDecl
+	for _, d := range file.Decls {
+		if f, ok := d.(*ast.FuncDecl); ok && f.Name.Name == funcName && f.Body != nil {
+			fn = f
+		}
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("%s: no function %s", filename, funcName)
+	}
+
+	var mutants []Mutant
+	// mutant prints the file as it is now, with one change made
+	mutant := func(pos token.Pos, from, to string) error {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, file); err != nil {
+			return err
+		}
+		mutants = append(mutants, Mutant{fset.Position(pos).Line, from, to, buf.Bytes()})
+		return nil
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		cmp, ok := n.(*ast.BinaryExpr)
+		if !ok || flips[cmp.Op] == nil || err != nil {
+			return err == nil
+		}
+		op := cmp.Op
+		for _, to := range flips[op] {
+			cmp.Op = to
+			err = errors.Join(err, mutant(cmp.OpPos, op.String(), to.String()))
+		}
+		cmp.Op = op
+
+		for _, operand := range []ast.Expr{cmp.X, cmp.Y} {
+			lit, ok := operand.(*ast.BasicLit)
+			if !ok || lit.Kind != token.INT {
+				continue
+			}
+			n, convErr := strconv.ParseInt(lit.Value, 0, 64)
+			if convErr != nil {
+				continue
+			}
+			value := lit.Value
+			for _, to := range []int64{n - 1, n + 1} {
+				lit.Value = strconv.FormatInt(to, 10)
+				err = errors.Join(err, mutant(lit.Pos(), value, lit.Value))
+			}
+			lit.Value = value
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mutants, nil
+}
+
+// Result is what the tests made of a mutant.
+type Result struct {
+	Mutant
+	// Failed lists the top-level tests that failed on the mutant: the
+	// ones that caught it.
+	Failed []string
+}
+
+// Killed reports whether any test caught the mutant.
+func (r Result) Killed() bool {
+	return len(r.Failed) > 0
+}
+
+// Run runs go test on the package of file once per mutant, with the
+// mutant in place of file, several at a time. testArgs go to go test
+// before the package, such as "-run", "^TestBulk". It runs the tests on
+// the unchanged code first: if they fail already, no mutant can tell.
+func Run(ctx context.Context, file string, mutants []Mutant, testArgs ...string) ([]Result, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "mutate-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	failed, ran, err := goTest(ctx, file, "", testArgs)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("the unchanged code: %w", err)
+	case len(failed) > 0:
+		return nil, fmt.Errorf("the tests fail without any mutation: %s", strings.Join(failed, ", "))
+	case ran == 0:
+		// Every mutant would survive
+		return nil, fmt.Errorf("no tests ran in %s", filepath.Dir(file))
+	}
+
+	results := make([]Result, len(mutants))
+	errs := make([]error, len(mutants))
+	sem := make(chan struct{}, runtime.NumCPU())
+	var wg sync.WaitGroup
+	for i, m := range mutants {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			mutated := filepath.Join(tmp, fmt.Sprintf("mutant%d.go", i))
+			if errs[i] = os.WriteFile(mutated, m.src, 0o644); errs[i] != nil {
+				return
+			}
+			overlay := filepath.Join(tmp, fmt.Sprintf("overlay%d.json", i))
+			data, _ := json.Marshal(map[string]any{"Replace": map[string]string{file: mutated}})
+			if errs[i] = os.WriteFile(overlay, data, 0o644); errs[i] != nil {
+				return
+			}
+			results[i].Mutant = m
+			results[i].Failed, _, errs[i] = goTest(ctx, file, overlay, testArgs)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%v: %w", m, errs[i])
+			}
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// goTest runs go test -json on the package of file, with the overlay if
+// there is one, and returns the top-level tests that failed, and how many
+// ran. A package that doesn't build, or a test binary that dies, is an
+// error: no test said anything about the code.
+func goTest(ctx context.Context, file, overlay string, testArgs []string) (failed []string, ran int, err error) {
+	args := []string{"test", "-json", "-count=1", "-timeout=1m"}
+	if overlay != "" {
+		args = append(args, "-overlay="+overlay)
+	}
+	cmd := exec.CommandContext(ctx, "go", append(append(args, testArgs...), ".")...)
+	cmd.Dir = filepath.Dir(file)
+	out, runErr := cmd.CombinedOutput()
+
+	var output strings.Builder
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		var e struct{ Action, Test, Output string }
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			output.Write(sc.Bytes()) // build errors, as plain text
+			output.WriteByte('\n')
+			continue
+		}
+		output.WriteString(e.Output)
+		if e.Test == "" || strings.Contains(e.Test, "/") {
+			continue
+		}
+		switch e.Action {
+		case "fail":
+			failed = append(failed, e.Test)
+			ran++
+		case "pass":
+			ran++
+		}
+	}
+	if runErr != nil && len(failed) == 0 && strings.Contains(output.String(), "panic: test timed out") {
+		// A mutant that loops forever is caught too, by the timeout
+		return []string{"(timeout)"}, ran, nil
+	}
+	if runErr != nil && len(failed) == 0 {
+		return nil, ran, fmt.Errorf("go test: %v\n%s", runErr, output.String())
+	}
+	return failed, ran, nil
+}
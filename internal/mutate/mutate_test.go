@@ -0,0 +1,162 @@
+package mutate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const clampSrc = `package clamp
+
+// Clamp limits n to [0, 10].
+func Clamp(n int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > 0xA {
+		return 10
+	}
+	return n
+}
+
+func other(a, b int) bool { return a == b }
+`
+
+func TestGenerate(t *testing.T) {
+	mutants, err := Generate("clamp.go", []byte(clampSrc), "Clamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range mutants {
+		got = append(got, m.String())
+	}
+	want := []string{
+		"line 5: < → <=", "line 5: < → >=", "line 5: 0 → -1", "line 5: 0 → 1",
+		"line 8: > → >=", "line 8: > → <=", "line 8: 0xA → 9", "line 8: 0xA → 11",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("mutants:\n%q\nwant:\n%q", got, want)
+	}
+
+	// Each mutant is the whole file with its one change, and nothing else
+	for _, m := range mutants {
+		lines := strings.Split(string(m.Source()), "\n")
+		orig := strings.Split(clampSrc, "\n")
+		var changed []int
+		for i := range min(len(lines), len(orig)) {
+			if lines[i] != orig[i] {
+				changed = append(changed, i+1)
+			}
+		}
+		if len(lines) != len(orig) || !slices.Equal(changed, []int{m.Line}) {
+			t.Errorf("%v changed lines %v of the file", m, changed)
+		}
+		if !strings.Contains(lines[m.Line-1], m.To) {
+			t.Errorf("%v: line reads %q", m, lines[m.Line-1])
+		}
+	}
+
+	if _, err := Generate("clamp.go", []byte(clampSrc), "Missing"); err == nil {
+		t.Error("mutated a function that isn't there")
+	}
+	if _, err := Generate("clamp.go", []byte("package clamp\nfunc ("), "Clamp"); err == nil {
+		t.Error("mutated a file that doesn't parse")
+	}
+}
+
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module clamp\n\ngo 1.25\n"
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "clamp.go")
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not on PATH")
+	}
+	// Tests of one value in each branch, none at a boundary
+	file := writeModule(t, map[string]string{
+		"clamp.go": clampSrc,
+		"clamp_test.go": `package clamp
+
+import "testing"
+
+func TestClamp(t *testing.T) {
+	for n, want := range map[int]int{-5: 0, 5: 5, 50: 10} {
+		if got := Clamp(n); got != want {
+			t.Errorf("Clamp(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestOther(t *testing.T) {}
+`,
+	})
+	mutants, err := Generate(file, []byte(clampSrc), "Clamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := Run(context.Background(), file, mutants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var survivors []string
+	for _, r := range results {
+		if !r.Killed() {
+			survivors = append(survivors, r.Mutant.String())
+		} else if !slices.Equal(r.Failed, []string{"TestClamp"}) {
+			t.Errorf("%v killed by %q, want TestClamp", r.Mutant, r.Failed)
+		}
+	}
+	// The boundaries are 0 and 10, and moving them changes nothing the
+	// test looks at; turning a comparison around does
+	want := []string{"line 5: < → <=", "line 5: 0 → -1", "line 5: 0 → 1", "line 8: > → >=", "line 8: 0xA → 9", "line 8: 0xA → 11"}
+	if !slices.Equal(survivors, want) {
+		t.Errorf("survivors:\n%q\nwant:\n%q", survivors, want)
+	}
+
+	// The source was never touched
+	if src, err := os.ReadFile(file); err != nil || !bytes.Equal(src, []byte(clampSrc)) {
+		t.Errorf("clamp.go changed: %v", err)
+	}
+}
+
+func TestRunRefuses(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not on PATH")
+	}
+	mutants, _ := Generate("clamp.go", []byte(clampSrc), "Clamp")
+	tests := []struct {
+		name string
+		test string
+		want string
+	}{
+		{"no tests", "", "no tests ran"},
+		{"failing tests", "package clamp\n\nimport \"testing\"\n\nfunc TestBroken(t *testing.T) { t.Fail() }\n", "fail without any mutation: TestBroken"},
+		{"no build", "package clamp\n\nfunc TestBroken(t *testing.T) {}\n", "the unchanged code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"clamp.go": clampSrc}
+			if tt.test != "" {
+				files["clamp_test.go"] = tt.test
+			}
+			_, err := Run(context.Background(), writeModule(t, files), mutants)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
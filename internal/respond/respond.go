@@ -0,0 +1,247 @@
+// Package respond writes the responses of the course 6 API: every body is
+// an Envelope, sent as JSON or as plain text depending on what the client
+// accepts, and every error gets its status code and error code from the
+// kind of error it is.
+//
+// Services report the kind by wrapping one of the sentinel errors:
+//
+//	var ErrUserNotFound = fmt.Errorf("user %w", respond.ErrNotFound)
+//
+// and handlers hand whatever they get to Error:
+//
+//	if err != nil {
+//		respond.Error(w, r, err) // 404 {"success":false,"error":"user not found","code":"not_found"}
+//		return
+//	}
+package respond
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ============ THE ENVELOPE ============
+
+// Envelope is the body of every response. Code is set on errors only: it
+// is stable, so clients can switch on it, while Error is meant for people
+// and may change.
+type Envelope struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"`
+}
+
+// Error codes, one per kind of error.
+const (
+	CodeInvalid      = "invalid_request"
+	CodeValidation   = "validation_failed"
+	CodeUnauthorized = "unauthorized"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeInternal     = "internal"
+)
+
+// ============ KINDS OF ERRORS ============
+
+// The kinds of error a service can report. Wrap them, with %w, to say what
+// went wrong; Error finds them with errors.Is.
+var (
+	ErrInvalid      = errors.New("invalid request")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// kinds maps each kind to its status and code. ErrValidation comes before
+// ErrInvalid so an error wrapping both is reported as the more precise one.
+var kinds = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{ErrValidation, http.StatusBadRequest, CodeValidation},
+	{ErrInvalid, http.StatusBadRequest, CodeInvalid},
+	{ErrUnauthorized, http.StatusUnauthorized, CodeUnauthorized},
+	{ErrNotFound, http.StatusNotFound, CodeNotFound},
+	{ErrConflict, http.StatusConflict, CodeConflict},
+}
+
+// Status returns the status and error code for err. An error of no known
+// kind is a bug on our side: 500.
+func Status(err error) (status int, code string) {
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			return k.status, k.code
+		}
+	}
+	return http.StatusInternalServerError, CodeInternal
+}
+
+// dataError is an error that has something to show the client besides its
+// message: the invalid fields, or the current version of what conflicted.
+type dataError interface {
+	error
+	ErrorData() interface{}
+}
+
+// WithData attaches data to err, to be sent as the envelope's Data.
+func WithData(err error, data interface{}) error {
+	return &withData{err, data}
+}
+
+type withData struct {
+	err  error
+	data interface{}
+}
+
+func (e *withData) Error() string          { return e.err.Error() }
+func (e *withData) Unwrap() error          { return e.err }
+func (e *withData) ErrorData() interface{} { return e.data }
+
+// ============ WRITING RESPONSES ============
+
+// OK sends a successful response.
+func OK(w http.ResponseWriter, r *http.Request, status int, message string, data interface{}) {
+	Write(w, r, status, Envelope{Success: true, Message: message, Data: data})
+}
+
+// Error sends err with the status and code of its kind. The message of an
+// internal error stays in the server: it may say more than a client should
+// know.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := Status(err)
+	env := Envelope{Success: false, Message: http.StatusText(status), Error: err.Error(), Code: code}
+	if status == http.StatusInternalServerError {
+		env.Error = "internal server error"
+	}
+	var de dataError
+	if errors.As(err, &de) {
+		env.Data = de.ErrorData()
+	}
+	Write(w, r, status, env)
+}
+
+// Write sends env in the format the client prefers, or 406 Not Acceptable
+// if it accepts none of them.
+func Write(w http.ResponseWriter, r *http.Request, status int, env Envelope) {
+	// The body depends on Accept, so caches must not share it
+	w.Header().Add("Vary", "Accept")
+	contentType, ok := Negotiate(r)
+	if !ok {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusNotAcceptable)
+		fmt.Fprintf(w, "not acceptable: can send %s\n", strings.Join(offers, " or "))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if contentType == "text/plain; charset=utf-8" {
+		writeText(w, env)
+		return
+	}
+	json.NewEncoder(w).Encode(env)
+}
+
+// writeText renders env for people: a summary line, then the data, one
+// line per element if it is a list.
+//
+//	error not_found: user not found
+//	ok: Users retrieved
+//	{ID:1 Name:Alice Email:alice@example.com Age:30 Version:1}
+func writeText(w http.ResponseWriter, env Envelope) {
+	if env.Success {
+		fmt.Fprintf(w, "ok: %s\n", env.Message)
+	} else {
+		fmt.Fprintf(w, "error %s: %s\n", env.Code, env.Error)
+	}
+	if env.Data == nil {
+		return
+	}
+	v := reflect.ValueOf(env.Data)
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			fmt.Fprintf(w, "%+v\n", v.Index(i).Interface())
+		}
+		return
+	}
+	fmt.Fprintf(w, "%+v\n", env.Data)
+}
+
+// ============ CONTENT NEGOTIATION ============
+
+// offers are the content types Write can send, the preferred one first.
+var offers = []string{"application/json", "text/plain"}
+
+// Negotiate picks the content type for the response from the request's
+// Accept header, e.g. "text/plain, application/json;q=0.5". The client's
+// highest q wins; on a tie, or with no Accept header at all, JSON does.
+// ok is false if the client accepts neither.
+func Negotiate(r *http.Request) (contentType string, ok bool) {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return contentTypeOf(offers[0]), true
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := quality(accept, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return contentTypeOf(best), true
+}
+
+func contentTypeOf(offer string) string {
+	if offer == "text/plain" {
+		return "text/plain; charset=utf-8"
+	}
+	return offer
+}
+
+// quality returns the q the Accept header gives offer: from the most
+// specific range that matches it (text/plain before text/* before */*),
+// 1 if that range has no q, and 0 if no range matches.
+func quality(accept, offer string) float64 {
+	offerType, _, _ := strings.Cut(offer, "/")
+	q, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		var s int
+		switch {
+		case mediaType == offer:
+			s = 2
+		case mediaType == offerType+"/*":
+			s = 1
+		case mediaType == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity, q = s, 1
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 && f <= 1 {
+				q = f
+			}
+		}
+	}
+	return q
+}
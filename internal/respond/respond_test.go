@@ -0,0 +1,174 @@
+package respond
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string // "" for 406
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/json", "application/json"},
+		{"text/plain", "text/plain; charset=utf-8"},
+		{"text/*", "text/plain; charset=utf-8"},
+		{"text/plain, application/json", "application/json"}, // a tie: JSON
+		{"application/json;q=0.5, text/plain", "text/plain; charset=utf-8"},
+		{"text/plain;q=0.9, */*;q=0.1", "text/plain; charset=utf-8"},
+		// The most specific range decides: text/plain is refused here
+		{"text/plain;q=0, */*", "application/json"},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", "application/json"}, // a browser
+		{"text/html", ""},
+		{"application/json;q=0", ""},
+		{"nonsense;;;, text/plain", "text/plain; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			got, ok := Negotiate(r)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("Negotiate(%q) = %q, %v; want %q", tt.accept, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"invalid", fmt.Errorf("%w: bad id", ErrInvalid), 400, CodeInvalid},
+		{"validation", fmt.Errorf("create: %w", ErrValidation), 400, CodeValidation},
+		{"validation wins over invalid", fmt.Errorf("%w: %w", ErrInvalid, ErrValidation), 400, CodeValidation},
+		{"unauthorized", ErrUnauthorized, 401, CodeUnauthorized},
+		{"not found", fmt.Errorf("get user 9: %w", fmt.Errorf("user %w", ErrNotFound)), 404, CodeNotFound},
+		{"conflict", WithData(fmt.Errorf("%w: stale", ErrConflict), 1), 409, CodeConflict},
+		{"unknown", errors.New("disk full"), 500, CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := Status(tt.err)
+			if status != tt.status || code != tt.code {
+				t.Errorf("Status = %d, %q; want %d, %q", status, code, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+// decode reads the JSON envelope of a response.
+func decode(t *testing.T, w *httptest.ResponseRecorder) Envelope {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	var env Envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestError(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := WithData(fmt.Errorf("update user 2: %w", ErrConflict), map[string]int{"version": 3})
+	Error(w, httptest.NewRequest("PUT", "/users/2", nil), err)
+
+	env := decode(t, w)
+	if w.Code != 409 || env.Success || env.Code != CodeConflict || env.Error != "update user 2: conflict" {
+		t.Errorf("got %d %+v", w.Code, env)
+	}
+	if data, _ := env.Data.(map[string]interface{}); data["version"] != 3.0 {
+		t.Errorf("data = %v, want the attached data", env.Data)
+	}
+	if vary := w.Header().Get("Vary"); vary != "Accept" {
+		t.Errorf("Vary = %q", vary)
+	}
+}
+
+func TestErrorHidesInternalErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	Error(w, httptest.NewRequest("GET", "/", nil), errors.New("pq: password authentication failed for user \"admin\""))
+
+	env := decode(t, w)
+	if w.Code != 500 || env.Code != CodeInternal || strings.Contains(env.Error, "admin") {
+		t.Errorf("got %d %+v", w.Code, env)
+	}
+}
+
+func TestOK(t *testing.T) {
+	w := httptest.NewRecorder()
+	OK(w, httptest.NewRequest("POST", "/users", nil), http.StatusCreated, "User created", map[string]int{"id": 4})
+
+	env := decode(t, w)
+	if w.Code != 201 || !env.Success || env.Message != "User created" || env.Code != "" || env.Error != "" {
+		t.Errorf("got %d %+v", w.Code, env)
+	}
+	// Success responses have no error fields at all
+	if strings.Contains(w.Body.String(), `"code"`) || strings.Contains(w.Body.String(), `"error"`) {
+		t.Errorf("body = %s", w.Body)
+	}
+}
+
+type item struct {
+	ID   int
+	Name string
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w http.ResponseWriter, r *http.Request)
+		want  string
+	}{
+		{"list", func(w http.ResponseWriter, r *http.Request) {
+			OK(w, r, 200, "Items", []item{{1, "pen"}, {2, "ink"}})
+		}, "ok: Items\n{ID:1 Name:pen}\n{ID:2 Name:ink}\n"},
+		{"one value", func(w http.ResponseWriter, r *http.Request) {
+			OK(w, r, 200, "Item", item{1, "pen"})
+		}, "ok: Item\n{ID:1 Name:pen}\n"},
+		{"no data", func(w http.ResponseWriter, r *http.Request) {
+			OK(w, r, 200, "Done", nil)
+		}, "ok: Done\n"},
+		{"error", func(w http.ResponseWriter, r *http.Request) {
+			Error(w, r, fmt.Errorf("item %w", ErrNotFound))
+		}, "error not_found: item not found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept", "text/plain")
+			w := httptest.NewRecorder()
+			tt.write(w, r)
+			if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotAcceptable(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+	OK(w, r, 200, "Items", nil)
+	if w.Code != http.StatusNotAcceptable || !strings.Contains(w.Body.String(), "application/json or text/plain") {
+		t.Errorf("got %d %q", w.Code, w.Body)
+	}
+}
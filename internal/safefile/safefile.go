@@ -0,0 +1,106 @@
+// Package safefile saves files so that a crash never leaves them half
+// written, and locks them so that two processes don't overwrite each
+// other's changes. The progress file is saved with it; course 28 shows how
+// it works.
+//
+// A save writes a temporary file next to the real one, flushes it to disk,
+// and renames it over the real one. A rename within a directory is atomic:
+// readers see the old file or the new one, never a mix.
+//
+//	if err := safefile.WriteFile(path, data, 0o644); err != nil {
+//		return err
+//	}
+//
+// A lock is advisory: it keeps out processes that take it too, and no one
+// else. Hold it around the whole read-change-write, not just the write:
+//
+//	lock, err := safefile.Obtain(path + ".lock")
+//	if err != nil {
+//		return err
+//	}
+//	defer lock.Release()
+//	// read path, change it, safefile.WriteFile(path, ...)
+package safefile
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data to name, as os.WriteFile does, but atomically: if
+// it fails, or the machine crashes, name still holds its old contents.
+// The data is flushed to disk (fsync) before the rename, and the
+// directory after it, so a save that returned nil survives a power cut.
+func WriteFile(name string, data []byte, perm fs.FileMode) (err error) {
+	dir := filepath.Dir(name)
+	// In the same directory, so that the rename doesn't cross file systems
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(name)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil { // CreateTemp uses 0600
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// ErrNotObtained is returned by TryObtain when another holder has the lock.
+var ErrNotObtained = errors.New("safefile: lock held elsewhere")
+
+// Lock is a held lock on a file.
+type Lock struct {
+	f *os.File
+}
+
+// Obtain takes an exclusive lock on the file name, creating it if need
+// be, and waits for as long as another holder has it.
+func Obtain(name string) (*Lock, error) {
+	return obtain(name, true)
+}
+
+// TryObtain takes the lock on name if it is free, and returns
+// ErrNotObtained if not. It never waits.
+func TryObtain(name string) (*Lock, error) {
+	return obtain(name, false)
+}
+
+func obtain(name string, wait bool) (*Lock, error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, wait); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Lock{f: f}, nil
+}
+
+// Release gives the lock up. The lock file stays: removing it would let a
+// process that opened it before the removal lock a file no one else sees.
+// The system releases the lock when the process exits, however it exits.
+func (l *Lock) Release() error {
+	err := unlockFile(l.f)
+	return errors.Join(err, l.f.Close())
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package safefile
+
+import "os"
+
+// lockFile is not implemented here: the lock is always obtained, and two
+// processes can still overwrite each other's changes.
+func lockFile(f *os.File, wait bool) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
+
+// syncDir does nothing: not every system here can flush a directory.
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,115 @@
+package safefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "progress.json")
+	for _, data := range []string{"first\n", "second, longer\n", ""} {
+		if err := WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != data {
+			t.Errorf("after writing %q: read %q, %v", data, got, err)
+		}
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o644 {
+		t.Errorf("mode = %v, want 0644, not CreateTemp's 0600", info.Mode().Perm())
+	}
+	// No temporary files left behind
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory holds %d files, want 1", len(entries))
+	}
+}
+
+// A save that fails leaves the old file as it was, and no temporary file.
+func TestWriteFileFails(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "progress.json")
+	if err := WriteFile(name, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// The rename fails: name is now a directory that isn't empty
+	if err := os.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(name, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(name, []byte("new\n"), 0o644); err == nil {
+		t.Fatal("writing over a directory succeeded")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("directory holds %d files, %v; want the one directory", len(entries), err)
+	}
+
+	if err := WriteFile(filepath.Join(dir, "missing", "progress.json"), nil, 0o644); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("writing into a missing directory: err = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+func TestLock(t *testing.T) {
+	switch runtime.GOOS {
+	case "linux", "darwin", "dragonfly", "freebsd", "netbsd", "openbsd", "windows":
+	default:
+		t.Skip("file locks are not implemented here")
+	}
+	name := filepath.Join(t.TempDir(), "progress.json.lock")
+	first, err := Obtain(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The lock belongs to the open file, so a second open excludes it
+	// even in the same process
+	if _, err := TryObtain(name); !errors.Is(err, ErrNotObtained) {
+		t.Fatalf("TryObtain while held: err = %v, want %v", err, ErrNotObtained)
+	}
+
+	obtained := make(chan *Lock)
+	go func() {
+		second, err := Obtain(name)
+		if err != nil {
+			t.Error(err)
+		}
+		obtained <- second
+	}()
+	select {
+	case <-obtained:
+		t.Fatal("Obtain didn't wait for the holder")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := first.Release(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case second := <-obtained:
+		if second != nil {
+			if err := second.Release(); err != nil {
+				t.Error(err)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Obtain still waiting after the release")
+	}
+
+	third, err := TryObtain(name)
+	if err != nil {
+		t.Fatalf("TryObtain when free: %v", err)
+	}
+	third.Release()
+}
@@ -0,0 +1,321 @@
+// Package yaml reads and writes the block-style subset of YAML that the
+// program's own files use: the question banks in quizzes/ and the test
+// fixtures of internal/fixtures. It is small enough to read in one
+// sitting, and keeps the module free of a YAML dependency.
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Quote writes s as a plain YAML scalar when it reads back as the
+// same string, and double-quoted otherwise.
+func Quote(s string) string {
+	plain := s != "" && s == strings.TrimSpace(s) &&
+		!strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") &&
+		!strings.Contains(s, ": ") && !strings.Contains(s, " #") && !strings.HasSuffix(s, ":") &&
+		!strings.ContainsAny(s, "\n\t\\")
+	if plain {
+		if _, ok := plainScalar(s).(string); ok {
+			return s
+		}
+	}
+	return strconv.Quote(s)
+}
+
+// Parse reads the block-style subset of YAML that question banks and
+// fixtures need: mappings, "- " sequences, plain, 'single' and "double" quoted
+// scalars, | and > block scalars, and # comments. Anything fancier (flow
+// collections, anchors, tags, several documents) is an error rather than
+// a silent misreading. Scalars become strings, ints, float64s, bools or
+// nil, as they would from JSON.
+func Parse(src []byte) (any, error) {
+	p := &parser{raw: strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")}
+	for i, line := range p.raw {
+		text := strings.TrimLeft(line, " ")
+		if text == "" || text[0] == '#' || (i == 0 && text == "---") {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: indent with spaces, not tabs", i+1)
+		}
+		if text == "---" || text == "..." {
+			return nil, fmt.Errorf("line %d: only one document per file", i+1)
+		}
+		p.lines = append(p.lines, srcLine{num: i, indent: len(line) - len(text), text: strings.TrimRight(text, " ")})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.block(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(p.lines) {
+		if _, ok := v.([]any); ok {
+			return nil, p.errorf("expected a \"- \" item like the lines above")
+		}
+		return nil, p.errorf("unexpected indentation")
+	}
+	return v, nil
+}
+
+type parser struct {
+	raw   []string
+	lines []srcLine // the lines that aren't blank or comments
+	i     int       // next line to read
+}
+
+type srcLine struct {
+	num    int // index into raw
+	indent int
+	text   string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	line := len(p.raw)
+	if p.i < len(p.lines) {
+		line = p.lines[p.i].num + 1
+	}
+	return fmt.Errorf("line %d: "+format, append([]any{line}, args...)...)
+}
+
+func isItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// block reads the value starting at the current line, which is at indent.
+func (p *parser) block(indent int) (any, error) {
+	text := p.lines[p.i].text
+	if isItem(text) {
+		return p.sequence(indent)
+	}
+	if _, _, ok := splitKey(text); ok {
+		return p.mapping(indent)
+	}
+	p.i++
+	return parseValue(text)
+}
+
+func (p *parser) sequence(indent int) (any, error) {
+	items := []any{}
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent && isItem(p.lines[p.i].text) {
+		l := &p.lines[p.i]
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest == "" {
+			p.i++
+			v, err := p.nested(indent, false)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+			continue
+		}
+		// "- key: value" starts a mapping whose other keys line up with
+		// key, so read the rest of the line as if it were its own line
+		l.indent += len(l.text) - len(rest)
+		l.text = rest
+		v, err := p.block(l.indent)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	if p.i < len(p.lines) && p.lines[p.i].indent > indent {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return items, nil
+}
+
+func (p *parser) mapping(indent int) (any, error) {
+	m := map[string]any{}
+	for p.i < len(p.lines) && p.lines[p.i].indent == indent {
+		key, rest, ok := splitKey(p.lines[p.i].text)
+		if !ok {
+			return nil, p.errorf("expected \"key: value\"")
+		}
+		if _, dup := m[key]; dup {
+			return nil, p.errorf("%q appears twice", key)
+		}
+		p.i++
+
+		var v any
+		var err error
+		switch {
+		case rest == "":
+			// A sequence under a key may line up with the key itself
+			if v, err = p.nested(indent, true); err != nil {
+				return nil, err
+			}
+		case rest == "|" || rest == ">":
+			v = p.blockScalar(indent, rest == ">")
+		default:
+			if v, err = parseValue(rest); err != nil {
+				p.i-- // report the key's line
+				return nil, p.errorf("%s: %v", key, err)
+			}
+		}
+		m[key] = v
+	}
+	if p.i < len(p.lines) && p.lines[p.i].indent > indent {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return m, nil
+}
+
+// nested reads the value under a "key:" or "-" with nothing after it: an
+// indented block, or nothing at all (null).
+func (p *parser) nested(indent int, sameIndentItems bool) (any, error) {
+	if p.i == len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.i]
+	switch {
+	case next.indent > indent:
+		return p.block(next.indent)
+	case sameIndentItems && next.indent == indent && isItem(next.text):
+		return p.sequence(indent)
+	}
+	return nil, nil
+}
+
+// blockScalar reads the indented lines after "key: |" (kept as lines) or
+// "key: >" (folded into one), from the raw text so that blank lines and
+// # inside it survive.
+func (p *parser) blockScalar(indent int, folded bool) string {
+	start := p.lines[p.i-1].num + 1
+	end, inner := start, -1
+	for end < len(p.raw) {
+		line := p.raw[end]
+		text := strings.TrimLeft(line, " ")
+		if text != "" {
+			if len(line)-len(text) <= indent {
+				break
+			}
+			if inner < 0 {
+				inner = len(line) - len(text)
+			}
+		}
+		end++
+	}
+	var lines []string
+	for _, line := range p.raw[start:end] {
+		if len(line) > inner && inner >= 0 {
+			line = line[inner:]
+		} else {
+			line = strings.TrimLeft(line, " ")
+		}
+		lines = append(lines, line)
+	}
+	for p.i < len(p.lines) && p.lines[p.i].num < end {
+		p.i++
+	}
+	if folded {
+		return strings.Join(strings.Fields(strings.Join(lines, " ")), " ") + "\n"
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// splitKey splits "key: value" or "key:". The key may be quoted.
+func splitKey(text string) (key, rest string, ok bool) {
+	if text[0] == '"' || text[0] == '\'' {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, rest = text[1:end+1], text[end+2:]
+		if rest, ok = strings.CutPrefix(rest, ":"); !ok || (rest != "" && rest[0] != ' ') {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest), true
+	}
+	if k, ok := strings.CutSuffix(text, ":"); ok && !strings.Contains(k, ": ") {
+		return k, "", true
+	}
+	key, rest, ok = strings.Cut(text, ": ")
+	if !ok || strings.HasPrefix(key, "#") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(rest), true
+}
+
+// parseValue reads a scalar written after a key or "- ".
+func parseValue(s string) (any, error) {
+	switch s[0] {
+	case '"':
+		end := closingQuote(s)
+		if end < 0 {
+			return nil, errors.New("unterminated string")
+		}
+		if rest := strings.TrimSpace(s[end+1:]); rest != "" && rest[0] != '#' {
+			return nil, fmt.Errorf("unexpected %q after the string", rest)
+		}
+		v, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("bad string %s", s[:end+1])
+		}
+		return v, nil
+	case '\'':
+		for i := 1; i < len(s); i++ {
+			if s[i] != '\'' {
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++ // '' is an escaped '
+				continue
+			}
+			if rest := strings.TrimSpace(s[i+1:]); rest != "" && rest[0] != '#' {
+				return nil, fmt.Errorf("unexpected %q after the string", rest)
+			}
+			return strings.ReplaceAll(s[1:i], "''", "'"), nil
+		}
+		return nil, errors.New("unterminated string")
+	case '[', '{':
+		return nil, errors.New("flow collections ([...] and {...}) aren't supported; use one line per item")
+	case '&', '*', '!':
+		return nil, errors.New("anchors, aliases and tags aren't supported")
+	case '|', '>':
+		return nil, errors.New("only plain | and > block scalars are supported")
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return plainScalar(s), nil
+}
+
+// closingQuote finds the " that ends the double-quoted string at the
+// start of s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// plainScalar gives a plain scalar its type.
+func plainScalar(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	// ParseFloat also takes "Inf", "NaN" and hex, which YAML reads as strings
+	if f, err := strconv.ParseFloat(s, 64); err == nil && strings.Trim(s, "0123456789.eE+-") == "" {
+		return f
+	}
+	return s
+}
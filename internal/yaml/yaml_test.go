@@ -0,0 +1,69 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := `# a comment
+course: 4
+questions:
+- prompt: "Quoted: with \"escapes\""   # trailing comment
+  choices:
+    - plain text, with commas
+    - 'it''s single-quoted'
+    -
+      nested: map
+  answer: 1
+  explain: |
+    Two lines,
+
+    # not a comment
+  tags:
+empty: ~
+flag: true
+ratio: 1.5
+url: http://example.com/a#b
+`
+	got, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"course": 4,
+		"questions": []any{map[string]any{
+			"prompt":  `Quoted: with "escapes"`,
+			"choices": []any{"plain text, with commas", "it's single-quoted", map[string]any{"nested": "map"}},
+			"answer":  1,
+			"explain": "Two lines,\n\n# not a comment\n",
+			"tags":    nil,
+		}},
+		"empty": nil,
+		"flag":  true,
+		"ratio": 1.5,
+		"url":   "http://example.com/a#b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse =\n%#v\nwant\n%#v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct{ src, err string }{
+		{"a: 1\n  b: 2\n", "line 2: unexpected indentation"},
+		{"a: 1\na: 2\n", `line 2: "a" appears twice`},
+		{"choices: [a, b]\n", "line 1: choices: flow collections"},
+		{"a: \"open\n", "line 1: a: unterminated string"},
+		{"a:\n\t- b\n", "line 2: indent with spaces"},
+		{"a: 1\n---\nb: 2\n", "only one document"},
+		{"- a\nb: 1\n", "line 2: expected"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q) = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+}
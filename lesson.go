@@ -0,0 +1,467 @@
+package learn
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// The prose of every course lives in lessons/NN-name.md, not in the Go
+// code: the course functions run the demos, and the lesson files say what
+// they mean. Editing, searching or translating the text never touches Go.
+//
+// A lesson file is Markdown with a few conventions:
+//
+//	# Title                         the course banner
+//	intro text
+//	## 1. SECTION TITLE {#id}       a section; the course code refers to it by id
+//	<!-- output -->                 where the demo prints its output
+//	<!-- code: name -->             a Go block of name from the course's file
+//	## Key takeaways {#takeaways}   numbered list printed at the end
+//	## Cheatsheet {#cheatsheet}     "### topic" entries for the cheatsheet command
+//
+// Translations go in lessons/<lang>/ (see i18n.go).
+//
+//go:embed lessons
+var lessonFiles embed.FS
+
+// lesson is a parsed lesson file.
+type lesson struct {
+	Number     int
+	Title      string
+	Sections   []lessonSection // Sections[0] is the untitled intro
+	Takeaways  []string
+	Cheatsheet []cheatTopic
+}
+
+// cheatTopic is one "### topic" of a lesson's cheatsheet: Markdown, usually
+// a short code block.
+type cheatTopic struct {
+	Name  string
+	Lines []string
+}
+
+// lessonSection is one "##" section. Parts holds the Markdown lines
+// between output points: a section with two <!-- output --> markers has
+// three parts.
+type lessonSection struct {
+	ID    string
+	Title string
+	Parts [][]string
+}
+
+// outputMarker is where a course's demo output goes in the lesson text.
+const outputMarker = "<!-- output -->"
+
+// codeMarkerRE matches a line that stands for code from the course's Go
+// file: see expandCode.
+var codeMarkerRE = regexp.MustCompile(`^<!-- code: (\w+) -->$`)
+
+var headingRE = regexp.MustCompile(`^##\s+(.*?)\s*\{#([a-z0-9-]+)\}\s*$`)
+
+// parseLesson parses the Markdown of one lesson file.
+func parseLesson(number int, src string) (*lesson, error) {
+	l := &lesson{Number: number, Sections: []lessonSection{{Parts: [][]string{nil}}}}
+	inFence := false
+	for i, line := range strings.Split(strings.TrimRight(src, "\n"), "\n") {
+		cur := &l.Sections[len(l.Sections)-1]
+		part := &cur.Parts[len(cur.Parts)-1]
+		switch {
+		case strings.HasPrefix(line, "```"):
+			inFence = !inFence
+			*part = append(*part, line)
+		case inFence:
+			*part = append(*part, line)
+		case i == 0:
+			title, ok := strings.CutPrefix(line, "# ")
+			if !ok {
+				return nil, fmt.Errorf("lesson %d: first line must be the \"# Title\"", number)
+			}
+			l.Title = title
+		case strings.HasPrefix(line, "## "):
+			m := headingRE.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("lesson %d line %d: section heading needs an {#id}: %s", number, i+1, line)
+			}
+			if m[2] == "takeaways" || m[2] == "cheatsheet" {
+				l.Sections = append(l.Sections, lessonSection{ID: m[2], Title: m[1], Parts: [][]string{nil}})
+				continue
+			}
+			for _, s := range l.Sections {
+				if s.ID == m[2] {
+					return nil, fmt.Errorf("lesson %d line %d: duplicate section id %q", number, i+1, m[2])
+				}
+			}
+			l.Sections = append(l.Sections, lessonSection{ID: m[2], Title: m[1], Parts: [][]string{nil}})
+		case strings.TrimSpace(line) == outputMarker:
+			cur.Parts = append(cur.Parts, nil)
+		default:
+			*part = append(*part, line)
+		}
+	}
+	if inFence {
+		return nil, fmt.Errorf("lesson %d: unclosed ``` code block", number)
+	}
+
+	// The takeaways and the cheatsheet come last and are reference
+	// material, not sections the course code visits
+	for len(l.Sections) > 1 {
+		last := l.Sections[len(l.Sections)-1]
+		switch last.ID {
+		case "takeaways":
+			for _, line := range last.Parts[0] {
+				if line = strings.TrimSpace(line); line != "" {
+					l.Takeaways = append(l.Takeaways, listItemRE.ReplaceAllString(line, ""))
+				}
+			}
+		case "cheatsheet":
+			topics, err := parseCheatsheet(last.Parts[0])
+			if err != nil {
+				return nil, fmt.Errorf("lesson %d: %v", number, err)
+			}
+			l.Cheatsheet = topics
+		default:
+			return l, nil
+		}
+		l.Sections = l.Sections[:len(l.Sections)-1]
+	}
+	return l, nil
+}
+
+// expandCode replaces each "<!-- code: name -->" line of a lesson with a
+// Go code block of name from its course's file: the body of function
+// name, or type name with its methods. The code in the lesson is then
+// compiled with the course, so it can't go stale as Go and its libraries
+// move on, though it needn't run.
+func expandCode(number int, src string) (string, error) {
+	if !strings.Contains(src, "<!-- code: ") {
+		return src, nil
+	}
+	c, ok := findCourse(number)
+	if !ok {
+		return "", fmt.Errorf("lesson %d has code markers but no course", number)
+	}
+	code, err := courseSources.ReadFile(c.source())
+	if err != nil {
+		return "", err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, c.source(), code, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		m := codeMarkerRE.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		text := declCode(fset, f, code, m[1])
+		if text == "" {
+			return "", fmt.Errorf("lesson %d line %d: %s has no function or type %s", number, i+1, c.source(), m[1])
+		}
+		lines[i] = "```go\n" + text + "\n```"
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// declCode returns the code of name in f, which was parsed from src: the
+// statements of function name, or the declaration of type name and of its
+// methods. It returns "" if f has neither.
+func declCode(fset *token.FileSet, f *ast.File, src []byte, name string) string {
+	text := func(from, to token.Pos) string {
+		return string(src[fset.Position(from).Offset:fset.Position(to).Offset])
+	}
+	// withDoc is the whole declaration, with its doc comment
+	withDoc := func(doc *ast.CommentGroup, decl ast.Decl) string {
+		if doc != nil {
+			return text(doc.Pos(), decl.End())
+		}
+		return text(decl.Pos(), decl.End())
+	}
+	var typ string
+	var methods []string
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.Name == name {
+				return demoText([]byte(text(d.Body.Lbrace+1, d.Body.Rbrace)))
+			}
+			if d.Recv != nil && receiverName(d.Recv.List[0].Type) == name {
+				methods = append(methods, withDoc(d.Doc, d))
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == name {
+					typ = withDoc(d.Doc, d)
+				}
+			}
+		}
+	}
+	if typ == "" {
+		return ""
+	}
+	return strings.Join(append([]string{typ}, methods...), "\n\n")
+}
+
+// receiverName is the type name of a method receiver: T for T or *T.
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// parseCheatsheet splits a cheatsheet section into its "### topic" parts.
+func parseCheatsheet(lines []string) ([]cheatTopic, error) {
+	var topics []cheatTopic
+	inFence := false
+	for _, line := range lines {
+		if name, ok := strings.CutPrefix(line, "### "); ok && !inFence {
+			topics = append(topics, cheatTopic{Name: strings.ToLower(strings.TrimSpace(name))})
+			continue
+		}
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+		}
+		if len(topics) == 0 {
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("cheatsheet text must come under a \"### topic\": %s", line)
+			}
+			continue
+		}
+		t := &topics[len(topics)-1]
+		t.Lines = append(t.Lines, line)
+	}
+	return topics, nil
+}
+
+var listItemRE = regexp.MustCompile(`^(\d+\.|[-*])\s+`)
+
+// loadLesson finds and parses lessons/NN-*.md, translated into lessonLang
+// where there is a translation.
+func loadLesson(number int) (*lesson, error) {
+	l, err := readLesson("lessons", number)
+	if err != nil || lessonLang == "en" {
+		return l, err
+	}
+	tr, err := readLesson("lessons/"+lessonLang, number)
+	if errors.Is(err, errNoLesson) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return translateLesson(l, tr, lessonLang)
+}
+
+// resumeAt is the id of a section to pick the next course up at, set by
+// "resume". A course's code is one function, so it still runs from the
+// start, but quietly and on a fake clock until it reaches that section.
+var resumeAt string
+
+// lessonRun prints a lesson as its course runs, section by section, with
+// the course's demo output in between: it is the demo.Text behind the
+// demo.Run a course gets from demo.Start.
+type lessonRun struct {
+	*demo.Printer
+	ctx    context.Context
+	lesson *lesson
+	out    *termRenderer // prints through the printer too
+	at     int           // index into lesson.Sections
+	part   int           // next part of that section to print
+
+	watch        *watchdog // paused at the prompts; nil with no time limit
+	skipTo       string    // section to resume at; nothing is shown until then
+	shown        io.Writer // while skipping, where the output goes after
+	restoreClock func()
+}
+
+// newLessonRun sets up a run of lesson l that prints with out. The demo
+// output goes to the same writer, and out prints through the run's
+// printer from then on, so that the lesson text and the demos take turns
+// and go quiet together while skipping.
+func newLessonRun(ctx context.Context, l *lesson, out *termRenderer) *lessonRun {
+	r := &lessonRun{Printer: demo.NewPrinter(out.w), ctx: ctx, lesson: l, out: out, watch: watchdogFrom(ctx)}
+	out.w = r.Printer
+	return r
+}
+
+func init() {
+	demo.Start = func(ctx context.Context, w io.Writer, number int) *demo.Run {
+		r := startLesson(ctx, w, number)
+		return demo.NewRun(r.Printer, r)
+	}
+}
+
+// startLesson prints the banner and the intro of course number to w. The
+// lessons are embedded, so a missing or malformed one is a bug in this
+// program, not something a learner can cause: it panics. Once ctx is done,
+// the course stops at its next section.
+func startLesson(ctx context.Context, w io.Writer, number int) *lessonRun {
+	l, err := loadLesson(number)
+	if err != nil {
+		panic(err)
+	}
+	r := newLessonRun(ctx, l, newTermRenderer(w))
+	study.start(number)
+	if !r.emit(lessonEvent{Type: "course", Title: l.Title}) {
+		r.out.banner(l.Title)
+		fmt.Fprintln(r.out.w)
+	}
+	if resumeAt != "" {
+		r.skip(resumeAt)
+		resumeAt = ""
+	}
+	r.Resume()
+	return r
+}
+
+// skip hides the lesson and the demos' output until the section with the
+// given id. If the lesson has no such section, as when it has changed
+// since, the course is shown from the start.
+func (r *lessonRun) skip(id string) {
+	if !slices.ContainsFunc(r.lesson.Sections[1:], func(s lessonSection) bool { return s.ID == id }) {
+		fmt.Fprintf(r.out.w, "(course %d has no section %q any more: starting from the beginning)\n\n", r.lesson.Number, id)
+		return
+	}
+	fmt.Fprintln(r.out.w, r.out.paint(styleComment, "(picking up where you left off...)"))
+	fmt.Fprintln(r.out.w)
+	r.skipTo, r.shown = id, r.Redirect(io.Discard)
+	saved := demo.Clock
+	demo.Clock = demo.NewFakeClock()
+	r.restoreClock = func() { demo.Clock = saved }
+}
+
+// unskip shows output again, from the section skipped to.
+func (r *lessonRun) unskip() {
+	r.Redirect(r.shown)
+	r.restoreClock()
+	r.skipTo, r.shown, r.restoreClock = "", nil, nil
+}
+
+// Rest prints every section left and the end of the lesson, for a lesson
+// with no demos to run.
+func (r *lessonRun) Rest() {
+	for _, s := range r.lesson.Sections[r.at+1:] {
+		r.Section(s.ID)
+	}
+	r.End()
+}
+
+// stopIfDone stops the course once its context is done, as quitting does,
+// so that the learner picks it up again at the section they were in: its
+// demo output was cut short.
+func (r *lessonRun) stopIfDone() {
+	if r.ctx.Err() == nil {
+		return
+	}
+	next := r.skipTo
+	if r.skipTo != "" {
+		r.unskip()
+	} else if r.at > 0 {
+		next = r.lesson.Sections[r.at].ID
+	} else if len(r.lesson.Sections) > 1 {
+		next = r.lesson.Sections[1].ID
+	}
+	panic(paceStop{quit: true, next: next})
+}
+
+// Section finishes the current section and prints the heading and opening
+// text of the section with the given id, up to its first output point.
+func (r *lessonRun) Section(id string) {
+	next := -1
+	for i := r.at + 1; i < len(r.lesson.Sections); i++ {
+		if r.lesson.Sections[i].ID == id {
+			next = i
+			break
+		}
+	}
+	if next < 0 {
+		panic(fmt.Sprintf("lesson %d has no section %q after %q", r.lesson.Number, id, r.lesson.Sections[r.at].ID))
+	}
+	r.stopIfDone()
+	r.finish()
+	switch {
+	case r.skipTo == id:
+		r.unskip()
+	case r.at > 0 && r.skipTo == "":
+		r.watch.pause()
+		pace.wait(r.ctx, r.out, id)
+		r.watch.resume()
+	}
+	r.at, r.part = next, 0
+	study.enter(id)
+	if r.skipTo == "" && !r.emit(lessonEvent{Type: "section", Title: r.lesson.Sections[next].Title}) {
+		r.out.heading(r.lesson.Sections[next].Title)
+	}
+	r.Resume()
+}
+
+// Resume prints the current section's text up to its next output point.
+func (r *lessonRun) Resume() {
+	s := r.lesson.Sections[r.at]
+	if r.part < len(s.Parts) && r.skipTo == "" {
+		text := strings.TrimSpace(strings.Join(s.Parts[r.part], "\n"))
+		if text == "" || !r.emit(lessonEvent{Type: "text", Text: text}) {
+			r.out.markdown(s.Parts[r.part])
+		}
+	}
+	if r.part < len(s.Parts) {
+		r.part++
+	}
+}
+
+// finish prints whatever is left of the current section.
+func (r *lessonRun) finish() {
+	s := r.lesson.Sections[r.at]
+	for r.part < len(s.Parts) {
+		r.Resume()
+	}
+	if !lessonJSON && r.skipTo == "" && (r.at > 0 || strings.TrimSpace(strings.Join(s.Parts[0], "")) != "") {
+		fmt.Fprintln(r.out.w)
+	}
+}
+
+// End finishes the lesson with its key takeaways and the closing banner.
+func (r *lessonRun) End() {
+	r.stopIfDone()
+	r.finish()
+	if r.skipTo != "" {
+		r.unskip()
+	}
+	if r.at > 0 {
+		r.watch.pause()
+		pace.wait(r.ctx, r.out, "")
+		r.watch.resume()
+	}
+	study.finish()
+	if lessonJSON {
+		for _, t := range r.lesson.Takeaways {
+			r.emit(lessonEvent{Type: "takeaway", Text: t})
+		}
+		r.emit(lessonEvent{Type: "end"})
+		return
+	}
+	if len(r.lesson.Takeaways) > 0 {
+		r.out.heading("KEY TAKEAWAYS")
+		for i, t := range r.lesson.Takeaways {
+			r.out.prose(fmt.Sprintf("%d. %s", i+1, t))
+		}
+		fmt.Fprintln(r.out.w)
+	}
+	r.out.banner("END OF " + r.lesson.Title)
+}
@@ -0,0 +1,179 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Run with: go test -run Lesson
+
+const sampleLesson = "# SAMPLE\n\nIntro.\n\n" +
+	"## 1. FIRST {#first}\n\nBefore.\n\n<!-- output -->\n\nAfter.\n\n" +
+	"## 2. SECOND {#second}\n\n```go\n## not a heading\n<!-- output -->\n```\n\n" +
+	"## Key takeaways {#takeaways}\n\n1. One\n2. Two\n"
+
+func TestParseLesson(t *testing.T) {
+	l, err := parseLesson(1, sampleLesson)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Title != "SAMPLE" || len(l.Sections) != 3 || !slices.Equal(l.Takeaways, []string{"One", "Two"}) {
+		t.Fatalf("got %+v", l)
+	}
+	if first := l.Sections[1]; first.ID != "first" || first.Title != "1. FIRST" || len(first.Parts) != 2 {
+		t.Errorf("first section = %+v, want two parts", first)
+	}
+	// Inside a code block, headings and markers are text
+	if second := l.Sections[2]; len(second.Parts) != 1 {
+		t.Errorf("second section = %+v, want one part", second)
+	}
+
+	for _, bad := range []string{
+		"no title\n",
+		"# T\n## Untagged\n",
+		"# T\n## A {#a}\n## B {#a}\n",
+		"# T\n```\nunclosed\n",
+	} {
+		if _, err := parseLesson(1, bad); err == nil {
+			t.Errorf("parseLesson(%q) should fail", bad)
+		}
+	}
+}
+
+func TestLessonRun(t *testing.T) {
+	l, _ := parseLesson(1, sampleLesson)
+	var buf bytes.Buffer
+	r := newLessonRun(context.Background(), l, &termRenderer{w: &buf, width: 80})
+	r.Resume()
+	r.Section("first")
+	buf.WriteString("(demo output)\n")
+	r.Resume()
+	r.Section("second")
+	r.End()
+
+	want := `Intro.
+
+1. FIRST
+---
+Before.
+(demo output)
+After.
+
+2. SECOND
+---
+## not a heading
+<!-- output -->
+
+KEY TAKEAWAYS
+---
+1. One
+2. Two
+
+=== END OF SAMPLE ===
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestExpandCode(t *testing.T) {
+	src, err := expandCode(13, "# T\n\n## A {#a}\n\n<!-- code: doWork -->\n\n<!-- code: Cache -->\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"```go\nselect {\ncase <-time.After(time.Second):", // a function's body, one tab less
+		"```go\n// Cache is a simple in-memory cache.\ntype Cache struct {",
+		"func (c *Cache) Get(key string) (any, bool) {", // and its methods
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("no %q in\n%s", want, src)
+		}
+	}
+	if _, err := expandCode(13, "# T\n<!-- code: missing -->\n"); err == nil {
+		t.Error("a marker for code the course doesn't have should fail")
+	}
+}
+
+// TestLessonsMatchCourses checks each course function against its lesson:
+// it must visit every section in order and resume once per output point,
+// or text would be skipped or printed in the wrong place.
+func TestLessonsMatchCourses(t *testing.T) {
+	for _, c := range courses {
+		t.Run(c.file, func(t *testing.T) {
+			l, err := loadLesson(c.number)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(l.Takeaways) == 0 {
+				t.Error("lesson has no key takeaways")
+			}
+
+			f, err := parser.ParseFile(token.NewFileSet(), c.source(), nil, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			calls := lessonCalls(f)
+			if slices.Equal(calls, []string{"demo.Print"}) {
+				for _, s := range l.Sections {
+					if len(s.Parts) > 1 {
+						t.Errorf("demo.Print has no output to put at the markers in section %q", s.ID)
+					}
+				}
+				return
+			}
+
+			// Expected calls: Section(id) then one Resume per marker
+			want := []string{"demo.Start"}
+			for i, s := range l.Sections {
+				if i > 0 {
+					want = append(want, "Section "+s.ID)
+				}
+				for range len(s.Parts) - 1 {
+					want = append(want, "Resume")
+				}
+			}
+			want = append(want, "End")
+			if !slices.Equal(calls, want) {
+				t.Errorf("course calls\n\t%v\nlesson needs\n\t%v", calls, want)
+			}
+		})
+	}
+}
+
+// lessonCalls lists the demo.Run calls in a course file in source order,
+// e.g. ["demo.Start", "Section basics", "Resume", "End"].
+func lessonCalls(f *ast.File) []string {
+	var calls []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		x, ok := fn.X.(*ast.Ident)
+		switch {
+		case !ok:
+		case x.Name == "demo" && (fn.Sel.Name == "Start" || fn.Sel.Name == "Print"):
+			calls = append(calls, "demo."+fn.Sel.Name)
+		case x.Name != "l":
+		case fn.Sel.Name == "Resume" || fn.Sel.Name == "End":
+			calls = append(calls, fn.Sel.Name)
+		case fn.Sel.Name == "Section":
+			id, _ := strconv.Unquote(call.Args[0].(*ast.BasicLit).Value)
+			calls = append(calls, "Section "+id)
+		}
+		return true
+	})
+	return calls
+}
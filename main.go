@@ -105,9 +105,9 @@
 // 	fmt.Println("\n  Option 1: Run individual file")
 // 	fmt.Println("    go run 01-basics.go")
 // 	fmt.Println("\n  Option 2: Run all files")
-// 	fmt.Println("    go run .")
+// 	fmt.Println("    go run ./cmd/learn")
 // 	fmt.Println("\n  Option 3: Create test file and run")
-// 	fmt.Println("    go run . your_test.go")
+// 	fmt.Println("    go run ./cmd/learn your_test.go")
 
 // 	fmt.Println("\n" + strings.Repeat("═", 70))
 // 	fmt.Println("\nKEY RESOURCES:")
@@ -174,7 +174,7 @@
 
 
 
-package main
+package learn
 
 import (
 	"fmt"
@@ -182,7 +182,48 @@ import (
 	"os"
 )
 
-func main() {
+// Main runs the command line of cmd/learn.
+func Main() {
+	// go run ./cmd/learn 16          - run course 16
+	// go run ./cmd/learn all         - run every course
+	// go run ./cmd/learn resume      - pick up a paced run where you quit it
+	// go run ./cmd/learn grade       - grade your exercise solutions
+	// go run ./cmd/learn diff        - compare a solution with the reference
+	// go run ./cmd/learn export      - write the course as a website or book
+	// go run ./cmd/learn web         - read the course and run its demos in a browser
+	// go run ./cmd/learn api         - serve courses, progress and quizzes as JSON
+	// go run ./cmd/learn search      - search the lessons and course code
+	// go run ./cmd/learn cheatsheet  - a one-screen reference for a topic
+	// go run ./cmd/learn review      - flashcards of the key takeaways, spaced out
+	// go run ./cmd/learn challenge   - a small daily coding task, scaffolded and graded
+	// go run ./cmd/learn quiz        - import or export quiz question banks
+	// go run ./cmd/learn stats       - time spent, completion and weakest topics
+	// go run ./cmd/learn next        - what to study next, for your goals
+	// go run ./cmd/learn track       - learning tracks, their courses and capstones
+	// go run ./cmd/learn note        - notes on a course or section, listed and searched
+	// go run ./cmd/learn certificate - a signed certificate once everything is passed
+	// go run ./cmd/learn backup      - zip your progress, notes and solutions; restore unpacks one
+	// go run ./cmd/learn coverage    - test coverage of every module, merged, with an HTML report
+	// go run ./cmd/learn mutate      - flip a function's comparisons and see if its tests notice
+	// go run ./cmd/learn new         - generate a project skeleton: rest-api, cli, worker, library
+	// go run ./cmd/learn doctor      - check Go, Docker and the ports the courses need
+	// go run ./cmd/learn env         - start or stop the databases of courses 7-9 in Docker
+	// go run ./cmd/learn seed        - fill those databases with users, products and orders
+	// go run ./cmd/learn             - start the demo backend
+	if len(os.Args) > 1 {
+		var err error
+		if run, ok := commands[os.Args[1]]; ok {
+			err = run(os.Args[2:])
+		} else {
+			err = runCourses(os.Args[1:])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -194,3 +235,31 @@ func main() {
 
 	http.ListenAndServe(":"+port, nil)
 }
+
+// commands are the subcommands; any other argument is a course number.
+var commands = map[string]func(args []string) error{
+	"grade":       runGrade,
+	"diff":        runDiff,
+	"export":      runExport,
+	"web":         runWeb,
+	"api":         runAPI,
+	"search":      runSearch,
+	"cheatsheet":  runCheatsheet,
+	"review":      runReview,
+	"challenge":   runChallenge,
+	"quiz":        runQuiz,
+	"stats":       runStats,
+	"next":        runNext,
+	"track":       runTrack,
+	"note":        runNote,
+	"certificate": runCertificate,
+	"backup":      runBackup,
+	"restore":     runRestore,
+	"coverage":    runCoverage,
+	"mutate":      runMutate,
+	"resume":      runResume,
+	"new":         runNew,
+	"doctor":      runDoctor,
+	"env":         runEnv,
+	"seed":        runSeed,
+}
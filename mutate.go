@@ -0,0 +1,70 @@
+package learn
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/mutate"
+)
+
+// runMutate implements "go run ./cmd/learn mutate [flags] <file.go> <function>".
+func runMutate(args []string) error {
+	flags := flag.NewFlagSet("mutate", flag.ContinueOnError)
+	run := flags.String("run", "", "run only the tests matching this regexp, as go test -run does")
+	timeout := flags.Duration("timeout", 10*time.Minute, "how long all the mutants may take")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn mutate [flags] <file.go> <function>")
+		fmt.Fprintln(flags.Output(), "Makes mutants of the function - each comparison flipped, each constant")
+		fmt.Fprintln(flags.Output(), "compared with off by one - and runs the package's tests against each.")
+		fmt.Fprintln(flags.Output(), "A mutant no test fails on survived: see course 32.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("mutate needs a file and a function")
+	}
+	file, funcName := flags.Arg(0), flags.Arg(1)
+
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	mutants, err := mutate.Generate(file, src, funcName)
+	if err != nil {
+		return err
+	}
+	if len(mutants) == 0 {
+		return fmt.Errorf("%s has no comparisons to mutate", funcName)
+	}
+	var testArgs []string
+	if *run != "" {
+		testArgs = []string{"-run", *run}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	fmt.Printf("Testing %d mutants of %s...\n", len(mutants), funcName)
+	results, err := mutate.Run(ctx, file, mutants, testArgs...)
+	if err != nil {
+		return err
+	}
+	killed := 0
+	for _, r := range results {
+		if r.Killed() {
+			killed++
+			fmt.Printf("  killed    %-22s by %s\n", r.Mutant, strings.Join(r.Failed, ", "))
+		} else {
+			fmt.Printf("  SURVIVED  %s\n", r.Mutant)
+		}
+	}
+	fmt.Printf("Mutation score: %d of %d killed (%d%%)\n", killed, len(results), 100*killed/len(results))
+	return nil
+}
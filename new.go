@@ -0,0 +1,176 @@
+package learn
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// projectTemplate is a skeleton "new" can generate, laid out the way
+// course 11 teaches.
+type projectTemplate struct {
+	name    string
+	summary string
+}
+
+var projectTemplates = []projectTemplate{
+	{"rest-api", "JSON HTTP API: cmd/server, internal/api, config and an in-memory store"},
+	{"cli", "command-line tool: cmd/<name> and its logic in internal/app"},
+	{"worker", "background worker pool that stops cleanly on SIGTERM"},
+	{"library", "importable package with table-driven tests and an example"},
+}
+
+// The skeletons live in testdata/new/<template>, plus the files in
+// testdata/new/common that every project gets. Each file is a text/template
+// of projectData. A ".tmpl" suffix is dropped when it is written: go.mod
+// must have one, or it would make the folder a module of its own that
+// can't be embedded, and so do Go files that aren't Go until filled in.
+// "NAME" in a path is the project name.
+//
+//go:embed all:testdata/new
+var projectFiles embed.FS
+
+// scaffoldGoVersion is the go line of new projects: the first release
+// with method and wildcard patterns in http.ServeMux, which rest-api uses.
+const scaffoldGoVersion = "1.22"
+
+// projectData is what the templates are filled in with.
+type projectData struct {
+	Name      string // as given: the folder, binary and image name
+	Package   string // Name as a package name, for library
+	Module    string
+	GoVersion string
+}
+
+var projectNameRE = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// runNew implements "go run ./cmd/learn new [flags] <template> <name>" and "new list".
+func runNew(args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	module := flags.String("module", "", "module path (default example.com/<name>)")
+	dir := flags.String("dir", "", "folder to create (default <name>)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn new [flags] <template> <name>")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn new list")
+		fmt.Fprintln(flags.Output(), "Generates a project that builds and passes its tests, laid out as in course 11.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 || flags.Arg(0) == "list" {
+		listProjectTemplates(os.Stdout)
+		return nil
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("name a template and the project, e.g. go run ./cmd/learn new rest-api myapi")
+	}
+
+	t, err := findProjectTemplate(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	name := flags.Arg(1)
+	if !projectNameRE.MatchString(name) {
+		return fmt.Errorf("invalid project name %q: use lowercase letters, digits and hyphens, starting with a letter", name)
+	}
+	data := projectData{
+		Name:      name,
+		Package:   strings.ReplaceAll(name, "-", ""),
+		Module:    *module,
+		GoVersion: scaffoldGoVersion,
+	}
+	if data.Module == "" {
+		data.Module = "example.com/" + name
+	}
+	if *dir == "" {
+		*dir = name
+	}
+
+	files, err := scaffoldProject(*dir, t, data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %s, a %s project (module %s):\n", *dir, t.name, data.Module)
+	for _, f := range files {
+		fmt.Printf("  %s\n", f)
+	}
+	fmt.Printf("\nNext: cd %s && make test\n", *dir)
+	return nil
+}
+
+func findProjectTemplate(name string) (projectTemplate, error) {
+	var names []string
+	for _, t := range projectTemplates {
+		if t.name == name {
+			return t, nil
+		}
+		names = append(names, t.name)
+	}
+	return projectTemplate{}, fmt.Errorf("no template %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+func listProjectTemplates(w io.Writer) {
+	fmt.Fprintln(w, "PROJECT TEMPLATES")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, t := range projectTemplates {
+		fmt.Fprintf(tw, "  %s\t%s\n", t.name, t.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nCreate one with: go run ./cmd/learn new <template> <name>")
+}
+
+// scaffoldProject writes the files of template t into dir, which must not
+// exist or be empty, and returns their paths relative to it.
+func scaffoldProject(dir string, t projectTemplate, data projectData) ([]string, error) {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s already exists and isn't empty", dir)
+	}
+	var written []string
+	for _, root := range []string{"testdata/new/common", "testdata/new/" + t.name} {
+		err := fs.WalkDir(projectFiles, root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel := strings.TrimPrefix(p, root+"/")
+			rel = strings.ReplaceAll(strings.TrimSuffix(rel, ".tmpl"), "NAME", data.Name)
+			src, err := projectFiles.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			tmpl, err := template.New(path.Base(p)).Option("missingkey=error").Parse(string(src))
+			if err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				return err
+			}
+			out := filepath.Join(dir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+				return err
+			}
+			written = append(written, rel)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("writing the %s project: %w", t.name, err)
+		}
+	}
+	return written, nil
+}
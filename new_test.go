@@ -0,0 +1,70 @@
+package learn
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProjectTemplates generates every template and checks that it
+// builds, vets clean and passes its own tests.
+func TestProjectTemplates(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not on PATH")
+	}
+	for _, tmpl := range projectTemplates {
+		t.Run(tmpl.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			data := projectData{Name: "my-" + tmpl.name, Package: "my" + strings.ReplaceAll(tmpl.name, "-", ""), Module: "example.com/x/my-" + tmpl.name, GoVersion: scaffoldGoVersion}
+			files, err := scaffoldProject(dir, tmpl, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"go.mod", "Makefile", "Dockerfile", "README.md", ".gitignore"} {
+				if !slices.Contains(files, want) {
+					t.Errorf("no %s in %q", want, files)
+				}
+			}
+			if !slices.ContainsFunc(files, func(f string) bool { return strings.HasSuffix(f, "_test.go") }) {
+				t.Errorf("no test in %q", files)
+			}
+			for _, f := range files {
+				if strings.Contains(f, "NAME") || strings.HasSuffix(f, ".tmpl") {
+					t.Errorf("file %s wasn't renamed", f)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			for _, args := range [][]string{{"vet", "./..."}, {"test", "./..."}} {
+				if out, err := goCommand(ctx, dir, args...); err != nil {
+					t.Errorf("go %s: %v\n%s", strings.Join(args, " "), err, out)
+				}
+			}
+		})
+	}
+}
+
+func TestScaffoldRefusesFolder(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644)
+	cli, err := findProjectTemplate("cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scaffoldProject(dir, cli, projectData{Name: "x", Package: "x", Module: "x", GoVersion: scaffoldGoVersion}); err == nil {
+		t.Error("wrote a project over a folder with files in it")
+	}
+	if _, err := findProjectTemplate("gui"); err == nil {
+		t.Error("found a template that doesn't exist")
+	}
+	if err := runNew([]string{"cli", "My_Tool"}); err == nil {
+		t.Error("accepted a project name with capitals and underscores")
+	}
+}
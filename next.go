@@ -0,0 +1,241 @@
+package learn
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// runNext implements "go run ./cmd/learn next [flags]".
+func runNext(args []string) error {
+	flags := flag.NewFlagSet("next", flag.ContinueOnError)
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file")
+	goalList := flags.String("goal", "", `tracks to aim for, remembered: backend, cli, data, sre, comma-separated ("none" forgets them)`)
+	n := flags.Int("n", 3, "how many steps to suggest")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn next [flags]")
+		fmt.Fprintln(flags.Output(), "Suggests what to study next from your progress, quiz scores and goals.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return errors.New("next takes no arguments")
+	}
+
+	p, err := loadProgress(*progressPath)
+	if err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	if *goalList != "" {
+		goals, err := parseGoals(*goalList)
+		if err != nil {
+			return err
+		}
+		p, err = updateProgress(*progressPath, func(p *progress) error {
+			p.Goals = goals
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log, err := loadStudyLog(studyLogPath(*progressPath))
+	if err != nil {
+		return fmt.Errorf("reading the time log: %w", err)
+	}
+	printNext(os.Stdout, courseStats(p, log), p.Goals, *n)
+	return nil
+}
+
+func parseGoals(list string) ([]string, error) {
+	if list == "none" {
+		return nil, nil
+	}
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		t, err := trackArg(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(names, t.name) {
+			names = append(names, t.name)
+		}
+	}
+	return names, nil
+}
+
+// goalCourses is the courses of the goal tracks, or nil when there are no
+// goals. A track holds everything its courses require.
+func goalCourses(names []string) map[int]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	wanted := map[int]bool{}
+	for _, name := range names {
+		t, _ := findTrack(name)
+		for _, n := range t.courses {
+			wanted[n] = true
+		}
+	}
+	return wanted
+}
+
+// step is one suggestion from "next".
+type step struct {
+	course  int
+	what    string
+	why     string
+	command string
+}
+
+// nextSteps orders what is left to do:
+//  1. quizzes standing in the way of other courses: a course counts as
+//     finished, for the courses that require it, once it is read and its
+//     quiz passed
+//  2. courses whose requirements are met, those the goals need first
+//  3. the rest of the read courses: exercises, quizzes nothing waits for
+//  4. courses the goals don't need
+func nextSteps(stats []courseStat, goalNames []string) []step {
+	byNumber := map[int]courseStat{}
+	for _, s := range stats {
+		byNumber[s.course.number] = s
+	}
+	finished := func(n int) bool {
+		s := byNumber[n]
+		return s.read && s.quiz >= quizPassMark
+	}
+	wanted := goalCourses(goalNames)
+	inGoals := func(n int) bool { return wanted == nil || wanted[n] }
+
+	var tiers [4][]step
+	for _, s := range stats {
+		c := s.course
+		var waiting []int
+		for _, other := range stats {
+			if !other.read && slices.Contains(other.course.requires, c.number) {
+				waiting = append(waiting, other.course.number)
+			}
+		}
+
+		if !s.read {
+			if !slices.ContainsFunc(c.requires, func(r int) bool { return !finished(r) }) {
+				st := step{
+					course:  c.number,
+					what:    fmt.Sprintf("Start course %d, %s", c.number, c.name),
+					command: fmt.Sprintf("go run ./cmd/learn %d", c.number),
+					why:     "Its requirements are done.",
+				}
+				switch {
+				case wanted != nil && inGoals(c.number):
+					st.why = "Part of the " + goalTitles(goalNames) + verb(len(goalNames), " track.", " tracks.")
+				case wanted != nil:
+					st.why = "Not needed for your goals, but ready when you are."
+				case len(waiting) > 0:
+					st.why = fmt.Sprintf("%s %s it.", courseList(waiting), verb(len(waiting), "needs", "need"))
+				}
+				tier := 1
+				if !inGoals(c.number) {
+					tier = 3
+				}
+				tiers[tier] = append(tiers[tier], st)
+			}
+			continue
+		}
+
+		if s.quiz < quizPassMark {
+			st := step{course: c.number, command: fmt.Sprintf("go run ./cmd/learn api, then take /quiz/%d", c.number)}
+			if s.quiz < 0 {
+				st.what = fmt.Sprintf("Take the quiz of course %d, %s", c.number, c.name)
+			} else {
+				st.what = fmt.Sprintf("Retake the quiz of course %d, %s (best %d%%, pass mark %d%%)", c.number, c.name, s.quiz, quizPassMark)
+				st.command = fmt.Sprintf("go run ./cmd/learn review %d, then the quiz again", c.number)
+			}
+			tier := 2
+			if len(waiting) > 0 {
+				st.why = fmt.Sprintf("%s %s it passed.", courseList(waiting), verb(len(waiting), "needs", "need"))
+				tier = 0
+			} else {
+				st.why = "To finish the course."
+			}
+			tiers[tier] = append(tiers[tier], st)
+		}
+		for _, e := range s.exercises {
+			if e.best == 100 {
+				continue
+			}
+			st := step{course: c.number, command: "go run ./cmd/learn grade " + e.name, why: fmt.Sprintf("Practice for course %d.", c.number)}
+			if e.best < 0 {
+				st.what = "Solve exercise " + e.name
+			} else {
+				st.what = fmt.Sprintf("Finish exercise %s (best %d%%)", e.name, e.best)
+			}
+			tiers[2] = append(tiers[2], st)
+		}
+	}
+	return slices.Concat(tiers[:]...)
+}
+
+func printNext(w io.Writer, stats []courseStat, goalNames []string, n int) {
+	steps := nextSteps(stats, goalNames)
+	if len(goalNames) > 0 {
+		fmt.Fprintf(w, "NEXT (goals: %s)\n", goalTitles(goalNames))
+	} else {
+		fmt.Fprintln(w, "NEXT")
+	}
+	if len(steps) == 0 {
+		fmt.Fprintln(w, "Everything is done. Claim your certificate: go run ./cmd/learn certificate")
+		return
+	}
+	for i, st := range steps[:min(n, len(steps))] {
+		fmt.Fprintf(w, "%2d. %s\n    %s\n    $ %s\n", i+1, st.what, st.why, st.command)
+	}
+	if more := len(steps) - n; more > 0 {
+		fmt.Fprintf(w, "\n...and %s after these.\n", plural(more, "more step", "more steps"))
+	}
+	if len(goalNames) == 0 {
+		fmt.Fprintln(w, "\nTell next what you want to build: go run ./cmd/learn next -goal backend|cli|data|sre")
+	}
+}
+
+func goalTitles(names []string) string {
+	var titles []string
+	for _, name := range names {
+		t, _ := findTrack(name)
+		titles = append(titles, t.title)
+	}
+	return andList(titles)
+}
+
+// courseList names courses: "Course 4", "Courses 4, 6 and 10".
+func courseList(numbers []int) string {
+	var s []string
+	for _, n := range numbers {
+		s = append(s, strconv.Itoa(n))
+	}
+	return verb(len(numbers), "Course ", "Courses ") + andList(s)
+}
+
+// andList joins words the way they are written: "a", "a and b",
+// "a, b and c".
+func andList(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+// verb picks the singular or plural form for n things.
+func verb(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,104 @@
+package learn
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPrerequisites(t *testing.T) {
+	for _, c := range courses {
+		for _, r := range c.requires {
+			if _, ok := findCourse(r); !ok || r >= c.number {
+				t.Errorf("course %d requires %d, which doesn't come before it", c.number, r)
+			}
+		}
+	}
+	for _, tr := range tracks {
+		for i, n := range tr.courses {
+			c, ok := findCourse(n)
+			if !ok {
+				t.Errorf("track %s: no course %d", tr.name, n)
+			}
+			for _, r := range c.requires {
+				if !slices.Contains(tr.courses[:i], r) {
+					t.Errorf("track %s: course %d requires %d, which doesn't come before it", tr.name, n, r)
+				}
+			}
+		}
+		for _, name := range tr.capstones {
+			if _, err := os.Stat(filepath.Join("examples", name, "go.mod")); err != nil {
+				t.Errorf("track %s: capstone %s: %v", tr.name, name, err)
+			}
+			if capstoneSummaries[name] == "" {
+				t.Errorf("track %s: capstone %s has no summary", tr.name, name)
+			}
+		}
+	}
+}
+
+func TestNextSteps(t *testing.T) {
+	p := &progress{
+		Exercises: map[string]exerciseProgress{"fizzbuzz": {Best: 100}, "reverse": {Best: 50}},
+		Quizzes:   map[int]quizProgress{1: {Best: 100}, 2: {Best: 80}, 3: {Best: 40}},
+	}
+	log := &studyLog{Courses: map[int]courseTime{1: {Finished: 1}, 2: {Finished: 1}, 3: {Finished: 1}}}
+	stats := courseStats(p, log)
+
+	var got []string
+	for _, st := range nextSteps(stats, nil) {
+		got = append(got, st.what)
+	}
+	want := []string{
+		// Courses 4, 6, 8, 9, 10 and 20 wait for it
+		"Retake the quiz of course 3, STRUCTS & INTERFACES (best 40%, pass mark 60%)",
+		"Start course 5, FILE HANDLING",
+		"Finish exercise reverse (best 50%)",
+		"Solve exercise safedivide",
+		"Solve exercise shapes",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("next steps:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// With course 3 passed, the backend goal puts HTTP before courses it
+	// doesn't need
+	p.Quizzes[3] = quizProgress{Best: 100}
+	steps := nextSteps(courseStats(p, log), []string{"backend"})
+	var order []int
+	for _, st := range steps {
+		if strings.HasPrefix(st.what, "Start") {
+			order = append(order, st.course)
+		}
+	}
+	if want := []int{4, 6, 10, 5, 8, 9}; !slices.Equal(order, want) {
+		t.Errorf("courses to start: %v, want %v", order, want)
+	}
+
+	var buf bytes.Buffer
+	printNext(&buf, courseStats(p, log), []string{"backend", "cli"}, 2)
+	for _, want := range []string{
+		"NEXT (goals: Web Backend and CLI & Tooling)",
+		" 1. Start course 4, GOROUTINES & CHANNELS\n    Part of the Web Backend and CLI & Tooling tracks.\n    $ go run ./cmd/learn 4\n",
+		"...and 7 more steps after these.",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestParseGoals(t *testing.T) {
+	if g, err := parseGoals("data, backend,data"); err != nil || strings.Join(g, " ") != "data backend" {
+		t.Errorf("parseGoals = %q, %v", g, err)
+	}
+	if g, err := parseGoals("none"); err != nil || g != nil {
+		t.Errorf("none = %q, %v", g, err)
+	}
+	if _, err := parseGoals("games"); err == nil || !strings.Contains(err.Error(), "backend, cli, data, sre") {
+		t.Errorf("unknown goal: %v", err)
+	}
+}
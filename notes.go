@@ -0,0 +1,317 @@
+package learn
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/safefile"
+)
+
+// notebook is the learner's notes, kept in notes.json next to the progress
+// file.
+type notebook struct {
+	Notes []note `json:"notes"`
+}
+
+// note is one note on a course, or on one section of it.
+type note struct {
+	ID      int       `json:"id"`
+	Course  int       `json:"course"`
+	Section string    `json:"section,omitempty"` // section id; empty for the course as a whole
+	Text    string    `json:"text"`
+	Created time.Time `json:"created"`
+}
+
+// notesPath is the notebook that goes with a progress file.
+func notesPath(progressPath string) string {
+	return filepath.Join(filepath.Dir(progressPath), "notes.json")
+}
+
+// loadNotes reads the notebook. A missing file is an empty notebook.
+func loadNotes(path string) (*notebook, error) {
+	nb := &notebook{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nb, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, nb); err != nil {
+		return nil, err
+	}
+	return nb, nil
+}
+
+func (nb *notebook) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(nb, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// add appends a note and returns it with its id: one more than the
+// highest so far.
+func (nb *notebook) add(course int, section, text string, at time.Time) note {
+	n := note{ID: 1, Course: course, Section: section, Text: text, Created: at}
+	for _, other := range nb.Notes {
+		n.ID = max(n.ID, other.ID+1)
+	}
+	nb.Notes = append(nb.Notes, n)
+	return n
+}
+
+// remove deletes the note with the given id and reports whether there was
+// one.
+func (nb *notebook) remove(id int) bool {
+	i := slices.IndexFunc(nb.Notes, func(n note) bool { return n.ID == id })
+	if i < 0 {
+		return false
+	}
+	nb.Notes = slices.Delete(nb.Notes, i, i+1)
+	return true
+}
+
+// search returns the notes that contain every word of query, ignoring
+// case, in their text or section id.
+func (nb *notebook) search(query string) []note {
+	words := strings.Fields(strings.ToLower(query))
+	var found []note
+	for _, n := range nb.Notes {
+		text := strings.ToLower(n.Text + " " + n.Section)
+		if !slices.ContainsFunc(words, func(w string) bool { return !strings.Contains(text, w) }) {
+			found = append(found, n)
+		}
+	}
+	return found
+}
+
+// updateNotes reads the notebook, changes it and saves it, all under the
+// progress file's lock, as updateProgress does: two runs adding a note at
+// once each keep the other's.
+func updateNotes(progressPath string, change func(nb *notebook) error) error {
+	path := notesPath(progressPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	lock, err := safefile.Obtain(progressPath + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	nb, err := loadNotes(path)
+	if err != nil {
+		return fmt.Errorf("reading notes: %w", err)
+	}
+	if err := change(nb); err != nil {
+		return err
+	}
+	if err := nb.save(path); err != nil {
+		return fmt.Errorf("saving notes: %w", err)
+	}
+	return nil
+}
+
+// runNote implements "go run ./cmd/learn note [flags] <course[/section]> text",
+// "note list [course]", "note search words" and "note rm id".
+func runNote(args []string) error {
+	flags := flag.NewFlagSet("note", flag.ContinueOnError)
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file; notes are kept in notes.json next to it")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn note [flags] <course>[/<section>] text...")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn note [flags] list [course]")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn note [flags] search words...")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn note [flags] rm <id>")
+		fmt.Fprintln(flags.Output(), "Notes on a course or one of its sections, e.g. note 4/select-statement \"default makes it non-blocking\".")
+		fmt.Fprintln(flags.Output(), "Include them in an export with: go run ./cmd/learn export html -notes")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("name a course to write a note on, or list, search or rm")
+	}
+
+	nb, err := loadNotes(notesPath(*progressPath))
+	if err != nil {
+		return fmt.Errorf("reading notes: %w", err)
+	}
+	rest := flags.Args()[1:]
+	switch flags.Arg(0) {
+	case "list":
+		if len(rest) > 1 {
+			return errors.New("list takes at most one course")
+		}
+		notes := nb.Notes
+		if len(rest) == 1 {
+			n, err := strconv.Atoi(rest[0])
+			if err != nil {
+				return fmt.Errorf("invalid course %q: expected a number", rest[0])
+			}
+			notes = slices.DeleteFunc(slices.Clone(notes), func(x note) bool { return x.Course != n })
+		}
+		printNotes(os.Stdout, notes)
+		return nil
+	case "search":
+		if len(rest) == 0 {
+			return errors.New("search for what? go run ./cmd/learn note search <words>")
+		}
+		printNotes(os.Stdout, nb.search(strings.Join(rest, " ")))
+		return nil
+	case "rm":
+		if len(rest) != 1 {
+			return errors.New("name one note by its id, as shown by: go run ./cmd/learn note list")
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(rest[0], "#"))
+		if err != nil {
+			return fmt.Errorf("no note %s", rest[0])
+		}
+		err = updateNotes(*progressPath, func(nb *notebook) error {
+			if !nb.remove(id) {
+				return fmt.Errorf("no note %s", rest[0])
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted note #%d\n", id)
+		return nil
+	}
+
+	course, section, err := noteTarget(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(strings.Join(rest, " "))
+	if text == "" {
+		return fmt.Errorf("write the note after the course: go run ./cmd/learn note %s \"your note\"", flags.Arg(0))
+	}
+	var n note
+	err = updateNotes(*progressPath, func(nb *notebook) error {
+		n = nb.add(course, section, text, time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Saved note #%d on %s\n", n.ID, noteWhere(n))
+	return nil
+}
+
+// noteTarget reads "4" or "4/select": a course, and a section of its
+// lesson.
+func noteTarget(arg string) (course int, section string, err error) {
+	number, section, _ := strings.Cut(arg, "/")
+	c, err := courseArg(number)
+	if err != nil || len(c) != 1 {
+		return 0, "", fmt.Errorf("invalid note target %q: expected a course number, or course/section", arg)
+	}
+	if section == "" {
+		return c[0].number, "", nil
+	}
+	l, err := loadLesson(c[0].number)
+	if err != nil {
+		return 0, "", err
+	}
+	var ids []string
+	for _, s := range l.Sections[1:] {
+		if s.ID == section {
+			return c[0].number, section, nil
+		}
+		ids = append(ids, s.ID)
+	}
+	return 0, "", fmt.Errorf("course %d has no section %q (sections: %s)", c[0].number, section, strings.Join(ids, ", "))
+}
+
+// noteWhere says what a note is on: "course 4" or "course 4, section select".
+func noteWhere(n note) string {
+	if n.Section == "" {
+		return fmt.Sprintf("course %d", n.Course)
+	}
+	return fmt.Sprintf("course %d, section %s", n.Course, n.Section)
+}
+
+// printNotes lists notes by course, oldest first.
+func printNotes(w io.Writer, notes []note) {
+	if len(notes) == 0 {
+		fmt.Fprintln(w, "No notes. Write one with: go run ./cmd/learn note <course>[/<section>] \"text\"")
+		return
+	}
+	notes = slices.Clone(notes)
+	slices.SortStableFunc(notes, func(a, b note) int { return a.Course - b.Course })
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	course := 0
+	for _, n := range notes {
+		if n.Course != course {
+			if course != 0 {
+				fmt.Fprintln(tw)
+			}
+			course = n.Course
+			c, _ := findCourse(course)
+			fmt.Fprintf(tw, "COURSE %d: %s\n", c.number, c.name)
+		}
+		section := n.Section
+		if section == "" {
+			section = "-"
+		}
+		fmt.Fprintf(tw, "  #%d\t%s\t%s\t%s\n", n.ID, n.Created.Format("2006-01-02 15:04"), section, n.Text)
+	}
+	tw.Flush()
+}
+
+// bookNote is a note as exports show it.
+type bookNote struct {
+	Date string
+	Text template.HTML
+
+	text string
+}
+
+// addNotes attaches notes to the courses and sections of a book.
+func (b *book) addNotes(nb *notebook) {
+	for _, n := range nb.Notes {
+		bn := bookNote{Date: n.Created.Format(dateLayout), Text: inlineHTML(n.Text), text: n.Text}
+		for _, c := range b.Courses {
+			if c.Number != n.Course {
+				continue
+			}
+			i := slices.IndexFunc(c.Sections, func(s bookSection) bool { return s.ID == n.Section })
+			if i < 0 {
+				// On the course, or on a section that has since gone
+				c.Notes = append(c.Notes, bn)
+				continue
+			}
+			c.Sections[i].Notes = append(c.Sections[i].Notes, bn)
+		}
+	}
+}
+
+// exportNotes loads the learner's notes into b for "export -notes".
+func exportNotes(b *book) error {
+	nb, err := loadNotes(notesPath(defaultProgressPath()))
+	if err != nil {
+		return fmt.Errorf("reading notes: %w", err)
+	}
+	b.addNotes(nb)
+	return nil
+}
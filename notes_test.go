@@ -0,0 +1,148 @@
+package learn
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotebook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+	nb, err := loadNotes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	nb.add(4, "select", "A default case makes select non-blocking", at)
+	nb.add(1, "", "Review zero values", at.Add(time.Hour))
+	nb.add(4, "", "Buffered channels decouple sender and receiver", at.Add(2*time.Hour))
+	if !nb.remove(2) || nb.remove(2) {
+		t.Error("remove(2) should delete the note once")
+	}
+	// Ids aren't reused, so a deleted note's id never names another
+	if n := nb.add(1, "", "again", at); n.ID != 4 {
+		t.Errorf("new note got id %d, want 4", n.ID)
+	}
+	if err := nb.save(path); err != nil {
+		t.Fatal(err)
+	}
+	nb, err = loadNotes(path)
+	if err != nil || len(nb.Notes) != 3 {
+		t.Fatalf("read back %+v, %v", nb, err)
+	}
+
+	if found := nb.search("SELECT default"); len(found) != 1 || found[0].ID != 1 {
+		t.Errorf("search = %+v, want note 1", found)
+	}
+	if found := nb.search("channels select"); len(found) != 0 {
+		t.Errorf("search needs every word, found %+v", found)
+	}
+
+	var buf bytes.Buffer
+	printNotes(&buf, nb.Notes)
+	out := buf.String()
+	for _, want := range []string{
+		"COURSE 1: BASICS\n  #4  2024-03-01 09:30  -  again\n\nCOURSE 4:",
+		"  #1  2024-03-01 09:30  select  A default case makes select non-blocking\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("notes missing %q:\n%s", want, out)
+		}
+	}
+}
+
+// Runs adding notes at the same time all keep theirs, each with an id of
+// its own.
+func TestUpdateNotes(t *testing.T) {
+	progressPath := filepath.Join(t.TempDir(), "progress.json")
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			err := updateNotes(progressPath, func(nb *notebook) error {
+				nb.add(1, "", fmt.Sprintf("note %d", i), time.Now())
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	nb, err := loadNotes(notesPath(progressPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[int]bool{}
+	for _, n := range nb.Notes {
+		ids[n.ID] = true
+	}
+	if len(nb.Notes) != 10 || len(ids) != 10 {
+		t.Errorf("%d notes with %d ids, want 10 of each", len(nb.Notes), len(ids))
+	}
+
+	// A change that fails saves nothing
+	err = updateNotes(progressPath, func(nb *notebook) error {
+		nb.remove(1)
+		return errors.New("no")
+	})
+	if nb, _ := loadNotes(notesPath(progressPath)); err == nil || len(nb.Notes) != 10 {
+		t.Errorf("failed change: err %v, %d notes left", err, len(nb.Notes))
+	}
+}
+
+func TestNoteTarget(t *testing.T) {
+	if c, s, err := noteTarget("4/select-statement"); err != nil || c != 4 || s != "select-statement" {
+		t.Errorf("noteTarget(4/select-statement) = %d, %q, %v", c, s, err)
+	}
+	if c, s, err := noteTarget("19"); err != nil || c != 19 || s != "" {
+		t.Errorf("noteTarget(19) = %d, %q, %v", c, s, err)
+	}
+	for _, bad := range []string{"all", "x", "99", "4/nope"} {
+		if _, _, err := noteTarget(bad); err == nil {
+			t.Errorf("noteTarget(%q) should fail", bad)
+		}
+	}
+}
+
+func TestExportNotes(t *testing.T) {
+	b, err := loadBook(".html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb := &notebook{}
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	nb.add(1, "variables", "Prefer **:=** inside functions", at)
+	nb.add(1, "", "Whole-course note", at)
+	b.addNotes(nb)
+
+	dir := t.TempDir()
+	if err := writeSite(dir, b); err != nil {
+		t.Fatal(err)
+	}
+	basics := readSiteFile(t, filepath.Join(dir, "01-basics.html"))
+	section := basics[strings.Index(basics, `<section id="variables">`):]
+	section = section[:strings.Index(section, "</section>")]
+	if !strings.Contains(section, "<time>2024-03-01</time> Prefer <strong>:=</strong> inside functions") {
+		t.Errorf("the variables section has no note:\n%s", section)
+	}
+	if !strings.Contains(basics, "Whole-course note") {
+		t.Error("01-basics.html has no course note")
+	}
+	if other := readSiteFile(t, filepath.Join(dir, "02-functions-and-errors.html")); strings.Contains(other, `class="notes"`) {
+		t.Error("a course without notes shows a notes box")
+	}
+
+	var buf bytes.Buffer
+	if err := writePDF(&buf, b, at); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("(YOUR NOTES)")) || !bytes.Contains(buf.Bytes(), []byte("(2024-03-01: Whole-course note)")) {
+		t.Error("the PDF has no notes")
+	}
+}
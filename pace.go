@@ -0,0 +1,99 @@
+package learn
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// pacer holds a lesson at the end of each numbered section until the
+// learner presses Enter, so the text doesn't scroll past unread. It is
+// set by --paced; nil means run straight through.
+type pacer struct {
+	in *bufio.Reader
+}
+
+var pace *pacer
+
+func newPacer(in io.Reader) *pacer {
+	return &pacer{in: bufio.NewReader(in)}
+}
+
+// paceStop unwinds a course function when the learner skips the rest of
+// it or quits. runCourse recovers it.
+type paceStop struct {
+	quit bool
+	next string // id of the section the learner stopped before; "" at the end
+}
+
+// wait prompts and reads a line: Enter carries on to the section next
+// ("" for the end of the course), "s" skips the rest of the course and
+// "q" quits, as does ctx being done (Ctrl+C) while it waits. Once the
+// input runs out (it may be a pipe) the lesson runs straight through.
+func (p *pacer) wait(ctx context.Context, out *termRenderer, next string) {
+	if p == nil || p.in == nil {
+		return
+	}
+	fmt.Fprint(out.w, out.paint(styleComment, "-- Enter: next section, s: skip this course, q: quit -- "))
+	var line string
+	var err error
+	read := make(chan struct{})
+	go func() {
+		line, err = p.in.ReadString('\n')
+		close(read)
+	}()
+	select {
+	case <-read:
+	case <-ctx.Done():
+		fmt.Fprintln(out.w)
+		panic(paceStop{quit: true, next: next})
+	}
+	if err != nil && line == "" {
+		fmt.Fprintln(out.w)
+		p.in = nil
+		return
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "s":
+		panic(paceStop{})
+	case "q":
+		panic(paceStop{quit: true, next: next})
+	}
+}
+
+// courseOutcome is how running one course ended.
+type courseOutcome struct {
+	skipped bool   // the learner skipped the rest of it
+	quit    bool   // the learner quit, or ctx was done
+	next    string // with quit, the id of the section to pick up at
+	err     error  // it failed, or hung
+}
+
+// runCourse runs one course in a workspace, writing to stdout, stopping
+// early if the learner skips it or quits. A course that fails or hangs
+// says so straight away, and the caller carries on with the next one.
+func runCourse(ctx context.Context, c course) courseOutcome {
+	var o courseOutcome
+	p, hung, err := runInWorkspace(ctx, c, os.Stdout)
+	switch {
+	case err != nil:
+		fmt.Printf("\n(course %d failed: %v)\n\n", c.number, err)
+		o.err = err
+	case hung:
+		fmt.Printf("\n(stopped course %d after %v: see the goroutines it was stuck in above)\n\n", c.number, courseTimeout)
+		o.err = fmt.Errorf("stuck: stopped after %v", courseTimeout)
+	case p != nil:
+		stop, ok := p.(paceStop)
+		if !ok {
+			panic(p)
+		}
+		if !stop.quit {
+			fmt.Printf("\n(skipped the rest of course %d)\n\n", c.number)
+		}
+		o.skipped, o.quit, o.next = !stop.quit, stop.quit, stop.next
+	}
+	return o
+}
@@ -0,0 +1,221 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+func TestPacedLesson(t *testing.T) {
+	tests := []struct {
+		input    string
+		prompts  int
+		ended    bool // reached the END banner
+		wantQuit bool
+		wantNext string // where a quit stopped
+	}{
+		{"\n\n", 2, true, false, ""},
+		{"", 1, true, false, ""}, // no input: stop asking and run through
+		{"\ns\n", 2, false, false, ""},
+		{"q\n", 1, false, true, "second"},
+		{"\nq\n", 2, false, true, ""},
+	}
+	for _, tt := range tests {
+		l, _ := parseLesson(1, sampleLesson)
+		var buf bytes.Buffer
+		c := course{number: 1, run: func(ctx context.Context, _ io.Writer) error {
+			r := newLessonRun(ctx, l, &termRenderer{w: &buf, width: 80})
+			r.Resume()
+			r.Section("first")
+			r.Section("second")
+			r.End()
+			return nil
+		}}
+
+		pace = newPacer(strings.NewReader(tt.input))
+		o := runCourse(context.Background(), c)
+		if o.err != nil {
+			t.Fatal(o.err)
+		}
+		pace = nil
+
+		out := buf.String()
+		if got := strings.Count(out, "-- Enter"); got != tt.prompts {
+			t.Errorf("input %q: %d prompts, want %d\n%s", tt.input, got, tt.prompts, out)
+		}
+		if ended := strings.Contains(out, "END OF"); ended != tt.ended || o.quit != tt.wantQuit {
+			t.Errorf("input %q: ended=%v quit=%v, want %v %v", tt.input, ended, o.quit, tt.ended, tt.wantQuit)
+		}
+		if o.next != tt.wantNext {
+			t.Errorf("input %q: stopped before %q, want %q", tt.input, o.next, tt.wantNext)
+		}
+	}
+}
+
+func TestResumeLesson(t *testing.T) {
+	l, _ := parseLesson(1, sampleLesson)
+	var buf bytes.Buffer
+	stdout := os.Stdout
+	r := newLessonRun(context.Background(), l, &termRenderer{w: &buf, width: 80})
+	r.skip("second")
+	r.Println("demo output") // thrown away
+	r.Resume()
+	r.Section("first")
+	demo.Clock.Sleep(time.Hour) // on the fake clock while skipping
+	r.Resume()
+	r.Section("second")
+	if os.Stdout != stdout {
+		t.Fatal("os.Stdout wasn't restored at the section resumed at")
+	}
+	if _, ok := demo.Clock.(demo.RealClock); !ok {
+		t.Error("the clock wasn't restored")
+	}
+	r.End()
+
+	out := buf.String()
+	for _, skipped := range []string{"Intro.", "FIRST", "Before.", "After."} {
+		if strings.Contains(out, skipped) {
+			t.Errorf("resuming showed %q:\n%s", skipped, out)
+		}
+	}
+	if !strings.Contains(out, "2. SECOND") || !strings.Contains(out, "END OF SAMPLE") {
+		t.Errorf("resuming didn't show the rest of the lesson:\n%s", out)
+	}
+}
+
+func TestBookmark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	if _, _, err := resumeCourses(path); err == nil {
+		t.Error("resumed with no bookmark")
+	}
+	four, _ := findCourse(4)
+	five, _ := findCourse(5)
+	six, _ := findCourse(6)
+	if err := saveBookmark(path, four, "select-statement", []course{five, six}); err != nil {
+		t.Fatal(err)
+	}
+	got, mark, err := resumeCourses(path)
+	if err != nil || len(got) != 3 || got[0].number != 4 || mark.Section != "select-statement" {
+		t.Fatalf("resume = %v, %+v, %v; want courses 4-6 from select-statement", got, mark, err)
+	}
+
+	// Quitting at the end of a course marks the start of the next
+	if err := saveBookmark(path, four, "", []course{five, six}); err != nil {
+		t.Fatal(err)
+	}
+	if got, mark, _ := resumeCourses(path); len(got) != 2 || got[0].number != 5 || mark.Section != "" {
+		t.Errorf("after the end of course 4: %v, %+v", got, mark)
+	}
+	if err := saveBookmark(path, course{}, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := resumeCourses(path); err == nil {
+		t.Error("the bookmark wasn't cleared")
+	}
+}
+
+// cancelOn is an output that cancels when something containing s is
+// written to it, as if Ctrl+C were pressed then.
+type cancelOn struct {
+	bytes.Buffer
+	s      string
+	cancel context.CancelFunc
+}
+
+func (w *cancelOn) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), w.s) {
+		w.cancel()
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestInterruptedLesson(t *testing.T) {
+	tests := []struct {
+		at       string // what is being printed when Ctrl+C is pressed
+		paced    bool
+		wantNext string
+	}{
+		{"Intro", false, "first"},
+		{"Before.", false, "first"}, // in a section: pick it up again
+		{"-- Enter", true, "second"},
+	}
+	for _, tt := range tests {
+		l, _ := parseLesson(1, sampleLesson)
+		ctx, cancel := context.WithCancel(context.Background())
+		out := &cancelOn{s: tt.at, cancel: cancel}
+		c := course{number: 1, run: func(ctx context.Context, _ io.Writer) error {
+			r := newLessonRun(ctx, l, &termRenderer{w: out, width: 80})
+			r.Resume()
+			r.Section("first")
+			r.Resume()
+			r.Section("second")
+			r.End()
+			return nil
+		}}
+
+		if tt.paced {
+			pr, pw := io.Pipe() // Enter is never pressed
+			defer pw.Close()
+			pace = newPacer(pr)
+		}
+		o := runCourse(ctx, c)
+		if o.err != nil {
+			t.Fatal(o.err)
+		}
+		pace = nil
+		cancel()
+
+		if !o.quit || o.next != tt.wantNext {
+			t.Errorf("Ctrl+C at %q: quit=%v before %q, want a quit before %q", tt.at, o.quit, o.next, tt.wantNext)
+		}
+		if strings.Contains(out.String(), "END OF") {
+			t.Errorf("Ctrl+C at %q: the lesson ran to the end", tt.at)
+		}
+	}
+}
+
+func TestInterruptCleansUp(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // course 5 makes its folder, then stops at its first section
+	five, _ := findCourse(5)
+	if err := runCaptured(ctx, five, io.Discard); !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted course 5: err = %v, want it stopped", err)
+	}
+	if entries, _ := os.ReadDir("."); len(entries) > 0 {
+		t.Errorf("course 5 left %s behind", entries[0].Name())
+	}
+}
+
+func TestFailingCourse(t *testing.T) {
+	t.Chdir(t.TempDir())
+	c := course{number: 5, name: "File Handling", run: func(context.Context, io.Writer) error {
+		_, err := os.ReadFile("missing.txt")
+		return err
+	}}
+	o := runCourse(context.Background(), c)
+	if !errors.Is(o.err, os.ErrNotExist) || o.quit {
+		t.Fatalf("failing course: err = %v, quit = %v", o.err, o.quit)
+	}
+
+	ok := course{number: 1, name: "Basics"}
+	var buf bytes.Buffer
+	printCourseSummary(&termRenderer{w: &buf, width: 80}, []course{ok, c}, []courseOutcome{{}, o})
+	out := buf.String()
+	for _, want := range []string{"ok    1. Basics", "FAIL  5. File Handling  open missing.txt", "1 course of 2 failed."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("no %q in the summary:\n%s", want, out)
+		}
+	}
+	if err := coursesFailed([]string{"5"}); err == nil || err.Error() != "1 course failed (5)" {
+		t.Errorf("coursesFailed = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A course prints to the writer it is given, but the courses share the
+// program's state. demo.Clock, the study timer and the lesson settings
+// (pace, resumeAt, lessonLang) are package variables, set for one run at a
+// time, which is why the web server runs one course at a time (see runMu
+// in web.go). So --parallel runs each course in a process of its own,
+// with its output going to a buffer.
+
+// courseOutput is what running one course printed, and how it ended.
+type courseOutput struct {
+	out  bytes.Buffer
+	err  error
+	done chan struct{} // closed when out and err are final
+}
+
+// runParallel runs the selected courses with run, a few at a time: a
+// worker pool as in course 4, taking course indexes from a jobs channel.
+// Each course's output is written to w in course order as soon as it and
+// the courses before it are done.
+func runParallel(ctx context.Context, selected []course, run func(ctx context.Context, c course, w io.Writer) error, w io.Writer) error {
+	start := time.Now()
+	results := make([]courseOutput, len(selected))
+	for i := range results {
+		results[i].done = make(chan struct{})
+	}
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range selected {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				results[i].err = ctx.Err()
+				close(results[i].done)
+			}
+		}
+	}()
+
+	// At least a few, even on one CPU: the demos spend most of their time
+	// sleeping, not computing
+	workers := min(max(runtime.NumCPU(), 4), len(selected))
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := &results[i]
+				r.err = run(ctx, selected[i], &r.out)
+				close(r.done)
+			}
+		}()
+	}
+
+	var failed, unfinished []string
+	for i, c := range selected {
+		r := &results[i]
+		<-r.done
+		if r.err != nil && ctx.Err() != nil {
+			unfinished = append(unfinished, strconv.Itoa(c.number))
+			continue
+		}
+		w.Write(r.out.Bytes())
+		if r.err != nil {
+			fmt.Fprintf(w, "\ncourse %d failed: %v\n\n", c.number, r.err)
+			failed = append(failed, strconv.Itoa(c.number))
+		}
+	}
+	wg.Wait()
+
+	if len(unfinished) > 0 {
+		fmt.Fprintf(os.Stderr, "\nInterrupted: %s didn't finish (%s).\n", plural(len(unfinished), "course", "courses"), andList(unfinished))
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Ran %s in %v, %d at a time.\n", plural(len(selected), "course", "courses"), time.Since(start).Round(time.Millisecond), workers)
+	return coursesFailed(failed)
+}
+
+// courseProcesses runs courses as this program run again on one course,
+// with the given flags.
+type courseProcesses struct {
+	exe   string
+	args  []string
+	tmp   string // a progress folder for each child
+	width string // of the terminal, for the children's pipes
+}
+
+// newCourseProcesses sets up child processes. Each has a progress folder
+// of its own, deleted by Close: its time isn't worth recording, as nobody
+// reads along, and it mustn't race the others.
+func newCourseProcesses(args []string) (*courseProcesses, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.MkdirTemp("", "learning-golang-parallel-*")
+	if err != nil {
+		return nil, err
+	}
+	return &courseProcesses{exe: exe, args: args, tmp: tmp, width: strconv.Itoa(newTermRenderer(os.Stdout).width)}, nil
+}
+
+func (p *courseProcesses) Close() error {
+	return os.RemoveAll(p.tmp)
+}
+
+// run runs course c in a child process writing to w. Ctrl+C reaches the
+// child too; if it's still running when ctx is done, it is interrupted so
+// that it cleans up.
+func (p *courseProcesses) run(ctx context.Context, c course, w io.Writer) error {
+	progress := filepath.Join(p.tmp, strconv.Itoa(c.number), "progress.json")
+	args := append(append([]string{"-progress", progress}, p.args...), strconv.Itoa(c.number))
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.exe, args...)
+	cmd.Stdout, cmd.Stderr = w, &stderr
+	cmd.Env = append(os.Environ(), "COLUMNS="+p.width)
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = 5 * time.Second
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		// Even if it exited cleanly: on Ctrl+C it stops early
+		return ctx.Err()
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package learn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunParallel(t *testing.T) {
+	var selected []course
+	for n := 1; n <= 6; n++ {
+		selected = append(selected, course{number: n})
+	}
+	// The later courses finish first, and course 3 fails
+	fake := func(ctx context.Context, c course, w io.Writer) error {
+		time.Sleep(time.Duration(len(selected)-c.number) * 5 * time.Millisecond)
+		fmt.Fprintf(w, "course %d line 1\ncourse %d line 2\n", c.number, c.number)
+		if c.number == 3 {
+			return errors.New("exit status 2")
+		}
+		return nil
+	}
+
+	var out strings.Builder
+	err := runParallel(context.Background(), selected, fake, &out)
+	if err == nil || !strings.Contains(err.Error(), "1 course failed (3)") {
+		t.Errorf("err = %v, want course 3 reported", err)
+	}
+	var want strings.Builder
+	for _, c := range selected {
+		fmt.Fprintf(&want, "course %d line 1\ncourse %d line 2\n", c.number, c.number)
+		if c.number == 3 {
+			want.WriteString("\ncourse 3 failed: exit status 2\n\n")
+		}
+	}
+	if out.String() != want.String() {
+		t.Errorf("output:\n%s\nwant, in course order:\n%s", out.String(), want.String())
+	}
+}
+
+func TestRunParallelInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	selected := []course{{number: 1}, {number: 2}}
+	fake := func(ctx context.Context, c course, w io.Writer) error {
+		fmt.Fprintf(w, "course %d\n", c.number)
+		if c.number == 2 {
+			cancel() // Ctrl+C
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}
+
+	var out strings.Builder
+	if err := runParallel(ctx, selected, fake, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "course 1\n" {
+		t.Errorf("printed %q, want only the course that finished", out.String())
+	}
+}
@@ -0,0 +1,471 @@
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func exportPDF(args []string) error {
+	flags := flag.NewFlagSet("export pdf", flag.ContinueOnError)
+	out := flags.String("out", "learning-golang.pdf", "file to write the book to")
+	withNotes := flags.Bool("notes", false, "include your notes (go run ./cmd/learn note) after the sections")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn export pdf [flags]")
+		fmt.Fprintln(flags.Output(), "Writes every course, its key takeaways and the exercises as a printable book.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	book, err := loadBook(".html")
+	if err != nil {
+		return err
+	}
+	if *withNotes {
+		if err := exportNotes(book); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := writePDF(f, book, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d courses to %s\n", len(book.Courses), *out)
+	return nil
+}
+
+// writePDF lays b out as a book: a title page, contents, one chapter per
+// course with its demo code and key takeaways, and the exercises.
+func writePDF(w io.Writer, b *book, date time.Time) error {
+	d := &pdfDoc{}
+
+	d.newPage()
+	d.space(200)
+	d.text(fontBold, 24, 0, b.Title)
+	d.space(12)
+	d.text(fontRegular, 12, 0, fmt.Sprintf("%d courses, with their code, key takeaways and exercises", len(b.Courses)))
+	d.text(fontRegular, 12, 0, date.Format("January 2006"))
+
+	// The contents page is filled in last, when the page numbers are known
+	d.newPage()
+	contents := len(d.pages) - 1
+	starts := make([]int, len(b.Courses))
+
+	for i, c := range b.Courses {
+		d.newPage()
+		starts[i] = len(d.pages)
+		d.bookmark(fmt.Sprintf("%d. %s", c.Number, c.Name))
+		d.text(fontBold, 18, 0, fmt.Sprintf("%d. %s", c.Number, c.Title))
+		d.text(fontRegular, 9, 0, fmt.Sprintf("Run the demos with: go run ./cmd/learn %d    (source: %s)", c.Number, c.File))
+		d.space(8)
+		d.markdown(c.intro)
+		d.notes(c.Notes)
+		for _, s := range c.Sections {
+			d.heading(s.Title)
+			d.markdown(s.text)
+			if s.demo != "" {
+				d.need(30)
+				d.text(fontBold, 8, 0, "DEMO")
+				d.code(strings.Split(s.demo, "\n"))
+			}
+			d.notes(s.Notes)
+		}
+		if len(c.takeaways) > 0 {
+			d.heading("Key takeaways")
+			for i, t := range c.takeaways {
+				d.paragraph(fontRegular, 10, 0, fmt.Sprintf("%d. %s", i+1, plainInline(t)))
+			}
+		}
+		if len(c.Exercises) > 0 {
+			d.heading("Exercises")
+			for _, ex := range c.Exercises {
+				d.paragraph(fontRegular, 10, 0, fmt.Sprintf("- %s (see Exercises at the end of the book)", ex.Name))
+			}
+		}
+	}
+
+	d.newPage()
+	exercisesStart := len(d.pages)
+	d.bookmark("Exercises")
+	d.text(fontBold, 18, 0, "Exercises")
+	d.paragraph(fontRegular, 10, 0, "Each exercise has a starter file in exercises/NAME/ with // TODOs where your code goes. Grade your work with: go run ./cmd/learn grade")
+	for _, ex := range b.Exercises {
+		d.heading(fmt.Sprintf("%s (course %d: %s)", ex.Name, ex.Course.Number, ex.Course.Name))
+		d.markdown(ex.task)
+	}
+
+	d.page, d.y = d.pages[contents], pdfTop
+	d.text(fontBold, 18, 0, "Contents")
+	d.space(8)
+	for i, c := range b.Courses {
+		d.contentsLine(fmt.Sprintf("%d. %s", c.Number, c.Name), starts[i])
+	}
+	d.contentsLine("Exercises", exercisesStart)
+
+	return d.write(w)
+}
+
+// notes writes the learner's notes, indented, under a heading of their
+// own.
+func (d *pdfDoc) notes(notes []bookNote) {
+	if len(notes) == 0 {
+		return
+	}
+	d.space(4)
+	d.need(30)
+	d.text(fontBold, 8, 0, "YOUR NOTES")
+	for _, n := range notes {
+		d.paragraph(fontRegular, 9, 12, n.Date+": "+plainInline(n.text))
+	}
+	d.space(4)
+}
+
+// plainInline drops the **bold** and `code` markers from a line of prose.
+func plainInline(line string) string {
+	var b strings.Builder
+	for _, sp := range inlineSpans(line) {
+		b.WriteString(sp.text)
+	}
+	return b.String()
+}
+
+// pdfDoc lays out text on A4 pages and writes it as a PDF file. It knows
+// just enough of the format for a book of prose and code: the standard
+// Helvetica and Courier fonts, which every PDF reader has built in so
+// nothing is embedded; lines of text; shaded boxes behind code; and
+// bookmarks for the chapters.
+type pdfDoc struct {
+	pages     []*bytes.Buffer // one content stream per page
+	page      *bytes.Buffer
+	y         float64 // baseline of the next line, from the bottom
+	bookmarks []pdfBookmark
+}
+
+type pdfBookmark struct {
+	title string
+	page  int
+	y     float64
+}
+
+// Page geometry in points (1/72 inch).
+const (
+	pdfWidth  = 595.0 // A4
+	pdfHeight = 842.0
+	pdfMargin = 56.0
+	pdfTop    = pdfHeight - pdfMargin
+	pdfText   = pdfWidth - 2*pdfMargin // width of the text column
+)
+
+// The fonts, by their resource names in each page.
+const (
+	fontRegular = "F1" // Helvetica
+	fontBold    = "F2" // Helvetica-Bold
+	fontMono    = "F3" // Courier
+)
+
+func (d *pdfDoc) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pdfTop
+}
+
+// need starts a new page unless h points are left above the bottom margin.
+func (d *pdfDoc) need(h float64) {
+	if d.y-h < pdfMargin {
+		d.newPage()
+	}
+}
+
+func (d *pdfDoc) space(h float64) {
+	d.y -= h
+}
+
+func (d *pdfDoc) bookmark(title string) {
+	d.bookmarks = append(d.bookmarks, pdfBookmark{title, len(d.pages) - 1, d.y + 20})
+}
+
+// text writes one line at the left margin plus indent.
+func (d *pdfDoc) text(font string, size, indent float64, s string) {
+	lead := size * 1.35
+	d.need(lead)
+	d.y -= lead
+	fmt.Fprintf(d.page, "BT /%s %g Tf %.2f %.2f Td %s Tj ET\n", font, size, pdfMargin+indent, d.y, pdfString(s))
+}
+
+// paragraph writes s wrapped to the text column. Continuation lines of a
+// list item line up under its text.
+func (d *pdfDoc) paragraph(font string, size, indent float64, s string) {
+	hang := indent
+	if m := listMarkerRE.FindString(s); m != "" {
+		hang += textWidth(font, size, m)
+	}
+	lines := wrapText(font, size, pdfText-indent, pdfText-hang, s)
+	for i, line := range lines {
+		if i == 0 {
+			d.text(font, size, indent, line)
+		} else {
+			d.text(font, size, hang, line)
+		}
+	}
+}
+
+// heading starts a section, moving to a new page if it would otherwise
+// be alone at the bottom of this one.
+func (d *pdfDoc) heading(title string) {
+	d.space(10)
+	d.need(60)
+	d.text(fontBold, 13, 0, title)
+	d.space(4)
+}
+
+// code writes lines of code on a shaded background. They are never
+// reflowed; a line too long for the page is cut and continued.
+func (d *pdfDoc) code(lines []string) {
+	size := 8.5
+	perLine := int(pdfText / (size * 0.6))
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		for first := true; first || line != ""; first = false {
+			n := min(perLine, len([]rune(line)))
+			part := string([]rune(line)[:n])
+			line = string([]rune(line)[n:])
+
+			lead := size * 1.35
+			d.need(lead)
+			fmt.Fprintf(d.page, "0.95 g %.2f %.2f %.2f %.2f re f 0 g\n", pdfMargin-4, d.y-lead-2.5, pdfText+8, lead)
+			d.text(fontMono, size, 0, part)
+		}
+	}
+	d.space(6)
+}
+
+// markdown writes lesson Markdown: prose wrapped, code blocks as code.
+func (d *pdfDoc) markdown(lines []string) {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "```"):
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(lines[i], "```"); i++ {
+				code = append(code, lines[i])
+			}
+			d.space(2)
+			d.code(code)
+		case strings.TrimSpace(line) == "":
+			d.space(5)
+		case mdHeadingRE.MatchString(line):
+			d.text(fontBold, 10, 0, mdHeadingRE.FindStringSubmatch(line)[1])
+		default:
+			indent := textWidth(fontRegular, 10, line[:len(line)-len(strings.TrimLeft(line, " "))])
+			d.paragraph(fontRegular, 10, indent, plainInline(strings.TrimLeft(line, " ")))
+		}
+	}
+}
+
+// contentsLine writes one contents entry with its page number on the right.
+func (d *pdfDoc) contentsLine(title string, page int) {
+	d.text(fontRegular, 11, 0, title)
+	num := strconv.Itoa(page)
+	fmt.Fprintf(d.page, "BT /%s 11 Tf %.2f %.2f Td %s Tj ET\n", fontRegular, pdfWidth-pdfMargin-textWidth(fontRegular, 11, num), d.y, pdfString(num))
+}
+
+// write assembles the PDF: the catalog, the page tree, the fonts, the
+// bookmarks, then each page and its content, then the cross-reference
+// table giving the byte offset of every object.
+func (d *pdfDoc) write(w io.Writer) error {
+	// Object numbers are fixed up front so objects can refer to each other
+	const (
+		catalogObj  = 1
+		pagesObj    = 2
+		fontObj     = 3 // three fonts: 3, 4, 5
+		outlinesObj = 6
+	)
+	bookmarkObj := func(i int) int { return outlinesObj + 1 + i }
+	pageObj := func(i int) int { return bookmarkObj(len(d.bookmarks)) + 2*i }
+
+	bw := bufio.NewWriter(w)
+	var offsets []int
+	pos := 0
+	out := func(format string, args ...any) {
+		n, _ := fmt.Fprintf(bw, format, args...)
+		pos += n
+	}
+	obj := func(body string) {
+		offsets = append(offsets, pos)
+		out("%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out("%%PDF-1.4\n")
+	obj(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R /Outlines %d 0 R /PageMode /UseOutlines >>", pagesObj, outlinesObj))
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj(i)))
+	}
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, name := range []string{"Helvetica", "Helvetica-Bold", "Courier"} {
+		obj("<< /Type /Font /Subtype /Type1 /BaseFont /" + name + " /Encoding /WinAnsiEncoding >>")
+	}
+
+	if len(d.bookmarks) == 0 {
+		obj("<< /Type /Outlines /Count 0 >>")
+	} else {
+		obj(fmt.Sprintf("<< /Type /Outlines /First %d 0 R /Last %d 0 R /Count %d >>",
+			bookmarkObj(0), bookmarkObj(len(d.bookmarks)-1), len(d.bookmarks)))
+	}
+	for i, bm := range d.bookmarks {
+		links := ""
+		if i > 0 {
+			links += fmt.Sprintf(" /Prev %d 0 R", bookmarkObj(i-1))
+		}
+		if i < len(d.bookmarks)-1 {
+			links += fmt.Sprintf(" /Next %d 0 R", bookmarkObj(i+1))
+		}
+		obj(fmt.Sprintf("<< /Title %s /Parent %d 0 R%s /Dest [%d 0 R /XYZ 0 %.2f 0] >>",
+			pdfString(bm.title), outlinesObj, links, pageObj(bm.page), bm.y))
+	}
+
+	for i, content := range d.pages {
+		// Page numbers in the footer, except on the title page
+		if i > 0 {
+			num := strconv.Itoa(i + 1)
+			fmt.Fprintf(content, "BT /%s 9 Tf %.2f %.2f Td %s Tj ET\n", fontRegular, (pdfWidth-textWidth(fontRegular, 9, num))/2, pdfMargin/2, pdfString(num))
+		}
+		obj(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R /F3 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, pdfWidth, pdfHeight, fontObj, fontObj+1, fontObj+2, pageObj(i)+1))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := pos
+	out("xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		out("%010d 00000 n \n", off)
+	}
+	out("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, catalogObj, xref)
+	return bw.Flush()
+}
+
+// The standard fonts use the Windows-1252 character set. Characters
+// outside it are replaced by a lookalike, or by "?".
+var (
+	pdfReplacer = strings.NewReplacer(
+		"→", "->", "←", "<-", "≤", "<=", "≥", ">=", "≠", "!=",
+		"✓", "+", "✗", "x", "├", "|", "└", "`", "│", "|", "─", "-",
+		"═", "=", "║", "|", "╔", "+", "╗", "+", "╚", "+", "╝", "+",
+	)
+	winAnsi = map[rune]byte{
+		'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+		'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+		'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+		'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+	}
+)
+
+// winAnsiBytes encodes s for the standard fonts.
+func winAnsiBytes(s string) []byte {
+	var b []byte
+	for _, r := range pdfReplacer.Replace(s) {
+		switch c, ok := winAnsi[r]; {
+		case ok:
+			b = append(b, c)
+		case r < 0x80 || r >= 0xa0 && r <= 0xff:
+			b = append(b, byte(r))
+		default:
+			b = append(b, '?')
+		}
+	}
+	return b
+}
+
+// pdfString returns s as a PDF string literal, with non-ASCII bytes as
+// octal escapes so the file stays plain text.
+func pdfString(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, c := range winAnsiBytes(s) {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c >= 0x80:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// helveticaWidths are the widths of ASCII 32-126 in Helvetica, in
+// thousandths of the font size, from the font's published metrics.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth is the width of s in points. Bold is a little wider than
+// regular; estimating it as 10% wider keeps wrapped lines inside the
+// margin.
+func textWidth(font string, size float64, s string) float64 {
+	b := winAnsiBytes(s)
+	if font == fontMono {
+		return float64(len(b)) * size * 0.6
+	}
+	total := 0
+	for _, c := range b {
+		if c >= 32 && c <= 126 {
+			total += helveticaWidths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	w := float64(total) * size / 1000
+	if font == fontBold {
+		w *= 1.1
+	}
+	return w
+}
+
+// wrapText breaks s at spaces into lines no wider than first (for the
+// first line) and rest (for the others). A word wider than a line is put
+// on a line of its own.
+func wrapText(font string, size, first, rest float64, s string) []string {
+	var lines []string
+	var cur string
+	limit := first
+	for _, word := range strings.Fields(s) {
+		next := word
+		if cur != "" {
+			next = cur + " " + word
+		}
+		if cur != "" && textWidth(font, size, next) > limit {
+			lines = append(lines, cur)
+			cur, limit = word, rest
+			continue
+		}
+		cur = next
+	}
+	return append(lines, cur)
+}
@@ -0,0 +1,61 @@
+package httpclient_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/pkg/httpclient"
+)
+
+func Example() {
+	tries := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tries++
+		if tries == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable) // a passing failure
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		io.WriteString(w, "hello")
+	}))
+	defer server.Close()
+
+	client := httpclient.New(httpclient.Options{
+		Timeout: 5 * time.Second,
+		Retry:   httpclient.Retry{Attempts: 3, Base: 10 * time.Millisecond, Max: 100 * time.Millisecond},
+		Cache:   httpclient.NewCache(100),
+	})
+	for range 2 {
+		res, err := client.Get(server.URL)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		fmt.Printf("%d %s, from cache: %v\n", res.StatusCode, body, httpclient.FromCache(res))
+	}
+	fmt.Println(tries, "requests")
+	// Output:
+	// 200 hello, from cache: false
+	// 200 hello, from cache: true
+	// 3 requests
+}
+
+func ExampleChain() {
+	// The pieces New puts together can be used on their own, and mixed
+	// with other RoundTripper middleware
+	transport := httpclient.Chain(http.DefaultTransport,
+		httpclient.Retrying(httpclient.Retry{Attempts: 2, Base: time.Second, Max: 5 * time.Second}),
+		httpclient.RateLimit(10, 1),
+	)
+	client := &http.Client{Transport: transport, Timeout: 30 * time.Second}
+	_ = client
+}
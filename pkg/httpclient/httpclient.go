@@ -0,0 +1,392 @@
+// Package httpclient builds an *http.Client that behaves well towards the
+// servers it calls: it times out, retries what failed for a passing reason,
+// keeps under a rate limit, and revalidates cached responses by ETag
+// instead of downloading them again.
+//
+// Each of those is a Middleware around an http.RoundTripper, the client
+// side of pkg/middleware's handler chain, and each is an earlier lesson
+// applied: the timeout and the waits are course 4's context and select,
+// the backoff is the notifier capstone's, the rate limit is the token
+// bucket of the proxy capstone, and the cache is course 19's map behind a
+// mutex.
+//
+//	client := httpclient.New(httpclient.Options{
+//		Timeout:   10 * time.Second,
+//		Retry:     httpclient.Retry{Attempts: 3, Base: 200 * time.Millisecond, Max: 2 * time.Second},
+//		PerSecond: 5, // per host
+//		Cache:     httpclient.NewCache(1000),
+//	})
+//
+// It lives in its own module, like pkg/middleware, so other projects can
+// import it.
+package httpclient
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Middleware wraps a RoundTripper with extra behaviour.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// Chain applies middlewares to base (http.DefaultTransport if nil) so the
+// first one listed sees each request first.
+func Chain(base http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		base = middlewares[i](base)
+	}
+	return base
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Options configure New. The zero value is a plain client.
+type Options struct {
+	Timeout   time.Duration     // the longest one Do may take, retries and waits included; 0 means no limit
+	Retry     Retry             // the zero value never retries
+	PerSecond float64           // requests per second to each host; 0 means no limit
+	Burst     int               // requests a quiet host may get at once; 0 means 1
+	Cache     *Cache            // nil means no caching
+	Transport http.RoundTripper // nil means http.DefaultTransport
+}
+
+// New returns a client with the behaviour opts ask for. A request goes
+// through the cache first, so a cached response can be revalidated
+// however the server is doing; then retries; and the rate limit last, so
+// every attempt waits its turn.
+func New(opts Options) *http.Client {
+	var middlewares []Middleware
+	if opts.Cache != nil {
+		middlewares = append(middlewares, opts.Cache.Middleware())
+	}
+	if opts.Retry.Attempts > 1 {
+		middlewares = append(middlewares, Retrying(opts.Retry))
+	}
+	if opts.PerSecond > 0 {
+		middlewares = append(middlewares, RateLimit(opts.PerSecond, opts.Burst))
+	}
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: Chain(opts.Transport, middlewares...),
+	}
+}
+
+// ============ RETRY WITH BACKOFF ============
+
+// Retry is an exponential backoff policy: wait Base, then 2x, 4x... up to
+// Max between attempts, with jitter.
+type Retry struct {
+	Attempts int // total tries, including the first
+	Base     time.Duration
+	Max      time.Duration
+}
+
+// Retrying retries a request that failed with a network error, or with a
+// status saying the server may do better later (429, 502, 503, 504). A
+// Retry-After header on the response is honoured up to r.Max.
+//
+// Only requests that are safe to repeat are retried: GET, HEAD, OPTIONS,
+// PUT and DELETE, with no body or one that GetBody can produce again, as
+// http.NewRequest arranges for bytes and strings readers.
+func Retrying(r Retry) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !replayable(req) {
+				return next.RoundTrip(req)
+			}
+			for attempt := 1; ; attempt++ {
+				res, err := next.RoundTrip(req)
+				if attempt >= r.Attempts || !retryable(req, res, err) {
+					return res, err
+				}
+
+				wait := r.backoff(attempt)
+				if res != nil {
+					if after, ok := retryAfter(res); ok {
+						wait = min(after, r.Max)
+					}
+					// Read what's left so the connection can be reused
+					io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+					res.Body.Close()
+				}
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				}
+
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					req = req.Clone(req.Context())
+					req.Body = body
+				}
+			}
+		})
+	}
+}
+
+// backoff is the wait after the given failed attempt. The random half
+// stops many clients that failed together from retrying in lockstep.
+func (r Retry) backoff(attempt int) time.Duration {
+	d := r.Base << (attempt - 1)
+	if d <= 0 || d > r.Max { // d <= 0: the shift overflowed
+		d = r.Max
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+func replayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+func retryable(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil // not if the caller gave up
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads a Retry-After header given in seconds.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	secs, err := strconv.Atoi(res.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// ============ RATE LIMITING ============
+
+// bucket is a token bucket that refills lazily, as in the proxy capstone.
+// Here tokens may go below zero: each one below is a request already
+// promised a slot, waiting for it.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter keeps one bucket per host.
+type limiter struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// RateLimit makes requests wait so no host gets more than perSecond of
+// them per second on average, or more than burst at once. A request whose
+// context ends while it waits fails with the context's error.
+func RateLimit(perSecond float64, burst int) Middleware {
+	l := &limiter{rate: perSecond, burst: float64(max(burst, 1)), buckets: make(map[string]*bucket)}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if err := l.wait(req.Context(), req.URL.Host); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// wait takes a token for host, sleeping until there is one.
+func (l *limiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now() // under the lock, so last only moves forward
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[host] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the slot back to the requests queued behind this one
+		l.mu.Lock()
+		b.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// ============ ETAG CACHE ============
+
+// Cache keeps the responses to GET requests that came with an ETag, keyed
+// by URL. The next GET of the URL asks the server If-None-Match that ETag;
+// on 304 Not Modified the cached response is returned, marked with an
+// X-From-Cache header, and the body isn't sent again.
+//
+// It evicts the least recently used entry beyond its size. It ignores
+// Cache-Control and Vary: every response is revalidated, so it is never
+// stale, but it is no use for responses that differ by request header.
+type Cache struct {
+	maxEntries int
+	maxBody    int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // of *cacheEntry
+	lru     list.List                // front is the most recently used
+}
+
+type cacheEntry struct {
+	url    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// NewCache returns a cache of up to maxEntries responses. Bodies over
+// 1 MiB are passed through but not kept.
+func NewCache(maxEntries int) *Cache {
+	return &Cache{maxEntries: maxEntries, maxBody: 1 << 20, entries: make(map[string]*list.Element)}
+}
+
+// Len is the number of responses cached.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Middleware returns the caching Middleware.
+func (c *Cache) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// Requests with conditions or ranges of their own get what they asked for
+			if req.Method != http.MethodGet || req.Header.Get("Range") != "" || req.Header.Get("If-None-Match") != "" {
+				return next.RoundTrip(req)
+			}
+			key := req.URL.String()
+			cached := c.get(key)
+			if cached != nil {
+				// A RoundTripper must not modify the request it was given
+				req = req.Clone(req.Context())
+				req.Header.Set("If-None-Match", cached.etag)
+			}
+
+			res, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case cached != nil && res.StatusCode == http.StatusNotModified:
+				io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+				return cached.response(req), nil
+			case res.StatusCode == http.StatusOK && res.Header.Get("ETag") != "":
+				return c.store(key, res)
+			}
+			return res, nil
+		})
+	}
+}
+
+func (c *Cache) get(key string) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*cacheEntry)
+}
+
+// store reads res's body to keep it, and returns res with a body that
+// replays what was read.
+func (c *Cache) store(key string, res *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(res.Body, c.maxBody+1))
+	if err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > c.maxBody {
+		// Too big to keep: hand back what was read followed by the rest
+		res.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), res.Body), res.Body}
+		return res, nil
+	}
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &cacheEntry{url: key, etag: res.Header.Get("ETag"), header: res.Header.Clone(), body: body}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return res, nil
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	if c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).url)
+	}
+	return res, nil
+}
+
+// response builds a fresh 200 response from the entry, for req.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := e.header.Clone()
+	header.Set("X-From-Cache", "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
+
+// FromCache reports whether res was served from a Cache.
+func FromCache(res *http.Response) bool {
+	return res.Header.Get("X-From-Cache") != ""
+}
@@ -0,0 +1,276 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// get fetches url with client and returns the status and body.
+func get(t *testing.T, client *http.Client, url string) (*http.Response, string) {
+	t.Helper()
+	res, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, string(body)
+}
+
+func TestRetry(t *testing.T) {
+	fast := Retry{Attempts: 3, Base: time.Millisecond, Max: 5 * time.Millisecond}
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int // what the server answers, in turn
+		wantStatus int
+		wantTries  int32
+	}{
+		{"success needs one try", "GET", []int{200}, 200, 1},
+		{"503 is retried", "GET", []int{503, 503, 200}, 200, 3},
+		{"429 is retried", "GET", []int{429, 200}, 200, 2},
+		{"gives up after Attempts", "GET", []int{502, 502, 502, 200}, 502, 3},
+		{"404 is not retried", "GET", []int{404, 200}, 404, 1},
+		{"500 is not retried", "GET", []int{500, 200}, 500, 1},
+		{"PUT is retried", "PUT", []int{503, 200}, 200, 2},
+		{"POST is not", "POST", []int{503, 200}, 503, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tries atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := tries.Add(1)
+				if body, _ := io.ReadAll(r.Body); r.Method != "GET" && string(body) != "payload" {
+					t.Errorf("try %d got body %q", n, body)
+				}
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer server.Close()
+
+			client := New(Options{Retry: fast})
+			req, _ := http.NewRequest(tt.method, server.URL, strings.NewReader("payload"))
+			if tt.method == "GET" {
+				req, _ = http.NewRequest(tt.method, server.URL, nil)
+			}
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tt.wantStatus || tries.Load() != tt.wantTries {
+				t.Errorf("status %d after %d tries, want %d after %d", res.StatusCode, tries.Load(), tt.wantStatus, tt.wantTries)
+			}
+		})
+	}
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	var tries atomic.Int32
+	failing := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if tries.Add(1) < 3 {
+			return nil, errors.New("connection reset")
+		}
+		return &http.Response{StatusCode: 200, Body: http.NoBody, Request: req}, nil
+	})
+	client := New(Options{Retry: Retry{Attempts: 3, Base: time.Millisecond, Max: time.Millisecond}, Transport: failing})
+	res, err := client.Get("http://example.test/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if tries.Load() != 3 {
+		t.Errorf("%d tries, want 3", tries.Load())
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Retry-After asks for a minute and Max allows it, so only the timeout ends the wait
+	client := New(Options{Timeout: 50 * time.Millisecond, Retry: Retry{Attempts: 5, Base: time.Millisecond, Max: time.Minute}})
+	start := time.Now()
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("took %v: the wait ignored the timeout", took)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := New(Options{PerSecond: 50, Burst: 2}) // a request every 20ms after the first two
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	// 2 at once, then 4 more 20ms apart: at least 80ms in all
+	if took := time.Since(start); took < 70*time.Millisecond {
+		t.Errorf("6 requests took %v, want at least 80ms", took)
+	}
+	if len(times) != 6 {
+		t.Errorf("%d requests arrived, want 6", len(times))
+	}
+}
+
+func TestRateLimitIsPerHost(t *testing.T) {
+	l := &limiter{rate: 1, burst: 1, buckets: make(map[string]*bucket)}
+	ctx := context.Background()
+	if err := l.wait(ctx, "a.test"); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := l.wait(ctx, "b.test"); err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(start); took > 100*time.Millisecond {
+		t.Errorf("b.test waited %v for a.test's bucket", took)
+	}
+
+	// a.test's bucket is empty; the next token is a second away
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx, "a.test"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the wait to end with ctx", err)
+	}
+	if tokens := l.buckets["a.test"].tokens; tokens < -0.1 {
+		t.Errorf("tokens = %.2f: the cancelled wait kept its slot", tokens)
+	}
+}
+
+// etagServer serves body under an ETag derived from its version, and
+// answers If-None-Match with 304 while the version is unchanged.
+type etagServer struct {
+	version atomic.Int32
+	full    atomic.Int32 // 200s sent
+	notMod  atomic.Int32 // 304s sent
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v := s.version.Load()
+	etag := fmt.Sprintf(`"v%d"`, v)
+	if r.URL.Path == "/no-etag" {
+		fmt.Fprintf(w, "no etag, version %d", v)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		s.notMod.Add(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.full.Add(1)
+	fmt.Fprintf(w, "%s, version %d", r.URL.Path, v)
+}
+
+func TestCache(t *testing.T) {
+	site := &etagServer{}
+	server := httptest.NewServer(site)
+	defer server.Close()
+	client := New(Options{Cache: NewCache(10)})
+
+	res, body := get(t, client, server.URL+"/page")
+	if FromCache(res) || body != "/page, version 0" {
+		t.Errorf("first GET: from cache %v, body %q", FromCache(res), body)
+	}
+	res, body = get(t, client, server.URL+"/page")
+	if !FromCache(res) || res.StatusCode != 200 || body != "/page, version 0" {
+		t.Errorf("second GET: from cache %v, status %d, body %q", FromCache(res), res.StatusCode, body)
+	}
+	if site.full.Load() != 1 || site.notMod.Load() != 1 {
+		t.Errorf("server sent %d full responses and %d 304s, want 1 and 1", site.full.Load(), site.notMod.Load())
+	}
+
+	// A new version replaces the cached one
+	site.version.Add(1)
+	res, body = get(t, client, server.URL+"/page")
+	if FromCache(res) || body != "/page, version 1" {
+		t.Errorf("after a change: from cache %v, body %q", FromCache(res), body)
+	}
+	if res, _ = get(t, client, server.URL+"/page"); !FromCache(res) {
+		t.Error("the new version was not cached")
+	}
+
+	// Without an ETag there is nothing to revalidate with
+	get(t, client, server.URL+"/no-etag")
+	if res, _ = get(t, client, server.URL+"/no-etag"); FromCache(res) {
+		t.Error("a response without an ETag was cached")
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	site := &etagServer{}
+	server := httptest.NewServer(site)
+	defer server.Close()
+	cache := NewCache(2)
+	client := New(Options{Cache: cache})
+
+	get(t, client, server.URL+"/a")
+	get(t, client, server.URL+"/b")
+	get(t, client, server.URL+"/a") // now /b is the least recently used
+	get(t, client, server.URL+"/c")
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+	if res, _ := get(t, client, server.URL+"/a"); !FromCache(res) {
+		t.Error("/a was evicted")
+	}
+	if res, _ := get(t, client, server.URL+"/b"); FromCache(res) {
+		t.Error("/b is still cached")
+	}
+}
+
+func TestCacheSkipsLargeBodies(t *testing.T) {
+	big := strings.Repeat("x", 2<<20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"big"`)
+		io.WriteString(w, big)
+	}))
+	defer server.Close()
+	cache := NewCache(10)
+	client := New(Options{Cache: cache})
+
+	if _, body := get(t, client, server.URL); body != big {
+		t.Errorf("body of %d bytes, want %d", len(body), len(big))
+	}
+	if cache.Len() != 0 {
+		t.Error("a 2 MiB body was cached")
+	}
+}
@@ -0,0 +1,164 @@
+package middleware_test
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/pkg/middleware"
+)
+
+func Example() {
+	logger := log.New(log.Writer(), "", log.LstdFlags)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "hello")
+	})
+
+	// RequestID first, so the others can log the ID; then Recover, so it
+	// also catches panics in the middleware after it
+	handler := middleware.Chain(mux,
+		middleware.RequestID(),
+		middleware.Recover(logger),
+		middleware.Logging(logger),
+		middleware.CORS(middleware.CORSOptions{AllowedOrigins: []string{"https://app.example"}}),
+	)
+	_ = handler // http.ListenAndServe(":8080", handler)
+}
+
+func ExampleChain() {
+	say := func(name string) middleware.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Println(name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	hello := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println("handler")
+	})
+
+	// The first middleware is the outermost: it runs first
+	handler := middleware.Chain(hello, say("auth"), say("metrics"))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	// Output:
+	// auth
+	// metrics
+	// handler
+}
+
+func ExampleRequestID() {
+	handler := middleware.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Anything with the request's context can read the ID
+		fmt.Println("handling", middleware.RequestIDFrom(r.Context()))
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "checkout-42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	fmt.Println("response header", rec.Header().Get(middleware.RequestIDHeader))
+	// Output:
+	// handling checkout-42
+	// response header checkout-42
+}
+
+func ExampleRecorder() {
+	// A middleware that counts server errors: it needs the status code,
+	// which only the Recorder knows once the handler has run
+	serverErrors := 0
+	countErrors := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := middleware.NewRecorder(w)
+			next.ServeHTTP(rec, r)
+			if rec.Status >= 500 {
+				serverErrors++
+			}
+		})
+	}
+	fail := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "database down", http.StatusServiceUnavailable)
+	})
+
+	countErrors(fail).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	fmt.Println("server errors:", serverErrors)
+	// Output: server errors: 1
+}
+
+func ExampleCORS() {
+	cors := middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins: []string{"https://app.example"},
+		MaxAge:         time.Hour,
+	})
+	handler := cors(http.NotFoundHandler())
+
+	// The browser asks first whether app.example may PUT
+	req := httptest.NewRequest("OPTIONS", "/api/items", nil)
+	req.Header.Set("Origin", "https://app.example")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	fmt.Println(rec.Code)
+	fmt.Println(rec.Header().Get("Access-Control-Allow-Origin"))
+	fmt.Println(rec.Header().Get("Access-Control-Allow-Methods"))
+	fmt.Println(rec.Header().Get("Access-Control-Max-Age"))
+	// Output:
+	// 204
+	// https://app.example
+	// GET, POST, PUT, PATCH, DELETE
+	// 3600
+}
+
+func ExampleContextHandler() {
+	// Drop the time so the output is the same on every run
+	noTime := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return a
+	}
+	logger := slog.New(middleware.ContextHandler(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: noTime})))
+
+	handler := middleware.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "user created", "id", 4)
+	}))
+	req := httptest.NewRequest("POST", "/users", nil)
+	req.Header.Set(middleware.RequestIDHeader, "signup-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	// Output: level=INFO msg="user created" id=4 request_id=signup-1
+}
+
+func ExamplePropagateRequestID() {
+	// inventory is another service, which logs the ID it was called with
+	inventory := httptest.NewServer(middleware.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println("inventory:", middleware.RequestIDFrom(r.Context()))
+	})))
+	defer inventory.Close()
+
+	client := &http.Client{Transport: middleware.PropagateRequestID(nil)}
+	shop := middleware.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println("shop:", middleware.RequestIDFrom(r.Context()))
+		// The incoming request's context carries the ID to the client
+		req, _ := http.NewRequestWithContext(r.Context(), "GET", inventory.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		resp.Body.Close()
+	}))
+
+	req := httptest.NewRequest("POST", "/checkout", nil)
+	req.Header.Set(middleware.RequestIDHeader, "order-7")
+	shop.ServeHTTP(httptest.NewRecorder(), req)
+	// Output:
+	// shop: order-7
+	// inventory: order-7
+}
@@ -0,0 +1,366 @@
+// Package middleware is a small library of net/http middleware: request
+// IDs, access logging, CORS and panic recovery.
+//
+// Request IDs tie together everything one request caused. RequestID gives
+// each request one; ContextHandler adds it to every slog record logged with
+// the request's context, and PropagateRequestID sends it on to the services
+// the handler calls, so their logs carry it too.
+//
+// It grew out of the middleware examples in courses 6 and 12 and lives in
+// its own module, like pkg/querybuilder, so other projects can import it:
+//
+//	handler := middleware.Chain(mux,
+//		middleware.RequestID(),
+//		middleware.Recover(logger),
+//		middleware.Logging(logger),
+//	)
+//
+// RequestID goes first so that everything after it, Recover included, can
+// log the ID; Recover next, to catch panics in the rest.
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Middleware wraps a handler with extra behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middlewares so the first one listed runs first.
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// ============ RESPONSE RECORDING ============
+
+// Recorder is a ResponseWriter that remembers the status code and the
+// number of body bytes written through it. A middleware can only know
+// these after the handler has run, which is why Logging logs afterwards.
+type Recorder struct {
+	http.ResponseWriter
+	Status int   // 200 until the handler says otherwise
+	Bytes  int64 // body bytes written so far
+
+	wroteHeader bool
+}
+
+// NewRecorder wraps w.
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+// WriteHeader records the first status code; like net/http, it ignores
+// later ones.
+func (r *Recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.Status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true // the first Write sends a 200 header
+	n, err := r.ResponseWriter.Write(p)
+	r.Bytes += int64(n)
+	return n, err
+}
+
+// WroteHeader reports whether the response has started. After that the
+// status can no longer be changed.
+func (r *Recorder) WroteHeader() bool {
+	return r.wroteHeader
+}
+
+// Unwrap lets http.ResponseController reach the real ResponseWriter, to
+// flush or hijack it through the Recorder.
+func (r *Recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// ============ REQUEST ID ============
+
+// RequestIDHeader carries the request ID in requests and responses.
+const RequestIDHeader = "X-Request-Id"
+
+// requestIDKey is the context key for the request ID. An unexported type
+// means no other package can read or overwrite the value by accident.
+type requestIDKey struct{}
+
+// RequestID gives every request an ID, stored in its context (read it with
+// RequestIDFrom) and echoed in the response header. An ID the client sent
+// is kept if it looks like one, so a single ID can follow a request
+// through several services.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFrom returns the request ID that RequestID stored in ctx, or ""
+// if there is none.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID accepts up to 64 letters, digits, '-', '_' and '.'. The ID
+// ends up in log lines, so anything else - newlines above all - is replaced.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		ok := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_.", c)
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(b)
+}
+
+// ============ LOGGING ============
+
+// Logging logs one line per request once it has been served: method, path,
+// status, body bytes, duration and, after RequestID, the request ID.
+func Logging(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := NewRecorder(w)
+			next.ServeHTTP(rec, r)
+
+			line := fmt.Sprintf("%s %s %d %d %v", r.Method, r.URL.RequestURI(),
+				rec.Status, rec.Bytes, time.Since(start).Round(time.Microsecond))
+			if id := RequestIDFrom(r.Context()); id != "" {
+				line += " id=" + id
+			}
+			logger.Print(line)
+		})
+	}
+}
+
+// Slog is Logging for log/slog: one "request" record per request, at
+// level Error for 5xx responses and Info otherwise, with the method, path,
+// status, bytes and duration as attributes. The logger's handler is wrapped
+// with ContextHandler, so after RequestID the record has a request_id.
+func Slog(logger *slog.Logger) Middleware {
+	if _, ok := logger.Handler().(*contextHandler); !ok {
+		logger = slog.New(ContextHandler(logger.Handler()))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := NewRecorder(w)
+			next.ServeHTTP(rec, r)
+
+			level := slog.LevelInfo
+			if rec.Status >= 500 {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(r.Context(), level, "request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.RequestURI()),
+				slog.Int("status", rec.Status),
+				slog.Int64("bytes", rec.Bytes),
+				slog.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
+
+// ContextHandler wraps h so that every record logged with a context that
+// carries a request ID gets a request_id attribute:
+//
+//	logger := slog.New(middleware.ContextHandler(slog.NewJSONHandler(os.Stderr, nil)))
+//	logger.InfoContext(r.Context(), "user created", "id", user.ID)
+//	// {"time":...,"level":"INFO","msg":"user created","id":4,"request_id":"9f2c..."}
+//
+// Handlers then only need to pass the context along, not the ID. Records
+// logged without a context (logger.Info) can't be tagged.
+func ContextHandler(h slog.Handler) slog.Handler {
+	return &contextHandler{h}
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFrom(ctx); id != "" {
+		r = r.Clone() // r may share its attributes with other handlers
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs and WithGroup keep the wrapper around the new handler;
+// embedding alone would return the bare one.
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
+
+// ============ CORS ============
+
+// CORSOptions configures CORS. The zero value allows no origins.
+type CORSOptions struct {
+	// AllowedOrigins are the origins allowed to call, e.g.
+	// "https://example.com". "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods default to GET, POST, PUT, PATCH and DELETE.
+	AllowedMethods []string
+	// AllowedHeaders default to Content-Type and Authorization.
+	AllowedHeaders []string
+	// MaxAge is how long browsers may cache a preflight answer.
+	MaxAge time.Duration
+}
+
+// CORS lets browsers call the handler from the allowed origins. It answers
+// preflight requests (OPTIONS with Access-Control-Request-Method) itself:
+// 204 for an allowed origin, 403 otherwise. Other requests always reach
+// next; from an origin that isn't allowed they get no CORS headers, so the
+// browser hides the response from the calling page.
+func CORS(opts CORSOptions) Middleware {
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	headers := opts.AllowedHeaders
+	if len(headers) == 0 {
+		headers = []string{"Content-Type", "Authorization"}
+	}
+	anyOrigin := slices.Contains(opts.AllowedOrigins, "*")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			// The answer depends on Origin, so caches must not share it
+			w.Header().Add("Vary", "Origin")
+
+			allowed := origin != "" && (anyOrigin || slices.Contains(opts.AllowedOrigins, origin))
+			if allowed {
+				if anyOrigin {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+			}
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+			if opts.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// ============ CLIENTS ============
+
+// PropagateRequestID returns a RoundTripper that sends the request ID in
+// each outgoing request's context on as an X-Request-Id header, then calls
+// base (http.DefaultTransport if nil). Give it to the http.Client a handler
+// uses to call other services, and build those requests with the incoming
+// request's context:
+//
+//	client := &http.Client{Transport: middleware.PropagateRequestID(nil)}
+//	req, _ := http.NewRequestWithContext(r.Context(), "GET", inventoryURL, nil)
+//	resp, err := client.Do(req)
+//
+// A service that also uses RequestID keeps the ID, so one ID shows up in
+// the logs of every service the request went through.
+func PropagateRequestID(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		id := RequestIDFrom(req.Context())
+		if id == "" || req.Header.Get(RequestIDHeader) != "" {
+			return base.RoundTrip(req)
+		}
+		// A RoundTripper must not modify the request it was given
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, id)
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// ============ RECOVERY ============
+
+// Recover turns a panic in the handler into a 500 response and logs the
+// panic value, the request ID if there is one, and the stack trace.
+// Without it net/http recovers too, but by dropping the connection: the
+// client gets no response at all. If the handler had already started the
+// response, the status can't change and the panic is only logged.
+func Recover(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := NewRecorder(w)
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v) // net/http's way to abort a response on purpose
+				}
+				id := ""
+				if rid := RequestIDFrom(r.Context()); rid != "" {
+					id = " id=" + rid
+				}
+				logger.Printf("panic serving %s %s: %v%s\n%s", r.Method, r.URL.Path, v, id, debug.Stack())
+				if !rec.WroteHeader() {
+					http.Error(rec, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
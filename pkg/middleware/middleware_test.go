@@ -0,0 +1,466 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// record is a middleware that notes its name in log before calling next.
+func record(log *[]string, name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*log = append(*log, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		name        string
+		middlewares []string
+	}{
+		{"none", nil},
+		{"one", []string{"a"}},
+		{"in the order given", []string{"a", "b", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			var mws []Middleware
+			for _, name := range tt.middlewares {
+				mws = append(mws, record(&log, name))
+			}
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				log = append(log, "handler")
+			})
+
+			Chain(handler, mws...).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			if want := append(slices.Clone(tt.middlewares), "handler"); !slices.Equal(log, want) {
+				t.Errorf("ran %v, want %v", log, want)
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter)
+		status  int
+		bytes   int64
+	}{
+		{"nothing written", func(w http.ResponseWriter) {}, 200, 0},
+		{"implicit 200", func(w http.ResponseWriter) { w.Write([]byte("hello")) }, 200, 5},
+		{"explicit status", func(w http.ResponseWriter) { w.WriteHeader(201); w.Write([]byte("{}")) }, 201, 2},
+		{"first status wins", func(w http.ResponseWriter) { w.WriteHeader(404); w.WriteHeader(500) }, 404, 0},
+		{"status after body is ignored", func(w http.ResponseWriter) { w.Write([]byte("x")); w.WriteHeader(500) }, 200, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rec := NewRecorder(w)
+			tt.handler(rec)
+			if rec.Status != tt.status || rec.Bytes != tt.bytes {
+				t.Errorf("recorded %d, %d bytes; want %d, %d bytes", rec.Status, rec.Bytes, tt.status, tt.bytes)
+			}
+			if w.Code != tt.status {
+				t.Errorf("real status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestRecorderUnwrap(t *testing.T) {
+	w := httptest.NewRecorder()
+	// Recorder has no Flush method: ResponseController finds the real one
+	if err := http.NewResponseController(NewRecorder(w)).Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Flushed {
+		t.Error("Flush did not reach the underlying ResponseWriter")
+	}
+}
+
+var hexID = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		client string // X-Request-Id sent by the client
+		keep   bool
+	}{
+		{"generated", "", false},
+		{"client's kept", "checkout-42_a.b", true},
+		{"newline replaced", "abc\nFAKE LOG LINE", false},
+		{"space replaced", "a b", false},
+		{"too long replaced", strings.Repeat("a", 65), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestIDFrom(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.client != "" {
+				req.Header.Set(RequestIDHeader, tt.client)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if tt.keep && seen != tt.client {
+				t.Errorf("ID = %q, want the client's %q", seen, tt.client)
+			}
+			if !tt.keep && !hexID.MatchString(seen) {
+				t.Errorf("ID = %q, want a generated one", seen)
+			}
+			if got := w.Header().Get(RequestIDHeader); got != seen {
+				t.Errorf("response header = %q, context = %q", got, seen)
+			}
+		})
+	}
+
+	if id := RequestIDFrom(httptest.NewRequest("GET", "/", nil).Context()); id != "" {
+		t.Errorf("RequestIDFrom without the middleware = %q, want \"\"", id)
+	}
+}
+
+func TestRequestIDsDiffer(t *testing.T) {
+	ids := map[string]bool{}
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids[RequestIDFrom(r.Context())] = true
+	}))
+	for range 100 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}
+	if len(ids) != 100 {
+		t.Errorf("100 requests got %d distinct IDs", len(ids))
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if buf.Len() != 0 {
+			t.Error("logged before the handler finished")
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	}), RequestID(), Logging(logger))
+
+	req := httptest.NewRequest("POST", "/items?sort=name", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	if !strings.HasPrefix(line, "POST /items?sort=name 201 5 ") || !strings.HasSuffix(line, " id=req-1\n") {
+		t.Errorf("logged %q", line)
+	}
+
+	// Without RequestID there is no id= field
+	buf.Reset()
+	Logging(logger)(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	if line := buf.String(); !strings.HasPrefix(line, "GET /missing 404 19 ") || strings.Contains(line, "id=") {
+		t.Errorf("logged %q", line)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("reached"))
+	})
+	only := CORS(CORSOptions{AllowedOrigins: []string{"https://app.example"}, MaxAge: 10 * time.Minute})(next)
+	anyone := CORS(CORSOptions{AllowedOrigins: []string{"*"}})(next)
+
+	tests := []struct {
+		name      string
+		handler   http.Handler
+		method    string
+		origin    string
+		preflight bool
+		status    int
+		allow     string // Access-Control-Allow-Origin
+		reached   bool
+	}{
+		{"allowed origin", only, "GET", "https://app.example", false, 200, "https://app.example", true},
+		{"other origin", only, "GET", "https://evil.example", false, 200, "", true},
+		{"same-origin request", only, "GET", "", false, 200, "", true},
+		{"any origin", anyone, "POST", "https://whoever.example", false, 200, "*", true},
+		{"preflight allowed", only, "OPTIONS", "https://app.example", true, 204, "https://app.example", false},
+		{"preflight refused", only, "OPTIONS", "https://evil.example", true, 403, "", false},
+		{"plain OPTIONS reaches handler", only, "OPTIONS", "https://app.example", false, 200, "https://app.example", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "PUT")
+			}
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allow {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.allow)
+			}
+			if reached := w.Body.String() == "reached"; reached != tt.reached {
+				t.Errorf("handler reached = %v, want %v", reached, tt.reached)
+			}
+			if !slices.Contains(w.Header().Values("Vary"), "Origin") {
+				t.Error("no Vary: Origin")
+			}
+		})
+	}
+
+	// An allowed preflight lists what may be sent
+	req := httptest.NewRequest("OPTIONS", "/api", nil)
+	req.Header.Set("Origin", "https://app.example")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	w := httptest.NewRecorder()
+	only.ServeHTTP(w, req)
+	for header, want := range map[string]string{
+		"Access-Control-Allow-Methods": "GET, POST, PUT, PATCH, DELETE",
+		"Access-Control-Allow-Headers": "Content-Type, Authorization",
+		"Access-Control-Max-Age":       "600",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  int
+		logged  bool
+	}{
+		{"no panic", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) }, http.StatusTeapot, false},
+		{"panic becomes 500", func(w http.ResponseWriter, r *http.Request) { panic("boom") }, 500, true},
+		// Too late to change the status, but the panic is still logged
+		{"panic after writing", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("partial")); panic("boom") }, 200, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := httptest.NewRecorder()
+			Recover(log.New(&buf, "", 0))(tt.handler).ServeHTTP(w, httptest.NewRequest("GET", "/crash", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			logged := buf.String()
+			if !tt.logged {
+				if logged != "" {
+					t.Errorf("logged %q", logged)
+				}
+				return
+			}
+			// The value, the request and the stack trace
+			for _, want := range []string{"panic serving GET /crash: boom", "goroutine ", "middleware_test.go"} {
+				if !strings.Contains(logged, want) {
+					t.Errorf("log has no %q:\n%s", want, logged)
+				}
+			}
+		})
+	}
+}
+
+func TestRecoverLogsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { panic("boom") }),
+		RequestID(), Recover(log.New(&buf, "", 0)))
+	req := httptest.NewRequest("GET", "/crash", nil)
+	req.Header.Set(RequestIDHeader, "req-3")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if !strings.HasPrefix(buf.String(), "panic serving GET /crash: boom id=req-3\n") {
+		t.Errorf("logged %q", buf.String())
+	}
+	// The client gets the ID with the 500, to quote when reporting it
+	if w.Code != 500 || w.Header().Get(RequestIDHeader) != "req-3" {
+		t.Errorf("got %d with ID %q", w.Code, w.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestRecoverLetsAbortHandlerThrough(t *testing.T) {
+	handler := Recover(log.New(&bytes.Buffer{}, "", 0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler to be re-panicked", v)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+// jsonLogger logs JSON lines without the time into buf.
+func jsonLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// records decodes the JSON lines in buf.
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var list []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		list = append(list, rec)
+	}
+	return list
+}
+
+func TestSlog(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		level   string
+		handler bool // the handler logs a record of its own
+	}{
+		{"ok", 200, "INFO", true},
+		{"client error", 404, "INFO", false},
+		{"server error", 503, "ERROR", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := jsonLogger(&buf)
+			handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.handler {
+					// Only the context is passed: the ID comes from it
+					logger.InfoContext(r.Context(), "working")
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte("body"))
+			}), RequestID(), Slog(logger))
+
+			req := httptest.NewRequest("GET", "/items?page=2", nil)
+			req.Header.Set(RequestIDHeader, "req-1")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			recs := records(t, &buf)
+			if tt.handler {
+				if len(recs) != 2 || recs[0]["msg"] != "working" {
+					t.Fatalf("logged %v", recs)
+				}
+				// The handler's logger wasn't wrapped: Slog wrapped its own copy
+				if _, ok := recs[0]["request_id"]; ok {
+					t.Errorf("handler record has a request_id without ContextHandler: %v", recs[0])
+				}
+				recs = recs[1:]
+			}
+			if len(recs) != 1 {
+				t.Fatalf("logged %v", recs)
+			}
+			rec := recs[0]
+			want := map[string]any{"msg": "request", "level": tt.level, "method": "GET", "path": "/items?page=2",
+				"status": float64(tt.status), "bytes": 4.0, "request_id": "req-1"}
+			for k, v := range want {
+				if rec[k] != v {
+					t.Errorf("%s = %v, want %v", k, rec[k], v)
+				}
+			}
+			if _, ok := rec["duration"]; !ok {
+				t.Error("no duration")
+			}
+		})
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(ContextHandler(jsonLogger(&buf).Handler()))
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-9")
+
+	logger.InfoContext(ctx, "tagged")
+	logger.Info("no context")
+	// WithAttrs and WithGroup must not lose the wrapper
+	logger.With("user", 4).InfoContext(ctx, "with attrs")
+	logger.WithGroup("db").InfoContext(ctx, "in group", "rows", 2)
+
+	recs := records(t, &buf)
+	if len(recs) != 4 {
+		t.Fatalf("logged %v", recs)
+	}
+	if recs[0]["request_id"] != "req-9" {
+		t.Errorf("tagged: %v", recs[0])
+	}
+	if _, ok := recs[1]["request_id"]; ok {
+		t.Errorf("logged without a context, but has a request_id: %v", recs[1])
+	}
+	if recs[2]["request_id"] != "req-9" || recs[2]["user"] != 4.0 {
+		t.Errorf("with attrs: %v", recs[2])
+	}
+	if group, _ := recs[3]["db"].(map[string]any); group["request_id"] != "req-9" {
+		t.Errorf("in group: %v", recs[3])
+	}
+}
+
+func TestPropagateRequestID(t *testing.T) {
+	// downstream is another service: it reports the ID it was called with
+	downstream := httptest.NewServer(RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(RequestIDFrom(r.Context())))
+	})))
+	defer downstream.Close()
+	client := &http.Client{Transport: PropagateRequestID(nil)}
+
+	call := func(ctx context.Context, header string) string {
+		req, _ := http.NewRequestWithContext(ctx, "GET", downstream.URL, nil)
+		if header != "" {
+			req.Header.Set(RequestIDHeader, header)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if req.Header.Get(RequestIDHeader) != header {
+			t.Error("the caller's request was modified")
+		}
+		return string(body)
+	}
+
+	withID := context.WithValue(context.Background(), requestIDKey{}, "req-5")
+	if got := call(withID, ""); got != "req-5" {
+		t.Errorf("downstream saw %q, want req-5", got)
+	}
+	if got := call(withID, "explicit"); got != "explicit" {
+		t.Errorf("downstream saw %q, want the header set on the request", got)
+	}
+	if got := call(context.Background(), ""); !hexID.MatchString(got) {
+		t.Errorf("downstream saw %q, want an ID of its own", got)
+	}
+}
@@ -0,0 +1,51 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/owolabijunior12/learning-golang/pkg/pipeline"
+)
+
+func Example() {
+	p := pipeline.New(context.Background())
+	words := pipeline.From(p, pipeline.Slice("fan", "out", "fan", "in"))
+	loud := pipeline.Parallel(p, words, 2, func(ctx context.Context, s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+	got, err := pipeline.Collect(p, loud)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	slices.Sort(got) // Parallel delivers in the order the stages finish
+	fmt.Println(got)
+	// Output: [FAN FAN IN OUT]
+}
+
+func ExampleTo() {
+	p := pipeline.New(context.Background())
+	lines := pipeline.From(p, func(ctx context.Context, emit func(string) bool) error {
+		for _, l := range []string{"ok", "ok", "corrupt", "ok"} {
+			if !emit(l) {
+				return ctx.Err() // the sink failed: stop reading
+			}
+		}
+		return nil
+	})
+	pipeline.To(p, lines, func(ctx context.Context, l string) error {
+		if l == "corrupt" {
+			return errors.New("corrupt line")
+		}
+		fmt.Println("saved", l)
+		return nil
+	})
+	fmt.Println(p.Wait())
+	// Output:
+	// saved ok
+	// saved ok
+	// corrupt line
+}
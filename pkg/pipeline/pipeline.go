@@ -0,0 +1,210 @@
+// Package pipeline connects goroutines with channels: the pipelines of
+// course 4, with cancellation and errors handled once, here, instead of in
+// every stage.
+//
+// A Source emits values, each Stage turns a value into another, and a Sink
+// consumes them. Stages run in goroutines of their own, joined by channels:
+//
+//	p := pipeline.New(ctx)
+//	urls := pipeline.From(p, pipeline.Slice(list...))
+//	pages := pipeline.Parallel(p, urls, 8, fetch) // fan out to 8 fetchers, fan back in
+//	pipeline.To(p, pages, save)
+//	err := p.Wait()
+//
+// The first stage to fail cancels the pipeline: every goroutine in it sees
+// its context done, stops sending, and returns, and Wait returns that first
+// error. No goroutine is left blocked on a channel nobody reads, which is
+// the leak hand-written pipelines are prone to.
+//
+// Every channel a function here returns must be passed to another, or read
+// until it is closed.
+//
+// It lives in its own module, like pkg/pool, so other projects can import
+// it.
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Source emits values by calling emit, which returns false once the
+// pipeline is cancelled; the source should then return.
+type Source[T any] func(ctx context.Context, emit func(T) bool) error
+
+// Stage turns one value into another. An error stops the pipeline.
+type Stage[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Sink consumes values at the end of a pipeline. An error stops the
+// pipeline.
+type Sink[T any] func(ctx context.Context, v T) error
+
+// Pipeline is a set of goroutines that stop together.
+type Pipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	once sync.Once
+	err  error
+}
+
+// New starts an empty pipeline. It is cancelled when ctx is done, when a
+// goroutine in it fails, or by Wait.
+func New(ctx context.Context) *Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{ctx: ctx, cancel: cancel}
+}
+
+// Context is done once the pipeline is cancelled.
+func (p *Pipeline) Context() context.Context { return p.ctx }
+
+// Wait waits for every goroutine in the pipeline to return, and returns the
+// first error one of them returned.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	return p.err
+}
+
+// run runs fn in a goroutine of the pipeline; its error cancels the rest.
+func (p *Pipeline) run(fn func(ctx context.Context) error) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if err := fn(p.ctx); err != nil {
+			p.once.Do(func() {
+				p.err = err
+				p.cancel()
+			})
+		}
+	}()
+}
+
+// send sends v on out unless ctx is done first.
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// From starts src, and returns the channel of what it emits.
+func From[T any](p *Pipeline, src Source[T]) <-chan T {
+	out := make(chan T)
+	p.run(func(ctx context.Context) error {
+		defer close(out)
+		return src(ctx, func(v T) bool { return send(ctx, out, v) })
+	})
+	return out
+}
+
+// Slice is a source that emits items in order.
+func Slice[T any](items ...T) Source[T] {
+	return func(ctx context.Context, emit func(T) bool) error {
+		for _, v := range items {
+			if !emit(v) {
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+}
+
+// Then runs stage on each value from in, in order, in one goroutine.
+func Then[In, Out any](p *Pipeline, in <-chan In, stage Stage[In, Out]) <-chan Out {
+	out := make(chan Out)
+	p.run(func(ctx context.Context) error {
+		defer close(out)
+		for v := range in {
+			r, err := stage(ctx, v)
+			if err != nil {
+				return err
+			}
+			if !send(ctx, out, r) {
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// FanOut hands the values from in to n channels: each value goes to
+// whichever is ready first, so a slow reader gets fewer.
+func FanOut[T any](p *Pipeline, in <-chan T, n int) []<-chan T {
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		p.run(func(ctx context.Context) error {
+			defer close(out)
+			for v := range in {
+				if !send(ctx, out, v) {
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	return outs
+}
+
+// FanIn merges ins into one channel, which is closed once all of them are.
+func FanIn[T any](p *Pipeline, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, in := range ins {
+		wg.Add(1)
+		p.run(func(ctx context.Context) error {
+			defer wg.Done()
+			for v := range in {
+				if !send(ctx, out, v) {
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	// Only once every sender is done is it safe to close
+	p.run(func(context.Context) error {
+		wg.Wait()
+		close(out)
+		return nil
+	})
+	return out
+}
+
+// Parallel runs stage on the values from in in n goroutines: FanOut, Then
+// on each branch, and FanIn. The results come in the order they finish.
+func Parallel[In, Out any](p *Pipeline, in <-chan In, n int, stage Stage[In, Out]) <-chan Out {
+	branches := make([]<-chan Out, n)
+	for i, branch := range FanOut(p, in, n) {
+		branches[i] = Then(p, branch, stage)
+	}
+	return FanIn(p, branches...)
+}
+
+// To runs sink on each value from in.
+func To[T any](p *Pipeline, in <-chan T, sink Sink[T]) {
+	p.run(func(ctx context.Context) error {
+		for v := range in {
+			if err := sink(ctx, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Collect reads every value from in, waits for the pipeline, and returns
+// the values with Wait's error.
+func Collect[T any](p *Pipeline, in <-chan T) ([]T, error) {
+	var all []T
+	for v := range in {
+		all = append(all, v)
+	}
+	return all, p.Wait()
+}
@@ -0,0 +1,171 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func square(ctx context.Context, n int) (int, error) { return n * n, nil }
+
+func TestThen(t *testing.T) {
+	p := New(context.Background())
+	strs := Then(p, Then(p, From(p, Slice(1, 2, 3)), square), func(ctx context.Context, n int) (string, error) {
+		return strconv.Itoa(n), nil
+	})
+	got, err := Collect(p, strs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1", "4", "9"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q: Then keeps the order", got, want)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	const workers = 4
+	var running, most atomic.Int32
+	p := New(context.Background())
+	out := Parallel(p, From(p, Slice(1, 2, 3, 4, 5, 6, 7, 8)), workers, func(ctx context.Context, n int) (int, error) {
+		now := running.Add(1)
+		defer running.Add(-1)
+		for m := most.Load(); now > m && !most.CompareAndSwap(m, now); m = most.Load() {
+		}
+		time.Sleep(10 * time.Millisecond)
+		return n * n, nil
+	})
+	got, err := Collect(p, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(got)
+	if want := []int{1, 4, 9, 16, 25, 36, 49, 64}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if m := most.Load(); m < 2 || m > workers {
+		t.Errorf("at most %d ran at once, want 2 to %d", m, workers)
+	}
+}
+
+func TestFanOutFanIn(t *testing.T) {
+	p := New(context.Background())
+	branches := FanOut(p, From(p, Slice(1, 2, 3, 4, 5, 6)), 3)
+	if len(branches) != 3 {
+		t.Fatalf("%d branches, want 3", len(branches))
+	}
+	got, err := Collect(p, FanIn(p, branches...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(got)
+	if want := []int{1, 2, 3, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want every value exactly once: %v", got, want)
+	}
+}
+
+func TestTo(t *testing.T) {
+	p := New(context.Background())
+	var sum int
+	To(p, Then(p, From(p, Slice(1, 2, 3)), square), func(ctx context.Context, n int) error {
+		sum += n
+		return nil
+	})
+	if err := p.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if sum != 14 {
+		t.Errorf("sum = %d, want 14", sum)
+	}
+}
+
+// endless emits 0, 1, 2, ... until the pipeline stops it.
+func endless(ctx context.Context, emit func(int) bool) error {
+	for i := 0; ; i++ {
+		if !emit(i) {
+			return ctx.Err()
+		}
+	}
+}
+
+func TestErrorStopsPipeline(t *testing.T) {
+	errBad := errors.New("bad value")
+	before := runtime.NumGoroutine()
+
+	tests := []struct {
+		name  string
+		build func(p *Pipeline) <-chan int
+	}{
+		{"stage", func(p *Pipeline) <-chan int {
+			return Then(p, From(p, endless), func(ctx context.Context, n int) (int, error) {
+				if n == 5 {
+					return 0, errBad
+				}
+				return n, nil
+			})
+		}},
+		{"parallel stage", func(p *Pipeline) <-chan int {
+			return Parallel(p, From(p, endless), 3, func(ctx context.Context, n int) (int, error) {
+				if n == 5 {
+					return 0, errBad
+				}
+				return n, nil
+			})
+		}},
+		{"source", func(p *Pipeline) <-chan int {
+			return From(p, func(ctx context.Context, emit func(int) bool) error {
+				emit(1)
+				return errBad
+			})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(context.Background())
+			if _, err := Collect(p, tt.build(p)); !errors.Is(err, errBad) {
+				t.Errorf("err = %v, want %v", err, errBad)
+			}
+		})
+	}
+
+	t.Run("sink", func(t *testing.T) {
+		p := New(context.Background())
+		To(p, From(p, endless), func(ctx context.Context, n int) error {
+			if n == 5 {
+				return errBad
+			}
+			return nil
+		})
+		if err := p.Wait(); !errors.Is(err, errBad) {
+			t.Errorf("err = %v, want %v", err, errBad)
+		}
+	})
+
+	// Wait has returned, so every goroutine of every pipeline is done, or
+	// about to exit: give them a moment
+	after := runtime.NumGoroutine()
+	for deadline := time.Now().Add(time.Second); after > before && time.Now().Before(deadline); after = runtime.NumGoroutine() {
+		time.Sleep(time.Millisecond)
+	}
+	if after > before {
+		t.Errorf("%d goroutines before, %d after: a pipeline leaked", before, after)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := New(ctx)
+	out := Parallel(p, From(p, endless), 2, square)
+	<-out
+	cancel()
+	if _, err := Collect(p, out); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if p.Context().Err() == nil {
+		t.Error("the pipeline's context is not done after Wait")
+	}
+}
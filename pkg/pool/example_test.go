@@ -0,0 +1,85 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/pkg/pool"
+)
+
+func Example() {
+	shout := func(ctx context.Context, s string) (string, error) {
+		return strings.ToUpper(s), nil
+	}
+	p := pool.New(context.Background(), shout, pool.Options{Workers: 2})
+
+	// Submit from one goroutine, read results in another: Submit waits
+	// while the queue is full, and the queue only drains if someone reads
+	go func() {
+		defer p.Close()
+		for _, s := range []string{"a", "b", "c"} {
+			p.Submit(context.Background(), s)
+		}
+	}()
+
+	n := 0
+	for r := range p.Results() { // in the order the jobs finish
+		if r.Err == nil {
+			n++
+		}
+	}
+	fmt.Println(n, "jobs done")
+	// Output: 3 jobs done
+}
+
+func ExampleMap() {
+	results := pool.Map(context.Background(), []int{1, 2, 3, 4}, func(ctx context.Context, n int) (int, error) {
+		if n == 3 {
+			panic("three")
+		}
+		return n * n, nil
+	}, pool.Options{Workers: 2})
+
+	for _, r := range results { // in the order of the inputs
+		if errors.Is(r.Err, pool.ErrPanic) {
+			fmt.Println(r.In, "failed:", r.Err)
+			continue
+		}
+		fmt.Println(r.In, r.Out)
+	}
+	// Output:
+	// 1 1
+	// 2 4
+	// 3 failed: pool: job panicked: three
+	// 4 16
+}
+
+func ExampleOptions() {
+	slow := func(ctx context.Context, d time.Duration) (string, error) {
+		select {
+		case <-time.After(d):
+			return "done", nil
+		case <-ctx.Done(): // a job must watch its context for JobTimeout to work
+			return "", ctx.Err()
+		}
+	}
+	results := pool.Map(context.Background(), []time.Duration{time.Millisecond, time.Hour}, slow, pool.Options{
+		Workers:    2,
+		QueueSize:  10,
+		JobTimeout: 50 * time.Millisecond,
+		Hooks: pool.Hooks{
+			Finished: func(took time.Duration, err error) {
+				// e.g. jobDuration.Observe(took.Seconds())
+			},
+		},
+	})
+	for _, r := range results {
+		fmt.Println(r.In, r.Out, r.Err)
+	}
+	// Output:
+	// 1ms done <nil>
+	// 1h0m0s  context deadline exceeded
+}
@@ -0,0 +1,276 @@
+// Package pool runs jobs on a fixed number of worker goroutines: the worker
+// pool of course 4, with what a real program needs around it.
+//
+//   - Pool[In, Out] is typed: jobs are In, results carry an Out
+//   - the queue is bounded, so Submit blocks (or TrySubmit fails) when the
+//     workers fall behind, instead of memory growing without limit
+//   - cancelling the pool's context stops it; a job can also have a timeout
+//   - a job that panics fails alone, and the worker goes on to the next one
+//   - Hooks report queueing and job durations to your metrics
+//
+// Every submitted job gets exactly one Result, even if it never ran, so a
+// consumer can count results instead of guessing when to stop:
+//
+//	p := pool.New(ctx, fetch, pool.Options{Workers: 8, JobTimeout: 5 * time.Second})
+//	go func() {
+//		defer p.Close()
+//		for _, url := range urls {
+//			if err := p.Submit(ctx, url); err != nil {
+//				return
+//			}
+//		}
+//	}()
+//	for r := range p.Results() {
+//		fmt.Println(r.In, r.Out, r.Err)
+//	}
+//
+// It lives in its own module, like pkg/middleware, so other projects can
+// import it.
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Func does one job. It should return soon after ctx is done: the pool
+// cannot stop a job that ignores its context.
+type Func[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Result is the outcome of one job.
+type Result[In, Out any] struct {
+	In  In
+	Out Out
+	Err error // the job's error, a *PanicError, or the context's error if it never ran
+}
+
+// Options configure a Pool. The zero value is usable.
+type Options struct {
+	Workers    int           // goroutines running jobs; 0 means runtime.GOMAXPROCS(0)
+	QueueSize  int           // jobs that may wait for a worker; 0 means as many as Workers
+	JobTimeout time.Duration // the longest one job may take; 0 means no limit
+	Hooks      Hooks
+}
+
+// Hooks let the pool report to metrics. Any of them may be nil. Workers
+// call them concurrently, so they must be safe for concurrent use.
+type Hooks struct {
+	Queued   func(depth int)                     // a job was queued; depth jobs are waiting now
+	Started  func(waited time.Duration)          // a worker took a job that had waited this long
+	Finished func(took time.Duration, err error) // a job returned, panicked or timed out
+}
+
+// ErrClosed is returned by Submit after Close.
+var ErrClosed = errors.New("pool: closed")
+
+// ErrFull is returned by TrySubmit when the queue has no room.
+var ErrFull = errors.New("pool: queue full")
+
+// ErrPanic is wrapped by the error of every job that panicked.
+var ErrPanic = errors.New("pool: job panicked")
+
+// PanicError is the error of a job that panicked. The panic is contained:
+// the worker recovers and takes the next job.
+type PanicError struct {
+	Value any    // what the job panicked with
+	Stack []byte // the job's goroutine at the time of the panic
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("%v: %v", ErrPanic, e.Value) }
+func (e *PanicError) Unwrap() error { return ErrPanic }
+
+// job is an In waiting in the queue.
+type job[In any] struct {
+	in     In
+	queued time.Time
+}
+
+// Pool runs jobs submitted to it on a fixed set of workers.
+type Pool[In, Out any] struct {
+	ctx     context.Context
+	fn      Func[In, Out]
+	opts    Options
+	jobs    chan job[In]
+	results chan Result[In, Out]
+
+	mu     sync.RWMutex // held for reading while sending on jobs, for writing to close it
+	closed bool
+}
+
+// New starts a pool running fn. It stops when ctx is done: jobs still
+// queued then get ctx's error as their result without running. Call Close
+// once every job is submitted, and read Results until it is closed.
+func New[In, Out any](ctx context.Context, fn Func[In, Out], opts Options) *Pool[In, Out] {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = opts.Workers
+	}
+	p := &Pool[In, Out]{
+		ctx:     ctx,
+		fn:      fn,
+		opts:    opts,
+		jobs:    make(chan job[In], opts.QueueSize),
+		results: make(chan Result[In, Out], opts.Workers),
+	}
+
+	var wg sync.WaitGroup
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(p.results)
+	}()
+	return p
+}
+
+// Submit queues in, waiting while the queue is full. It fails with ctx's
+// error, or the pool's once the pool is stopped, and with ErrClosed after
+// Close.
+func (p *Pool[In, Out]) Submit(ctx context.Context, in In) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+	// select picks at random among ready cases: check first, so a stopped
+	// pool takes no more jobs even when the queue has room
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case p.jobs <- job[In]{in, time.Now()}:
+		p.queued()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+}
+
+// TrySubmit queues in if there is room, and returns ErrFull if not. It
+// never blocks, so a goroutine that also reads Results can use it without
+// deadlocking.
+func (p *Pool[In, Out]) TrySubmit(in In) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrClosed
+	}
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case p.jobs <- job[In]{in, time.Now()}:
+		p.queued()
+		return nil
+	default:
+		return ErrFull
+	}
+}
+
+func (p *Pool[In, Out]) queued() {
+	if p.opts.Hooks.Queued != nil {
+		p.opts.Hooks.Queued(len(p.jobs))
+	}
+}
+
+// Close says no more jobs are coming. The workers finish those queued, and
+// then Results is closed. It waits for Submits in progress, and is safe to
+// call more than once.
+func (p *Pool[In, Out]) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+}
+
+// Results delivers one Result per job, in the order the jobs finish. It
+// is closed once the pool is closed and every job is done. Keep reading
+// it: the workers wait while nobody does.
+func (p *Pool[In, Out]) Results() <-chan Result[In, Out] {
+	return p.results
+}
+
+// work runs jobs until the queue is closed and empty.
+func (p *Pool[In, Out]) work() {
+	for j := range p.jobs {
+		// After cancellation the queue is drained without running anything,
+		// so each job still gets its result
+		if err := p.ctx.Err(); err != nil {
+			p.results <- Result[In, Out]{In: j.in, Err: err}
+			continue
+		}
+		if p.opts.Hooks.Started != nil {
+			p.opts.Hooks.Started(time.Since(j.queued))
+		}
+		start := time.Now()
+		out, err := p.run(j.in)
+		if p.opts.Hooks.Finished != nil {
+			p.opts.Hooks.Finished(time.Since(start), err)
+		}
+		p.results <- Result[In, Out]{In: j.in, Out: out, Err: err}
+	}
+}
+
+// run does one job, with its timeout, turning a panic into a *PanicError.
+func (p *Pool[In, Out]) run(in In) (out Out, err error) {
+	ctx := p.ctx
+	if p.opts.JobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.opts.JobTimeout)
+		defer cancel()
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			var zero Out
+			out, err = zero, &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return p.fn(ctx, in)
+}
+
+// Map runs fn on every input and returns the results in the order of
+// inputs. It is the pool for a batch known in advance.
+func Map[In, Out any](ctx context.Context, inputs []In, fn Func[In, Out], opts Options) []Result[In, Out] {
+	// Submit indexes, so each result finds its place
+	p := New(ctx, func(ctx context.Context, i int) (Out, error) {
+		return fn(ctx, inputs[i])
+	}, opts)
+	go func() {
+		defer p.Close()
+		for i := range inputs {
+			if p.Submit(ctx, i) != nil {
+				return
+			}
+		}
+	}()
+
+	results := make([]Result[In, Out], len(inputs))
+	done := make([]bool, len(inputs))
+	for r := range p.Results() {
+		results[r.In] = Result[In, Out]{In: inputs[r.In], Out: r.Out, Err: r.Err}
+		done[r.In] = true
+	}
+	// Inputs never submitted, because ctx was done, get its error
+	for i := range results {
+		if !done[i] {
+			results[i] = Result[In, Out]{In: inputs[i], Err: ctx.Err()}
+		}
+	}
+	return results
+}
@@ -0,0 +1,317 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func double(ctx context.Context, n int) (int, error) { return 2 * n, nil }
+
+// collect submits inputs from another goroutine, closes the pool and
+// returns every result it delivers.
+func collect[In, Out any](t *testing.T, p *Pool[In, Out], inputs ...In) []Result[In, Out] {
+	t.Helper()
+	go func() {
+		defer p.Close()
+		for _, in := range inputs {
+			if err := p.Submit(context.Background(), in); err != nil {
+				t.Errorf("Submit(%v): %v", in, err)
+				return
+			}
+		}
+	}()
+	var results []Result[In, Out]
+	for r := range p.Results() {
+		results = append(results, r)
+	}
+	return results
+}
+
+func TestPool(t *testing.T) {
+	p := New(context.Background(), double, Options{Workers: 3})
+	results := collect(t, p, 1, 2, 3, 4, 5, 6, 7)
+
+	var outs []int
+	for _, r := range results {
+		if r.Err != nil || r.Out != 2*r.In {
+			t.Errorf("result %+v", r)
+		}
+		outs = append(outs, r.Out)
+	}
+	slices.Sort(outs)
+	if want := []int{2, 4, 6, 8, 10, 12, 14}; !slices.Equal(outs, want) {
+		t.Errorf("outs = %v, want %v", outs, want)
+	}
+}
+
+func TestWorkersRunConcurrently(t *testing.T) {
+	const workers = 4
+	var running, most atomic.Int32
+	all := make(chan struct{})
+	var once sync.Once
+	p := New(context.Background(), func(ctx context.Context, n int) (int, error) {
+		now := running.Add(1)
+		for {
+			m := most.Load()
+			if now <= m || most.CompareAndSwap(m, now) {
+				break
+			}
+		}
+		if now == workers {
+			once.Do(func() { close(all) })
+		}
+		select {
+		case <-all:
+		case <-time.After(time.Second):
+		}
+		running.Add(-1)
+		return n, nil
+	}, Options{Workers: workers})
+	collect(t, p, 1, 2, 3, 4, 5, 6, 7, 8)
+
+	if most.Load() != workers {
+		t.Errorf("at most %d jobs ran at once, want %d", most.Load(), workers)
+	}
+}
+
+func TestBoundedQueue(t *testing.T) {
+	release := make(chan struct{})
+	p := New(context.Background(), func(ctx context.Context, n int) (int, error) {
+		<-release
+		return n, nil
+	}, Options{Workers: 1, QueueSize: 2})
+
+	// One job runs, two wait; there's no room for a fourth
+	var submitted int
+	deadline := time.Now().Add(time.Second)
+	for submitted < 3 && time.Now().Before(deadline) {
+		if err := p.TrySubmit(submitted); err == nil {
+			submitted++
+		}
+	}
+	if err := p.TrySubmit(99); !errors.Is(err, ErrFull) {
+		t.Errorf("TrySubmit on a full queue = %v, want ErrFull", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Submit(ctx, 99); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Submit on a full queue = %v, want it to wait until ctx is done", err)
+	}
+
+	close(release)
+	p.Close()
+	n := 0
+	for range p.Results() {
+		n++
+	}
+	if n != 3 {
+		t.Errorf("%d results, want 3", n)
+	}
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Close = %v, want ErrClosed", err)
+	}
+	p.Close() // a second Close does nothing
+}
+
+func TestPanicIsolation(t *testing.T) {
+	p := New(context.Background(), func(ctx context.Context, n int) (int, error) {
+		if n == 2 {
+			panic("two is right out")
+		}
+		return n, nil
+	}, Options{Workers: 1})
+	results := collect(t, p, 1, 2, 3)
+
+	if len(results) != 3 {
+		t.Fatalf("%d results, want 3: the worker should survive the panic", len(results))
+	}
+	for _, r := range results {
+		var pe *PanicError
+		switch {
+		case r.In != 2 && r.Err != nil:
+			t.Errorf("job %d: %v", r.In, r.Err)
+		case r.In == 2 && !errors.As(r.Err, &pe):
+			t.Errorf("job 2: err = %v, want a *PanicError", r.Err)
+		case r.In == 2:
+			if !errors.Is(r.Err, ErrPanic) || pe.Value != "two is right out" || !strings.Contains(string(pe.Stack), "pool_test.go") {
+				t.Errorf("job 2: %v, stack:\n%s", pe, pe.Stack)
+			}
+		}
+	}
+}
+
+func TestJobTimeout(t *testing.T) {
+	p := New(context.Background(), func(ctx context.Context, d time.Duration) (string, error) {
+		select {
+		case <-time.After(d):
+			return "done", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}, Options{Workers: 2, JobTimeout: 50 * time.Millisecond})
+	results := collect(t, p, time.Millisecond, time.Minute)
+
+	for _, r := range results {
+		switch r.In {
+		case time.Millisecond:
+			if r.Err != nil || r.Out != "done" {
+				t.Errorf("quick job: %+v", r)
+			}
+		case time.Minute:
+			if !errors.Is(r.Err, context.DeadlineExceeded) {
+				t.Errorf("slow job: err = %v, want a timeout", r.Err)
+			}
+		}
+	}
+}
+
+// TestJobTimeoutExact runs the pool in a synctest bubble, where time is
+// fake and moves only when every goroutine in the bubble is blocked. The
+// timeout then fires at exactly JobTimeout, and a job that would take a
+// minute costs the test nothing.
+func TestJobTimeoutExact(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var mu sync.Mutex
+		took := map[error]time.Duration{}
+		p := New(context.Background(), func(ctx context.Context, d time.Duration) (string, error) {
+			select {
+			case <-time.After(d):
+				return "done", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}, Options{Workers: 2, JobTimeout: 50 * time.Millisecond, Hooks: Hooks{
+			Finished: func(d time.Duration, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				took[err] = d
+			},
+		}})
+		start := time.Now()
+		results := collect(t, p, 49*time.Millisecond, time.Minute)
+
+		if len(results) != 2 {
+			t.Fatalf("%d results, want 2", len(results))
+		}
+		if got := took[nil]; got != 49*time.Millisecond {
+			t.Errorf("the quick job took %v, want 49ms", got)
+		}
+		if got := took[context.DeadlineExceeded]; got != 50*time.Millisecond {
+			t.Errorf("the slow job was stopped after %v, want 50ms", got)
+		}
+		if elapsed := time.Since(start); elapsed != 50*time.Millisecond {
+			t.Errorf("the pool took %v, want 50ms", elapsed)
+		}
+	})
+}
+
+func TestCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	p := New(ctx, func(ctx context.Context, n int) (int, error) {
+		if n == 0 {
+			close(started)
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, Options{Workers: 1, QueueSize: 5})
+	for i := range 4 {
+		if err := p.Submit(context.Background(), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-started
+	cancel()
+
+	if err := p.Submit(context.Background(), 9); !errors.Is(err, context.Canceled) {
+		t.Errorf("Submit after cancel = %v", err)
+	}
+	p.Close()
+	// The running job and the three queued all report the cancellation
+	n := 0
+	for r := range p.Results() {
+		n++
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("job %d: err = %v", r.In, r.Err)
+		}
+	}
+	if n != 4 {
+		t.Errorf("%d results, want 4", n)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var mu sync.Mutex
+	var queued, started, failed, finished int
+	p := New(context.Background(), func(ctx context.Context, n int) (int, error) {
+		if n%2 == 0 {
+			return 0, errors.New("even")
+		}
+		return n, nil
+	}, Options{Workers: 2, Hooks: Hooks{
+		Queued: func(depth int) {
+			mu.Lock()
+			defer mu.Unlock()
+			queued++
+		},
+		Started: func(waited time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			started++
+		},
+		Finished: func(took time.Duration, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			finished++
+			if err != nil {
+				failed++
+			}
+		},
+	}})
+	collect(t, p, 1, 2, 3, 4, 5)
+
+	if queued != 5 || started != 5 || finished != 5 || failed != 2 {
+		t.Errorf("queued %d, started %d, finished %d (%d failed); want 5, 5, 5 (2)", queued, started, finished, failed)
+	}
+}
+
+func TestMap(t *testing.T) {
+	words := []string{"pool", "", "generic", "go"}
+	results := Map(context.Background(), words, func(ctx context.Context, s string) (int, error) {
+		if s == "" {
+			return 0, errors.New("empty")
+		}
+		return len(s), nil
+	}, Options{Workers: 3})
+
+	if len(results) != len(words) {
+		t.Fatalf("%d results for %d inputs", len(results), len(words))
+	}
+	for i, r := range results {
+		if r.In != words[i] {
+			t.Errorf("results[%d].In = %q: out of order", i, r.In)
+		}
+		if (r.Err != nil) != (words[i] == "") || r.Out != len(words[i]) {
+			t.Errorf("results[%d] = %+v", i, r)
+		}
+	}
+}
+
+func TestMapCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := Map(ctx, []int{1, 2, 3}, double, Options{})
+	for _, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("%+v, want context.Canceled", r)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package querybuilder_test
+
+import (
+	"fmt"
+
+	"github.com/owolabijunior12/learning-golang/pkg/querybuilder"
+)
+
+func Example() {
+	query, args := querybuilder.New().
+		Select("id, name, email").
+		From("users").
+		Where("age > ?", 18).
+		Limit(10).
+		Build()
+
+	fmt.Println(query)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, name, email FROM users WHERE age > ? LIMIT 10
+	// [18]
+}
+
+func ExampleBuilder_Where() {
+	query, args := querybuilder.New().
+		Select("*").
+		From("products").
+		Where("category = ? AND price < ?", "books", 20.0).
+		Build()
+
+	fmt.Println(query)
+	fmt.Println(args)
+	// Output:
+	// SELECT * FROM products WHERE category = ? AND price < ?
+	// [books 20]
+}
+
+func ExampleBuilder_Where_repeated() {
+	query, args := querybuilder.New().
+		Select("id").
+		From("users").
+		Where("age > ?", 18).
+		Where("country = ?", "NG").
+		Build()
+
+	fmt.Println(query)
+	fmt.Println(args)
+	// Output:
+	// SELECT id FROM users WHERE age > ? AND country = ?
+	// [18 NG]
+}
+
+func ExampleBuilder_Join() {
+	query, args := querybuilder.New().
+		Select("u.name, COUNT(o.id) AS orders").
+		From("users u").
+		Join("orders o", "o.user_id = u.id").
+		Where("o.status = ?", "paid").
+		GroupBy("u.name").
+		OrderBy("orders DESC").
+		Limit(5).
+		Build()
+
+	fmt.Println(query)
+	fmt.Println(args)
+	// Output:
+	// SELECT u.name, COUNT(o.id) AS orders FROM users u JOIN orders o ON o.user_id = u.id WHERE o.status = ? GROUP BY u.name ORDER BY orders DESC LIMIT 5
+	// [paid]
+}
+
+func ExampleBuilder_WhereIn() {
+	query, args := querybuilder.New().
+		Select("id, name").
+		From("users").
+		WhereIn("id", 3, 5, 8).
+		Build()
+
+	fmt.Println(query)
+	fmt.Println(args)
+	// Output:
+	// SELECT id, name FROM users WHERE id IN (?, ?, ?)
+	// [3 5 8]
+}
+
+func ExampleBuilder_Dialect() {
+	// Conditions are written with ?; Postgres gets numbered placeholders
+	query, args := querybuilder.New().
+		Dialect(querybuilder.Postgres).
+		Select("*").
+		From("users").
+		Where("age > ?", 18).
+		WhereIn("city", "Lagos", "Abuja").
+		Build()
+
+	fmt.Println(query)
+	fmt.Println(args)
+	// Output:
+	// SELECT * FROM users WHERE age > $1 AND city IN ($2, $3)
+	// [18 Lagos Abuja]
+}
+
+func ExampleBuilder_SQL() {
+	_, _, err := querybuilder.New().
+		Select("*").
+		From("users").
+		Where("age > ? AND city = ?", 18).
+		SQL()
+
+	fmt.Println(err)
+	// Output:
+	// querybuilder: Where("age > ? AND city = ?") has 2 placeholders but 1 arguments
+}
@@ -0,0 +1,221 @@
+// Package querybuilder builds simple SQL SELECT statements step by step.
+//
+// It started life as the Builder pattern example in course 12 and was
+// extracted into its own module in course 14 so it can be versioned,
+// tagged and imported by other projects:
+//
+//	go get github.com/owolabijunior12/learning-golang/pkg/querybuilder@v0.1.0
+//
+// Values passed to Where and WhereIn are never concatenated into the query
+// string. They are returned separately by Build so they can be handed to
+// database/sql as placeholder arguments. Conditions are always written
+// with ? placeholders; for PostgreSQL, Build numbers them $1, $2, ...
+//
+// The clauses may be added in any order: Build puts them where SQL
+// expects them.
+package querybuilder
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect is the placeholder style of a database.
+type Dialect int
+
+const (
+	// MySQL uses ? placeholders, as do SQLite and most other databases.
+	// It is the default.
+	MySQL Dialect = iota
+	// Postgres numbers its placeholders: $1, $2, ...
+	Postgres
+)
+
+// Builder accumulates the parts of a SELECT statement.
+// Create one with New and finish it with SQL or Build.
+type Builder struct {
+	dialect Dialect
+	columns string
+	table   string
+	joins   []string
+	where   []string
+	groupBy []string
+	orderBy []string
+	limit   int
+	hasLim  bool
+	params  []interface{}
+	errs    []error
+}
+
+// New returns an empty Builder for the MySQL dialect.
+func New() *Builder {
+	return &Builder{}
+}
+
+// Dialect sets the placeholder style Build produces.
+func (b *Builder) Dialect(d Dialect) *Builder {
+	b.dialect = d
+	return b
+}
+
+// Select sets the column list, e.g. "id, name".
+func (b *Builder) Select(fields string) *Builder {
+	b.columns = fields
+	return b
+}
+
+// From sets the table to select from.
+func (b *Builder) From(table string) *Builder {
+	b.table = table
+	return b
+}
+
+// Join adds an INNER JOIN: Join("orders o", "o.user_id = u.id").
+func (b *Builder) Join(table, on string) *Builder {
+	return b.join("JOIN", table, on)
+}
+
+// LeftJoin adds a LEFT JOIN, which keeps rows with no match in table.
+func (b *Builder) LeftJoin(table, on string) *Builder {
+	return b.join("LEFT JOIN", table, on)
+}
+
+func (b *Builder) join(kind, table, on string) *Builder {
+	if table == "" || on == "" {
+		b.errs = append(b.errs, fmt.Errorf("%s needs a table and a condition", kind))
+	}
+	b.joins = append(b.joins, kind+" "+table+" ON "+on)
+	return b
+}
+
+// Where adds a condition using ? placeholders and records its arguments.
+// Calling it again adds another condition joined with AND.
+func (b *Builder) Where(condition string, args ...interface{}) *Builder {
+	if n := countPlaceholders(condition); n != len(args) {
+		b.errs = append(b.errs, fmt.Errorf("Where(%q) has %d placeholders but %d arguments", condition, n, len(args)))
+	}
+	b.where = append(b.where, condition)
+	b.params = append(b.params, args...)
+	return b
+}
+
+// WhereIn adds the condition "column IN (?, ?, ...)" with one placeholder
+// per value. To pass a slice of another type, copy it into a
+// []interface{} first. An empty list is an error: "IN ()" is not SQL.
+func (b *Builder) WhereIn(column string, values ...interface{}) *Builder {
+	if len(values) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("WhereIn(%q) needs at least one value", column))
+		return b
+	}
+	marks := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return b.Where(column+" IN ("+marks+")", values...)
+}
+
+// GroupBy adds columns to group the rows by.
+func (b *Builder) GroupBy(columns ...string) *Builder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// OrderBy adds sort columns, each optionally followed by ASC or DESC:
+// OrderBy("age DESC", "name").
+func (b *Builder) OrderBy(columns ...string) *Builder {
+	b.orderBy = append(b.orderBy, columns...)
+	return b
+}
+
+// Limit caps the number of returned rows.
+func (b *Builder) Limit(n int) *Builder {
+	if n < 0 {
+		b.errs = append(b.errs, fmt.Errorf("Limit(%d) is negative", n))
+	}
+	b.limit, b.hasLim = n, true
+	return b
+}
+
+// SQL returns the finished query and the arguments for its placeholders,
+// or an error describing every way the builder was misused: a missing
+// Select or From, a Where whose placeholders and arguments don't match,
+// an empty WhereIn, a negative Limit.
+func (b *Builder) SQL() (string, []interface{}, error) {
+	errs := b.errs
+	if b.columns == "" {
+		errs = append(errs, errors.New("no columns: call Select"))
+	}
+	if b.table == "" {
+		errs = append(errs, errors.New("no table: call From"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", nil, fmt.Errorf("querybuilder: %w", err)
+	}
+	query, params := b.Build()
+	return query, params, nil
+}
+
+// Build returns the finished query and the arguments for its placeholders.
+// It doesn't check the builder: use SQL unless the query is fixed and
+// known to be valid.
+func (b *Builder) Build() (string, []interface{}) {
+	var q strings.Builder
+	q.WriteString("SELECT " + b.columns + " FROM " + b.table)
+	for _, join := range b.joins {
+		q.WriteString(" " + join)
+	}
+	if len(b.where) > 0 {
+		q.WriteString(" WHERE " + strings.Join(b.where, " AND "))
+	}
+	if len(b.groupBy) > 0 {
+		q.WriteString(" GROUP BY " + strings.Join(b.groupBy, ", "))
+	}
+	if len(b.orderBy) > 0 {
+		q.WriteString(" ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+	if b.hasLim {
+		q.WriteString(" LIMIT " + strconv.Itoa(b.limit))
+	}
+
+	query := q.String()
+	if b.dialect == Postgres {
+		query = numberPlaceholders(query)
+	}
+	return query, b.params
+}
+
+// countPlaceholders counts the ? in s that are outside 'quoted' strings.
+func countPlaceholders(s string) int {
+	n := 0
+	eachPlaceholder(s, func(int) { n++ })
+	return n
+}
+
+// numberPlaceholders turns each ? outside quotes into $1, $2, ...
+func numberPlaceholders(s string) string {
+	var out strings.Builder
+	n, last := 0, 0
+	eachPlaceholder(s, func(i int) {
+		n++
+		out.WriteString(s[last:i] + "$" + strconv.Itoa(n))
+		last = i + 1
+	})
+	out.WriteString(s[last:])
+	return out.String()
+}
+
+// eachPlaceholder calls f with the index of each ? that is not inside a
+// single-quoted SQL string. A quote inside a string is escaped as two
+// quotes in a row, which toggle twice, so it needs no special case.
+func eachPlaceholder(s string, f func(i int)) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			quoted = !quoted
+		case '?':
+			if !quoted {
+				f(i)
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package querybuilder
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name   string
+		build  func() *Builder
+		query  string
+		params []interface{}
+	}{
+		{
+			name:  "no conditions",
+			build: func() *Builder { return New().Select("*").From("users") },
+			query: "SELECT * FROM users",
+		},
+		{
+			name:   "one placeholder",
+			build:  func() *Builder { return New().Select("id").From("users").Where("age > ?", 18) },
+			query:  "SELECT id FROM users WHERE age > ?",
+			params: []interface{}{18},
+		},
+		{
+			name: "several placeholders in one condition",
+			build: func() *Builder {
+				return New().Select("*").From("products").Where("category = ? AND price < ?", "books", 20.0)
+			},
+			query:  "SELECT * FROM products WHERE category = ? AND price < ?",
+			params: []interface{}{"books", 20.0},
+		},
+		{
+			name: "repeated Where joins with AND, params in call order",
+			build: func() *Builder {
+				return New().Select("*").From("users").Where("age > ?", 18).Where("country = ?", "NG").Limit(5)
+			},
+			query:  "SELECT * FROM users WHERE age > ? AND country = ? LIMIT 5",
+			params: []interface{}{18, "NG"},
+		},
+		{
+			name: "joins, grouping and ordering",
+			build: func() *Builder {
+				return New().Select("u.name, COUNT(o.id)").From("users u").
+					Join("orders o", "o.user_id = u.id").
+					LeftJoin("refunds r", "r.order_id = o.id").
+					Where("o.total > ?", 10).
+					GroupBy("u.name").
+					OrderBy("COUNT(o.id) DESC", "u.name")
+			},
+			query:  "SELECT u.name, COUNT(o.id) FROM users u JOIN orders o ON o.user_id = u.id LEFT JOIN refunds r ON r.order_id = o.id WHERE o.total > ? GROUP BY u.name ORDER BY COUNT(o.id) DESC, u.name",
+			params: []interface{}{10},
+		},
+		{
+			name: "clauses in any order",
+			build: func() *Builder {
+				return New().Limit(3).OrderBy("id").Where("age > ?", 18).From("users").Select("id")
+			},
+			query:  "SELECT id FROM users WHERE age > ? ORDER BY id LIMIT 3",
+			params: []interface{}{18},
+		},
+		{
+			name:   "IN list",
+			build:  func() *Builder { return New().Select("*").From("users").Where("age > ?", 18).WhereIn("id", 3, 5, 8) },
+			query:  "SELECT * FROM users WHERE age > ? AND id IN (?, ?, ?)",
+			params: []interface{}{18, 3, 5, 8},
+		},
+		{
+			name:   "quoted ? is not a placeholder",
+			build:  func() *Builder { return New().Select("*").From("faq").Where("question LIKE '%?' AND id > ?", 1) },
+			query:  "SELECT * FROM faq WHERE question LIKE '%?' AND id > ?",
+			params: []interface{}{1},
+		},
+		{
+			name:  "condition without arguments",
+			build: func() *Builder { return New().Select("*").From("users").Where("deleted_at IS NULL") },
+			query: "SELECT * FROM users WHERE deleted_at IS NULL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, params := tt.build().Build()
+			if query != tt.query {
+				t.Errorf("query = %q, want %q", query, tt.query)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("params = %#v, want %#v", params, tt.params)
+			}
+			if n := countPlaceholders(query); n != len(params) {
+				t.Errorf("%d placeholders but %d params", n, len(params))
+			}
+		})
+	}
+}
+
+func TestPostgresPlaceholders(t *testing.T) {
+	query, params := New().Dialect(Postgres).Select("*").From("users").
+		Where("age BETWEEN ? AND ?", 18, 65).
+		WhereIn("city", "Lagos", "Abuja").
+		Where("note <> 'why?'").
+		Build()
+
+	want := "SELECT * FROM users WHERE age BETWEEN $1 AND $2 AND city IN ($3, $4) AND note <> 'why?'"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if len(params) != 4 {
+		t.Errorf("params = %v, want 4", params)
+	}
+}
+
+func TestSQLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build *Builder
+		want  string // in the error; "" for no error
+	}{
+		{"valid", New().Select("*").From("users").WhereIn("id", 1), ""},
+		{"no select", New().From("users"), "call Select"},
+		{"no from", New().Select("*"), "call From"},
+		{"too few arguments", New().Select("*").From("users").Where("a = ? AND b = ?", 1), "2 placeholders but 1 arguments"},
+		{"too many arguments", New().Select("*").From("users").Where("a = ?", 1, 2), "1 placeholders but 2 arguments"},
+		{"empty IN", New().Select("*").From("users").WhereIn("id"), "at least one value"},
+		{"join without condition", New().Select("*").From("users").Join("orders", ""), "needs a table and a condition"},
+		{"negative limit", New().Select("*").From("users").Limit(-1), "negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, params, err := tt.build.SQL()
+			if tt.want == "" {
+				if err != nil || query == "" {
+					t.Errorf("SQL() = %q, %v, %v", query, params, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one containing %q", err, tt.want)
+			}
+			if query != "" || params != nil {
+				t.Errorf("SQL() returned %q, %v along with an error", query, params)
+			}
+		})
+	}
+
+	// Every problem is reported, not just the first
+	_, _, err := New().WhereIn("id").SQL()
+	for _, want := range []string{"WhereIn", "Select", "From"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to mention %s", err, want)
+		}
+	}
+}
+
+// Values must never end up in the SQL text, whatever they contain.
+func TestWhereDoesNotInterpolate(t *testing.T) {
+	evil := "x'; DROP TABLE users; --"
+	query, params := New().Select("*").From("users").Where("name = ?", evil).Build()
+	if strings.Contains(query, evil) {
+		t.Errorf("value leaked into query %q", query)
+	}
+	if len(params) != 1 || params[0] != evil {
+		t.Errorf("params = %#v, want the value unchanged", params)
+	}
+}
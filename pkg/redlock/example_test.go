@@ -0,0 +1,58 @@
+package redlock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/owolabijunior12/learning-golang/pkg/redlock"
+)
+
+func Example() {
+	mr, _ := miniredis.Run() // a real server in production: redis.NewClient(&redis.Options{Addr: ...})
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	ctx := context.Background()
+
+	locker := redlock.New(rdb)
+	lock, err := locker.Obtain(ctx, "lock:nightly-report", 30*time.Second)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Another process, meanwhile
+	_, err = locker.TryObtain(ctx, "lock:nightly-report", 30*time.Second)
+	fmt.Println("another process:", err)
+
+	fmt.Println("release:", lock.Release(ctx))
+	// Output:
+	// another process: redlock: lock held by someone else
+	// release: <nil>
+}
+
+func ExampleLock_Extend() {
+	mr, _ := miniredis.Run()
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	ctx := context.Background()
+
+	lock, _ := redlock.New(rdb).TryObtain(ctx, "lock:migration", 10*time.Second)
+	for step := range 3 {
+		mr.FastForward(8 * time.Second) // each step takes a while
+		// Extend before the TTL runs out; if it already has, stop: someone
+		// else may be running the migration now
+		if err := lock.Extend(ctx, 10*time.Second); errors.Is(err, redlock.ErrNotHeld) {
+			fmt.Println("lost the lock at step", step)
+			return
+		}
+	}
+	fmt.Println("done, still holding the lock")
+	// Output: done, still holding the lock
+}
@@ -0,0 +1,160 @@
+// Package redlock is a lock on a single Redis server, for processes that
+// must not do something at the same time: run a migration, send the
+// nightly report, refresh a token.
+//
+// A lock is a key set with SET key token NX PX ttl: only if it doesn't
+// exist, and with an expiry, so a holder that crashes can't keep it
+// forever. Its value is a random token only the holder knows, and Release
+// deletes the key only if it still holds that token, checked and deleted
+// in one Lua script. A holder that was paused past the expiry, and lost
+// the lock to another process, can't then delete the other process's lock.
+//
+//	locker := redlock.New(rdb)
+//	lock, err := locker.Obtain(ctx, "lock:nightly-report", 30*time.Second)
+//	if err != nil {
+//		return err
+//	}
+//	defer lock.Release(context.Background())
+//
+// It is the single-instance algorithm from the Redis documentation, not
+// Redlock over several servers: if the Redis server fails over to a replica
+// that hadn't seen the SET, two processes can hold the lock. Where that
+// matters, give the protected resource a fencing token as well.
+//
+// It lives in its own module, like pkg/pool, so other projects can import
+// it.
+package redlock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotObtained is returned by TryObtain when another holder has the lock.
+var ErrNotObtained = errors.New("redlock: lock held by someone else")
+
+// ErrNotHeld is returned by Release and Extend when the lock expired, and
+// may now be someone else's.
+var ErrNotHeld = errors.New("redlock: lock no longer held")
+
+// release deletes the key only if it holds our token. GET and DEL as two
+// commands would leave a gap in which the key can expire and be taken.
+var release = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extend resets the expiry only if the key holds our token.
+var extend = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// Client is the part of a go-redis client the locks use. *redis.Client
+// has it.
+type Client interface {
+	redis.Scripter
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd
+}
+
+// Locker obtains locks on one Redis server.
+type Locker struct {
+	client     Client
+	RetryDelay time.Duration // how often Obtain tries again while the lock is held
+}
+
+// New returns a Locker on client.
+func New(client Client) *Locker {
+	return &Locker{client: client, RetryDelay: 50 * time.Millisecond}
+}
+
+// Lock is a held lock.
+type Lock struct {
+	client redis.Scripter
+	key    string
+	token  string
+}
+
+// Key is the Redis key of the lock.
+func (l *Lock) Key() string { return l.key }
+
+// Token is the random value only this holder knows.
+func (l *Lock) Token() string { return l.token }
+
+// TryObtain takes the lock on key for ttl if it is free, and returns
+// ErrNotObtained if not. It never waits.
+func (l *Locker) TryObtain(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotObtained
+	}
+	return &Lock{client: l.client, key: key, token: token}, nil
+}
+
+// Obtain takes the lock on key for ttl, trying every RetryDelay until it
+// is free or ctx is done.
+func (l *Locker) Obtain(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	for {
+		lock, err := l.TryObtain(ctx, key, ttl)
+		if !errors.Is(err, ErrNotObtained) {
+			return lock, err
+		}
+		timer := time.NewTimer(l.RetryDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Release gives the lock up. It returns ErrNotHeld, and deletes nothing,
+// if the lock expired first.
+func (l *Lock) Release(ctx context.Context) error {
+	n, err := release.Run(ctx, l.client, []string{l.key}, l.token).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+// Extend sets the lock to expire ttl from now, for work that takes longer
+// than planned. It returns ErrNotHeld if the lock already expired.
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	n, err := extend.Run(ctx, l.client, []string{l.key}, l.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotHeld
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,178 @@
+package redlock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newLocker returns a Locker on a fake Redis server, which the test can
+// fast-forward to expire keys.
+func newLocker(t *testing.T) (*Locker, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	l := New(rdb)
+	l.RetryDelay = time.Millisecond
+	return l, mr
+}
+
+func TestTryObtain(t *testing.T) {
+	ctx := context.Background()
+	l, mr := newLocker(t)
+
+	lock, err := l.TryObtain(ctx, "lock:job", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := mr.Get("lock:job"); got != lock.Token() || len(got) != 32 {
+		t.Errorf("key holds %q, want the 32-digit token %q", got, lock.Token())
+	}
+	if ttl := mr.TTL("lock:job"); ttl != time.Minute {
+		t.Errorf("TTL = %v, want 1m", ttl)
+	}
+
+	if _, err := l.TryObtain(ctx, "lock:job", time.Minute); !errors.Is(err, ErrNotObtained) {
+		t.Errorf("second TryObtain: err = %v, want ErrNotObtained", err)
+	}
+	if _, err := l.TryObtain(ctx, "lock:other", time.Minute); err != nil {
+		t.Errorf("a different key: %v", err)
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists("lock:job") {
+		t.Error("the key is still there after Release")
+	}
+	if _, err := l.TryObtain(ctx, "lock:job", time.Minute); err != nil {
+		t.Errorf("TryObtain after Release: %v", err)
+	}
+}
+
+func TestTokensDiffer(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newLocker(t)
+	a, _ := l.TryObtain(ctx, "a", time.Minute)
+	b, _ := l.TryObtain(ctx, "b", time.Minute)
+	if a.Token() == b.Token() {
+		t.Errorf("two locks share the token %q", a.Token())
+	}
+}
+
+// The failure naive SETNX/DEL locks have: a holder paused past the TTL
+// comes back and deletes the lock the next holder took meanwhile.
+func TestExpiredHolderCannotReleaseNextHoldersLock(t *testing.T) {
+	ctx := context.Background()
+	l, mr := newLocker(t)
+
+	first, err := l.TryObtain(ctx, "lock:job", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(2 * time.Second) // first is paused (GC, a slow disk...) past its TTL
+	second, err := l.TryObtain(ctx, "lock:job", time.Minute)
+	if err != nil {
+		t.Fatalf("the expired lock wasn't free: %v", err)
+	}
+
+	if err := first.Release(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("the expired holder's Release = %v, want ErrNotHeld", err)
+	}
+	if err := first.Extend(ctx, time.Minute); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("the expired holder's Extend = %v, want ErrNotHeld", err)
+	}
+	if got, _ := mr.Get("lock:job"); got != second.Token() {
+		t.Errorf("the key holds %q, want the second holder's token", got)
+	}
+	if err := second.Release(ctx); err != nil {
+		t.Errorf("the second holder's Release: %v", err)
+	}
+}
+
+func TestExtend(t *testing.T) {
+	ctx := context.Background()
+	l, mr := newLocker(t)
+	lock, _ := l.TryObtain(ctx, "lock:job", time.Second)
+
+	mr.FastForward(900 * time.Millisecond)
+	if err := lock.Extend(ctx, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(time.Second) // past the first TTL, within the second
+	if err := lock.Release(ctx); err != nil {
+		t.Errorf("Release after Extend: %v", err)
+	}
+}
+
+func TestObtainWaits(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newLocker(t)
+	held, _ := l.TryObtain(ctx, "lock:job", time.Minute)
+
+	got := make(chan error, 1)
+	go func() {
+		lock, err := l.Obtain(ctx, "lock:job", time.Minute)
+		if err == nil {
+			err = lock.Release(ctx)
+		}
+		got <- err
+	}()
+	select {
+	case err := <-got:
+		t.Fatalf("Obtain returned %v while the lock was held", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	held.Release(ctx)
+	if err := <-got; err != nil {
+		t.Errorf("Obtain once the lock was free: %v", err)
+	}
+
+	held, _ = l.TryObtain(ctx, "lock:job", time.Minute)
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Obtain(ctx, "lock:job", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Obtain on a held lock = %v, want it to give up with ctx", err)
+	}
+}
+
+func TestMutualExclusion(t *testing.T) {
+	ctx := context.Background()
+	l, _ := newLocker(t)
+
+	var inside, most, total atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				lock, err := l.Obtain(ctx, "lock:counter", time.Minute)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				now := inside.Add(1)
+				for m := most.Load(); now > m && !most.CompareAndSwap(m, now); m = most.Load() {
+				}
+				total.Add(1)
+				inside.Add(-1)
+				if err := lock.Release(ctx); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if most.Load() != 1 || total.Load() != 40 {
+		t.Errorf("%d holders at once, %d turns; want 1 and 40", most.Load(), total.Load())
+	}
+}
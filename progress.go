@@ -0,0 +1,222 @@
+package learn
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/safefile"
+)
+
+// progress is what the learner has done so far, kept in a JSON file
+// between runs.
+type progress struct {
+	Exercises map[string]exerciseProgress `json:"exercises"`
+	Quizzes   map[int]quizProgress        `json:"quizzes,omitempty"`
+	Review    map[string]cardState        `json:"review,omitempty"` // flashcard schedule, by card id
+
+	Challenges    map[string]challengeProgress `json:"challenges,omitempty"`
+	ChallengeDays []string                     `json:"challenge_days,omitempty"` // YYYY-MM-DD, days a challenge was solved
+	Daily         dailyPick                    `json:"daily,omitzero"`
+
+	Goals  []string                 `json:"goals,omitempty"` // tracks the learner is aiming for, for "next"
+	Tracks map[string]trackProgress `json:"tracks,omitempty"`
+
+	Bookmark *bookmark `json:"bookmark,omitempty"` // where the learner last quit, for "resume"
+}
+
+// bookmark is where the learner quit a paced run: the course and the
+// section they stopped before, and the courses that were to follow.
+type bookmark struct {
+	Course  int       `json:"course"`
+	Section string    `json:"section,omitempty"` // section id; empty for the start of the course
+	Then    []int     `json:"then,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// exerciseProgress records the grading history of one exercise.
+type exerciseProgress struct {
+	Course   int       `json:"course"`
+	Score    int       `json:"score"` // percent, from the latest attempt
+	Best     int       `json:"best"`
+	Attempts int       `json:"attempts"`
+	GradedAt time.Time `json:"graded_at"`
+}
+
+// quizProgress records the attempts at one course's quiz.
+type quizProgress struct {
+	Score    int       `json:"score"` // percent, from the latest attempt
+	Best     int       `json:"best"`
+	Attempts int       `json:"attempts"`
+	TakenAt  time.Time `json:"taken_at"`
+}
+
+// challengeProgress records the attempts at one challenge.
+type challengeProgress struct {
+	Best     int    `json:"best"` // percent
+	Attempts int    `json:"attempts"`
+	Solved   string `json:"solved,omitempty"` // YYYY-MM-DD of the first full score
+}
+
+// trackProgress is how far the learner has come in one track. The
+// courses are shared between tracks; the capstones count per track.
+type trackProgress struct {
+	Started   string            `json:"started,omitempty"`   // YYYY-MM-DD of the first run with --track
+	Capstones map[string]string `json:"capstones,omitempty"` // YYYY-MM-DD each capstone's tests first passed
+}
+
+// dailyPick is the day's challenge, kept so the pick holds all day.
+type dailyPick struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// defaultProgressPath is where progress lives unless -progress says
+// otherwise: ~/.config/learning-golang/progress.json on Linux.
+func defaultProgressPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "progress.json"
+	}
+	return filepath.Join(dir, "learning-golang", "progress.json")
+}
+
+// loadProgress reads the progress file. A missing file is a fresh start,
+// not an error.
+func loadProgress(path string) (*progress, error) {
+	p := &progress{Exercises: map[string]exerciseProgress{}, Quizzes: map[int]quizProgress{}, Review: map[string]cardState{}, Challenges: map[string]challengeProgress{}, Tracks: map[string]trackProgress{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	if p.Exercises == nil {
+		p.Exercises = map[string]exerciseProgress{}
+	}
+	if p.Quizzes == nil {
+		p.Quizzes = map[int]quizProgress{}
+	}
+	if p.Review == nil {
+		p.Review = map[string]cardState{}
+	}
+	if p.Challenges == nil {
+		p.Challenges = map[string]challengeProgress{}
+	}
+	if p.Tracks == nil {
+		p.Tracks = map[string]trackProgress{}
+	}
+	return p, nil
+}
+
+// save writes the progress file atomically: a crash while saving leaves
+// the previous progress, not half of the new.
+func (p *progress) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// updateProgress reads the progress file, lets change update it, and saves
+// it, holding a lock on path+".lock" from the read to the save. Two runs
+// at once, say a quiz in the browser and a grade in a terminal, then each
+// see the other's changes instead of saving over them. It returns the
+// progress as saved; if change fails, nothing is saved.
+func updateProgress(path string, change func(p *progress) error) (*progress, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	lock, err := safefile.Obtain(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release()
+
+	p, err := loadProgress(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading progress: %w", err)
+	}
+	if err := change(p); err != nil {
+		return nil, err
+	}
+	if err := p.save(path); err != nil {
+		return nil, fmt.Errorf("saving progress: %w", err)
+	}
+	return p, nil
+}
+
+// recordGrade adds one grading attempt, keeping the best score seen.
+func (p *progress) recordGrade(r gradeResult, at time.Time) {
+	e := p.Exercises[r.exercise.name]
+	e.Course = r.exercise.course
+	e.Score = r.percent()
+	e.Best = max(e.Best, e.Score)
+	e.Attempts++
+	e.GradedAt = at
+	p.Exercises[r.exercise.name] = e
+}
+
+// recordQuiz adds one attempt at a course's quiz.
+func (p *progress) recordQuiz(course, score int, at time.Time) {
+	q := p.Quizzes[course]
+	q.Score = score
+	q.Best = max(q.Best, score)
+	q.Attempts++
+	q.TakenAt = at
+	p.Quizzes[course] = q
+}
+
+// recordChallenge adds one grading attempt at a challenge. The first full
+// score marks it solved and counts the day towards the streak.
+func (p *progress) recordChallenge(r gradeResult, at time.Time) {
+	c := p.Challenges[r.exercise.name]
+	c.Best = max(c.Best, r.percent())
+	c.Attempts++
+	if r.percent() == 100 {
+		today := at.Format(dateLayout)
+		if c.Solved == "" {
+			c.Solved = today
+		}
+		if !slices.Contains(p.ChallengeDays, today) {
+			p.ChallengeDays = append(p.ChallengeDays, today)
+		}
+	}
+	p.Challenges[r.exercise.name] = c
+}
+
+// startTrack notes the day the learner first followed a track.
+func (p *progress) startTrack(name string, at time.Time) {
+	t := p.Tracks[name]
+	if t.Started == "" {
+		t.Started = at.Format(dateLayout)
+	}
+	p.Tracks[name] = t
+}
+
+// recordCapstone marks a capstone of a track as passed, on the first day
+// its tests passed.
+func (p *progress) recordCapstone(track, capstone string, at time.Time) {
+	p.startTrack(track, at)
+	t := p.Tracks[track]
+	if t.Capstones == nil {
+		t.Capstones = map[string]string{}
+	}
+	if t.Capstones[capstone] == "" {
+		t.Capstones[capstone] = at.Format(dateLayout)
+	}
+	p.Tracks[track] = t
+}
@@ -0,0 +1,64 @@
+package learn
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Updates at the same time each see the others' changes: none is lost.
+func TestUpdateProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "learning-golang", "progress.json")
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for course := 1; course <= 20; course++ {
+		wg.Go(func() {
+			_, err := updateProgress(path, func(p *progress) error {
+				p.recordQuiz(course, 80, at)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	p, err := loadProgress(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Quizzes) != 20 {
+		t.Errorf("%d quizzes recorded, want 20", len(p.Quizzes))
+	}
+
+	// A change that fails saves nothing
+	errNo := errors.New("no")
+	_, err = updateProgress(path, func(p *progress) error {
+		p.Quizzes = nil
+		return errNo
+	})
+	if !errors.Is(err, errNo) {
+		t.Errorf("err = %v, want %v", err, errNo)
+	}
+	if p, err := loadProgress(path); err != nil || len(p.Quizzes) != 20 {
+		t.Errorf("after a failed change: %d quizzes, %v", len(p.Quizzes), err)
+	}
+
+	// The lock file stays; the temporary files don't
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if len(names) != 2 {
+		t.Errorf("progress directory holds %v, want progress.json and its lock", names)
+	}
+}
@@ -0,0 +1,172 @@
+package learn
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// quizQuestion is one multiple-choice question about a course.
+type quizQuestion struct {
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices"`
+	Answer  int      `json:"answer"`  // index into Choices
+	Explain string   `json:"explain"` // shown once the question is answered
+}
+
+// quizPassMark is the score, in percent, that passes a quiz: two
+// questions out of three.
+const quizPassMark = 60
+
+// scoreQuiz checks a learner's answers to a course's quiz and returns
+// which ones were right.
+func scoreQuiz(quizzes map[int][]quizQuestion, number int, answers []int) ([]bool, error) {
+	questions, ok := quizzes[number]
+	if !ok {
+		return nil, fmt.Errorf("course %d has no quiz", number)
+	}
+	if len(answers) != len(questions) {
+		return nil, fmt.Errorf("quiz %d has %d questions, got %d answers", number, len(questions), len(answers))
+	}
+	right := make([]bool, len(questions))
+	for i, q := range questions {
+		right[i] = answers[i] == q.Answer
+	}
+	return right, nil
+}
+
+// runQuiz implements "go run ./cmd/learn quiz import|export ...".
+func runQuiz(args []string) error {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "usage: go run ./cmd/learn quiz import [flags] file...")
+		fmt.Fprintln(os.Stderr, "       go run ./cmd/learn quiz export [flags] [course...|all]")
+		fmt.Fprintln(os.Stderr, "Question banks are JSON or YAML files; see quizzes/README.md for the format.")
+	}
+	if len(args) == 0 {
+		usage()
+		return errors.New("quiz needs import or export")
+	}
+	switch args[0] {
+	case "import":
+		return runQuizImport(args[1:])
+	case "export":
+		return runQuizExport(args[1:])
+	case "-h", "-help", "--help":
+		usage()
+		return nil
+	}
+	usage()
+	return fmt.Errorf("unknown quiz command %q", args[0])
+}
+
+// runQuizImport checks question banks and copies them into the quiz
+// folder, where the API and the other quiz tools pick them up. Nothing is
+// copied unless every file is valid.
+func runQuizImport(args []string) error {
+	flags := flag.NewFlagSet("quiz import", flag.ContinueOnError)
+	dir := flags.String("dir", defaultQuizDir(), "folder to import question banks into")
+	check := flags.Bool("check", false, "only validate the files")
+	force := flags.Bool("force", false, "replace a bank already imported under the same name")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn quiz import [flags] file...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("name at least one question bank")
+	}
+
+	data := make(map[string][]byte)
+	var errs []error
+	for _, name := range flags.Args() {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		b, err := decodeQuizBank(name, src)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Printf("%s: %s for course %d\n", name, plural(len(b.Questions), "question", "questions"), b.Course)
+		data[name] = src
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if *check {
+		return nil
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+	for _, name := range flags.Args() {
+		to := filepath.Join(*dir, filepath.Base(name))
+		if _, err := os.Stat(to); err == nil && !*force {
+			return fmt.Errorf("%s is already imported; use -force to replace it", to)
+		}
+		if err := os.WriteFile(to, data[name], 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("imported %s\n", to)
+	}
+	return nil
+}
+
+// runQuizExport writes the quizzes, built-in and imported, as one bank
+// per course: a starting point for a new bank, or a way to review one.
+func runQuizExport(args []string) error {
+	flags := flag.NewFlagSet("quiz export", flag.ContinueOnError)
+	format := flags.String("format", "yaml", "file format: yaml or json")
+	out := flags.String("o", "quiz-export", "folder to write the banks to")
+	from := flags.String("dir", defaultQuizDir(), "folder of imported question banks to include")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn quiz export [flags] [course...|all]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *format != "yaml" && *format != "json" {
+		return fmt.Errorf("-format must be yaml or json, not %q", *format)
+	}
+	selected := courses
+	if flags.NArg() > 0 {
+		selected = nil
+		for _, arg := range flags.Args() {
+			more, err := courseArg(arg)
+			if err != nil {
+				return err
+			}
+			selected = append(selected, more...)
+		}
+	}
+	quizzes, err := loadQuizzes(*from)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	for _, c := range selected {
+		data, err := encodeQuizBank(quizBank{Course: c.number, Questions: quizzes[c.number]}, *format)
+		if err != nil {
+			return err
+		}
+		name := filepath.Join(*out, strings.TrimSuffix(c.file, ".go")+"."+*format)
+		if err := os.WriteFile(name, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Println(name)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package learn
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/owolabijunior12/learning-golang/internal/yaml"
+)
+
+// quizBank is the file format for quiz questions. It is the same in JSON
+// and in YAML:
+//
+//	course: 4
+//	questions:
+//	  - prompt: Who should close a channel?
+//	    choices:
+//	      - The sender
+//	      - The receiver
+//	    answer: 0   # index into choices
+//	    explain: Only the sender knows no more values are coming.
+//
+// quizzes/schema.json describes it as a JSON Schema, for editors that can
+// check files as they are written.
+type quizBank struct {
+	Course    int            `json:"course"`
+	Questions []quizQuestion `json:"questions"`
+}
+
+// The built-in quizzes are files in the same format, one per course, so
+// adding a question never means editing Go code.
+//
+//go:embed quizzes/*.yaml
+var builtinQuizzes embed.FS
+
+// defaultQuizDir is where "quiz import" puts question banks: next to the
+// progress file.
+func defaultQuizDir() string {
+	return filepath.Join(filepath.Dir(defaultProgressPath()), "quizzes")
+}
+
+// loadQuizzes reads the built-in quizzes and any banks imported into dir,
+// by course number. An imported bank named like a built-in one
+// (04-goroutines-and-channels.json, say) replaces it; any other adds its
+// questions to its course. Every file is validated, and the first bad one
+// is an error, so a broken bank is noticed when it's loaded rather than
+// halfway through a quiz. A missing dir is not an error.
+func loadQuizzes(dir string) (map[int][]quizQuestion, error) {
+	var imported []string
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && quizFormat(e.Name()) != "" {
+				imported = append(imported, e.Name())
+			}
+		}
+	}
+	replaced := func(name string) bool {
+		return slices.ContainsFunc(imported, func(i string) bool { return bankName(i) == bankName(name) })
+	}
+
+	quizzes := make(map[int][]quizQuestion)
+	add := func(name string, data []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		b, err := decodeQuizBank(name, data)
+		if err != nil {
+			return err
+		}
+		quizzes[b.Course] = append(quizzes[b.Course], b.Questions...)
+		return nil
+	}
+	names, _ := fs.Glob(builtinQuizzes, "quizzes/*.yaml")
+	for _, name := range names {
+		if replaced(name) {
+			continue
+		}
+		data, err := builtinQuizzes.ReadFile(name)
+		if err := add(name, data, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range imported {
+		name = filepath.Join(dir, name)
+		data, err := os.ReadFile(name)
+		if err := add(name, data, err); err != nil {
+			return nil, err
+		}
+	}
+	return quizzes, nil
+}
+
+// bankName is a question bank's file name without its folder and
+// extension.
+func bankName(name string) string {
+	name = path.Base(filepath.ToSlash(name))
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// quizFormat is "json" or "yaml" going by a file's extension, or "" for
+// anything else.
+func quizFormat(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return ""
+}
+
+// decodeQuizBank parses and validates a question bank. The format comes
+// from the file name's extension.
+func decodeQuizBank(name string, data []byte) (quizBank, error) {
+	var doc any
+	switch quizFormat(name) {
+	case "json":
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return quizBank{}, fmt.Errorf("%s: %w", name, err)
+		}
+	case "yaml":
+		var err error
+		if doc, err = yaml.Parse(data); err != nil {
+			return quizBank{}, fmt.Errorf("%s: %w", name, err)
+		}
+	default:
+		return quizBank{}, fmt.Errorf("%s: a question bank must be .json, .yaml or .yml", name)
+	}
+
+	// Decoding leaves a missing answer as 0, which is a real choice, so
+	// look for it while the fields can still be told apart
+	if m, ok := doc.(map[string]any); ok {
+		qs, _ := m["questions"].([]any)
+		for i, q := range qs {
+			if q, ok := q.(map[string]any); ok && q["answer"] == nil {
+				return quizBank{}, fmt.Errorf("%s: question %d: no answer", name, i+1)
+			}
+		}
+	}
+
+	// Both formats end up as the same values, so one strict decode checks
+	// the field names and types of either
+	js, err := json.Marshal(doc)
+	if err != nil {
+		return quizBank{}, fmt.Errorf("%s: %w", name, err)
+	}
+	var b quizBank
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return quizBank{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := b.validate(); err != nil {
+		return quizBank{}, fmt.Errorf("%s: %w", name, err)
+	}
+	// Surrounding space, like the newline a | block ends with, isn't
+	// part of the text
+	for i := range b.Questions {
+		q := &b.Questions[i]
+		q.Prompt, q.Explain = strings.TrimSpace(q.Prompt), strings.TrimSpace(q.Explain)
+		for j := range q.Choices {
+			q.Choices[j] = strings.TrimSpace(q.Choices[j])
+		}
+	}
+	return b, nil
+}
+
+// validate checks what the types can't: that the course exists and that
+// every question can be answered. It reports every problem, not just the
+// first.
+func (b quizBank) validate() error {
+	var errs []error
+	if _, ok := findCourse(b.Course); !ok {
+		errs = append(errs, fmt.Errorf("course %d does not exist (available: 1-%d)", b.Course, len(courses)))
+	}
+	if len(b.Questions) == 0 {
+		errs = append(errs, errors.New("no questions"))
+	}
+	for i, q := range b.Questions {
+		bad := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("question %d: "+format, append([]any{i + 1}, args...)...))
+		}
+		if strings.TrimSpace(q.Prompt) == "" {
+			bad("no prompt")
+		}
+		if len(q.Choices) < 2 {
+			bad("needs at least 2 choices, has %d", len(q.Choices))
+		}
+		for j, c := range q.Choices {
+			if strings.TrimSpace(c) == "" {
+				bad("choice %d is empty", j)
+			} else if slices.Index(q.Choices, c) < j {
+				bad("choice %q appears twice", c)
+			}
+		}
+		if q.Answer < 0 || q.Answer >= len(q.Choices) {
+			bad("answer %d is not one of the choices (0-%d)", q.Answer, len(q.Choices)-1)
+		}
+		if strings.TrimSpace(q.Explain) == "" {
+			bad("no explanation")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// encodeQuizBank writes a question bank in the given format.
+func encodeQuizBank(b quizBank, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(b, "", "  ")
+		return append(data, '\n'), err
+	case "yaml":
+		var buf bytes.Buffer
+		if c, ok := findCourse(b.Course); ok {
+			fmt.Fprintf(&buf, "# Quiz for course %d: %s\n", c.number, c.name)
+		}
+		fmt.Fprintf(&buf, "course: %d\nquestions:\n", b.Course)
+		for _, q := range b.Questions {
+			fmt.Fprintf(&buf, "  - prompt: %s\n    choices:\n", yaml.Quote(q.Prompt))
+			for _, c := range q.Choices {
+				fmt.Fprintf(&buf, "      - %s\n", yaml.Quote(c))
+			}
+			fmt.Fprintf(&buf, "    answer: %d\n    explain: %s\n", q.Answer, yaml.Quote(q.Explain))
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown format %q: use json or yaml", format)
+}
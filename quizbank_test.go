@@ -0,0 +1,98 @@
+package learn
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/yaml"
+)
+
+// TestQuizBankRoundTrip exports every built-in quiz in both formats and
+// reads it back unchanged.
+func TestQuizBankRoundTrip(t *testing.T) {
+	quizzes, err := loadQuizzes("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range courses {
+		bank := quizBank{Course: c.number, Questions: quizzes[c.number]}
+		for _, format := range []string{"yaml", "json"} {
+			data, err := encodeQuizBank(bank, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := decodeQuizBank("bank."+format, data)
+			if err != nil {
+				t.Fatalf("course %d as %s: %v\n%s", c.number, format, err, data)
+			}
+			if !reflect.DeepEqual(got, bank) {
+				t.Errorf("course %d as %s changed:\n%s", c.number, format, data)
+			}
+		}
+	}
+
+	for _, s := range []string{"- item", "a: b", "#tag", "123", "true", "null", "ends:", "x #y", "  padded", `back\slash`, "line\nbreak", "it's"} {
+		v, err := yaml.Parse([]byte("k: " + yaml.Quote(s) + "\n"))
+		if err != nil || v.(map[string]any)["k"] != s {
+			t.Errorf("yaml.Quote(%q) = %s reads back as %#v, %v", s, yaml.Quote(s), v, err)
+		}
+	}
+}
+
+func TestDecodeQuizBankErrors(t *testing.T) {
+	tests := []struct{ name, src, err string }{
+		{"q.yaml", "course: 4\nquestions:\n  - prompt: P\n    choices: [a]\n", "flow collections"},
+		{"q.json", `{"course": 99, "questions": []}`, "course 99 does not exist"},
+		{"q.json", `{"course": 4, "questions": []}`, "no questions"},
+		{"q.json", `{"course": 4, "questions": [{"prompt": "P", "choices": ["a", "b"], "explain": "E"}]}`, "question 1: no answer"},
+		{"q.json", `{"course": 4, "questions": [{"prompt": "P", "choices": ["a", "b"], "answer": 2, "explain": "E"}]}`, "answer 2 is not one of the choices (0-1)"},
+		{"q.json", `{"course": 4, "questions": [{"prompt": "P", "choices": ["a", "a"], "answer": 0, "explain": "E"}]}`, `choice "a" appears twice`},
+		{"q.json", `{"course": 4, "questions": [{"prompt": "P", "choices": ["a", "b"], "answer": 0, "explain": "E", "hint": "H"}]}`, `unknown field "hint"`},
+		{"q.yaml", "course: 4\nquestions:\n  - prompt: P\n    choices:\n      - a\n      - b\n    answer: first\n    explain: E\n", "cannot unmarshal string"},
+		{"q.txt", "", "must be .json, .yaml or .yml"},
+	}
+	for _, tt := range tests {
+		_, err := decodeQuizBank(tt.name, []byte(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.err) || !strings.HasPrefix(err.Error(), tt.name+": ") {
+			t.Errorf("decodeQuizBank(%s) = %v, want %q", tt.src, err, tt.err)
+		}
+	}
+
+	// Every problem is listed, not just the first
+	_, err := decodeQuizBank("q.json", []byte(`{"course": 4, "questions": [{"prompt": "", "choices": ["a"], "answer": 0, "explain": ""}]}`))
+	if err == nil || strings.Count(err.Error(), "question 1:") != 3 {
+		t.Errorf("want three problems with question 1, got %v", err)
+	}
+}
+
+func TestLoadImportedQuizzes(t *testing.T) {
+	builtin, _ := loadQuizzes("")
+	dir := t.TempDir()
+	write := func(name, src string) {
+		os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644)
+	}
+	// Same name as the built-in bank: replaces it
+	write("04-goroutines-and-channels.json", `{"course": 4, "questions": [{"prompt": "P", "choices": ["a", "b"], "answer": 1, "explain": "E"}]}`)
+	// Any other name: adds to the course
+	write("extra.yml", "course: 5\nquestions:\n  - prompt: P\n    choices:\n      - a\n      - b\n    answer: 0\n    explain: E\n")
+	write("notes.txt", "not a bank")
+
+	quizzes, err := loadQuizzes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quizzes[4]) != 1 || quizzes[4][0].Answer != 1 {
+		t.Errorf("course 4 = %+v, want the imported question only", quizzes[4])
+	}
+	if len(quizzes[5]) != len(builtin[5])+1 {
+		t.Errorf("course 5 has %d questions, want %d", len(quizzes[5]), len(builtin[5])+1)
+	}
+
+	write("broken.json", `{"course": 4}`)
+	if _, err := loadQuizzes(dir); err == nil || !strings.Contains(err.Error(), "broken.json: no questions") {
+		t.Errorf("a broken bank should stop loading: %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+package learn
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// termRenderer prints lesson Markdown to a terminal: headings and **bold**
+// in bold, `code` and Go code blocks highlighted, and prose wrapped to the
+// terminal width. Without color it prints the text as written, so piped
+// output stays plain.
+type termRenderer struct {
+	w     io.Writer
+	width int
+	color bool
+}
+
+// maxWidth caps wrapping on very wide terminals, where long lines are
+// hard to read.
+const maxWidth = 100
+
+// newTermRenderer sets up a renderer for w. Color is on for terminals
+// unless NO_COLOR is set; the width is the terminal's, or $COLUMNS, or 80.
+// A w that isn't a file, such as a buffer, is no terminal.
+func newTermRenderer(w io.Writer) *termRenderer {
+	f, _ := w.(*os.File)
+	width := 0
+	if f != nil {
+		width = terminalWidth(f)
+	}
+	if width <= 0 {
+		width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	if width <= 0 {
+		width = 80
+	}
+	return &termRenderer{
+		w:     w,
+		width: min(width, maxWidth),
+		color: f != nil && isTerminal(f) && os.Getenv("NO_COLOR") == "",
+	}
+}
+
+// Styles of text. The terminal shows them as colors; HTML export uses
+// them as class names.
+const (
+	styleBold    = "bold"
+	styleHeading = "heading"
+	styleBanner  = "banner"
+	styleCode    = "code"
+	styleKeyword = "keyword"
+	styleString  = "string"
+	styleNumber  = "number"
+	styleComment = "comment"
+
+	// Outcomes, as of a check
+	styleOK   = "ok"
+	styleWarn = "warn"
+	styleFail = "fail"
+)
+
+var ansiStyles = map[string]string{
+	styleBold:    "1",
+	styleHeading: "1;36",
+	styleBanner:  "1;35",
+	styleCode:    "36",
+	styleKeyword: "35",
+	styleString:  "32",
+	styleNumber:  "33",
+	styleComment: "90",
+	styleOK:      "32",
+	styleWarn:    "33",
+	styleFail:    "1;31",
+}
+
+func (t *termRenderer) paint(style, s string) string {
+	if !t.color || style == "" || s == "" {
+		return s
+	}
+	return "\033[" + ansiStyles[style] + "m" + s + "\033[0m"
+}
+
+// banner prints a course's opening or closing "=== TITLE ===" line.
+func (t *termRenderer) banner(text string) {
+	fmt.Fprintln(t.w, t.paint(styleBanner, "=== "+text+" ==="))
+}
+
+// heading prints a section title underlined with dashes.
+func (t *termRenderer) heading(text string) {
+	if !t.color {
+		fmt.Fprintf(t.w, "%s\n---\n", text)
+		return
+	}
+	fmt.Fprintln(t.w, t.paint(styleHeading, text))
+	fmt.Fprintln(t.w, t.paint(styleComment, strings.Repeat("─", min(utf8.RuneCountInString(text), t.width))))
+}
+
+// markdown prints Markdown lines, without the blank lines at either end.
+func (t *termRenderer) markdown(lines []string) {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var code []string
+	lang, inFence := "", false
+	for _, line := range lines {
+		if rest, ok := strings.CutPrefix(line, "```"); ok {
+			if inFence {
+				t.code(lang, code)
+				code = code[:0]
+			}
+			lang, inFence = strings.TrimSpace(rest), !inFence
+			continue
+		}
+		if inFence {
+			code = append(code, line)
+			continue
+		}
+		if m := mdHeadingRE.FindStringSubmatch(line); m != nil {
+			fmt.Fprintln(t.w, t.paint(styleBold, m[1]))
+			continue
+		}
+		t.prose(line)
+	}
+	if inFence {
+		t.code(lang, code)
+	}
+}
+
+var mdHeadingRE = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+
+// code prints a fenced code block as it is, never wrapped, with Go
+// syntax highlighted.
+func (t *termRenderer) code(lang string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	src := strings.Join(lines, "\n")
+	if !t.color || lang != "go" {
+		fmt.Fprintln(t.w, src)
+		return
+	}
+	var b strings.Builder
+	for _, sp := range highlightGo(src) {
+		b.WriteString(t.paint(sp.style, sp.text))
+	}
+	fmt.Fprintln(t.w, b.String())
+}
+
+// highlightGo splits Go source into spans of keywords, literals, comments
+// and unstyled text in between. Lesson snippets are often fragments rather
+// than whole files, which the scanner copes with: it only needs tokens,
+// not a valid program.
+func highlightGo(src string) []span {
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(src))
+	s.Init(file, []byte(src), func(token.Position, string) {}, scanner.ScanComments)
+
+	var spans []span
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit != ";" {
+			continue // inserted at a newline, not in the source
+		}
+		start := file.Offset(pos)
+		end := start + len(lit)
+		if lit == "" {
+			end = start + len(tok.String())
+		}
+		if start < last || end > len(src) {
+			continue
+		}
+
+		var style string
+		switch {
+		case tok.IsKeyword():
+			style = styleKeyword
+		case tok == token.STRING || tok == token.CHAR:
+			style = styleString
+		case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+			style = styleNumber
+		case tok == token.COMMENT:
+			style = styleComment
+		}
+		if start > last {
+			spans = append(spans, span{src[last:start], ""})
+		}
+		spans = append(spans, span{src[start:end], style})
+		last = end
+	}
+	if last < len(src) {
+		spans = append(spans, span{src[last:], ""})
+	}
+	return spans
+}
+
+// span is a run of prose in one style.
+type span struct {
+	text  string
+	style string
+}
+
+var inlineRE = regexp.MustCompile("\\*\\*(.+?)\\*\\*|`([^`]+)`")
+
+// inlineSpans splits a line of prose into spans at **bold** and `code`,
+// without the markers.
+func inlineSpans(line string) []span {
+	var spans []span
+	last := 0
+	for _, m := range inlineRE.FindAllStringSubmatchIndex(line, -1) {
+		spans = append(spans, span{line[last:m[0]], ""})
+		if m[2] >= 0 {
+			spans = append(spans, span{line[m[2]:m[3]], styleBold})
+		} else {
+			spans = append(spans, span{line[m[4]:m[5]], styleCode})
+		}
+		last = m[1]
+	}
+	return append(spans, span{line[last:], ""})
+}
+
+var listMarkerRE = regexp.MustCompile(`^(\s*)((\d+\.|[-*•✓✗])\s+)?`)
+
+// prose prints one line of text. A line that fits is printed as written,
+// spacing and all; a longer one is wrapped at spaces, with continuation
+// lines lined up under the text of a list item.
+func (t *termRenderer) prose(line string) {
+	m := listMarkerRE.FindStringSubmatch(line)
+	lead, hang := m[0], strings.Repeat(" ", utf8.RuneCountInString(m[0]))
+	if m[2] == "" {
+		hang = m[1]
+	}
+	spans := inlineSpans(line[len(lead):])
+	if !t.color {
+		// Code keeps its backticks: in plain text they are the only thing
+		// that sets it apart
+		for i, sp := range spans {
+			if sp.style == styleCode {
+				spans[i] = span{"`" + sp.text + "`", ""}
+			}
+		}
+	}
+
+	width := utf8.RuneCountInString(lead)
+	for _, sp := range spans {
+		width += utf8.RuneCountInString(sp.text)
+	}
+	if width <= t.width {
+		var b strings.Builder
+		b.WriteString(lead)
+		for _, sp := range spans {
+			b.WriteString(t.paint(sp.style, sp.text))
+		}
+		fmt.Fprintln(t.w, b.String())
+		return
+	}
+
+	// A word may mix styles, as in "`x`," - it is never split
+	type word struct {
+		parts []span
+		width int
+	}
+	var words []word
+	var cur word
+	for _, sp := range spans {
+		for i, piece := range strings.Split(sp.text, " ") {
+			if i > 0 && cur.width > 0 {
+				words, cur = append(words, cur), word{}
+			}
+			if piece != "" {
+				cur.parts = append(cur.parts, span{piece, sp.style})
+				cur.width += utf8.RuneCountInString(piece)
+			}
+		}
+	}
+	if cur.width > 0 {
+		words = append(words, cur)
+	}
+
+	var b strings.Builder
+	b.WriteString(lead)
+	col := utf8.RuneCountInString(lead)
+	for i, w := range words {
+		if i > 0 && col+1+w.width > t.width {
+			b.WriteString("\n" + hang)
+			col = len(hang)
+		} else if i > 0 {
+			b.WriteByte(' ')
+			col++
+		}
+		for _, p := range w.parts {
+			b.WriteString(t.paint(p.style, p.text))
+		}
+		col += w.width
+	}
+	fmt.Fprintln(t.w, b.String())
+}
@@ -0,0 +1,85 @@
+package learn
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Run with: go test -run Render
+
+func renderString(width int, color bool, md string) string {
+	var buf bytes.Buffer
+	t := &termRenderer{w: &buf, width: width, color: color}
+	t.markdown(strings.Split(md, "\n"))
+	return buf.String()
+}
+
+func TestRenderWrap(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"fits", "a   b,  kept", "a   b,  kept\n"},
+		{"paragraph", "one two three four five six", "one two three\nfour five six\n"},
+		{"list item", "10. alpha beta gamma delta", "10. alpha beta\n    gamma delta\n"},
+		{"indented", "  alpha beta gamma delta", "  alpha beta\n  gamma delta\n"},
+		{"long word", "supercalifragilistic word", "supercalifragilistic\nword\n"},
+		{"code not wrapped", "```\nx := veryLongName + anotherLongName\n```", "x := veryLongName + anotherLongName\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderString(16, false, tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderInline(t *testing.T) {
+	in := "Use **errors.Is**, not `==`"
+	if got := renderString(80, false, in); got != "Use errors.Is, not `==`\n" {
+		t.Errorf("plain: got %q", got)
+	}
+	got := renderString(80, true, in)
+	if want := "Use \033[1merrors.Is\033[0m, not \033[36m==\033[0m\n"; got != want {
+		t.Errorf("color: got %q, want %q", got, want)
+	}
+
+	// Styled words wrap by their visible width, not counting escape codes
+	got = renderString(12, true, "aaaa **bbbb** cccc")
+	if strings.Count(got, "\n") != 2 {
+		t.Errorf("want two lines, got %q", got)
+	}
+}
+
+func TestRenderHighlightGo(t *testing.T) {
+	var got strings.Builder
+	for _, sp := range highlightGo("// add\nfunc add(a int) int { return a + 1 }\ns := \"hi\"") {
+		if sp.style != "" {
+			fmt.Fprintf(&got, "<%s>%s</>", sp.style, sp.text)
+		} else {
+			got.WriteString(sp.text)
+		}
+	}
+	for _, want := range []string{
+		"<comment>// add</>\n",
+		"<keyword>func</> add(a int)",
+		"<keyword>return</> a + <number>1</> }",
+		"s := <string>\"hi\"</>",
+	} {
+		if !strings.Contains(got.String(), want) {
+			t.Errorf("highlight missing %q in %q", want, got.String())
+		}
+	}
+
+	// Fragments that don't parse keep every byte
+	frag := "if err != nil {\n\t… // unicode and an unclosed brace"
+	var plain strings.Builder
+	for _, sp := range highlightGo(frag) {
+		plain.WriteString(sp.text)
+	}
+	if plain.String() != frag {
+		t.Errorf("highlighting changed the text: %q", plain.String())
+	}
+}
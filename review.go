@@ -0,0 +1,235 @@
+package learn
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// runReview implements "go run ./cmd/learn review [flags] [course...]".
+func runReview(args []string) error {
+	flags := flag.NewFlagSet("review", flag.ContinueOnError)
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file holding the review schedule")
+	newCards := flags.Int("new", 10, "most new cards to add in one session")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn review [flags] [course...|all]")
+		fmt.Fprintln(flags.Output(), "Reviews the key takeaways that are due as flashcards. Courses named on the")
+		fmt.Fprintln(flags.Output(), "command line add their cards to the deck.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var from []course
+	for _, arg := range flags.Args() {
+		more, err := courseArg(arg)
+		if err != nil {
+			return err
+		}
+		from = append(from, more...)
+	}
+
+	p, err := loadProgress(*progressPath)
+	if err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	s := &reviewSession{
+		in:    bufio.NewReader(os.Stdin),
+		out:   newTermRenderer(os.Stdout),
+		p:     p,
+		today: time.Now(),
+		save: func(id string, st cardState) error {
+			_, err := updateProgress(*progressPath, func(p *progress) error {
+				p.Review[id] = st
+				return nil
+			})
+			return err
+		},
+	}
+	cards, err := s.deck(from, *newCards)
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		fmt.Println("Nothing to review today. Add a course's takeaways with: go run ./cmd/learn review <course>")
+		return nil
+	}
+	return s.run(cards)
+}
+
+// flashcard is a key takeaway to recall: the front is its beginning, the
+// back the whole sentence.
+type flashcard struct {
+	id          string // course number and a hash of the text, so edits make a new card
+	course      course
+	front, back string
+}
+
+// courseFlashcards turns a course's key takeaways into flashcards.
+func courseFlashcards(c course) ([]flashcard, error) {
+	l, err := loadLesson(c.number)
+	if err != nil {
+		return nil, err
+	}
+	var cards []flashcard
+	for _, t := range l.Takeaways {
+		sum := sha1.Sum([]byte(t))
+		cards = append(cards, flashcard{
+			id:     fmt.Sprintf("%d/%x", c.number, sum[:4]),
+			course: c,
+			front:  cardFront(t),
+			back:   t,
+		})
+	}
+	return cards, nil
+}
+
+// cardFront hides the end of a takeaway. It cuts where the sentence
+// turns ("Close a closed channel = panic", "Goroutines are lightweight -
+// you can have thousands"), or else after about half of the words.
+func cardFront(t string) string {
+	for _, sep := range []string{" - ", ": ", " = ", "; "} {
+		if i := strings.Index(t, sep); i > 0 && i < len(t)-len(sep)-1 {
+			return t[:i+len(sep)] + "..."
+		}
+	}
+	words := strings.Fields(t)
+	keep := max(2, (len(words)+1)/2)
+	if keep >= len(words) {
+		keep = len(words) - 1
+	}
+	return strings.Join(words[:keep], " ") + " ..."
+}
+
+// cardState is a card's place in the SM-2 spaced repetition schedule.
+type cardState struct {
+	Ease     float64 `json:"ease"`
+	Interval int     `json:"interval_days"`
+	Reps     int     `json:"reps"` // correct reviews in a row
+	Due      string  `json:"due"`  // YYYY-MM-DD
+}
+
+const dateLayout = "2006-01-02"
+
+// schedule applies one review graded 0 (blackout) to 5 (perfect), as in
+// SM-2: a lapse starts the card over, a success multiplies the interval
+// by the ease, and the ease follows how hard recalling was.
+func (s cardState) schedule(grade int, today time.Time) cardState {
+	if s.Ease == 0 {
+		s.Ease = 2.5
+	}
+	if grade < 3 {
+		s.Reps, s.Interval = 0, 1
+	} else {
+		switch s.Reps {
+		case 0:
+			s.Interval = 1
+		case 1:
+			s.Interval = 6
+		default:
+			s.Interval = int(math.Round(float64(s.Interval) * s.Ease))
+		}
+		s.Reps++
+	}
+	q := float64(5 - grade)
+	s.Ease = max(1.3, s.Ease+0.1-q*(0.08+q*0.02))
+	s.Due = today.AddDate(0, 0, s.Interval).Format(dateLayout)
+	return s
+}
+
+// reviewSession asks the cards one by one and records the answers.
+type reviewSession struct {
+	in    *bufio.Reader
+	out   *termRenderer
+	p     *progress
+	today time.Time
+	save  func(id string, st cardState) error // records one card's new schedule
+}
+
+// deck is what to review today: the cards that are due, oldest first,
+// then up to limit cards not seen yet from the given courses.
+func (s *reviewSession) deck(from []course, limit int) ([]flashcard, error) {
+	all := make(map[int][]flashcard)
+	var due, fresh []flashcard
+	today := s.today.Format(dateLayout)
+	for _, c := range courses {
+		cards, err := courseFlashcards(c)
+		if err != nil {
+			return nil, err
+		}
+		all[c.number] = cards
+		for _, card := range cards {
+			if st, ok := s.p.Review[card.id]; ok && st.Due <= today {
+				due = append(due, card)
+			}
+		}
+	}
+	for _, c := range from {
+		for _, card := range all[c.number] {
+			if _, ok := s.p.Review[card.id]; !ok && len(fresh) < limit {
+				fresh = append(fresh, card)
+			}
+		}
+	}
+	// Stable, so cards due the same day stay in course order
+	slices.SortStableFunc(due, func(a, b flashcard) int {
+		return strings.Compare(s.p.Review[a.id].Due, s.p.Review[b.id].Due)
+	})
+	return append(due, fresh...), nil
+}
+
+// grades maps the answer keys to SM-2 grades.
+var grades = map[string]int{"1": 1, "2": 3, "3": 4, "4": 5}
+
+func (s *reviewSession) run(cards []flashcard) error {
+	done := 0
+	for i, card := range cards {
+		fmt.Fprintln(s.out.w)
+		fmt.Fprintln(s.out.w, s.out.paint(styleComment, fmt.Sprintf("[%d/%d] course %d: %s", i+1, len(cards), card.course.number, card.course.name)))
+		s.out.prose("**" + card.front + "**")
+		fmt.Fprint(s.out.w, s.out.paint(styleComment, "(Enter to show the answer) "))
+		if _, err := s.in.ReadString('\n'); err != nil {
+			break
+		}
+		s.out.prose(card.back)
+
+		grade, ok := s.ask()
+		if !ok {
+			break
+		}
+		st := s.p.Review[card.id].schedule(grade, s.today)
+		s.p.Review[card.id] = st
+		if err := s.save(card.id, st); err != nil {
+			return err
+		}
+		done++
+		fmt.Fprintln(s.out.w, s.out.paint(styleComment, "next review: "+st.Due))
+	}
+	fmt.Fprintf(s.out.w, "\nReviewed %s.\n", plural(done, "card", "cards"))
+	return nil
+}
+
+// ask reads how well the card was remembered. It reports false when the
+// learner stops or the input ends.
+func (s *reviewSession) ask() (int, bool) {
+	for {
+		fmt.Fprint(s.out.w, "How well did you remember? 1 again, 2 hard, 3 good, 4 easy (q to stop): ")
+		line, err := s.in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if grade, ok := grades[answer]; ok {
+			return grade, true
+		}
+		if answer == "q" || errors.Is(err, io.EOF) {
+			return 0, false
+		}
+	}
+}
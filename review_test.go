@@ -0,0 +1,103 @@
+package learn
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReviewSchedule(t *testing.T) {
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	var s cardState
+	for _, want := range []struct {
+		grade, interval int
+		due             string
+	}{
+		{4, 1, "2024-03-02"},
+		{4, 6, "2024-03-07"},
+		{4, 15, "2024-03-16"},
+		{1, 1, "2024-03-02"}, // a lapse starts over
+		{4, 1, "2024-03-02"},
+	} {
+		s = s.schedule(want.grade, day)
+		if s.Interval != want.interval || s.Due != want.due {
+			t.Errorf("after grade %d: %+v, want interval %d due %s", want.grade, s, want.interval, want.due)
+		}
+	}
+	if s.Ease >= 2.5 {
+		t.Errorf("a lapse should lower the ease, got %v", s.Ease)
+	}
+	for range 10 {
+		s = s.schedule(0, day)
+	}
+	if s.Ease != 1.3 {
+		t.Errorf("ease should bottom out at 1.3, got %v", s.Ease)
+	}
+}
+
+func TestCardFront(t *testing.T) {
+	tests := []struct{ takeaway, want string }{
+		{"Goroutines are lightweight - you can have thousands", "Goroutines are lightweight - ..."},
+		{"Send to channel: ch <- value", "Send to channel: ..."},
+		{"Close a closed channel = panic", "Close a closed channel = ..."},
+		{"Maps are unordered key-value stores", "Maps are unordered ..."},
+		{"Test edge cases", "Test edge ..."},
+	}
+	for _, tt := range tests {
+		if got := cardFront(tt.takeaway); got != tt.want {
+			t.Errorf("cardFront(%q) = %q, want %q", tt.takeaway, got, tt.want)
+		}
+	}
+}
+
+func TestReviewSession(t *testing.T) {
+	today := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	c4, _ := findCourse(4)
+	cards4, err := courseFlashcards(c4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1, _ := findCourse(1)
+	cards1, _ := courseFlashcards(c1)
+
+	// One card from course 1 is due, one isn't yet
+	p := &progress{Review: map[string]cardState{
+		cards1[0].id: {Ease: 2.5, Interval: 6, Reps: 2, Due: "2024-03-01"},
+		cards1[1].id: {Ease: 2.5, Interval: 6, Reps: 2, Due: "2024-03-05"},
+	}}
+	var out bytes.Buffer
+	saves := 0
+	s := &reviewSession{
+		in:    bufio.NewReader(strings.NewReader("\n4\n\nx\n1\n\nq\n")),
+		out:   &termRenderer{w: &out, width: 80},
+		p:     p,
+		today: today,
+		save:  func(string, cardState) error { saves++; return nil },
+	}
+
+	deck, err := s.deck([]course{c4}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deck) != 4 || deck[0].id != cards1[0].id || deck[1].id != cards4[0].id {
+		t.Fatalf("deck: want the due card then 3 new ones, got %d cards", len(deck))
+	}
+
+	if err := s.run(deck); err != nil {
+		t.Fatal(err)
+	}
+	if saves != 2 || !strings.HasSuffix(out.String(), "Reviewed 2 cards.\n") {
+		t.Errorf("saves=%d, output:\n%s", saves, out.String())
+	}
+	if got := p.Review[cards1[0].id]; got.Interval != 15 || got.Due != "2024-03-16" {
+		t.Errorf("easy review of a known card: %+v", got)
+	}
+	if got := p.Review[cards4[0].id]; got.Reps != 0 || got.Due != "2024-03-02" {
+		t.Errorf("failed new card: %+v", got)
+	}
+	if _, ok := p.Review[cards4[1].id]; ok {
+		t.Error("the card the learner quit on should stay new")
+	}
+}
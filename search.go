@@ -0,0 +1,176 @@
+package learn
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// runSearch implements "go run ./cmd/learn search [flags] <term>".
+func runSearch(args []string) error {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	only := flags.Int("course", 0, "search only this course")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn search [flags] <term>")
+		fmt.Fprintln(flags.Output(), "Searches the lessons, key takeaways and course code. Case doesn't matter;")
+		fmt.Fprintln(flags.Output(), "several words are searched for as a phrase.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	query := strings.Join(flags.Args(), " ")
+	if strings.TrimSpace(query) == "" {
+		flags.Usage()
+		return errors.New("nothing to search for")
+	}
+
+	var selected []course
+	for _, c := range courses {
+		if *only == 0 || c.number == *only {
+			selected = append(selected, c)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("course %d does not exist (available: 1-%d)", *only, len(courses))
+	}
+
+	hits, err := searchCourses(selected, query)
+	if err != nil {
+		return err
+	}
+	printHits(newTermRenderer(os.Stdout), hits, query)
+	return nil
+}
+
+// searchHit is one line that matched.
+type searchHit struct {
+	course course
+	where  string // "3. PREPARED STATEMENTS", "takeaway 4", "07-sql-database.go:88"
+	line   string
+}
+
+// searchCourses finds the lines of the lessons, takeaways and course code
+// of the given courses that contain query, ignoring case.
+func searchCourses(selected []course, query string) ([]searchHit, error) {
+	query = strings.ToLower(query)
+	match := func(line string) bool { return strings.Contains(strings.ToLower(line), query) }
+
+	var hits []searchHit
+	for _, c := range selected {
+		l, err := loadLesson(c.number)
+		if err != nil {
+			return nil, err
+		}
+		for i, s := range l.Sections {
+			where := s.Title
+			if i == 0 {
+				where = "introduction"
+			}
+			for _, line := range joinParts(s.Parts) {
+				if match(line) {
+					hits = append(hits, searchHit{c, where, line})
+				}
+			}
+		}
+		for i, t := range l.Takeaways {
+			if match(t) {
+				hits = append(hits, searchHit{c, fmt.Sprintf("takeaway %d", i+1), t})
+			}
+		}
+
+		src, err := courseSources.ReadFile(c.source())
+		if err != nil {
+			return nil, err
+		}
+		for i, line := range strings.Split(string(src), "\n") {
+			if match(line) {
+				hits = append(hits, searchHit{c, fmt.Sprintf("%s:%d", c.source(), i+1), line})
+			}
+		}
+	}
+	return hits, nil
+}
+
+// printHits prints the hits grouped by course, each with a snippet of its
+// line around the match.
+func printHits(out *termRenderer, hits []searchHit, query string) {
+	if len(hits) == 0 {
+		fmt.Fprintf(out.w, "No matches for %q.\n", query)
+		return
+	}
+	var last, count int
+	for _, h := range hits {
+		if h.course.number != last {
+			if last != 0 {
+				fmt.Fprintln(out.w)
+			}
+			fmt.Fprintln(out.w, out.paint(styleHeading, fmt.Sprintf("%d. %s", h.course.number, h.course.name)))
+			last = h.course.number
+			count++
+		}
+		fmt.Fprintf(out.w, "  %s\n      %s\n", out.paint(styleComment, h.where), snippet(out, h.line, query, out.width-6))
+	}
+	fmt.Fprintf(out.w, "\n%s in %s. Run one with: go run ./cmd/learn <number>\n", plural(len(hits), "match", "matches"), plural(count, "course", "courses"))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// snippet trims line to at most width runes around the first match of
+// query, marking the match.
+func snippet(out *termRenderer, line, query string, width int) string {
+	line = strings.Join(strings.Fields(line), " ")
+	i := strings.Index(strings.ToLower(line), strings.ToLower(query))
+	if i < 0 || i+len(query) > len(line) {
+		return line
+	}
+	before, match, after := line[:i], line[i:i+len(query)], line[i+len(query):]
+
+	// Keep some text on both sides of the match, cutting at word breaks
+	room := width - utf8.RuneCountInString(match)
+	if n := utf8.RuneCountInString(before); n > room/3 && n+utf8.RuneCountInString(after) > room {
+		keep := max(room/3, room-utf8.RuneCountInString(after))
+		before = "..." + trimStart(before, keep-3)
+	}
+	if n := utf8.RuneCountInString(before) + utf8.RuneCountInString(after); n > room {
+		after = trimEnd(after, room-utf8.RuneCountInString(before)-3) + "..."
+	}
+	if !out.color {
+		match = "[" + match + "]"
+	}
+	return before + out.paint(styleBold, match) + after
+}
+
+// trimStart keeps the last n runes of s, starting at a word if it can.
+func trimStart(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	s = string(r[len(r)-max(n, 0):])
+	if i := strings.IndexByte(s, ' '); i >= 0 && i < len(s)/2 {
+		s = s[i+1:]
+	}
+	return s
+}
+
+// trimEnd keeps the first n runes of s, ending at a word if it can.
+func trimEnd(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	s = string(r[:max(n, 0)])
+	if i := strings.LastIndexByte(s, ' '); i > len(s)/2 {
+		s = s[:i]
+	}
+	return s
+}
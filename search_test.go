@@ -0,0 +1,55 @@
+package learn
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSearchCourses(t *testing.T) {
+	hits, err := searchCourses(courses, "Prepared Statement")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var where []string
+	for _, h := range hits {
+		if h.course.number != 7 {
+			t.Errorf("unexpected hit in course %d: %s", h.course.number, h.line)
+		}
+		where = append(where, h.where)
+	}
+	got := strings.Join(where, ", ")
+	for _, want := range []string{"takeaway 3", "07-sql-database.go:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("hits %q: want one in %q", got, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	printHits(&termRenderer{w: &buf, width: 80}, hits[:1], "prepared statement")
+	if !strings.Contains(buf.String(), "[prepared statement]") || !strings.HasSuffix(buf.String(), "1 match in 1 course. Run one with: go run ./cmd/learn <number>\n") {
+		t.Errorf("printed:\n%s", buf.String())
+	}
+}
+
+func TestSearchSnippet(t *testing.T) {
+	out := &termRenderer{width: 80}
+	long := strings.Repeat("words before the match ", 10) + "needle" + strings.Repeat(" and words after it", 10)
+	tests := []struct {
+		line, want string
+	}{
+		{"  a short   needle line ", "a short [needle] line"},
+		{"no match here", "no match here"},
+	}
+	for _, tt := range tests {
+		if got := snippet(out, tt.line, "NEEDLE", 40); got != tt.want {
+			t.Errorf("snippet(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	got := snippet(out, long, "needle", 40)
+	if n := utf8.RuneCountInString(got); n > 42 || !strings.HasPrefix(got, "...") || !strings.HasSuffix(got, "...") || !strings.Contains(got, "[needle]") {
+		t.Errorf("long line: %q (%d runes)", got, n)
+	}
+}
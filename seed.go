@@ -0,0 +1,402 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// seedData is a small made-up shop for the database courses to query:
+// users, the products they buy and their orders.
+type seedData struct {
+	Users    []seedUser
+	Products []seedProduct
+	Orders   []seedOrder
+}
+
+type seedUser struct {
+	ID     int
+	Name   string
+	Email  string
+	Age    int
+	City   string
+	Joined time.Time
+}
+
+type seedProduct struct {
+	ID       int
+	Name     string
+	Category string
+	Price    int // in cents
+	Stock    int
+	Tags     []string
+	Added    time.Time
+}
+
+type seedOrder struct {
+	ID        int
+	UserID    int
+	ProductID int
+	Quantity  int
+	Status    string
+	Ordered   time.Time
+}
+
+// seedCounts is how many rows of each to make.
+type seedCounts struct {
+	users, products, orders int
+}
+
+var (
+	seedFirstNames = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Ken", "Barbara", "Dennis", "Frances", "Rob",
+		"Radia", "Robert", "Hedy", "Brian", "Karen", "Niklaus", "Joan", "Edsger", "Shafi", "Tim"}
+	seedLastNames = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Thompson", "Liskov", "Ritchie",
+		"Allen", "Pike", "Perlman", "Griesemer", "Lamarr", "Kernighan", "Jones", "Wirth", "Clarke", "Dijkstra",
+		"Goldwasser", "O'Brien"}
+	seedCities = []string{"Lagos", "London", "Berlin", "Toronto", "Nairobi", "Sydney", "Austin", "Tokyo",
+		"Accra", "Lisbon", "Bangalore", "São Paulo"}
+
+	// seedCatalog is, for each category, the things in it and a price
+	// range in dollars.
+	seedCatalog = []struct {
+		category string
+		items    []string
+		low, top int
+	}{
+		{"electronics", []string{"Laptop", "Monitor", "Headphones", "Keyboard", "Webcam", "Phone"}, 25, 1500},
+		{"books", []string{"Go Programming", "Database Design", "Clean Code", "Algorithms", "Networking"}, 15, 60},
+		{"home", []string{"Desk Lamp", "Office Chair", "Coffee Mug", "Bookshelf", "Plant Pot"}, 8, 350},
+		{"sports", []string{"Running Shoes", "Yoga Mat", "Water Bottle", "Bike Helmet", "Backpack"}, 10, 180},
+		{"toys", []string{"Gopher Plush", "Puzzle", "Board Game", "Kite", "Building Blocks"}, 5, 90},
+	}
+	seedAdjectives = []string{"Classic", "Pro", "Compact", "Deluxe", "Eco", "Smart", "Mini", "Ultra"}
+	seedTags       = []string{"new", "sale", "popular", "limited", "gift"}
+
+	// seedStatuses are the states of an order, most of them delivered.
+	seedStatuses = []string{"delivered", "delivered", "delivered", "delivered", "delivered", "delivered",
+		"shipped", "shipped", "pending", "cancelled"}
+)
+
+// generateSeed makes the data. The same seed and day always make the same
+// data; the dates are in the year up to now.
+func generateSeed(counts seedCounts, seed uint64, now time.Time) *seedData {
+	r := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	day := now.UTC().Truncate(24 * time.Hour)
+	past := func(days int) time.Time {
+		return day.Add(-time.Duration(r.IntN(days*24*60)) * time.Minute)
+	}
+
+	d := &seedData{}
+	emails := map[string]int{}
+	for id := 1; id <= counts.users; id++ {
+		first := seedFirstNames[r.IntN(len(seedFirstNames))]
+		last := seedLastNames[r.IntN(len(seedLastNames))]
+		local := strings.ToLower(first + "." + strings.ReplaceAll(last, "'", ""))
+		emails[local]++
+		if n := emails[local]; n > 1 {
+			local += fmt.Sprint(n)
+		}
+		d.Users = append(d.Users, seedUser{
+			ID:     id,
+			Name:   first + " " + last,
+			Email:  local + "@example.com",
+			Age:    18 + r.IntN(50),
+			City:   seedCities[r.IntN(len(seedCities))],
+			Joined: past(365),
+		})
+	}
+
+	for id := 1; id <= counts.products; id++ {
+		c := seedCatalog[r.IntN(len(seedCatalog))]
+		p := seedProduct{
+			ID:       id,
+			Name:     seedAdjectives[r.IntN(len(seedAdjectives))] + " " + c.items[r.IntN(len(c.items))],
+			Category: c.category,
+			// Prices end in .99 or .49, as in a shop
+			Price: (c.low+r.IntN(c.top-c.low+1))*100 - []int{1, 51}[r.IntN(2)],
+			Added: past(365),
+		}
+		if r.IntN(5) > 0 {
+			p.Stock = r.IntN(200) + 1
+		}
+		for _, tag := range seedTags {
+			if r.IntN(4) == 0 {
+				p.Tags = append(p.Tags, tag)
+			}
+		}
+		d.Products = append(d.Products, p)
+	}
+
+	// A few users and products get most of the orders, so that grouping
+	// and sorting them shows something
+	skewed := func(n int) int { return 1 + min(r.IntN(n), r.IntN(n)) }
+	for id := 1; id <= counts.orders && counts.users > 0 && counts.products > 0; id++ {
+		d.Orders = append(d.Orders, seedOrder{
+			ID:        id,
+			UserID:    skewed(counts.users),
+			ProductID: skewed(counts.products),
+			Quantity:  1 + r.IntN(3)*r.IntN(2),
+			Status:    seedStatuses[r.IntN(len(seedStatuses))],
+			Ordered:   past(180),
+		})
+	}
+	return d
+}
+
+// dollars formats cents as 12.99.
+func dollars(cents int) string {
+	return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+}
+
+const seedTimeLayout = "2006-01-02 15:04:05"
+
+// sqlString quotes s for PostgreSQL or MySQL.
+func sqlString(s, dialect string) string {
+	if dialect == "mysql" {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// writeSeedSQL writes a script that recreates the users, products and
+// orders tables, for dialect "postgres" or "mysql". Ids continue after the
+// seeded rows, so the course's INSERTs without one still work.
+func writeSeedSQL(w io.Writer, d *seedData, dialect string) {
+	id := "INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY"
+	if dialect == "mysql" {
+		id = "INT AUTO_INCREMENT PRIMARY KEY"
+	}
+	fmt.Fprintln(w, "-- Made by: go run ./cmd/learn seed. Running it again starts over.")
+	fmt.Fprintln(w, "DROP TABLE IF EXISTS orders;\nDROP TABLE IF EXISTS products;\nDROP TABLE IF EXISTS users;")
+	fmt.Fprintf(w, `
+CREATE TABLE users (
+    id %[1]s,
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    age INT NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE products (
+    id %[1]s,
+    name VARCHAR(100) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    price NUMERIC(10, 2) NOT NULL,
+    stock INT NOT NULL
+);
+
+CREATE TABLE orders (
+    id %[1]s,
+    user_id INT NOT NULL REFERENCES users (id),
+    product_id INT NOT NULL REFERENCES products (id),
+    quantity INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    ordered_at TIMESTAMP NOT NULL
+);
+`, id)
+
+	insert := func(table, columns string, rows []string) {
+		for len(rows) > 0 {
+			n := min(len(rows), 100)
+			fmt.Fprintf(w, "\nINSERT INTO %s (%s) VALUES\n    %s;\n", table, columns, strings.Join(rows[:n], ",\n    "))
+			rows = rows[n:]
+		}
+	}
+	var rows []string
+	for _, u := range d.Users {
+		rows = append(rows, fmt.Sprintf("(%d, %s, %s, %d, %s, '%s')", u.ID, sqlString(u.Name, dialect),
+			sqlString(u.Email, dialect), u.Age, sqlString(u.City, dialect), u.Joined.Format(seedTimeLayout)))
+	}
+	insert("users", "id, name, email, age, city, created_at", rows)
+	rows = nil
+	for _, p := range d.Products {
+		rows = append(rows, fmt.Sprintf("(%d, %s, %s, %s, %d)", p.ID, sqlString(p.Name, dialect),
+			sqlString(p.Category, dialect), dollars(p.Price), p.Stock))
+	}
+	insert("products", "id, name, category, price, stock", rows)
+	rows = nil
+	for _, o := range d.Orders {
+		rows = append(rows, fmt.Sprintf("(%d, %d, %d, %d, '%s', '%s')", o.ID, o.UserID, o.ProductID, o.Quantity,
+			o.Status, o.Ordered.Format(seedTimeLayout)))
+	}
+	insert("orders", "id, user_id, product_id, quantity, status, ordered_at", rows)
+
+	if dialect == "postgres" {
+		fmt.Fprintln(w)
+		for _, table := range []string{"users", "products", "orders"} {
+			fmt.Fprintf(w, "SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), (SELECT COALESCE(MAX(id), 0) + 1 FROM %[1]s), false);\n", table)
+		}
+	}
+}
+
+// jsString quotes s for JavaScript.
+func jsString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`).Replace(s) + "'"
+}
+
+// writeSeedMongo writes a mongosh script that recreates the users,
+// products and orders collections of the learn database. Products have the
+// fields of course 8's Product; orders carry the product's name, category
+// and price, as documents do rather than joining.
+func writeSeedMongo(w io.Writer, d *seedData) {
+	date := func(t time.Time) string { return "ISODate('" + t.Format(time.RFC3339) + "')" }
+	fmt.Fprintln(w, "// Made by: go run ./cmd/learn seed. Running it again starts over.")
+	fmt.Fprintln(w, "db = db.getSiblingDB('learn');")
+	fmt.Fprintln(w, "db.users.drop();\ndb.products.drop();\ndb.orders.drop();")
+
+	insert := func(collection string, docs []string) {
+		fmt.Fprintf(w, "\ndb.%s.insertMany([\n  %s\n]);\n", collection, strings.Join(docs, ",\n  "))
+	}
+	var docs []string
+	for _, u := range d.Users {
+		docs = append(docs, fmt.Sprintf("{_id: 'u%d', name: %s, email: %s, age: %d, city: %s, createdAt: %s}",
+			u.ID, jsString(u.Name), jsString(u.Email), u.Age, jsString(u.City), date(u.Joined)))
+	}
+	insert("users", docs)
+	docs = nil
+	for _, p := range d.Products {
+		tags := make([]string, len(p.Tags))
+		for i, t := range p.Tags {
+			tags[i] = jsString(t)
+		}
+		docs = append(docs, fmt.Sprintf("{_id: 'p%d', name: %s, price: %s, category: %s, inStock: %t, stock: %d, tags: [%s], createdAt: %s}",
+			p.ID, jsString(p.Name), dollars(p.Price), jsString(p.Category), p.Stock > 0, p.Stock, strings.Join(tags, ", "), date(p.Added)))
+	}
+	insert("products", docs)
+	docs = nil
+	for _, o := range d.Orders {
+		p := d.Products[o.ProductID-1]
+		docs = append(docs, fmt.Sprintf("{_id: 'o%d', userId: 'u%d', productId: 'p%d', product: %s, category: %s, price: %s, quantity: %d, status: '%s', orderedAt: %s}",
+			o.ID, o.UserID, o.ProductID, jsString(p.Name), jsString(p.Category), dollars(p.Price), o.Quantity, o.Status, date(o.Ordered)))
+	}
+	insert("orders", docs)
+
+	fmt.Fprintln(w, "\ndb.products.createIndex({category: 1, price: -1});")
+	fmt.Fprintln(w, "db.orders.createIndex({userId: 1});")
+}
+
+// redisString quotes s for redis-cli.
+func redisString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// writeSeedRedis writes redis-cli commands: a hash per user and product,
+// the products of each category as a set, the users ranked by what they
+// spent in a sorted set, and the latest orders in a list.
+func writeSeedRedis(w io.Writer, d *seedData) {
+	// Start over, including the keys of a bigger seeding before
+	fmt.Fprintln(w, "DEL leaderboard orders:recent")
+	for _, pattern := range []string{"user:*", "product:*", "category:*"} {
+		fmt.Fprintf(w, "EVAL \"for _, k in ipairs(redis.call('KEYS', ARGV[1])) do redis.call('DEL', k) end\" 0 %s\n", pattern)
+	}
+	for _, u := range d.Users {
+		fmt.Fprintf(w, "HSET user:%d name %s email %s age %d city %s\n", u.ID, redisString(u.Name), redisString(u.Email), u.Age, redisString(u.City))
+	}
+	for _, p := range d.Products {
+		fmt.Fprintf(w, "HSET product:%d name %s category %s price %s stock %d\n", p.ID, redisString(p.Name), p.Category, dollars(p.Price), p.Stock)
+		fmt.Fprintf(w, "SADD category:%s product:%d\n", p.Category, p.ID)
+	}
+	spent := map[int]int{}
+	for _, o := range d.Orders {
+		if o.Status != "cancelled" {
+			spent[o.UserID] += d.Products[o.ProductID-1].Price * o.Quantity
+		}
+	}
+	for _, u := range d.Users {
+		if spent[u.ID] > 0 {
+			fmt.Fprintf(w, "ZADD leaderboard %s user:%d\n", dollars(spent[u.ID]), u.ID)
+		}
+	}
+	for _, o := range d.Orders[max(len(d.Orders)-20, 0):] {
+		fmt.Fprintf(w, "LPUSH orders:recent %s\n", redisString(fmt.Sprintf("order:%d user:%d product:%d x%d %s", o.ID, o.UserID, o.ProductID, o.Quantity, o.Status)))
+	}
+}
+
+// seeder loads the data into one of the databases: the script to write,
+// and the client in the container that runs it.
+type seeder struct {
+	file   string
+	write  func(w io.Writer, d *seedData)
+	client []string
+}
+
+var seeders = map[string]seeder{
+	"postgres": {"postgres.sql", func(w io.Writer, d *seedData) { writeSeedSQL(w, d, "postgres") },
+		[]string{"psql", "-q", "-v", "ON_ERROR_STOP=1", "-U", "learn", "-d", "learn"}},
+	"mysql": {"mysql.sql", func(w io.Writer, d *seedData) { writeSeedSQL(w, d, "mysql") },
+		[]string{"mysql", "-ulearn", "-plearn", "learn"}},
+	"mongo": {"mongo.js", writeSeedMongo, []string{"mongosh", "--quiet"}},
+	"redis": {"redis.txt", writeSeedRedis, []string{"redis-cli"}},
+}
+
+// runSeed implements "go run ./cmd/learn seed [flags] [database...]".
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	users := flags.Int("users", 50, "number of users")
+	products := flags.Int("products", 40, "number of products")
+	orders := flags.Int("orders", 300, "number of orders")
+	seed := flags.Uint64("seed", 1, "random seed: the same seed makes the same data")
+	out := flags.String("out", "", "write the scripts into this folder instead of loading them")
+	timeout := flags.Duration("timeout", 2*time.Minute, "time limit for loading")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn seed [flags] [database...]")
+		fmt.Fprintln(flags.Output(), "Fills the databases started by go run ./cmd/learn env up with made-up users, products and orders.")
+		fmt.Fprintln(flags.Output(), "Databases:", strings.Join(databaseNames(), ", "), "(default all). Running it again starts over.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *users < 1 || *products < 1 || *orders < 0 {
+		return errors.New("seed needs at least one user and one product, and no negative counts")
+	}
+	dbs, err := databaseArgs(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	d := generateSeed(seedCounts{*users, *products, *orders}, *seed, time.Now())
+	summary := fmt.Sprintf("%s, %s and %s", plural(len(d.Users), "user", "users"),
+		plural(len(d.Products), "product", "products"), plural(len(d.Orders), "order", "orders"))
+	if *out != "" {
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			return err
+		}
+		for _, db := range dbs {
+			s := seeders[db.name]
+			path := filepath.Join(*out, s.file)
+			if err := writeFileWith(path, func(w io.Writer) error { s.write(w, d); return nil }); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s for %s\n", path, db.title)
+		}
+		return nil
+	}
+
+	if _, err := exec.LookPath("docker"); err != nil {
+		return errors.New("docker isn't installed: write the scripts with -out instead, or see go run ./cmd/learn doctor")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	for _, db := range dbs {
+		s := seeders[db.name]
+		var script bytes.Buffer
+		s.write(&script, d)
+		cmd := append([]string{"exec", "-T", db.name}, s.client...)
+		if err := dockerCompose(ctx, &script, io.Discard, cmd...); err != nil {
+			return fmt.Errorf("seeding %s (is it running? go run ./cmd/learn env up %s): %w", db.title, db.name, err)
+		}
+		fmt.Printf("Seeded %s with %s\n", db.title, summary)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package learn
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSeed(t *testing.T) {
+	now := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	counts := seedCounts{users: 30, products: 20, orders: 200}
+	d := generateSeed(counts, 7, now)
+	if len(d.Users) != 30 || len(d.Products) != 20 || len(d.Orders) != 200 {
+		t.Fatalf("made %d users, %d products, %d orders", len(d.Users), len(d.Products), len(d.Orders))
+	}
+	if again := generateSeed(counts, 7, now.Add(time.Hour)); !reflect.DeepEqual(d, again) {
+		t.Error("the same seed on the same day made different data")
+	}
+	if other := generateSeed(counts, 8, now); reflect.DeepEqual(d, other) {
+		t.Error("another seed made the same data")
+	}
+
+	emails := map[string]bool{}
+	for _, u := range d.Users {
+		if emails[u.Email] {
+			t.Errorf("email %s is used twice", u.Email)
+		}
+		emails[u.Email] = true
+	}
+	perUser := map[int]int{}
+	for _, o := range d.Orders {
+		if o.UserID < 1 || o.UserID > 30 || o.ProductID < 1 || o.ProductID > 20 {
+			t.Fatalf("order %d refers to user %d, product %d", o.ID, o.UserID, o.ProductID)
+		}
+		if o.Ordered.After(now) || o.Ordered.Before(now.AddDate(0, 0, -181)) {
+			t.Errorf("order %d is dated %v", o.ID, o.Ordered)
+		}
+		perUser[o.UserID]++
+	}
+	// Skewed: the first users order far more than the last
+	if perUser[1] <= perUser[30]*2 {
+		t.Errorf("user 1 has %d orders and user 30 %d: want a few big customers", perUser[1], perUser[30])
+	}
+}
+
+func TestSeedScripts(t *testing.T) {
+	d := &seedData{
+		Users:    []seedUser{{ID: 1, Name: "Tim O'Brien", Email: "tim.obrien@example.com", Age: 30, City: "Lagos"}},
+		Products: []seedProduct{{ID: 1, Name: "Pro Laptop", Category: "electronics", Price: 99999, Stock: 3, Tags: []string{"new"}}},
+		Orders:   []seedOrder{{ID: 1, UserID: 1, ProductID: 1, Quantity: 2, Status: "delivered"}},
+	}
+	tests := []struct {
+		name  string
+		wants []string
+	}{
+		{"postgres", []string{
+			"id INTEGER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY",
+			"(1, 'Tim O''Brien', 'tim.obrien@example.com', 30, 'Lagos', '0001-01-01 00:00:00')",
+			"(1, 'Pro Laptop', 'electronics', 999.99, 3)",
+			"SELECT setval(pg_get_serial_sequence('orders', 'id')",
+		}},
+		{"mysql", []string{"id INT AUTO_INCREMENT PRIMARY KEY", "INSERT INTO orders (id, user_id, product_id, quantity, status, ordered_at) VALUES\n    (1, 1, 1, 2, 'delivered',"}},
+		{"mongo", []string{
+			"{_id: 'u1', name: 'Tim O\\'Brien',",
+			"price: 999.99, category: 'electronics', inStock: true, stock: 3, tags: ['new']",
+			"{_id: 'o1', userId: 'u1', productId: 'p1', product: 'Pro Laptop',",
+		}},
+		{"redis", []string{
+			`HSET user:1 name "Tim O'Brien" email "tim.obrien@example.com" age 30 city "Lagos"`,
+			"SADD category:electronics product:1\n",
+			"ZADD leaderboard 1999.98 user:1\n",
+		}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		seeders[tt.name].write(&buf, d)
+		for _, want := range tt.wants {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("%s script missing %q:\n%s", tt.name, want, buf.String())
+			}
+		}
+	}
+	for _, db := range courseDatabases {
+		if _, ok := seeders[db.name]; !ok {
+			t.Errorf("no seeder for %s", db.name)
+		}
+	}
+}
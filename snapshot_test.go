@@ -0,0 +1,125 @@
+package learn
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/fileio"
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+var update = flag.Bool("update", false, "rewrite the snapshots in testdata/snapshots")
+
+// snapshotScrubs replace what changes from run to run or machine to
+// machine: file times, course 19's racy counter, the load test timings of
+// courses 19 and 23, course 24's insert timings, course 28's save
+// timings and course 29's memory use, and the addresses and paths in
+// stack traces.
+var snapshotScrubs = []struct {
+	re   *regexp.Regexp
+	with string
+}{
+	{regexp.MustCompile(`\d{4}-\d\d-\d\d \d\d:\d\d:\d\d(\.\d+)? [+-]\d{4} \w+( m=[+-][\d.]+)?`), "<time>"},
+	{regexp.MustCompile(`(increments) = \d+`), "$1 = <racy>"},
+	{regexp.MustCompile(`(no race), [\d.]+[nµm]?s`), "$1, <elapsed>"},
+	{regexp.MustCompile(`(go_sql_wait_duration_seconds_total\{.*\}) \S+`), "$1 <elapsed>"},
+	{regexp.MustCompile(`(rows) in \S+ \(\d+ rows/s\)`), "$1 in <elapsed>"},
+	{regexp.MustCompile(`(saves) in [\d.]+[nµm]?s`), "$1 in <elapsed>"},
+	{regexp.MustCompile(`(heap peak) .*`), "$1 <measured>"},
+	{regexp.MustCompile(`(bytes\)) in [\d.]+[nµm]?s`), "$1 in <elapsed>"},
+	{regexp.MustCompile(`(\$GOROOT/[^\s:]+):\d+`), "$1:N"},
+	{regexp.MustCompile(` \+0x[0-9a-f]+`), ""},
+	{regexp.MustCompile(`0x[0-9a-f]+\??`), "0x?"},
+	{regexp.MustCompile(`goroutine \d+`), "goroutine N"},
+}
+
+func scrubSnapshot(out string) string {
+	wd, _ := os.Getwd()
+	out = strings.ReplaceAll(out, wd+string(filepath.Separator), "")
+	out = strings.ReplaceAll(out, runtime.GOROOT(), "$GOROOT")
+	out = strings.ReplaceAll(out, os.TempDir(), "$TMPDIR")
+	for _, s := range snapshotScrubs {
+		out = s.re.ReplaceAllString(out, s.with)
+	}
+	return out
+}
+
+// unorderedCourses print from goroutines in whatever order the scheduler
+// runs them, so their output is compared a block at a time, ignoring the
+// order of the lines within each and which worker took which job.
+var unorderedCourses = map[int]bool{4: true, 17: true}
+
+var workerRE = regexp.MustCompile(`Worker \d+`)
+
+// unordered sorts the lines between each pair of blank lines.
+func unordered(out string) string {
+	out = workerRE.ReplaceAllString(out, "Worker N")
+	blocks := strings.Split(out, "\n\n")
+	for i, b := range blocks {
+		lines := strings.Split(b, "\n")
+		slices.Sort(lines)
+		blocks[i] = strings.Join(lines, "\n")
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// TestCourseSnapshots runs every course with the fake clock and compares
+// what it prints with testdata/snapshots. After changing a course on
+// purpose, rewrite them with: go test -run Snapshots -update
+func TestCourseSnapshots(t *testing.T) {
+	demo.Clock = demo.NewFakeClock()
+	defer func() { demo.Clock = demo.RealClock{} }()
+	t.Setenv("COLUMNS", "")
+	t.Setenv("NO_COLOR", "1")
+	// Course 29's file, 1 MB instead of hundreds
+	defer func(size int64) { fileio.LargeFileSize = size }(fileio.LargeFileSize)
+	fileio.LargeFileSize = 1 << 20
+
+	for _, c := range courses {
+		name := fmt.Sprintf("%02d-%s.txt", c.number, strings.TrimSuffix(strings.TrimPrefix(c.file, fmt.Sprintf("%02d-", c.number)), ".go"))
+		t.Run(name, func(t *testing.T) {
+			if c.number == 19 && raceEnabled {
+				t.Skip("course 19 races on purpose, which fails the test under -race")
+			}
+			// As a fresh process has them: other tests add to the course 6 users
+			demo.Clock, web.Users = demo.NewFakeClock(), web.NewDemoUsers()
+			var out strings.Builder
+			if err := runCaptured(context.Background(), c, &out); err != nil {
+				t.Fatal(err)
+			}
+			got := scrubSnapshot(out.String())
+
+			path := filepath.Join("testdata", "snapshots", name)
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (create it with -update)", err)
+			}
+			if unorderedCourses[c.number] {
+				want, got = []byte(unordered(string(want))), unordered(got)
+			}
+			if ops := diffLines(splitLines(string(want)), splitLines(got), func(x, y string) bool { return x == y }); hasChanges(ops) {
+				var diff strings.Builder
+				printHunks(&diff, ops, 2, false)
+				t.Errorf("output differs from %s (rerun with -update if the change is intended):\n%s", path, diff.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+//go:build solutions
+
+// Challenge balanced (course 1: basics)
+//
+// Balanced reports whether the brackets (), [] and {} in s are balanced:
+// every opener is closed by the matching closer, in the right order.
+// Other characters don't matter. "f(a[1], {b})" is balanced; "(]" and
+// "((" are not.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package balanced
+
+var closers = map[rune]rune{')': '(', ']': '[', '}': '{'}
+
+func Balanced(s string) bool {
+	var stack []rune
+	for _, r := range s {
+		switch r {
+		case '(', '[', '{':
+			stack = append(stack, r)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != closers[r] {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return len(stack) == 0
+}
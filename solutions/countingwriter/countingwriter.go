@@ -0,0 +1,28 @@
+//go:build solutions
+
+// Challenge countingwriter (course 20: IO streams)
+//
+// CountingWriter passes everything written to it on to W, and counts the
+// bytes and the lines ('\n' characters) that W accepted. If W fails part
+// way, only the bytes it took count.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package countingwriter
+
+import (
+	"bytes"
+	"io"
+)
+
+type CountingWriter struct {
+	W     io.Writer
+	Bytes int64
+	Lines int64
+}
+
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	n, err := c.W.Write(p)
+	c.Bytes += int64(n)
+	c.Lines += int64(bytes.Count(p[:n], []byte("\n")))
+	return n, err
+}
@@ -0,0 +1,21 @@
+//go:build solutions
+
+// Challenge dedupe (course 1: basics)
+//
+// Dedupe returns s without repeated strings, keeping the first of each in
+// its original order: [a b a c b] becomes [a b c].
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package dedupe
+
+func Dedupe(s []string) []string {
+	seen := make(map[string]bool, len(s))
+	out := make([]string, 0, len(s))
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,30 @@
+//go:build solutions
+
+// Exercise fizzbuzz (course 1: basics)
+//
+// FizzBuzz returns the numbers 1..n as strings, except that multiples of 3
+// are "Fizz", multiples of 5 are "Buzz" and multiples of both are
+// "FizzBuzz". FizzBuzz(5) is ["1" "2" "Fizz" "4" "Buzz"]; n < 1 gives an
+// empty slice.
+//
+// Grade it with: go run ./cmd/learn grade fizzbuzz
+package fizzbuzz
+
+import "strconv"
+
+func FizzBuzz(n int) []string {
+	out := make([]string, 0, max(n, 0))
+	for i := 1; i <= n; i++ {
+		switch {
+		case i%15 == 0: // check both first, or 15 would stop at "Fizz"
+			out = append(out, "FizzBuzz")
+		case i%3 == 0:
+			out = append(out, "Fizz")
+		case i%5 == 0:
+			out = append(out, "Buzz")
+		default:
+			out = append(out, strconv.Itoa(i))
+		}
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+//go:build solutions
+
+// Challenge lru (course 3: structs & interfaces)
+//
+// Cache is a least-recently-used cache: it holds at most capacity entries,
+// and adding one more evicts the entry that was used (read or written)
+// longest ago.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package lru
+
+import "container/list"
+
+type Cache struct {
+	capacity int
+	order    *list.List // front is the most recently used
+	items    map[string]*list.Element
+}
+
+type entry struct {
+	key, value string
+}
+
+func New(capacity int) *Cache {
+	return &Cache{capacity: capacity, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// Get returns the value for key and marks it as just used.
+func (c *Cache) Get(key string) (string, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*entry).value, true
+}
+
+// Put adds or updates key, evicting the least recently used entry if the
+// cache is full.
+func (c *Cache) Put(key, value string) {
+	if e, ok := c.items[key]; ok {
+		e.Value.(*entry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry).key)
+	}
+	c.items[key] = c.order.PushFront(&entry{key, value})
+}
+
+// Len is the number of entries in the cache.
+func (c *Cache) Len() int {
+	return c.order.Len()
+}
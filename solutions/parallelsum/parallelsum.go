@@ -0,0 +1,39 @@
+//go:build solutions
+
+// Exercise parallelsum (course 4: goroutines and channels)
+//
+// Sum adds up nums using the given number of goroutines, each summing one
+// chunk and sending its partial sum back on a channel. workers < 1 means 1,
+// and more workers than numbers must still work. Check your solution with
+// go test -race too: the grader only checks the answers.
+//
+// Grade it with: go run ./cmd/learn grade parallelsum
+package parallelsum
+
+func Sum(nums []int, workers int) int {
+	workers = max(workers, 1)
+	size := (len(nums) + workers - 1) / workers // round up so no number is left over
+	if size == 0 {
+		return 0
+	}
+
+	partials := make(chan int)
+	chunks := 0
+	for start := 0; start < len(nums); start += size {
+		chunk := nums[start:min(start+size, len(nums))]
+		chunks++
+		go func() {
+			total := 0
+			for _, n := range chunk {
+				total += n
+			}
+			partials <- total
+		}()
+	}
+
+	total := 0
+	for range chunks {
+		total += <-partials
+	}
+	return total
+}
@@ -0,0 +1,31 @@
+//go:build solutions
+
+// Challenge retry (course 16: error handling II)
+//
+// Retry calls fn until it succeeds, at most attempts times (but always at
+// least once), and returns nil once it does. If every attempt fails, it
+// returns all their errors joined with errors.Join, so errors.Is finds any
+// of them. An error wrapping ErrPermanent can't be fixed by trying again:
+// Retry stops at once and returns that error as it is.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package retry
+
+import "errors"
+
+var ErrPermanent = errors.New("permanent failure")
+
+func Retry(attempts int, fn func() error) error {
+	var errs []error
+	for range max(attempts, 1) {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, ErrPermanent) {
+			return err
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,17 @@
+//go:build solutions
+
+// Exercise reverse (course 1: basics)
+//
+// Reverse returns s with its characters in reverse order. Characters, not
+// bytes: Reverse("héllo") is "olléh", and "日本" becomes "本日".
+//
+// Grade it with: go run ./cmd/learn grade reverse
+package reverse
+
+func Reverse(s string) string {
+	r := []rune(s) // é is two bytes but one rune
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
@@ -0,0 +1,29 @@
+//go:build solutions
+
+// Challenge reverserunes (course 1: basics)
+//
+// ReverseRunes reverses the UTF-8 text in b in place, character by
+// character, without allocating: "héllo" becomes "olléh". Converting to
+// []rune or string allocates, so that's out.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package reverserunes
+
+import "unicode/utf8"
+
+func ReverseRunes(b []byte) {
+	// Reversing each character's bytes, then all of them, puts the
+	// characters in reverse order with their bytes the right way round
+	for i := 0; i < len(b); {
+		_, size := utf8.DecodeRune(b[i:])
+		reverse(b[i : i+size])
+		i += size
+	}
+	reverse(b)
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
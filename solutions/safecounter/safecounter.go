@@ -0,0 +1,42 @@
+//go:build solutions
+
+// Challenge safecounter (course 19: concurrent store)
+//
+// Counter counts events by name, and is safe to use from many goroutines
+// at once. Its zero value is ready to use.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package safecounter
+
+import (
+	"maps"
+	"sync"
+)
+
+type Counter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *Counter) Inc(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = map[string]int{}
+	}
+	c.counts[name]++
+}
+
+func (c *Counter) Get(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[name] // reading a nil map is fine
+}
+
+// Snapshot returns a copy of all the counts: changing it must not change
+// the counter.
+func (c *Counter) Snapshot() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.counts)
+}
@@ -0,0 +1,40 @@
+//go:build solutions
+
+// Exercise safedivide (course 2: functions and errors)
+//
+// Divide returns a / b. Dividing by zero must not panic: return
+// ErrDivideByZero instead, so callers can check it with errors.Is.
+//
+// ParseAndDivide parses two decimal strings and divides them. A bad number
+// returns an error that wraps the strconv error (use %w) and names the
+// input that was wrong.
+//
+// Grade it with: go run ./cmd/learn grade safedivide
+package safedivide
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var ErrDivideByZero = errors.New("division by zero")
+
+func Divide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	return a / b, nil
+}
+
+func ParseAndDivide(a, b string) (int, error) {
+	x, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, fmt.Errorf("dividend %q: %w", a, err)
+	}
+	y, err := strconv.Atoi(b)
+	if err != nil {
+		return 0, fmt.Errorf("divisor %q: %w", b, err)
+	}
+	return Divide(x, y) // ErrDivideByZero passes through unchanged
+}
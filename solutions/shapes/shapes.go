@@ -0,0 +1,41 @@
+//go:build solutions
+
+// Exercise shapes (course 3: structs and interfaces)
+//
+// Give Rect and Circle Area and Perimeter methods so both satisfy Shape,
+// then write Largest, which returns the shape with the biggest area (nil
+// for an empty slice).
+//
+// Grade it with: go run ./cmd/learn grade shapes
+package shapes
+
+import "math"
+
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+type Rect struct {
+	Width, Height float64
+}
+
+type Circle struct {
+	Radius float64
+}
+
+func (r Rect) Area() float64      { return r.Width * r.Height }
+func (r Rect) Perimeter() float64 { return 2 * (r.Width + r.Height) }
+
+func (c Circle) Area() float64      { return math.Pi * c.Radius * c.Radius }
+func (c Circle) Perimeter() float64 { return 2 * math.Pi * c.Radius }
+
+func Largest(shapes []Shape) Shape {
+	var largest Shape
+	for _, s := range shapes {
+		if largest == nil || s.Area() > largest.Area() {
+			largest = s
+		}
+	}
+	return largest
+}
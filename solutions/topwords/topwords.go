@@ -0,0 +1,36 @@
+//go:build solutions
+
+// Challenge topwords (course 1: basics)
+//
+// TopWords returns the k most frequent words in text, most frequent first.
+// A word is a run of letters, and case doesn't matter: "Go", "go" and "GO"
+// are all "go". Words used equally often come in alphabetical order. If
+// there are fewer than k different words, it returns all of them.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package topwords
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+func TopWords(text string, k int) []string {
+	counts := map[string]int{}
+	for _, w := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		counts[strings.ToLower(w)]++
+	}
+	words := make([]string, 0, len(counts))
+	for w := range counts {
+		words = append(words, w)
+	}
+	slices.SortFunc(words, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	return words[:min(k, len(words))]
+}
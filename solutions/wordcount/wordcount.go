@@ -0,0 +1,34 @@
+//go:build solutions
+
+// Exercise wordcount (course 20: io streams)
+//
+// CountWords reads r to the end and counts each word, lowercased, with
+// surrounding punctuation trimmed ("Go," and "go" are the same word). It
+// must work on input far bigger than memory, so read it as a stream
+// (bufio.Scanner with bufio.ScanWords) rather than io.ReadAll. A read
+// error is returned.
+//
+// Grade it with: go run ./cmd/learn grade wordcount
+package wordcount
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+)
+
+func CountWords(r io.Reader) (map[string]int, error) {
+	counts := map[string]int{}
+	sc := bufio.NewScanner(r)
+	sc.Split(bufio.ScanWords)
+	for sc.Scan() {
+		word := strings.TrimFunc(sc.Text(), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		})
+		if word != "" {
+			counts[strings.ToLower(word)]++
+		}
+	}
+	return counts, sc.Err() // nil at io.EOF, the read error otherwise
+}
@@ -0,0 +1,32 @@
+//go:build solutions
+
+// Challenge workerpool (course 4: goroutines & channels)
+//
+// Map calls fn on every job, running at most workers calls at the same
+// time, and returns the results in the same order as jobs.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package workerpool
+
+import "sync"
+
+func Map(jobs []int, workers int, fn func(int) int) []int {
+	results := make([]int, len(jobs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = fn(jobs[i])
+			}
+		}()
+	}
+	for i := range jobs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,280 @@
+package learn
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runStats implements "go run ./cmd/learn stats [flags] [course]".
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file; the time log is time.json next to it")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn stats [flags] [course]")
+		fmt.Fprintln(flags.Output(), "Shows time spent, completion, quiz scores and your weakest topics. Name a")
+		fmt.Fprintln(flags.Output(), "course to see the time spent in each of its sections.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return errors.New("name one course at a time")
+	}
+
+	p, err := loadProgress(*progressPath)
+	if err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	log, err := loadStudyLog(studyLogPath(*progressPath))
+	if err != nil {
+		return fmt.Errorf("reading the time log: %w", err)
+	}
+
+	if flags.NArg() == 1 {
+		n, err := strconv.Atoi(flags.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid course %q: expected a number", flags.Arg(0))
+		}
+		c, ok := findCourse(n)
+		if !ok {
+			return fmt.Errorf("course %d does not exist (available: 1-%d)", n, len(courses))
+		}
+		return printCourseTime(os.Stdout, c, log.Courses[n])
+	}
+	printStats(os.Stdout, courseStats(p, log))
+	return nil
+}
+
+// courseStat is where the learner stands in one course.
+type courseStat struct {
+	course    course
+	seconds   float64
+	read      bool // run to the end at least once
+	quiz      int  // best score, -1 if never taken
+	exercises []exerciseStat
+	forgotten int // flashcards whose last review was a lapse
+}
+
+type exerciseStat struct {
+	name string
+	best int // -1 if never graded
+}
+
+// done and total count the steps of a course: reading it, passing its
+// quiz and passing each of its exercises.
+func (s courseStat) done() (done, total int) {
+	total = 2 + len(s.exercises)
+	if s.read {
+		done++
+	}
+	if s.quiz >= quizPassMark {
+		done++
+	}
+	for _, e := range s.exercises {
+		if e.best == 100 {
+			done++
+		}
+	}
+	return done, total
+}
+
+func courseStats(p *progress, log *studyLog) []courseStat {
+	var stats []courseStat
+	for _, c := range courses {
+		ct := log.Courses[c.number]
+		s := courseStat{course: c, seconds: ct.Seconds, read: ct.Finished > 0, quiz: -1}
+		if q, ok := p.Quizzes[c.number]; ok {
+			s.quiz = q.Best
+		}
+		for _, ex := range exerciseList {
+			if ex.course != c.number {
+				continue
+			}
+			e := exerciseStat{name: ex.name, best: -1}
+			if ep, ok := p.Exercises[ex.name]; ok {
+				e.best = ep.Best
+			}
+			s.exercises = append(s.exercises, e)
+		}
+		prefix := fmt.Sprintf("%d/", c.number)
+		for id, card := range p.Review {
+			if strings.HasPrefix(id, prefix) && card.Reps == 0 {
+				s.forgotten++
+			}
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+func printStats(w io.Writer, stats []courseStat) {
+	var seconds float64
+	var done, total, read, quizzes, exercises, passed int
+	for _, s := range stats {
+		d, t := s.done()
+		done, total = done+d, total+t
+		seconds += s.seconds
+		if s.read {
+			read++
+		}
+		if s.quiz >= quizPassMark {
+			quizzes++
+		}
+		for _, e := range s.exercises {
+			exercises++
+			if e.best == 100 {
+				passed++
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "STATS")
+	fmt.Fprintf(w, "Time studied: %s\n", studyDuration(seconds))
+	fmt.Fprintf(w, "Completion:   %d%% (%d/%d courses read, %d/%d quizzes passed, %d/%d exercises passed)\n\n",
+		done*100/max(total, 1), read, len(stats), quizzes, len(stats), passed, exercises)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "     COURSE\tTIME\tREAD\tQUIZ\tEXERCISES\tDONE")
+	for _, s := range stats {
+		t, readMark, quiz, ex := "-", "-", "-", "-"
+		if s.seconds > 0 {
+			t = studyDuration(s.seconds)
+		}
+		if s.read {
+			readMark = "yes"
+		}
+		if s.quiz >= 0 {
+			quiz = fmt.Sprintf("%d%%", s.quiz)
+		}
+		if len(s.exercises) > 0 {
+			n := 0
+			for _, e := range s.exercises {
+				if e.best == 100 {
+					n++
+				}
+			}
+			ex = fmt.Sprintf("%d/%d", n, len(s.exercises))
+		}
+		d, total := s.done()
+		fmt.Fprintf(tw, "%3d. %s\t%s\t%s\t%s\t%s\t%d%%\n", s.course.number, s.course.name, t, readMark, quiz, ex, d*100/total)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nWEAKEST TOPICS")
+	weak := weakestTopics(stats, 3)
+	if len(weak) == 0 {
+		fmt.Fprintln(w, "Nothing stands out yet: take a quiz, grade an exercise or review some flashcards.")
+		return
+	}
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range weak {
+		fmt.Fprintf(tw, "%3d. %s\t%s\n", s.course.number, s.course.name, strings.Join(weakness(s), ", "))
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nGo over one again with: go run ./cmd/learn <number>, then go run ./cmd/learn review <number>")
+}
+
+// strength is a course's lowest quiz or exercise score, or 100 if the
+// learner hasn't been tested on it.
+func (s courseStat) strength() int {
+	lowest := 100
+	if s.quiz >= 0 {
+		lowest = s.quiz
+	}
+	for _, e := range s.exercises {
+		if e.best >= 0 {
+			lowest = min(lowest, e.best)
+		}
+	}
+	return lowest
+}
+
+// weakestTopics returns up to n courses with a score below 100 or
+// forgotten flashcards, weakest first.
+func weakestTopics(stats []courseStat, n int) []courseStat {
+	var weak []courseStat
+	for _, s := range stats {
+		if s.strength() < 100 || s.forgotten > 0 {
+			weak = append(weak, s)
+		}
+	}
+	slices.SortStableFunc(weak, func(a, b courseStat) int {
+		if c := cmp.Compare(a.strength(), b.strength()); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.forgotten, a.forgotten)
+	})
+	return weak[:min(n, len(weak))]
+}
+
+// weakness lists what makes a course weak: "quiz 33%", "reverse 50%",
+// "2 forgotten flashcards".
+func weakness(s courseStat) []string {
+	var why []string
+	if s.quiz >= 0 && s.quiz < 100 {
+		why = append(why, fmt.Sprintf("quiz %d%%", s.quiz))
+	}
+	for _, e := range s.exercises {
+		if e.best >= 0 && e.best < 100 {
+			why = append(why, fmt.Sprintf("%s %d%%", e.name, e.best))
+		}
+	}
+	if s.forgotten > 0 {
+		why = append(why, plural(s.forgotten, "forgotten flashcard", "forgotten flashcards"))
+	}
+	return why
+}
+
+// printCourseTime shows the time spent in each section of a course.
+func printCourseTime(w io.Writer, c course, ct courseTime) error {
+	l, err := loadLesson(c.number)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "COURSE %d: %s\n", c.number, c.name)
+	if ct.Runs == 0 {
+		fmt.Fprintf(w, "Not started yet. Run it with: go run ./cmd/learn %d\n", c.number)
+		return nil
+	}
+	fmt.Fprintf(w, "Time: %s over %s, read to the end %s, last run %s\n\n",
+		studyDuration(ct.Seconds), plural(ct.Runs, "run", "runs"), plural(ct.Finished, "time", "times"), ct.LastRun.Format(dateLayout))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, s := range l.Sections {
+		id, title := s.ID, s.Title
+		if i == 0 {
+			id, title = "intro", "introduction"
+		}
+		spent := "-"
+		if secs, ok := ct.Sections[id]; ok {
+			spent = studyDuration(secs)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", title, spent)
+	}
+	return tw.Flush()
+}
+
+// studyDuration formats seconds the way people say them: "2h05m",
+// "12m30s", "45s".
+func studyDuration(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second)).Round(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm%02ds", m, s)
+	}
+	return fmt.Sprintf("%ds", s)
+}
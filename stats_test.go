@@ -0,0 +1,168 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStudyTimer(t *testing.T) {
+	progressPath := filepath.Join(t.TempDir(), "progress.json")
+	timer := newStudyTimer(progressPath)
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	timer.now = func() time.Time { return now }
+	wait := func(d time.Duration) { now = now.Add(d) }
+
+	// One course read to the end; a paused section counts for at most
+	// idleLimit
+	l, _ := parseLesson(1, sampleLesson)
+	study = timer
+	defer func() { study = nil }()
+	runCourse(context.Background(), course{number: 1, run: func(ctx context.Context, _ io.Writer) error {
+		study.start(1)
+		r := newLessonRun(ctx, l, &termRenderer{w: &bytes.Buffer{}, width: 80})
+		wait(5 * time.Second)
+		r.Section("first")
+		wait(2 * time.Hour)
+		r.Section("second")
+		wait(1500 * time.Millisecond)
+		r.End()
+		return nil
+	}})
+	// Then a course stopped part way, which save closes
+	timer.start(2)
+	wait(time.Minute)
+	if err := timer.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := loadStudyLog(studyLogPath(progressPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1 := log.Courses[1]
+	want := map[string]float64{"intro": 5, "first": idleLimit.Seconds(), "second": 1.5}
+	for id, secs := range want {
+		if c1.Sections[id] != secs {
+			t.Errorf("section %s: %v seconds, want %v", id, c1.Sections[id], secs)
+		}
+	}
+	if c1.Seconds != 5+idleLimit.Seconds()+1.5 || c1.Runs != 1 || c1.Finished != 1 {
+		t.Errorf("course 1 = %+v", c1)
+	}
+	if c2 := log.Courses[2]; c2.Seconds != 60 || c2.Finished != 0 {
+		t.Errorf("course 2 = %+v, want 60 seconds, not finished", c2)
+	}
+}
+
+// Two runs at once each add their own time to the log, whichever saves
+// first, and a run that saves twice doesn't count its time twice.
+func TestStudyTimerMerges(t *testing.T) {
+	progressPath := filepath.Join(t.TempDir(), "progress.json")
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	first, second := newStudyTimer(progressPath), newStudyTimer(progressPath)
+	first.now, second.now = clock, clock
+
+	first.start(1)
+	second.start(1)
+	second.start(2)
+	now = now.Add(time.Minute)
+	first.finish()
+	if err := second.save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := loadStudyLog(studyLogPath(progressPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1, c2 := log.Courses[1], log.Courses[2]
+	if c1.Runs != 2 || c1.Finished != 1 || c1.Seconds != 60 || c1.Sections["intro"] != 60 {
+		t.Errorf("course 1 = %+v, want 2 runs, 1 finished, 60 seconds", c1)
+	}
+	if c2.Runs != 1 || c2.Seconds != 60 || !c2.LastRun.Equal(now.Add(-time.Minute)) {
+		t.Errorf("course 2 = %+v, want 1 run of 60 seconds", c2)
+	}
+}
+
+func TestStats(t *testing.T) {
+	p := &progress{
+		Exercises: map[string]exerciseProgress{
+			"fizzbuzz":    {Best: 100},
+			"reverse":     {Best: 50},
+			"parallelsum": {Best: 100},
+		},
+		Quizzes: map[int]quizProgress{1: {Best: 100}, 4: {Best: 33}, 3: {Best: 66}},
+		Review: map[string]cardState{
+			"3/aaaa": {Reps: 0},
+			"3/bbbb": {Reps: 0},
+			"3/cccc": {Reps: 2},
+		},
+	}
+	log := &studyLog{Courses: map[int]courseTime{
+		1: {Seconds: 754, Finished: 2},
+		4: {Seconds: 3725, Finished: 1},
+	}}
+	stats := courseStats(p, log)
+
+	if d, total := stats[0].done(); d != 3 || total != 4 {
+		t.Errorf("course 1: %d of %d done, want read + quiz + fizzbuzz of 4", d, total)
+	}
+	weak := weakestTopics(stats, 3)
+	var got []int
+	for _, s := range weak {
+		got = append(got, s.course.number)
+	}
+	// Course 4's quiz (33%), course 1's reverse (50%), then course 3's
+	// quiz (66%) with its forgotten flashcards
+	if len(got) != 3 || got[0] != 4 || got[1] != 1 || got[2] != 3 {
+		t.Errorf("weakest topics = %v, want [4 1 3]", got)
+	}
+	if why := strings.Join(weakness(weak[2]), ", "); why != "quiz 66%, 2 forgotten flashcards" {
+		t.Errorf("course 3 is weak because %q", why)
+	}
+
+	var buf bytes.Buffer
+	printStats(&buf, stats)
+	out := buf.String()
+	for _, want := range []string{
+		"Time studied: 1h14m",
+		fmt.Sprintf("(2/%d courses read, 2/%d quizzes passed, 2/%d exercises passed)", len(courses), len(courses), len(exerciseList)),
+		"1. BASICS", "12m34s  yes   100%  1/2",
+		" 4. GOROUTINES & CHANNELS  quiz 33%",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("stats missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStudyDuration(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    string
+	}{
+		{0, "0s"},
+		{0.4, "0s"},
+		{59.6, "1m00s"},
+		{754, "12m34s"},
+		{7500, "2h05m"},
+	}
+	for _, tt := range tests {
+		if got := studyDuration(tt.seconds); got != tt.want {
+			t.Errorf("studyDuration(%v) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
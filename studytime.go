@@ -0,0 +1,209 @@
+package learn
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/safefile"
+)
+
+// studyLog is how long the learner has spent in each course, kept in
+// time.json next to the progress file.
+type studyLog struct {
+	Courses map[int]courseTime `json:"courses"`
+}
+
+// courseTime is the time spent in one course.
+type courseTime struct {
+	Seconds  float64            `json:"seconds"`
+	Sections map[string]float64 `json:"sections"` // seconds by section id; the intro is "intro"
+	Runs     int                `json:"runs"`
+	Finished int                `json:"finished"` // runs that got to the key takeaways
+	LastRun  time.Time          `json:"last_run"`
+}
+
+// studyLogPath is the time log that goes with a progress file.
+func studyLogPath(progressPath string) string {
+	return filepath.Join(filepath.Dir(progressPath), "time.json")
+}
+
+// loadStudyLog reads the time log. A missing file is a fresh start.
+func loadStudyLog(path string) (*studyLog, error) {
+	l := &studyLog{Courses: map[int]courseTime{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, err
+	}
+	if l.Courses == nil {
+		l.Courses = map[int]courseTime{}
+	}
+	return l, nil
+}
+
+func (l *studyLog) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// idleLimit is the most one visit to a section counts for, so a course
+// left paused overnight doesn't claim the whole night.
+const idleLimit = 30 * time.Minute
+
+// add merges the times of one course, from another log, into l.
+func (l *studyLog) add(course int, ct courseTime) {
+	c := l.Courses[course]
+	c.Seconds = addSeconds(c.Seconds, ct.Seconds)
+	for id, secs := range ct.Sections {
+		if c.Sections == nil {
+			c.Sections = map[string]float64{}
+		}
+		c.Sections[id] = addSeconds(c.Sections[id], secs)
+	}
+	c.Runs += ct.Runs
+	c.Finished += ct.Finished
+	if ct.LastRun.After(c.LastRun) {
+		c.LastRun = ct.LastRun
+	}
+	l.Courses[course] = c
+}
+
+// studyTimer times the sections of the courses as they run: from one
+// section's heading to the next, which with --paced includes the reading
+// at the prompt. Like pace, a nil *studyTimer does nothing; only runs from
+// the command line are timed.
+type studyTimer struct {
+	// log is the time of this run not saved yet: save adds it to the file
+	// as it is then, so another run saving meanwhile, or a restore, isn't
+	// lost.
+	log  *studyLog
+	path string
+	lock string // the progress file's lock, which the time log shares
+	now  func() time.Time
+
+	course  int // 0 when no course is open
+	section string
+	since   time.Time
+}
+
+// study is the timer for this run, if any.
+var study *studyTimer
+
+// newStudyTimer times a run whose progress file is progressPath.
+func newStudyTimer(progressPath string) *studyTimer {
+	return &studyTimer{
+		log:  &studyLog{Courses: map[int]courseTime{}},
+		path: studyLogPath(progressPath),
+		lock: progressPath + ".lock",
+		now:  time.Now,
+	}
+}
+
+// start opens a course at its intro.
+func (t *studyTimer) start(course int) {
+	if t == nil {
+		return
+	}
+	t.close()
+	now := t.now()
+	ct := t.log.Courses[course]
+	ct.Runs++
+	ct.LastRun = now
+	t.log.Courses[course] = ct
+	t.course, t.section, t.since = course, "intro", now
+}
+
+// enter moves on to the section with the given id.
+func (t *studyTimer) enter(id string) {
+	if t == nil || t.course == 0 {
+		return
+	}
+	course := t.course
+	t.close()
+	t.course, t.section, t.since = course, id, t.now()
+}
+
+// finish closes the course after its key takeaways.
+func (t *studyTimer) finish() {
+	if t == nil || t.course == 0 {
+		return
+	}
+	ct := t.log.Courses[t.course]
+	ct.Finished++
+	t.log.Courses[t.course] = ct
+	t.close()
+}
+
+// close adds the time spent in the open section, if any.
+func (t *studyTimer) close() {
+	if t.course == 0 {
+		return
+	}
+	spent := min(t.now().Sub(t.since), idleLimit)
+	ct := t.log.Courses[t.course]
+	if ct.Sections == nil {
+		ct.Sections = map[string]float64{}
+	}
+	ct.Sections[t.section] = addTime(ct.Sections[t.section], spent)
+	ct.Seconds = addTime(ct.Seconds, spent)
+	t.log.Courses[t.course] = ct
+	t.course = 0
+}
+
+// addTime adds d to seconds, to the millisecond, so that the file doesn't
+// fill up with float noise.
+func addTime(seconds float64, d time.Duration) float64 {
+	return math.Round(seconds*1000+float64(d.Milliseconds())) / 1000
+}
+
+// addSeconds adds two times in seconds, to the millisecond.
+func addSeconds(a, b float64) float64 {
+	return math.Round((a+b)*1000) / 1000
+}
+
+// save closes the open section, for a course that was skipped or stopped,
+// and adds the time since the last save to the log. It holds the lock
+// updateProgress takes from reading the log to writing it.
+func (t *studyTimer) save() error {
+	if t == nil {
+		return nil
+	}
+	t.close()
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	lock, err := safefile.Obtain(t.lock)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	l, err := loadStudyLog(t.path)
+	if err != nil {
+		return err
+	}
+	for course, ct := range t.log.Courses {
+		l.add(course, ct)
+	}
+	if err := l.save(t.path); err != nil {
+		return err
+	}
+	t.log.Courses = map[int]courseTime{}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package learn
+
+import "os"
+
+// terminalWidth is not implemented here; callers fall back to $COLUMNS.
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,14 @@
+// Challenge balanced (course 1: basics)
+//
+// Balanced reports whether the brackets (), [] and {} in s are balanced:
+// every opener is closed by the matching closer, in the right order.
+// Other characters don't matter. "f(a[1], {b})" is balanced; "(]" and
+// "((" are not.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package balanced
+
+func Balanced(s string) bool {
+	// TODO: a slice makes a good stack
+	return false
+}
@@ -0,0 +1,21 @@
+// Challenge countingwriter (course 20: IO streams)
+//
+// CountingWriter passes everything written to it on to W, and counts the
+// bytes and the lines ('\n' characters) that W accepted. If W fails part
+// way, only the bytes it took count.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package countingwriter
+
+import "io"
+
+type CountingWriter struct {
+	W     io.Writer
+	Bytes int64
+	Lines int64
+}
+
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	// TODO: count what W actually wrote
+	return c.W.Write(p)
+}
@@ -0,0 +1,12 @@
+// Challenge dedupe (course 1: basics)
+//
+// Dedupe returns s without repeated strings, keeping the first of each in
+// its original order: [a b a c b] becomes [a b c].
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package dedupe
+
+func Dedupe(s []string) []string {
+	// TODO: a map remembers what you've already seen
+	return s
+}
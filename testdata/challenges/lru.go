@@ -0,0 +1,32 @@
+// Challenge lru (course 3: structs & interfaces)
+//
+// Cache is a least-recently-used cache: it holds at most capacity entries,
+// and adding one more evicts the entry that was used (read or written)
+// longest ago.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package lru
+
+type Cache struct {
+	// TODO: a map for lookups plus a way to track recency
+	// (container/list is a doubly linked list)
+}
+
+func New(capacity int) *Cache {
+	return &Cache{}
+}
+
+// Get returns the value for key and marks it as just used.
+func (c *Cache) Get(key string) (string, bool) {
+	return "", false
+}
+
+// Put adds or updates key, evicting the least recently used entry if the
+// cache is full.
+func (c *Cache) Put(key, value string) {
+}
+
+// Len is the number of entries in the cache.
+func (c *Cache) Len() int {
+	return 0
+}
@@ -0,0 +1,13 @@
+// Challenge reverserunes (course 1: basics)
+//
+// ReverseRunes reverses the UTF-8 text in b in place, character by
+// character, without allocating: "héllo" becomes "olléh". Converting to
+// []rune or string allocates, so that's out.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package reverserunes
+
+func ReverseRunes(b []byte) {
+	// TODO: reverse the bytes of each character, then the whole slice
+	// (utf8.DecodeRune tells you how long a character is)
+}
@@ -0,0 +1,24 @@
+// Challenge safecounter (course 19: concurrent store)
+//
+// Counter counts events by name, and is safe to use from many goroutines
+// at once. Its zero value is ready to use.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package safecounter
+
+type Counter struct {
+	// TODO: a map and something to guard it
+}
+
+func (c *Counter) Inc(name string) {
+}
+
+func (c *Counter) Get(name string) int {
+	return 0
+}
+
+// Snapshot returns a copy of all the counts: changing it must not change
+// the counter.
+func (c *Counter) Snapshot() map[string]int {
+	return nil
+}
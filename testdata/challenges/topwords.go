@@ -0,0 +1,14 @@
+// Challenge topwords (course 1: basics)
+//
+// TopWords returns the k most frequent words in text, most frequent first.
+// A word is a run of letters, and case doesn't matter: "Go", "go" and "GO"
+// are all "go". Words used equally often come in alphabetical order. If
+// there are fewer than k different words, it returns all of them.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package topwords
+
+func TopWords(text string, k int) []string {
+	// TODO: strings.FieldsFunc, a map of counts, then slices.SortFunc
+	return nil
+}
@@ -0,0 +1,13 @@
+// Challenge workerpool (course 4: goroutines & channels)
+//
+// Map calls fn on every job, running at most workers calls at the same
+// time, and returns the results in the same order as jobs.
+//
+// Grade it with: go run ./cmd/learn challenge grade
+package workerpool
+
+func Map(jobs []int, workers int, fn func(int) int) []int {
+	// TODO: start workers goroutines reading job indexes from a channel;
+	// each writes its result to its own index, so no locking is needed
+	return nil
+}
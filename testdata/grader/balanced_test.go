@@ -0,0 +1,27 @@
+package balanced
+
+import "testing"
+
+func TestBalancedTrue(t *testing.T) {
+	for _, s := range []string{"", "()", "f(a[1], {b})", "{[()()]}", "no brackets"} {
+		if !Balanced(s) {
+			t.Errorf("Balanced(%q) = false, want true", s)
+		}
+	}
+}
+
+func TestBalancedWrongCloser(t *testing.T) {
+	for _, s := range []string{"(]", "{[}]", "([)]"} {
+		if Balanced(s) {
+			t.Errorf("Balanced(%q) = true, want false", s)
+		}
+	}
+}
+
+func TestBalancedUnclosedOrUnopened(t *testing.T) {
+	for _, s := range []string{"((", ")(", "x)", "{"} {
+		if Balanced(s) {
+			t.Errorf("Balanced(%q) = true, want false", s)
+		}
+	}
+}
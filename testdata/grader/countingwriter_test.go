@@ -0,0 +1,45 @@
+package countingwriter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCountingWriterCounts(t *testing.T) {
+	var buf bytes.Buffer
+	cw := &CountingWriter{W: &buf}
+	fmt.Fprintf(cw, "one\ntwo\n")
+	fmt.Fprintf(cw, "three")
+	if buf.String() != "one\ntwo\nthree" {
+		t.Errorf("passed on %q", buf.String())
+	}
+	if cw.Bytes != 13 || cw.Lines != 2 {
+		t.Errorf("Bytes=%d Lines=%d, want 13 and 2", cw.Bytes, cw.Lines)
+	}
+}
+
+// shortWriter accepts n bytes, then fails.
+type shortWriter struct{ n int }
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if len(p) <= w.n {
+		w.n -= len(p)
+		return len(p), nil
+	}
+	n := w.n
+	w.n = 0
+	return n, errors.New("disk full")
+}
+
+func TestCountingWriterShortWrite(t *testing.T) {
+	cw := &CountingWriter{W: &shortWriter{n: 4}}
+	n, err := cw.Write([]byte("ab\ncd\nef"))
+	if n != 4 || err == nil {
+		t.Errorf("Write = %d, %v; want 4 and the error", n, err)
+	}
+	if cw.Bytes != 4 || cw.Lines != 1 {
+		t.Errorf("Bytes=%d Lines=%d, want 4 and 1", cw.Bytes, cw.Lines)
+	}
+}
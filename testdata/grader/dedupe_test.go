@@ -0,0 +1,29 @@
+package dedupe
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDedupeKeepsFirstInOrder(t *testing.T) {
+	got := Dedupe([]string{"a", "b", "a", "c", "b", "a"})
+	if want := []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Dedupe = %q, want %q", got, want)
+	}
+}
+
+func TestDedupeNoRepeats(t *testing.T) {
+	in := []string{"go", "rust", "zig"}
+	if got := Dedupe(in); !slices.Equal(got, in) {
+		t.Errorf("Dedupe(%q) = %q, want it unchanged", in, got)
+	}
+}
+
+func TestDedupeEmpty(t *testing.T) {
+	if got := Dedupe(nil); len(got) != 0 {
+		t.Errorf("Dedupe(nil) = %q, want empty", got)
+	}
+	if got := Dedupe([]string{"", "", "x"}); !slices.Equal(got, []string{"", "x"}) {
+		t.Errorf("the empty string is a value too, got %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package fizzbuzz
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFizzBuzzFirstFifteen(t *testing.T) {
+	want := []string{"1", "2", "Fizz", "4", "Buzz", "Fizz", "7", "8", "Fizz", "Buzz", "11", "Fizz", "13", "14", "FizzBuzz"}
+	if got := FizzBuzz(15); !slices.Equal(got, want) {
+		t.Errorf("FizzBuzz(15) = %q, want %q", got, want)
+	}
+}
+
+func TestFizzBuzzLength(t *testing.T) {
+	if got := len(FizzBuzz(100)); got != 100 {
+		t.Errorf("len(FizzBuzz(100)) = %d, want 100", got)
+	}
+}
+
+func TestFizzBuzzMultiplesOfFifteen(t *testing.T) {
+	got := FizzBuzz(90)
+	for _, n := range []int{30, 45, 60, 90} {
+		if len(got) < n || got[n-1] != "FizzBuzz" {
+			t.Errorf("element for %d should be FizzBuzz", n)
+		}
+	}
+}
+
+func TestFizzBuzzNothing(t *testing.T) {
+	for _, n := range []int{0, -3} {
+		if got := FizzBuzz(n); len(got) != 0 {
+			t.Errorf("FizzBuzz(%d) = %q, want empty", n, got)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package lru
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLRUGetPut(t *testing.T) {
+	c := New(2)
+	c.Put("a", "1")
+	c.Put("b", "2")
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Errorf("Get(a) = %q, %v", v, ok)
+	}
+	c.Put("a", "one")
+	if v, _ := c.Get("a"); v != "one" || c.Len() != 2 {
+		t.Errorf("after updating a: Get(a) = %q, Len = %d", v, c.Len())
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("Get(missing) found something")
+	}
+}
+
+func TestLRUEvictsLeastRecent(t *testing.T) {
+	c := New(2)
+	c.Put("a", "1")
+	c.Put("b", "2")
+	c.Get("a") // b is now the least recently used
+	c.Put("c", "3")
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := c.Get(k); !ok {
+			t.Errorf("%s should still be cached", k)
+		}
+	}
+}
+
+func TestLRUCapacity(t *testing.T) {
+	c := New(3)
+	for i := range 100 {
+		c.Put(fmt.Sprint(i), "x")
+	}
+	if c.Len() != 3 {
+		t.Errorf("Len = %d, want 3", c.Len())
+	}
+	if _, ok := c.Get("99"); !ok {
+		t.Error("the newest entry should be cached")
+	}
+}
@@ -0,0 +1,34 @@
+package parallelsum
+
+import "testing"
+
+func numbers(n int) []int {
+	nums := make([]int, n)
+	for i := range nums {
+		nums[i] = i + 1
+	}
+	return nums
+}
+
+func TestSum(t *testing.T) {
+	for _, workers := range []int{1, 2, 3, 8} {
+		if got := Sum(numbers(1000), workers); got != 500500 {
+			t.Errorf("Sum(1..1000, %d workers) = %d, want 500500", workers, got)
+		}
+	}
+}
+
+func TestSumMoreWorkersThanNumbers(t *testing.T) {
+	if got := Sum([]int{4, 5, 6}, 10); got != 15 {
+		t.Errorf("Sum([4 5 6], 10 workers) = %d, want 15", got)
+	}
+}
+
+func TestSumEdgeCases(t *testing.T) {
+	if got := Sum(nil, 4); got != 0 {
+		t.Errorf("Sum(nil) = %d, want 0", got)
+	}
+	if got := Sum([]int{-2, 7}, 0); got != 5 {
+		t.Errorf("Sum([-2 7], 0 workers) = %d, want 5", got)
+	}
+}
@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRetrySucceeds(t *testing.T) {
+	calls := 0
+	err := Retry(5, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Retry = %v after %d calls, want nil after 3", err, calls)
+	}
+}
+
+func TestRetryJoinsErrors(t *testing.T) {
+	errs := []error{errors.New("first"), errors.New("second"), errors.New("third")}
+	calls := 0
+	err := Retry(3, func() error {
+		calls++
+		return errs[calls-1]
+	})
+	if calls != 3 {
+		t.Fatalf("fn called %d times, want 3", calls)
+	}
+	for _, e := range errs {
+		if !errors.Is(err, e) {
+			t.Errorf("errors.Is(err, %v) = false; err = %v", e, err)
+		}
+	}
+}
+
+func TestRetryPermanent(t *testing.T) {
+	calls := 0
+	perm := fmt.Errorf("bad credentials: %w", ErrPermanent)
+	err := Retry(5, func() error {
+		calls++
+		return perm
+	})
+	if calls != 1 || err != perm {
+		t.Errorf("Retry = %v after %d calls, want the permanent error after 1", err, calls)
+	}
+}
+
+func TestRetryAtLeastOnce(t *testing.T) {
+	calls := 0
+	Retry(0, func() error { calls++; return errors.New("no") })
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,27 @@
+package reverse
+
+import "testing"
+
+func TestReverseASCII(t *testing.T) {
+	for in, want := range map[string]string{"hello": "olleh", "ab": "ba", "a": "a", "": ""} {
+		if got := Reverse(in); got != want {
+			t.Errorf("Reverse(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestReverseUnicode(t *testing.T) {
+	for in, want := range map[string]string{"héllo": "olléh", "日本語": "語本日", "Go🚀": "🚀oG"} {
+		if got := Reverse(in); got != want {
+			t.Errorf("Reverse(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestReverseTwiceIsIdentity(t *testing.T) {
+	for _, s := range []string{"racecar", "Hello, 世界", "x y z"} {
+		if got := Reverse(Reverse(s)); got != s {
+			t.Errorf("Reverse(Reverse(%q)) = %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package reverserunes
+
+import "testing"
+
+func TestReverseRunesASCII(t *testing.T) {
+	for in, want := range map[string]string{"hello": "olleh", "ab": "ba", "a": "a", "": ""} {
+		b := []byte(in)
+		ReverseRunes(b)
+		if string(b) != want {
+			t.Errorf("ReverseRunes(%q) = %q, want %q", in, b, want)
+		}
+	}
+}
+
+func TestReverseRunesUnicode(t *testing.T) {
+	for in, want := range map[string]string{"héllo": "olléh", "Go 世界": "界世 oG", "🙂!": "!🙂"} {
+		b := []byte(in)
+		ReverseRunes(b)
+		if string(b) != want {
+			t.Errorf("ReverseRunes(%q) = %q, want %q", in, b, want)
+		}
+	}
+}
+
+func TestReverseRunesNoAllocations(t *testing.T) {
+	b := []byte("héllo, 世界")
+	if n := testing.AllocsPerRun(100, func() { ReverseRunes(b) }); n != 0 {
+		t.Errorf("ReverseRunes allocated %v times per call, want 0", n)
+	}
+}
@@ -0,0 +1,52 @@
+package safecounter
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCounterZeroValue(t *testing.T) {
+	var c Counter
+	if got := c.Get("x"); got != 0 {
+		t.Errorf("Get on a new counter = %d", got)
+	}
+	c.Inc("x")
+	c.Inc("x")
+	c.Inc("y")
+	if c.Get("x") != 2 || c.Get("y") != 1 {
+		t.Errorf("x=%d y=%d, want 2 and 1", c.Get("x"), c.Get("y"))
+	}
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	var c Counter
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				c.Inc("hits")
+				c.Get("hits")
+			}
+		}()
+	}
+	wg.Wait()
+	if got := c.Get("hits"); got != 5000 {
+		t.Errorf("hits = %d, want 5000 (lost updates?)", got)
+	}
+}
+
+func TestCounterSnapshotIsACopy(t *testing.T) {
+	var c Counter
+	c.Inc("a")
+	snap := c.Snapshot()
+	if snap["a"] != 1 {
+		t.Fatalf("Snapshot = %v", snap)
+	}
+	snap["a"] = 100
+	c.Inc("a")
+	if c.Get("a") != 2 || snap["a"] != 100 {
+		t.Errorf("the snapshot and the counter share state: counter %d, snapshot %d", c.Get("a"), snap["a"])
+	}
+}
@@ -0,0 +1,45 @@
+package safedivide
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDivide(t *testing.T) {
+	tests := []struct{ a, b, want int }{{10, 2, 5}, {7, 2, 3}, {-9, 3, -3}, {0, 5, 0}}
+	for _, tt := range tests {
+		got, err := Divide(tt.a, tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("Divide(%d, %d) = %d, %v; want %d, nil", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
+
+func TestDivideByZero(t *testing.T) {
+	if _, err := Divide(1, 0); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf("Divide(1, 0) error = %v, want ErrDivideByZero", err)
+	}
+}
+
+func TestParseAndDivide(t *testing.T) {
+	got, err := ParseAndDivide("84", "2")
+	if err != nil || got != 42 {
+		t.Errorf(`ParseAndDivide("84", "2") = %d, %v; want 42, nil`, got, err)
+	}
+	if _, err := ParseAndDivide("1", "0"); !errors.Is(err, ErrDivideByZero) {
+		t.Errorf(`ParseAndDivide("1", "0") error = %v, want ErrDivideByZero`, err)
+	}
+}
+
+func TestParseAndDivideWrapsParseErrors(t *testing.T) {
+	_, err := ParseAndDivide("12", "ten")
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Fatalf(`ParseAndDivide("12", "ten") error = %v, want one wrapping *strconv.NumError`, err)
+	}
+	if !strings.Contains(err.Error(), "ten") {
+		t.Errorf("error %q should name the bad input", err)
+	}
+}
@@ -0,0 +1,35 @@
+package shapes
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestRect(t *testing.T) {
+	r := Rect{Width: 3, Height: 4}
+	if !near(r.Area(), 12) || !near(r.Perimeter(), 14) {
+		t.Errorf("Rect{3, 4}: area %v, perimeter %v; want 12, 14", r.Area(), r.Perimeter())
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c := Circle{Radius: 2}
+	if !near(c.Area(), 4*math.Pi) || !near(c.Perimeter(), 4*math.Pi) {
+		t.Errorf("Circle{2}: area %v, perimeter %v; want 4π for both", c.Area(), c.Perimeter())
+	}
+}
+
+func TestLargest(t *testing.T) {
+	small, big := Rect{Width: 1, Height: 1}, Circle{Radius: 10}
+	if got := Largest([]Shape{small, big, Rect{Width: 2, Height: 3}}); got != big {
+		t.Errorf("Largest = %v, want %v", got, big)
+	}
+}
+
+func TestLargestEmpty(t *testing.T) {
+	if got := Largest(nil); got != nil {
+		t.Errorf("Largest(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,35 @@
+package topwords
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopWordsOrder(t *testing.T) {
+	text := "the cat and the dog and the bird"
+	if got, want := TopWords(text, 2), []string{"the", "and"}; !slices.Equal(got, want) {
+		t.Errorf("TopWords = %q, want %q", got, want)
+	}
+}
+
+func TestTopWordsCaseAndPunctuation(t *testing.T) {
+	text := "Go, go... GO! Gopher? gopher"
+	if got, want := TopWords(text, 5), []string{"go", "gopher"}; !slices.Equal(got, want) {
+		t.Errorf("TopWords = %q, want %q", got, want)
+	}
+}
+
+func TestTopWordsTiesAlphabetical(t *testing.T) {
+	if got, want := TopWords("pear apple fig apple pear fig kiwi", 3), []string{"apple", "fig", "pear"}; !slices.Equal(got, want) {
+		t.Errorf("TopWords = %q, want %q", got, want)
+	}
+}
+
+func TestTopWordsFewerThanK(t *testing.T) {
+	if got := TopWords("", 3); len(got) != 0 {
+		t.Errorf("TopWords(\"\", 3) = %q, want empty", got)
+	}
+	if got := TopWords("one", 3); !slices.Equal(got, []string{"one"}) {
+		t.Errorf("TopWords(\"one\", 3) = %q", got)
+	}
+}
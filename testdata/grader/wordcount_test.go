@@ -0,0 +1,42 @@
+package wordcount
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCountWords(t *testing.T) {
+	got, err := CountWords(strings.NewReader("the cat and the hat"))
+	want := map[string]int{"the": 2, "cat": 1, "and": 1, "hat": 1}
+	if err != nil || !maps.Equal(got, want) {
+		t.Errorf("CountWords = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestCountWordsNormalises(t *testing.T) {
+	got, err := CountWords(strings.NewReader("Go, go! GO.\n\"go\"\tgopher"))
+	want := map[string]int{"go": 4, "gopher": 1}
+	if err != nil || !maps.Equal(got, want) {
+		t.Errorf("CountWords = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestCountWordsStreams(t *testing.T) {
+	// One byte per Read: solutions that assume a whole line per Read fail
+	got, err := CountWords(iotest.OneByteReader(strings.NewReader("a b a")))
+	if err != nil || got["a"] != 2 || got["b"] != 1 {
+		t.Errorf("CountWords(one byte at a time) = %v, %v", got, err)
+	}
+}
+
+func TestCountWordsReadError(t *testing.T) {
+	boom := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("some words "), iotest.ErrReader(boom))
+	if _, err := CountWords(r); !errors.Is(err, boom) {
+		t.Errorf("CountWords error = %v, want %v", err, boom)
+	}
+}
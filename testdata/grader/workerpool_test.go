@@ -0,0 +1,47 @@
+package workerpool
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapKeepsOrder(t *testing.T) {
+	jobs := []int{5, 1, 4, 2, 3}
+	got := Map(jobs, 3, func(n int) int {
+		time.Sleep(time.Duration(n) * time.Millisecond) // finish out of order
+		return n * n
+	})
+	if want := []int{25, 1, 16, 4, 9}; !slices.Equal(got, want) {
+		t.Errorf("Map = %v, want %v", got, want)
+	}
+}
+
+func TestMapLimitsWorkers(t *testing.T) {
+	var running, most atomic.Int32
+	jobs := make([]int, 20)
+	Map(jobs, 4, func(n int) int {
+		now := running.Add(1)
+		for {
+			m := most.Load()
+			if now <= m || most.CompareAndSwap(m, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return n
+	})
+	if m := most.Load(); m > 4 {
+		t.Errorf("%d calls ran at once, want at most 4", m)
+	} else if m < 2 {
+		t.Errorf("only %d call ran at a time: the jobs should run concurrently", m)
+	}
+}
+
+func TestMapNoJobs(t *testing.T) {
+	if got := Map(nil, 3, func(n int) int { return n }); len(got) != 0 {
+		t.Errorf("Map(nil) = %v, want empty", got)
+	}
+}
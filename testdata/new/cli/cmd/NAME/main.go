@@ -0,0 +1,23 @@
+// Command {{.Name}} counts the lines, words and bytes of files, or of its
+// standard input.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"{{.Module}}/internal/app"
+)
+
+func main() {
+	err := app.Run(os.Args[1:], os.Stdin, os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "{{.Name}}:", err)
+		os.Exit(1)
+	}
+}
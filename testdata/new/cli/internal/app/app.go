@@ -0,0 +1,104 @@
+// Package app is everything {{.Name}} does. main only hands it the
+// arguments and the standard streams, so the tests can too.
+package app
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"unicode"
+)
+
+// Counts are what is counted in one input.
+type Counts struct {
+	Lines, Words, Bytes int
+}
+
+func (c *Counts) add(other Counts) {
+	c.Lines += other.Lines
+	c.Words += other.Words
+	c.Bytes += other.Bytes
+}
+
+// Count reads r to the end.
+func Count(r io.Reader) (Counts, error) {
+	var c Counts
+	br := bufio.NewReader(r)
+	inWord := false
+	for {
+		ch, size, err := br.ReadRune()
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return c, err
+		}
+		c.Bytes += size
+		if ch == '\n' {
+			c.Lines++
+		}
+		if unicode.IsSpace(ch) {
+			inWord = false
+		} else if !inWord {
+			c.Words++
+			inWord = true
+		}
+	}
+}
+
+// Run runs the command with args, not including the program name.
+func Run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("{{.Name}}", flag.ContinueOnError)
+	linesOnly := flags.Bool("l", false, "only count lines")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: {{.Name}} [-l] [file...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 1, ' ', tabwriter.AlignRight)
+	defer tw.Flush()
+	print := func(c Counts, name string) {
+		if *linesOnly {
+			fmt.Fprintf(tw, "%d\t %s\n", c.Lines, name)
+			return
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t %s\n", c.Lines, c.Words, c.Bytes, name)
+	}
+
+	if flags.NArg() == 0 {
+		c, err := Count(stdin)
+		if err != nil {
+			return err
+		}
+		print(c, "")
+		return nil
+	}
+	var total Counts
+	for _, name := range flags.Args() {
+		c, err := countFile(name)
+		if err != nil {
+			return err
+		}
+		print(c, name)
+		total.add(c)
+	}
+	if flags.NArg() > 1 {
+		print(total, "total")
+	}
+	return nil
+}
+
+func countFile(name string) (Counts, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Counts{}, err
+	}
+	defer f.Close()
+	return Count(f)
+}
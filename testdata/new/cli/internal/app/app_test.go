@@ -0,0 +1,58 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Counts
+	}{
+		{"", Counts{}},
+		{"hello\n", Counts{1, 1, 6}},
+		{"one two  three\nfour\n", Counts{2, 4, 20}},
+		{"no newline", Counts{0, 2, 10}},
+		{"héllo wörld\n", Counts{1, 2, 14}},
+	}
+	for _, tt := range tests {
+		got, err := Count(strings.NewReader(tt.in))
+		if err != nil || got != tt.want {
+			t.Errorf("Count(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	os.WriteFile(a, []byte("one two\n"), 0o644)
+	os.WriteFile(b, []byte("three\nfour\n"), 0o644)
+
+	var out bytes.Buffer
+	if err := Run([]string{"-l", a, b}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	// The counts are right-aligned, so only compare the fields
+	want := []string{"1", a, "2", b, "3", "total"}
+	if got := strings.Fields(out.String()); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("output:\n%s\nwant the fields %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := Run(nil, strings.NewReader("a b c\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(out.String()); strings.Join(got, " ") != "1 3 6" {
+		t.Errorf("counting stdin printed %q", out.String())
+	}
+
+	if err := Run([]string{filepath.Join(dir, "missing")}, nil, &out); err == nil {
+		t.Error("a missing file should be an error")
+	}
+}
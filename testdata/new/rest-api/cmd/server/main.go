@@ -0,0 +1,45 @@
+// Command server runs the {{.Name}} HTTP API.
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"{{.Module}}/internal/api"
+	"{{.Module}}/internal/config"
+	"{{.Module}}/internal/store"
+)
+
+func main() {
+	cfg := config.Load()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           api.NewServer(store.NewMemory(), logger),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// Stop accepting requests on Ctrl+C or SIGTERM, and let the ones in
+	// flight finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	logger.Info("listening", "addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+}
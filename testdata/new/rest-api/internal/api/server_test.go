@@ -0,0 +1,47 @@
+package api
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"{{.Module}}/internal/store"
+)
+
+func newTestServer() http.Handler {
+	return NewServer(store.NewMemory(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestItems(t *testing.T) {
+	srv := newTestServer()
+
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+		wantBody           string
+	}{
+		{"GET", "/health", "", http.StatusOK, `{"status":"ok"}`},
+		{"GET", "/items", "", http.StatusOK, `[]`},
+		{"POST", "/items", `{"name":"gopher"}`, http.StatusCreated, `{"id":1,"name":"gopher"}`},
+		{"GET", "/items/1", "", http.StatusOK, `{"id":1,"name":"gopher"}`},
+		{"GET", "/items", "", http.StatusOK, `[{"id":1,"name":"gopher"}]`},
+		{"GET", "/items/2", "", http.StatusNotFound, `{"error":"item not found"}`},
+		{"GET", "/items/x", "", http.StatusBadRequest, `{"error":"id must be a number"}`},
+		{"POST", "/items", `{"name":" "}`, http.StatusBadRequest, `{"error":"name is required"}`},
+		{"POST", "/items", `{`, http.StatusBadRequest, `{"error":"invalid JSON body"}`},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.path, rec.Code, tt.wantStatus)
+		}
+		if got := strings.TrimSpace(rec.Body.String()); got != tt.wantBody {
+			t.Errorf("%s %s: body %s, want %s", tt.method, tt.path, got, tt.wantBody)
+		}
+	}
+}
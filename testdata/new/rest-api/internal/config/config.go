@@ -0,0 +1,23 @@
+// Package config reads the settings of the server from the environment.
+package config
+
+import "os"
+
+// Config is everything the server can be told at start-up.
+type Config struct {
+	Port string
+}
+
+// Load reads the config, with defaults for anything unset.
+func Load() Config {
+	return Config{
+		Port: getEnv("PORT", "8080"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,56 @@
+// Package store keeps the items the API serves.
+package store
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotFound means there is no item with the id asked for.
+var ErrNotFound = errors.New("item not found")
+
+// Item is one thing in the store.
+type Item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Memory is a store in memory, safe for concurrent use. Swap it for one
+// backed by a database without touching the API: it only sees the
+// interface it needs.
+type Memory struct {
+	mu    sync.RWMutex
+	items []Item
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// List returns every item, oldest first.
+func (m *Memory) List() []Item {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Item{}, m.items...) // [] in JSON, not null, when empty
+}
+
+// Create adds an item and returns it with its new id.
+func (m *Memory) Create(name string) Item {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item := Item{ID: len(m.items) + 1, Name: name}
+	m.items = append(m.items, item)
+	return item
+}
+
+// Get looks an item up by id.
+func (m *Memory) Get(id int) (Item, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, item := range m.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return Item{}, ErrNotFound
+}
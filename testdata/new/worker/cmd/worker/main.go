@@ -0,0 +1,56 @@
+// Command worker runs the {{.Name}} job workers until it is stopped.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"{{.Module}}/internal/config"
+	"{{.Module}}/internal/worker"
+)
+
+func main() {
+	cfg := config.Load()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	// Ctrl+C or SIGTERM stops queueing; the jobs in hand still finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jobs := make(chan worker.Job)
+	go produce(ctx, jobs, cfg.Interval)
+
+	pool := &worker.Pool{Workers: cfg.Workers, Logger: logger, Handle: func(ctx context.Context, job worker.Job) error {
+		// Replace with the real work: send an email, resize an image...
+		logger.Info("handled job", "id", job.ID, "payload", job.Payload)
+		return nil
+	}}
+	logger.Info("started", "workers", cfg.Workers, "interval", cfg.Interval)
+	stats := pool.Run(ctx, jobs)
+	logger.Info("stopped", "done", stats.Done, "failed", stats.Failed)
+}
+
+// produce queues a job every interval until ctx is done. A real worker
+// would read them from a queue such as Redis or a database table.
+func produce(ctx context.Context, jobs chan<- worker.Job, interval time.Duration) {
+	defer close(jobs)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for id := 1; ; id++ {
+		select {
+		case <-ctx.Done():
+			return
+		case at := <-ticker.C:
+			select {
+			case jobs <- worker.Job{ID: id, Payload: fmt.Sprint("queued at ", at.Format(time.TimeOnly))}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package config reads the settings of the worker from the environment.
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config is everything the worker can be told at start-up.
+type Config struct {
+	Workers  int           // jobs handled at once
+	Interval time.Duration // how often a job is queued
+}
+
+// Load reads the config, with defaults for anything unset or invalid.
+func Load() Config {
+	return Config{
+		Workers:  getEnvInt("WORKERS", 4),
+		Interval: getEnvDuration("INTERVAL", time.Second),
+	}
+}
+
+func getEnvInt(key string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
@@ -0,0 +1,62 @@
+// Package worker handles jobs from a queue with a fixed number of
+// goroutines.
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// Job is one unit of work.
+type Job struct {
+	ID      int
+	Payload string
+}
+
+// Handler does one job. An error fails the job but not the pool.
+type Handler func(ctx context.Context, job Job) error
+
+// Stats counts the jobs a pool has handled.
+type Stats struct {
+	Done, Failed int64
+}
+
+// Pool runs Workers goroutines that take jobs and hand them to Handle.
+type Pool struct {
+	Workers int
+	Handle  Handler
+	Logger  *slog.Logger
+}
+
+// Run handles jobs until the channel is closed or ctx is cancelled, then
+// waits for the jobs in hand to finish.
+func (p *Pool) Run(ctx context.Context, jobs <-chan Job) Stats {
+	var done, failed atomic.Int64
+	var wg sync.WaitGroup
+	for range max(p.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job, ok := <-jobs:
+					if !ok {
+						return
+					}
+					if err := p.Handle(ctx, job); err != nil {
+						failed.Add(1)
+						p.Logger.Warn("job failed", "id", job.ID, "err", err)
+						continue
+					}
+					done.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return Stats{Done: done.Load(), Failed: failed.Load()}
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestPool(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	p := &Pool{Workers: 4, Logger: discard, Handle: func(ctx context.Context, job Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[job.ID] = true
+		if job.ID%10 == 0 {
+			return errors.New("multiple of ten")
+		}
+		return nil
+	}}
+
+	jobs := make(chan Job)
+	go func() {
+		defer close(jobs)
+		for i := 1; i <= 100; i++ {
+			jobs <- Job{ID: i}
+		}
+	}()
+	stats := p.Run(context.Background(), jobs)
+	if stats.Done != 90 || stats.Failed != 10 {
+		t.Errorf("stats = %+v, want 90 done and 10 failed", stats)
+	}
+	if len(seen) != 100 {
+		t.Errorf("%d jobs were handled, want 100", len(seen))
+	}
+}
+
+func TestPoolStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{Workers: 2, Logger: discard, Handle: func(ctx context.Context, job Job) error { return nil }}
+	stopped := make(chan Stats)
+	go func() { stopped <- p.Run(ctx, make(chan Job)) }()
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Run didn't return after the context was cancelled")
+	}
+}
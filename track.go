@@ -0,0 +1,261 @@
+package learn
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// track is a path through the courses towards one kind of work, ending
+// with capstone projects from examples/.
+type track struct {
+	name      string
+	title     string
+	summary   string
+	courses   []int    // in the order to take them; a course's requirements come before it
+	capstones []string // directories under examples/
+}
+
+var tracks = []track{
+	{"backend", "Web Backend", "HTTP services: routing, validation, errors, SQL, structure and safe concurrency",
+		[]int{1, 2, 3, 6, 16, 18, 7, 10, 11, 12, 17, 4, 19, 21, 14},
+		[]string{"todo-api", "urlshortener", "proxy"}},
+	{"cli", "CLI & Tooling", "command-line tools: files, streams, testing, modules and workspaces",
+		[]int{1, 2, 3, 5, 20, 10, 11, 14, 15, 4, 28, 29, 30, 32, 13},
+		[]string{"expenses", "ssg", "loganalyzer"}},
+	{"data", "Data & Databases", "storing and moving data: files, streams, SQL, MongoDB, Redis and concurrent stores",
+		[]int{1, 2, 3, 5, 20, 6, 7, 8, 9, 22, 10, 4, 19, 23, 24, 11, 12, 25, 26, 27, 28, 29, 30, 13},
+		[]string{"kvstore", "urlshortener", "loganalyzer"}},
+	{"sre", "SRE & Performance", "reliable, fast services: concurrency, races, profiling, panics and load",
+		[]int{1, 2, 3, 4, 6, 10, 19, 31, 32, 13, 16, 17, 5, 20, 29},
+		[]string{"loadtest", "crawler", "bank"}},
+}
+
+// capstoneSummaries describe the capstones, as in the README.
+var capstoneSummaries = map[string]string{
+	"todo-api":     "TODO REST API with cmd/, internal/, SQLite, middleware and tests",
+	"urlshortener": "URL shortener: base62 codes, SQLite, Redis click counts",
+	"proxy":        "reverse proxy: prefix routing, per-client rate limits, request IDs",
+	"expenses":     "CLI expense tracker: subcommands, JSON or SQLite storage, reports",
+	"ssg":          "static site generator: Markdown, front matter, templates",
+	"loganalyzer":  "access-log analyzer: reader/worker pipeline, gzip, reports",
+	"kvstore":      "key-value server over TCP with append-only log persistence",
+	"loadtest":     "HTTP load generator: worker pool, latency percentiles, histogram",
+	"crawler":      "concurrent link checker: worker pool, robots.txt, cancellation",
+	"bank":         "concurrent transfers without races or deadlocks, with benchmarks",
+}
+
+func findTrack(name string) (track, bool) {
+	for _, t := range tracks {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return track{}, false
+}
+
+// trackArg looks up a track named on the command line.
+func trackArg(name string) (track, error) {
+	t, ok := findTrack(name)
+	if !ok {
+		var known []string
+		for _, t := range tracks {
+			known = append(known, t.name)
+		}
+		return track{}, fmt.Errorf("unknown track %q (available: %s)", name, strings.Join(known, ", "))
+	}
+	return t, nil
+}
+
+// courseArg reads a course named on the command line with --track: a
+// course of the track, or "all" for the whole track in its order.
+func (t track) courseArg(arg string) ([]course, error) {
+	if arg == "all" {
+		var list []course
+		for _, n := range t.courses {
+			c, _ := findCourse(n)
+			list = append(list, c)
+		}
+		return list, nil
+	}
+	list, err := courseArg(arg)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(t.courses, list[0].number) {
+		return nil, fmt.Errorf("course %d is not part of the %s track", list[0].number, t.title)
+	}
+	return list, nil
+}
+
+// nextCourse is the first course of the track that hasn't been read to
+// the end.
+func (t track) nextCourse(log *studyLog) (course, bool) {
+	for _, n := range t.courses {
+		if log.Courses[n].Finished == 0 {
+			return findCourse(n)
+		}
+	}
+	return course{}, false
+}
+
+// runTrack implements "go run ./cmd/learn track [name [capstone name]]".
+func runTrack(args []string) error {
+	flags := flag.NewFlagSet("track", flag.ContinueOnError)
+	progressPath := flags.String("progress", defaultProgressPath(), "progress file")
+	timeout := flags.Duration("timeout", 5*time.Minute, "time limit for a capstone's tests")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn track [flags] [name]")
+		fmt.Fprintln(flags.Output(), "       go run ./cmd/learn track [flags] <name> capstone <capstone>")
+		fmt.Fprintln(flags.Output(), "Lists the learning tracks, shows where you are in one, or checks one of")
+		fmt.Fprintln(flags.Output(), "its capstones by running its tests. Follow a track with: go run ./cmd/learn --track <name>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	p, err := loadProgress(*progressPath)
+	if err != nil {
+		return fmt.Errorf("reading progress: %w", err)
+	}
+	log, err := loadStudyLog(studyLogPath(*progressPath))
+	if err != nil {
+		return fmt.Errorf("reading the time log: %w", err)
+	}
+	stats := courseStats(p, log)
+
+	switch {
+	case flags.NArg() == 0:
+		listTracks(os.Stdout, stats, p)
+		return nil
+	case flags.NArg() == 1:
+		t, err := trackArg(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		showTrack(os.Stdout, t, stats, p.Tracks[t.name])
+		return nil
+	case flags.NArg() == 3 && flags.Arg(1) == "capstone":
+		t, err := trackArg(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		return checkCapstone(ctx, t, flags.Arg(2), p, *progressPath)
+	}
+	flags.Usage()
+	return errors.New("expected a track name, or a track and a capstone")
+}
+
+// trackStatus counts a track's finished courses and passed capstones.
+func trackStatus(t track, stats []courseStat, tp trackProgress) (courses, capstones int) {
+	for _, s := range stats {
+		if d, total := s.done(); d == total && slices.Contains(t.courses, s.course.number) {
+			courses++
+		}
+	}
+	for _, name := range t.capstones {
+		if tp.Capstones[name] != "" {
+			capstones++
+		}
+	}
+	return courses, capstones
+}
+
+func listTracks(w io.Writer, stats []courseStat, p *progress) {
+	fmt.Fprintln(w, "TRACKS")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, t := range tracks {
+		tp := p.Tracks[t.name]
+		courses, capstones := trackStatus(t, stats, tp)
+		status := fmt.Sprintf("%d/%d capstones", capstones, len(t.capstones))
+		if tp.Started != "" {
+			status += ", started " + tp.Started
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%d/%d courses\t%s\n", t.name, t.title, courses, len(t.courses), status)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nSee one with: go run ./cmd/learn track <name>; follow it with: go run ./cmd/learn --track <name>")
+}
+
+func showTrack(w io.Writer, t track, stats []courseStat, tp trackProgress) {
+	courses, capstones := trackStatus(t, stats, tp)
+	fmt.Fprintf(w, "TRACK %s: %s\n", t.name, t.title)
+	fmt.Fprintf(w, "For %s.\n", t.summary)
+	fmt.Fprintf(w, "%d/%d courses done, %d/%d capstones passed", courses, len(t.courses), capstones, len(t.capstones))
+	if tp.Started != "" {
+		fmt.Fprintf(w, ", started %s", tp.Started)
+	}
+	fmt.Fprint(w, "\n\n")
+
+	byNumber := map[int]courseStat{}
+	for _, s := range stats {
+		byNumber[s.course.number] = s
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	marked := false
+	for _, n := range t.courses {
+		s := byNumber[n]
+		d, total := s.done()
+		status := fmt.Sprintf("%d%%", d*100/total)
+		if d < total && !marked {
+			status, marked = status+"  <- next", true
+		}
+		fmt.Fprintf(tw, "%3d. %s\t%s\n", n, s.course.name, status)
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w, "\nCAPSTONES")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range t.capstones {
+		status := ""
+		if day := tp.Capstones[name]; day != "" {
+			status = "passed " + day
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", name, status, capstoneSummaries[name])
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nContinue with: go run ./cmd/learn --track %s\n", t.name)
+	fmt.Fprintf(w, "Extend a capstone under examples/, then check it: go run ./cmd/learn track %s capstone <name>\n", t.name)
+}
+
+// checkCapstone runs a capstone's tests and records it as passed for the
+// track. The capstones are separate modules, tested on their own.
+func checkCapstone(ctx context.Context, t track, name string, p *progress, progressPath string) error {
+	if !slices.Contains(t.capstones, name) {
+		return fmt.Errorf("%s is not a capstone of the %s track (its capstones: %s)", name, t.title, strings.Join(t.capstones, ", "))
+	}
+	dir := filepath.Join("examples", name)
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		return fmt.Errorf("no capstone module in %s: run this from the repository root", dir)
+	}
+	fmt.Printf("Testing %s...\n", dir)
+	out, err := goCommand(ctx, dir, "test", "./...")
+	if err != nil {
+		os.Stdout.Write(out)
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s: tests timed out", name)
+		}
+		return fmt.Errorf("%s: tests failed", name)
+	}
+	p, err = updateProgress(progressPath, func(p *progress) error {
+		p.recordCapstone(t.name, name, time.Now())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, passed := trackStatus(t, nil, p.Tracks[t.name])
+	fmt.Printf("%s passes: %d/%d capstones of the %s track.\n", name, passed, len(t.capstones), t.title)
+	return nil
+}
@@ -0,0 +1,68 @@
+package learn
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSelectCoursesWithTrack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	log := &studyLog{Courses: map[int]courseTime{1: {Finished: 1}, 2: {Finished: 1}}}
+	if err := log.save(studyLogPath(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	// No course: the track's next one
+	got, err := selectCourses(nil, "backend", path)
+	if err != nil || len(got) != 1 || got[0].number != 3 {
+		t.Fatalf("next course of the backend track = %v, %v; want course 3", got, err)
+	}
+	p, err := loadProgress(path)
+	if err != nil || p.Tracks["backend"].Started == "" {
+		t.Errorf("following the track wasn't recorded: %+v, %v", p.Tracks, err)
+	}
+
+	got, err = selectCourses([]string{"all"}, "sre", path)
+	if err != nil || len(got) != 15 || got[3].number != 4 || got[4].number != 6 {
+		t.Errorf("the sre track in order = %v, %v", got, err)
+	}
+	if _, err := selectCourses([]string{"8"}, "cli", path); err == nil || !strings.Contains(err.Error(), "not part of the CLI & Tooling track") {
+		t.Errorf("course 8 with the cli track: %v", err)
+	}
+	if _, err := selectCourses(nil, "", path); err != errNoCourse {
+		t.Errorf("no course and no track: %v", err)
+	}
+	if _, err := selectCourses(nil, "games", path); err == nil {
+		t.Error("accepted an unknown track")
+	}
+}
+
+func TestShowTrack(t *testing.T) {
+	p := &progress{
+		Exercises: map[string]exerciseProgress{"fizzbuzz": {Best: 100}, "reverse": {Best: 100}},
+		Quizzes:   map[int]quizProgress{1: {Best: 100}, 2: {Best: 20}},
+		Tracks:    map[string]trackProgress{},
+	}
+	log := &studyLog{Courses: map[int]courseTime{1: {Finished: 1}, 2: {Finished: 1}}}
+	day := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	p.recordCapstone("cli", "ssg", day)
+	p.recordCapstone("cli", "ssg", day.AddDate(0, 0, 1))
+
+	var buf bytes.Buffer
+	cli, _ := findTrack("cli")
+	showTrack(&buf, cli, courseStats(p, log), p.Tracks["cli"])
+	for _, want := range []string{
+		"1/15 courses done, 1/3 capstones passed, started 2024-03-01",
+		"  1. BASICS                 100%\n",
+		"  2. FUNCTIONS & ERRORS     33%  <- next\n",
+		" 20. IO STREAMS             0%\n",
+		"  ssg          passed 2024-03-01  static site generator",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("track missing %q:\n%s", want, buf.String())
+		}
+	}
+}
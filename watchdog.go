@@ -0,0 +1,105 @@
+package learn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime/pprof"
+	"sync"
+	"time"
+)
+
+// courseTimeout is how long a course may run before the watchdog decides
+// it is stuck, as a demo with a blocked channel would be. Time spent
+// waiting at a paced prompt doesn't count. 0 is no limit. It is set by
+// --timeout.
+var courseTimeout = 2 * time.Minute
+
+// watchdog fires when its time runs out, unless paused.
+type watchdog struct {
+	fired chan struct{}
+
+	mu     sync.Mutex
+	timer  *time.Timer
+	left   time.Duration // while paused
+	since  time.Time     // when it was last started
+	paused bool
+}
+
+// watchdogKey is the context key of the watchdog of the course running,
+// if any. The lesson pauses it while the learner reads. A course left
+// behind hung keeps its own, in its context, and no other run's.
+type watchdogKey struct{}
+
+// watchdogFrom returns the watchdog in ctx, or nil, which does nothing.
+func watchdogFrom(ctx context.Context) *watchdog {
+	wd, _ := ctx.Value(watchdogKey{}).(*watchdog)
+	return wd
+}
+
+func newWatchdog(limit time.Duration) *watchdog {
+	w := &watchdog{fired: make(chan struct{}), left: limit, since: time.Now()}
+	w.timer = time.AfterFunc(limit, func() { close(w.fired) })
+	return w
+}
+
+func (w *watchdog) pause() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.paused && w.timer.Stop() {
+		w.left -= time.Since(w.since)
+		w.paused = true
+	}
+}
+
+func (w *watchdog) resume() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.paused {
+		w.since, w.paused = time.Now(), false
+		w.timer.Reset(w.left)
+	}
+}
+
+// runWatched runs course c, writing to w, on a goroutine of its own, so
+// that one that hangs can be left behind. It returns the error the course
+// failed with, or what it panicked with, such as a paceStop. If it runs
+// past courseTimeout, the stacks of all goroutines are written to stderr
+// to show where it is stuck, its context is canceled, in case it ever gets
+// to its next section, and it reports that the course hung.
+func runWatched(ctx context.Context, c course, w io.Writer) (panicked any, hung bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var fired <-chan struct{} // nil, which never is ready, with no limit
+	if courseTimeout > 0 {
+		wd := newWatchdog(courseTimeout)
+		ctx, fired = context.WithValue(ctx, watchdogKey{}, wd), wd.fired
+		defer wd.timer.Stop()
+	}
+	type ending struct {
+		panicked any
+		err      error
+	}
+	done := make(chan ending, 1)
+	go func() {
+		var err error
+		defer func() { done <- ending{recover(), err} }()
+		err = c.run(ctx, w)
+	}()
+
+	select {
+	case e := <-done:
+		return e.panicked, false, e.err
+	case <-fired:
+		fmt.Fprintf(os.Stderr, "\ncourse %d has run for %v without finishing: it looks stuck. Its goroutines:\n\n", c.number, courseTimeout)
+		pprof.Lookup("goroutine").WriteTo(os.Stderr, 2)
+		return nil, true, nil
+	}
+}
@@ -0,0 +1,121 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stuck is a course with a demo blocked on a channel until the test ends.
+func stuck(t *testing.T) course {
+	block := make(chan struct{})
+	t.Cleanup(func() { close(block) })
+	return course{number: 42, run: func(context.Context, io.Writer) error {
+		<-block
+		return nil
+	}}
+}
+
+func TestWatchdogStopsStuckCourse(t *testing.T) {
+	courseTimeout = 50 * time.Millisecond
+	defer func() { courseTimeout = 2 * time.Minute }()
+	stderr := os.Stderr
+	f, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = f
+	defer func() { os.Stderr = stderr }()
+
+	if o := runCourse(context.Background(), stuck(t)); o.quit || o.err == nil {
+		t.Errorf("a stuck course: quit = %v, err = %v; want it failed", o.quit, o.err)
+	}
+	err = runCaptured(context.Background(), stuck(t), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "hung") {
+		t.Errorf("runCaptured of a stuck course: err = %v", err)
+	}
+
+	dump, _ := os.ReadFile(f.Name())
+	if !strings.Contains(string(dump), "course 42 has run for 50ms") || !strings.Contains(string(dump), "stuck.func") {
+		t.Errorf("no goroutine dump showing where it's stuck:\n%s", dump)
+	}
+}
+
+// slowReader is a learner who takes d to press each key.
+type slowReader struct {
+	d time.Duration
+	r io.Reader
+}
+
+func (s slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.d)
+	return s.r.Read(p[:1])
+}
+
+func TestWatchdogPausedAtPrompt(t *testing.T) {
+	courseTimeout = 50 * time.Millisecond
+	defer func() { courseTimeout = 2 * time.Minute }()
+
+	l, _ := parseLesson(1, sampleLesson)
+	var buf bytes.Buffer
+	c := course{number: 1, run: func(ctx context.Context, _ io.Writer) error {
+		r := newLessonRun(ctx, l, &termRenderer{w: &buf, width: 80})
+		r.Resume()
+		r.Section("first")
+		r.Section("second")
+		r.End()
+		return nil
+	}}
+	pace = newPacer(slowReader{40 * time.Millisecond, strings.NewReader("\n\n")})
+	defer func() { pace = nil }()
+	runCourse(context.Background(), c)
+	if !strings.Contains(buf.String(), "END OF") {
+		t.Errorf("reading for longer than the timeout stopped the course:\n%s", buf.String())
+	}
+}
+
+// A course left behind hung still holds its watchdog: when it gets going
+// again it pauses that one, not the next course's, and nothing races.
+// Run with -race.
+func TestWatchdogOfHungCourse(t *testing.T) {
+	courseTimeout = 50 * time.Millisecond
+	defer func() { courseTimeout = 2 * time.Minute }()
+	stderr := os.Stderr
+	os.Stderr, _ = os.Open(os.DevNull)
+	defer func() { os.Stderr = stderr }()
+
+	unblock, left := make(chan struct{}), make(chan struct{})
+	hung := course{number: 42, run: func(ctx context.Context, _ io.Writer) error {
+		defer close(left)
+		<-unblock
+		wd := watchdogFrom(ctx)
+		for range 100 {
+			wd.pause()
+			wd.resume()
+		}
+		if wd == nil {
+			t.Error("the course's context has no watchdog")
+		}
+		return nil
+	}}
+	if _, stopped, _ := runWatched(context.Background(), hung, io.Discard); !stopped {
+		t.Fatal("the course didn't hang")
+	}
+
+	// The next course runs while the hung one wakes up
+	next := course{number: 1, run: func(ctx context.Context, _ io.Writer) error {
+		close(unblock)
+		<-left
+		if watchdogFrom(ctx) == nil {
+			t.Error("the next course's context has no watchdog")
+		}
+		return nil
+	}}
+	if _, stopped, err := runWatched(context.Background(), next, io.Discard); stopped || err != nil {
+		t.Errorf("next course: hung %v, err %v", stopped, err)
+	}
+}
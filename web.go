@@ -0,0 +1,314 @@
+package learn
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// runWeb implements "go run ./cmd/learn web [flags]".
+func runWeb(args []string) error {
+	flags := flag.NewFlagSet("web", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8085", "address to listen on")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go run ./cmd/learn web [flags]")
+		fmt.Fprintln(flags.Output(), "Serves the course in your browser: read the lessons and run the demos.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	s, err := newWebServer(runCaptured)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Course running on http://%s\n", *addr)
+	return http.ListenAndServe(*addr, s.routes())
+}
+
+// webServer is the course as a local web app. Progress is kept per
+// browser, in memory: it lasts as long as the server does, or until the
+// browser has been away for sessionIdle.
+type webServer struct {
+	book *book
+	tmpl map[string]*template.Template
+	run  func(ctx context.Context, c course, w io.Writer) error // runs a course's demos, writing their output to w
+
+	mu       sync.Mutex
+	sessions map[string]*webSession
+}
+
+// webSession is what one browser has done.
+type webSession struct {
+	Viewed map[int]bool
+	Ran    map[int]bool // demos run to the end
+	used   time.Time    // the browser's last request
+}
+
+// sessionIdle is how long a session is kept after its browser's last
+// request. Every request without a known cookie starts a session, so
+// without an end the map would grow for as long as the server runs.
+const sessionIdle = 24 * time.Hour
+
+func newWebServer(run func(ctx context.Context, c course, w io.Writer) error) (*webServer, error) {
+	b, err := loadBook("")
+	if err != nil {
+		return nil, err
+	}
+	s := &webServer{book: b, run: run, tmpl: make(map[string]*template.Template), sessions: make(map[string]*webSession)}
+	for _, name := range []string{"index.html", "course.html"} {
+		t, err := template.ParseFS(exportTemplates, "templates/web/layout.html", "templates/web/"+name)
+		if err != nil {
+			return nil, err
+		}
+		s.tmpl[name] = t
+	}
+	return s, nil
+}
+
+func (s *webServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /courses/{number}", s.handleCourse)
+	mux.HandleFunc("GET /courses/{number}/run", s.handleRun)
+	mux.HandleFunc("GET /style.css", s.handleAsset("templates/html/style.css"))
+	mux.HandleFunc("GET /web.css", s.handleAsset("templates/web/web.css"))
+	return mux
+}
+
+// session returns the browser's session, starting one if the request has
+// no (or an unknown) session cookie. Starting one drops those idle for
+// longer than sessionIdle.
+func (s *webServer) session(w http.ResponseWriter, r *http.Request) *webSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if c, err := r.Cookie("session"); err == nil {
+		if sess, ok := s.sessions[c.Value]; ok {
+			sess.used = now
+			return sess
+		}
+	}
+	for id, sess := range s.sessions {
+		if now.Sub(sess.used) > sessionIdle {
+			delete(s.sessions, id)
+		}
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	sess := &webSession{Viewed: make(map[int]bool), Ran: make(map[int]bool), used: now}
+	s.sessions[hex.EncodeToString(id)] = sess
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: hex.EncodeToString(id), Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+	return sess
+}
+
+// webPage is what the templates see.
+type webPage struct {
+	Book     *book
+	Course   *bookCourse
+	Viewed   map[int]bool
+	Ran      map[int]bool
+	Progress int // percent of courses whose demos were run
+}
+
+func (s *webServer) page(w http.ResponseWriter, r *http.Request, name string, c *bookCourse) {
+	sess := s.session(w, r)
+	s.mu.Lock()
+	if c != nil {
+		sess.Viewed[c.Number] = true
+	}
+	// Copies, so the template doesn't read the maps while a run updates them
+	data := webPage{Book: s.book, Course: c, Viewed: make(map[int]bool), Ran: make(map[int]bool)}
+	for n := range sess.Viewed {
+		data.Viewed[n] = true
+	}
+	for n := range sess.Ran {
+		data.Ran[n] = true
+	}
+	s.mu.Unlock()
+	data.Progress = 100 * len(data.Ran) / len(s.book.Courses)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := s.tmpl[name].ExecuteTemplate(w, "layout.html", data); err != nil {
+		log.Printf("web: %s: %v", r.URL.Path, err)
+	}
+}
+
+func (s *webServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	s.page(w, r, "index.html", nil)
+}
+
+func (s *webServer) handleCourse(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.course(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.page(w, r, "course.html", c)
+}
+
+func (s *webServer) course(r *http.Request) (*bookCourse, bool) {
+	n, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		return nil, false
+	}
+	for _, c := range s.book.Courses {
+		if c.Number == n {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// handleRun runs a course's demos and streams their output as
+// server-sent events: one "data:" event per line, then a "done" event.
+func (s *webServer) handleRun(w http.ResponseWriter, r *http.Request) {
+	bc, ok := s.course(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	c, _ := findCourse(bc.Number)
+	sess := s.session(w, r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	sse := &sseWriter{w: w, flush: flusher.Flush}
+	err := s.run(r.Context(), c, sse)
+	sse.Close()
+	if err != nil {
+		fmt.Fprintf(w, "event: failed\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+		flusher.Flush()
+		return
+	}
+
+	s.mu.Lock()
+	sess.Ran[c.number] = true
+	s.mu.Unlock()
+	fmt.Fprint(w, "event: done\ndata: \n\n")
+	flusher.Flush()
+}
+
+func (s *webServer) handleAsset(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := exportTemplates.ReadFile(name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/css; charset=utf-8")
+		w.Write(data)
+	}
+}
+
+// sseWriter turns written text into server-sent events, one per line.
+// Write never fails: if the browser has gone, the demo must still be able
+// to finish, so the output is thrown away.
+type sseWriter struct {
+	w       io.Writer
+	flush   func()
+	partial []byte
+	gone    bool
+}
+
+func (s *sseWriter) Write(p []byte) (int, error) {
+	s.partial = append(s.partial, p...)
+	for {
+		i := bytes.IndexByte(s.partial, '\n')
+		if i < 0 {
+			break
+		}
+		s.event(string(s.partial[:i]))
+		s.partial = s.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Close sends a last line that had no newline.
+func (s *sseWriter) Close() error {
+	if len(s.partial) > 0 {
+		s.event(string(s.partial))
+		s.partial = nil
+	}
+	return nil
+}
+
+func (s *sseWriter) event(line string) {
+	if s.gone {
+		return
+	}
+	if _, err := fmt.Fprintf(s.w, "data: %s\n\n", line); err != nil {
+		s.gone = true
+		return
+	}
+	s.flush()
+}
+
+// runMu serialises demo runs: the courses share the clock and the time
+// log, and two runs would trip over each other.
+var runMu sync.Mutex
+
+// runCaptured runs a course with its output going to w, through a pipe
+// that is closed when the run ends, so that a course left behind hung
+// can't write to w afterwards. A failure or a panic escaping the course is
+// reported as an error rather than stopping the server, and so are ctx
+// being done before the end and the course hanging.
+func runCaptured(ctx context.Context, c course, w io.Writer) (err error) {
+	runMu.Lock()
+	defer runMu.Unlock()
+	// The browser going away ends the demos' waits, as Ctrl+C does on the
+	// command line, so the next visitor's run isn't kept waiting for runMu.
+	// A fake clock (--fast, the tests) never waits, and is kept.
+	if clock, ok := demo.Clock.(demo.RealClock); ok {
+		demo.Clock = demo.RealClock{Done: ctx.Done()}
+		defer func() { demo.Clock = clock }()
+	}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(w, pr)
+		close(copied)
+	}()
+
+	p, hung, err := runInWorkspace(ctx, c, pw)
+	switch _, stopped := p.(paceStop); {
+	case err != nil:
+		err = fmt.Errorf("course %d failed: %w", c.number, err)
+	case stopped && ctx.Err() != nil:
+		err = fmt.Errorf("course %d stopped: %w", c.number, ctx.Err())
+	case p != nil:
+		err = fmt.Errorf("course %d panicked: %v", c.number, p)
+	case hung:
+		err = fmt.Errorf("course %d hung: stopped after %v", c.number, courseTimeout)
+	}
+
+	pw.Close()
+	<-copied
+	pr.Close()
+	return err
+}
@@ -0,0 +1,170 @@
+package learn
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+// Run with: go test -run Web
+
+func TestWebRunStreamsOutput(t *testing.T) {
+	fake := func(_ context.Context, c course, w io.Writer) error {
+		fmt.Fprintf(w, "demo %d\nsecond line\nno newline", c.number)
+		return nil
+	}
+	s, err := newWebServer(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/courses/3"); code != 200 || !strings.Contains(body, `id="interfaces"`) {
+		t.Fatalf("course page: %d\n%s", code, body)
+	}
+	if code, _ := get("/courses/99"); code != 404 {
+		t.Errorf("unknown course: got %d, want 404", code)
+	}
+
+	_, events := get("/courses/3/run")
+	want := "data: demo 3\n\ndata: second line\n\ndata: no newline\n\nevent: done\ndata: \n\n"
+	if events != want {
+		t.Errorf("events:\n%q\nwant\n%q", events, want)
+	}
+
+	// The same browser sees its progress; a new one starts from scratch
+	if _, body := get("/"); !strings.Contains(body, "You've done 1 of") {
+		t.Errorf("progress not recorded:\n%s", body)
+	}
+	client.Jar, _ = cookiejar.New(nil)
+	if _, body := get("/"); !strings.Contains(body, "You've done 0 of") {
+		t.Error("a new session should have no progress")
+	}
+}
+
+// Sessions whose browser stayed away are dropped when another starts
+func TestWebSessionsExpire(t *testing.T) {
+	s, err := newWebServer(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSession := func() string {
+		w := httptest.NewRecorder()
+		s.session(w, httptest.NewRequest("GET", "/", nil))
+		return w.Result().Cookies()[0].Value
+	}
+	idle, recent := newSession(), newSession()
+	s.sessions[idle].used = time.Now().Add(-sessionIdle - time.Minute)
+	s.sessions[recent].used = time.Now().Add(-sessionIdle + time.Minute)
+
+	// Coming back keeps a session; a stranger's visit sweeps the idle one
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: idle})
+	s.session(httptest.NewRecorder(), req)
+	if s.sessions[idle] == nil || time.Since(s.sessions[idle].used) > time.Minute {
+		t.Fatal("a returning browser's session should be kept and marked used")
+	}
+	s.sessions[idle].used = time.Now().Add(-sessionIdle - time.Minute)
+	newSession()
+	if _, ok := s.sessions[idle]; ok {
+		t.Error("the idle session was kept")
+	}
+	if _, ok := s.sessions[recent]; !ok {
+		t.Error("a session used within sessionIdle was dropped")
+	}
+	if len(s.sessions) != 2 {
+		t.Errorf("%d sessions, want 2", len(s.sessions))
+	}
+}
+
+// A browser leaving mid-demo ends the demo's waits, and the next visitor's
+// run goes ahead instead of waiting for it
+func TestWebRunStopsWhenBrowserLeaves(t *testing.T) {
+	sleeper := course{number: 42, run: func(_ context.Context, w io.Writer) error {
+		fmt.Fprintln(w, "started")
+		demo.Clock.Sleep(time.Hour)
+		return nil
+	}}
+	s, err := newWebServer(func(ctx context.Context, _ course, w io.Writer) error {
+		return runCaptured(ctx, sleeper, w)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.routes())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/courses/3/run", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+	if line != "data: started\n" {
+		t.Fatalf("first event %q", line)
+	}
+	cancel()
+	resp.Body.Close()
+
+	next := make(chan error)
+	go func() {
+		c := course{number: 43, run: func(context.Context, io.Writer) error { return nil }}
+		next <- runCaptured(context.Background(), c, io.Discard)
+	}()
+	select {
+	case err := <-next:
+		if err != nil {
+			t.Errorf("next run: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the next run is still waiting for the abandoned one")
+	}
+	if _, ok := demo.Clock.(demo.RealClock); !ok || demo.Clock.(demo.RealClock).Done != nil {
+		t.Errorf("demo.Clock left as %#v", demo.Clock)
+	}
+}
+
+func TestWebRunCaptured(t *testing.T) {
+	var out strings.Builder
+	c := course{number: 42, run: func(_ context.Context, w io.Writer) error {
+		fmt.Fprintln(w, "to w")
+		return nil
+	}}
+	if err := runCaptured(context.Background(), c, &out); err != nil || out.String() != "to w\n" {
+		t.Errorf("got %q, %v", out.String(), err)
+	}
+
+	c.run = func(context.Context, io.Writer) error { panic("boom") }
+	if err := runCaptured(context.Background(), c, io.Discard); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("panic should become an error, got %v", err)
+	}
+	c.run = func(context.Context, io.Writer) error { return errors.New("disk full") }
+	if err := runCaptured(context.Background(), c, io.Discard); err == nil || err.Error() != "course 42 failed: disk full" {
+		t.Errorf("a failing course's error should be returned, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package learn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// workspaceRoot is where courses get their working folders: a new one
+// each run, made the current directory while the course runs, so files a
+// demo writes (course 5's ./temp) never land in the learner's folder. ""
+// is os.TempDir(). It is set by --workdir.
+var workspaceRoot string
+
+// workspace is the folder a course runs in.
+type workspace struct {
+	dir  string
+	prev string // the working directory to go back to
+}
+
+func enterWorkspace(number int) (*workspace, error) {
+	prev, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(workspaceRoot, fmt.Sprintf("learning-golang-course%02d-*", number))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &workspace{dir: dir, prev: prev}, nil
+}
+
+// leave goes back to the previous working directory and deletes the
+// workspace. It returns what the course left in it, folders with a
+// trailing slash.
+func (ws *workspace) leave() ([]string, error) {
+	if err := os.Chdir(ws.prev); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(ws.dir)
+	if err != nil {
+		return nil, err
+	}
+	var left []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		left = append(left, name)
+	}
+	slices.Sort(left)
+	return left, os.RemoveAll(ws.dir)
+}
+
+// runInWorkspace runs course c with runWatched in a workspace of its own,
+// then deletes it whether the course finished, failed, panicked, was
+// interrupted or hung. Anything the course didn't clean up itself is
+// reported on stderr, as a demo should tidy up after itself. The error is
+// the course's, else one with its workspace.
+func runInWorkspace(ctx context.Context, c course, w io.Writer) (panicked any, hung bool, err error) {
+	ws, err := enterWorkspace(c.number)
+	if err != nil {
+		return nil, false, fmt.Errorf("making a folder to run in: %w", err)
+	}
+	defer func() {
+		left, leaveErr := ws.leave()
+		if len(left) > 0 {
+			fmt.Fprintf(os.Stderr, "(course %d left %s in %s, now deleted)\n", c.number, andList(left), filepath.Base(ws.dir))
+		}
+		if err == nil && leaveErr != nil {
+			err = fmt.Errorf("deleting the folder it ran in: %w", leaveErr)
+		}
+	}()
+	return runWatched(ctx, c, w)
+}
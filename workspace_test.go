@@ -0,0 +1,80 @@
+package learn
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/owolabijunior12/learning-golang/internal/courses/web"
+	"github.com/owolabijunior12/learning-golang/internal/demo"
+)
+
+func TestWorkspace(t *testing.T) {
+	workspaceRoot = t.TempDir()
+	defer func() { workspaceRoot = "" }()
+	wd, _ := os.Getwd()
+
+	ws, err := enterWorkspace(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile("left.txt", nil, 0o644)
+	os.Mkdir("sub", 0o755)
+	left, err := ws.leave()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"left.txt", "sub/"}; !slices.Equal(left, want) {
+		t.Errorf("left %q, want %q", left, want)
+	}
+
+	// A course that panics is cleaned up after too
+	var ranIn string
+	c := course{number: 1, run: func(context.Context, io.Writer) error {
+		ranIn, _ = os.Getwd()
+		os.WriteFile("data.txt", nil, 0o644)
+		panic("boom")
+	}}
+	p, _, err := runInWorkspace(context.Background(), c, io.Discard)
+	if p != "boom" || err != nil {
+		t.Errorf("runInWorkspace = %v, %v; want the panic", p, err)
+	}
+	if now, _ := os.Getwd(); now != wd || ranIn == wd {
+		t.Errorf("ran in %s, then back in %s; want a folder of its own, then %s", ranIn, now, wd)
+	}
+	if entries, _ := os.ReadDir(workspaceRoot); len(entries) > 0 {
+		t.Errorf("%s wasn't deleted", entries[0].Name())
+	}
+}
+
+// TestCoursesCleanUp checks that no course leaves files behind: the
+// workspace hides it from learners, but a demo should tidy up itself.
+func TestCoursesCleanUp(t *testing.T) {
+	workspaceRoot = t.TempDir()
+	defer func() { workspaceRoot = "" }()
+	demo.Clock = demo.NewFakeClock()
+	defer func() { demo.Clock = demo.RealClock{} }()
+	stdout := os.Stdout
+	os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	defer func() { os.Stdout = stdout }()
+
+	for _, c := range courses {
+		if c.number == 19 && raceEnabled {
+			continue // it races on purpose
+		}
+		ws, err := enterWorkspace(c.number)
+		if err != nil {
+			t.Fatal(err)
+		}
+		web.Users = web.NewDemoUsers()
+		if err := c.run(context.Background(), os.Stdout); err != nil {
+			t.Errorf("course %d: %v", c.number, err)
+		}
+		left, err := ws.leave()
+		if err != nil || len(left) > 0 {
+			t.Errorf("course %d left %q (%v)", c.number, left, err)
+		}
+	}
+}